@@ -16,6 +16,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Common errors
@@ -276,10 +277,12 @@ func (b *Beads) Init(prefix string) error {
 // run executes a bd command and returns stdout.
 func (b *Beads) run(args ...string) (_ []byte, retErr error) {
 	start := time.Now()
+	ctx, span := startBDSpan(args)
 	// Declare buffers before defer so the closure captures them after cmd.Run.
 	var stdout, stderr bytes.Buffer
 	defer func() {
-		telemetry.RecordBDCall(context.Background(), args, float64(time.Since(start).Milliseconds()), retErr, stdout.Bytes(), stderr.String())
+		telemetry.EndSpan(span, retErr)
+		telemetry.RecordBDCall(ctx, args, float64(time.Since(start).Milliseconds()), retErr, stdout.Bytes(), stderr.String())
 	}()
 	// Use --allow-stale to prevent failures when db is temporarily stale
 	// (e.g., after daemon is killed during shutdown).
@@ -307,6 +310,7 @@ func (b *Beads) run(args ...string) (_ []byte, retErr error) {
 
 	cmd.Env = append(b.buildRunEnv(), "BEADS_DIR="+beadsDir)
 	cmd.Env = append(cmd.Env, telemetry.OTELEnvForSubprocess()...)
+	cmd.Env = append(cmd.Env, telemetry.TraceEnv(ctx)...)
 
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -326,6 +330,16 @@ func (b *Beads) run(args ...string) (_ []byte, retErr error) {
 	return stdout.Bytes(), nil
 }
 
+// startBDSpan starts the span of a bd subprocess call. The span joins the
+// process trace, and its context is passed to bd as TRACEPARENT.
+func startBDSpan(args []string) (context.Context, trace.Span) {
+	sub := "bd"
+	if len(args) > 0 {
+		sub = "bd " + args[0]
+	}
+	return telemetry.StartSpan(context.Background(), sub)
+}
+
 // runWithRouting executes a bd command without setting BEADS_DIR, allowing bd's
 // native prefix-based routing via routes.jsonl to resolve cross-prefix beads.
 // This is needed for slot operations that reference beads with different prefixes
@@ -333,9 +347,11 @@ func (b *Beads) run(args ...string) (_ []byte, retErr error) {
 // See: sling_helpers.go verifyBeadExists/hookBeadWithRetry for the same pattern.
 func (b *Beads) runWithRouting(args ...string) (_ []byte, retErr error) { //nolint:unparam // mirrors run() signature for consistency
 	start := time.Now()
+	ctx, span := startBDSpan(args)
 	var stdout, stderr bytes.Buffer
 	defer func() {
-		telemetry.RecordBDCall(context.Background(), args, float64(time.Since(start).Milliseconds()), retErr, stdout.Bytes(), stderr.String())
+		telemetry.EndSpan(span, retErr)
+		telemetry.RecordBDCall(ctx, args, float64(time.Since(start).Milliseconds()), retErr, stdout.Bytes(), stderr.String())
 	}()
 	fullArgs := append([]string{"--allow-stale"}, args...)
 
@@ -344,6 +360,7 @@ func (b *Beads) runWithRouting(args ...string) (_ []byte, retErr error) { //noli
 
 	cmd.Env = b.buildRoutingEnv()
 	cmd.Env = append(cmd.Env, telemetry.OTELEnvForSubprocess()...)
+	cmd.Env = append(cmd.Env, telemetry.TraceEnv(ctx)...)
 
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
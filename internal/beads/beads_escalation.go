@@ -27,8 +27,104 @@ type EscalationFields struct {
 	ReescalationCount  int    // Number of times this has been re-escalated
 	LastReescalatedAt  string // When last re-escalated (empty if never)
 	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	Deliveries         []EscalationDelivery // External notification attempts, oldest first
+	Hops               []EscalationHop      // Automatic re-routes, oldest first
 }
 
+// EscalationHop records one re-escalation of an escalation through the
+// routes of its next severity. Stored as a "hop:" line in the description:
+//
+//	hop: <at> <from>-><to> by=<who> [on_call=<name>] [targets=<t1,t2>]
+type EscalationHop struct {
+	At      string   `json:"at"`                // ISO 8601 timestamp
+	From    string   `json:"from"`              // Severity before the hop
+	To      string   `json:"to"`                // Severity after the hop (same as From for a critical re-page)
+	By      string   `json:"by"`                // Who re-escalated (e.g., "daemon")
+	OnCall  string   `json:"on_call,omitempty"` // On-call window active at the time
+	Targets []string `json:"targets,omitempty"` // Mail targets paged
+}
+
+// EscalationDelivery records one external notification attempt for an escalation.
+// Stored as a "delivery:" line in the description:
+//
+//	delivery: <at> <action> <status> attempts=<n> [detail...]
+type EscalationDelivery struct {
+	At       string `json:"at"`               // ISO 8601 timestamp of the final attempt
+	Action   string `json:"action"`           // Route action (e.g., "email:human", "slack", "log")
+	Status   string `json:"status"`           // sent, failed, or skipped
+	Attempts int    `json:"attempts"`         // Number of attempts made (0 when skipped)
+	Detail   string `json:"detail,omitempty"` // Error or skip reason (empty on success)
+}
+
+// Escalation delivery status values.
+const (
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+	DeliverySkipped = "skipped"
+)
+
+// formatDelivery renders a delivery as the value of a "delivery:" line.
+func formatDelivery(d EscalationDelivery) string {
+	line := fmt.Sprintf("%s %s %s attempts=%d", d.At, d.Action, d.Status, d.Attempts)
+	if d.Detail != "" {
+		// Keep the record on one line so it round-trips through the parser.
+		line += " " + strings.Join(strings.Fields(d.Detail), " ")
+	}
+	return line
+}
+
+// parseDelivery parses the value of a "delivery:" line.
+func parseDelivery(value string) (EscalationDelivery, bool) {
+	parts := strings.SplitN(value, " ", 5)
+	if len(parts) < 4 || !strings.HasPrefix(parts[3], "attempts=") {
+		return EscalationDelivery{}, false
+	}
+	d := EscalationDelivery{At: parts[0], Action: parts[1], Status: parts[2]}
+	d.Attempts, _ = strconv.Atoi(strings.TrimPrefix(parts[3], "attempts="))
+	if len(parts) == 5 {
+		d.Detail = parts[4]
+	}
+	return d, true
+}
+
+// formatHop renders a hop as the value of a "hop:" line.
+func formatHop(h EscalationHop) string {
+	line := fmt.Sprintf("%s %s->%s by=%s", h.At, h.From, h.To, h.By)
+	if h.OnCall != "" {
+		line += " on_call=" + strings.Join(strings.Fields(h.OnCall), "-")
+	}
+	if len(h.Targets) > 0 {
+		line += " targets=" + strings.Join(h.Targets, ",")
+	}
+	return line
+}
+
+// parseHop parses the value of a "hop:" line.
+func parseHop(value string) (EscalationHop, bool) {
+	parts := strings.Fields(value)
+	if len(parts) < 2 {
+		return EscalationHop{}, false
+	}
+	from, to, ok := strings.Cut(parts[1], "->")
+	if !ok {
+		return EscalationHop{}, false
+	}
+	h := EscalationHop{At: parts[0], From: from, To: to}
+	for _, part := range parts[2:] {
+		key, val, _ := strings.Cut(part, "=")
+		switch key {
+		case "by":
+			h.By = val
+		case "on_call":
+			h.OnCall = val
+		case "targets":
+			if val != "" {
+				h.Targets = strings.Split(val, ",")
+			}
+		}
+	}
+	return h, true
+}
 
 // FormatEscalationDescription creates a description string from escalation fields.
 func FormatEscalationDescription(title string, fields *EscalationFields) string {
@@ -96,6 +192,12 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 	} else {
 		lines = append(lines, "last_reescalated_by: null")
 	}
+	for _, d := range fields.Deliveries {
+		lines = append(lines, "delivery: "+formatDelivery(d))
+	}
+	for _, h := range fields.Hops {
+		lines = append(lines, "hop: "+formatHop(h))
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -152,6 +254,14 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "delivery":
+			if d, ok := parseDelivery(value); ok {
+				fields.Deliveries = append(fields.Deliveries, d)
+			}
+		case "hop":
+			if h, ok := parseHop(value); ok {
+				fields.Hops = append(fields.Hops, h)
+			}
 		}
 	}
 
@@ -262,6 +372,27 @@ func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
 	return err
 }
 
+// RecordEscalationDeliveries appends external notification attempts to an
+// escalation bead so `gt escalate show` can report delivery status.
+func (b *Beads) RecordEscalationDeliveries(id string, deliveries []EscalationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	issue, fields, err := b.GetEscalationBead(id)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return fmt.Errorf("escalation not found: %s", id)
+	}
+
+	fields.Deliveries = append(fields.Deliveries, deliveries...)
+	description := FormatEscalationDescription(issue.Title, fields)
+
+	return b.Update(id, UpdateOptions{Description: &description})
+}
+
 // GetEscalationBead retrieves an escalation bead by ID.
 // Returns nil if not found.
 func (b *Beads) GetEscalationBead(id string) (*Issue, *EscalationFields, error) {
@@ -316,8 +447,18 @@ func (b *Beads) ListEscalationsBySeverity(severity string) ([]*Issue, error) {
 	return issues, nil
 }
 
-// ListStaleEscalations returns escalations older than the given threshold.
-// threshold is a duration string like "1h" or "30m".
+// EscalationLastRouted returns when an escalation was last routed to
+// someone: its most recent re-escalation, or its creation.
+func EscalationLastRouted(issue *Issue, fields *EscalationFields) (time.Time, error) {
+	if fields.LastReescalatedAt != "" {
+		return time.Parse(time.RFC3339, fields.LastReescalatedAt)
+	}
+	return time.Parse(time.RFC3339, issue.CreatedAt)
+}
+
+// ListStaleEscalations returns unacknowledged escalations that have gone
+// longer than threshold since they were last routed, so each re-escalation
+// restarts the clock.
 func (b *Beads) ListStaleEscalations(threshold time.Duration) ([]*Issue, error) {
 	// Get all open escalations
 	escalations, err := b.ListEscalations()
@@ -334,13 +475,13 @@ func (b *Beads) ListStaleEscalations(threshold time.Duration) ([]*Issue, error)
 			continue
 		}
 
-		// Check if older than threshold
-		createdAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
+		// Check if last routed before the threshold
+		routedAt, err := EscalationLastRouted(issue, ParseEscalationFields(issue.Description))
 		if err != nil {
 			continue // Skip if can't parse
 		}
 
-		if createdAt.Before(cutoff) {
+		if routedAt.Before(cutoff) {
 			stale = append(stale, issue)
 		}
 	}
@@ -359,11 +500,12 @@ type ReescalationResult struct {
 	SkipReason      string
 }
 
-// ReescalateEscalation bumps the severity of an escalation and updates tracking fields.
-// Returns the new severity if successful, or an error.
-// reescalatedBy should be the identity of the agent/process doing the reescalation.
+// ReescalateEscalation bumps the severity of an escalation, updates tracking
+// fields, and records the hop on the bead. A critical escalation stays
+// critical and is re-paged. hop.By should be the identity of the agent/process
+// doing the reescalation; its OnCall and Targets are recorded as given.
 // maxReescalations limits how many times an escalation can be bumped (0 = unlimited).
-func (b *Beads) ReescalateEscalation(id, reescalatedBy string, maxReescalations int) (*ReescalationResult, error) {
+func (b *Beads) ReescalateEscalation(id string, hop EscalationHop, maxReescalations int) (*ReescalationResult, error) {
 	// Get the escalation
 	issue, fields, err := b.GetEscalationBead(id)
 	if err != nil {
@@ -386,14 +528,6 @@ func (b *Beads) ReescalateEscalation(id, reescalatedBy string, maxReescalations
 		return result, nil
 	}
 
-	// Check if already at critical (can't bump further)
-	if fields.Severity == "critical" {
-		result.Skipped = true
-		result.SkipReason = "already at critical severity"
-		result.NewSeverity = "critical"
-		return result, nil
-	}
-
 	// Save original severity on first reescalation
 	if fields.OriginalSeverity == "" {
 		fields.OriginalSeverity = fields.Severity
@@ -404,7 +538,11 @@ func (b *Beads) ReescalateEscalation(id, reescalatedBy string, maxReescalations
 	fields.Severity = newSeverity
 	fields.ReescalationCount++
 	fields.LastReescalatedAt = time.Now().Format(time.RFC3339)
-	fields.LastReescalatedBy = reescalatedBy
+	fields.LastReescalatedBy = hop.By
+	hop.At = fields.LastReescalatedAt
+	hop.From = result.OldSeverity
+	hop.To = newSeverity
+	fields.Hops = append(fields.Hops, hop)
 
 	result.NewSeverity = newSeverity
 	result.ReescalationNum = fields.ReescalationCount
@@ -413,11 +551,14 @@ func (b *Beads) ReescalateEscalation(id, reescalatedBy string, maxReescalations
 	description := FormatEscalationDescription(issue.Title, fields)
 
 	// Update the bead with new description and severity label
-	if err := b.Update(id, UpdateOptions{
-		Description:  &description,
-		AddLabels:    []string{"reescalated", "severity:" + newSeverity},
-		RemoveLabels: []string{"severity:" + result.OldSeverity},
-	}); err != nil {
+	opts := UpdateOptions{
+		Description: &description,
+		AddLabels:   []string{"reescalated", "severity:" + newSeverity},
+	}
+	if newSeverity != result.OldSeverity {
+		opts.RemoveLabels = []string{"severity:" + result.OldSeverity}
+	}
+	if err := b.Update(id, opts); err != nil {
 		return nil, fmt.Errorf("updating escalation: %w", err)
 	}
 
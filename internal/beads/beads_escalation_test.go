@@ -3,6 +3,7 @@ package beads
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFormatEscalationDescription(t *testing.T) {
@@ -300,6 +301,71 @@ func TestEscalationFieldsRoundTrip(t *testing.T) {
 	}
 }
 
+func TestEscalationDeliveriesRoundTrip(t *testing.T) {
+	original := &EscalationFields{
+		Severity: "critical",
+		Deliveries: []EscalationDelivery{
+			{At: "2024-06-15T12:00:01Z", Action: "email:human", Status: DeliverySent, Attempts: 1},
+			{At: "2024-06-15T12:00:09Z", Action: "slack", Status: DeliveryFailed, Attempts: 3, Detail: "HTTP 503:\nservice unavailable"},
+			{At: "2024-06-15T12:00:09Z", Action: "sms:human", Status: DeliverySkipped, Detail: "contacts.human_sms not configured"},
+		},
+	}
+
+	parsed := ParseEscalationFields(FormatEscalationDescription("Escalation: outage", original))
+
+	if len(parsed.Deliveries) != len(original.Deliveries) {
+		t.Fatalf("got %d deliveries, want %d", len(parsed.Deliveries), len(original.Deliveries))
+	}
+	for i, want := range original.Deliveries {
+		got := parsed.Deliveries[i]
+		if i == 1 {
+			// Multi-line details are flattened to keep the record on one line.
+			want.Detail = "HTTP 503: service unavailable"
+		}
+		if got != want {
+			t.Errorf("delivery %d: got %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestEscalationHopsRoundTrip(t *testing.T) {
+	original := &EscalationFields{
+		Severity: "critical",
+		Hops: []EscalationHop{
+			{At: "2024-06-15T12:00:00Z", From: "high", To: "critical", By: "daemon", OnCall: "night shift", Targets: []string{"mayor/", "overseer"}},
+			{At: "2024-06-15T16:00:00Z", From: "critical", To: "critical", By: "daemon"},
+		},
+	}
+
+	parsed := ParseEscalationFields(FormatEscalationDescription("Escalation: outage", original))
+
+	if len(parsed.Hops) != 2 {
+		t.Fatalf("got %d hops, want 2", len(parsed.Hops))
+	}
+	first := parsed.Hops[0]
+	if first.From != "high" || first.To != "critical" || first.By != "daemon" || first.OnCall != "night-shift" {
+		t.Errorf("hop 0 = %+v", first)
+	}
+	if len(first.Targets) != 2 || first.Targets[1] != "overseer" {
+		t.Errorf("hop 0 targets = %v", first.Targets)
+	}
+	if second := parsed.Hops[1]; second.At != "2024-06-15T16:00:00Z" || second.OnCall != "" || second.Targets != nil {
+		t.Errorf("hop 1 = %+v", second)
+	}
+}
+
+func TestEscalationLastRouted(t *testing.T) {
+	issue := &Issue{CreatedAt: "2024-06-15T12:00:00Z"}
+	got, err := EscalationLastRouted(issue, &EscalationFields{})
+	if err != nil || got.Format(time.RFC3339) != issue.CreatedAt {
+		t.Errorf("EscalationLastRouted() = %v, %v; want creation time", got, err)
+	}
+	got, err = EscalationLastRouted(issue, &EscalationFields{LastReescalatedAt: "2024-06-15T16:00:00Z"})
+	if err != nil || got.Format(time.RFC3339) != "2024-06-15T16:00:00Z" {
+		t.Errorf("EscalationLastRouted() = %v, %v; want last re-escalation", got, err)
+	}
+}
+
 func TestBumpSeverity(t *testing.T) {
 	tests := []struct {
 		input string
@@ -596,6 +596,8 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		Rig:         "gastown",
 		MergeCommit: "abc123def789",
 		CloseReason: "merged",
+		AgentPreset: "codex",
+		Formula:     "mol-polecat-work",
 	}
 
 	// Format to string
@@ -912,6 +914,7 @@ Some existing prose content.`,
 func TestAttachmentFieldsRoundTrip(t *testing.T) {
 	original := &AttachmentFields{
 		AttachedMolecule: "mol-roundtrip",
+		AttachedFormula:  "mol-polecat-work",
 		AttachedAt:       "2025-12-21T15:30:00Z",
 	}
 
@@ -19,6 +19,7 @@ func ParseAgentFieldsFromDescription(description string) *AgentFields {
 // These fields track which molecule is attached to a handoff/pinned bead.
 type AttachmentFields struct {
 	AttachedMolecule string // Root issue ID of the attached molecule
+	AttachedFormula  string // Formula the attached molecule was instantiated from
 	AttachedAt       string // ISO 8601 timestamp when attached
 	AttachedArgs     string // Natural language args passed via gt sling --args (no-tmux mode)
 	DispatchedBy     string // Agent ID that dispatched this work (for completion notification)
@@ -27,6 +28,7 @@ type AttachmentFields struct {
 	ConvoyID         string // Convoy bead ID tracking this issue (e.g., "hq-cv-abc")
 	MergeStrategy    string // Convoy merge strategy: "direct", "mr", "local", or "" (default = mr)
 	ConvoyOwned      bool   // If true, convoy has gt:owned label (caller-managed lifecycle)
+	TraceParent      string // W3C traceparent of the sling that dispatched this work
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -62,6 +64,9 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "attached_molecule", "attached-molecule", "attachedmolecule":
 			fields.AttachedMolecule = value
 			hasFields = true
+		case "attached_formula", "attached-formula", "attachedformula":
+			fields.AttachedFormula = value
+			hasFields = true
 		case "attached_at", "attached-at", "attachedat":
 			fields.AttachedAt = value
 			hasFields = true
@@ -86,6 +91,9 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "convoy_owned", "convoy-owned", "convoyowned":
 			fields.ConvoyOwned = strings.ToLower(value) == "true"
 			hasFields = true
+		case "trace_parent", "trace-parent", "traceparent":
+			fields.TraceParent = value
+			hasFields = true
 		}
 	}
 
@@ -107,6 +115,9 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.AttachedMolecule != "" {
 		lines = append(lines, "attached_molecule: "+fields.AttachedMolecule)
 	}
+	if fields.AttachedFormula != "" {
+		lines = append(lines, "attached_formula: "+fields.AttachedFormula)
+	}
 	if fields.AttachedAt != "" {
 		lines = append(lines, "attached_at: "+fields.AttachedAt)
 	}
@@ -131,6 +142,9 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.ConvoyOwned {
 		lines = append(lines, "convoy_owned: true")
 	}
+	if fields.TraceParent != "" {
+		lines = append(lines, "trace_parent: "+fields.TraceParent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -144,6 +158,9 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"attached_molecule": true,
 		"attached-molecule": true,
 		"attachedmolecule":  true,
+		"attached_formula":  true,
+		"attached-formula":  true,
+		"attachedformula":   true,
 		"attached_at":       true,
 		"attached-at":       true,
 		"attachedat":        true,
@@ -167,6 +184,9 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"convoy_owned":      true,
 		"convoy-owned":      true,
 		"convoyowned":       true,
+		"trace_parent":      true,
+		"trace-parent":      true,
+		"traceparent":       true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -228,6 +248,8 @@ type MRFields struct {
 	MergeCommit string // SHA of merge commit (set on close)
 	CloseReason string // Reason for closing: merged, rejected, conflict, superseded
 	AgentBead   string // Agent bead ID that created this MR (for traceability)
+	AgentPreset string // Agent preset the worker ran (for routing track records)
+	Formula     string // Formula the work ran under (for routing track records)
 
 	// Conflict resolution fields (for priority scoring)
 	RetryCount      int    // Number of conflict-resolution cycles
@@ -237,6 +259,10 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// TraceParent is the W3C traceparent of the gt done that submitted the MR,
+	// so the refinery's gates and merge join the work's trace.
+	TraceParent string
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -294,6 +320,12 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "agent_bead", "agent-bead", "agentbead":
 			fields.AgentBead = value
 			hasFields = true
+		case "agent_preset", "agent-preset", "agentpreset":
+			fields.AgentPreset = value
+			hasFields = true
+		case "formula":
+			fields.Formula = value
+			hasFields = true
 		case "retry_count", "retry-count", "retrycount":
 			if n, err := parseIntField(value); err == nil {
 				fields.RetryCount = n
@@ -311,6 +343,9 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "trace_parent", "trace-parent", "traceparent":
+			fields.TraceParent = value
+			hasFields = true
 		}
 	}
 
@@ -360,6 +395,12 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.AgentBead != "" {
 		lines = append(lines, "agent_bead: "+fields.AgentBead)
 	}
+	if fields.AgentPreset != "" {
+		lines = append(lines, "agent_preset: "+fields.AgentPreset)
+	}
+	if fields.Formula != "" {
+		lines = append(lines, "formula: "+fields.Formula)
+	}
 	if fields.RetryCount > 0 {
 		lines = append(lines, fmt.Sprintf("retry_count: %d", fields.RetryCount))
 	}
@@ -375,6 +416,9 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.TraceParent != "" {
+		lines = append(lines, "trace_parent: "+fields.TraceParent)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -405,6 +449,10 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"agent_bead":         true,
 		"agent-bead":         true,
 		"agentbead":          true,
+		"agent_preset":       true,
+		"agent-preset":       true,
+		"agentpreset":        true,
+		"formula":            true,
 		"retry_count":        true,
 		"retry-count":        true,
 		"retrycount":         true,
@@ -421,6 +469,9 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"trace_parent":       true,
+		"trace-parent":       true,
+		"traceparent":        true,
 	}
 
 	// Collect non-MR lines from existing description
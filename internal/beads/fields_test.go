@@ -290,3 +290,24 @@ func TestParseAgentFieldsFromDescription(t *testing.T) {
 		t.Errorf("NotificationLevel = %q, want %q", got.NotificationLevel, "verbose")
 	}
 }
+
+func TestTraceParentFieldsRoundTrip(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	attachment := &Issue{Description: "Fix the bug\n\n" + FormatAttachmentFields(&AttachmentFields{
+		AttachedMolecule: "gt-wisp-123",
+		TraceParent:      tp,
+	})}
+	if got := ParseAttachmentFields(attachment); got == nil || got.TraceParent != tp {
+		t.Errorf("attachment TraceParent = %+v, want %q", got, tp)
+	}
+	updated := SetAttachmentFields(attachment, &AttachmentFields{AttachedMolecule: "gt-wisp-123"})
+	if strings.Contains(updated, "trace_parent") {
+		t.Errorf("SetAttachmentFields kept stale trace_parent:\n%s", updated)
+	}
+
+	mr := &Issue{Description: FormatMRFields(&MRFields{Branch: "polecat/nux", TraceParent: tp})}
+	if got := ParseMRFields(mr); got == nil || got.TraceParent != tp {
+		t.Errorf("MR TraceParent = %+v, want %q", got, tp)
+	}
+}
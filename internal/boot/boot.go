@@ -15,7 +15,6 @@ import (
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/session"
-	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // MarkerFileName is the lock file for Boot startup coordination.
@@ -38,7 +37,7 @@ type Boot struct {
 	townRoot   string
 	bootDir    string // ~/gt/deacon/dogs/boot/
 	deaconDir  string // ~/gt/deacon/
-	tmux       *tmux.Tmux
+	tmux       session.Backend
 	degraded   bool
 	lockHandle *flock.Flock // held during triage execution
 }
@@ -49,7 +48,7 @@ func New(townRoot string) *Boot {
 		townRoot:  townRoot,
 		bootDir:   filepath.Join(townRoot, "deacon", "dogs", "boot"),
 		deaconDir: filepath.Join(townRoot, "deacon"),
-		tmux:      tmux.NewTmux(),
+		tmux:      session.NewBackend(),
 		degraded:  os.Getenv("GT_DEGRADED") == "true",
 	}
 }
@@ -155,18 +154,18 @@ func (b *Boot) LoadStatus() (*Status, error) {
 // Boot is ephemeral - each spawn kills any existing session and starts fresh.
 func (b *Boot) Spawn(agentOverride string) error {
 	// No IsRunning() guard here - Boot is ephemeral by design.
-	// spawnTmux() kills any existing session before spawning fresh.
+	// spawnSession() kills any existing session before spawning fresh.
 
 	// Check for degraded mode
 	if b.degraded {
 		return b.spawnDegraded()
 	}
 
-	return b.spawnTmux(agentOverride)
+	return b.spawnSession(agentOverride)
 }
 
-// spawnTmux spawns Boot in a tmux session.
-func (b *Boot) spawnTmux(agentOverride string) error {
+// spawnSession spawns Boot in a session on the configured backend.
+func (b *Boot) spawnSession(agentOverride string) error {
 	// Kill any stale session first (Boot is ephemeral).
 	if b.IsSessionAlive() {
 		_ = b.tmux.KillSessionWithProcesses(session.BootSessionName())
@@ -229,7 +228,7 @@ func (b *Boot) DeaconDir() string {
 	return b.deaconDir
 }
 
-// Tmux returns the tmux manager.
-func (b *Boot) Tmux() *tmux.Tmux {
+// Backend returns the session backend Boot runs on.
+func (b *Boot) Backend() session.Backend {
 	return b.tmux
 }
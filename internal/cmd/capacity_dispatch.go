@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -78,8 +79,21 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 		return 0, nil
 	}
 
+	// Time windows adjust the limits; blackouts pause dispatch outright.
+	if err := schedulerCfg.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid scheduler schedule: %w", err)
+	}
+	policy := schedulerCfg.PolicyAt(time.Now())
+	if policy.Blackout != nil {
+		if !dryRun {
+			fmt.Printf("%s Scheduler is in blackout %s, skipping dispatch\n", style.Dim.Render("⏸"), describeBlackout(policy))
+		}
+		return 0, nil
+	}
+	maxPolecats = policy.MaxPolecats
+
 	// Determine limits
-	batchSize := schedulerCfg.GetBatchSize()
+	batchSize := policy.BatchSize
 	if batchOverride > 0 {
 		batchSize = batchOverride
 	}
@@ -93,6 +107,9 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 		cleanupStaleContexts(townRoot)
 	}
 
+	// Spend budgets hold back work charged to an exhausted budget.
+	budget := loadBudgetGate(townRoot, settings, !dryRun)
+
 	// Wire up the DispatchCycle
 	successfulRigs := make(map[string]bool)
 	// Track polecat names from dispatch results, keyed by context bead ID.
@@ -107,7 +124,11 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 			return cap, nil
 		},
 		QueryPending: func() ([]capacity.PendingBead, error) {
-			return getReadySlingContexts(townRoot)
+			pending, err := getReadySlingContexts(townRoot)
+			if err != nil {
+				return nil, err
+			}
+			return filterByBudget(pending, budget), nil
 		},
 		Execute: func(b capacity.PendingBead) error {
 			result, err := dispatchSingleBead(b, townRoot, actor)
@@ -155,6 +176,10 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 			}
 			recordDispatchFailure(townBeads, b, err)
 		},
+		ActiveByRig: func() (map[string]int, error) {
+			return countActivePolecatsByRig(), nil
+		},
+		Config:     schedulerCfg,
 		BatchSize:  batchSize,
 		SpawnDelay: spawnDelay,
 	}
@@ -217,6 +242,10 @@ func printDryRunPlan(plan capacity.DispatchPlan, maxPolecats, batchSize int) {
 	}
 
 	totalReady := len(plan.ToDispatch) + plan.Skipped
+	if len(plan.ToDispatch) == 0 && plan.Reason == "rig-cap" {
+		fmt.Printf("All rigs with ready work are at their per-rig cap, %d ready bead(s) waiting\n", totalReady)
+		return
+	}
 	if len(plan.ToDispatch) == 0 {
 		fmt.Printf("No capacity: %s, %d ready bead(s) waiting\n", capStr, totalReady)
 		return
@@ -225,7 +254,10 @@ func printDryRunPlan(plan capacity.DispatchPlan, maxPolecats, batchSize int) {
 	fmt.Printf("%s Would dispatch %d bead(s) (capacity: %s, batch: %d, ready: %d, reason: %s)\n",
 		style.Bold.Render("📋"), len(plan.ToDispatch), capStr, batchSize, totalReady, plan.Reason)
 	for _, b := range plan.ToDispatch {
-		fmt.Printf("  Would dispatch: %s → %s\n", b.WorkBeadID, b.TargetRig)
+		fmt.Printf("  Would dispatch: %s → %s (P%d)\n", b.WorkBeadID, b.TargetRig, b.Priority)
+	}
+	if plan.RigCapped > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d bead(s) held back by per-rig caps", plan.RigCapped)))
 	}
 }
 
@@ -284,10 +316,12 @@ func cleanupStaleContexts(townRoot string) {
 	}
 }
 
-// beadStatusInfo holds batch-fetched bead status and title.
+// beadStatusInfo holds batch-fetched bead status, title and scheduling fields.
 type beadStatusInfo struct {
-	Status string
-	Title  string
+	Status    string
+	Title     string
+	Priority  int
+	CreatedAt string
 }
 
 // batchFetchBeadInfoByIDs returns a map of bead ID → status+title for specific beads.
@@ -312,13 +346,20 @@ func batchFetchBeadInfoByIDs(townRoot string, ids []string) map[string]beadStatu
 			continue
 		}
 		var items []struct {
-			ID     string `json:"id"`
-			Status string `json:"status"`
-			Title  string `json:"title"`
+			ID        string `json:"id"`
+			Status    string `json:"status"`
+			Title     string `json:"title"`
+			Priority  int    `json:"priority"`
+			CreatedAt string `json:"created_at"`
 		}
 		if err := json.Unmarshal(out, &items); err == nil {
 			for _, item := range items {
-				result[item.ID] = beadStatusInfo{Status: item.Status, Title: item.Title}
+				result[item.ID] = beadStatusInfo{
+					Status:    item.Status,
+					Title:     item.Title,
+					Priority:  item.Priority,
+					CreatedAt: item.CreatedAt,
+				}
 			}
 		}
 	}
@@ -397,12 +438,46 @@ func getReadySlingContexts(townRoot string) ([]capacity.PendingBead, error) {
 			Description: ctx.Description,
 			Labels:      ctx.Labels,
 			Context:     fields,
+			Priority:    capacity.DefaultPriority,
 		})
 	}
 
+	annotateDispatchOrder(townRoot, result)
+	annotateAutoRoutes(townRoot, result)
 	return result, nil
 }
 
+// annotateDispatchOrder fills in the work bead priority and convoy creation
+// time used by capacity.OrderPending. Beads that can't be looked up keep the
+// default priority and no convoy age.
+func annotateDispatchOrder(townRoot string, pending []capacity.PendingBead) {
+	if len(pending) == 0 {
+		return
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, b := range pending {
+		for _, id := range []string{b.WorkBeadID, b.Context.Convoy} {
+			if id != "" && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	info := batchFetchBeadInfoByIDs(townRoot, ids)
+	for i := range pending {
+		if wi, ok := info[pending[i].WorkBeadID]; ok {
+			pending[i].Priority = wi.Priority
+		}
+		if ci, ok := info[pending[i].Context.Convoy]; ok && ci.CreatedAt != "" {
+			if t, err := time.Parse(time.RFC3339, ci.CreatedAt); err == nil {
+				pending[i].ConvoyCreatedAt = t
+			}
+		}
+	}
+}
+
 // dispatchSingleBead dispatches one scheduled bead via executeSling.
 // Context fields are already parsed (from PendingBead.Context).
 // Returns the SlingResult (including PolecatName) on success.
@@ -412,6 +487,13 @@ func dispatchSingleBead(b capacity.PendingBead, townRoot, _ string) (*SlingResul
 	}
 
 	dp := capacity.ReconstructFromContext(b.Context)
+	// Auto-routed beads go where annotateAutoRoutes sent them.
+	if b.TargetRig != "" {
+		dp.RigName = b.TargetRig
+	}
+	if b.Agent != "" {
+		dp.Agent = b.Agent
+	}
 	params := SlingParams{
 		BeadID:           dp.BeadID,
 		RigName:          dp.RigName,
@@ -428,6 +510,7 @@ func dispatchSingleBead(b capacity.PendingBead, townRoot, _ string) (*SlingResul
 		FormulaFailFatal: true,
 		CallerContext:    "scheduler-dispatch",
 		NoConvoy:         true,
+		Convoy:           b.Context.Convoy,
 		NoBoot:           true,
 		TownRoot:         townRoot,
 		BeadsDir:         filepath.Join(townRoot, ".beads"),
@@ -525,3 +608,24 @@ func listReadyWorkBeadIDs(townRoot string) map[string]bool {
 	return ids
 }
 
+// filterByBudget drops pending beads whose rig or convoy budget is exhausted.
+// Held-back beads stay queued and dispatch once spend resets or limits rise.
+func filterByBudget(pending []capacity.PendingBead, gate *budgetGate) []capacity.PendingBead {
+	if gate == nil {
+		return pending
+	}
+	allowed := make([]capacity.PendingBead, 0, len(pending))
+	for _, b := range pending {
+		var convoy string
+		if b.Context != nil {
+			convoy = b.Context.Convoy
+		}
+		if gate.allow(b.TargetRig, convoy) {
+			allowed = append(allowed, b)
+		}
+	}
+	if held := len(pending) - len(allowed); held > 0 {
+		fmt.Printf("%s Holding %d bead(s) over budget\n", style.Dim.Render("○"), held)
+	}
+	return allowed
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"os"
 	"os/exec"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
@@ -11,7 +10,7 @@ import (
 )
 
 // DefaultAgentEmailDomain is the default domain for agent git emails.
-const DefaultAgentEmailDomain = "gastown.local"
+const DefaultAgentEmailDomain = config.DefaultAgentEmailDomain
 
 var commitCmd = &cobra.Command{
 	Use:   "commit [flags] [-- git-commit-args...]",
@@ -83,13 +82,7 @@ func runCommit(cmd *cobra.Command, args []string) error {
 // "gastown/crew/jack" → "gastown.crew.jack@domain"
 // "mayor/" → "mayor@domain"
 func identityToEmail(identity, domain string) string {
-	// Remove trailing slash if present
-	identity = strings.TrimSuffix(identity, "/")
-
-	// Replace slashes with dots for email local part
-	localPart := strings.ReplaceAll(identity, "/", ".")
-
-	return localPart + "@" + domain
+	return config.AgentEmail(identity, domain)
 }
 
 // runGitCommit executes git commit with optional identity override.
@@ -609,13 +609,26 @@ Supported keys:
   scheduler.max_polecats      Dispatch mode: -1 = direct (default), N > 0 = deferred
   scheduler.batch_size        Beads per heartbeat (default: 1)
   scheduler.spawn_delay       Delay between spawns (default: 0s)
+  scheduler.max_polecats_per_rig
+                              Cap on polecats in any one rig (-1 = none, default)
+  scheduler.rig_max_polecats.<rig>
+                              Per-rig cap override (-1 = uncapped)
+  scheduler.rig_weight.<rig>  Fair-share weight for a rig (default: 1)
+  costs.budgets.daily_usd     Town-wide spend limit per day (0 = none)
+  costs.budgets.rig_daily_usd Per-rig spend limit per day (0 = none)
+  costs.budgets.rig.<rig>     Per-rig daily limit override (-1 = unlimited)
+  costs.budgets.convoy_usd    Total spend limit per convoy (0 = none)
+  costs.budgets.warn_percent  Share of a budget at which warnings start (default: 80)
+  costs.budgets.action        When exhausted: warn, pause (default), escalate
 
 Examples:
   gt config set convoy.notify_on_complete true
   gt config set cli_theme dark
   gt config set default_agent claude
   gt config set scheduler.max_polecats 5
-  gt config set scheduler.max_polecats -1`,
+  gt config set scheduler.max_polecats -1
+  gt config set scheduler.rig_weight.gastown 2
+  gt config set costs.budgets.daily_usd 200`,
 	Args: cobra.ExactArgs(2),
 	RunE: runConfigSet,
 }
@@ -634,6 +647,12 @@ Supported keys:
   scheduler.max_polecats      Dispatch mode (-1 = direct, N > 0 = deferred)
   scheduler.batch_size        Beads per heartbeat
   scheduler.spawn_delay       Delay between spawns
+  scheduler.max_polecats_per_rig
+                              Cap on polecats in any one rig
+  scheduler.rig_max_polecats.<rig>
+                              Effective cap for a rig (0 = uncapped)
+  scheduler.rig_weight.<rig>  Fair-share weight for a rig
+  costs.budgets.<field>       Spend budget setting (see gt config set --help)
 
 Examples:
   gt config get convoy.notify_on_complete
@@ -658,6 +677,41 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("loading town settings: %w", err)
 	}
 
+	if rig, ok := strings.CutPrefix(key, "scheduler.rig_max_polecats."); ok && rig != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < -1 {
+			return fmt.Errorf("invalid value for %s: expected integer >= -1 (-1 = uncapped)", key)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		if townSettings.Scheduler.RigMaxPolecats == nil {
+			townSettings.Scheduler.RigMaxPolecats = make(map[string]int)
+		}
+		townSettings.Scheduler.RigMaxPolecats[rig] = n
+		return saveConfigSetting(settingsPath, townSettings, key, value)
+	}
+	if rig, ok := strings.CutPrefix(key, "scheduler.rig_weight."); ok && rig != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid value for %s: expected positive integer", key)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		if townSettings.Scheduler.RigWeights == nil {
+			townSettings.Scheduler.RigWeights = make(map[string]int)
+		}
+		townSettings.Scheduler.RigWeights[rig] = n
+		return saveConfigSetting(settingsPath, townSettings, key, value)
+	}
+	if field, ok := strings.CutPrefix(key, "costs.budgets."); ok {
+		if err := setBudgetConfig(townSettings, field, value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		return saveConfigSetting(settingsPath, townSettings, key, value)
+	}
+
 	switch key {
 	case "convoy.notify_on_complete":
 		b, err := parseBool(value)
@@ -714,10 +768,25 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 		townSettings.Scheduler.SpawnDelay = value
 
+	case "scheduler.max_polecats_per_rig":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < -1 {
+			return fmt.Errorf("invalid value for %s: expected integer >= -1 (-1 = no per-rig cap)", key)
+		}
+		if townSettings.Scheduler == nil {
+			townSettings.Scheduler = capacity.DefaultSchedulerConfig()
+		}
+		townSettings.Scheduler.MaxPolecatsPerRig = &n
+
 	default:
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay", key)
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  scheduler.max_polecats_per_rig\n  scheduler.rig_max_polecats.<rig>\n  scheduler.rig_weight.<rig>\n  costs.budgets.<field>", key)
 	}
 
+	return saveConfigSetting(settingsPath, townSettings, key, value)
+}
+
+// saveConfigSetting writes town settings after a successful config set.
+func saveConfigSetting(settingsPath string, townSettings *config.TownSettings, key, value string) error {
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
 		return fmt.Errorf("saving town settings: %w", err)
 	}
@@ -741,6 +810,23 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 	}
 
 	var value string
+	if rig, ok := strings.CutPrefix(key, "scheduler.rig_max_polecats."); ok && rig != "" {
+		fmt.Println(strconv.Itoa(townSettings.Scheduler.GetRigCap(rig)))
+		return nil
+	}
+	if rig, ok := strings.CutPrefix(key, "scheduler.rig_weight."); ok && rig != "" {
+		fmt.Println(strconv.Itoa(townSettings.Scheduler.GetRigWeight(rig)))
+		return nil
+	}
+	if field, ok := strings.CutPrefix(key, "costs.budgets."); ok {
+		v, err := getBudgetConfig(townSettings, field)
+		if err != nil {
+			return fmt.Errorf("unknown config key: %q", key)
+		}
+		fmt.Println(v)
+		return nil
+	}
+
 	switch key {
 	case "convoy.notify_on_complete":
 		if townSettings.Convoy != nil && townSettings.Convoy.NotifyOnComplete {
@@ -782,14 +868,110 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		}
 		value = scfg.GetSpawnDelay().String()
 
+	case "scheduler.max_polecats_per_rig":
+		value = "-1"
+		if scfg := townSettings.Scheduler; scfg != nil && scfg.MaxPolecatsPerRig != nil {
+			value = strconv.Itoa(*scfg.MaxPolecatsPerRig)
+		}
+
 	default:
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay", key)
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  scheduler.max_polecats_per_rig\n  scheduler.rig_max_polecats.<rig>\n  scheduler.rig_weight.<rig>\n  costs.budgets.<field>", key)
 	}
 
 	fmt.Println(value)
 	return nil
 }
 
+// setBudgetConfig sets one costs.budgets field from a config set value.
+func setBudgetConfig(townSettings *config.TownSettings, field, value string) error {
+	if townSettings.Costs == nil {
+		townSettings.Costs = &config.CostsConfig{}
+	}
+	if townSettings.Costs.Budgets == nil {
+		townSettings.Costs.Budgets = &config.BudgetConfig{}
+	}
+	b := townSettings.Costs.Budgets
+
+	if field == "action" {
+		switch value {
+		case config.BudgetActionWarn, config.BudgetActionPause, config.BudgetActionEscalate:
+			b.Action = value
+			return nil
+		}
+		return fmt.Errorf("expected warn, pause or escalate")
+	}
+	if field == "warn_percent" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 100 {
+			return fmt.Errorf("expected integer 1-100")
+		}
+		b.WarnPercent = n
+		return nil
+	}
+
+	usd, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("expected USD amount")
+	}
+	if rig, ok := strings.CutPrefix(field, "rig."); ok && rig != "" {
+		if usd < 0 && usd != -1 {
+			return fmt.Errorf("expected USD amount >= 0 (-1 = unlimited)")
+		}
+		if b.Rigs == nil {
+			b.Rigs = make(map[string]float64)
+		}
+		b.Rigs[rig] = usd
+		return nil
+	}
+	if usd < 0 {
+		return fmt.Errorf("expected USD amount >= 0 (0 = no limit)")
+	}
+	switch field {
+	case "daily_usd":
+		b.DailyUSD = usd
+	case "rig_daily_usd":
+		b.RigDailyUSD = usd
+	case "convoy_usd":
+		b.ConvoyUSD = usd
+	default:
+		return fmt.Errorf("unknown budget field %q", field)
+	}
+	return nil
+}
+
+// getBudgetConfig returns the effective value of one costs.budgets field.
+func getBudgetConfig(townSettings *config.TownSettings, field string) (string, error) {
+	var b *config.BudgetConfig
+	if townSettings.Costs != nil {
+		b = townSettings.Costs.Budgets
+	}
+	usd := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	if rig, ok := strings.CutPrefix(field, "rig."); ok && rig != "" {
+		if v, ok := b.GetRigOverride(rig); ok {
+			return usd(v), nil
+		}
+		return usd(b.GetRigDailyUSD(rig)), nil
+	}
+	switch field {
+	case "action":
+		return b.GetAction(), nil
+	case "warn_percent":
+		return strconv.Itoa(b.GetWarnPercent()), nil
+	}
+	if b == nil {
+		b = &config.BudgetConfig{}
+	}
+	switch field {
+	case "daily_usd":
+		return usd(b.DailyUSD), nil
+	case "rig_daily_usd":
+		return usd(b.RigDailyUSD), nil
+	case "convoy_usd":
+		return usd(b.ConvoyUSD), nil
+	}
+	return "", fmt.Errorf("unknown budget field %q", field)
+}
+
 // parseBool parses a boolean string (true/false, yes/no, 1/0).
 func parseBool(s string) (bool, error) {
 	switch strings.ToLower(s) {
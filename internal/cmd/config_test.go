@@ -715,6 +715,94 @@ func TestConfigSetGet(t *testing.T) {
 		}
 	})
 
+	t.Run("set per-rig scheduler caps and weights", func(t *testing.T) {
+		townRoot := setupTestTownForConfig(t)
+		settingsPath := config.TownSettingsPath(townRoot)
+
+		originalWd, _ := os.Getwd()
+		defer os.Chdir(originalWd)
+		if err := os.Chdir(townRoot); err != nil {
+			t.Fatalf("chdir: %v", err)
+		}
+
+		cmd := &cobra.Command{}
+		for _, kv := range [][]string{
+			{"scheduler.max_polecats_per_rig", "2"},
+			{"scheduler.rig_max_polecats.gastown", "5"},
+			{"scheduler.rig_weight.gastown", "3"},
+		} {
+			if err := runConfigSet(cmd, kv); err != nil {
+				t.Fatalf("runConfigSet(%v) failed: %v", kv, err)
+			}
+		}
+		if err := runConfigSet(cmd, []string{"scheduler.rig_weight.gastown", "0"}); err == nil {
+			t.Error("expected error for zero rig weight")
+		}
+
+		loaded, err := config.LoadOrCreateTownSettings(settingsPath)
+		if err != nil {
+			t.Fatalf("load settings: %v", err)
+		}
+		scfg := loaded.Scheduler
+		if scfg.GetRigCap("gastown") != 5 || scfg.GetRigCap("beads") != 2 || scfg.GetRigWeight("gastown") != 3 {
+			t.Errorf("scheduler config = %+v", scfg)
+		}
+		if err := runConfigGet(cmd, []string{"scheduler.rig_weight.gastown"}); err != nil {
+			t.Errorf("runConfigGet failed: %v", err)
+		}
+	})
+
+	t.Run("set spend budgets", func(t *testing.T) {
+		townRoot := setupTestTownForConfig(t)
+		settingsPath := config.TownSettingsPath(townRoot)
+
+		originalWd, _ := os.Getwd()
+		defer os.Chdir(originalWd)
+		if err := os.Chdir(townRoot); err != nil {
+			t.Fatalf("chdir: %v", err)
+		}
+
+		cmd := &cobra.Command{}
+		for _, kv := range [][]string{
+			{"costs.budgets.daily_usd", "200"},
+			{"costs.budgets.rig_daily_usd", "50.5"},
+			{"costs.budgets.rig.beads", "-1"},
+			{"costs.budgets.convoy_usd", "75"},
+			{"costs.budgets.warn_percent", "90"},
+			{"costs.budgets.action", "escalate"},
+		} {
+			if err := runConfigSet(cmd, kv); err != nil {
+				t.Fatalf("runConfigSet(%v) failed: %v", kv, err)
+			}
+		}
+		for _, kv := range [][]string{
+			{"costs.budgets.daily_usd", "-5"},
+			{"costs.budgets.action", "panic"},
+			{"costs.budgets.warn_percent", "0"},
+			{"costs.budgets.bogus", "1"},
+		} {
+			if err := runConfigSet(cmd, kv); err == nil {
+				t.Errorf("runConfigSet(%v) succeeded, want error", kv)
+			}
+		}
+
+		loaded, err := config.LoadOrCreateTownSettings(settingsPath)
+		if err != nil {
+			t.Fatalf("load settings: %v", err)
+		}
+		b := loaded.Costs.Budgets
+		if b.DailyUSD != 200 || b.GetRigDailyUSD("gastown") != 50.5 || b.GetRigDailyUSD("beads") != 0 ||
+			b.ConvoyUSD != 75 || b.GetWarnPercent() != 90 || b.GetAction() != config.BudgetActionEscalate {
+			t.Errorf("budget config = %+v", b)
+		}
+		if err := runConfigGet(cmd, []string{"costs.budgets.rig.beads"}); err != nil {
+			t.Errorf("runConfigGet failed: %v", err)
+		}
+		if err := runConfigGet(cmd, []string{"costs.budgets.bogus"}); err == nil {
+			t.Error("expected error for unknown budget key")
+		}
+	})
+
 	t.Run("convoy.notify_on_complete rejects non-boolean", func(t *testing.T) {
 		townRoot := setupTestTownForConfig(t)
 
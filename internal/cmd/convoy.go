@@ -181,11 +181,18 @@ TRACKING SEMANTICS:
   - Cross-prefix capable (convoy in hq-* tracks issues in gt-*, bd-*)
   - Landed: all tracked issues closed → notification sent to subscribers
 
+CONVOY DEPENDENCIES:
+  - A convoy can wait for upstream convoys to land ('gt convoy depend')
+  - Waiting convoys are not fed; staged ones launch when upstreams land
+  - 'gt convoy graph' shows the dependency DAG
+
 COMMANDS:
   create    Create a convoy tracking specified issues
   add       Add issues to an existing convoy (reopens if closed)
   close     Close a convoy (verifies all items done, or use --force)
   land      Land an owned convoy (cleanup worktrees, close convoy)
+  depend    Make a convoy wait for other convoys to land
+  graph     Show the convoy dependency graph
   status    Show convoy progress, tracked issues, and active workers
   list      List convoys (the dashboard view)`,
 }
@@ -270,6 +277,10 @@ in rig beads won't auto-close via bd close alone. This command bridges that gap.
 
 Can be run manually or by deacon patrol to ensure convoys close promptly.
 
+When a convoy lands, staged convoys waiting on it (see 'gt convoy depend')
+are launched once all of their upstream convoys have landed. Checking an
+already-closed convoy re-runs that launch step.
+
 Examples:
   gt convoy check              # Check all open convoys
   gt convoy check hq-cv-abc    # Check specific convoy
@@ -672,6 +683,13 @@ func runConvoyCheck(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Launch staged convoys that were waiting on the ones that just landed.
+	if !convoyCheckDryRun {
+		for _, c := range closed {
+			launchDownstreamConvoys(townBeads, c.ID)
+		}
+	}
+
 	return nil
 }
 
@@ -713,9 +731,14 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 		return fmt.Errorf("convoy '%s' has invalid lifecycle state: %w", convoyID, err)
 	}
 
-	// Check if convoy is already closed
+	// Check if convoy is already closed. Staged convoys waiting on it are
+	// still launched, so re-checking a landed convoy is a safe way to
+	// release its downstream convoys (the refinery relies on this).
 	if normalizeConvoyStatus(convoy.Status) == convoyStatusClosed {
 		fmt.Printf("%s Convoy %s is already closed\n", style.Dim.Render("○"), convoyID)
+		if !dryRun {
+			launchDownstreamConvoys(townBeads, convoyID)
+		}
 		return nil
 	}
 
@@ -764,6 +787,9 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	// Send completion notification
 	notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
 
+	// Launch staged convoys that were waiting on this one
+	launchDownstreamConvoys(townBeads, convoyID)
+
 	return nil
 }
 
@@ -910,6 +936,12 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 		notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
 	}
 
+	// A force-closed convoy was abandoned, not landed: leave convoys that
+	// wait on it staged for a human decision.
+	if !convoyCloseForce {
+		launchDownstreamConvoys(townBeads, convoyID)
+	}
+
 	return nil
 }
 
@@ -1069,6 +1101,9 @@ func runConvoyLand(cmd *cobra.Command, args []string) error {
 	// Phase 3: Send completion notifications
 	notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
 
+	// Phase 4: Launch staged convoys that were waiting on this one
+	launchDownstreamConvoys(townBeads, convoyID)
+
 	return nil
 }
 
@@ -1161,6 +1196,7 @@ type strandedConvoyInfo struct {
 	Title       string   `json:"title"`
 	ReadyCount  int      `json:"ready_count"`
 	ReadyIssues []string `json:"ready_issues"`
+	WaitingOn   []string `json:"waiting_on,omitempty"` // open upstream convoys; not fed until they land
 }
 
 // readyIssueInfo holds info about a ready (stranded) issue.
@@ -1197,6 +1233,8 @@ func runConvoyStranded(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  🚚 %s: %s\n", s.ID, s.Title)
 		if s.ReadyCount == 0 {
 			fmt.Printf("     Empty convoy (0 tracked issues) — needs cleanup\n")
+		} else if len(s.WaitingOn) > 0 {
+			fmt.Printf("     Ready issues: %d (waiting on upstream convoy(s): %s)\n", s.ReadyCount, strings.Join(s.WaitingOn, ", "))
 		} else {
 			fmt.Printf("     Ready issues: %d\n", s.ReadyCount)
 			for _, issueID := range s.ReadyIssues {
@@ -1210,7 +1248,9 @@ func runConvoyStranded(cmd *cobra.Command, args []string) error {
 	var feedable, empty []strandedConvoyInfo
 	for _, s := range stranded {
 		if s.ReadyCount > 0 {
-			feedable = append(feedable, s)
+			if len(s.WaitingOn) == 0 {
+				feedable = append(feedable, s)
+			}
 		} else {
 			empty = append(empty, s)
 		}
@@ -1312,6 +1352,7 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 				Title:       convoy.Title,
 				ReadyCount:  len(readyIssues),
 				ReadyIssues: readyIssues,
+				WaitingOn:   openUpstreamConvoys(townBeads, convoy.ID),
 			})
 		}
 	}
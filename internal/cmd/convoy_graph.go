@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	convoyops "github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Convoy graph command flags
+var (
+	convoyDependRemove bool
+	convoyGraphJSON    bool
+	convoyGraphAll     bool
+)
+
+var convoyDependCmd = &cobra.Command{
+	Use:   "depend <convoy-id> <upstream-convoy-id> [upstream-convoy-id...]",
+	Short: "Make a convoy wait for other convoys to land",
+	Long: `Declare that a convoy must wait for one or more upstream convoys to land
+(e.g. "API migration" before "client rollout").
+
+While any upstream convoy is open, the waiting convoy is not fed: the daemon
+skips it and 'gt convoy launch' refuses to launch it (use --force to
+override). A staged convoy (see 'gt convoy stage') launches automatically
+once its last upstream convoy lands.
+
+Dependencies that would create a cycle are rejected.
+
+Examples:
+  gt convoy depend hq-cv-client hq-cv-api          # client waits for api
+  gt convoy depend hq-cv-rollout hq-cv-a hq-cv-b   # waits for both
+  gt convoy depend hq-cv-client hq-cv-api --remove # drop the dependency`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runConvoyDepend,
+}
+
+var convoyGraphCmd = &cobra.Command{
+	Use:   "graph [convoy-id]",
+	Short: "Show the convoy dependency graph",
+	Long: `Show convoys that depend on other convoys as a DAG, in stages.
+
+Stage 1 holds convoys with no upstream convoys; each later stage waits for
+convoys in earlier stages. With a convoy ID, only the convoys connected to
+it are shown. Landed convoys are hidden unless --all is given or an open
+convoy still references them.
+
+Examples:
+  gt convoy graph
+  gt convoy graph hq-cv-client
+  gt convoy graph --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConvoyGraph,
+}
+
+func init() {
+	convoyDependCmd.Flags().BoolVar(&convoyDependRemove, "remove", false, "Remove the dependencies instead of adding them")
+	convoyGraphCmd.Flags().BoolVar(&convoyGraphJSON, "json", false, "Output as JSON")
+	convoyGraphCmd.Flags().BoolVar(&convoyGraphAll, "all", false, "Include landed convoys")
+
+	convoyCmd.AddCommand(convoyDependCmd)
+	convoyCmd.AddCommand(convoyGraphCmd)
+}
+
+// convoyUpstreamDep is an upstream convoy as reported by bd dep list.
+type convoyUpstreamDep struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	IssueType string `json:"issue_type"`
+}
+
+// listConvoyUpstream returns the convoys that convoyID waits for.
+// Tests override this variable to avoid shelling out to bd.
+var listConvoyUpstream = func(townBeads, convoyID string) ([]convoyUpstreamDep, error) {
+	cmd := exec.Command("bd", "dep", "list", convoyID, "--direction=down", "--type="+convoyops.ConvoyDepType, "--json")
+	cmd.Dir = townBeads
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("bd dep list %s: %w", convoyID, err)
+	}
+	if trimmed := strings.TrimSpace(string(out)); trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+	var deps []convoyUpstreamDep
+	if err := json.Unmarshal(out, &deps); err != nil {
+		return nil, fmt.Errorf("parsing dependencies of %s: %w", convoyID, err)
+	}
+	upstream := deps[:0]
+	for _, d := range deps {
+		if d.IssueType == "convoy" {
+			upstream = append(upstream, d)
+		}
+	}
+	return upstream, nil
+}
+
+// listAllConvoys returns every convoy in town beads, including staged and
+// closed ones. Tests override this variable to avoid shelling out to bd.
+var listAllConvoys = func(townBeads string) ([]bdShowResult, error) {
+	cmd := exec.Command("bd", "list", "--type=convoy", "--all", "--json")
+	cmd.Dir = townBeads
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing convoys: %w", err)
+	}
+	var convoys []bdShowResult
+	if err := json.Unmarshal(out, &convoys); err != nil {
+		return nil, fmt.Errorf("parsing convoy list: %w", err)
+	}
+	return convoys, nil
+}
+
+// loadConvoyGraph builds the convoy dependency graph. Upstream edges are read
+// for convoys that have not landed; a landed convoy can no longer wait on
+// anything, so its edges do not affect feeding or launching.
+func loadConvoyGraph(townBeads string) (*convoyops.Graph, error) {
+	convoys, err := listAllConvoys(townBeads)
+	if err != nil {
+		return nil, err
+	}
+
+	g := convoyops.NewGraph()
+	for _, c := range convoys {
+		g.AddConvoy(c.ID, c.Title, normalizeConvoyStatus(c.Status))
+	}
+	for _, c := range convoys {
+		if status := normalizeConvoyStatus(c.Status); status == convoyStatusClosed || status == "tombstone" {
+			continue
+		}
+		deps, err := listConvoyUpstream(townBeads, c.ID)
+		if err != nil {
+			style.PrintWarning("skipping dependencies of convoy %s: %v", c.ID, err)
+			continue
+		}
+		for _, d := range deps {
+			if _, known := g.Nodes[d.ID]; !known {
+				g.AddConvoy(d.ID, "", normalizeConvoyStatus(d.Status))
+			}
+			g.AddDependency(c.ID, d.ID)
+		}
+	}
+	return g, nil
+}
+
+// openUpstreamConvoys returns the upstream convoys of convoyID that have not
+// landed. Errors are treated as no upstream convoys (fail-open, like the
+// other convoy readiness checks).
+func openUpstreamConvoys(townBeads, convoyID string) []string {
+	deps, err := listConvoyUpstream(townBeads, convoyID)
+	if err != nil {
+		return nil
+	}
+	var open []string
+	for _, d := range deps {
+		if d.Status != convoyStatusClosed && d.Status != "tombstone" {
+			open = append(open, d.ID)
+		}
+	}
+	return open
+}
+
+func runConvoyDepend(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+
+	convoyID, upstreamIDs := args[0], args[1:]
+	for _, id := range args {
+		result, err := bdShow(id)
+		if err != nil {
+			return fmt.Errorf("convoy '%s' not found", id)
+		}
+		if result.IssueType != "convoy" {
+			return fmt.Errorf("'%s' is not a convoy (type: %s)", id, result.IssueType)
+		}
+	}
+
+	if convoyDependRemove {
+		for _, up := range upstreamIDs {
+			if out, err := BdCmd("dep", "remove", convoyID, up).Dir(townBeads).CombinedOutput(); err != nil {
+				return fmt.Errorf("removing dependency %s → %s: %w\n%s", convoyID, up, err, strings.TrimSpace(string(out)))
+			}
+			fmt.Printf("%s %s no longer waits for %s\n", style.Bold.Render("✓"), convoyID, up)
+		}
+		return nil
+	}
+
+	g, err := loadConvoyGraph(townBeads)
+	if err != nil {
+		return err
+	}
+	for _, up := range upstreamIDs {
+		if cycle := g.DependencyCycle(convoyID, up); cycle != nil {
+			return fmt.Errorf("%s waiting for %s would create a cycle: %s", convoyID, up, strings.Join(cycle, " → "))
+		}
+		if out, err := BdCmd("dep", "add", convoyID, up, "--type="+convoyops.ConvoyDepType).Dir(townBeads).WithAutoCommit().CombinedOutput(); err != nil {
+			return fmt.Errorf("adding dependency %s → %s: %w\n%s", convoyID, up, err, strings.TrimSpace(string(out)))
+		}
+		g.AddDependency(convoyID, up)
+		fmt.Printf("%s %s now waits for %s\n", style.Bold.Render("✓"), convoyID, up)
+	}
+
+	if open := g.OpenUpstream(convoyID); len(open) > 0 {
+		switch node := g.Nodes[convoyID]; {
+		case isStagedStatus(node.Status):
+			fmt.Printf("  Staged: launches automatically when %s land(s)\n", strings.Join(open, ", "))
+		case node.Status == convoyStatusOpen:
+			fmt.Printf("  Open: feeding pauses until %s land(s)\n", strings.Join(open, ", "))
+		}
+	}
+	return nil
+}
+
+// convoyGraphJSONOutput is the JSON form of `gt convoy graph`.
+type convoyGraphJSONOutput struct {
+	Stages [][]*convoyops.GraphNode `json:"stages"`
+}
+
+func runConvoyGraph(cmd *cobra.Command, args []string) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	g, err := loadConvoyGraph(townBeads)
+	if err != nil {
+		return err
+	}
+
+	focus := ""
+	if len(args) == 1 {
+		focus = args[0]
+		if _, ok := g.Nodes[focus]; !ok {
+			return fmt.Errorf("convoy '%s' not found", focus)
+		}
+	}
+	stages, err := convoyGraphStages(g, focus, convoyGraphAll)
+	if err != nil {
+		return err
+	}
+
+	if convoyGraphJSON {
+		out := convoyGraphJSONOutput{Stages: make([][]*convoyops.GraphNode, 0, len(stages))}
+		for _, stage := range stages {
+			nodes := make([]*convoyops.GraphNode, 0, len(stage))
+			for _, id := range stage {
+				nodes = append(nodes, g.Nodes[id])
+			}
+			out.Stages = append(out.Stages, nodes)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(stages) == 0 {
+		fmt.Println("No convoy dependencies.")
+		return nil
+	}
+	fmt.Print(renderConvoyGraph(g, stages))
+	return nil
+}
+
+// convoyGraphStages returns the staged IDs to display: convoys with
+// dependency edges (connected to focus, when set), without landed convoys
+// that no open convoy waits on unless all is set.
+func convoyGraphStages(g *convoyops.Graph, focus string, all bool) ([][]string, error) {
+	show := make(map[string]bool)
+	if focus != "" {
+		collectConnectedConvoys(g, focus, show)
+	} else {
+		for _, id := range g.Connected() {
+			show[id] = true
+		}
+	}
+	if !all {
+		for id := range show {
+			node := g.Nodes[id]
+			if node.Status != convoyStatusClosed && node.Status != "tombstone" {
+				continue
+			}
+			referenced := false
+			for _, down := range node.Downstream {
+				if n := g.Nodes[down]; n.Status != convoyStatusClosed && n.Status != "tombstone" {
+					referenced = true
+				}
+			}
+			if !referenced && id != focus {
+				delete(show, id)
+			}
+		}
+	}
+
+	ordered, err := g.Stages()
+	if err != nil {
+		return nil, err
+	}
+	var stages [][]string
+	for _, stage := range ordered {
+		var ids []string
+		for _, id := range stage {
+			if show[id] {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 0 {
+			stages = append(stages, ids)
+		}
+	}
+	return stages, nil
+}
+
+// collectConnectedConvoys marks every convoy reachable from id in either
+// direction.
+func collectConnectedConvoys(g *convoyops.Graph, id string, seen map[string]bool) {
+	if seen[id] {
+		return
+	}
+	seen[id] = true
+	node := g.Nodes[id]
+	if node == nil {
+		return
+	}
+	for _, next := range node.Upstream {
+		collectConnectedConvoys(g, next, seen)
+	}
+	for _, next := range node.Downstream {
+		collectConnectedConvoys(g, next, seen)
+	}
+}
+
+// renderConvoyGraph renders staged convoys with their upstream edges.
+func renderConvoyGraph(g *convoyops.Graph, stages [][]string) string {
+	var b strings.Builder
+	b.WriteString(style.Bold.Render("Convoy dependency graph") + "\n")
+	for i, stage := range stages {
+		fmt.Fprintf(&b, "\n%s\n", style.Bold.Render(fmt.Sprintf("Stage %d", i+1)))
+		for _, id := range stage {
+			node := g.Nodes[id]
+			title := node.Title
+			if title == "" {
+				title = "(unknown)"
+			}
+			fmt.Fprintf(&b, "  %s 🚚 %s: %s %s\n", convoyGraphIcon(node.Status), id, title, style.Dim.Render("["+node.Status+"]"))
+			if len(node.Upstream) == 0 {
+				continue
+			}
+			open := g.OpenUpstream(id)
+			line := "waits for " + strings.Join(node.Upstream, ", ")
+			if len(open) > 0 && node.Status != convoyStatusClosed {
+				line += fmt.Sprintf(" (%d open)", len(open))
+			}
+			fmt.Fprintf(&b, "      %s %s\n", style.Dim.Render("└─"), style.Dim.Render(line))
+		}
+	}
+	return b.String()
+}
+
+func convoyGraphIcon(status string) string {
+	switch {
+	case status == convoyStatusClosed:
+		return style.Success.Render("✓")
+	case status == convoyStatusOpen:
+		return style.Info.Render("●")
+	case isStagedStatus(status):
+		return style.Dim.Render("○")
+	default:
+		return style.Warning.Render("⚠")
+	}
+}
+
+// launchDownstreamConvoys launches staged_ready convoys whose upstream
+// convoys have now all landed, after landedID closed. Launch failures are
+// reported as warnings; the convoy stays staged for a manual launch.
+func launchDownstreamConvoys(townBeads, landedID string) []string {
+	g, err := loadConvoyGraph(townBeads)
+	if err != nil {
+		style.PrintWarning("couldn't check convoys waiting on %s: %v", landedID, err)
+		return nil
+	}
+
+	var launched []string
+	for _, id := range g.Launchable(landedID) {
+		if err := launchStagedConvoy(id, false); err != nil {
+			style.PrintWarning("couldn't launch convoy %s after %s landed: %v", id, landedID, err)
+			continue
+		}
+		fmt.Printf("%s Launched convoy 🚚 %s: %s (upstream %s landed)\n", style.Bold.Render("✓"), id, g.Nodes[id].Title, landedID)
+		launched = append(launched, id)
+	}
+	return launched
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func stubConvoyGraph(t *testing.T, convoys []bdShowResult, upstream map[string][]convoyUpstreamDep) {
+	t.Helper()
+	origList, origUp := listAllConvoys, listConvoyUpstream
+	t.Cleanup(func() { listAllConvoys, listConvoyUpstream = origList, origUp })
+
+	var queried []string
+	listAllConvoys = func(string) ([]bdShowResult, error) { return convoys, nil }
+	listConvoyUpstream = func(_, id string) ([]convoyUpstreamDep, error) {
+		queried = append(queried, id)
+		return upstream[id], nil
+	}
+	t.Cleanup(func() {
+		for _, id := range queried {
+			if id == "hq-cv-old" {
+				t.Errorf("dependencies of landed convoy %s should not be read", id)
+			}
+		}
+	})
+}
+
+func TestLoadConvoyGraph(t *testing.T) {
+	stubConvoyGraph(t,
+		[]bdShowResult{
+			{ID: "hq-cv-old", Title: "Old", Status: "closed"},
+			{ID: "hq-cv-api", Title: "API migration", Status: "closed"},
+			{ID: "hq-cv-client", Title: "Client rollout", Status: "staged_ready"},
+			{ID: "hq-cv-misc", Title: "Unrelated", Status: "open"},
+		},
+		map[string][]convoyUpstreamDep{
+			"hq-cv-client": {{ID: "hq-cv-api", Status: "closed", IssueType: "convoy"}},
+		},
+	)
+
+	g, err := loadConvoyGraph("/town/.beads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Launchable("hq-cv-api"); !reflect.DeepEqual(got, []string{"hq-cv-client"}) {
+		t.Errorf("Launchable(api) = %v, want [hq-cv-client]", got)
+	}
+
+	stages, err := convoyGraphStages(g, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"hq-cv-api"}, {"hq-cv-client"}}
+	if !reflect.DeepEqual(stages, want) {
+		t.Errorf("stages = %v, want %v (unconnected convoys hidden)", stages, want)
+	}
+
+	out := renderConvoyGraph(g, stages)
+	for _, s := range []string{"Stage 1", "Stage 2", "hq-cv-client", "waits for hq-cv-api"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("graph output missing %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestConvoyGraphStages_HidesLandedChains(t *testing.T) {
+	stubConvoyGraph(t,
+		[]bdShowResult{
+			{ID: "hq-cv-a", Title: "A", Status: "closed"},
+			{ID: "hq-cv-b", Title: "B", Status: "closed"},
+			{ID: "hq-cv-c", Title: "C", Status: "open"},
+		},
+		map[string][]convoyUpstreamDep{
+			"hq-cv-c": {{ID: "hq-cv-b", Status: "closed", IssueType: "convoy"}},
+		},
+	)
+	g, err := loadConvoyGraph("/town/.beads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// b → a is not read (b has landed), and b stays visible because the
+	// open convoy c still waits on it.
+	g.AddDependency("hq-cv-b", "hq-cv-a")
+
+	stages, _ := convoyGraphStages(g, "", false)
+	if want := [][]string{{"hq-cv-b"}, {"hq-cv-c"}}; !reflect.DeepEqual(stages, want) {
+		t.Errorf("stages = %v, want %v", stages, want)
+	}
+	stages, _ = convoyGraphStages(g, "", true)
+	if want := [][]string{{"hq-cv-a"}, {"hq-cv-b"}, {"hq-cv-c"}}; !reflect.DeepEqual(stages, want) {
+		t.Errorf("--all stages = %v, want %v", stages, want)
+	}
+}
+
+func TestOpenUpstreamConvoys(t *testing.T) {
+	stubConvoyGraph(t, nil, map[string][]convoyUpstreamDep{
+		"hq-cv-c": {
+			{ID: "hq-cv-a", Status: "closed", IssueType: "convoy"},
+			{ID: "hq-cv-b", Status: "open", IssueType: "convoy"},
+		},
+	})
+	if got := openUpstreamConvoys("/town/.beads", "hq-cv-c"); !reflect.DeepEqual(got, []string{"hq-cv-b"}) {
+		t.Errorf("openUpstreamConvoys = %v, want [hq-cv-b]", got)
+	}
+}
@@ -263,6 +263,55 @@ func renderLaunchOutput(convoyID string, waves []Wave, results []DispatchResult,
 	return b.String()
 }
 
+// launchStagedConvoy transitions a staged convoy to open and dispatches
+// Wave 1. A convoy waiting on open upstream convoys is not launched unless
+// force is set; it launches automatically when they land.
+func launchStagedConvoy(convoyID string, force bool) error {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	if open := openUpstreamConvoys(townBeads, convoyID); len(open) > 0 && !force {
+		return fmt.Errorf("convoy %s is waiting on upstream convoy(s) %s; it launches automatically when they land (use --force to launch now)",
+			convoyID, strings.Join(open, ", "))
+	}
+
+	if err := transitionConvoyToOpen(convoyID, force); err != nil {
+		return err
+	}
+
+	// Rebuild DAG from tracked beads and dispatch Wave 1.
+	beads, deps, err := collectConvoyBeads(convoyID)
+	if err != nil {
+		return fmt.Errorf("collect beads for dispatch: %w", err)
+	}
+
+	dag := buildConvoyDAG(beads, deps)
+	waves, err := computeWaves(dag)
+	if err != nil {
+		return fmt.Errorf("compute waves for dispatch: %w", err)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("resolve town root for dispatch: %w", err)
+	}
+
+	// Check for parked rigs before dispatch (gt-4owfd.1)
+	if err := checkParkedRigsForLaunch(dag, townRoot, force); err != nil {
+		return err
+	}
+
+	results, err := dispatchWave1(convoyID, dag, waves, townRoot)
+	if err != nil {
+		return fmt.Errorf("dispatch wave 1: %w", err)
+	}
+
+	// Report results.
+	fmt.Print(renderLaunchOutput(convoyID, waves, results, dag))
+	return nil
+}
+
 // runConvoyLaunch is the handler for `gt convoy launch`.
 func runConvoyLaunch(cmd *cobra.Command, args []string) error {
 	// Step 1: Validate args.
@@ -285,42 +334,7 @@ func runConvoyLaunch(cmd *cobra.Command, args []string) error {
 	if len(args) == 1 {
 		result := beadTypes[args[0]]
 		if result.IssueType == "convoy" && isStagedStatus(normalizeConvoyStatus(result.Status)) {
-			convoyID := args[0]
-
-			if err := transitionConvoyToOpen(convoyID, convoyLaunchForce); err != nil {
-				return err
-			}
-
-			// Rebuild DAG from tracked beads and dispatch Wave 1.
-			beads, deps, err := collectConvoyBeads(convoyID)
-			if err != nil {
-				return fmt.Errorf("collect beads for dispatch: %w", err)
-			}
-
-			dag := buildConvoyDAG(beads, deps)
-			waves, err := computeWaves(dag)
-			if err != nil {
-				return fmt.Errorf("compute waves for dispatch: %w", err)
-			}
-
-			townRoot, err := workspace.FindFromCwdOrError()
-			if err != nil {
-				return fmt.Errorf("resolve town root for dispatch: %w", err)
-			}
-
-			// Check for parked rigs before dispatch (gt-4owfd.1)
-			if err := checkParkedRigsForLaunch(dag, townRoot, convoyLaunchForce); err != nil {
-				return err
-			}
-
-			results, err := dispatchWave1(convoyID, dag, waves, townRoot)
-			if err != nil {
-				return fmt.Errorf("dispatch wave 1: %w", err)
-			}
-
-			// Report results.
-			fmt.Print(renderLaunchOutput(convoyID, waves, results, dag))
-			return nil
+			return launchStagedConvoy(args[0], convoyLaunchForce)
 		}
 	}
 
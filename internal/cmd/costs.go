@@ -2,10 +2,8 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,6 +15,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -34,6 +33,7 @@ var (
 	// Record subcommand flags
 	recordSession  string
 	recordWorkItem string
+	recordConvoy   string
 
 	// Digest subcommand flags
 	digestYesterday bool
@@ -47,11 +47,16 @@ var (
 var costsCmd = &cobra.Command{
 	Use:     "costs",
 	GroupID: GroupDiag,
-	Short:   "Show costs for running Claude sessions",
-	Long: `Display costs for Claude Code sessions in Gas Town.
+	Short:   "Show costs for running agent sessions",
+	Long: `Display costs for agent sessions in Gas Town.
 
-Costs are calculated from Claude Code transcript files at ~/.claude/projects/
-by summing token usage from assistant messages and applying model-specific pricing.
+Costs are calculated from each agent's session logs by summing token usage
+and applying model-specific pricing. Claude Code, Codex, Gemini and OpenCode
+logs are supported; each agent preset's usage_format selects the parser.
+Prices can be overridden per model with costs.pricing in town settings.
+
+Spend budgets (costs.budgets) cap daily town, per-rig and per-convoy spend;
+see 'gt costs budget'.
 
 Examples:
   gt costs              # Live costs from running sessions
@@ -64,7 +69,8 @@ Examples:
 
 Subcommands:
   gt costs record       # Record session cost to local log file (Stop hook)
-  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)`,
+  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)
+  gt costs budget       # Show spend against configured budgets`,
 	RunE: runCosts,
 }
 
@@ -73,8 +79,9 @@ var costsRecordCmd = &cobra.Command{
 	Short: "Record session cost to local log file (called by Stop hook)",
 	Long: `Record the final cost of a session to a local log file.
 
-This command is intended to be called from a Claude Code Stop hook.
-It reads token usage from the Claude Code transcript file (~/.claude/projects/...)
+This command is intended to be called from an agent Stop hook (or plugin
+event), and runs again from 'gt done' for agents without hooks. It reads
+token usage from the agent's session log (GT_AGENT selects the format)
 and calculates the cost based on model pricing, then appends it to
 ~/.gt/costs.jsonl. This is a simple append operation that never fails
 due to database availability.
@@ -84,7 +91,8 @@ permanent "Cost Report YYYY-MM-DD" bead for audit purposes.
 
 Examples:
   gt costs record --session gt-gastown-toast
-  gt costs record --session gt-gastown-toast --work-item gt-abc123`,
+  gt costs record --session gt-gastown-toast --work-item gt-abc123
+  gt costs record --session gt-gastown-toast --convoy hq-cv-abc`,
 	RunE: runCostsRecord,
 }
 
@@ -141,6 +149,7 @@ func init() {
 	costsCmd.AddCommand(costsRecordCmd)
 	costsRecordCmd.Flags().StringVar(&recordSession, "session", "", "Tmux session name to record")
 	costsRecordCmd.Flags().StringVar(&recordWorkItem, "work-item", "", "Work item ID (bead) for attribution")
+	costsRecordCmd.Flags().StringVar(&recordConvoy, "convoy", "", "Convoy ID for budget attribution")
 
 	// Add digest subcommand
 	costsCmd.AddCommand(costsDigestCmd)
@@ -159,6 +168,7 @@ type SessionCost struct {
 	Role    string  `json:"role"`
 	Rig     string  `json:"rig,omitempty"`
 	Worker  string  `json:"worker,omitempty"`
+	Agent   string  `json:"agent,omitempty"`
 	Cost    float64 `json:"cost_usd"`
 	Running bool    `json:"running"`
 }
@@ -187,56 +197,6 @@ type CostsOutput struct {
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
-
-// TokenUsage aggregates token usage across a session.
-type TokenUsage struct {
-	Model                    string
-	InputTokens              int
-	CacheCreationInputTokens int
-	CacheReadInputTokens     int
-	OutputTokens             int
-}
-
-// Model pricing per million tokens (as of Jan 2025).
-// See: https://www.anthropic.com/pricing
-var modelPricing = map[string]struct {
-	InputPerMillion       float64
-	OutputPerMillion      float64
-	CacheReadPerMillion   float64 // 90% discount on input price
-	CacheCreatePerMillion float64 // 25% premium on input price
-}{
-	// Claude Opus 4.5
-	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
-	// Claude Sonnet 4
-	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
-	// Claude Haiku 3.5
-	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
-	// Fallback for unknown models (use Sonnet pricing)
-	"default": {3.0, 15.0, 0.3, 3.75},
-}
-
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
 	if costsToday || costsWeek || costsByRole || costsByRig {
@@ -256,7 +216,9 @@ func runLiveCosts() error {
 		return fmt.Errorf("listing sessions: %w", err)
 	}
 
-	var costs []SessionCost
+	_, settings := costSettings()
+
+	var sessionCosts []SessionCost
 	var total float64
 
 	for _, sess := range sessions {
@@ -277,8 +239,9 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Extract cost from Claude transcript
-		cost, err := extractCostFromWorkDir(workDir)
+		// Extract cost from the agent's session log
+		agent, _ := t.GetEnvironment(sess, "GT_AGENT")
+		cost, _, err := extractSessionCost(settings, agent, workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", sess, err)
@@ -290,11 +253,12 @@ func runLiveCosts() error {
 		// Check if an agent appears to be running
 		running := t.IsAgentRunning(sess)
 
-		costs = append(costs, SessionCost{
+		sessionCosts = append(sessionCosts, SessionCost{
 			Session: sess,
 			Role:    role,
 			Rig:     rig,
 			Worker:  worker,
+			Agent:   agent,
 			Cost:    cost,
 			Running: running,
 		})
@@ -302,18 +266,18 @@ func runLiveCosts() error {
 	}
 
 	// Sort by session name
-	sort.Slice(costs, func(i, j int) bool {
-		return costs[i].Session < costs[j].Session
+	sort.Slice(sessionCosts, func(i, j int) bool {
+		return sessionCosts[i].Session < sessionCosts[j].Session
 	})
 
 	if costsJSON {
 		return outputCostsJSON(CostsOutput{
-			Sessions: costs,
+			Sessions: sessionCosts,
 			Total:    total,
 		})
 	}
 
-	return outputCostsHuman(costs, total)
+	return outputCostsHuman(sessionCosts, total)
 }
 
 func runCostsFromLedger() error {
@@ -699,144 +663,46 @@ func extractCost(content string) float64 {
 	return cost
 }
 
-// getClaudeProjectDir returns the Claude Code project directory for a working directory.
-// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
-func getClaudeProjectDir(workDir string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
+// costSettings returns the town root and settings that govern cost accounting.
+// Both are empty outside a town; built-in pricing then applies.
+func costSettings() (string, *config.TownSettings) {
+	townRoot := os.Getenv("GT_TOWN_ROOT")
+	if townRoot == "" {
+		townRoot, _ = workspace.FindFromCwd()
 	}
-
-	// Convert path to Claude's directory naming: replace / with -
-	// Keep leading slash - it becomes a leading dash in Claude's encoding
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	return filepath.Join(home, ".claude", "projects", projectName), nil
-}
-
-// findLatestTranscript finds the most recently modified .jsonl file in a directory.
-func findLatestTranscript(projectDir string) (string, error) {
-	var latestPath string
-	var latestTime time.Time
-
-	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() && path != projectDir {
-			return fs.SkipDir // Don't recurse into subdirectories
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
-			info, err := d.Info()
-			if err != nil {
-				return nil // Skip files we can't stat
-			}
-			if info.ModTime().After(latestTime) {
-				latestTime = info.ModTime()
-				latestPath = path
-			}
-		}
-		return nil
-	})
-
-	if err != nil {
-		return "", err
-	}
-	if latestPath == "" {
-		return "", fmt.Errorf("no transcript files found in %s", projectDir)
+	if townRoot == "" {
+		return "", nil
 	}
-	return latestPath, nil
-}
-
-// parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
-func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
 	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
-		}
-
-		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
-		}
-
-		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
+		return townRoot, nil
 	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return usage, nil
+	return townRoot, settings
 }
 
-// calculateCost converts token usage to USD cost based on model pricing.
-func calculateCost(usage *TokenUsage) float64 {
-	if usage == nil {
-		return 0.0
-	}
-
-	// Look up pricing for the model
-	pricing, ok := modelPricing[usage.Model]
-	if !ok {
-		pricing = modelPricing["default"]
+// usageFormatForAgent returns the usage log format for an agent name as found
+// in GT_AGENT. Custom agents resolve through their command; sessions without
+// an agent are assumed to be Claude Code.
+func usageFormatForAgent(settings *config.TownSettings, agent string) string {
+	if agent == "" {
+		return costs.FormatClaude
+	}
+	command := ""
+	if settings != nil {
+		if rc := settings.Agents[agent]; rc != nil {
+			command = rc.Command
+		}
 	}
-
-	// Calculate cost (prices are per million tokens)
-	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
-	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
-	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
-	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
-
-	return inputCost + cacheReadCost + cacheCreateCost + outputCost
+	return config.ResolveUsageFormat(agent, command)
 }
 
-// extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
-// This reads the most recent transcript file and sums all token usage.
-func extractCostFromWorkDir(workDir string) (float64, error) {
-	projectDir, err := getClaudeProjectDir(workDir)
-	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
-	}
-
-	transcriptPath, err := findLatestTranscript(projectDir)
-	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
-	}
-
-	usage, err := parseTranscriptUsage(transcriptPath)
+// extractSessionCost prices the most recent session of an agent in workDir.
+func extractSessionCost(settings *config.TownSettings, agent, workDir string) (float64, *costs.Usage, error) {
+	usage, err := costs.SessionUsage(usageFormatForAgent(settings, agent), workDir)
 	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
+		return 0, nil, err
 	}
-
-	return calculateCost(usage), nil
+	return costs.PriceTableFromSettings(settings).Cost(usage), usage, nil
 }
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
@@ -934,23 +800,11 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 }
 
 // CostLogEntry represents a single entry in the costs.jsonl log file.
-type CostLogEntry struct {
-	SessionID string    `json:"session_id"`
-	Role      string    `json:"role"`
-	Rig       string    `json:"rig,omitempty"`
-	Worker    string    `json:"worker,omitempty"`
-	CostUSD   float64   `json:"cost_usd"`
-	EndedAt   time.Time `json:"ended_at"`
-	WorkItem  string    `json:"work_item,omitempty"`
-}
+type CostLogEntry = costs.LedgerEntry
 
 // getCostsLogPath returns the path to the costs log file (~/.gt/costs.jsonl).
 func getCostsLogPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "/tmp/gt-costs.jsonl" // Fallback
-	}
-	return filepath.Join(home, ".gt", "costs.jsonl")
+	return costs.LedgerPath()
 }
 
 // runCostsRecord captures the final cost from a session and appends it to a local log file.
@@ -979,6 +833,28 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	entry, err := recordSessionCost(session, recordWorkItem, recordConvoy)
+	if err != nil {
+		return err
+	}
+	cost, workItem := entry.CostUSD, entry.WorkItem
+
+	// Output confirmation (silent if cost is zero and no work item)
+	if cost > 0 || workItem != "" {
+		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
+		if workItem != "" {
+			fmt.Printf(" (work: %s)", workItem)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// recordSessionCost prices a session's most recent agent log and appends it
+// to the costs ledger. Empty workItemFlag/convoyFlag fall back to the
+// GT_ISSUE/GT_CONVOY environment set when the session was spawned.
+func recordSessionCost(session, workItemFlag, convoyFlag string) (*CostLogEntry, error) {
 	// Get working directory from environment or tmux session
 	workDir := os.Getenv("GT_CWD")
 	if workDir == "" {
@@ -992,23 +868,44 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Extract cost from Claude transcript
+	// Attribute the session: agent, work item and convoy come from flags,
+	// the process environment, or the tmux session environment set at spawn.
+	t := tmux.NewTmux()
+	sessionEnv := func(key string) string {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+		v, _ := t.GetEnvironment(session, key)
+		return v
+	}
+	agent := sessionEnv("GT_AGENT")
+	workItem := workItemFlag
+	if workItem == "" {
+		workItem = sessionEnv("GT_ISSUE")
+	}
+	convoy := convoyFlag
+	if convoy == "" {
+		convoy = sessionEnv("GT_CONVOY")
+	}
+
+	// Extract cost from the agent's session log
+	_, settings := costSettings()
 	var cost float64
+	var model string
 	if workDir != "" {
-		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		c, usage, err := extractSessionCost(settings, agent, workDir)
 		if err != nil {
 			if costsVerbose {
-				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
+				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from session log: %v\n", err)
 			}
-			cost = 0.0
+		} else {
+			cost, model = c, usage.Model
 		}
 	}
 
 	// Parse session name
 	role, rig, worker := parseSessionName(session)
 
-	// Build log entry
 	entry := CostLogEntry{
 		SessionID: session,
 		Role:      role,
@@ -1016,48 +913,16 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		Worker:    worker,
 		CostUSD:   cost,
 		EndedAt:   time.Now(),
-		WorkItem:  recordWorkItem,
-	}
-
-	// Marshal to JSON
-	entryJSON, err := json.Marshal(entry)
-	if err != nil {
-		return fmt.Errorf("marshaling cost entry: %w", err)
-	}
-
-	// Append to log file
-	logPath := getCostsLogPath()
-
-	// Ensure directory exists
-	logDir := filepath.Dir(logPath)
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		return fmt.Errorf("creating log directory: %w", err)
+		WorkItem:  workItem,
+		Convoy:    convoy,
+		Agent:     agent,
+		Model:     model,
 	}
-
-	// Open file for append (create if doesn't exist).
-	// O_APPEND writes are atomic on POSIX for writes < PIPE_BUF (~4KB).
-	// A JSON log entry is ~200 bytes, so concurrent appends are safe.
-	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("opening costs log: %w", err)
-	}
-	defer f.Close()
-
-	// Write entry with newline
-	if _, err := f.Write(append(entryJSON, '\n')); err != nil {
-		return fmt.Errorf("writing to costs log: %w", err)
-	}
-
-	// Output confirmation (silent if cost is zero and no work item)
-	if cost > 0 || recordWorkItem != "" {
-		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
-		if recordWorkItem != "" {
-			fmt.Printf(" (work: %s)", recordWorkItem)
-		}
-		fmt.Println()
+	if err := costs.AppendLedger(getCostsLogPath(), entry); err != nil {
+		return nil, err
 	}
+	return &entry, nil
 
-	return nil
 }
 
 // deriveSessionName derives the tmux session name from GT_* environment variables.
@@ -1220,6 +1085,12 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating digest bead: %w", err)
 	}
 
+	// Preserve convoy spend before its ledger entries go away, so convoy
+	// budgets keep counting work that spans several days.
+	if err := archiveConvoySpend(targetDate); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to archive convoy spend: %v\n", err)
+	}
+
 	// Delete source entries from log file
 	deletedCount, deleteErr := deleteSessionCostEntries(targetDate)
 	if deleteErr != nil {
@@ -1235,6 +1106,27 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// archiveConvoySpend folds the target date's convoy spend into the town's
+// convoy spend archive.
+func archiveConvoySpend(targetDate time.Time) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil // Convoy budgets are per-town; nothing to archive outside one
+	}
+	entries, err := costs.ReadLedger(getCostsLogPath())
+	if err != nil {
+		return err
+	}
+	targetDay := targetDate.Format("2006-01-02")
+	var day []costs.LedgerEntry
+	for _, e := range entries {
+		if e.Convoy != "" && e.EndedAt.Format("2006-01-02") == targetDay {
+			day = append(day, e)
+		}
+	}
+	return costs.ArchiveConvoySpend(townRoot, costs.Tally(day, nil, targetDate).Convoy)
+}
+
 // querySessionCostEntries reads session cost entries from the local log file for a target date.
 func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 	logPath := getCostsLogPath()
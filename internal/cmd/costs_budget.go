@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var costsBudgetJSON bool
+
+var costsBudgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show spend against configured budgets",
+	Long: `Show today's spend against the budgets in town settings (costs.budgets).
+
+Budgets cap town-wide spend per day, each rig's spend per day, and each
+convoy's total spend. When a budget is exhausted the configured action
+applies to new dispatches charged to it:
+
+  warn      Warn but keep dispatching
+  pause     Hold back scheduler dispatch and refuse gt sling (default)
+  escalate  Pause, and raise a high-severity escalation once per day
+
+Spend comes from the ~/.gt/costs.jsonl ledger written by 'gt costs record'.
+
+Configure with:
+  gt config set costs.budgets.daily_usd 200
+  gt config set costs.budgets.rig_daily_usd 50
+  gt config set costs.budgets.rig.<rig> 100
+  gt config set costs.budgets.convoy_usd 75
+  gt config set costs.budgets.action escalate
+
+Examples:
+  gt costs budget
+  gt costs budget --json`,
+	RunE: runCostsBudget,
+}
+
+func init() {
+	costsCmd.AddCommand(costsBudgetCmd)
+	costsBudgetCmd.Flags().BoolVar(&costsBudgetJSON, "json", false, "Output as JSON")
+}
+
+// budgetEscalateTimeout bounds the gt escalate run for an exhausted budget.
+// It covers escalation delivery, including webhook retries.
+const budgetEscalateTimeout = escalationDeliveryTimeout + 30*time.Second
+
+// escalateBudgetFn raises an escalation for an exhausted budget.
+// Overridable in tests.
+var escalateBudgetFn = func(townRoot string, s costs.Status) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), budgetEscalateTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, gtPath, "escalate", "Budget exhausted: "+s.Key(),
+		"--severity", "high",
+		"--reason", s.String()+"; dispatch charged to this budget is paused",
+		"--source", "costs:budget")
+	cmd.Dir = townRoot
+	return cmd.Run()
+}
+
+// budgetGate checks dispatches against spend budgets. Spend is loaded once,
+// so one gate serves a whole dispatch cycle.
+type budgetGate struct {
+	townRoot string
+	cfg      *config.BudgetConfig
+	spend    *costs.Spend
+	now      time.Time
+	escalate bool            // raise escalations (false for dry runs)
+	reported map[string]bool // budget keys already reported this cycle
+}
+
+// loadBudgetGate returns a gate for the town's budgets, or nil if no budgets
+// are configured. Unreadable spend fails open with a warning: a broken
+// ledger must not stop all dispatch.
+func loadBudgetGate(townRoot string, settings *config.TownSettings, escalate bool) *budgetGate {
+	if settings == nil || settings.Costs == nil || settings.Costs.Budgets == nil {
+		return nil
+	}
+	now := time.Now()
+	spend, err := costs.LoadSpend(townRoot, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not load spend, budgets not enforced: %v\n", style.Warning.Render("⚠"), err)
+		return nil
+	}
+	return &budgetGate{
+		townRoot: townRoot,
+		cfg:      settings.Costs.Budgets,
+		spend:    spend,
+		now:      now,
+		escalate: escalate,
+		reported: make(map[string]bool),
+	}
+}
+
+// allow reports whether work on rig (in convoy, if any) may be dispatched.
+// Budgets at their warning threshold are reported once per gate; exhausted
+// budgets are escalated once per day when the action is "escalate".
+func (g *budgetGate) allow(rig, convoy string) bool {
+	if g == nil {
+		return true
+	}
+	v := g.spend.Evaluate(g.cfg, rig, convoy)
+	for _, s := range v.Statuses {
+		if g.reported[s.Key()] {
+			continue
+		}
+		g.reported[s.Key()] = true
+		switch {
+		case !s.Exceeded:
+			fmt.Printf("%s Budget warning: %s\n", style.Warning.Render("⚠"), s)
+		case v.Action == config.BudgetActionWarn:
+			fmt.Printf("%s Budget exhausted (action: warn): %s\n", style.Warning.Render("⚠"), s)
+		default:
+			fmt.Printf("%s Budget exhausted, holding dispatch: %s\n", style.Error.Render("⏸"), s)
+			if v.Action == config.BudgetActionEscalate && g.escalate {
+				g.escalateOnce(s)
+			}
+		}
+	}
+	return !v.Blocks()
+}
+
+func (g *budgetGate) escalateOnce(s costs.Status) {
+	first, err := costs.MarkEscalated(g.townRoot, s, g.now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not record budget escalation: %v\n", style.Warning.Render("⚠"), err)
+	}
+	if !first {
+		return
+	}
+	if err := escalateBudgetFn(g.townRoot, s); err != nil {
+		fmt.Fprintf(os.Stderr, "%s Could not escalate exhausted budget %s: %v\n", style.Warning.Render("⚠"), s.Key(), err)
+	}
+}
+
+// enforceSlingBudget checks budgets before gt sling dispatches beadID (empty
+// for batches) on rig. Returns an error when an exhausted budget blocks it.
+// With --ignore-budget the budgets are still reported but never escalated,
+// since the dispatch goes ahead.
+func enforceSlingBudget(townRoot, rig, beadID string) error {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil // No settings, no budgets
+	}
+	gate := loadBudgetGate(townRoot, settings, !slingDryRun && !slingIgnoreBudget)
+	if gate == nil {
+		return nil
+	}
+	var convoy string
+	if beadID != "" && gate.cfg.ConvoyUSD > 0 {
+		convoy = isTrackedByConvoy(beadID)
+	}
+	if gate.allow(rig, convoy) {
+		return nil
+	}
+	if slingIgnoreBudget {
+		fmt.Printf("%s Dispatching anyway (--ignore-budget)\n", style.Warning.Render("⚠"))
+		return nil
+	}
+	return fmt.Errorf("spend budget exhausted for %s (see 'gt costs budget'); use --ignore-budget to override", rig)
+}
+
+// budgetReport is the JSON output of gt costs budget.
+type budgetReport struct {
+	Action   string         `json:"action"`
+	Statuses []costs.Status `json:"budgets"`
+}
+
+func runCostsBudget(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	var cfg *config.BudgetConfig
+	if settings.Costs != nil {
+		cfg = settings.Costs.Budgets
+	}
+	spend, err := costs.LoadSpend(townRoot, time.Now())
+	if err != nil {
+		return err
+	}
+
+	report := budgetReport{Action: cfg.GetAction(), Statuses: budgetStatuses(cfg, spend)}
+	if costsBudgetJSON {
+		return outputJSON(report)
+	}
+
+	if cfg == nil {
+		fmt.Println(style.Dim.Render("No budgets configured (set costs.budgets in town settings)"))
+		return nil
+	}
+	fmt.Printf("%s (action when exhausted: %s)\n\n", style.Bold.Render("💰 Budgets"), report.Action)
+	if len(report.Statuses) == 0 {
+		fmt.Println(style.Dim.Render("No spend charged to a budget yet"))
+		return nil
+	}
+	for _, s := range report.Statuses {
+		icon := style.Success.Render("✓")
+		if s.Exceeded {
+			icon = style.Error.Render("✗")
+		} else if s.SpentUSD >= s.LimitUSD*float64(cfg.GetWarnPercent())/100 {
+			icon = style.Warning.Render("⚠")
+		}
+		fmt.Printf("  %s %s\n", icon, s)
+	}
+	return nil
+}
+
+// budgetStatuses lists spend against every configured budget that has spend
+// charged to it: the town, each rig with spend today, and each convoy.
+func budgetStatuses(cfg *config.BudgetConfig, spend *costs.Spend) []costs.Status {
+	if cfg == nil {
+		return []costs.Status{}
+	}
+	statuses := []costs.Status{}
+	add := func(scope, name string, limit, spent float64) {
+		if limit > 0 {
+			statuses = append(statuses, costs.Status{
+				Scope: scope, Name: name, LimitUSD: limit, SpentUSD: spent, Exceeded: spent >= limit,
+			})
+		}
+	}
+	add(costs.ScopeDay, "", cfg.DailyUSD, spend.Day)
+	for _, rig := range sortedSpendKeys(spend.RigDay) {
+		add(costs.ScopeRig, rig, cfg.GetRigDailyUSD(rig), spend.RigDay[rig])
+	}
+	for _, convoy := range sortedSpendKeys(spend.Convoy) {
+		add(costs.ScopeConvoy, convoy, cfg.ConvoyUSD, spend.Convoy[convoy])
+	}
+	return statuses
+}
+
+func sortedSpendKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+func TestBudgetGate_HoldsExhaustedAndEscalatesOnce(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	townRoot := t.TempDir()
+
+	now := time.Now()
+	ledger := filepath.Join(home, ".gt", "costs.jsonl")
+	for _, e := range []costs.LedgerEntry{
+		{SessionID: "gt-gastown-toast", Rig: "gastown", CostUSD: 30, EndedAt: now},
+		{SessionID: "gt-beads-nux", Rig: "beads", Convoy: "hq-cv-1", CostUSD: 5, EndedAt: now},
+	} {
+		if err := costs.AppendLedger(ledger, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var escalated []string
+	orig := escalateBudgetFn
+	escalateBudgetFn = func(_ string, s costs.Status) error {
+		escalated = append(escalated, s.Key())
+		return nil
+	}
+	t.Cleanup(func() { escalateBudgetFn = orig })
+
+	settings := config.NewTownSettings()
+	settings.Costs = &config.CostsConfig{Budgets: &config.BudgetConfig{
+		RigDailyUSD: 25,
+		ConvoyUSD:   5,
+		Action:      config.BudgetActionEscalate,
+	}}
+
+	for cycle := 0; cycle < 2; cycle++ {
+		gate := loadBudgetGate(townRoot, settings, true)
+		if gate == nil {
+			t.Fatal("loadBudgetGate returned nil with budgets configured")
+		}
+		pending := []capacity.PendingBead{
+			{ID: "ctx-1", TargetRig: "gastown"},
+			{ID: "ctx-2", TargetRig: "beads"},
+			{ID: "ctx-3", TargetRig: "beads", Context: &capacity.SlingContextFields{Convoy: "hq-cv-1"}},
+		}
+		allowed := filterByBudget(pending, gate)
+		if len(allowed) != 1 || allowed[0].ID != "ctx-2" {
+			t.Fatalf("cycle %d: allowed = %+v, want only ctx-2", cycle, allowed)
+		}
+	}
+
+	if len(escalated) != 2 {
+		t.Errorf("escalated = %v, want rig:gastown and convoy:hq-cv-1 once each", escalated)
+	}
+}
+
+func TestBudgetGate_NilWithoutBudgets(t *testing.T) {
+	if gate := loadBudgetGate(t.TempDir(), config.NewTownSettings(), false); gate != nil {
+		t.Fatalf("loadBudgetGate = %+v, want nil without budgets", gate)
+	}
+	var gate *budgetGate
+	if !gate.allow("gastown", "hq-cv-1") {
+		t.Error("nil gate should allow dispatch")
+	}
+}
+
+func TestEnforceSlingBudget_IgnoreBudgetSkipsEscalation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	townRoot := t.TempDir()
+
+	ledger := filepath.Join(home, ".gt", "costs.jsonl")
+	entry := costs.LedgerEntry{SessionID: "gt-gastown-toast", Rig: "gastown", CostUSD: 30, EndedAt: time.Now()}
+	if err := costs.AppendLedger(ledger, entry); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.Costs = &config.CostsConfig{Budgets: &config.BudgetConfig{
+		RigDailyUSD: 25,
+		Action:      config.BudgetActionEscalate,
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	var escalated []string
+	origEscalate := escalateBudgetFn
+	escalateBudgetFn = func(_ string, s costs.Status) error {
+		escalated = append(escalated, s.Key())
+		return nil
+	}
+	origIgnore, origDryRun := slingIgnoreBudget, slingDryRun
+	t.Cleanup(func() {
+		escalateBudgetFn = origEscalate
+		slingIgnoreBudget, slingDryRun = origIgnore, origDryRun
+	})
+	slingDryRun = false
+
+	slingIgnoreBudget = true
+	if err := enforceSlingBudget(townRoot, "gastown", ""); err != nil {
+		t.Fatalf("--ignore-budget: %v", err)
+	}
+	if len(escalated) != 0 {
+		t.Fatalf("--ignore-budget escalated %v, want none", escalated)
+	}
+
+	slingIgnoreBudget = false
+	if err := enforceSlingBudget(townRoot, "gastown", ""); err == nil {
+		t.Fatal("exhausted budget should block dispatch")
+	}
+	if len(escalated) != 1 {
+		t.Errorf("escalated = %v, want rig:gastown once", escalated)
+	}
+}
@@ -33,6 +33,18 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+The server also exposes a read-only typed JSON API under /api/v1
+(rigs, polecats, crew, merge queue, issues, convoys, mail). List
+endpoints are paginated with ?limit= and ?offset=; GET /api/v1 lists
+the available endpoints. Mail endpoints serve only the overseer's
+mailbox.
+
+GET /api/v1/events/stream pushes events from .events.jsonl as they are
+written, over Server-Sent Events or WebSocket (Upgrade: websocket).
+Filter with ?type=, ?actor=, ?rig= and ?visibility= (comma-separated);
+resume with Last-Event-ID or ?last_event_id=, or replay the whole log
+with ?from=start.
+
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
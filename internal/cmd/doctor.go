@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -17,6 +18,8 @@ var (
 	doctorRestartSessions bool
 	doctorNoStart         bool
 	doctorSlow            string
+	doctorFormat          string
+	doctorOutput          string
 )
 
 var doctorCmd = &cobra.Command{
@@ -100,6 +103,29 @@ Patrol checks:
   - patrol-not-stuck         Detect stale wisps (>1h)
   - patrol-plugins-accessible Verify plugin directories
 
+Plugin checks:
+  External checks live in doctor.d/ at the town root and in each rig.
+  Each entry is an executable, or a *.toml file declaring a command:
+
+    name = "disk-space"
+    category = "Infrastructure"
+    command = "./check-disk.sh"
+    fix = "./prune-worktrees.sh"   # optional, run by --fix
+    timeout = "10s"                # optional, default 30s
+
+  A check prints one JSON object on stdout:
+    {"status": "ok|warning|error", "message": "...", "details": [...],
+     "fix_hint": "...", "fixable": true}
+  Executables that report fixable are re-run with --fix under --fix.
+  Rig plugins are named <rig>/<name> and get GT_RIG; all plugins get
+  GT_TOWN_ROOT (and GT_DOCTOR_VERBOSE=1 with -v).
+
+Report formats:
+  --format json    One JSON document with per-check status and timing
+  --format junit   JUnit XML, one test suite per category (errors fail)
+  Use -o FILE to write the report to a file while streaming text output;
+  without -o the report replaces the text output on stdout.
+
 Use --fix to attempt automatic fixes for issues that support it.
 Use --no-start with --fix to suppress starting the daemon and agents.
 Use --rig to check a specific rig instead of the entire workspace.
@@ -116,10 +142,21 @@ func init() {
 	doctorCmd.Flags().StringVar(&doctorSlow, "slow", "", "Highlight slow checks (optional threshold, default 1s)")
 	// Allow --slow without a value (uses default 1s)
 	doctorCmd.Flags().Lookup("slow").NoOptDefVal = "1s"
+	doctorCmd.Flags().StringVar(&doctorFormat, "format", doctor.FormatText, "Report format: text, json, junit")
+	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "", "Write the json/junit report to a file instead of stdout")
 	rootCmd.AddCommand(doctorCmd)
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	switch doctorFormat {
+	case doctor.FormatText, doctor.FormatJSON, doctor.FormatJUnit:
+	default:
+		return fmt.Errorf("invalid --format %q: must be text, json, or junit", doctorFormat)
+	}
+	if doctorOutput != "" && doctorFormat == doctor.FormatText {
+		return fmt.Errorf("--output requires --format json or junit")
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -246,6 +283,9 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		d.RegisterAll(doctor.RigChecks()...)
 	}
 
+	// External checks from town and rig doctor.d/ directories
+	d.RegisterAll(doctor.DiscoverPluginChecks(townRoot, doctorRig)...)
+
 	// Parse slow threshold (0 = disabled)
 	var slowThreshold time.Duration
 	if doctorSlow != "" {
@@ -256,17 +296,30 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Run checks with streaming output
-	fmt.Println() // Initial blank line
+	// Run checks with streaming output, unless a machine-readable report
+	// is going to stdout
+	textOut := doctorFormat == doctor.FormatText || doctorOutput != ""
+	var stream io.Writer
+	if textOut {
+		stream = os.Stdout
+		fmt.Println() // Initial blank line
+	}
 	var report *doctor.Report
 	if doctorFix {
-		report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
+		report = d.FixStreaming(ctx, stream, slowThreshold)
 	} else {
-		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
+		report = d.RunStreaming(ctx, stream, slowThreshold)
 	}
 
 	// Print summary (checks were already printed during streaming)
-	report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
+	if textOut {
+		report.PrintSummaryOnly(os.Stdout, doctorVerbose, slowThreshold)
+	}
+	if doctorFormat != doctor.FormatText {
+		if err := writeDoctorReport(report, doctorFormat, doctorOutput); err != nil {
+			return err
+		}
+	}
 
 	// Exit with error code if there are errors
 	if report.HasErrors() {
@@ -275,3 +328,28 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// writeDoctorReport writes report in a machine-readable format to path, or
+// to stdout when path is empty.
+func writeDoctorReport(report *doctor.Report, format, path string) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("creating report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var err error
+	if format == doctor.FormatJUnit {
+		err = report.WriteJUnit(w)
+	} else {
+		err = report.WriteJSON(w)
+	}
+	if err != nil {
+		return fmt.Errorf("writing %s report: %w", format, err)
+	}
+	return nil
+}
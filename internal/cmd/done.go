@@ -20,12 +20,15 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var doneCmd = &cobra.Command{
@@ -60,6 +63,9 @@ var (
 	doneStatus        string
 	doneCleanupStatus string
 	doneResume        bool
+
+	// doneSpan is the gt.done span, ended early by selfKillSession.
+	doneSpan trace.Span
 )
 
 // Valid exit types for gt done
@@ -80,7 +86,16 @@ func init() {
 }
 
 func runDone(cmd *cobra.Command, args []string) (retErr error) {
-	defer func() { telemetry.RecordDone(context.Background(), strings.ToUpper(doneStatus), retErr) }()
+	// gt done runs inside the polecat session, so its span joins the trace of
+	// the sling that assigned the work (TRACEPARENT from the session env).
+	ctx, span := telemetry.StartSpan(context.Background(), "gt.done",
+		attribute.String("gt.exit", strings.ToUpper(doneStatus)))
+	telemetry.SetTraceContext(ctx)
+	doneSpan = span
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+		telemetry.RecordDone(ctx, strings.ToUpper(doneStatus), retErr)
+	}()
 	// Guard: Only polecats should call gt done
 	// Crew, deacons, witnesses etc. don't use gt done - they persist across tasks.
 	// Polecat sessions end with gt done — the session is cleaned up, but the
@@ -782,11 +797,24 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			// Record what ran the work so the refinery's verdict feeds
+			// routing track records (gt sling --auto).
+			agentPreset, workFormula := doneRoutingFields(townRoot, rigName, sourceIssueForNoMerge)
+			if agentPreset != "" {
+				description += fmt.Sprintf("\nagent_preset: %s", agentPreset)
+			}
+			if workFormula != "" {
+				description += fmt.Sprintf("\nformula: %s", workFormula)
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
 			description += "\nlast_conflict_sha: null"
 			description += "\nconflict_task_id: null"
+			// Carry the trace so the refinery's gates and merge join it.
+			if tp := telemetry.CurrentTraceParent(); tp != "" {
+				description += fmt.Sprintf("\ntrace_parent: %s", tp)
+			}
 
 			mrIssue, err := bd.Create(beads.CreateOptions{
 				Title:       title,
@@ -1422,6 +1450,30 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 	_ = events.LogFeed(events.TypeSessionDeath, agentID,
 		events.SessionDeathPayload(sessionName, agentID, "self-clean: done means idle", "gt done"))
 
+	// Record final session cost before the transcript's owner goes away.
+	// Agents without a Stop hook (e.g. Codex) are only costed here.
+	if _, err := recordSessionCost(sessionName, "", ""); err != nil {
+		style.PrintWarning("could not record session cost: %v", err)
+	}
+
+	// The kill below ends this process before main's telemetry shutdown runs:
+	// end the gt.done span and flush it now.
+	if doneSpan != nil {
+		doneSpan.End()
+	}
+	flushCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	_ = telemetry.Shutdown(flushCtx)
+	cancel()
+
+	// A sandboxed polecat can't reach tmux: end the sandbox instead, which
+	// closes the pane and the session with it.
+	if sandbox.Active() {
+		if err := killSandboxProcesses(); err != nil {
+			return fmt.Errorf("ending sandbox for %s: %w", sessionName, err)
+		}
+		return nil
+	}
+
 	// Kill our own tmux session with proper process cleanup
 	// This will terminate Claude and all child processes, completing the self-cleaning cycle.
 	// We use KillSessionWithProcessesExcluding to ensure no orphaned processes are left behind,
@@ -1435,3 +1487,16 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 
 	return nil
 }
+
+// doneRoutingFields returns the agent preset this session runs (GT_AGENT, or
+// the rig's polecat agent) and the formula the source issue was slung with.
+func doneRoutingFields(townRoot, rigName string, sourceIssue *beads.Issue) (agent, formula string) {
+	agent = os.Getenv("GT_AGENT")
+	if agent == "" && townRoot != "" && rigName != "" {
+		agent, _ = config.ResolveRoleAgentName("polecat", townRoot, filepath.Join(townRoot, rigName))
+	}
+	if fields := beads.ParseAttachmentFields(sourceIssue); fields != nil {
+		formula = fields.AttachedFormula
+	}
+	return agent, formula
+}
@@ -108,14 +108,18 @@ var escalateStaleCmd = &cobra.Command{
 	Long: `Find and re-escalate escalations that haven't been acknowledged within the threshold.
 
 When run without --dry-run, this command:
-1. Finds escalations older than the stale threshold (default: 4h)
-2. Bumps their severity: low→medium→high→critical
+1. Finds unacked escalations not routed within the stale threshold (default: 4h)
+2. Bumps their severity: low→medium→high→critical (critical is re-paged)
 3. Re-routes them according to the new severity level
-4. Sends mail to the new routing targets
+4. Sends mail to the new routing targets and whoever is on call
+5. Records the hop on the escalation bead
 
-Respects max_reescalations from config (default: 2) to prevent infinite escalation.
+Each re-escalation restarts the clock. Respects max_reescalations from config
+(default: 2) to prevent infinite escalation, and holds hops below
+quiet_hours.min_severity during quiet hours.
 
-The threshold is configured in settings/escalation.json.
+The daemon runs this on every heartbeat. The threshold, quiet hours and
+on-call windows are configured in settings/escalation.json.
 
 Examples:
   gt escalate stale              # Re-escalate stale escalations
@@ -1,16 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
@@ -64,7 +67,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	// Dry run mode
 	if escalateDryRun {
 		actions := escalationConfig.GetRouteForSeverity(severity)
-		targets := extractMailTargetsFromActions(actions)
+		targets := escalationMailTargets(escalationConfig, actions, time.Now())
 		fmt.Printf("Would create escalation:\n")
 		fmt.Printf("  Severity: %s\n", severity)
 		fmt.Printf("  Description: %s\n", description)
@@ -97,7 +100,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 
 	// Get routing actions for this severity
 	actions := escalationConfig.GetRouteForSeverity(severity)
-	targets := extractMailTargetsFromActions(actions)
+	targets := escalationMailTargets(escalationConfig, actions, time.Now())
 
 	// Send mail to each target (actions with "mail:" prefix)
 	router := mail.NewRouter(townRoot)
@@ -128,8 +131,21 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	// Process external notification actions (email:, sms:, slack, webhook, log)
+	// and record each delivery attempt on the escalation bead.
+	dispatcher := escalation.NewDispatcher(escalationConfig, townRoot)
+	deliveries := executeExternalActions(dispatcher, actions, &escalation.Notification{
+		ID:          issue.ID,
+		Severity:    severity,
+		Title:       description,
+		Reason:      escalateReason,
+		Source:      escalateSource,
+		From:        agentID,
+		RelatedBead: escalateRelatedBead,
+	})
+	if err := bd.RecordEscalationDeliveries(issue.ID, deliveries); err != nil {
+		style.PrintWarning("failed to record delivery status on %s: %v", issue.ID, err)
+	}
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -151,6 +167,9 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		if escalateSource != "" {
 			result["source"] = escalateSource
 		}
+		if len(deliveries) > 0 {
+			result["deliveries"] = deliveries
+		}
 		out, _ := json.MarshalIndent(result, "", "  ")
 		fmt.Println(string(out))
 	} else {
@@ -316,33 +335,36 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 
 	// Detect who is reescalating
 	reescalatedBy := detectSender()
-	if reescalatedBy == "" {
+	if isDaemonDispatch() {
+		reescalatedBy = "daemon"
+	} else if reescalatedBy == "" {
 		reescalatedBy = "system"
 	}
 
+	now := time.Now()
+	onCall := ""
+	if w := escalationConfig.OnCallAt(now); w != nil {
+		onCall = w.Name
+	}
+
 	// Dry run mode - just show what would happen
 	if escalateDryRun {
 		fmt.Printf("Would re-escalate %d stale escalations (threshold: %s):\n\n", len(stale), threshold)
 		for _, issue := range stale {
 			fields := beads.ParseEscalationFields(issue.Description)
-			newSeverity := getNextSeverity(fields.Severity)
-			willSkip := maxReescalations > 0 && fields.ReescalationCount >= maxReescalations
-			if fields.Severity == "critical" {
-				willSkip = true
-			}
+			newSeverity := config.NextSeverity(fields.Severity)
 
 			emoji := severityEmoji(fields.Severity)
-			if willSkip {
+			if reason := reescalationHoldReason(escalationConfig, fields, now); reason != "" {
 				fmt.Printf("  %s %s [SKIP] %s\n", emoji, issue.ID, issue.Title)
-				if fields.Severity == "critical" {
-					fmt.Printf("     Already at critical severity\n")
-				} else {
-					fmt.Printf("     Already at max reescalations (%d)\n", maxReescalations)
-				}
+				fmt.Printf("     %s\n", reason)
 			} else {
 				fmt.Printf("  %s %s %s\n", emoji, issue.ID, issue.Title)
 				fmt.Printf("     %s → %s (reescalation %d/%d)\n",
 					fields.Severity, newSeverity, fields.ReescalationCount+1, maxReescalations)
+				if onCall != "" {
+					fmt.Printf("     On call: %s\n", onCall)
+				}
 			}
 			fmt.Println()
 		}
@@ -353,9 +375,30 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 	var results []*beads.ReescalationResult
 	router := mail.NewRouter(townRoot)
 	defer router.WaitPendingNotifications()
+	dispatcher := escalation.NewDispatcher(escalationConfig, townRoot)
 
 	for _, issue := range stale {
-		result, err := bd.ReescalateEscalation(issue.ID, reescalatedBy, maxReescalations)
+		fields := beads.ParseEscalationFields(issue.Description)
+		if reason := reescalationHoldReason(escalationConfig, fields, now); reason != "" {
+			results = append(results, &beads.ReescalationResult{
+				ID:          issue.ID,
+				Title:       issue.Title,
+				OldSeverity: fields.Severity,
+				Skipped:     true,
+				SkipReason:  reason,
+			})
+			continue
+		}
+
+		// Route through the next severity, paging whoever is on call now.
+		actions := escalationConfig.GetRouteForSeverity(config.NextSeverity(fields.Severity))
+		targets := escalationMailTargets(escalationConfig, actions, now)
+
+		result, err := bd.ReescalateEscalation(issue.ID, beads.EscalationHop{
+			By:      reescalatedBy,
+			OnCall:  onCall,
+			Targets: targets,
+		}, maxReescalations)
 		if err != nil {
 			style.PrintWarning("failed to reescalate %s: %v", issue.ID, err)
 			continue
@@ -364,9 +407,6 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 
 		// If not skipped, re-route to new severity targets
 		if !result.Skipped {
-			actions := escalationConfig.GetRouteForSeverity(result.NewSeverity)
-			targets := extractMailTargetsFromActions(actions)
-
 			// Send mail to each target about the reescalation
 			for _, target := range targets {
 				msg := &mail.Message{
@@ -394,15 +434,31 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			// Notify external channels on the new severity's route.
+			deliveries := executeExternalActions(dispatcher, actions, &escalation.Notification{
+				ID:           result.ID,
+				Severity:     result.NewSeverity,
+				Title:        result.Title,
+				From:         reescalatedBy,
+				Reescalation: true,
+			})
+			if err := bd.RecordEscalationDeliveries(result.ID, deliveries); err != nil {
+				style.PrintWarning("failed to record delivery status on %s: %v", result.ID, err)
+			}
+
 			// Log to activity feed
-			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
+			payload := map[string]interface{}{
 				"escalation_id":    result.ID,
 				"reescalated":      true,
 				"old_severity":     result.OldSeverity,
 				"new_severity":     result.NewSeverity,
 				"reescalation_num": result.ReescalationNum,
 				"targets":          strings.Join(targets, ","),
-			})
+			}
+			if onCall != "" {
+				payload["on_call"] = onCall
+			}
+			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, payload)
 		}
 	}
 
@@ -423,28 +479,69 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if reescalated == 0 && skipped > 0 {
-		fmt.Printf("No escalations re-escalated (%d at max level)\n", skipped)
-		return nil
-	}
-
-	fmt.Printf("🔄 Re-escalated %d stale escalations:\n\n", reescalated)
-	for _, result := range results {
-		if result.Skipped {
-			continue
+	if reescalated > 0 {
+		fmt.Printf("🔄 Re-escalated %d stale escalations:\n\n", reescalated)
+		for _, result := range results {
+			if result.Skipped {
+				continue
+			}
+			emoji := severityEmoji(result.NewSeverity)
+			fmt.Printf("  %s %s: %s → %s (reescalation %d)\n",
+				emoji, result.ID, result.OldSeverity, result.NewSeverity, result.ReescalationNum)
 		}
-		emoji := severityEmoji(result.NewSeverity)
-		fmt.Printf("  %s %s: %s → %s (reescalation %d)\n",
-			emoji, result.ID, result.OldSeverity, result.NewSeverity, result.ReescalationNum)
+	} else {
+		fmt.Printf("No escalations re-escalated\n")
 	}
 
 	if skipped > 0 {
-		fmt.Printf("\n  (%d skipped - at max level)\n", skipped)
+		fmt.Printf("\n  %d skipped:\n", skipped)
+		for _, result := range results {
+			if result.Skipped {
+				fmt.Printf("  %s %s: %s\n", style.Dim.Render("○"), result.ID, result.SkipReason)
+			}
+		}
 	}
 
 	return nil
 }
 
+// reescalationHoldReason explains why a stale escalation will not be
+// re-escalated at now, or returns "" if it will be.
+func reescalationHoldReason(cfg *config.EscalationConfig, fields *beads.EscalationFields, now time.Time) string {
+	maxReescalations := cfg.GetMaxReescalations()
+	if maxReescalations > 0 && fields.ReescalationCount >= maxReescalations {
+		return fmt.Sprintf("already at max reescalations (%d)", maxReescalations)
+	}
+	if next := config.NextSeverity(fields.Severity); cfg.HoldForQuietHours(next, now) {
+		return fmt.Sprintf("held for quiet hours (%s → %s goes out after %s)", fields.Severity, next, cfg.QuietHours.End)
+	}
+	return ""
+}
+
+// escalationMailTargets returns the mail targets for a route at now: its
+// mail: actions plus the active on-call window's mail targets, which are
+// paged on any route that notifies beyond the bead itself.
+func escalationMailTargets(cfg *config.EscalationConfig, actions []string, now time.Time) []string {
+	targets := extractMailTargetsFromActions(actions)
+	notifies := false
+	for _, action := range actions {
+		if action != "bead" {
+			notifies = true
+			break
+		}
+	}
+	w := cfg.OnCallAt(now)
+	if w == nil || !notifies {
+		return targets
+	}
+	for _, target := range w.Mail {
+		if !slices.Contains(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
 func getNextSeverity(severity string) string {
 	switch severity {
 	case "low":
@@ -505,6 +602,8 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedBy":    fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
+			"deliveries":  fields.Deliveries,
+			"hops":        fields.Hops,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -531,6 +630,33 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if len(fields.Deliveries) > 0 {
+		fmt.Printf("  Deliveries:\n")
+		for _, d := range fields.Deliveries {
+			line := fmt.Sprintf("    %s %s %s", deliveryIcon(d.Status), d.Action, d.Status)
+			if d.Attempts > 1 {
+				line += fmt.Sprintf(" after %d attempts", d.Attempts)
+			}
+			line += " (" + formatRelativeTime(d.At) + ")"
+			if d.Detail != "" {
+				line += ": " + d.Detail
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(fields.Hops) > 0 {
+		fmt.Printf("  Re-escalations:\n")
+		for _, h := range fields.Hops {
+			line := fmt.Sprintf("    %s → %s by %s (%s)", h.From, h.To, h.By, formatRelativeTime(h.At))
+			if h.OnCall != "" {
+				line += ", on call: " + h.OnCall
+			}
+			if len(h.Targets) > 0 {
+				line += ", paged " + strings.Join(h.Targets, ", ")
+			}
+			fmt.Println(line)
+		}
+	}
 
 	return nil
 }
@@ -553,41 +679,41 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
-	for _, action := range actions {
-		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
-			}
-
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
-
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
-			}
-
-		case action == "log":
-			// Log action always succeeds - writes to escalation log file
-			// TODO: Implement actual log file writing
-			fmt.Printf("  📝 Logged to escalation log\n")
+// escalationDeliveryTimeout bounds the total time spent delivering one
+// escalation to external channels (including webhook retries).
+const escalationDeliveryTimeout = 2 * time.Minute
+
+// executeExternalActions delivers an escalation to its external notification
+// actions (email:, sms:, slack, webhook, log), prints the outcome of each, and
+// returns the delivery records for the escalation bead.
+func executeExternalActions(d *escalation.Dispatcher, actions []string, n *escalation.Notification) []beads.EscalationDelivery {
+	ctx, cancel := context.WithTimeout(context.Background(), escalationDeliveryTimeout)
+	defer cancel()
+
+	deliveries := d.Deliver(ctx, actions, n)
+	for _, rec := range deliveries {
+		switch rec.Status {
+		case beads.DeliverySent:
+			fmt.Printf("  %s %s delivered\n", deliveryIcon(rec.Status), rec.Action)
+		case beads.DeliverySkipped:
+			style.PrintWarning("%s action skipped: %s", rec.Action, rec.Detail)
+		default:
+			style.PrintWarning("%s delivery failed after %d attempt(s): %s", rec.Action, rec.Attempts, rec.Detail)
 		}
 	}
+	return deliveries
+}
+
+// deliveryIcon returns a status marker for an escalation delivery.
+func deliveryIcon(status string) string {
+	switch status {
+	case beads.DeliverySent:
+		return "✓"
+	case beads.DeliverySkipped:
+		return "○"
+	default:
+		return "✗"
+	}
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -8,6 +11,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/escalation"
 )
 
 func TestGetNextSeverity(t *testing.T) {
@@ -332,90 +336,138 @@ func TestDetectSenderFallback(t *testing.T) {
 }
 
 func TestExecuteExternalActions(t *testing.T) {
-	// executeExternalActions prints warnings/info but doesn't return errors.
-	// We test that it doesn't panic with various configurations.
+	var slackPosts int
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slackPosts++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slack.Close()
+
+	var smsTo []string
+	smsGateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			To string `json:"to"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		smsTo = append(smsTo, body.To)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer smsGateway.Close()
 
 	tests := []struct {
-		name    string
-		actions []string
-		cfg     *config.EscalationConfig
+		name       string
+		actions    []string
+		cfg        *config.EscalationConfig
+		wantStatus map[string]string
 	}{
 		{
-			name:    "no external actions",
-			actions: []string{"bead", "mail:mayor"},
-			cfg:     &config.EscalationConfig{},
+			name:       "no external actions",
+			actions:    []string{"bead", "mail:mayor"},
+			cfg:        &config.EscalationConfig{},
+			wantStatus: map[string]string{},
 		},
 		{
-			name:    "email action without contact",
-			actions: []string{"email:human"},
-			cfg:     &config.EscalationConfig{},
+			name:       "email action without contact",
+			actions:    []string{"email:human"},
+			cfg:        &config.EscalationConfig{},
+			wantStatus: map[string]string{"email:human": beads.DeliverySkipped},
 		},
 		{
-			name:    "email action with contact",
+			name:    "email action without smtp channel",
 			actions: []string{"email:human"},
 			cfg: &config.EscalationConfig{
-				Contacts: config.EscalationContacts{
-					HumanEmail: "test@example.com",
-				},
+				Contacts: config.EscalationContacts{HumanEmail: "test@example.com"},
 			},
+			wantStatus: map[string]string{"email:human": beads.DeliverySkipped},
 		},
 		{
-			name:    "sms action without contact",
-			actions: []string{"sms:human"},
-			cfg:     &config.EscalationConfig{},
+			name:       "sms action without contact",
+			actions:    []string{"sms:human"},
+			cfg:        &config.EscalationConfig{},
+			wantStatus: map[string]string{"sms:human": beads.DeliverySkipped},
 		},
 		{
 			name:    "sms action with contact",
 			actions: []string{"sms:human"},
 			cfg: &config.EscalationConfig{
-				Contacts: config.EscalationContacts{
-					HumanSMS: "+15551234567",
+				Contacts: config.EscalationContacts{HumanSMS: "+15551234567"},
+				Channels: &config.EscalationChannels{
+					SMS: &config.EscalationSMSConfig{URL: smsGateway.URL},
 				},
 			},
+			wantStatus: map[string]string{"sms:human": beads.DeliverySent},
 		},
 		{
-			name:    "slack action without webhook",
-			actions: []string{"slack"},
-			cfg:     &config.EscalationConfig{},
+			name:       "slack action without webhook",
+			actions:    []string{"slack"},
+			cfg:        &config.EscalationConfig{},
+			wantStatus: map[string]string{"slack": beads.DeliverySkipped},
 		},
 		{
 			name:    "slack action with webhook",
 			actions: []string{"slack"},
 			cfg: &config.EscalationConfig{
-				Contacts: config.EscalationContacts{
-					SlackWebhook: "https://hooks.slack.com/test",
-				},
+				Contacts: config.EscalationContacts{SlackWebhook: slack.URL},
 			},
+			wantStatus: map[string]string{"slack": beads.DeliverySent},
 		},
 		{
-			name:    "log action",
-			actions: []string{"log"},
-			cfg:     &config.EscalationConfig{},
+			name:       "log action",
+			actions:    []string{"log"},
+			cfg:        &config.EscalationConfig{},
+			wantStatus: map[string]string{"log": beads.DeliverySent},
 		},
 		{
 			name:    "all external actions combined",
-			actions: []string{"email:human", "sms:human", "slack", "log"},
+			actions: []string{"bead", "email:human", "sms:human", "slack", "log"},
 			cfg: &config.EscalationConfig{
 				Contacts: config.EscalationContacts{
 					HumanEmail:   "test@example.com",
 					HumanSMS:     "+15551234567",
-					SlackWebhook: "https://hooks.slack.com/test",
+					SlackWebhook: slack.URL,
 				},
 			},
+			wantStatus: map[string]string{
+				"email:human": beads.DeliverySkipped,
+				"sms:human":   beads.DeliverySkipped,
+				"slack":       beads.DeliverySent,
+				"log":         beads.DeliverySent,
+			},
 		},
 		{
-			name:    "empty actions",
-			actions: []string{},
-			cfg:     &config.EscalationConfig{},
+			name:       "empty actions",
+			actions:    []string{},
+			cfg:        &config.EscalationConfig{},
+			wantStatus: map[string]string{},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Should not panic
-			executeExternalActions(tt.actions, tt.cfg, "hq-test", "high", "Test escalation")
+			d := escalation.NewDispatcher(tt.cfg, t.TempDir())
+			deliveries := executeExternalActions(d, tt.actions, &escalation.Notification{
+				ID:       "hq-test",
+				Severity: "high",
+				Title:    "Test escalation",
+				From:     "test",
+			})
+			if len(deliveries) != len(tt.wantStatus) {
+				t.Fatalf("got %d deliveries, want %d: %+v", len(deliveries), len(tt.wantStatus), deliveries)
+			}
+			for _, rec := range deliveries {
+				if want := tt.wantStatus[rec.Action]; rec.Status != want {
+					t.Errorf("%s status = %q, want %q (%s)", rec.Action, rec.Status, want, rec.Detail)
+				}
+			}
 		})
 	}
+
+	if slackPosts != 2 {
+		t.Errorf("slack received %d posts, want 2", slackPosts)
+	}
+	if len(smsTo) != 1 || smsTo[0] != "+15551234567" {
+		t.Errorf("sms gateway received %v, want [+15551234567]", smsTo)
+	}
 }
 
 func TestRunEscalateValidation(t *testing.T) {
@@ -485,3 +537,46 @@ func TestGetNextSeverityMatchesConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestReescalationHoldReason(t *testing.T) {
+	cfg := &config.EscalationConfig{
+		Timezone:         "UTC",
+		MaxReescalations: func() *int { n := 2; return &n }(),
+		QuietHours:       &config.EscalationQuietHours{Start: "22:00", End: "07:00"},
+	}
+	night, _ := time.Parse(time.RFC3339, "2026-10-16T23:30:00Z")
+	noon, _ := time.Parse(time.RFC3339, "2026-10-16T12:00:00Z")
+
+	if got := reescalationHoldReason(cfg, &beads.EscalationFields{Severity: "medium"}, noon); got != "" {
+		t.Errorf("daytime medium hop held: %q", got)
+	}
+	if got := reescalationHoldReason(cfg, &beads.EscalationFields{Severity: "medium"}, night); !strings.Contains(got, "quiet hours") {
+		t.Errorf("overnight medium → high hop = %q, want held for quiet hours", got)
+	}
+	if got := reescalationHoldReason(cfg, &beads.EscalationFields{Severity: "high"}, night); got != "" {
+		t.Errorf("overnight high → critical hop held: %q", got)
+	}
+	if got := reescalationHoldReason(cfg, &beads.EscalationFields{Severity: "critical", ReescalationCount: 2}, noon); !strings.Contains(got, "max reescalations") {
+		t.Errorf("hop past max = %q, want max reescalations", got)
+	}
+}
+
+func TestEscalationMailTargets_OnCall(t *testing.T) {
+	cfg := &config.EscalationConfig{
+		Timezone: "UTC",
+		OnCall:   []config.EscalationOnCall{{Name: "night", Start: "18:00", End: "09:00", Mail: []string{"overseer", "mayor/"}}},
+	}
+	night, _ := time.Parse(time.RFC3339, "2026-10-16T23:30:00Z")
+	noon, _ := time.Parse(time.RFC3339, "2026-10-16T12:00:00Z")
+
+	got := escalationMailTargets(cfg, []string{"bead", "mail:mayor/"}, night)
+	if strings.Join(got, ",") != "mayor/,overseer" {
+		t.Errorf("on-call targets = %v, want [mayor/ overseer]", got)
+	}
+	if got := escalationMailTargets(cfg, []string{"bead", "mail:mayor/"}, noon); strings.Join(got, ",") != "mayor/" {
+		t.Errorf("off-hours targets = %v, want [mayor/]", got)
+	}
+	if got := escalationMailTargets(cfg, []string{"bead"}, night); len(got) != 0 {
+		t.Errorf("bead-only route paged on-call: %v", got)
+	}
+}
@@ -9,10 +9,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
@@ -25,12 +27,13 @@ import (
 
 // Formula command flags
 var (
-	formulaListJSON   bool
-	formulaShowJSON   bool
-	formulaRunPR      int
-	formulaRunRig     string
-	formulaRunDryRun  bool
-	formulaCreateType string
+	formulaListJSON     bool
+	formulaShowJSON     bool
+	formulaShowResolved bool
+	formulaRunPR        int
+	formulaRunRig       string
+	formulaRunDryRun    bool
+	formulaCreateType   string
 )
 
 var formulaCmd = &cobra.Command{
@@ -90,9 +93,16 @@ Shows:
   - Steps with dependencies
   - Composition rules (extends, aspects)
 
+With --resolved, gt flattens the formula's composition and prints the
+result as TOML: steps and vars inherited via extends (with the formula's
+own overrides applied) and step fragments pulled in by [[include]], with
+their IDs prefixed. Formulas are looked up in .beads/formulas/ (project),
+$GT_ROOT/.beads/formulas/ (town), then the formulas built into gt.
+
 Examples:
   gt formula show shiny
-  gt formula show rule-of-five --json`,
+  gt formula show rule-of-five --json
+  gt formula show mol-polecat-work --resolved`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaShow,
 }
@@ -154,6 +164,7 @@ func init() {
 
 	// Show flags
 	formulaShowCmd.Flags().BoolVar(&formulaShowJSON, "json", false, "Output as JSON")
+	formulaShowCmd.Flags().BoolVar(&formulaShowResolved, "resolved", false, "Print the formula with extends and includes flattened")
 
 	// Run flags
 	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run formula on")
@@ -188,6 +199,9 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 // runFormulaShow delegates to bd formula show
 func runFormulaShow(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
+	if formulaShowResolved {
+		return runFormulaShowResolved(formulaName)
+	}
 	bdArgs := []string{"formula", "show", formulaName}
 	if formulaShowJSON {
 		bdArgs = append(bdArgs, "--json")
@@ -199,6 +213,18 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 	return bdCmd.Run()
 }
 
+// runFormulaShowResolved prints a formula with its composition flattened.
+func runFormulaShowResolved(name string) error {
+	f, err := newFormulaResolver().Resolve(name)
+	if err != nil {
+		return err
+	}
+	if formulaShowJSON {
+		return outputJSON(f)
+	}
+	return toml.NewEncoder(os.Stdout).Encode(f)
+}
+
 // runFormulaRun executes a formula by spawning a convoy of polecats.
 // For convoy-type formulas, it creates a convoy bead, creates leg beads,
 // and slings each leg to a separate polecat with leg-specific prompts.
@@ -670,9 +696,31 @@ func findFormulaFile(name string) (string, error) {
 	return "", fmt.Errorf("formula '%s' not found in search paths", name)
 }
 
-// parseFormulaFile parses a formula file using the formula package's TOML parser.
+// parseFormulaFile parses a formula file using the formula package's TOML parser,
+// resolving any extends and includes.
 func parseFormulaFile(path string) (*formula.Formula, error) {
-	return formula.ParseFile(path)
+	return newFormulaResolver().ResolveFile(path)
+}
+
+// newFormulaResolver returns a formula resolver for the current directory.
+func newFormulaResolver() *formula.Resolver {
+	cwd, _ := os.Getwd()
+	townRoot, _ := workspace.FindFromCwd()
+	return newFormulaResolverAt(cwd, townRoot)
+}
+
+// newFormulaResolverAt returns a formula resolver with the search path
+// findFormulaFile uses: project formulas, town formulas, the user's
+// ~/.beads/formulas, then the embedded ones.
+func newFormulaResolverAt(workDir, townRoot string) *formula.Resolver {
+	r := formula.NewResolver(workDir, townRoot)
+	if home, err := os.UserHomeDir(); err == nil {
+		userDir := filepath.Join(home, ".beads", "formulas")
+		if !slices.Contains(r.Dirs, userDir) {
+			r.Dirs = append(r.Dirs, userDir)
+		}
+	}
+	return r
 }
 
 // renderTemplate renders a Go text/template with the given context map
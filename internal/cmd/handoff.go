@@ -673,12 +673,46 @@ var claudeEnvVars = []string{
 	// GT telemetry source vars — needed to recompute derived vars after handoff
 	"GT_OTEL_METRICS_URL",
 	"GT_OTEL_LOGS_URL",
+	"GT_OTEL_TRACES_URL",
+	// Trace context — the respawned session stays in its work's trace
+	"TRACEPARENT",
+}
+
+// Test seams for restartEnv.
+var (
+	currentSessionName = tmux.CurrentSessionName
+	sessionEnvironment = func(sessionName string) (map[string]string, error) {
+		return tmux.NewTmux().GetAllEnvironment(sessionName)
+	}
+)
+
+// restartEnv returns a lookup for the environment of the session being
+// restarted: the process env when it is the caller's own session, otherwise
+// the target's tmux session env. The caller may be another agent (gt handoff
+// <role>) or the daemon (gt quota rotate --auto), whose GT_AGENT, process
+// names and credentials belong to a different session.
+func restartEnv(sessionName string) func(string) (string, bool) {
+	if current := currentSessionName(); current != "" && current == sessionName {
+		return os.LookupEnv
+	}
+	env, _ := sessionEnvironment(sessionName)
+	return func(key string) (string, bool) {
+		val, ok := env[key]
+		return val, ok
+	}
 }
 
 // buildRestartCommand creates the command to run when respawning a session's pane.
 // This needs to be the actual command to execute (e.g., claude), not a session attach command.
 // The command includes a cd to the correct working directory for the role.
 func buildRestartCommand(sessionName string) (string, error) {
+	return buildRestartCommandWithAgent(sessionName, "")
+}
+
+// buildRestartCommandWithAgent is buildRestartCommand with an optional agent
+// override. A non-empty agentOverride replaces the session's current agent
+// (GT_AGENT), e.g. when quota failover moves a session to another provider.
+func buildRestartCommandWithAgent(sessionName, agentOverride string) (string, error) {
 	// Detect town root from current directory
 	townRoot := detectTownRootFromCwd()
 	if townRoot == "" {
@@ -721,12 +755,15 @@ func buildRestartCommand(sessionName string) (string, error) {
 	// 4. run claude with the startup beacon (triggers immediate context loading)
 	// Use exec to ensure clean process replacement.
 	//
-	// Check if current session is using a non-default agent (GT_AGENT env var).
+	// Check if the session is using a non-default agent (GT_AGENT env var).
 	// If so, preserve it across handoff by using the override variant.
 	// Fall back to tmux session environment if process env doesn't have it,
 	// since exec env vars may not propagate through all agent runtimes.
-	currentAgent, agentInEnv := os.LookupEnv("GT_AGENT")
-	if !agentInEnv {
+	lookupEnv := restartEnv(sessionName)
+	currentAgent, agentInEnv := lookupEnv("GT_AGENT")
+	if agentOverride != "" {
+		currentAgent = agentOverride
+	} else if !agentInEnv {
 		// GT_AGENT not in process env at all — try tmux session environment
 		// as fallback, since exec env vars may not propagate through all runtimes.
 		t := tmux.NewTmux()
@@ -791,7 +828,7 @@ func buildRestartCommand(sessionName string) (string, error) {
 	// Without this, custom agents that shadow built-in presets (e.g., custom
 	// "codex" running "opencode") would revert to GT_AGENT-based lookup after
 	// handoff, causing false liveness failures.
-	if processNames := os.Getenv("GT_PROCESS_NAMES"); processNames != "" {
+	if processNames, _ := lookupEnv("GT_PROCESS_NAMES"); processNames != "" && agentOverride == "" {
 		// Preserve existing process names from environment
 		exports = append(exports, "GT_PROCESS_NAMES="+processNames)
 	} else if currentAgent != "" {
@@ -800,9 +837,9 @@ func buildRestartCommand(sessionName string) (string, error) {
 		exports = append(exports, "GT_PROCESS_NAMES="+strings.Join(resolved, ","))
 	}
 
-	// Add Claude-related env vars from current environment
+	// Add Claude-related env vars from the session's environment
 	for _, name := range claudeEnvVars {
-		if val := os.Getenv(name); val != "" {
+		if val, _ := lookupEnv(name); val != "" {
 			// Shell-escape the value in case it contains special chars
 			exports = append(exports, fmt.Sprintf("%s=%q", name, val))
 		}
@@ -834,11 +871,12 @@ func buildRestartCommand(sessionName string) (string, error) {
 // would use stale values from the previous agent.
 func updateSessionEnvForHandoff(t *tmux.Tmux, sessionName, agentOverride string) {
 	// Resolve current agent using the same priority as buildRestartCommandWithAgent
+	lookupEnv := restartEnv(sessionName)
 	var currentAgent string
 	if agentOverride != "" {
 		currentAgent = agentOverride
 	} else {
-		currentAgent = os.Getenv("GT_AGENT")
+		currentAgent, _ = lookupEnv("GT_AGENT")
 		if currentAgent == "" {
 			if val, err := t.GetEnvironment(sessionName, "GT_AGENT"); err == nil && val != "" {
 				currentAgent = val
@@ -874,7 +912,7 @@ func updateSessionEnvForHandoff(t *tmux.Tmux, sessionName, agentOverride string)
 	}
 	if processNames == "" {
 		// Preserve existing value or compute from current agent
-		if pn := os.Getenv("GT_PROCESS_NAMES"); pn != "" {
+		if pn, _ := lookupEnv("GT_PROCESS_NAMES"); pn != "" {
 			processNames = pn
 		} else {
 			resolved := config.ResolveProcessNames(currentAgent, "")
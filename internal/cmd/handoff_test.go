@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -518,9 +519,25 @@ func TestWarnHandoffGitStatus(t *testing.T) {
 	})
 }
 
+// stubRestartSessions makes current the caller's tmux session and serves
+// other sessions' tmux environments from envs.
+func stubRestartSessions(t *testing.T, current string, envs map[string]map[string]string) {
+	t.Helper()
+	origCurrent, origEnv := currentSessionName, sessionEnvironment
+	t.Cleanup(func() { currentSessionName, sessionEnvironment = origCurrent, origEnv })
+	currentSessionName = func() string { return current }
+	sessionEnvironment = func(sessionName string) (map[string]string, error) {
+		if env, ok := envs[sessionName]; ok {
+			return env, nil
+		}
+		return nil, fmt.Errorf("no session %s", sessionName)
+	}
+}
+
 func TestHandoffProcessNames(t *testing.T) {
 	t.Run("same-agent restart preserves GT_PROCESS_NAMES from env", func(t *testing.T) {
 		setupHandoffTestRegistry(t)
+		stubRestartSessions(t, "gt-crew-propane", nil)
 
 		tmpTown := t.TempDir()
 		mayorDir := filepath.Join(tmpTown, "mayor")
@@ -546,6 +563,7 @@ func TestHandoffProcessNames(t *testing.T) {
 
 	t.Run("first boot without GT_PROCESS_NAMES computes from config", func(t *testing.T) {
 		setupHandoffTestRegistry(t)
+		stubRestartSessions(t, "gt-crew-propane", nil)
 
 		tmpTown := t.TempDir()
 		mayorDir := filepath.Join(tmpTown, "mayor")
@@ -570,6 +588,44 @@ func TestHandoffProcessNames(t *testing.T) {
 			t.Errorf("expected GT_PROCESS_NAMES=node,claude computed from config, got: %q", cmd)
 		}
 	})
+
+	t.Run("restarting another session reads its tmux env", func(t *testing.T) {
+		setupHandoffTestRegistry(t)
+		// The caller (e.g. the daemon) is not in tmux and has its own env.
+		stubRestartSessions(t, "", map[string]map[string]string{
+			"gt-crew-propane": {
+				"GT_AGENT":          "claude",
+				"GT_PROCESS_NAMES":  "node,claude",
+				"ANTHROPIC_API_KEY": "session-key",
+			},
+		})
+
+		tmpTown := t.TempDir()
+		mayorDir := filepath.Join(tmpTown, "mayor")
+		os.MkdirAll(mayorDir, 0755)
+		os.WriteFile(filepath.Join(mayorDir, "town.json"), []byte(`{"name":"test"}`), 0644)
+
+		t.Setenv("GT_ROOT", tmpTown)
+		t.Setenv("GT_AGENT", "codex")
+		t.Setenv("GT_PROCESS_NAMES", "gt")
+		t.Setenv("ANTHROPIC_API_KEY", "daemon-key")
+		origCwd, _ := os.Getwd()
+		os.Chdir(os.TempDir())
+		t.Cleanup(func() { os.Chdir(origCwd) })
+
+		cmd, err := buildRestartCommand("gt-crew-propane")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, want := range []string{"GT_AGENT=claude", "GT_PROCESS_NAMES=node,claude", `ANTHROPIC_API_KEY="session-key"`} {
+			if !strings.Contains(cmd, want) {
+				t.Errorf("expected %s from the session env, got: %q", want, cmd)
+			}
+		}
+		if strings.Contains(cmd, "daemon-key") || strings.Contains(cmd, "codex") {
+			t.Errorf("caller's env leaked into the restart command: %q", cmd)
+		}
+	})
 }
 
 // TestCollectGitState verifies that collectGitState returns deterministic
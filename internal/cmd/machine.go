@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Machine command flags
+var (
+	machineJSON     bool
+	machineKeyPath  string
+	machineTownPath string
+)
+
+var machineCmd = &cobra.Command{
+	Use:     "machine",
+	GroupID: GroupConfig,
+	Short:   "Manage machines that can host rigs",
+	RunE:    requireSubcommand,
+	Long: `Manage the machines Gas Town can run rigs on.
+
+The local machine is always registered. Remote machines are reached over
+SSH using the system ssh binary with connection multiplexing, so rigs can
+live on a build box while the mayor stays local.
+
+Machines are stored in mayor/machines.json.
+
+Commands:
+  gt machine list                        List registered machines
+  gt machine add <name> <user@host>      Register an SSH machine
+  gt machine test <name>                 Check a machine is reachable
+  gt machine remove <name>               Unregister a machine`,
+}
+
+var machineListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered machines",
+	Long: `List all registered machines.
+
+Examples:
+  gt machine list
+  gt machine list --json`,
+	Args: cobra.NoArgs,
+	RunE: runMachineList,
+}
+
+var machineAddCmd = &cobra.Command{
+	Use:   "add <name> <user@host[:port]>",
+	Short: "Register an SSH machine",
+	Long: `Register a remote machine reachable over SSH.
+
+The host may include a port (user@host:2222). Authentication uses
+--key if given, otherwise your ssh config and agent. Connections run in
+batch mode, so the key must not need a passphrase prompt.
+
+Run 'gt machine test <name>' afterwards to verify the connection.
+
+Examples:
+  gt machine add buildbox dev@build.example.com
+  gt machine add buildbox dev@10.0.0.5:2222 --key ~/.ssh/gastown --town-path /home/dev/gt`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMachineAdd,
+}
+
+var machineTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Check a machine is reachable",
+	Long: `Connect to a machine and check it can host rigs.
+
+Verifies the connection, that tmux is installed, and that the town path
+exists (when configured).
+
+Examples:
+  gt machine test buildbox`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMachineTest,
+}
+
+var machineRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Unregister a machine",
+	Long: `Remove a machine from the registry.
+
+The local machine cannot be removed.
+
+Examples:
+  gt machine remove buildbox`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMachineRemove,
+}
+
+func init() {
+	machineListCmd.Flags().BoolVar(&machineJSON, "json", false, "Output as JSON")
+	machineAddCmd.Flags().StringVar(&machineKeyPath, "key", "", "SSH private key path")
+	machineAddCmd.Flags().StringVar(&machineTownPath, "town-path", "", "Path to the town root on the remote machine")
+
+	machineCmd.AddCommand(machineListCmd)
+	machineCmd.AddCommand(machineAddCmd)
+	machineCmd.AddCommand(machineTestCmd)
+	machineCmd.AddCommand(machineRemoveCmd)
+
+	rootCmd.AddCommand(machineCmd)
+}
+
+// loadMachineRegistry opens the town's machine registry.
+func loadMachineRegistry() (*connection.MachineRegistry, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return connection.NewMachineRegistry(constants.MayorMachinesPath(townRoot))
+}
+
+func runMachineList(cmd *cobra.Command, args []string) error {
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+
+	machines := reg.List()
+	sort.Slice(machines, func(i, j int) bool {
+		// Local first, then by name
+		if (machines[i].Name == "local") != (machines[j].Name == "local") {
+			return machines[i].Name == "local"
+		}
+		return machines[i].Name < machines[j].Name
+	})
+
+	if machineJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(machines)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Machines"))
+	for _, m := range machines {
+		fmt.Printf("  %s  %s", style.Bold.Render(m.Name), style.Dim.Render(m.Type))
+		if m.Host != "" {
+			fmt.Printf("  %s", m.Host)
+		}
+		fmt.Println()
+		if m.TownPath != "" {
+			fmt.Printf("    town: %s\n", m.TownPath)
+		}
+		if m.KeyPath != "" {
+			fmt.Printf("    key:  %s\n", m.KeyPath)
+		}
+	}
+	return nil
+}
+
+func runMachineAdd(cmd *cobra.Command, args []string) error {
+	name, host := args[0], args[1]
+	if strings.ContainsAny(name, ":/ ") {
+		return fmt.Errorf("invalid machine name %q: must not contain ':', '/' or spaces", name)
+	}
+
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	if _, err := reg.Get(name); err == nil {
+		return fmt.Errorf("machine '%s' already exists", name)
+	}
+
+	m := &connection.Machine{
+		Name:     name,
+		Type:     "ssh",
+		Host:     host,
+		KeyPath:  machineKeyPath,
+		TownPath: machineTownPath,
+	}
+	if err := reg.Add(m); err != nil {
+		return fmt.Errorf("adding machine: %w", err)
+	}
+
+	fmt.Printf("%s Added machine %s (%s)\n", style.Success.Render("✓"), name, host)
+	fmt.Printf("  %s\n", style.Dim.Render("Run 'gt machine test "+name+"' to verify the connection"))
+	return nil
+}
+
+func runMachineTest(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	m, err := reg.Get(name)
+	if err != nil {
+		return err
+	}
+	conn, err := reg.Connection(name)
+	if err != nil {
+		return err
+	}
+	if closer, ok := conn.(interface{ Close() error }); ok {
+		defer func() { _ = closer.Close() }()
+	}
+
+	fmt.Printf("Testing %s...\n", style.Bold.Render(name))
+
+	out, err := conn.Exec("uname", "-sn")
+	if err != nil {
+		fmt.Printf("  %s connect: %v\n", style.Error.Render("✗"), err)
+		return fmt.Errorf("machine %s is not reachable", name)
+	}
+	fmt.Printf("  %s connect: %s\n", style.Success.Render("✓"), strings.TrimSpace(string(out)))
+
+	if out, err := conn.Exec("tmux", "-V"); err != nil {
+		fmt.Printf("  %s tmux: not available\n", style.Warning.Render("⚠"))
+	} else {
+		fmt.Printf("  %s tmux: %s\n", style.Success.Render("✓"), strings.TrimSpace(string(out)))
+	}
+
+	if m.TownPath != "" {
+		exists, err := conn.Exists(m.TownPath)
+		switch {
+		case err != nil:
+			fmt.Printf("  %s town path: %v\n", style.Error.Render("✗"), err)
+			return fmt.Errorf("checking town path on %s: %w", name, err)
+		case !exists:
+			fmt.Printf("  %s town path: %s does not exist\n", style.Warning.Render("⚠"), m.TownPath)
+		default:
+			fmt.Printf("  %s town path: %s\n", style.Success.Render("✓"), m.TownPath)
+		}
+	}
+
+	return nil
+}
+
+func runMachineRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	reg, err := loadMachineRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Remove(name); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Removed machine %s\n", style.Success.Render("✓"), name)
+	return nil
+}
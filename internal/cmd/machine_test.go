@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/connection"
+)
+
+func TestMachineAddListRemove(t *testing.T) {
+	townRoot, _ := setupTestTownForAccount(t)
+	t.Chdir(townRoot)
+
+	machineKeyPath, machineTownPath = "/keys/gt", "/srv/gt"
+	t.Cleanup(func() { machineKeyPath, machineTownPath = "", "" })
+
+	if err := runMachineAdd(machineAddCmd, []string{"buildbox", "dev@build:2222"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := runMachineAdd(machineAddCmd, []string{"buildbox", "dev@other"}); err == nil {
+		t.Error("expected duplicate add to fail")
+	}
+	if err := runMachineAdd(machineAddCmd, []string{"bad:name", "dev@build"}); err == nil {
+		t.Error("expected invalid name to be rejected")
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "machines.json"))
+	if err != nil {
+		t.Fatalf("reading machines.json: %v", err)
+	}
+	for _, want := range []string{`"buildbox"`, `"dev@build:2222"`, `"/keys/gt"`, `"/srv/gt"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("machines.json missing %s:\n%s", want, data)
+		}
+	}
+
+	reg, err := connection.NewMachineRegistry(filepath.Join(townRoot, "mayor", "machines.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reg.List()) != 2 {
+		t.Errorf("expected local + buildbox, got %d machines", len(reg.List()))
+	}
+
+	if err := runMachineRemove(machineRemoveCmd, []string{"local"}); err == nil {
+		t.Error("expected removing local to fail")
+	}
+	if err := runMachineRemove(machineRemoveCmd, []string{"buildbox"}); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := runMachineTest(machineTestCmd, []string{"buildbox"}); err == nil {
+		t.Error("expected test of removed machine to fail")
+	}
+}
+
+func TestMachineTestLocal(t *testing.T) {
+	townRoot, _ := setupTestTownForAccount(t)
+	t.Chdir(townRoot)
+
+	if err := runMachineTest(machineTestCmd, []string{"local"}); err != nil {
+		t.Errorf("testing local machine: %v", err)
+	}
+}
@@ -31,6 +31,9 @@ var (
 	mailReplySubject  string
 	mailReplyMessage  string
 	mailStdin         bool // Read message body from stdin
+	mailAt            string
+	mailIn            string
+	mailExpires       string
 
 	// Search flags
 	mailSearchFrom    string
@@ -48,6 +51,10 @@ var (
 	// Archive flags
 	mailArchiveStale  bool
 	mailArchiveDryRun bool
+
+	// Scheduled flags
+	mailScheduledCancel string
+	mailScheduledJSON   bool
 )
 
 var mailCmd = &cobra.Command{
@@ -123,6 +130,12 @@ Priority levels:
 
 Use --urgent as shortcut for --priority 0.
 
+Scheduled delivery:
+  --at 09:00 or --in 2h holds the message until then; the daemon releases
+  it (see 'gt mail scheduled'). --expires 4h archives the message that long
+  after delivery if it is still unread, or drops it undelivered. --expires
+  also accepts an absolute time.
+
 Examples:
   gt mail send greenplace/Toast -s "Status check" -m "How's that bug fix going?"
   gt mail send mayor/ -s "Work complete" -m "Finished gt-abc"
@@ -133,6 +146,8 @@ Examples:
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
   gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send greenplace/witness -s "Standup" -m "Status?" --at 09:00
+  gt mail send queue:merges -s "Merge ready" -m "gt-abc" --in 2h --expires 4h
 
   # Read body from stdin (avoids shell quoting issues):
   gt mail send mayor/ -s "Update" --stdin <<'BODY'
@@ -142,6 +157,22 @@ Examples:
 	RunE: runMailSend,
 }
 
+var mailScheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "List or cancel scheduled messages",
+	Long: `List messages sent with --at or --in that have not been delivered yet.
+
+The daemon's mail patrol delivers them when due, resolving the address at
+that point. Use --cancel to drop one before it goes out.
+
+Examples:
+  gt mail scheduled
+  gt mail scheduled --json
+  gt mail scheduled --cancel msg-abc123`,
+	Args: cobra.NoArgs,
+	RunE: runMailScheduled,
+}
+
 var mailInboxCmd = &cobra.Command{
 	Use:   "inbox [address]",
 	Short: "Check inbox",
@@ -469,6 +500,10 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailAt, "at", "", "Deliver at a time (HH:MM, \"YYYY-MM-DD HH:MM\" or RFC3339)")
+	mailSendCmd.Flags().StringVar(&mailIn, "in", "", "Deliver after a delay (e.g. 30m, 2h)")
+	mailSendCmd.MarkFlagsMutuallyExclusive("at", "in")
+	mailSendCmd.Flags().StringVar(&mailExpires, "expires", "", "Archive if still unread this long after delivery (e.g. 4h), or at a time")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -512,6 +547,10 @@ func init() {
 	mailArchiveCmd.Flags().BoolVar(&mailArchiveStale, "stale", false, "Archive messages sent before session start")
 	mailArchiveCmd.Flags().BoolVarP(&mailArchiveDryRun, "dry-run", "n", false, "Show what would be archived without archiving")
 
+	// Scheduled flags
+	mailScheduledCmd.Flags().StringVar(&mailScheduledCancel, "cancel", "", "Cancel the scheduled message with this ID")
+	mailScheduledCmd.Flags().BoolVar(&mailScheduledJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	mailCmd.AddCommand(mailSendCmd)
 	mailCmd.AddCommand(mailInboxCmd)
@@ -529,6 +568,7 @@ func init() {
 	mailCmd.AddCommand(mailClearCmd)
 	mailCmd.AddCommand(mailSearchCmd)
 	mailCmd.AddCommand(mailAnnouncesCmd)
+	mailCmd.AddCommand(mailScheduledCmd)
 
 	rootCmd.AddCommand(mailCmd)
 }
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Rules command flags
+var (
+	rulesJSON bool
+	rulesTo   string
+)
+
+var mailRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Show and test mail filter rules",
+	Long: `Show and test the mail filter rules in settings/mail-rules.toml.
+
+Rules run as direct mail is delivered, in file order. Each rule matches on
+the recipient (to patterns or roles), sender, type, and subject/body regular
+expressions, and can archive, label, change priority or delivery, forward a
+copy, or summarize the message in a per-recipient digest. Rules never change
+a message's subject or body, so protocol mail stays machine-readable.
+
+A digest rule still delivers each message, but without a nudge: the
+recipient is told once, when a digest starts, and the open digest lists
+every message added since with its ID.
+
+Example settings/mail-rules.toml:
+
+  [[rule]]
+  name     = "mayor-escalations"
+  to       = ["mayor/"]
+  subject  = "ESCALATION"
+  priority = "urgent"
+  delivery = "interrupt"
+  stop     = true
+
+  [[rule]]
+  name    = "mayor-lifecycle"
+  to      = ["mayor/"]
+  subject = "^LIFECYCLE"
+  archive = true
+
+  [[rule]]
+  name   = "mayor-witness-reports"
+  to     = ["mayor/"]
+  from   = ["*/witness"]
+  digest = true
+
+Examples:
+  gt mail rules list
+  gt mail rules test hq-abc123
+  gt mail rules test hq-abc123 --to gastown/witness`,
+	RunE: requireSubcommand,
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List mail filter rules",
+	Args:  cobra.NoArgs,
+	RunE:  runRulesList,
+}
+
+var rulesTestCmd = &cobra.Command{
+	Use:   "test <msg-id>",
+	Short: "Show which rules match a message",
+	Long: `Evaluate the mail rules against an existing message and show which
+rules matched, why the others did not, and the combined result.
+
+Nothing is changed. Use --to to test delivery to a different recipient.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRulesTest,
+}
+
+func init() {
+	rulesListCmd.Flags().BoolVar(&rulesJSON, "json", false, "Output as JSON")
+	rulesTestCmd.Flags().BoolVar(&rulesJSON, "json", false, "Output as JSON")
+	rulesTestCmd.Flags().StringVar(&rulesTo, "to", "", "Evaluate as if delivered to this address")
+
+	mailRulesCmd.AddCommand(rulesListCmd)
+	mailRulesCmd.AddCommand(rulesTestCmd)
+
+	mailCmd.AddCommand(mailRulesCmd)
+}
+
+// loadMailRulesFromCwd loads the town's mail rules; no file means no rules.
+func loadMailRulesFromCwd() (*config.MailRulesConfig, string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	path := config.MailRulesPath(townRoot)
+	cfg, err := config.LoadMailRules(path)
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return &config.MailRulesConfig{}, path, nil
+		}
+		return nil, path, err
+	}
+	return cfg, path, nil
+}
+
+func runRulesList(cmd *cobra.Command, args []string) error {
+	cfg, path, err := loadMailRulesFromCwd()
+	if err != nil {
+		return err
+	}
+
+	if rulesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		rules := cfg.Rules
+		if rules == nil {
+			rules = []config.MailRule{}
+		}
+		return enc.Encode(rules)
+	}
+
+	if len(cfg.Rules) == 0 {
+		fmt.Printf("%s No mail rules (%s)\n", style.Dim.Render("○"), path)
+		return nil
+	}
+
+	fmt.Printf("%s Mail rules (%d) from %s\n\n", style.Bold.Render("📋"), len(cfg.Rules), path)
+	for i, rule := range cfg.Rules {
+		fmt.Printf("  %d. %s\n", i+1, style.Bold.Render(rule.Name))
+		fmt.Printf("     match: %s\n", describeRuleMatch(&rule))
+		fmt.Printf("     then:  %s\n", describeRuleActions(&rule))
+	}
+	return nil
+}
+
+func runRulesTest(cmd *cobra.Command, args []string) error {
+	cfg, _, err := loadMailRulesFromCwd()
+	if err != nil {
+		return err
+	}
+
+	mailbox, err := getMailbox(detectSender())
+	if err != nil {
+		return err
+	}
+	msg, err := mailbox.Get(args[0])
+	if err != nil {
+		return fmt.Errorf("getting message: %w", err)
+	}
+	if rulesTo != "" {
+		msg.To = rulesTo
+	}
+
+	result := mail.EvaluateRules(cfg, msg)
+
+	if rulesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render(msg.ID), msg.Subject)
+	fmt.Printf("  %s → %s\n\n", msg.From, msg.To)
+	if len(result.Trace) == 0 {
+		fmt.Printf("%s No mail rules configured\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, t := range result.Trace {
+		if t.Matched {
+			fmt.Printf("  %s %s\n", style.Bold.Render("✓"), t.Rule)
+			continue
+		}
+		reason := t.Reason
+		if !strings.HasPrefix(reason, "stopped") {
+			reason = reason + " did not match"
+		}
+		fmt.Printf("  %s %s\n", style.Dim.Render("✗"), style.Dim.Render(t.Rule+" ("+reason+")"))
+	}
+
+	if len(result.Matched) == 0 {
+		fmt.Printf("\nDelivered unchanged.\n")
+		return nil
+	}
+	fmt.Println()
+	if result.ArchivedBy != "" {
+		fmt.Printf("  Archived by: %s\n", result.ArchivedBy)
+	}
+	if result.Digest != "" && result.ArchivedBy == "" {
+		fmt.Printf("  Digest:      %s\n", result.Digest)
+	}
+	if result.Priority != msg.Priority {
+		fmt.Printf("  Priority:    %s → %s\n", msg.Priority, result.Priority)
+	}
+	if result.Delivery != msg.Delivery {
+		fmt.Printf("  Delivery:    %s\n", result.Delivery)
+	}
+	if len(result.Labels) > 0 {
+		fmt.Printf("  Labels:      %s\n", strings.Join(result.Labels, ", "))
+	}
+	if len(result.Forward) > 0 {
+		fmt.Printf("  Forward to:  %s\n", strings.Join(result.Forward, ", "))
+	}
+	return nil
+}
+
+// describeRuleMatch summarizes a rule's match criteria.
+func describeRuleMatch(r *config.MailRule) string {
+	var parts []string
+	if len(r.To) > 0 {
+		parts = append(parts, "to "+strings.Join(r.To, "|"))
+	}
+	if len(r.Roles) > 0 {
+		parts = append(parts, "role "+strings.Join(r.Roles, "|"))
+	}
+	if len(r.From) > 0 {
+		parts = append(parts, "from "+strings.Join(r.From, "|"))
+	}
+	if len(r.Types) > 0 {
+		parts = append(parts, "type "+strings.Join(r.Types, "|"))
+	}
+	if r.Subject != "" {
+		parts = append(parts, fmt.Sprintf("subject /%s/", r.Subject))
+	}
+	if r.Body != "" {
+		parts = append(parts, fmt.Sprintf("body /%s/", r.Body))
+	}
+	if len(parts) == 0 {
+		return "all direct mail"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeRuleActions summarizes what a rule does.
+func describeRuleActions(r *config.MailRule) string {
+	var parts []string
+	if r.Archive {
+		parts = append(parts, "archive")
+	}
+	if r.Digest {
+		parts = append(parts, "digest")
+	}
+	if len(r.Labels) > 0 {
+		parts = append(parts, "label "+strings.Join(r.Labels, ", "))
+	}
+	if r.Priority != "" {
+		parts = append(parts, "priority "+r.Priority)
+	}
+	if r.Delivery != "" {
+		parts = append(parts, "deliver by "+r.Delivery)
+	}
+	if len(r.Forward) > 0 {
+		parts = append(parts, "forward to "+strings.Join(r.Forward, ", "))
+	}
+	if r.Stop {
+		parts = append(parts, "stop")
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// mailTimeLayouts are the absolute forms accepted by --at and --expires,
+// besides a bare clock time (15:04).
+var mailTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"}
+
+// parseMailTime parses an absolute delivery or expiry time. A bare clock time
+// (09:00) means its next occurrence: today, or tomorrow if already past.
+func parseMailTime(s string, now time.Time) (time.Time, error) {
+	if clock, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	for _, layout := range mailTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use HH:MM, \"YYYY-MM-DD HH:MM\" or RFC3339)", s)
+}
+
+// parseMailSchedule turns --at, --in and --expires into delivery and expiry
+// times. A relative --expires counts from delivery, so "--in 1h --expires 4h"
+// expires five hours from now. Both results are nil when the flags are empty.
+func parseMailSchedule(at, in, expires string, now time.Time) (deliverAt, expiresAt *time.Time, err error) {
+	if at != "" && in != "" {
+		return nil, nil, fmt.Errorf("cannot use --at with --in")
+	}
+	if at != "" {
+		t, err := parseMailTime(at, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("--at: %w", err)
+		}
+		deliverAt = &t
+	}
+	if in != "" {
+		d, err := time.ParseDuration(in)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("--in: invalid duration %q (e.g. 30m, 2h)", in)
+		}
+		t := now.Add(d)
+		deliverAt = &t
+	}
+
+	if expires != "" {
+		base := now
+		if deliverAt != nil {
+			base = *deliverAt
+		}
+		var t time.Time
+		if d, derr := time.ParseDuration(expires); derr == nil {
+			if d <= 0 {
+				return nil, nil, fmt.Errorf("--expires: duration must be positive")
+			}
+			t = base.Add(d)
+		} else if t, err = parseMailTime(expires, now); err != nil {
+			return nil, nil, fmt.Errorf("--expires: %w", err)
+		}
+		if !t.After(base) {
+			return nil, nil, fmt.Errorf("--expires: message would expire before it is delivered")
+		}
+		expiresAt = &t
+	}
+	return deliverAt, expiresAt, nil
+}
+
+// runMailScheduled lists deferred messages, or cancels one with --cancel.
+func runMailScheduled(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(workDir)
+
+	if mailScheduledCancel != "" {
+		if err := router.CancelScheduled(mailScheduledCancel); err != nil {
+			if errors.Is(err, mail.ErrMessageNotFound) {
+				return fmt.Errorf("no scheduled message %s", mailScheduledCancel)
+			}
+			return fmt.Errorf("canceling scheduled message: %w", err)
+		}
+		fmt.Printf("%s Canceled scheduled message %s\n", style.Bold.Render("✓"), mailScheduledCancel)
+		return nil
+	}
+
+	msgs, err := router.ListScheduled()
+	if err != nil {
+		return err
+	}
+
+	if mailScheduledJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if msgs == nil {
+			msgs = []*mail.Message{}
+		}
+		return enc.Encode(msgs)
+	}
+
+	if len(msgs) == 0 {
+		fmt.Printf("%s No scheduled messages\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Scheduled messages (%d)\n\n", style.Bold.Render("⏰"), len(msgs))
+	for _, msg := range msgs {
+		fmt.Printf("  %s %s\n", style.Bold.Render(msg.ID), msg.Subject)
+		fmt.Printf("    %s → %s at %s\n", msg.From, msg.To, msg.DeliverAt.Local().Format("2006-01-02 15:04"))
+		if msg.ExpiresAt != nil {
+			fmt.Printf("    %s\n", style.Dim.Render("expires "+msg.ExpiresAt.Local().Format("2006-01-02 15:04")))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMailTime(t *testing.T) {
+	loc := time.FixedZone("test", 2*3600)
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, loc)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"11:00", time.Date(2026, 3, 1, 11, 0, 0, 0, loc)},
+		{"09:00", time.Date(2026, 3, 2, 9, 0, 0, 0, loc)},   // already past: tomorrow
+		{"10:30", time.Date(2026, 3, 2, 10, 30, 0, 0, loc)}, // now counts as past
+		{"2026-03-05 08:15", time.Date(2026, 3, 5, 8, 15, 0, 0, loc)},
+		{"2026-03-05T08:15:00Z", time.Date(2026, 3, 5, 8, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseMailTime(tt.in, now)
+		if err != nil {
+			t.Errorf("parseMailTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseMailTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "9am", "25:00", "tomorrow"} {
+		if _, err := parseMailTime(bad, now); err == nil {
+			t.Errorf("parseMailTime(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParseMailSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	deliverAt, expiresAt, err := parseMailSchedule("", "", "", now)
+	if err != nil || deliverAt != nil || expiresAt != nil {
+		t.Fatalf("no flags: %v %v %v", deliverAt, expiresAt, err)
+	}
+
+	// Relative expiry counts from delivery.
+	deliverAt, expiresAt, err = parseMailSchedule("", "2h", "4h", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deliverAt.Equal(now.Add(2*time.Hour)) || !expiresAt.Equal(now.Add(6*time.Hour)) {
+		t.Errorf("--in 2h --expires 4h = %v, %v", deliverAt, expiresAt)
+	}
+
+	// Immediate delivery with an expiry.
+	deliverAt, expiresAt, err = parseMailSchedule("", "", "30m", now)
+	if err != nil || deliverAt != nil || !expiresAt.Equal(now.Add(30*time.Minute)) {
+		t.Errorf("--expires 30m = %v, %v, %v", deliverAt, expiresAt, err)
+	}
+
+	// Absolute expiry.
+	_, expiresAt, err = parseMailSchedule("11:00", "", "2026-03-01 18:00", now)
+	if err != nil || !expiresAt.Equal(time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("absolute --expires = %v, %v", expiresAt, err)
+	}
+
+	for _, tc := range [][3]string{
+		{"11:00", "1h", ""},               // --at with --in
+		{"", "-1h", ""},                   // negative delay
+		{"", "soon", ""},                  // bad duration
+		{"", "", "0s"},                    // zero expiry
+		{"12:00", "", "2026-03-01 11:00"}, // expires before delivery
+	} {
+		if _, _, err := parseMailSchedule(tc[0], tc[1], tc[2], now); err == nil {
+			t.Errorf("parseMailSchedule(%q, %q, %q): expected error", tc[0], tc[1], tc[2])
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
@@ -28,6 +30,11 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		mailBody = strings.TrimRight(string(data), "\n")
 	}
 
+	deliverAt, expiresAt, err := parseMailSchedule(mailAt, mailIn, mailExpires, time.Now())
+	if err != nil {
+		return err
+	}
+
 	var to string
 
 	if mailSendSelf {
@@ -95,6 +102,10 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Deferred delivery and expiry (--at/--in/--expires)
+	msg.DeliverAt = deliverAt
+	msg.ExpiresAt = expiresAt
+
 	// Suppress router-side notification when --no-notify is passed.
 	// Otherwise the router handles idle-aware notification per-recipient,
 	// which also works correctly for fan-out (groups, lists, channels).
@@ -145,8 +156,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("sending message: %w", err)
 		}
 		_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
-		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
-		fmt.Printf("  Subject: %s\n", mailSubject)
+		printMailSent(msg, to)
 		return nil
 	}
 
@@ -199,8 +209,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Log mail event to activity feed
 	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
 
-	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
-	fmt.Printf("  Subject: %s\n", mailSubject)
+	printMailSent(msg, to)
 
 	// Show resolved recipients if fan-out occurred
 	if len(recipientAddrs) > 1 || (len(recipientAddrs) == 1 && recipientAddrs[0] != to) {
@@ -217,6 +226,19 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printMailSent reports a sent or scheduled message.
+func printMailSent(msg *mail.Message, to string) {
+	if msg.DeliverAt != nil {
+		fmt.Printf("%s Message scheduled for %s at %s\n", style.Bold.Render("✓"), to, msg.DeliverAt.Format("2006-01-02 15:04"))
+	} else {
+		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
+	}
+	fmt.Printf("  Subject: %s\n", mailSubject)
+	if msg.ExpiresAt != nil {
+		fmt.Printf("  Expires: %s\n", msg.ExpiresAt.Format("2006-01-02 15:04"))
+	}
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
@@ -222,6 +222,8 @@ When a polecat is working on a molecule, it processes one step at a time:
 2. When done: gt mol step done <step-id>
 3. System auto-continues to next ready step
 
+If a step fails: gt mol step fail <step-id> (retries if the formula allows)
+
 IMPORTANT: Always use 'gt mol step done' to complete steps. Do not manually
 close steps with 'bd close' - that skips the auto-continuation logic.`,
 }
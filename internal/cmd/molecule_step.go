@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -34,6 +35,10 @@ This command handles the step-to-step transition for polecats:
    - Sends POLECAT_DONE to witness
    - Exits the session
 
+Steps whose formula "when" condition is false for the molecule's vars are
+closed as skipped instead of being continued to. If a step fails, use
+'gt mol step fail' so formula retries and backoff apply.
+
 IMPORTANT: This is the canonical way to complete molecule steps. Do NOT manually
 close steps with 'bd close' - it skips the auto-continuation logic.
 
@@ -112,6 +117,17 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		MoleculeID: moleculeID,
 	}
 
+	// Step policies (when/retries/timeout) recorded when the formula was poured.
+	run, err := formula.LoadRun(townRoot, moleculeID)
+	if err != nil {
+		style.PrintWarning("could not load step policies: %v", err)
+	}
+	if run != nil && run.Failed != "" {
+		fmt.Printf("%s Molecule %s failed: %s\n", style.Error.Render("✗"), moleculeID, run.Failed)
+		fmt.Printf("Stop work and signal the blocker: gt done --status ESCALATED\n")
+		return NewSilentExit(1)
+	}
+
 	// Step 3: Close the step
 	if moleculeStepDryRun {
 		fmt.Printf("[dry-run] Would close step: %s\n", stepID)
@@ -129,6 +145,22 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("finding next steps: %w", err)
 	}
+	if run != nil {
+		if id, _ := run.StepFor(stepID, step.Title); id != "" {
+			run.Complete(id)
+		}
+		readySteps, allComplete, err = applyStepPolicies(b, run, moleculeID, readySteps, allComplete, moleculeStepDryRun)
+		if err != nil {
+			return fmt.Errorf("applying step policies: %w", err)
+		}
+		if !moleculeStepDryRun {
+			if allComplete {
+				_ = formula.RemoveRun(townRoot, moleculeID)
+			} else {
+				startSteps(townRoot, run, readySteps)
+			}
+		}
+	}
 
 	if allComplete {
 		result.Complete = true
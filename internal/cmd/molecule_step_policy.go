@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// moleculeStepFailCmd is the "gt mol step fail" command.
+var moleculeStepFailCmd = &cobra.Command{
+	Use:   "fail <step-id>",
+	Short: "Report a failed step and retry it if the formula allows",
+	Long: `Report that a molecule step failed.
+
+If the step's formula declares retries, the attempt is recorded, the step
+waits out its backoff (doubling per attempt), and the session respawns on
+the same step for a fresh attempt.
+
+When the step is out of retries (or declares none), the molecule fails.
+Stop work and signal the blocker with 'gt done --status ESCALATED'.
+
+Formula fields:
+  retries = 3          # Extra attempts after the first failure
+  backoff = "1m"       # Delay before the first retry (default: 30s)
+
+Examples:
+  gt mol step fail gt-abc.3 --reason "flaky integration test"
+  gt mol step fail gt-abc.3 --no-wait   # Retry without waiting out the backoff`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMoleculeStepFail,
+}
+
+var (
+	moleculeStepFailReason string
+	moleculeStepFailNoWait bool
+)
+
+func init() {
+	moleculeStepFailCmd.Flags().StringVar(&moleculeStepFailReason, "reason", "", "Why the step failed")
+	moleculeStepFailCmd.Flags().BoolVar(&moleculeStepFailNoWait, "no-wait", false, "Retry immediately instead of waiting out the backoff")
+	moleculeStepFailCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	moleculeStepFailCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
+	moleculeStepCmd.AddCommand(moleculeStepFailCmd)
+}
+
+// StepFailResult is the result of a step fail operation.
+type StepFailResult struct {
+	StepID     string `json:"step_id"`
+	MoleculeID string `json:"molecule_id"`
+	Attempt    int    `json:"attempt"`
+	Retries    int    `json:"retries"`
+	RetryIn    string `json:"retry_in,omitempty"`
+	Failed     string `json:"failed,omitempty"` // molecule failure reason
+}
+
+// sleepFn waits out retry backoff. Overridable in tests.
+var sleepFn = time.Sleep
+
+func runMoleculeStepFail(cmd *cobra.Command, args []string) error {
+	stepID := args[0]
+	reason := moleculeStepFailReason
+	if reason == "" {
+		reason = "no reason given"
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	workDir, err := findLocalBeadsDir()
+	if err != nil {
+		return fmt.Errorf("not in a beads workspace: %w", err)
+	}
+	b := beads.New(workDir)
+
+	step, err := b.Show(stepID)
+	if err != nil {
+		return fmt.Errorf("step not found: %w", err)
+	}
+	moleculeID := extractMoleculeIDFromStep(stepID)
+	if moleculeID == "" {
+		moleculeID = step.Parent
+	}
+	if moleculeID == "" {
+		return fmt.Errorf("cannot determine molecule for step %s", stepID)
+	}
+
+	run, err := formula.LoadRun(townRoot, moleculeID)
+	if err != nil {
+		return fmt.Errorf("loading step policies: %w", err)
+	}
+	if run == nil {
+		// No policies recorded: the step cannot be retried.
+		run = &formula.Run{MoleculeID: moleculeID, Steps: map[string]*formula.StepState{}}
+	}
+
+	formulaStep, state := run.StepFor(stepID, step.Title)
+	if formulaStep == "" {
+		formulaStep = stepID
+	}
+	now := time.Now()
+	delay, retry := run.Fail(formulaStep, reason, now)
+
+	result := StepFailResult{StepID: stepID, MoleculeID: moleculeID, Failed: run.Failed}
+	if state != nil {
+		result.Attempt, result.Retries = state.Attempts, state.Retries
+	} else {
+		result.Attempt = 1
+	}
+	if retry {
+		result.RetryIn = delay.String()
+	}
+
+	if !moleculeStepDryRun && len(run.Steps) > 0 {
+		if err := formula.SaveRun(townRoot, run); err != nil {
+			return fmt.Errorf("saving step policies: %w", err)
+		}
+	}
+
+	if moleculeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+		if !retry {
+			return NewSilentExit(1)
+		}
+		return nil
+	}
+
+	if !retry {
+		fmt.Printf("%s Molecule %s failed: %s\n", style.Error.Render("✗"), moleculeID, run.Failed)
+		fmt.Printf("Stop work and signal the blocker: gt done --status ESCALATED\n")
+		return NewSilentExit(1)
+	}
+
+	fmt.Printf("%s Step %s failed (attempt %d of %d): %s\n",
+		style.Warning.Render("⚠"), stepID, result.Attempt, result.Retries+1, reason)
+	if moleculeStepDryRun {
+		fmt.Printf("[dry-run] Would retry after %s\n", delay)
+		return nil
+	}
+	if !moleculeStepFailNoWait {
+		fmt.Printf("%s Retrying in %s...\n", style.Dim.Render("⏳"), delay)
+		sleepFn(delay)
+	}
+
+	run.Start(formulaStep, stepID, time.Now())
+	if err := formula.SaveRun(townRoot, run); err != nil {
+		style.PrintWarning("could not record retry start: %v", err)
+	}
+	return handleStepContinue(cwd, townRoot, step, false)
+}
+
+// recordFormulaRun records the step policies of a molecule poured from
+// formulaName, if its steps use when, retries or timeout. Best-effort:
+// failures only lose policy enforcement, so they are reported and ignored.
+func recordFormulaRun(townRoot, formulaName, moleculeID, beadID string, vars []string) {
+	f, err := loadFormulaByName(formulaName)
+	if err != nil || f.Type != formula.TypeWorkflow || !f.HasStepPolicies() {
+		return
+	}
+	run := formula.NewRun(f, moleculeID, parseVarList(vars), time.Now())
+	run.BeadID = beadID
+	if beadID != "" {
+		run.Rig = resolveRigForBead(townRoot, beadID)
+	}
+	if run.Rig == "" {
+		run.Rig = os.Getenv("GT_RIG")
+	}
+	if err := formula.SaveRun(townRoot, run); err != nil {
+		style.PrintWarning("could not record step policies for %s: %v", moleculeID, err)
+	}
+}
+
+// loadFormulaByName parses a formula from the search paths, falling back to
+// the embedded copy, and resolves its extends and includes.
+func loadFormulaByName(name string) (*formula.Formula, error) {
+	if path, err := findFormulaFile(name); err == nil {
+		return parseFormulaFile(path)
+	}
+	return newFormulaResolver().Resolve(name)
+}
+
+// parseVarList converts key=value formula vars to a map.
+func parseVarList(vars []string) map[string]string {
+	m := make(map[string]string, len(vars))
+	for _, v := range vars {
+		if key, value, ok := strings.Cut(v, "="); ok {
+			m[key] = value
+		}
+	}
+	return m
+}
+
+// applyStepPolicies closes ready steps whose when condition is false and
+// returns the steps that should actually run next. Skipping a step can
+// unblock others, so readiness is re-queried until no skippable step is ready.
+func applyStepPolicies(b *beads.Beads, run *formula.Run, moleculeID string, ready []*beads.Issue, allComplete, dryRun bool) ([]*beads.Issue, bool, error) {
+	for range len(run.Steps) + 1 {
+		var runnable []*beads.Issue
+		skippedAny := false
+		for _, issue := range ready {
+			id, state := run.StepFor(issue.ID, issue.Title)
+			if state == nil || !state.Skipped {
+				runnable = append(runnable, issue)
+				continue
+			}
+			skippedAny = true
+			state.BeadID = issue.ID
+			run.Complete(id)
+			if dryRun {
+				fmt.Printf("[dry-run] Would skip step %s (when condition is false)\n", issue.ID)
+				continue
+			}
+			if err := b.CloseWithReason("skipped: when condition is false", issue.ID); err != nil {
+				return nil, false, fmt.Errorf("skipping step %s: %w", issue.ID, err)
+			}
+			fmt.Printf("%s Skipped step %s: %s\n", style.Dim.Render("○"), issue.ID, issue.Title)
+		}
+		if !skippedAny || dryRun {
+			return runnable, allComplete && len(runnable) == 0, nil
+		}
+
+		var err error
+		ready, allComplete, err = findAllReadySteps(b, moleculeID)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return ready, allComplete, nil
+}
+
+// startSteps records that the given step beads are starting, for timeouts.
+func startSteps(townRoot string, run *formula.Run, steps []*beads.Issue) {
+	now := time.Now()
+	for _, s := range steps {
+		if id, _ := run.StepFor(s.ID, s.Title); id != "" {
+			run.Start(id, s.ID, now)
+		}
+	}
+	if err := formula.SaveRun(townRoot, run); err != nil {
+		style.PrintWarning("could not record step start: %v", err)
+	}
+}
@@ -239,10 +239,13 @@ var mqIntegrationLandCmd = &cobra.Command{
 Lands all work for an epic by merging its integration branch to main
 as a single atomic merge commit.
 
+The rig's merge_queue.merge_strategy (default for landing: merge-no-ff),
+commit_template and commit_trailers apply, as they do in the refinery.
+
 Actions:
   1. Verify all MRs targeting integration/<epic> are merged
   2. Verify integration branch exists
-  3. Merge integration/<epic> to main (--no-ff)
+  3. Merge integration/<epic> to main (merge_strategy, default --no-ff)
   4. Run tests on main
   5. Push to origin
   6. Delete integration branch
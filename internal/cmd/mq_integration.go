@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -283,6 +284,39 @@ func getIntegrationBranchTemplate(rigPath, cliOverride string) string {
 	return defaultIntegrationBranchTemplate
 }
 
+// getIntegrationLandStrategy returns the merge strategy for landing an
+// integration branch: the rig's merge_queue.merge_strategy, or merge-no-ff
+// (which keeps the epic's history as one merge) when unset.
+func getIntegrationLandStrategy(rigPath string) string {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.MergeQueue == nil || settings.MergeQueue.MergeStrategy == "" {
+		return config.MergeStrategyMergeNoFF
+	}
+	return settings.MergeQueue.MergeStrategy
+}
+
+// buildIntegrationLandMessage builds the commit message for landing an
+// integration branch, applying the rig's merge_queue.commit_template and
+// commit_trailers. The epic is the template's bead.
+func buildIntegrationLandMessage(rigPath, epicID, epicTitle, branchName, targetBranch string) (string, error) {
+	data := refinery.CommitMessageData{
+		BeadID:  epicID,
+		Title:   epicTitle,
+		Branch:  branchName,
+		Target:  targetBranch,
+		Message: fmt.Sprintf("Merge %s: %s\n\nEpic: %s", branchName, epicTitle, epicID),
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.MergeQueue == nil {
+		return data.Message, nil
+	}
+	msg, err := refinery.RenderCommitMessage(settings.MergeQueue.CommitTemplate, settings.MergeQueue.CommitTrailers, data)
+	if err != nil {
+		return "", fmt.Errorf("building commit message: %w", err)
+	}
+	return msg, nil
+}
+
 // IntegrationStatusOutput is the JSON output structure for integration status.
 type IntegrationStatusOutput struct {
 	Epic            string                       `json:"epic"`
@@ -587,7 +621,7 @@ func runMqIntegrationLand(cmd *cobra.Command, args []string) error {
 	// Dry run stops here
 	if mqIntegrationLandDryRun {
 		fmt.Printf("\n%s Dry run complete. Would perform:\n", style.Bold.Render("🔍"))
-		fmt.Printf("  1. Merge %s to %s (--no-ff)\n", branchName, targetBranch)
+		fmt.Printf("  1. Merge %s to %s (%s)\n", branchName, targetBranch, getIntegrationLandStrategy(r.Path))
 		if !mqIntegrationLandSkipTests {
 			fmt.Printf("  2. Run tests on %s\n", targetBranch)
 		}
@@ -627,11 +661,21 @@ func runMqIntegrationLand(cmd *cobra.Command, args []string) error {
 	}
 
 	// 4. Merge integration branch into target
-	fmt.Printf("Merging %s to %s...\n", branchName, targetBranch)
-	mergeMsg := fmt.Sprintf("Merge %s: %s\n\nEpic: %s", branchName, epic.Title, epicID)
-	if err := landGit.MergeNoFF("origin/"+branchName, mergeMsg); err != nil {
-		// Abort merge on failure (cleanup handles worktree removal)
-		_ = landGit.AbortMerge()
+	strategy := getIntegrationLandStrategy(r.Path)
+	fmt.Printf("Merging %s to %s (%s)...\n", branchName, targetBranch, strategy)
+	preMerge, err := landGit.Rev("HEAD")
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", targetBranch, err)
+	}
+	mergeMsg, err := buildIntegrationLandMessage(r.Path, epicID, epic.Title, branchName, targetBranch)
+	if err != nil {
+		return err
+	}
+	// On failure the merge is already undone (cleanup handles worktree removal)
+	if conflicts, err := refinery.MergeWithStrategy(landGit, strategy, "origin/"+branchName, mergeMsg); err != nil {
+		if len(conflicts) > 0 {
+			return fmt.Errorf("merge failed: conflicts in %s", strings.Join(conflicts, ", "))
+		}
 		return fmt.Errorf("merge failed: %w", err)
 	}
 	fmt.Printf("  %s Merged successfully\n", style.Bold.Render("✓"))
@@ -657,7 +701,7 @@ func runMqIntegrationLand(cmd *cobra.Command, args []string) error {
 	// Verify the merge actually brought changes (guard against empty merges).
 	// An empty merge means conflict resolution discarded all integration branch work,
 	// which would silently lose data if we proceed to delete the branch.
-	verifyCmd := exec.Command("git", "diff", "--stat", "HEAD~1..HEAD")
+	verifyCmd := exec.Command("git", "diff", "--stat", preMerge+"..HEAD")
 	verifyCmd.Dir = landGit.WorkDir()
 	diffOutput, verifyErr := verifyCmd.Output()
 	if verifyErr == nil && len(strings.TrimSpace(string(diffOutput))) == 0 {
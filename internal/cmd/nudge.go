@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/telemetry"
@@ -121,6 +123,30 @@ const ifFreshMaxAge = 60 * time.Second
 // This is a var (not const) so tests can override it to avoid 15s waits.
 var waitIdleTimeout = 15 * time.Second
 
+// nudgeSessionExists reports whether a nudge target is running. Inside the
+// sandbox tmux can't be asked, so targets are assumed to be running: a queued
+// nudge for a stopped session is simply never drained.
+func nudgeSessionExists(t *tmux.Tmux, sessionName string) (bool, error) {
+	if sandbox.Active() {
+		return true, nil
+	}
+	return t.HasSession(sessionName)
+}
+
+// isCrewTarget reports whether a short rig/name address means a crew member
+// rather than a polecat. Inside the sandbox this goes by the crew workspace.
+func isCrewTarget(t *tmux.Tmux, townRoot, rigName, name, crewSession string) bool {
+	if sandbox.Active() {
+		if townRoot == "" {
+			return false
+		}
+		info, err := os.Stat(filepath.Join(townRoot, rigName, "crew", name))
+		return err == nil && info.IsDir()
+	}
+	exists, _ := t.HasSession(crewSession)
+	return exists
+}
+
 // deliverNudge routes a nudge based on the --mode flag.
 // For "immediate" mode: sends directly via tmux (current behavior).
 // For "queue" mode: writes to the nudge queue for cooperative delivery.
@@ -133,7 +159,13 @@ func deliverNudge(t *tmux.Tmux, sessionName, message, sender string) error {
 	// FormatForInjection adds the prefix, so we must NOT double-prefix.
 	prefixedMessage := fmt.Sprintf("[from %s] %s", sender, message)
 
-	switch nudgeModeFlag {
+	mode := nudgeModeFlag
+	if sandbox.Active() {
+		// The tmux server is out of reach; the recipient drains the queue.
+		mode = NudgeModeQueue
+	}
+
+	switch mode {
 	case NudgeModeQueue:
 		if townRoot == "" {
 			return fmt.Errorf("--mode=queue requires a Gas Town workspace")
@@ -315,7 +347,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 	if target == "deacon" {
 		deaconSession := session.DeaconSessionName()
 		// Check if Deacon session exists
-		exists, err := t.HasSession(deaconSession)
+		exists, err := nudgeSessionExists(t, deaconSession)
 		if err != nil {
 			return fmt.Errorf("checking deacon session: %w", err)
 		}
@@ -368,7 +400,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 			// Try crew first (matches mail system's addressToSessionIDs pattern),
 			// then fall back to polecat.
 			crewSession := crewSessionName(rigName, polecatName)
-			if exists, _ := t.HasSession(crewSession); exists {
+			if isCrewTarget(t, townRoot, rigName, polecatName, crewSession) {
 				sessionName = crewSession
 			} else {
 				mgr, _, err := getSessionManager(rigName)
@@ -383,7 +415,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		// Without this, queue mode silently succeeds for nonexistent sessions —
 		// the file is written but never drained.
 		if nudgeModeFlag != NudgeModeImmediate {
-			exists, err := t.HasSession(sessionName)
+			exists, err := nudgeSessionExists(t, sessionName)
 			if err != nil {
 				return fmt.Errorf("checking session: %w", err)
 			}
@@ -406,7 +438,7 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		_ = events.LogFeed(events.TypeNudge, sender, events.NudgePayload(rigName, target, message))
 	} else {
 		// Raw session name (legacy)
-		exists, err := t.HasSession(target)
+		exists, err := nudgeSessionExists(t, target)
 		if err != nil {
 			return fmt.Errorf("checking session: %w", err)
 		}
@@ -32,6 +32,10 @@ type SpawnedPolecatInfo struct {
 	// Internal fields for deferred session start
 	account string
 	agent   string
+
+	// Work the session is charged to, tagged as GT_ISSUE/GT_CONVOY for gt costs.
+	costWorkItem string
+	costConvoy   string
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -345,6 +349,15 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 		return "", fmt.Errorf("starting session: %w", err)
 	}
 
+	// Tag the session with its work so gt costs record can attribute spend
+	// to the bead and convoy (per-convoy budgets).
+	if s.costWorkItem != "" {
+		_ = t.SetEnvironment(s.SessionName, "GT_ISSUE", s.costWorkItem)
+	}
+	if s.costConvoy != "" {
+		_ = t.SetEnvironment(s.SessionName, "GT_CONVOY", s.costConvoy)
+	}
+
 	// Wait for runtime to be fully ready before returning.
 	// When an agent override is specified (e.g., --agent codex), resolve the runtime
 	// config from the override so WaitForRuntimeReady uses the correct readiness
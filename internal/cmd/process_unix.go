@@ -2,7 +2,13 @@
 
 package cmd
 
-import "syscall"
+import (
+	"errors"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/sandbox"
+)
 
 // isProcessRunning checks if a process with the given PID exists.
 func isProcessRunning(pid int) bool {
@@ -18,3 +24,22 @@ func isProcessRunning(pid int) bool {
 	// EPERM means process exists but we don't have permission to signal it.
 	return err == syscall.EPERM
 }
+
+// killSandboxProcesses ends every process in the sandbox's PID namespace
+// except its init and the caller. The sandboxed command exits, and with it
+// the tmux pane, without gt having to reach the host's tmux server.
+// It refuses unless this process is in the sandbox's own PID namespace,
+// where kill(-1) cannot reach anything outside it.
+func killSandboxProcesses() error {
+	if !sandbox.OwnsPIDNamespace() {
+		return errors.New("not in a sandbox PID namespace, refusing to signal all processes")
+	}
+	if err := syscall.Kill(-1, syscall.SIGTERM); err != nil && err != syscall.ESRCH {
+		return err
+	}
+	time.Sleep(2 * time.Second)
+	if err := syscall.Kill(-1, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,7 @@
 package cmd
 
 import (
+	"errors"
 	"math"
 
 	"golang.org/x/sys/windows"
@@ -31,3 +32,8 @@ func isProcessRunning(pid int) bool {
 
 	return exitCode == processStillActive
 }
+
+// killSandboxProcesses is not supported: sandboxes are Linux-only.
+func killSandboxProcesses() error {
+	return errors.New("sandbox not supported on windows")
+}
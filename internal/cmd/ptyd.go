@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/ptyd"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	ptydSocket       string
+	ptydCaptureLines int
+)
+
+var ptydCmd = &cobra.Command{
+	Use:     "ptyd",
+	GroupID: GroupServices,
+	Short:   "Manage the headless session supervisor (tmux-free mode)",
+	RunE:    requireSubcommand,
+	Long: `Manage the headless pty session supervisor.
+
+Where tmux isn't available (CI containers, systemd units), Gas Town can run
+its agents under a small supervisor process that holds a pseudo-terminal and
+scrollback for each session. Select it with:
+
+  export GT_SESSION_BACKEND=pty
+
+When GT_SESSION_BACKEND is unset, the supervisor is used automatically if
+tmux is not installed. It starts on demand with the first session; run
+'gt ptyd serve' yourself to manage it with systemd.
+
+The socket defaults to $XDG_RUNTIME_DIR/gastown-ptyd.sock
+(override with GT_PTYD_SOCKET).`,
+}
+
+var ptydServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the supervisor in the foreground",
+	Long: `Run the pty session supervisor in the foreground.
+
+Sessions are killed when the supervisor exits (SIGINT/SIGTERM).
+
+Example systemd unit:
+  [Service]
+  Environment=GT_SESSION_BACKEND=pty
+  ExecStart=/usr/local/bin/gt ptyd serve`,
+	Args: cobra.NoArgs,
+	RunE: runPtydServe,
+}
+
+var ptydListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List supervised sessions",
+	Args:  cobra.NoArgs,
+	RunE:  runPtydList,
+}
+
+var ptydCaptureCmd = &cobra.Command{
+	Use:   "capture <session>",
+	Short: "Print a session's recent output",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPtydCapture,
+}
+
+var ptydKillCmd = &cobra.Command{
+	Use:   "kill <session>",
+	Short: "Kill a session and its processes",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPtydKill,
+}
+
+func init() {
+	ptydCmd.PersistentFlags().StringVar(&ptydSocket, "socket", "", "Supervisor socket path (default: $GT_PTYD_SOCKET or per-user runtime dir)")
+	ptydCaptureCmd.Flags().IntVarP(&ptydCaptureLines, "lines", "n", 50, "Number of lines to print (0 = all scrollback)")
+
+	ptydCmd.AddCommand(ptydServeCmd)
+	ptydCmd.AddCommand(ptydListCmd)
+	ptydCmd.AddCommand(ptydCaptureCmd)
+	ptydCmd.AddCommand(ptydKillCmd)
+	rootCmd.AddCommand(ptydCmd)
+}
+
+func ptydClient() *ptyd.Client {
+	if ptydSocket != "" {
+		return ptyd.NewClient(ptydSocket)
+	}
+	return ptyd.NewClient(ptyd.SocketPath())
+}
+
+func runPtydServe(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	socket := ptydClient().Socket()
+	fmt.Fprintf(os.Stderr, "%s ptyd listening on %s\n", style.Bold.Render("●"), socket)
+	return ptyd.NewServer(socket).Serve(ctx)
+}
+
+func runPtydList(cmd *cobra.Command, args []string) error {
+	c := ptydClient()
+	if !c.IsRunning() {
+		fmt.Printf("%s ptyd is not running (backend: %s)\n", style.Dim.Render("○"), session.BackendName())
+		return nil
+	}
+	names, err := c.ListSessions()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println(style.Dim.Render("No sessions"))
+		return nil
+	}
+	for _, name := range names {
+		pid, _ := c.GetPanePID(name)
+		fmt.Printf("%s %s %s\n", style.Bold.Render("●"), name, style.Dim.Render("pid "+pid))
+	}
+	return nil
+}
+
+func runPtydCapture(cmd *cobra.Command, args []string) error {
+	out, err := ptydClient().CapturePane(args[0], ptydCaptureLines)
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
+
+func runPtydKill(cmd *cobra.Command, args []string) error {
+	if err := ptydClient().KillSessionWithProcesses(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Killed %s\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
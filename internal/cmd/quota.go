@@ -12,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	ttmux "github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -220,9 +221,8 @@ func runQuotaScan(cmd *cobra.Command, args []string) error {
 	acctCfg, loadErr := config.LoadAccountsConfig(accountsPath)
 	// acctCfg can be nil if no accounts configured — scan still works
 
-	// Create scanner
-	t := ttmux.NewTmux()
-	scanner, err := quota.NewScanner(t, nil, acctCfg)
+	// Create scanner over whichever session backend the town runs on
+	scanner, err := quota.NewScanner(session.NewBackend(), nil, acctCfg)
 	if err != nil {
 		return fmt.Errorf("creating scanner: %w", err)
 	}
@@ -316,6 +316,7 @@ func printScanText(results []quota.ScanResult) error {
 // Rotate command flags
 var (
 	rotateDryRun bool
+	rotateAuto   bool
 )
 
 var quotaRotateCmd = &cobra.Command{
@@ -332,9 +333,24 @@ The rotation process:
   3. Updates tmux session environment with new CLAUDE_CONFIG_DIR
   4. Restarts blocked sessions via respawn-pane
 
+With --auto (run by the daemon's opt-in "quota" patrol on every heartbeat),
+the pass is unattended:
+  - Accounts whose reset time has passed are marked available again. When
+    the reset time can't be parsed, quota.reset_cooldown applies (default 5h).
+  - Limits are recorded in quota state, so 'gt quota status' shows them.
+  - When every account is limited, sessions fail over to the fallback agent
+    preset in quota.fallback, keep their hooked work, and the mayor gets mail.
+
+Town settings (settings/config.json):
+  "quota": {"fallback": {"claude": "gemini"}, "reset_cooldown": "5h"}
+
+Enable the patrol in mayor/daemon.json:
+  "patrols": {"quota": {"enabled": true}}
+
 Examples:
   gt quota rotate              # Rotate all blocked sessions
   gt quota rotate --dry-run    # Show plan without executing
+  gt quota rotate --auto       # Unattended pass with reset and failover
   gt quota rotate --json       # JSON output`,
 	RunE: runQuotaRotate,
 }
@@ -345,6 +361,13 @@ func runQuotaRotate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("finding town root: %w", err)
 	}
 
+	if rotateAuto {
+		if rotateDryRun {
+			return fmt.Errorf("--auto and --dry-run cannot be combined")
+		}
+		return runQuotaAutoRotate(townRoot)
+	}
+
 	// Load accounts config (required for rotation)
 	accountsPath := constants.MayorAccountsPath(townRoot)
 	acctCfg, err := config.LoadAccountsConfig(accountsPath)
@@ -514,6 +537,7 @@ func init() {
 	quotaScanCmd.Flags().BoolVar(&scanUpdate, "update", false, "Update quota state with detected limits")
 
 	quotaRotateCmd.Flags().BoolVar(&rotateDryRun, "dry-run", false, "Show plan without executing")
+	quotaRotateCmd.Flags().BoolVar(&rotateAuto, "auto", false, "Unattended pass: release reset accounts, rotate, and fail over")
 	quotaRotateCmd.Flags().BoolVar(&quotaJSON, "json", false, "Output as JSON")
 
 	quotaCmd.AddCommand(quotaStatusCmd)
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/quota"
+	"github.com/steveyegge/gastown/internal/style"
+	ttmux "github.com/steveyegge/gastown/internal/tmux"
+)
+
+// quotaAutoResult is the JSON output of gt quota rotate --auto.
+type quotaAutoResult struct {
+	Released   []string               `json:"released,omitempty"`
+	Rotated    []quota.RotateResult   `json:"rotated,omitempty"`
+	FailedOver []quota.FailoverResult `json:"failed_over,omitempty"`
+	Waiting    []string               `json:"waiting,omitempty"`
+}
+
+// runQuotaAutoRotate is one unattended rotation pass, run by the daemon's
+// quota patrol on every heartbeat:
+//
+//  1. Accounts past their reset time are marked available again.
+//  2. Limited sessions rotate to available accounts. Sessions still showing
+//     a limit that has since reset restart on their own account.
+//  3. Limits are recorded so step 1 can release them later.
+//  4. Sessions with no account left fail over to the quota.fallback agent
+//     for their preset; the mayor gets mail.
+func runQuotaAutoRotate(townRoot string) error {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	defaultAgent := settings.DefaultAgent
+	if defaultAgent == "" {
+		defaultAgent = string(config.DefaultAgentPreset())
+	}
+
+	// Rotation needs accounts, but failover works without any.
+	acctCfg, err := config.LoadAccountsConfig(constants.MayorAccountsPath(townRoot))
+	if err != nil || acctCfg.Accounts == nil {
+		acctCfg = &config.AccountsConfig{Accounts: map[string]config.Account{}}
+	}
+
+	mgr := quota.NewManager(townRoot)
+	now := time.Now()
+	var out quotaAutoResult
+
+	out.Released, err = mgr.ReleaseExpired(now, settings.Quota.GetResetCooldown())
+	if err != nil {
+		return fmt.Errorf("releasing expired limits: %w", err)
+	}
+
+	t := ttmux.NewTmux()
+	scanner, err := quota.NewScanner(t, nil, acctCfg)
+	if err != nil {
+		return fmt.Errorf("creating scanner: %w", err)
+	}
+	plan, err := quota.PlanRotation(scanner, mgr, acctCfg)
+	if err != nil {
+		return fmt.Errorf("planning rotation: %w", err)
+	}
+
+	var limits []quota.ScanResult
+	for _, r := range plan.LimitedSessions {
+		if r.AccountHandle != "" && slices.Contains(out.Released, r.AccountHandle) {
+			// The pane still shows a limit that has reset since.
+			if _, ok := plan.Assignments[r.Session]; !ok {
+				plan.Assignments[r.Session] = r.AccountHandle
+			}
+			continue
+		}
+		limits = append(limits, r)
+	}
+	if err := mgr.RecordLimits(limits, now); err != nil {
+		return fmt.Errorf("recording limits: %w", err)
+	}
+
+	failovers := quota.PlanFailover(plan, t, defaultAgent, settings.Quota.FallbackFor)
+	failing := make(map[string]bool, len(failovers))
+	for _, f := range failovers {
+		failing[f.Session] = true
+	}
+	for _, r := range plan.LimitedSessions {
+		if _, ok := plan.Assignments[r.Session]; !ok && !failing[r.Session] {
+			out.Waiting = append(out.Waiting, r.Session)
+		}
+	}
+	slices.Sort(out.Waiting)
+
+	rotator := quota.NewRotator(t, t, mgr, acctCfg, buildRestartCommand, quotaLogger{},
+		townRoot, "" /* agentName: default "claude" */, symlinkSessionToConfigDir)
+	if len(plan.Assignments) > 0 {
+		out.Rotated = rotator.Execute(plan, slices.Sorted(maps.Keys(plan.Assignments)))
+	}
+	if len(failovers) > 0 {
+		out.FailedOver = rotator.Failover(failovers, buildRestartCommandWithAgent)
+	}
+
+	reportQuotaAuto(townRoot, out)
+
+	if quotaJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	printQuotaAuto(out)
+	return nil
+}
+
+// reportQuotaAuto logs feed events for the pass and mails the mayor about
+// failovers, which change which provider a session runs on.
+func reportQuotaAuto(townRoot string, out quotaAutoResult) {
+	actor := detectActor()
+	if isDaemonDispatch() {
+		actor = "daemon"
+	}
+
+	for _, r := range out.Rotated {
+		if r.Rotated {
+			_ = events.LogFeed(events.TypeQuotaRotate, actor,
+				events.QuotaRotatePayload(r.Session, r.OldAccount, r.NewAccount))
+		}
+	}
+
+	var lines []string
+	for _, f := range out.FailedOver {
+		if !f.FailedOver {
+			continue
+		}
+		_ = events.LogFeed(events.TypeQuotaFailover, actor,
+			events.QuotaFailoverPayload(f.Session, f.FromAgent, f.ToAgent))
+		lines = append(lines, fmt.Sprintf("- %s: %s → %s", f.Session, f.FromAgent, f.ToAgent))
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	body := fmt.Sprintf(`Every account was rate-limited, so these sessions failed over to their fallback agent:
+
+%s
+
+They keep their hooked work and stay on the fallback agent (GT_AGENT) across
+handoffs until they are started fresh. New sessions use their usual agent.
+Run 'gt quota status' to see when accounts reset.`, strings.Join(lines, "\n"))
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	defer router.WaitPendingNotifications()
+	msg := &mail.Message{
+		From:      actor,
+		To:        "mayor/",
+		Subject:   fmt.Sprintf("Quota failover: %d session(s) moved to fallback agent", len(lines)),
+		Body:      body,
+		Priority:  mail.PriorityHigh,
+		Type:      mail.TypeNotification,
+		Timestamp: time.Now(),
+	}
+	if err := router.Send(msg); err != nil {
+		style.PrintWarning("could not mail mayor about quota failover: %v", err)
+	}
+}
+
+func printQuotaAuto(out quotaAutoResult) {
+	for _, handle := range out.Released {
+		fmt.Printf(" %s %s → available (limit reset)\n", style.SuccessPrefix, handle)
+	}
+	for _, r := range out.Rotated {
+		switch {
+		case r.Session == "" && r.Error != "":
+			fmt.Printf(" %s %s\n", style.ErrorPrefix, r.Error)
+		case r.Rotated:
+			fmt.Printf(" %s %s → %s\n", style.SuccessPrefix, r.Session, r.NewAccount)
+		case r.Error != "":
+			fmt.Printf(" %s %s: %s\n", style.ErrorPrefix, r.Session, r.Error)
+		}
+	}
+	for _, f := range out.FailedOver {
+		if f.FailedOver {
+			fmt.Printf(" %s %s → %s (failover from %s)\n", style.SuccessPrefix, f.Session, f.ToAgent, f.FromAgent)
+		} else {
+			fmt.Printf(" %s %s: failover to %s: %s\n", style.ErrorPrefix, f.Session, f.ToAgent, f.Error)
+		}
+	}
+	if len(out.Waiting) > 0 {
+		fmt.Printf(" %s %d session(s) rate-limited with no account or fallback: %s\n",
+			style.WarningPrefix, len(out.Waiting), strings.Join(out.Waiting, ", "))
+	}
+}
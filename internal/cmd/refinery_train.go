@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	refineryTrainDryRun bool
+	refineryTrainJSON   bool
+	refineryTrainSize   int
+)
+
+var refineryTrainCmd = &cobra.Command{
+	Use:   "train [rig]",
+	Short: "Merge the top ready MRs as one merge train",
+	Long: `Land several ready MRs with a single quality-gate run.
+
+The top N ready MRs (in score order, sharing the same target branch) are
+squash-merged on top of each other and the configured gates run once on the
+combined result. If the gates fail, the train is bisected to find the
+offending MR(s) while the rest still land.
+
+N defaults to merge_queue.max_concurrent from the rig config. With
+max_concurrent of 1 a train is a single ordinary merge. The ff-only
+merge strategy cannot stack branches, so its MRs land one at a time.
+
+MRs that conflict with the train or fail gates are handled exactly like a
+failed single merge (witness notified, conflict task created) and released
+back to the queue.
+
+Examples:
+  gt refinery train
+  gt refinery train gastown --dry-run
+  gt refinery train --size 4 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryTrain,
+}
+
+func init() {
+	refineryTrainCmd.Flags().BoolVar(&refineryTrainDryRun, "dry-run", false, "Show which MRs would ride the train without merging")
+	refineryTrainCmd.Flags().BoolVar(&refineryTrainJSON, "json", false, "Output as JSON")
+	refineryTrainCmd.Flags().IntVar(&refineryTrainSize, "size", 0, "Override train size (default: merge_queue.max_concurrent)")
+
+	refineryCmd.AddCommand(refineryTrainCmd)
+}
+
+func runRefineryTrain(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if refineryTrainJSON {
+		eng.SetOutput(os.Stderr)
+	}
+
+	size := eng.TrainSize()
+	if refineryTrainSize > 0 {
+		size = refineryTrainSize
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+	mrs := refinery.SelectTrain(ready, size, time.Now())
+
+	if refineryTrainDryRun {
+		if refineryTrainJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(mrs)
+		}
+		printTrain(rigName, mrs, size)
+		return nil
+	}
+
+	if len(mrs) == 0 {
+		if refineryTrainJSON {
+			fmt.Println("[]")
+			return nil
+		}
+		fmt.Printf("  %s\n", style.Dim.Render("(no ready MRs)"))
+		return nil
+	}
+
+	// Claim every car so parallel workers don't pick them up mid-train.
+	workerID := getWorkerID()
+	var claimed []*refinery.MRInfo
+	for _, mr := range mrs {
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			style.PrintWarning("could not claim %s, leaving it off the train: %v", mr.ID, err)
+			continue
+		}
+		claimed = append(claimed, mr)
+	}
+
+	cars := eng.ProcessTrain(context.Background(), claimed)
+	for _, car := range cars {
+		if car.Landed() {
+			eng.HandleMRInfoSuccess(car.MR, car.Result)
+			continue
+		}
+		eng.HandleMRInfoFailure(car.MR, car.Result)
+		if err := eng.ReleaseMR(car.MR.ID); err != nil {
+			style.PrintWarning("could not release %s: %v", car.MR.ID, err)
+		}
+	}
+
+	if refineryTrainJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cars)
+	}
+
+	landed := 0
+	fmt.Printf("\n%s Merge train for '%s':\n\n", style.Bold.Render("🚂"), rigName)
+	for i, car := range cars {
+		if car.Landed() {
+			landed++
+			fmt.Printf("  %d. %s %s %s\n", i+1, style.Bold.Render("✓"), car.MR.ID, style.Dim.Render(car.Result.MergeCommit))
+			continue
+		}
+		fmt.Printf("  %d. %s %s %s\n", i+1, style.Bold.Render("✗"), car.MR.ID, car.Result.Error)
+	}
+	fmt.Printf("\n  Landed %d/%d\n", landed, len(cars))
+	return nil
+}
+
+// printTrain shows the MRs that would ride the next train.
+func printTrain(rigName string, mrs []*refinery.MRInfo, size int) {
+	fmt.Printf("%s Next merge train for '%s' (size %d):\n\n", style.Bold.Render("🚂"), rigName, size)
+	if len(mrs) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no ready MRs)"))
+		return
+	}
+	for i, mr := range mrs {
+		fmt.Printf("  %d. [P%d] %s → %s\n", i+1, mr.Priority, mr.Branch, mr.Target)
+		fmt.Printf("     ID: %s  Worker: %s  Score: %.1f\n", mr.ID, mr.Worker, mr.Score())
+	}
+}
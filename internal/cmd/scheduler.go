@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -38,9 +41,33 @@ Subcommands:
   gt scheduler resume    # Resume dispatch
   gt scheduler clear     # Remove beads from scheduler
 
+Ready beads are dispatched in priority order (bead priority, then convoy
+age, then enqueue time), with free slots shared fairly between rigs so one
+large epic cannot starve other rigs.
+
 Config:
-  gt config set scheduler.max_polecats 5    # Enable deferred dispatch
-  gt config set scheduler.max_polecats -1   # Direct dispatch (default)`,
+  gt config set scheduler.max_polecats 5              # Enable deferred dispatch
+  gt config set scheduler.max_polecats -1             # Direct dispatch (default)
+  gt config set scheduler.max_polecats_per_rig 3      # Cap any single rig
+  gt config set scheduler.rig_max_polecats.gastown 5  # Per-rig cap override
+  gt config set scheduler.rig_weight.gastown 2        # Double gastown's share
+
+Schedules (scheduler section of settings/config.json, deferred mode only):
+  "timezone": "America/Los_Angeles",
+  "windows": [
+    {"name": "business-hours", "days": ["weekdays"], "start": "09:00",
+     "end": "18:00", "max_polecats": 4},
+    {"name": "overnight", "start": "22:00", "end": "06:00", "max_polecats": 20}
+  ],
+  "blackouts": [
+    {"name": "release-freeze", "from": "2026-12-18", "until": "2027-01-04"},
+    {"name": "weekly-deploy", "days": ["thu"], "start": "16:00", "end": "18:00"}
+  ]
+
+The first matching window overrides max_polecats/batch_size; outside all
+windows the base values apply. During a blackout dispatch pauses as if
+'gt scheduler pause' had been run, and resumes on its own when it ends.
+Windows ending before they start wrap past midnight.`,
 	RunE: requireSubcommand,
 }
 
@@ -126,6 +153,55 @@ type scheduledBeadInfo struct {
 	Blocked   bool   `json:"blocked,omitempty"`
 }
 
+// rigSchedulerStatus is one rig's share of the scheduler for status display.
+type rigSchedulerStatus struct {
+	Rig    string `json:"rig"`
+	Active int    `json:"active"`
+	Cap    int    `json:"cap,omitempty"` // 0 = uncapped
+	Weight int    `json:"weight"`
+	Queued int    `json:"queued"`
+	Ready  int    `json:"ready"`
+}
+
+// buildRigSchedulerStatus summarizes each rig that has active polecats,
+// scheduled beads or explicit scheduler settings, sorted by rig name.
+func buildRigSchedulerStatus(cfg *capacity.SchedulerConfig, active map[string]int, scheduled []scheduledBeadInfo) []rigSchedulerStatus {
+	byRig := make(map[string]*rigSchedulerStatus)
+	get := func(rig string) *rigSchedulerStatus {
+		if r, ok := byRig[rig]; ok {
+			return r
+		}
+		r := &rigSchedulerStatus{Rig: rig, Cap: cfg.GetRigCap(rig), Weight: cfg.GetRigWeight(rig)}
+		byRig[rig] = r
+		return r
+	}
+	for rig, n := range active {
+		get(rig).Active = n
+	}
+	for _, b := range scheduled {
+		r := get(b.TargetRig)
+		r.Queued++
+		if !b.Blocked {
+			r.Ready++
+		}
+	}
+	if cfg != nil {
+		for rig := range cfg.RigMaxPolecats {
+			get(rig)
+		}
+		for rig := range cfg.RigWeights {
+			get(rig)
+		}
+	}
+
+	result := make([]rigSchedulerStatus, 0, len(byRig))
+	for _, r := range byRig {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Rig < result[j].Rig })
+	return result
+}
+
 func runSchedulerStatus(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -142,25 +218,58 @@ func runSchedulerStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing scheduled beads: %w", err)
 	}
 
-	activePolecats := countActivePolecats()
+	schedulerCfg := capacity.DefaultSchedulerConfig()
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Scheduler != nil {
+		schedulerCfg = settings.Scheduler
+	}
+
+	scheduleErr := schedulerCfg.Validate()
+	policy := schedulerCfg.PolicyAt(time.Now())
+
+	activeByRig := countActivePolecatsByRig()
+	activePolecats := 0
+	for _, n := range activeByRig {
+		activePolecats += n
+	}
+	rigs := buildRigSchedulerStatus(schedulerCfg, activeByRig, scheduled)
 
 	if schedulerStatusJSON {
 		out := struct {
-			Paused         bool               `json:"paused"`
-			PausedBy       string             `json:"paused_by,omitempty"`
-			ScheduledTotal int                `json:"queued_total"`
-			ScheduledReady int                `json:"queued_ready"`
-			ActivePolecats int                `json:"active_polecats"`
-			LastDispatchAt string             `json:"last_dispatch_at,omitempty"`
-			Beads          []scheduledBeadInfo `json:"beads"`
+			Paused         bool                 `json:"paused"`
+			PausedBy       string               `json:"paused_by,omitempty"`
+			Blackout       string               `json:"blackout,omitempty"`
+			Window         string               `json:"window,omitempty"`
+			NextChange     string               `json:"next_change,omitempty"`
+			ScheduleError  string               `json:"schedule_error,omitempty"`
+			ScheduledTotal int                  `json:"queued_total"`
+			ScheduledReady int                  `json:"queued_ready"`
+			ActivePolecats int                  `json:"active_polecats"`
+			MaxPolecats    int                  `json:"max_polecats"`
+			LastDispatchAt string               `json:"last_dispatch_at,omitempty"`
+			Rigs           []rigSchedulerStatus `json:"rigs"`
+			Beads          []scheduledBeadInfo  `json:"beads"`
 		}{
 			Paused:         state.Paused,
 			PausedBy:       state.PausedBy,
 			ScheduledTotal: len(scheduled),
 			ActivePolecats: activePolecats,
+			MaxPolecats:    policy.MaxPolecats,
 			LastDispatchAt: state.LastDispatchAt,
+			Rigs:           rigs,
 			Beads:          scheduled,
 		}
+		if policy.Blackout != nil {
+			out.Blackout = windowName(policy.Blackout.Name)
+		}
+		if policy.Window != nil {
+			out.Window = windowName(policy.Window.Name)
+		}
+		if !policy.NextChange.IsZero() {
+			out.NextChange = policy.NextChange.UTC().Format(time.RFC3339)
+		}
+		if scheduleErr != nil {
+			out.ScheduleError = scheduleErr.Error()
+		}
 		for _, b := range scheduled {
 			if !b.Blocked {
 				out.ScheduledReady++
@@ -179,17 +288,41 @@ func runSchedulerStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s\n\n", style.Bold.Render("Scheduler Status"))
-	if state.Paused {
+	switch {
+	case state.Paused:
 		fmt.Printf("  State:    %s (by %s)\n", style.Warning.Render("PAUSED"), state.PausedBy)
-	} else {
+	case policy.Blackout != nil && schedulerCfg.IsDeferred():
+		fmt.Printf("  State:    %s %s\n", style.Warning.Render("BLACKOUT"), describeBlackout(policy))
+	default:
 		fmt.Printf("  State:    active\n")
 	}
+	if scheduleErr != nil {
+		fmt.Printf("  Schedule: %s %v\n", style.Error.Render("invalid:"), scheduleErr)
+	} else if schedulerCfg.HasSchedule() {
+		fmt.Printf("  Window:   %s\n", describeWindow(schedulerCfg, policy))
+	}
 	fmt.Printf("  Scheduled: %d total, %d ready\n", len(scheduled), readyCount)
-	fmt.Printf("  Active:    %d polecats\n", activePolecats)
+	if schedulerCfg.IsDeferred() {
+		fmt.Printf("  Active:    %d of %d polecats\n", activePolecats, policy.MaxPolecats)
+	} else {
+		fmt.Printf("  Active:    %d polecats\n", activePolecats)
+	}
 	if state.LastDispatchAt != "" {
 		fmt.Printf("  Last dispatch: %s (%d beads)\n", state.LastDispatchAt, state.LastDispatchCount)
 	}
 
+	if len(rigs) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Rigs"))
+		for _, r := range rigs {
+			capStr := "no cap"
+			if r.Cap > 0 {
+				capStr = fmt.Sprintf("cap %d", r.Cap)
+			}
+			fmt.Printf("    %-16s %d active (%s, weight %d), %d scheduled, %d ready\n",
+				r.Rig, r.Active, capStr, r.Weight, r.Queued, r.Ready)
+		}
+	}
+
 	return nil
 }
 
@@ -483,13 +616,22 @@ func beadsSearchDirs(townRoot string) []string {
 
 // countActivePolecats counts all running polecats across all rigs in the town.
 func countActivePolecats() int {
+	count := 0
+	for _, n := range countActivePolecatsByRig() {
+		count += n
+	}
+	return count
+}
+
+// countActivePolecatsByRig counts running polecats per rig.
+func countActivePolecatsByRig() map[string]int {
+	counts := make(map[string]int)
 	listCmd := tmux.BuildCommand("list-sessions", "-F", "#{session_name}")
 	out, err := listCmd.Output()
 	if err != nil {
-		return 0
+		return counts
 	}
 
-	count := 0
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 		if line == "" {
 			continue
@@ -499,8 +641,44 @@ func countActivePolecats() int {
 			continue
 		}
 		if identity.Role == session.RolePolecat {
-			count++
+			counts[identity.Rig]++
 		}
 	}
-	return count
+	return counts
+}
+
+// windowName returns a display name for a capacity or blackout window.
+func windowName(name string) string {
+	if name == "" {
+		return "(unnamed)"
+	}
+	return name
+}
+
+// describeBlackout describes the active blackout, e.g.
+// "release-freeze (code freeze) until Mon Jan 04 00:00".
+func describeBlackout(p capacity.DispatchPolicy) string {
+	desc := windowName(p.Blackout.Name)
+	if p.Blackout.Reason != "" {
+		desc += " (" + p.Blackout.Reason + ")"
+	}
+	if !p.BlackoutEnds.IsZero() {
+		desc += " until " + p.BlackoutEnds.Local().Format("Mon Jan 02 15:04")
+	}
+	return desc
+}
+
+// describeWindow describes the active capacity window and the next change.
+func describeWindow(cfg *capacity.SchedulerConfig, p capacity.DispatchPolicy) string {
+	desc := "base settings"
+	if p.Window != nil {
+		desc = windowName(p.Window.Name)
+	}
+	desc += fmt.Sprintf(" (max %d polecats, batch %d)", p.MaxPolecats, p.BatchSize)
+	if !cfg.IsDeferred() {
+		desc += " — inactive: schedules need scheduler.max_polecats > 0"
+	} else if !p.NextChange.IsZero() {
+		desc += ", changes " + p.NextChange.Local().Format("Mon Jan 02 15:04")
+	}
+	return desc
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+func TestBuildRigSchedulerStatus(t *testing.T) {
+	two := 2
+	cfg := &capacity.SchedulerConfig{
+		MaxPolecatsPerRig: &two,
+		RigWeights:        map[string]int{"idle": 4},
+	}
+	active := map[string]int{"gastown": 2}
+	scheduled := []scheduledBeadInfo{
+		{ID: "gt-1", TargetRig: "gastown"},
+		{ID: "gt-2", TargetRig: "gastown", Blocked: true},
+		{ID: "bd-1", TargetRig: "beads"},
+	}
+
+	rigs := buildRigSchedulerStatus(cfg, active, scheduled)
+	if len(rigs) != 3 {
+		t.Fatalf("got %d rigs, want 3: %+v", len(rigs), rigs)
+	}
+	want := []rigSchedulerStatus{
+		{Rig: "beads", Cap: 2, Weight: 1, Queued: 1, Ready: 1},
+		{Rig: "gastown", Active: 2, Cap: 2, Weight: 1, Queued: 2, Ready: 1},
+		{Rig: "idle", Cap: 2, Weight: 4},
+	}
+	for i := range want {
+		if rigs[i] != want[i] {
+			t.Errorf("rigs[%d] = %+v, want %+v", i, rigs[i], want[i])
+		}
+	}
+}
@@ -14,9 +14,11 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/routing"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var slingCmd = &cobra.Command{
@@ -55,6 +57,14 @@ Target Resolution:
   gt sling gt-abc deacon/dogs           # Auto-dispatch to idle dog
   gt sling gt-abc deacon/dogs/alpha     # Specific dog
 
+Auto-Routing (--auto):
+  gt sling gt-abc --auto                # Router picks the rig and agent preset
+
+  Rigs advertise capabilities (bead labels) and owned paths in the routing
+  block of their settings; the refinery's merge/reject history breaks ties
+  and chooses between agent presets. The choice is explained before dispatch.
+  With the scheduler enabled, the bead is re-routed when it is dispatched.
+
 Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --create               # Create polecat if missing
   gt sling gp-abc greenplace --force                # Ignore unread mail
@@ -128,6 +138,8 @@ var (
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
 	slingRalph         bool   // --ralph: enable Ralph Wiggum loop mode for multi-step workflows
 	slingFormula       string // --formula: override formula for dispatch (default: mol-polecat-work)
+	slingIgnoreBudget  bool   // --ignore-budget: dispatch even if a spend budget is exhausted
+	slingAuto          bool   // --auto: pick the rig and agent preset by routing
 )
 
 func init() {
@@ -154,11 +166,17 @@ func init() {
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
 	slingCmd.Flags().BoolVar(&slingRalph, "ralph", false, "Enable Ralph Wiggum loop mode (fresh context per step, for multi-step workflows)")
 	slingCmd.Flags().StringVar(&slingFormula, "formula", "", "Formula to apply (default: mol-polecat-work for polecat targets)")
+	slingCmd.Flags().BoolVar(&slingIgnoreBudget, "ignore-budget", false, "Dispatch even if a spend budget is exhausted")
+	slingCmd.Flags().BoolVar(&slingAuto, "auto", false, "Pick the rig and agent preset from bead labels, linked files and track record")
 
 	rootCmd.AddCommand(slingCmd)
 }
 
 func runSling(cmd *cobra.Command, args []string) (retErr error) {
+	// Root span of the work's trace: the bead records it (trace_parent) and
+	// spawned polecat sessions inherit it, so gt done and the refinery join.
+	ctx, span := telemetry.StartSpan(context.Background(), "gt.sling")
+	telemetry.SetTraceContext(ctx)
 	defer func() {
 		bead, target := "", ""
 		if len(args) > 0 {
@@ -167,7 +185,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		if len(args) > 1 {
 			target = args[1]
 		}
-		telemetry.RecordSling(context.Background(), bead, target, retErr)
+		span.SetAttributes(attribute.String("gt.bead", bead), attribute.String("gt.target", target))
+		telemetry.EndSpan(span, retErr)
+		telemetry.RecordSling(ctx, bead, target, retErr)
 	}()
 	// Polecats cannot sling - check early before writing anything.
 	// Check GT_ROLE first: coordinators (mayor, witness, etc.) may have a stale
@@ -242,6 +262,53 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		args[i] = strings.TrimRight(args[i], "/")
 	}
 
+	// Auto-routing: the router picks the rig (and agent preset) for a single
+	// bead. Deferred beads are re-routed again when the scheduler dispatches.
+	if slingAuto {
+		if len(args) != 1 || slingOnTarget != "" {
+			return fmt.Errorf("--auto routes a single bead: gt sling <bead> --auto")
+		}
+		beadID := args[0]
+		formula := resolveFormula(slingFormula, slingHookRawBead)
+		history, err := routing.LoadOutcomes(townRoot)
+		if err != nil {
+			style.PrintWarning("routing without track record: %v", err)
+		}
+		decision, err := routeBead(townRoot, beadID, formula, slingAgent, history)
+		if err != nil {
+			return fmt.Errorf("auto-routing %s: %w", beadID, err)
+		}
+		printRouteDecision(beadID, decision)
+
+		deferred, deferErr := shouldDeferDispatch()
+		if deferErr != nil {
+			return deferErr
+		}
+		if deferred {
+			return scheduleBead(beadID, decision.Rig, ScheduleOptions{
+				Formula:     formula,
+				Args:        slingArgs,
+				Vars:        slingVars,
+				Merge:       slingMerge,
+				BaseBranch:  slingBaseBranch,
+				NoConvoy:    slingNoConvoy,
+				Owned:       slingOwned,
+				DryRun:      slingDryRun,
+				Force:       slingForce,
+				NoMerge:     slingNoMerge,
+				Account:     slingAccount,
+				Agent:       slingAgent,
+				HookRawBead: slingHookRawBead,
+				Ralph:       slingRalph,
+				AutoRoute:   true,
+			})
+		}
+		if slingAgent == "" && decision.Agent != defaultPolecatAgent(townRoot, decision.Rig) {
+			slingAgent = decision.Agent
+		}
+		args = append(args, decision.Rig)
+	}
+
 	// Validate target format early, before any dispatch path (bead, formula, batch)
 	// can trigger resolveTarget side-effects like polecat spawning.
 	if len(args) > 1 {
@@ -279,6 +346,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 			fmt.Printf("  %s the rig can be auto-resolved from bead prefixes. "+
 				"You can omit <%s>.\n",
 				style.Dim.Render("Tip:"), rigName)
+			if err := enforceSlingBudget(townRoot, rigName, ""); err != nil {
+				return err
+			}
 			return runBatchSling(beadIDs, rigName, townBeadsDir)
 		}
 		// No explicit rig -- try auto-resolving from bead prefixes
@@ -287,6 +357,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 			if err != nil {
 				return err
 			}
+			if err := enforceSlingBudget(townRoot, rigName, ""); err != nil {
+				return err
+			}
 			return runBatchSling(args, rigName, townBeadsDir)
 		}
 	}
@@ -457,6 +530,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 			if err != nil {
 				return err
 			}
+			if err := enforceSlingBudget(townRoot, rigName, ""); err != nil {
+				return err
+			}
 			return runBatchSling(args, rigName, townBeadsDir)
 		}
 	}
@@ -605,6 +681,12 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 	if len(args) > 1 {
 		target = args[1]
 	}
+	// Spend budgets gate new polecat work before anything is spawned.
+	if rigName, isRig := IsRigName(target); isRig {
+		if err := enforceSlingBudget(townRoot, rigName, beadID); err != nil {
+			return err
+		}
+	}
 	resolved, err := resolveTarget(target, ResolveTargetOptions{
 		DryRun:     slingDryRun,
 		Force:      force,
@@ -698,8 +780,10 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 
 	// Auto-convoy: check if issue is already tracked by a convoy
 	// If not, create one for dashboard visibility (unless --no-convoy is set)
+	var slingConvoyID string
 	if !slingNoConvoy && formulaName == "" {
 		existingConvoy := isTrackedByConvoy(beadID)
+		slingConvoyID = existingConvoy
 		if existingConvoy == "" {
 			if slingDryRun {
 				fmt.Printf("Would create convoy 'Work: %s'\n", info.Title)
@@ -713,6 +797,7 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 					// Log warning but don't fail - convoy is optional
 					fmt.Printf("%s Could not create auto-convoy: %v\n", style.Dim.Render("Warning:"), err)
 				} else {
+					slingConvoyID = convoyID
 					fmt.Printf("%s Created convoy 🚚 %s\n", style.Bold.Render("→"), convoyID)
 					fmt.Printf("  Tracking: %s\n", beadID)
 					if slingOwned {
@@ -865,6 +950,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		AttachedMolecule: attachedMoleculeID,
 		NoMerge:          slingNoMerge,
 	}
+	if attachedMoleculeID != "" {
+		fieldUpdates.AttachedFormula = formulaName
+	}
 	if err := storeFieldsInBead(beadID, fieldUpdates); err != nil {
 		// Warn but don't fail - polecat will still complete work
 		fmt.Printf("%s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
@@ -891,6 +979,8 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 	// This ensures polecat sees the molecule when gt prime runs on session start.
 	freshlySpawned := newPolecatInfo != nil
 	if freshlySpawned {
+		newPolecatInfo.costWorkItem = beadID
+		newPolecatInfo.costConvoy = slingConvoyID
 		pane, err := newPolecatInfo.StartSession()
 		if err != nil {
 			// Rollback: session failed, clean up zombie artifacts (worktree, hooked bead).
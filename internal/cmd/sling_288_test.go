@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
 )
 
 // TestInstantiateFormulaOnBead verifies the helper function works correctly.
@@ -266,6 +268,63 @@ exit /b 0
 	}
 }
 
+// TestCookFormula_Composed verifies that a formula using extends/include is
+// cooked from its resolved form, since bd can't compose formulas.
+func TestCookFormula_Composed(t *testing.T) {
+	townRoot := t.TempDir()
+	formulasDir := filepath.Join(townRoot, ".beads", "formulas")
+	if err := os.MkdirAll(formulasDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"base-work":    "formula = \"base-work\"\ntype = \"workflow\"\n\n[[steps]]\nid = \"implement\"\ntitle = \"Implement\"\n",
+		"careful-work": "formula = \"careful-work\"\nextends = \"base-work\"\n\n[[steps]]\nid = \"review\"\ntitle = \"Review\"\nneeds = [\"implement\"]\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(formulasDir, name+".formula.toml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	binDir := filepath.Join(townRoot, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("mkdir binDir: %v", err)
+	}
+	logPath := filepath.Join(townRoot, "bd.log")
+	cookedPath := filepath.Join(townRoot, "cooked.toml")
+	bdScript := `#!/bin/sh
+echo "CMD:$*" >> "${BD_LOG}"
+if [ "$1" = "cook" ]; then cp "$2" "${BD_COOKED}"; fi
+exit 0
+`
+	bdScriptWindows := `@echo off
+echo CMD:%*>>"%BD_LOG%"
+if "%1"=="cook" copy "%2" "%BD_COOKED%" >nul
+exit /b 0
+`
+	_ = writeBDStub(t, binDir, bdScript, bdScriptWindows)
+
+	t.Setenv("BD_LOG", logPath)
+	t.Setenv("BD_COOKED", cookedPath)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := CookFormula("careful-work", townRoot, townRoot); err != nil {
+		t.Fatalf("CookFormula failed: %v", err)
+	}
+
+	logBytes, _ := os.ReadFile(logPath)
+	if strings.Contains(string(logBytes), "cook careful-work") {
+		t.Errorf("composed formula cooked by name: %s", logBytes)
+	}
+	cooked, err := formula.ParseFile(cookedPath)
+	if err != nil {
+		t.Fatalf("parsing cooked formula: %v", err)
+	}
+	if cooked.IsComposed() || cooked.Name != "careful-work" || cooked.GetStep("implement") == nil || cooked.GetStep("review") == nil {
+		t.Errorf("cooked formula not resolved: %+v", cooked)
+	}
+}
+
 // TestSlingHookRawBeadFlag verifies --hook-raw-bead flag exists.
 func TestSlingHookRawBeadFlag(t *testing.T) {
 	// Verify the flag variable exists and works
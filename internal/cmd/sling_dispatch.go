@@ -29,6 +29,7 @@ type SlingParams struct {
 	Account    string   // --account
 	Agent      string   // --agent
 	NoConvoy   bool     // --no-convoy
+	Convoy     string   // Existing convoy tracking the bead (cost attribution when NoConvoy)
 	Owned      bool     // --owned
 	NoMerge    bool     // --no-merge
 	Force      bool     // --force
@@ -214,6 +215,7 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 	hookWorkDir := spawnInfo.ClonePath
 
 	// 4. Auto-convoy (if !NoConvoy)
+	spawnInfo.costConvoy = params.Convoy
 	if !params.NoConvoy {
 		existingConvoy := isTrackedByConvoy(params.BeadID)
 		if existingConvoy == "" {
@@ -222,9 +224,11 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 				fmt.Printf("  %s Could not create auto-convoy: %v\n", style.Dim.Render("Warning:"), err)
 			} else {
 				fmt.Printf("  %s Created convoy %s\n", style.Bold.Render("→"), convoyID)
+				spawnInfo.costConvoy = convoyID
 			}
 		} else {
 			fmt.Printf("  %s Already tracked by convoy %s\n", style.Dim.Render("○"), existingConvoy)
+			spawnInfo.costConvoy = existingConvoy
 		}
 	}
 
@@ -301,6 +305,9 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 		NoMerge:          params.NoMerge,
 		Mode:             params.Mode,
 	}
+	if attachedMoleculeID != "" {
+		fieldUpdates.AttachedFormula = params.FormulaName
+	}
 	// Use beadToHook for the update target (may differ from beadID when formula-on-bead)
 	if err := storeFieldsInBead(beadToHook, fieldUpdates); err != nil {
 		fmt.Printf("  %s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
@@ -312,6 +319,7 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 	}
 
 	// 11. Start polecat session
+	spawnInfo.costWorkItem = params.BeadID
 	pane, err := spawnInfo.StartSession()
 	if err != nil {
 		fmt.Printf("  %s Could not start session: %v, cleaning up partial state...\n", style.Dim.Render("✗"), err)
@@ -133,9 +133,20 @@ func runSlingFormula(args []string) error {
 		formulaWorkDir = townRoot
 	}
 
+	// bd doesn't understand extends/include: cook and instantiate a
+	// flattened copy of composed formulas.
+	cookName, cookCleanup, err := resolveComposedFormulaToTempFile(formulaName, formulaWorkDir, townRoot)
+	if err != nil {
+		rollbackSpawned("")
+		return err
+	}
+	if cookCleanup != nil {
+		defer cookCleanup()
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	fmt.Printf("  Cooking formula...\n")
-	if err := BdCmd("cook", formulaName).
+	if err := BdCmd("cook", cookName).
 		Dir(formulaWorkDir).
 		WithGTRoot(townRoot).
 		Run(); err != nil {
@@ -145,7 +156,7 @@ func runSlingFormula(args []string) error {
 
 	// Step 2: Create wisp instance (ephemeral)
 	fmt.Printf("  Creating wisp...\n")
-	wispArgs := []string{"mol", "wisp", formulaName}
+	wispArgs := []string{"mol", "wisp", cookName}
 	for _, v := range slingVars {
 		wispArgs = append(wispArgs, "--var", v)
 	}
@@ -169,6 +180,7 @@ func runSlingFormula(args []string) error {
 	}
 
 	fmt.Printf("%s Wisp created: %s\n", style.Bold.Render("✓"), wispRootID)
+	recordFormulaRun(townRoot, formulaName, wispRootID, "", slingVars)
 
 	// Step 3: Hook the wisp bead with retry and verification.
 	// See: https://github.com/steveyegge/gastown/issues/148
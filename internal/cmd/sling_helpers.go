@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/telemetry"
@@ -81,6 +82,7 @@ type beadInfo struct {
 	Description  string           `json:"description"`
 	Labels       []string         `json:"labels,omitempty"`
 	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
+	Dependents   []beads.IssueDep `json:"dependents,omitempty"`
 	IssueType    string           `json:"issue_type,omitempty"`
 }
 
@@ -226,6 +228,7 @@ type beadFieldUpdates struct {
 	Dispatcher       string // Agent that dispatched the work
 	Args             string // Natural language instructions
 	AttachedMolecule string // Wisp root ID
+	AttachedFormula  string // Formula the wisp was instantiated from
 	NoMerge          bool   // Skip merge queue on completion
 	Mode             string // Execution mode: "" (normal) or "ralph"
 	ConvoyID         string // Convoy bead ID (e.g., "hq-cv-abc")
@@ -283,6 +286,9 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 			fields.AttachedAt = time.Now().UTC().Format(time.RFC3339)
 		}
 	}
+	if updates.AttachedFormula != "" {
+		fields.AttachedFormula = updates.AttachedFormula
+	}
 	if updates.NoMerge {
 		fields.NoMerge = true
 	}
@@ -298,6 +304,10 @@ func storeFieldsInBead(beadID string, updates beadFieldUpdates) error {
 	if updates.ConvoyOwned {
 		fields.ConvoyOwned = true
 	}
+	// Record the sling's trace so the polecat, gt done and the refinery join it.
+	if tp := telemetry.CurrentTraceParent(); tp != "" {
+		fields.TraceParent = tp
+	}
 
 	// Write back once
 	newDesc := beads.SetAttachmentFields(issue, fields)
@@ -623,6 +633,11 @@ func nudgeRefinery(rigName, message string) {
 		})
 	}
 
+	if sandbox.Active() {
+		// No tmux in the sandbox; the event above wakes the refinery.
+		return
+	}
+
 	t := tmux.NewTmux()
 	if err := t.NudgeSession(refinerySession, message); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to nudge refinery %s: %v\n", refinerySession, err)
@@ -657,22 +672,39 @@ type FormulaOnBeadResult struct {
 //   - extraVars: additional --var values supplied by the user
 //
 // Returns the wisp root ID which should be hooked.
-func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot string, skipCook bool, extraVars []string) (_ *FormulaOnBeadResult, retErr error) {
+func InstantiateFormulaOnBead(formulaName, beadID, title, hookWorkDir, townRoot string, skipCook bool, extraVars []string) (res *FormulaOnBeadResult, retErr error) {
 	defer func() { telemetry.RecordFormulaInstantiate(context.Background(), formulaName, beadID, retErr) }()
+	defer func() {
+		if retErr == nil && res != nil {
+			recordFormulaRun(townRoot, formulaName, res.WispRootID, beadID,
+				append([]string{"feature=" + title, "issue=" + beadID}, extraVars...))
+		}
+	}()
 	// Route bd mutations (wisp/bond) to the correct beads context for the target bead.
 	formulaWorkDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 
+	// bd doesn't understand extends/include: composed formulas are cooked
+	// and instantiated from a flattened copy.
+	resolvedFormula, formulaCleanup, err := resolveComposedFormulaToTempFile(formulaName, formulaWorkDir, townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if formulaCleanup != nil {
+		defer formulaCleanup()
+	}
+
 	// Step 1: Cook the formula (ensures proto exists)
 	// If cook fails, retry with the embedded formula extracted to a temp file.
 	// This handles non-gastown rigs that don't have formulas provisioned on disk.
 	// See gt-oir.
-	resolvedFormula := formulaName
-	var formulaCleanup func()
 	if !skipCook {
-		if err := BdCmd("cook", formulaName).
+		if err := BdCmd("cook", resolvedFormula).
 			Dir(formulaWorkDir).
 			WithGTRoot(townRoot).
 				Run(); err != nil {
+			if resolvedFormula != formulaName {
+				return nil, fmt.Errorf("cooking formula %s: %w", formulaName, err)
+			}
 			// Retry with embedded formula
 			resolvedFormula, formulaCleanup = resolveFormulaToTempFile(formulaName)
 			if formulaCleanup != nil {
@@ -868,12 +900,19 @@ func ensureFormulaRequiredVars(formulaName string, vars []string) []string {
 // townRoot is required for GT_ROOT so bd can find town-level formulas.
 // Falls back to embedded formula extraction if bd can't find the formula on disk.
 func CookFormula(formulaName, workDir, townRoot string) error {
-	err := BdCmd("cook", formulaName).
+	composed, composedCleanup, err := resolveComposedFormulaToTempFile(formulaName, workDir, townRoot)
+	if err != nil {
+		return err
+	}
+	if composedCleanup != nil {
+		defer composedCleanup()
+	}
+	err = BdCmd("cook", composed).
 		Dir(workDir).
 		WithGTRoot(townRoot).
 		Run()
-	if err == nil {
-		return nil
+	if err == nil || composed != formulaName {
+		return err
 	}
 	// Retry with embedded formula extracted to temp file
 	resolved, cleanup := resolveFormulaToTempFile(formulaName)
@@ -889,27 +928,60 @@ func CookFormula(formulaName, workDir, townRoot string) error {
 		Run()
 }
 
-// resolveFormulaToTempFile extracts an embedded formula to a temp file.
-// Returns the temp file path and a cleanup function, or the original name
-// if extraction fails. Used as a fallback when bd can't find the formula on disk.
-func resolveFormulaToTempFile(formulaName string) (resolved string, cleanup func()) {
-	content, err := formula.GetEmbeddedFormulaContent(formulaName)
+// resolveComposedFormulaToTempFile writes the resolved form of a formula that
+// extends or includes others to a temp file for bd, which can't compose
+// formulas itself. Plain formulas, and ones gt can't find or parse, are left
+// to bd: it gets formulaName back and a nil cleanup.
+func resolveComposedFormulaToTempFile(formulaName, workDir, townRoot string) (resolved string, cleanup func(), err error) {
+	r := newFormulaResolverAt(workDir, townRoot)
+	src, err := r.Find(formulaName)
+	if err != nil || !src.Formula.IsComposed() {
+		return formulaName, nil, nil
+	}
+	f, err := r.ResolveSource(src)
 	if err != nil {
-		return formulaName, nil
+		return "", nil, fmt.Errorf("resolving formula %s: %w", formulaName, err)
+	}
+	content, err := f.Encode()
+	if err != nil {
+		return "", nil, err
+	}
+	path, cleanup, err := writeFormulaTempFile(content)
+	if err != nil {
+		return "", nil, fmt.Errorf("writing resolved formula %s: %w", formulaName, err)
 	}
+	return path, cleanup, nil
+}
 
+// writeFormulaTempFile writes formula content to a temp file and returns
+// its path with a cleanup function that removes it.
+func writeFormulaTempFile(content []byte) (string, func(), error) {
 	tmpFile, err := os.CreateTemp("", "gt-formula-*.formula.toml")
 	if err != nil {
-		return formulaName, nil
+		return "", nil, err
 	}
 	if _, err := tmpFile.Write(content); err != nil {
 		tmpFile.Close()
 		os.Remove(tmpFile.Name())
-		return formulaName, nil
+		return "", nil, err
 	}
 	tmpFile.Close()
+	return tmpFile.Name(), func() { os.Remove(tmpFile.Name()) }, nil
+}
 
-	return tmpFile.Name(), func() { os.Remove(tmpFile.Name()) }
+// resolveFormulaToTempFile extracts an embedded formula to a temp file.
+// Returns the temp file path and a cleanup function, or the original name
+// if extraction fails. Used as a fallback when bd can't find the formula on disk.
+func resolveFormulaToTempFile(formulaName string) (resolved string, cleanup func()) {
+	content, err := formula.GetEmbeddedFormulaContent(formulaName)
+	if err != nil {
+		return formulaName, nil
+	}
+	path, cleanup, err := writeFormulaTempFile(content)
+	if err != nil {
+		return formulaName, nil
+	}
+	return path, cleanup
 }
 
 // isHookedAgentDeadFn is a seam for tests. Production uses isHookedAgentDead.
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/routing"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// routeBead picks a rig and agent preset for a bead from its labels, the
+// files its linked beads touched, and the town's routing track record.
+// agent pins the agent preset (--agent); empty lets the router choose.
+func routeBead(townRoot, beadID, formula, agent string, history []routing.Outcome) (*routing.Decision, error) {
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return nil, err
+	}
+	var linked []string
+	for _, dep := range info.Dependencies {
+		linked = append(linked, dep.ID)
+	}
+	for _, dep := range info.Dependents {
+		linked = append(linked, dep.ID)
+	}
+	req := routing.Request{
+		Bead:    beadID,
+		Labels:  info.Labels,
+		Paths:   routing.TouchedFiles(history, linked),
+		Formula: formula,
+		Agent:   agent,
+	}
+	return routing.Route(req, routingCandidates(townRoot), history)
+}
+
+// routingCandidates returns the town's rigs that can take routed work:
+// registered, not parked or docked, with their routing settings and
+// default polecat agent.
+func routingCandidates(townRoot string) []routing.Candidate {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var candidates []routing.Candidate
+	for _, name := range names {
+		prefix := ""
+		if entry := rigsConfig.Rigs[name]; entry.BeadsConfig != nil {
+			prefix = entry.BeadsConfig.Prefix
+		}
+		if IsRigParked(townRoot, name) || (prefix != "" && IsRigDocked(townRoot, name, prefix)) {
+			continue
+		}
+		rigPath := filepath.Join(townRoot, name)
+		cand := routing.Candidate{Rig: name}
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil {
+			cand.Routing = settings.Routing
+		}
+		if agent, _ := config.ResolveRoleAgentName("polecat", townRoot, rigPath); agent != "" {
+			cand.Agents = []string{agent}
+		}
+		candidates = append(candidates, cand)
+	}
+	return candidates
+}
+
+// defaultPolecatAgent returns the agent preset a rig's polecats run by default.
+func defaultPolecatAgent(townRoot, rigName string) string {
+	agent, _ := config.ResolveRoleAgentName("polecat", townRoot, filepath.Join(townRoot, rigName))
+	return agent
+}
+
+// printRouteDecision explains an auto-routing decision.
+func printRouteDecision(beadID string, d *routing.Decision) {
+	fmt.Printf("%s Routed %s → %s", style.Bold.Render("🧭"), beadID, d.Explain())
+}
+
+// annotateAutoRoutes re-routes auto-routed sling contexts against the current
+// track record, so a deferred bead goes where it fits best at dispatch time.
+// Beads that can no longer be routed keep the rig chosen when scheduled.
+func annotateAutoRoutes(townRoot string, pending []capacity.PendingBead) {
+	var history []routing.Outcome
+	loaded := false
+	for i := range pending {
+		ctx := pending[i].Context
+		if ctx == nil || !ctx.AutoRoute {
+			continue
+		}
+		if !loaded {
+			history, _ = routing.LoadOutcomes(townRoot)
+			loaded = true
+		}
+		d, err := routeBead(townRoot, ctx.WorkBeadID, ctx.Formula, ctx.Agent, history)
+		if err != nil {
+			fmt.Printf("  %s Could not re-route %s, keeping %s: %v\n",
+				style.Dim.Render("○"), ctx.WorkBeadID, pending[i].TargetRig, err)
+			continue
+		}
+		pending[i].TargetRig = d.Rig
+		if ctx.Agent == "" && d.Agent != defaultPolecatAgent(townRoot, d.Rig) {
+			pending[i].Agent = d.Agent
+		}
+	}
+}
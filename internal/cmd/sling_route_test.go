@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+func TestRoutingCandidates(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), &config.RigsConfig{
+		Version: 1,
+		Rigs:    map[string]config.RigEntry{"web": {}, "api": {}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewRigSettings()
+	settings.Agent = "codex"
+	settings.Routing = &config.RoutingConfig{Capabilities: []string{"frontend"}}
+	if err := os.MkdirAll(filepath.Join(townRoot, "web", "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigSettings(config.RigSettingsPath(filepath.Join(townRoot, "web")), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates := routingCandidates(townRoot)
+	if len(candidates) != 2 || candidates[0].Rig != "api" || candidates[1].Rig != "web" {
+		t.Fatalf("routingCandidates = %+v, want api and web in order", candidates)
+	}
+	if candidates[0].Routing != nil {
+		t.Errorf("api has no routing settings, got %+v", candidates[0].Routing)
+	}
+	web := candidates[1]
+	if web.Routing == nil || web.Routing.Capabilities[0] != "frontend" {
+		t.Errorf("web routing = %+v", web.Routing)
+	}
+	if len(web.Agents) != 1 || web.Agents[0] != "codex" {
+		t.Errorf("web default agents = %v, want [codex]", web.Agents)
+	}
+}
+
+func TestAnnotateAutoRoutes_SkipsPinnedContexts(t *testing.T) {
+	pending := []capacity.PendingBead{
+		{WorkBeadID: "gt-1", TargetRig: "api", Context: &capacity.SlingContextFields{WorkBeadID: "gt-1", TargetRig: "api"}},
+	}
+	annotateAutoRoutes(t.TempDir(), pending)
+	if pending[0].TargetRig != "api" || pending[0].Agent != "" {
+		t.Errorf("non-auto context was re-routed: %+v", pending[0])
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
-	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -303,7 +302,7 @@ func runWarrantExecute(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	tm := tmux.NewTmux()
+	tm := session.NewBackend()
 
 	if warrant != nil {
 		if err := executeOneWarrant(warrant, warrantPath, tm); err != nil {
@@ -335,7 +334,7 @@ func runWarrantExecute(cmd *cobra.Command, args []string) error {
 // session exists, kills it with full process tree cleanup, and marks the warrant
 // as executed on disk. Returns nil on success. On error, the warrant is NOT
 // marked as executed so it can be retried on the next triage cycle.
-func executeOneWarrant(w *Warrant, warrantPath string, tm *tmux.Tmux) error {
+func executeOneWarrant(w *Warrant, warrantPath string, tm session.Backend) error {
 	sessionName, err := targetToSessionName(w.Target)
 	if err != nil {
 		return fmt.Errorf("invalid target %s: %w", w.Target, err)
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -18,6 +19,7 @@ import (
 var (
 	witnessForeground    bool
 	witnessStatusJSON    bool
+	witnessTimeoutsJSON  bool
 	witnessAgentOverride string
 	witnessEnvOverrides  []string
 )
@@ -120,6 +122,23 @@ Examples:
 	RunE: runWitnessRestart,
 }
 
+var witnessTimeoutsCmd = &cobra.Command{
+	Use:   "timeouts <rig>",
+	Short: "Fail molecule steps that exceeded their formula timeout",
+	Long: `Enforce formula step timeouts for a rig's molecules.
+
+Formula steps may declare a timeout (e.g. timeout = "30m"). A step still
+running past its timeout fails the molecule: the polecat is nudged to stop
+and run 'gt done --status ESCALATED', and the Deacon is sent a STEP_TIMEOUT
+escalation. Run by the Witness on each patrol cycle.
+
+Examples:
+  gt witness timeouts greenplace
+  gt witness timeouts greenplace --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessTimeouts,
+}
+
 func init() {
 	// Start flags
 	witnessStartCmd.Flags().BoolVar(&witnessForeground, "foreground", false, "Run in foreground (default: background)")
@@ -129,6 +148,9 @@ func init() {
 	// Status flags
 	witnessStatusCmd.Flags().BoolVar(&witnessStatusJSON, "json", false, "Output as JSON")
 
+	// Timeouts flags
+	witnessTimeoutsCmd.Flags().BoolVar(&witnessTimeoutsJSON, "json", false, "Output as JSON")
+
 	// Restart flags
 	witnessRestartCmd.Flags().StringVar(&witnessAgentOverride, "agent", "", "Agent alias to run the Witness with (overrides town default)")
 	witnessRestartCmd.Flags().StringArrayVar(&witnessEnvOverrides, "env", nil, "Environment variable override (KEY=VALUE, can be repeated)")
@@ -139,6 +161,7 @@ func init() {
 	witnessCmd.AddCommand(witnessRestartCmd)
 	witnessCmd.AddCommand(witnessStatusCmd)
 	witnessCmd.AddCommand(witnessAttachCmd)
+	witnessCmd.AddCommand(witnessTimeoutsCmd)
 
 	rootCmd.AddCommand(witnessCmd)
 }
@@ -290,6 +313,41 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runWitnessTimeouts(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	result := witness.DetectStepTimeouts(r.Path, rigName, mail.NewRouter(townRoot))
+
+	if witnessTimeoutsJSON {
+		return outputJSON(result)
+	}
+
+	for _, e := range result.Errors {
+		style.PrintWarning("%v", e)
+	}
+	if len(result.TimedOut) == 0 {
+		fmt.Printf("%s No step timeouts (%d molecule(s) checked)\n", style.Success.Render("✓"), result.Checked)
+		return nil
+	}
+	for _, t := range result.TimedOut {
+		polecat := t.PolecatName
+		if polecat == "" {
+			polecat = "unknown polecat"
+		}
+		fmt.Printf("%s %s step %s exceeded %s (%s)\n",
+			style.Error.Render("✗"), t.MoleculeID, t.StepID, t.Timeout, polecat)
+		if t.Error != nil {
+			style.PrintWarning("%v", t.Error)
+		}
+	}
+	return nil
+}
+
 // witnessSessionName returns the tmux session name for a rig's witness.
 func witnessSessionName(rigName string) string {
 	return session.WitnessSessionName(session.PrefixFor(rigName))
@@ -132,6 +132,11 @@ type AgentPresetInfo struct {
 	// EmitsPermissionWarning indicates the agent shows a bypass-permissions warning on startup
 	// that needs to be acknowledged via tmux.
 	EmitsPermissionWarning bool `json:"emits_permission_warning,omitempty"`
+
+	// UsageFormat identifies the on-disk session log format gt costs reads token
+	// usage from (e.g., "claude", "codex", "gemini", "opencode").
+	// Empty means the agent's spend is not tracked.
+	UsageFormat string `json:"usage_format,omitempty"`
 }
 
 // NonInteractiveConfig contains settings for running agents non-interactively.
@@ -185,6 +190,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ReadyDelayMs:           10000,
 		InstructionsFile:       "CLAUDE.md",
 		EmitsPermissionWarning: true,
+		UsageFormat:            "claude",
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
@@ -208,6 +214,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		HooksSettingsFile: "settings.json",
 		ReadyDelayMs:      5000,
 		InstructionsFile:  "AGENTS.md",
+		UsageFormat:       "gemini",
 	},
 	AgentCodex: {
 		Name:                AgentCodex,
@@ -227,6 +234,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		PromptMode:       "none",
 		ReadyDelayMs:     3000,
 		InstructionsFile: "AGENTS.md",
+		UsageFormat:      "codex",
 	},
 	AgentCursor: {
 		Name:                AgentCursor,
@@ -300,6 +308,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		HooksSettingsFile: "gastown.js",
 		ReadyDelayMs:      8000,
 		InstructionsFile:  "AGENTS.md",
+		UsageFormat:       "opencode",
 	},
 	AgentCopilot: {
 		Name:                AgentCopilot,
@@ -638,6 +647,29 @@ func ResolveProcessNames(agentName, command string) []string {
 	return []string{"node", "claude"}
 }
 
+// ResolveUsageFormat determines the usage log format for an agent, resolving
+// custom agents by their command the same way ResolveProcessNames does.
+// Returns "" if the agent's usage cannot be tracked.
+func ResolveUsageFormat(agentName, command string) string {
+	registryMu.Lock()
+	initRegistryLocked()
+	defer registryMu.Unlock()
+
+	if info, ok := globalRegistry.Agents[agentName]; ok {
+		return info.UsageFormat
+	}
+	if command == "" {
+		return ""
+	}
+	cmdBase := filepath.Base(command)
+	for _, info := range globalRegistry.Agents {
+		if info.Command == command || filepath.Base(info.Command) == cmdBase {
+			return info.UsageFormat
+		}
+	}
+	return ""
+}
+
 // MergeWithPreset applies preset defaults to a RuntimeConfig.
 // User-specified values take precedence over preset defaults.
 // Returns a new RuntimeConfig without modifying the original.
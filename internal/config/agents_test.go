@@ -1258,3 +1258,22 @@ func TestAllHookSupportingAgentsHaveHookFields(t *testing.T) {
 		}
 	}
 }
+
+func TestResolveUsageFormat(t *testing.T) {
+	tests := []struct {
+		agent, command, want string
+	}{
+		{"claude", "", "claude"},
+		{"codex", "", "codex"},
+		{"gemini", "", "gemini"},
+		{"opencode", "", "opencode"},
+		{"my-claude", "/opt/bin/claude", "claude"}, // custom agent, matched by command
+		{"unknown", "", ""},
+		{"unknown", "aider", ""},
+	}
+	for _, tt := range tests {
+		if got := ResolveUsageFormat(tt.agent, tt.command); got != tt.want {
+			t.Errorf("ResolveUsageFormat(%q, %q) = %q, want %q", tt.agent, tt.command, got, tt.want)
+		}
+	}
+}
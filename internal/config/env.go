@@ -58,6 +58,12 @@ type AgentEnvConfig struct {
 	// Added as gt.session to OTEL_RESOURCE_ATTRIBUTES so all Claude logs from a
 	// single GT session can be correlated, and as GT_SESSION env var.
 	SessionName string
+
+	// TraceParent is the W3C traceparent of the span that started the session
+	// (e.g., the gt sling that assigned its work). Set as TRACEPARENT so gt
+	// and bd calls inside the session join that trace. Left empty for
+	// long-lived agents, which are not part of any one piece of work.
+	TraceParent string
 }
 
 // AgentEnv returns all environment variables for an agent based on the config.
@@ -250,6 +256,15 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		}
 	}
 
+	// Distributed tracing: gt commands inside the session export spans to the
+	// same collector and parent them under the span that started the session.
+	if tracesURL := os.Getenv("GT_OTEL_TRACES_URL"); tracesURL != "" {
+		env["GT_OTEL_TRACES_URL"] = tracesURL
+	}
+	if cfg.TraceParent != "" {
+		env["TRACEPARENT"] = cfg.TraceParent
+	}
+
 	// Pass through cloud API credentials and provider configuration from the parent shell.
 	// Only variables explicitly listed here are forwarded; all others are blocked for isolation.
 	for _, key := range []string{
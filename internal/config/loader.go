@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/constants"
@@ -219,12 +222,49 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Sandbox != nil {
+		if err := validateSandboxConfig(c.Sandbox); err != nil {
+			return err
+		}
+	}
+	if c.Routing != nil {
+		if err := validateRoutingConfig(c.Routing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRoutingConfig validates a RoutingConfig.
+func validateRoutingConfig(c *RoutingConfig) error {
+	for _, p := range c.Paths {
+		if _, err := path.Match(strings.ReplaceAll(p, "**", "*"), ""); err != nil {
+			return fmt.Errorf("invalid routing path %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// validateSandboxConfig validates a SandboxConfig.
+func validateSandboxConfig(c *SandboxConfig) error {
+	if c.CPUs < 0 {
+		return fmt.Errorf("invalid sandbox cpus: %v", c.CPUs)
+	}
+	if c.Pids < 0 {
+		return fmt.Errorf("invalid sandbox pids: %d", c.Pids)
+	}
+	if _, err := c.MemoryBytes(); err != nil {
+		return err
+	}
 	return nil
 }
 
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
+// ErrInvalidMergeStrategy indicates an invalid merge_strategy.
+var ErrInvalidMergeStrategy = errors.New("invalid merge_strategy")
+
 // validateMergeQueueConfig validates a MergeQueueConfig.
 func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	// Validate on_conflict strategy
@@ -233,6 +273,27 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 			ErrInvalidOnConflict, c.OnConflict, OnConflictAssignBack, OnConflictAutoRebase)
 	}
 
+	if c.MergeStrategy != "" && !slices.Contains(MergeStrategies, c.MergeStrategy) {
+		return fmt.Errorf("%w: got '%s', want one of %s",
+			ErrInvalidMergeStrategy, c.MergeStrategy, strings.Join(MergeStrategies, ", "))
+	}
+	if c.CommitTemplate != "" {
+		if _, err := template.New("commit_template").Parse(c.CommitTemplate); err != nil {
+			return fmt.Errorf("invalid commit_template: %w", err)
+		}
+	}
+	if c.Forge != nil {
+		if err := c.Forge.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, t := range c.CommitTrailers {
+		if t != CommitTrailerBead && t != CommitTrailerCoAuthoredBy {
+			return fmt.Errorf("invalid commit_trailers entry '%s', want '%s' or '%s'",
+				t, CommitTrailerBead, CommitTrailerCoAuthoredBy)
+		}
+	}
+
 	// Validate poll_interval if specified
 	if c.PollInterval != "" {
 		if _, err := time.ParseDuration(c.PollInterval); err != nil {
@@ -2358,9 +2419,197 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		return fmt.Errorf("%w: max_reescalations must be non-negative", ErrMissingField)
 	}
 
+	// Validate channel settings if specified
+	if c.Channels != nil {
+		if w := c.Channels.Webhook; w != nil {
+			if w.InitialBackoff != "" {
+				if _, err := time.ParseDuration(w.InitialBackoff); err != nil {
+					return fmt.Errorf("invalid channels.webhook.initial_backoff: %w", err)
+				}
+			}
+			if w.Timeout != "" {
+				if _, err := time.ParseDuration(w.Timeout); err != nil {
+					return fmt.Errorf("invalid channels.webhook.timeout: %w", err)
+				}
+			}
+		}
+		if s := c.Channels.SMTP; s != nil && (s.Host == "" || s.From == "") {
+			return fmt.Errorf("%w: channels.smtp requires host and from", ErrMissingField)
+		}
+		if s := c.Channels.SMS; s != nil && s.URL == "" {
+			return fmt.Errorf("%w: channels.sms requires url", ErrMissingField)
+		}
+	}
+
+	// Validate quiet hours and on-call windows if specified
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+	if q := c.QuietHours; q != nil {
+		if err := validateDailyWindow("quiet_hours", q.Start, q.End, q.Days); err != nil {
+			return err
+		}
+		if q.MinSeverity != "" && !IsValidSeverity(q.MinSeverity) {
+			return fmt.Errorf("%w: quiet_hours.min_severity '%s' (valid: low, medium, high, critical)", ErrMissingField, q.MinSeverity)
+		}
+	}
+	for i, w := range c.OnCall {
+		if w.Name == "" {
+			return fmt.Errorf("%w: on_call[%d] requires name", ErrMissingField, i)
+		}
+		if err := validateDailyWindow(fmt.Sprintf("on_call[%d]", i), w.Start, w.End, w.Days); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// weekdayNames maps the day names accepted in escalation windows.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// validateDailyWindow checks the start/end clock times and days of a window.
+func validateDailyWindow(name, start, end string, days []string) error {
+	if _, err := parseClock(start); err != nil {
+		return fmt.Errorf("invalid %s.start: %w", name, err)
+	}
+	if _, err := parseClock(end); err != nil {
+		return fmt.Errorf("invalid %s.end: %w", name, err)
+	}
+	for _, day := range days {
+		if _, ok := weekdayNames[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid %s.days: unknown day %q (valid: mon, tue, wed, thu, fri, sat, sun)", name, day)
+		}
+	}
+	return nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inDailyWindow reports whether t falls inside a daily start–end window.
+// Windows with end before start wrap past midnight and belong to the day they
+// start on; start equal to end covers the whole day.
+func inDailyWindow(t time.Time, start, end string, days []string) bool {
+	s, err := parseClock(start)
+	if err != nil {
+		return false
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return false
+	}
+	m := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	switch {
+	case s == e:
+	case s < e:
+		if m < s || m >= e {
+			return false
+		}
+	case m >= s:
+	case m < e:
+		day = (day + 6) % 7 // early-morning tail of yesterday's window
+	default:
+		return false
+	}
+	if len(days) == 0 {
+		return true
+	}
+	for _, name := range days {
+		if wd, ok := weekdayNames[strings.ToLower(name)]; ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// location returns the zone escalation windows are written in.
+func (c *EscalationConfig) location() *time.Location {
+	if c.Timezone != "" {
+		if loc, err := time.LoadLocation(c.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// InQuietHours reports whether t falls inside the configured quiet hours.
+func (c *EscalationConfig) InQuietHours(t time.Time) bool {
+	q := c.QuietHours
+	return q != nil && inDailyWindow(t.In(c.location()), q.Start, q.End, q.Days)
+}
+
+// HoldForQuietHours reports whether a re-escalation to severity should be
+// held back at t. Severities at or above quiet_hours.min_severity (default
+// critical) are always delivered.
+func (c *EscalationConfig) HoldForQuietHours(severity string, t time.Time) bool {
+	if !c.InQuietHours(t) {
+		return false
+	}
+	minSeverity := c.QuietHours.MinSeverity
+	if minSeverity == "" {
+		minSeverity = SeverityCritical
+	}
+	return severityRank(severity) < severityRank(minSeverity)
+}
+
+// OnCallAt returns the on-call window covering t, or nil if none does.
+func (c *EscalationConfig) OnCallAt(t time.Time) *EscalationOnCall {
+	local := t.In(c.location())
+	for i := range c.OnCall {
+		if inDailyWindow(local, c.OnCall[i].Start, c.OnCall[i].End, c.OnCall[i].Days) {
+			return &c.OnCall[i]
+		}
+	}
+	return nil
+}
+
+// ContactsAt returns the contacts in effect at t: the town contacts with any
+// fields set by the active on-call window overriding them.
+func (c *EscalationConfig) ContactsAt(t time.Time) EscalationContacts {
+	contacts := c.Contacts
+	w := c.OnCallAt(t)
+	if w == nil {
+		return contacts
+	}
+	if w.Contacts.HumanEmail != "" {
+		contacts.HumanEmail = w.Contacts.HumanEmail
+	}
+	if w.Contacts.HumanSMS != "" {
+		contacts.HumanSMS = w.Contacts.HumanSMS
+	}
+	if w.Contacts.SlackWebhook != "" {
+		contacts.SlackWebhook = w.Contacts.SlackWebhook
+	}
+	if w.Contacts.WebhookURL != "" {
+		contacts.WebhookURL = w.Contacts.WebhookURL
+	}
+	return contacts
+}
+
+// severityRank orders severities from low (0) to critical (3).
+// Unknown severities rank below low.
+func severityRank(severity string) int {
+	for i, s := range ValidSeverities() {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
 // GetStaleThreshold returns the stale threshold as a time.Duration.
 // Returns 4 hours if not configured or invalid.
 func (c *EscalationConfig) GetStaleThreshold() time.Duration {
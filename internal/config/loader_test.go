@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -240,6 +241,40 @@ func TestRigSettingsWithCustomMergeQueue(t *testing.T) {
 	}
 }
 
+func TestMergeQueueConfigValidation_MergeStrategy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		mq      MergeQueueConfig
+		wantErr bool
+	}{
+		{"default", MergeQueueConfig{}, false},
+		{"rebase-ff with trailers", MergeQueueConfig{
+			MergeStrategy:  MergeStrategyRebaseFF,
+			CommitTemplate: "{{.Title}} ({{.BeadID}})",
+			CommitTrailers: []string{CommitTrailerBead, CommitTrailerCoAuthoredBy},
+		}, false},
+		{"unknown strategy", MergeQueueConfig{MergeStrategy: "octopus"}, true},
+		{"unparseable template", MergeQueueConfig{CommitTemplate: "{{.Title"}, true},
+		{"unknown trailer", MergeQueueConfig{CommitTrailers: []string{"signed-off-by"}}, true},
+		{"forge github", MergeQueueConfig{Forge: &ForgeConfig{Type: ForgeGitHub, RequiredChecks: []string{"ci"}}}, false},
+		{"forge unknown type", MergeQueueConfig{Forge: &ForgeConfig{Type: "bitbucket"}}, true},
+		{"forge gitea without url", MergeQueueConfig{Forge: &ForgeConfig{Type: ForgeGitea}}, true},
+		{"forge without required checks", MergeQueueConfig{Forge: &ForgeConfig{Type: ForgeGitLab}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMergeQueueConfig(&tt.mq)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMergeQueueConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.mq.MergeStrategy == "octopus" && !errors.Is(err, ErrInvalidMergeStrategy) {
+				t.Errorf("expected ErrInvalidMergeStrategy, got %v", err)
+			}
+		})
+	}
+}
+
 func TestRigConfigValidation(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -328,6 +363,42 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid sandbox",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Sandbox: &SandboxConfig{Enabled: true, CPUs: 2, Memory: "4G", Pids: 512},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid sandbox memory",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Sandbox: &SandboxConfig{Enabled: true, Memory: "lots"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid routing",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Routing: &RoutingConfig{Capabilities: []string{"frontend"}, Paths: []string{"web/**", "*.proto"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid routing path",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Routing: &RoutingConfig{Paths: []string{"web/[a"}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid poll_interval",
 			settings: &RigSettings{
@@ -431,6 +502,33 @@ func TestLoadRigConfigNotFound(t *testing.T) {
 	}
 }
 
+func TestSandboxConfigMemoryBytes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]int64{
+		"":      0,
+		"1024":  1024,
+		"512M":  512 << 20,
+		"4g":    4 << 30,
+		"1.5G":  3 << 29,
+		"2T":    2 << 40,
+		"-1G":   -1,
+		"G":     -1,
+		"4 GiB": -1,
+	}
+	for in, want := range tests {
+		got, err := (&SandboxConfig{Memory: in}).MemoryBytes()
+		if want < 0 {
+			if err == nil {
+				t.Errorf("MemoryBytes(%q) = %d, want error", in, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("MemoryBytes(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+}
+
 func TestLoadRigSettingsNotFound(t *testing.T) {
 	t.Parallel()
 	_, err := LoadRigSettings("/nonexistent/path.json")
@@ -3776,6 +3874,57 @@ func TestEscalationConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max_reescalations must be non-negative",
 		},
+		{
+			name: "valid quiet hours and on-call",
+			config: &EscalationConfig{
+				Type:       "escalation",
+				Version:    1,
+				Timezone:   "UTC",
+				QuietHours: &EscalationQuietHours{Start: "22:00", End: "07:00", MinSeverity: "high"},
+				OnCall:     []EscalationOnCall{{Name: "alice", Start: "09:00", End: "17:00", Days: []string{"mon", "Tue"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid quiet hours clock",
+			config: &EscalationConfig{
+				Type:       "escalation",
+				Version:    1,
+				QuietHours: &EscalationQuietHours{Start: "10pm", End: "07:00"},
+			},
+			wantErr: true,
+			errMsg:  "invalid quiet_hours.start",
+		},
+		{
+			name: "on-call unknown day",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				OnCall:  []EscalationOnCall{{Name: "bob", Start: "00:00", End: "00:00", Days: []string{"funday"}}},
+			},
+			wantErr: true,
+			errMsg:  "unknown day",
+		},
+		{
+			name: "on-call missing name",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				OnCall:  []EscalationOnCall{{Start: "00:00", End: "00:00"}},
+			},
+			wantErr: true,
+			errMsg:  "on_call[0] requires name",
+		},
+		{
+			name: "unknown timezone",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Timezone: "Mars/Olympus",
+			},
+			wantErr: true,
+			errMsg:  "invalid timezone",
+		},
 	}
 
 	for _, tt := range tests {
@@ -3878,6 +4027,78 @@ func TestEscalationConfigGetRouteForSeverity(t *testing.T) {
 	}
 }
 
+func TestEscalationQuietHours(t *testing.T) {
+	cfg := &EscalationConfig{
+		Timezone:   "UTC",
+		QuietHours: &EscalationQuietHours{Start: "22:00", End: "07:00", Days: []string{"fri"}},
+	}
+	tests := []struct {
+		at   string
+		want bool
+	}{
+		{"2026-10-16T21:59:00Z", false}, // Friday, before the window
+		{"2026-10-16T22:00:00Z", true},  // Friday night
+		{"2026-10-17T06:59:00Z", true},  // Saturday morning, tail of Friday's window
+		{"2026-10-17T07:00:00Z", false}, // window closed
+		{"2026-10-17T23:00:00Z", false}, // Saturday night is not listed
+		{"2026-10-16T03:00:00Z", false}, // tail of Thursday's window
+	}
+	for _, tt := range tests {
+		at, _ := time.Parse(time.RFC3339, tt.at)
+		if got := cfg.InQuietHours(at); got != tt.want {
+			t.Errorf("InQuietHours(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+
+	night, _ := time.Parse(time.RFC3339, "2026-10-16T23:00:00Z")
+	if !cfg.HoldForQuietHours(SeverityHigh, night) {
+		t.Error("high re-escalation should be held during quiet hours")
+	}
+	if cfg.HoldForQuietHours(SeverityCritical, night) {
+		t.Error("critical re-escalation should go out during quiet hours by default")
+	}
+	cfg.QuietHours.MinSeverity = SeverityHigh
+	if cfg.HoldForQuietHours(SeverityHigh, night) {
+		t.Error("high re-escalation should go out when min_severity is high")
+	}
+	if (&EscalationConfig{}).HoldForQuietHours(SeverityLow, night) {
+		t.Error("nothing should be held without quiet hours")
+	}
+}
+
+func TestEscalationOnCall(t *testing.T) {
+	cfg := &EscalationConfig{
+		Timezone: "UTC",
+		Contacts: EscalationContacts{HumanEmail: "team@example.com", HumanSMS: "+15550000"},
+		OnCall: []EscalationOnCall{
+			{Name: "day", Start: "09:00", End: "18:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}},
+			{Name: "night", Start: "18:00", End: "09:00", Contacts: EscalationContacts{HumanSMS: "+15551111"}},
+		},
+	}
+
+	day, _ := time.Parse(time.RFC3339, "2026-10-16T10:00:00Z")
+	if w := cfg.OnCallAt(day); w == nil || w.Name != "day" {
+		t.Errorf("OnCallAt(Friday 10:00) = %+v, want day", w)
+	}
+	if got := cfg.ContactsAt(day); got != cfg.Contacts {
+		t.Errorf("ContactsAt(day) = %+v, want town contacts", got)
+	}
+
+	night, _ := time.Parse(time.RFC3339, "2026-10-17T02:00:00Z")
+	if w := cfg.OnCallAt(night); w == nil || w.Name != "night" {
+		t.Errorf("OnCallAt(Saturday 02:00) = %+v, want night", w)
+	}
+	got := cfg.ContactsAt(night)
+	if got.HumanSMS != "+15551111" || got.HumanEmail != "team@example.com" {
+		t.Errorf("ContactsAt(night) = %+v, want on-call SMS over town email", got)
+	}
+
+	weekend, _ := time.Parse(time.RFC3339, "2026-10-17T12:00:00Z")
+	if w := cfg.OnCallAt(weekend); w != nil {
+		t.Errorf("OnCallAt(Saturday noon) = %+v, want nil", w)
+	}
+}
+
 func TestEscalationConfigGetMaxReescalations(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/BurntSushi/toml"
+)
+
+// MailRulesConfig is settings/mail-rules.toml: declarative filters applied
+// to direct mail as it is delivered. Rules run in file order; every matching
+// rule applies its actions unless an earlier match set stop.
+//
+//	[[rule]]
+//	name   = "mayor-witness-reports"
+//	to     = ["mayor/"]
+//	from   = ["*/witness"]
+//	digest = true
+type MailRulesConfig struct {
+	Rules []MailRule `toml:"rule"`
+}
+
+// MailRule matches messages by recipient, sender and content, and says what
+// to do with them. Empty match fields match anything; at least one action is
+// required.
+type MailRule struct {
+	// Name identifies the rule in labels and `gt mail rules test` output.
+	Name string `toml:"name"`
+
+	// To lists recipient address patterns ("*/witness", "mayor/", "gastown/*").
+	// '*' matches one path segment; a lone "*" matches every address.
+	To []string `toml:"to,omitempty"`
+
+	// Roles lists recipient roles: mayor, deacon, overseer, witness,
+	// refinery, dog, or worker (polecats and crew).
+	Roles []string `toml:"roles,omitempty"`
+
+	// From lists sender address patterns, as for To.
+	From []string `toml:"from,omitempty"`
+
+	// Subject and Body are regular expressions matched against the message.
+	Subject string `toml:"subject,omitempty"`
+	Body    string `toml:"body,omitempty"`
+
+	// Types lists message types (task, scavenge, notification, reply).
+	Types []string `toml:"types,omitempty"`
+
+	// Archive files the message as read on arrival.
+	Archive bool `toml:"archive,omitempty"`
+
+	// Labels are added to the message.
+	Labels []string `toml:"labels,omitempty"`
+
+	// Priority sets the priority (urgent, high, normal, low), or moves it
+	// one step with "raise" or "lower".
+	Priority string `toml:"priority,omitempty"`
+
+	// Delivery overrides how the recipient is told: "interrupt" nudges the
+	// session immediately, "queue" waits for the next turn boundary.
+	Delivery string `toml:"delivery,omitempty"`
+
+	// Forward sends a copy to each address (the original is still delivered
+	// unless Archive is set).
+	Forward []string `toml:"forward,omitempty"`
+
+	// Digest delivers matching messages without a nudge and lists them in
+	// one open digest per recipient, which is announced once when started.
+	Digest bool `toml:"digest,omitempty"`
+
+	// Stop skips the rules after this one when it matches.
+	Stop bool `toml:"stop,omitempty"`
+
+	// subjectRe and bodyRe are Subject and Body as compiled by Validate.
+	subjectRe *regexp.Regexp
+	bodyRe    *regexp.Regexp
+}
+
+// MatchSubject reports whether subject matches the rule's subject pattern.
+// An empty pattern matches anything; an invalid one matches nothing.
+func (r *MailRule) MatchSubject(subject string) bool {
+	return matchRulePattern(r.subjectRe, r.Subject, subject)
+}
+
+// MatchBody reports whether body matches the rule's body pattern.
+// An empty pattern matches anything; an invalid one matches nothing.
+func (r *MailRule) MatchBody(body string) bool {
+	return matchRulePattern(r.bodyRe, r.Body, body)
+}
+
+// matchRulePattern matches s against re, compiling expr when the rule was
+// not validated (a loaded config is always validated, so this is rare).
+func matchRulePattern(re *regexp.Regexp, expr, s string) bool {
+	if expr == "" {
+		return true
+	}
+	if re == nil {
+		var err error
+		if re, err = regexp.Compile(expr); err != nil {
+			return false
+		}
+	}
+	return re.MatchString(s)
+}
+
+// mailRuleRoles are the recipient roles a rule may name.
+var mailRuleRoles = map[string]bool{
+	"mayor": true, "deacon": true, "overseer": true, "witness": true,
+	"refinery": true, "dog": true, "worker": true,
+}
+
+// mailRulePriorities are the accepted values of MailRule.Priority.
+var mailRulePriorities = map[string]bool{
+	"urgent": true, "high": true, "normal": true, "low": true, "raise": true, "lower": true,
+}
+
+// MailRulesPath returns the standard path for mail filter rules in a town.
+func MailRulesPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "mail-rules.toml")
+}
+
+// LoadMailRules loads and validates mail filter rules.
+// Returns an error wrapping ErrNotFound if the file does not exist.
+func LoadMailRules(path string) (*MailRulesConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading mail rules: %w", err)
+	}
+
+	var cfg MailRulesConfig
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing mail rules: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that every rule is named, uniquely, has an action, and
+// uses known roles, priorities and delivery modes. It keeps the compiled
+// subject and body patterns for matching.
+func (c *MailRulesConfig) Validate() error {
+	seen := make(map[string]bool)
+	var errs []error
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		if r.Name == "" {
+			errs = append(errs, fmt.Errorf("%w: rule %d: name", ErrMissingField, i+1))
+			continue
+		}
+		if seen[r.Name] {
+			errs = append(errs, fmt.Errorf("rule %q: duplicate name", r.Name))
+		}
+		seen[r.Name] = true
+
+		if !r.Archive && len(r.Labels) == 0 && r.Priority == "" && r.Delivery == "" &&
+			len(r.Forward) == 0 && !r.Digest {
+			errs = append(errs, fmt.Errorf("rule %q: no action (archive, labels, priority, delivery, forward or digest)", r.Name))
+		}
+		for _, role := range r.Roles {
+			if !mailRuleRoles[role] {
+				errs = append(errs, fmt.Errorf("rule %q: unknown role %q", r.Name, role))
+			}
+		}
+		if r.Priority != "" && !mailRulePriorities[r.Priority] {
+			errs = append(errs, fmt.Errorf("rule %q: invalid priority %q (urgent, high, normal, low, raise or lower)", r.Name, r.Priority))
+		}
+		if r.Delivery != "" && r.Delivery != "queue" && r.Delivery != "interrupt" {
+			errs = append(errs, fmt.Errorf("rule %q: invalid delivery %q (queue or interrupt)", r.Name, r.Delivery))
+		}
+		var err error
+		if r.subjectRe, err = regexp.Compile(r.Subject); err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: invalid subject pattern: %w", r.Name, err))
+		}
+		if r.bodyRe, err = regexp.Compile(r.Body); err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: invalid body pattern: %w", r.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
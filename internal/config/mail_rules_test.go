@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeMailRules(t *testing.T, content string) string {
+	t.Helper()
+	path := MailRulesPath(t.TempDir())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMailRules(t *testing.T) {
+	path := writeMailRules(t, `
+[[rule]]
+name    = "witness-status"
+roles   = ["witness"]
+from    = ["*/polecats/*"]
+subject = "^STATUS"
+digest  = true
+
+[[rule]]
+name     = "escalations"
+to       = ["mayor/"]
+priority = "raise"
+delivery = "interrupt"
+forward  = ["overseer"]
+stop     = true
+`)
+	cfg, err := LoadMailRules(path)
+	if err != nil {
+		t.Fatalf("LoadMailRules: %v", err)
+	}
+	if len(cfg.Rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(cfg.Rules))
+	}
+	r := cfg.Rules[0]
+	if r.Name != "witness-status" || !r.Digest || r.Roles[0] != "witness" || r.From[0] != "*/polecats/*" {
+		t.Errorf("rule 0 = %+v", r)
+	}
+	r = cfg.Rules[1]
+	if r.Priority != "raise" || r.Delivery != "interrupt" || r.Forward[0] != "overseer" || !r.Stop {
+		t.Errorf("rule 1 = %+v", r)
+	}
+}
+
+func TestLoadMailRules_NotFound(t *testing.T) {
+	_, err := LoadMailRules(MailRulesPath(t.TempDir()))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLoadMailRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"syntax", `[[rule]` + "\n", "parsing mail rules"},
+		{"no name", "[[rule]]\narchive = true\n", "name"},
+		{"duplicate", "[[rule]]\nname = \"a\"\narchive = true\n[[rule]]\nname = \"a\"\narchive = true\n", "duplicate"},
+		{"no action", "[[rule]]\nname = \"a\"\nto = [\"mayor/\"]\n", "no action"},
+		{"role", "[[rule]]\nname = \"a\"\nroles = [\"polecat\"]\narchive = true\n", "unknown role"},
+		{"priority", "[[rule]]\nname = \"a\"\npriority = \"p0\"\n", "invalid priority"},
+		{"delivery", "[[rule]]\nname = \"a\"\ndelivery = \"email\"\n", "invalid delivery"},
+		{"regexp", "[[rule]]\nname = \"a\"\nsubject = \"(\"\narchive = true\n", "invalid subject pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMailRules(writeMailRules(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
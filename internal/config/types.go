@@ -3,9 +3,11 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -91,6 +93,12 @@ type TownSettings struct {
 
 	// Scheduler configures the capacity scheduler for polecat dispatch.
 	Scheduler *capacity.SchedulerConfig `json:"scheduler,omitempty"`
+
+	// Costs configures model pricing and spend budgets for gt costs.
+	Costs *CostsConfig `json:"costs,omitempty"`
+
+	// Quota configures automatic account rotation and provider failover.
+	Quota *QuotaConfig `json:"quota,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -104,6 +112,25 @@ func NewTownSettings() *TownSettings {
 	}
 }
 
+// DefaultAgentEmailDomain is the default domain for agent git emails.
+const DefaultAgentEmailDomain = "gastown.local"
+
+// AgentEmail converts a Gas Town identity to a git email address:
+// "gastown/crew/jack" → "gastown.crew.jack@{domain}".
+func AgentEmail(identity, domain string) string {
+	identity = strings.TrimSuffix(identity, "/")
+	return strings.ReplaceAll(identity, "/", ".") + "@" + domain
+}
+
+// GetAgentEmailDomain returns the agent email domain (default "gastown.local").
+// Nil-safe.
+func (s *TownSettings) GetAgentEmailDomain() string {
+	if s == nil || s.AgentEmailDomain == "" {
+		return DefaultAgentEmailDomain
+	}
+	return s.AgentEmailDomain
+}
+
 // WebTimeoutsConfig configures command execution timeouts for the web dashboard.
 type WebTimeoutsConfig struct {
 	// CmdTimeout is the timeout for bd (beads) commands. Default: "15s".
@@ -187,6 +214,105 @@ type ConvoyConfig struct {
 	NotifyOnComplete bool `json:"notify_on_complete,omitempty"`
 }
 
+// CostsConfig configures cost accounting: model pricing and spend budgets.
+type CostsConfig struct {
+	// Pricing overrides or extends the built-in model price table.
+	// Keys are model names or model name prefixes (e.g., "gpt-5", "claude-sonnet-4");
+	// the longest matching key wins.
+	Pricing map[string]*ModelPricing `json:"pricing,omitempty"`
+
+	// Budgets sets spend limits enforced by the scheduler and gt sling.
+	Budgets *BudgetConfig `json:"budgets,omitempty"`
+}
+
+// ModelPricing is the USD price per million tokens for a model.
+type ModelPricing struct {
+	InputPerMillion      float64 `json:"input_per_million"`
+	OutputPerMillion     float64 `json:"output_per_million"`
+	CacheReadPerMillion  float64 `json:"cache_read_per_million,omitempty"`
+	CacheWritePerMillion float64 `json:"cache_write_per_million,omitempty"`
+}
+
+// Budget actions taken when a budget is exhausted.
+const (
+	// BudgetActionWarn only warns; dispatch continues.
+	BudgetActionWarn = "warn"
+	// BudgetActionPause holds back dispatch of work charged to the budget.
+	BudgetActionPause = "pause"
+	// BudgetActionEscalate pauses dispatch and raises an escalation.
+	BudgetActionEscalate = "escalate"
+)
+
+// DefaultBudgetWarnPercent is the share of a budget at which warnings start.
+const DefaultBudgetWarnPercent = 80
+
+// BudgetConfig sets spend limits in USD. Zero means no limit.
+type BudgetConfig struct {
+	// DailyUSD limits town-wide spend per calendar day.
+	DailyUSD float64 `json:"daily_usd,omitempty"`
+
+	// RigDailyUSD limits each rig's spend per calendar day.
+	RigDailyUSD float64 `json:"rig_daily_usd,omitempty"`
+
+	// Rigs overrides RigDailyUSD for specific rigs. A negative value means unlimited.
+	Rigs map[string]float64 `json:"rigs,omitempty"`
+
+	// ConvoyUSD limits the total spend of each convoy.
+	ConvoyUSD float64 `json:"convoy_usd,omitempty"`
+
+	// WarnPercent is the share of a budget at which warnings start. Default: 80.
+	WarnPercent int `json:"warn_percent,omitempty"`
+
+	// Action is what happens when a budget is exhausted: "warn", "pause" or "escalate".
+	// Default: "pause".
+	Action string `json:"action,omitempty"`
+}
+
+// GetRigDailyUSD returns the daily limit for a rig, or 0 if unlimited.
+func (c *BudgetConfig) GetRigDailyUSD(rig string) float64 {
+	if c == nil {
+		return 0
+	}
+	if v, ok := c.Rigs[rig]; ok {
+		if v < 0 {
+			return 0
+		}
+		return v
+	}
+	return c.RigDailyUSD
+}
+
+// GetRigOverride returns the per-rig limit set for rig, if any
+// (-1 means unlimited).
+func (c *BudgetConfig) GetRigOverride(rig string) (float64, bool) {
+	if c == nil {
+		return 0, false
+	}
+	v, ok := c.Rigs[rig]
+	return v, ok
+}
+
+// GetWarnPercent returns the warning threshold percentage.
+func (c *BudgetConfig) GetWarnPercent() int {
+	if c == nil || c.WarnPercent <= 0 || c.WarnPercent > 100 {
+		return DefaultBudgetWarnPercent
+	}
+	return c.WarnPercent
+}
+
+// GetAction returns the budget action, defaulting to pause.
+func (c *BudgetConfig) GetAction() string {
+	if c == nil {
+		return BudgetActionPause
+	}
+	switch c.Action {
+	case BudgetActionWarn, BudgetActionEscalate:
+		return c.Action
+	default:
+		return BudgetActionPause
+	}
+}
+
 // ParseDurationOrDefault parses a Go duration string, returning fallback on error or empty input.
 func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 	if s == "" {
@@ -203,6 +329,7 @@ func ParseDurationOrDefault(s string, fallback time.Duration) time.Duration {
 type DaemonConfig struct {
 	HeartbeatInterval string `json:"heartbeat_interval,omitempty"` // e.g., "30s"
 	PollInterval      string `json:"poll_interval,omitempty"`      // e.g., "10s"
+	MetricsAddr       string `json:"metrics_addr,omitempty"`       // Prometheus /metrics listen address, e.g., "127.0.0.1:9464" (empty = disabled)
 }
 
 // DaemonPatrolConfig represents the daemon patrol configuration (mayor/daemon.json).
@@ -357,6 +484,99 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Sandbox runs this rig's polecats in an isolated sandbox with resource
+	// limits. Nil (or disabled) runs polecats as plain tmux processes.
+	Sandbox *SandboxConfig `json:"sandbox,omitempty"`
+
+	// Routing advertises what work this rig takes when the dispatcher picks
+	// the rig itself (gt sling --auto). Nil means the rig is only chosen on
+	// its track record.
+	Routing *RoutingConfig `json:"routing,omitempty"`
+}
+
+// RoutingConfig describes which beads a rig should receive under automatic
+// routing, and which agent presets may work them.
+type RoutingConfig struct {
+	// Capabilities are bead labels this rig handles (e.g., "frontend", "go").
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// Paths are repository path globs this rig owns (e.g., "web/**", "*.proto").
+	// They are matched against the files touched by the bead's linked beads.
+	Paths []string `json:"paths,omitempty"`
+
+	// Agents lists the agent presets the router may choose between for this
+	// rig's polecats. Empty means the rig's configured polecat agent.
+	Agents []string `json:"agents,omitempty"`
+
+	// Exclude keeps the rig out of automatic routing entirely.
+	Exclude bool `json:"exclude,omitempty"`
+}
+
+// SandboxConfig configures sandboxed polecat execution for a rig.
+//
+// Polecats run under bubblewrap (bwrap) with user, mount, PID, IPC and UTS
+// namespaces. The filesystem is mounted read-only except for the polecat's
+// worktree, the rig's shared .repo.git, the town state gt and bd write to,
+// and the Writable paths. Resource limits are applied with a transient
+// systemd scope (cgroup v2), so a runaway process only exhausts its own
+// polecat's budget.
+type SandboxConfig struct {
+	// Enabled turns on the sandbox for polecat sessions.
+	Enabled bool `json:"enabled"`
+
+	// CPUs caps CPU time in cores (e.g., 2 or 0.5). Zero means no limit.
+	CPUs float64 `json:"cpus,omitempty"`
+
+	// Memory caps memory use, e.g. "4G" or "512M". Swap is disabled when set.
+	// Empty means no limit.
+	Memory string `json:"memory,omitempty"`
+
+	// Pids caps the number of processes and threads (fork bomb protection).
+	// Zero means no limit.
+	Pids int `json:"pids,omitempty"`
+
+	// Writable lists extra paths the agent may write to. "~/" expands to the
+	// home directory; paths that do not exist are skipped.
+	// Nil defaults to DefaultSandboxWritable. Empty array [] means none.
+	Writable []string `json:"writable"`
+}
+
+// DefaultSandboxWritable are the home directory paths agent runtimes keep
+// their session state in.
+var DefaultSandboxWritable = []string{"~/.claude", "~/.claude.json", "~/.cache", "~/.config/gastown"}
+
+// HasLimits reports whether any resource limit is configured.
+func (c *SandboxConfig) HasLimits() bool {
+	return c.CPUs > 0 || c.Memory != "" || c.Pids > 0
+}
+
+// WritablePaths returns the configured extra writable paths.
+func (c *SandboxConfig) WritablePaths() []string {
+	if c.Writable == nil {
+		return DefaultSandboxWritable
+	}
+	return c.Writable
+}
+
+// MemoryBytes returns the memory limit in bytes, or 0 if unset.
+// Accepts a byte count with an optional K, M, G or T suffix (powers of 1024).
+func (c *SandboxConfig) MemoryBytes() (int64, error) {
+	s := strings.TrimSpace(c.Memory)
+	if s == "" {
+		return 0, nil
+	}
+	mult := float64(1)
+	switch suffix := strings.ToUpper(s[len(s)-1:]); suffix {
+	case "K", "M", "G", "T":
+		mult = math.Pow(1024, float64(strings.Index("KMGT", suffix)+1))
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid sandbox memory %q (want e.g. 512M or 4G)", c.Memory)
+	}
+	return int64(n * mult), nil
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -854,6 +1074,25 @@ type MergeQueueConfig struct {
 	// OnConflict specifies conflict resolution strategy: "assign_back" or "auto_rebase".
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy controls how the refinery lands a branch on its target:
+	// "squash" (default), "merge-no-ff", "rebase-ff" or "ff-only".
+	MergeStrategy string `json:"merge_strategy,omitempty"`
+
+	// CommitTemplate is a Go text/template for the commit message of squash
+	// and merge-no-ff merges. Fields: .BeadID, .Title, .Worker, .Convoy,
+	// .MRID, .Branch, .Target and .Message (the branch's last commit message).
+	// Empty keeps the branch's commit message.
+	CommitTemplate string `json:"commit_template,omitempty"`
+
+	// CommitTrailers lists trailers appended to squash and merge-no-ff
+	// commit messages: "bead" (Bead: <id>) and "co-authored-by" (the worker).
+	CommitTrailers []string `json:"commit_trailers,omitempty"`
+
+	// Forge switches the refinery to forge mode: it lands work by opening and
+	// merging pull requests instead of pushing to the target branch. Nil
+	// keeps direct pushes.
+	Forge *ForgeConfig `json:"forge,omitempty"`
+
 	// RunTests controls whether to run tests before merging.
 	// Nil defaults to true (tests are run).
 	RunTests *bool `json:"run_tests,omitempty"`
@@ -897,6 +1136,96 @@ const (
 	OnConflictAutoRebase = "auto_rebase"
 )
 
+// MergeStrategy constants.
+const (
+	MergeStrategySquash    = "squash"
+	MergeStrategyMergeNoFF = "merge-no-ff"
+	MergeStrategyRebaseFF  = "rebase-ff"
+	MergeStrategyFFOnly    = "ff-only"
+)
+
+// MergeStrategies lists the valid merge_strategy values.
+var MergeStrategies = []string{MergeStrategySquash, MergeStrategyMergeNoFF, MergeStrategyRebaseFF, MergeStrategyFFOnly}
+
+// ForgeConfig configures forge mode for a rig's merge queue. The refinery
+// pushes the polecat branch, opens (or updates) a pull request, waits for
+// status checks and merges through the forge's REST API, so rigs with
+// protected branches can use the merge queue.
+type ForgeConfig struct {
+	// Type is the forge: "github", "gitlab" or "gitea".
+	Type string `json:"type"`
+
+	// URL is the REST API base URL. Defaults to https://api.github.com or
+	// https://gitlab.com/api/v4; required for gitea
+	// (e.g., "https://gitea.example.com/api/v1").
+	URL string `json:"url,omitempty"`
+
+	// Repo is the repository as "owner/name" (GitLab: the full project
+	// path). Empty derives it from the origin remote URL.
+	Repo string `json:"repo,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token.
+	// Default: GITHUB_TOKEN, GITLAB_TOKEN or GITEA_TOKEN.
+	TokenEnv string `json:"token_env,omitempty"`
+
+	// RequiredChecks names the status checks that gate the merge (required).
+	// A listed check that has not reported yet counts as pending, so a PR
+	// is never merged before its CI has started.
+	RequiredChecks []string `json:"required_checks"`
+}
+
+// Forge type constants.
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+	ForgeGitea  = "gitea"
+)
+
+// Validate checks the forge type, URL and required checks.
+func (c *ForgeConfig) Validate() error {
+	switch c.Type {
+	case ForgeGitHub, ForgeGitLab:
+	case ForgeGitea:
+		if c.URL == "" {
+			return fmt.Errorf("merge_queue.forge: url is required for gitea")
+		}
+	default:
+		return fmt.Errorf("merge_queue.forge: invalid type %q (want %s, %s or %s)", c.Type, ForgeGitHub, ForgeGitLab, ForgeGitea)
+	}
+	if len(c.RequiredChecks) == 0 {
+		return fmt.Errorf("merge_queue.forge: required_checks is required (the checks that must pass before merging)")
+	}
+	return nil
+}
+
+// GetURL returns the REST API base URL, applying the per-type default.
+func (c *ForgeConfig) GetURL() string {
+	if c.URL != "" {
+		return strings.TrimSuffix(c.URL, "/")
+	}
+	switch c.Type {
+	case ForgeGitHub:
+		return "https://api.github.com"
+	case ForgeGitLab:
+		return "https://gitlab.com/api/v4"
+	}
+	return ""
+}
+
+// GetTokenEnv returns the environment variable holding the API token.
+func (c *ForgeConfig) GetTokenEnv() string {
+	if c.TokenEnv != "" {
+		return c.TokenEnv
+	}
+	return strings.ToUpper(c.Type) + "_TOKEN"
+}
+
+// CommitTrailer constants for merge_queue.commit_trailers.
+const (
+	CommitTrailerBead         = "bead"
+	CommitTrailerCoAuthoredBy = "co-authored-by"
+)
+
 // IsPolecatIntegrationEnabled returns whether polecat integration branch
 // sourcing is enabled. Nil-safe, defaults to true.
 func (c *MergeQueueConfig) IsPolecatIntegrationEnabled() bool {
@@ -1047,6 +1376,45 @@ type AccountQuotaState struct {
 // CurrentQuotaVersion is the current schema version for QuotaState.
 const CurrentQuotaVersion = 1
 
+// DefaultQuotaResetCooldown is how long an account stays limited when the
+// provider's reset time cannot be parsed (Claude usage windows are 5 hours).
+const DefaultQuotaResetCooldown = 5 * time.Hour
+
+// QuotaConfig configures automatic quota rotation (gt quota rotate --auto).
+type QuotaConfig struct {
+	// Fallback maps an agent preset to the preset its sessions fail over to
+	// when every account is rate-limited, e.g. {"claude": "gemini"}.
+	Fallback map[string]string `json:"fallback,omitempty"`
+
+	// ResetCooldown is how long a limited account stays limited when its
+	// reset time cannot be parsed (Go duration, default "5h").
+	ResetCooldown string `json:"reset_cooldown,omitempty"`
+}
+
+// GetResetCooldown returns ResetCooldown, or DefaultQuotaResetCooldown if
+// unset or invalid.
+func (c *QuotaConfig) GetResetCooldown() time.Duration {
+	if c == nil || c.ResetCooldown == "" {
+		return DefaultQuotaResetCooldown
+	}
+	d, err := time.ParseDuration(c.ResetCooldown)
+	if err != nil || d <= 0 {
+		return DefaultQuotaResetCooldown
+	}
+	return d
+}
+
+// FallbackFor returns the failover preset for agent, or "" if none.
+func (c *QuotaConfig) FallbackFor(agent string) string {
+	if c == nil {
+		return ""
+	}
+	if fb := c.Fallback[agent]; fb != agent {
+		return fb
+	}
+	return ""
+}
+
 // MessagingConfig represents the messaging configuration (config/messaging.json).
 // This defines mailing lists, work queues, and announcement channels.
 type MessagingConfig struct {
@@ -1123,12 +1491,19 @@ type EscalationConfig struct {
 	//   - "email:human" → Send email to contacts.human_email
 	//   - "sms:human"   → Send SMS to contacts.human_sms
 	//   - "slack"       → Post to contacts.slack_webhook
+	//   - "webhook"     → POST JSON to contacts.webhook_url
 	//   - "log"         → Write to escalation log file
 	Routes map[string][]string `json:"routes"`
 
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// Channels configures the transports used by external notification
+	// actions (SMTP server, SMS gateway, webhook retry policy, log file).
+	// Nil means defaults: email/sms are skipped, webhooks use the default
+	// retry policy, and the log goes to <townRoot>/logs/escalations.log.
+	Channels *EscalationChannels `json:"channels,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -1139,6 +1514,38 @@ type EscalationConfig struct {
 	// re-escalated. Default: 2 (low→medium→high, then stops)
 	// Pointer type to distinguish "not configured" (nil) from explicit 0.
 	MaxReescalations *int `json:"max_reescalations,omitempty"`
+
+	// Timezone is the IANA zone (e.g., "America/Los_Angeles") that
+	// quiet_hours and on_call windows are written in. Default: local time.
+	Timezone string `json:"timezone,omitempty"`
+
+	// QuietHours holds back automatic re-escalations below a severity
+	// during a daily window. Nil means re-escalations go out at any hour.
+	QuietHours *EscalationQuietHours `json:"quiet_hours,omitempty"`
+
+	// OnCall lists on-call rotation windows. The first window covering the
+	// current time overrides Contacts and adds its mail targets to routes.
+	OnCall []EscalationOnCall `json:"on_call,omitempty"`
+}
+
+// EscalationQuietHours is a daily window during which the escalation tracker
+// holds re-escalations to severities below MinSeverity. Held escalations go
+// out on the first tracker pass after the window closes.
+type EscalationQuietHours struct {
+	Start       string   `json:"start"`                  // window start, "HH:MM"
+	End         string   `json:"end"`                    // window end, "HH:MM" (may wrap past midnight)
+	Days        []string `json:"days,omitempty"`         // days the window starts on ("mon".."sun"); empty = every day
+	MinSeverity string   `json:"min_severity,omitempty"` // lowest severity still delivered (default "critical")
+}
+
+// EscalationOnCall is one on-call rotation window.
+type EscalationOnCall struct {
+	Name     string             `json:"name"`               // who is on call (recorded on each hop)
+	Start    string             `json:"start"`              // window start, "HH:MM"
+	End      string             `json:"end"`                // window end, "HH:MM" (may wrap past midnight)
+	Days     []string           `json:"days,omitempty"`     // days the window starts on; empty = every day
+	Mail     []string           `json:"mail,omitempty"`     // gt mail targets paged in addition to the route's mail: actions
+	Contacts EscalationContacts `json:"contacts,omitempty"` // non-empty fields override the town contacts
 }
 
 // EscalationContacts contains contact information for external notification channels.
@@ -1146,6 +1553,46 @@ type EscalationContacts struct {
 	HumanEmail   string `json:"human_email,omitempty"`   // email address for email:human action
 	HumanSMS     string `json:"human_sms,omitempty"`     // phone number for sms:human action
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
+	WebhookURL   string `json:"webhook_url,omitempty"`   // URL for generic webhook action
+}
+
+// EscalationChannels configures transports for external escalation actions.
+type EscalationChannels struct {
+	SMTP    *EscalationSMTPConfig    `json:"smtp,omitempty"`
+	SMS     *EscalationSMSConfig     `json:"sms,omitempty"`
+	Webhook *EscalationWebhookConfig `json:"webhook,omitempty"`
+	Log     *EscalationLogConfig     `json:"log,omitempty"`
+}
+
+// EscalationSMTPConfig configures the SMTP server used by email:<target> actions.
+type EscalationSMTPConfig struct {
+	Host        string `json:"host"`                   // SMTP server host
+	Port        int    `json:"port,omitempty"`         // SMTP server port (default 587)
+	From        string `json:"from"`                   // envelope and header sender address
+	Username    string `json:"username,omitempty"`     // PLAIN auth username (empty = no auth)
+	PasswordEnv string `json:"password_env,omitempty"` // env var holding the auth password
+}
+
+// EscalationSMSConfig configures the HTTP SMS gateway used by sms:<target> actions.
+// The gateway receives a JSON POST of {"to": "<number>", "message": "<text>"}.
+type EscalationSMSConfig struct {
+	URL      string `json:"url"`                 // gateway endpoint
+	TokenEnv string `json:"token_env,omitempty"` // env var holding a bearer token
+}
+
+// EscalationWebhookConfig configures retry behavior for webhook-style
+// deliveries (slack, webhook, sms).
+type EscalationWebhookConfig struct {
+	MaxAttempts    int    `json:"max_attempts,omitempty"`    // total attempts including the first (default 3)
+	InitialBackoff string `json:"initial_backoff,omitempty"` // delay before first retry, doubled each time (default "1s")
+	Timeout        string `json:"timeout,omitempty"`         // per-request timeout (default "10s")
+}
+
+// EscalationLogConfig configures the append-only escalation log written by the log action.
+type EscalationLogConfig struct {
+	Path       string `json:"path,omitempty"`        // log file path (default <townRoot>/logs/escalations.log)
+	MaxSizeMB  int    `json:"max_size_mb,omitempty"` // rotate when the file exceeds this size (default 10)
+	MaxBackups int    `json:"max_backups,omitempty"` // rotated files to keep (default 5)
 }
 
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
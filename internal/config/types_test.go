@@ -582,3 +582,36 @@ func TestParseDurationOrDefault_AllWebTimeoutDefaults(t *testing.T) {
 }
 
 
+
+func TestBudgetConfig_Getters(t *testing.T) {
+	t.Parallel()
+	var nilCfg *BudgetConfig
+	if nilCfg.GetRigDailyUSD("gastown") != 0 || nilCfg.GetWarnPercent() != DefaultBudgetWarnPercent || nilCfg.GetAction() != BudgetActionPause {
+		t.Error("nil BudgetConfig should report no limits and defaults")
+	}
+
+	c := &BudgetConfig{
+		RigDailyUSD: 50,
+		Rigs:        map[string]float64{"big": 200, "free": -1},
+		WarnPercent: 150,
+		Action:      "explode",
+	}
+	for rig, want := range map[string]float64{"gastown": 50, "big": 200, "free": 0} {
+		if got := c.GetRigDailyUSD(rig); got != want {
+			t.Errorf("GetRigDailyUSD(%q) = %v, want %v", rig, got, want)
+		}
+	}
+	if v, ok := c.GetRigOverride("free"); !ok || v != -1 {
+		t.Errorf("GetRigOverride(free) = %v, %v; want -1, true", v, ok)
+	}
+	if got := c.GetWarnPercent(); got != DefaultBudgetWarnPercent {
+		t.Errorf("GetWarnPercent() with out-of-range value = %d, want default", got)
+	}
+	if got := c.GetAction(); got != BudgetActionPause {
+		t.Errorf("GetAction() with unknown action = %q, want pause", got)
+	}
+	c.Action = BudgetActionEscalate
+	if got := c.GetAction(); got != BudgetActionEscalate {
+		t.Errorf("GetAction() = %q, want escalate", got)
+	}
+}
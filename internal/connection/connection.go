@@ -57,6 +57,9 @@ type Connection interface {
 	ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error)
 
 	// Tmux operations
+	//
+	// Despite the names, these go to the connection's session backend, which
+	// is tmux unless a local town runs under the headless pty supervisor.
 
 	// TmuxNewSession creates a new tmux session with the given name.
 	TmuxNewSession(name, dir string) error
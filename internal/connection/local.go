@@ -6,18 +6,20 @@ import (
 	"os/exec"
 	"path/filepath"
 
-	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/session"
 )
 
 // LocalConnection implements Connection for local file and command operations.
+// Session operations go to the configured session backend (tmux, or the
+// headless pty supervisor; see session.NewBackend).
 type LocalConnection struct {
-	tmux *tmux.Tmux
+	sessions session.Backend
 }
 
 // NewLocalConnection creates a new local connection.
 func NewLocalConnection() *LocalConnection {
 	return &LocalConnection{
-		tmux: tmux.NewTmux(),
+		sessions: session.NewBackend(),
 	}
 }
 
@@ -155,35 +157,35 @@ func (c *LocalConnection) ExecEnv(env map[string]string, cmd string, args ...str
 	return command.CombinedOutput()
 }
 
-// TmuxNewSession creates a new tmux session.
+// TmuxNewSession creates a new session.
 func (c *LocalConnection) TmuxNewSession(name, dir string) error {
-	return c.tmux.NewSession(name, dir)
+	return c.sessions.NewSession(name, dir)
 }
 
-// TmuxKillSession terminates a tmux session.
+// TmuxKillSession terminates a session.
 // Uses KillSessionWithProcesses to ensure all descendant processes are killed.
 func (c *LocalConnection) TmuxKillSession(name string) error {
-	return c.tmux.KillSessionWithProcesses(name)
+	return c.sessions.KillSessionWithProcesses(name)
 }
 
-// TmuxSendKeys sends keys to a tmux session.
+// TmuxSendKeys sends keys to a session.
 func (c *LocalConnection) TmuxSendKeys(session, keys string) error {
-	return c.tmux.SendKeys(session, keys)
+	return c.sessions.SendKeys(session, keys)
 }
 
-// TmuxCapturePane captures the last N lines from a tmux pane.
+// TmuxCapturePane captures the last N lines of a session.
 func (c *LocalConnection) TmuxCapturePane(session string, lines int) (string, error) {
-	return c.tmux.CapturePane(session, lines)
+	return c.sessions.CapturePane(session, lines)
 }
 
 // TmuxHasSession returns true if the session exists.
 func (c *LocalConnection) TmuxHasSession(name string) (bool, error) {
-	return c.tmux.HasSession(name)
+	return c.sessions.HasSession(name)
 }
 
-// TmuxListSessions returns all tmux session names.
+// TmuxListSessions returns all session names.
 func (c *LocalConnection) TmuxListSessions() ([]string, error) {
-	return c.tmux.ListSessions()
+	return c.sessions.ListSessions()
 }
 
 // Verify LocalConnection implements Connection.
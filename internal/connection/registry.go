@@ -128,6 +128,9 @@ func (r *MachineRegistry) Add(m *Machine) error {
 	if m.Type == "ssh" && m.Host == "" {
 		return fmt.Errorf("ssh machine requires host")
 	}
+	if m.Name == "local" && m.Type != "local" {
+		return fmt.Errorf("machine name %q is reserved", m.Name)
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -176,8 +179,7 @@ func (r *MachineRegistry) Connection(name string) (Connection, error) {
 	case "local":
 		return NewLocalConnection(), nil
 	case "ssh":
-		// SSH connection not yet implemented
-		return nil, fmt.Errorf("ssh connections not yet implemented")
+		return NewSSHConnection(m)
 	default:
 		return nil, fmt.Errorf("unknown machine type: %s", m.Type)
 	}
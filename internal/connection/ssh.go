@@ -0,0 +1,499 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Remote exit codes used by the helper scripts to report typed errors.
+const (
+	sshExitNotFound   = 44
+	sshExitPermission = 45
+
+	// sshExitConnection is what ssh itself exits with on connection failure.
+	sshExitConnection = 255
+)
+
+// DefaultSSHControlPersist is how long the multiplexed master connection
+// stays open after the last command finishes.
+const DefaultSSHControlPersist = 10 * time.Minute
+
+// DefaultSSHConnectTimeout bounds how long ssh waits to establish a connection.
+const DefaultSSHConnectTimeout = 10 * time.Second
+
+// SSHConnection implements Connection for a remote machine using the system
+// ssh binary. Commands are multiplexed over a single ControlMaster connection
+// so each operation costs a round trip, not a full handshake.
+//
+// File operations are implemented with small POSIX shell scripts on the remote
+// side, so the remote only needs sh, coreutils and tmux.
+type SSHConnection struct {
+	machine *Machine
+
+	host string
+	port string
+
+	// sshPath is the ssh binary to run (overridable for tests).
+	sshPath string
+
+	// controlDir holds the ControlMaster sockets.
+	controlDir string
+}
+
+// NewSSHConnection creates a connection to the given ssh machine.
+// The machine's Host is "user@host" with an optional ":port" suffix.
+func NewSSHConnection(m *Machine) (*SSHConnection, error) {
+	if m.Host == "" {
+		return nil, fmt.Errorf("ssh machine %s requires host", m.Name)
+	}
+	host, port := splitHostPort(m.Host)
+	return &SSHConnection{
+		machine:    m,
+		host:       host,
+		port:       port,
+		sshPath:    "ssh",
+		controlDir: filepath.Join(os.TempDir(), fmt.Sprintf("gt-ssh-%d", os.Getuid())),
+	}, nil
+}
+
+// splitHostPort splits "user@host:port" into host and port. A host without
+// a numeric port suffix is returned unchanged.
+func splitHostPort(h string) (string, string) {
+	idx := strings.LastIndex(h, ":")
+	if idx < 0 || strings.Contains(h, "]") {
+		return h, ""
+	}
+	if _, err := strconv.Atoi(h[idx+1:]); err != nil {
+		return h, ""
+	}
+	return h[:idx], h[idx+1:]
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.machine.Name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// sshArgs returns the ssh arguments that precede the remote command.
+func (c *SSHConnection) sshArgs() []string {
+	args := []string{
+		"-T",
+		"-o", "BatchMode=yes",
+		"-o", fmt.Sprintf("ConnectTimeout=%d", int(DefaultSSHConnectTimeout.Seconds())),
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath=" + filepath.Join(c.controlDir, "%C"),
+		"-o", fmt.Sprintf("ControlPersist=%d", int(DefaultSSHControlPersist.Seconds())),
+	}
+	if c.machine.KeyPath != "" {
+		args = append(args, "-i", c.machine.KeyPath)
+	}
+	if c.port != "" {
+		args = append(args, "-p", c.port)
+	}
+	return append(args, c.host)
+}
+
+// command builds an ssh command that runs script on the remote host.
+func (c *SSHConnection) command(script string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(c.controlDir, 0700); err != nil {
+		return nil, &ConnectionError{Op: "connect", Machine: c.machine.Name, Err: err}
+	}
+	args := append(c.sshArgs(), script)
+	return exec.Command(c.sshPath, args...), nil //nolint:gosec // G204: args are built from registry config and quoted
+}
+
+// run executes script remotely, feeding stdin if non-nil, and returns stdout.
+// Connection failures are returned as *ConnectionError; remote failures as
+// an error carrying the remote stderr.
+func (c *SSHConnection) run(op, script string, stdin []byte) ([]byte, error) {
+	cmd, err := c.command(script)
+	if err != nil {
+		return nil, err
+	}
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.Bytes(), c.wrapError(op, err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// wrapError converts an ssh exit into the connection error types.
+func (c *SSHConnection) wrapError(op string, err error, stderr string) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() == sshExitConnection {
+		if msg := strings.TrimSpace(stderr); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return &ConnectionError{Op: op, Machine: c.machine.Name, Err: err}
+	}
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("%s on %s: %w: %s", op, c.machine.Name, err, msg)
+	}
+	return fmt.Errorf("%s on %s: %w", op, c.machine.Name, err)
+}
+
+// exitCode returns the remote exit status carried by err, or -1.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// fileError maps the helper scripts' exit codes to typed errors.
+func fileError(err error, path, op string) error {
+	switch exitCode(err) {
+	case sshExitNotFound:
+		return &NotFoundError{Path: path}
+	case sshExitPermission:
+		return &PermissionError{Path: path, Op: op}
+	}
+	return err
+}
+
+// ReadFile reads the named remote file.
+func (c *SSHConnection) ReadFile(path string) ([]byte, error) {
+	p := shellQuote(path)
+	script := fmt.Sprintf("[ -e %[1]s ] || exit %[2]d; [ -r %[1]s ] || exit %[3]d; exec cat -- %[1]s",
+		p, sshExitNotFound, sshExitPermission)
+	out, err := c.run("read", script, nil)
+	if err != nil {
+		return nil, fileError(err, path, "read")
+	}
+	return out, nil
+}
+
+// WriteFile writes data to the named remote file. As with os.WriteFile,
+// perm is only applied when the file is created.
+func (c *SSHConnection) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	p := shellQuote(path)
+	d := shellQuote(filepath.Dir(path))
+	script := fmt.Sprintf(`[ -d %[2]s ] || exit %[3]d
+if [ -e %[1]s ]; then
+  [ -w %[1]s ] || exit %[4]d
+  exec cat > %[1]s
+fi
+[ -w %[2]s ] || exit %[4]d
+umask 077 && cat > %[1]s && chmod %[5]o %[1]s`,
+		p, d, sshExitNotFound, sshExitPermission, perm.Perm())
+	if _, err := c.run("write", script, data); err != nil {
+		return fileError(err, path, "write")
+	}
+	return nil
+}
+
+// MkdirAll creates a remote directory and all parent directories.
+func (c *SSHConnection) MkdirAll(path string, perm fs.FileMode) error {
+	script := fmt.Sprintf("mkdir -p -m %o -- %s", perm.Perm(), shellQuote(path))
+	if _, err := c.run("mkdir", script, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Remove removes the named remote file or empty directory.
+// A missing path is not an error.
+func (c *SSHConnection) Remove(path string) error {
+	p := shellQuote(path)
+	script := fmt.Sprintf("if [ -d %[1]s ] && [ ! -L %[1]s ]; then rmdir -- %[1]s; elif [ -e %[1]s ] || [ -L %[1]s ]; then rm -f -- %[1]s; fi", p)
+	if _, err := c.run("remove", script, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RemoveAll removes the named remote file or directory and any children.
+func (c *SSHConnection) RemoveAll(path string) error {
+	if _, err := c.run("remove", "rm -rf -- "+shellQuote(path), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Stat returns file info for the named remote file, following symlinks.
+// Supports both GNU and BSD stat.
+func (c *SSHConnection) Stat(path string) (FileInfo, error) {
+	p := shellQuote(path)
+	script := fmt.Sprintf("[ -e %[1]s ] || exit %[2]d; stat -L -c '%%s %%f %%Y' -- %[1]s 2>/dev/null || stat -L -f '%%z %%Xp %%m' -- %[1]s",
+		p, sshExitNotFound)
+	out, err := c.run("stat", script, nil)
+	if err != nil {
+		return nil, fileError(err, path, "stat")
+	}
+	return parseStat(filepath.Base(path), string(out))
+}
+
+// parseStat parses "<size> <hex st_mode> <mtime>" as printed by the Stat script.
+func parseStat(name, out string) (FileInfo, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected stat output %q", strings.TrimSpace(out))
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat size %q: %w", fields[0], err)
+	}
+	rawMode, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat mode %q: %w", fields[1], err)
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing stat mtime %q: %w", fields[2], err)
+	}
+	mode := unixFileMode(uint32(rawMode))
+	return BasicFileInfo{
+		FileName:    name,
+		FileSize:    size,
+		FileMode:    mode,
+		FileModTime: time.Unix(mtime, 0),
+		FileIsDir:   mode.IsDir(),
+	}, nil
+}
+
+// unixFileMode converts a raw st_mode into an fs.FileMode.
+func unixFileMode(m uint32) fs.FileMode {
+	mode := fs.FileMode(m & 0777)
+	switch m & 0170000 {
+	case 0040000:
+		mode |= fs.ModeDir
+	case 0120000:
+		mode |= fs.ModeSymlink
+	case 0010000:
+		mode |= fs.ModeNamedPipe
+	case 0140000:
+		mode |= fs.ModeSocket
+	case 0020000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0060000:
+		mode |= fs.ModeDevice
+	}
+	if m&04000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if m&02000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if m&01000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+// Glob returns the names of all remote files matching the pattern.
+// The pattern is expanded by the remote shell; only *, ? and bracket
+// expressions are treated as wildcards.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	word, err := globWord(pattern)
+	if err != nil {
+		return nil, err
+	}
+	script := fmt.Sprintf(`for f in %s; do [ -e "$f" ] || [ -L "$f" ] && printf '%%s\n' "$f"; done; exit 0`, word)
+	out, err := c.run("glob", script, nil)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			matches = append(matches, line)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// globWord escapes every character of pattern except glob metacharacters so
+// it can be expanded, but not otherwise interpreted, by the remote shell.
+func globWord(pattern string) (string, error) {
+	if pattern == "" {
+		return "", fmt.Errorf("empty glob pattern")
+	}
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '\n', 0:
+			return "", fmt.Errorf("invalid character in glob pattern %q", pattern)
+		case '*', '?', '[', ']', '!', '^', '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
+
+// Exists returns true if the remote path exists.
+func (c *SSHConnection) Exists(path string) (bool, error) {
+	_, err := c.run("stat", "[ -e "+shellQuote(path)+" ]", nil)
+	if err == nil {
+		return true, nil
+	}
+	if exitCode(err) == 1 {
+		return false, nil
+	}
+	return false, err
+}
+
+// Exec runs a remote command and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.execScript("exec " + shellJoin(cmd, args...))
+}
+
+// ExecDir runs a remote command in the specified directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	return c.execScript("cd -- " + shellQuote(dir) + " && exec " + shellJoin(cmd, args...))
+}
+
+// ExecEnv runs a remote command with additional environment variables.
+// Variables are set explicitly since sshd does not forward the local environment.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	assignments := make([]string, 0, len(keys))
+	for _, k := range keys {
+		assignments = append(assignments, shellQuote(k+"="+env[k]))
+	}
+	prefix := "exec env"
+	if len(assignments) > 0 {
+		prefix += " " + strings.Join(assignments, " ")
+	}
+	return c.execScript(prefix + " " + shellJoin(cmd, args...))
+}
+
+// execScript runs script remotely and returns combined stdout/stderr, matching
+// exec.Cmd.CombinedOutput. Only ssh-level failures become ConnectionErrors;
+// a non-zero remote exit is returned as the *exec.ExitError like locally.
+func (c *SSHConnection) execScript(script string) ([]byte, error) {
+	cmd, err := c.command(script)
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil && (exitCode(err) == sshExitConnection || exitCode(err) < 0) {
+		return out, &ConnectionError{Op: "exec", Machine: c.machine.Name, Err: err}
+	}
+	return out, err
+}
+
+// TmuxNewSession creates a new remote tmux session.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	_, err := c.run("tmux", shellJoin("tmux", args...), nil)
+	return err
+}
+
+// TmuxKillSession terminates a remote tmux session along with the pane's
+// process tree, mirroring KillSessionWithProcesses for local sessions.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	target := shellQuote("=" + name)
+	script := fmt.Sprintf(`kill_tree() { for c in $(pgrep -P "$1" 2>/dev/null); do kill_tree "$c"; done; kill -TERM "$1" 2>/dev/null; }
+pid=$(tmux display-message -p -t %[1]s '#{pane_pid}' 2>/dev/null)
+[ -n "$pid" ] && kill_tree "$pid"
+tmux kill-session -t %[1]s 2>/dev/null
+exit 0`, target)
+	_, err := c.run("tmux", script, nil)
+	return err
+}
+
+// TmuxSendKeys sends literal keys followed by Enter to a remote tmux session.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	script := shellJoin("tmux", "send-keys", "-t", session, "-l", keys) + " && sleep 0.1 && " +
+		shellJoin("tmux", "send-keys", "-t", session, "Enter")
+	_, err := c.run("tmux", script, nil)
+	return err
+}
+
+// TmuxCapturePane captures the last N lines from a remote tmux pane.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	out, err := c.run("tmux", shellJoin("tmux", "capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines)), nil)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// TmuxHasSession returns true if the remote session exists.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	_, err := c.run("tmux", shellJoin("tmux", "has-session", "-t", "="+name)+" 2>/dev/null", nil)
+	if err == nil {
+		return true, nil
+	}
+	if exitCode(err) == 1 {
+		return false, nil
+	}
+	return false, err
+}
+
+// TmuxListSessions returns all remote tmux session names.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	// A missing tmux server means no sessions, not an error.
+	script := shellJoin("tmux", "list-sessions", "-F", "#{session_name}") + " 2>/dev/null; exit 0"
+	out, err := c.run("tmux", script, nil)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			sessions = append(sessions, line)
+		}
+	}
+	return sessions, nil
+}
+
+// Close shuts down the multiplexed master connection, if one is running.
+func (c *SSHConnection) Close() error {
+	if err := os.MkdirAll(c.controlDir, 0700); err != nil {
+		return err
+	}
+	args := append([]string{"-O", "exit"}, c.sshArgs()...)
+	cmd := exec.Command(c.sshPath, args...) //nolint:gosec // G204: args are built from registry config
+	_ = cmd.Run()                           // No master running is fine
+	return nil
+}
+
+// shellQuote single-quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin quotes a command and its arguments into a single shell command line.
+func shellJoin(cmd string, args ...string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(cmd))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
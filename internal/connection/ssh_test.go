@@ -0,0 +1,260 @@
+package connection
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSSH is a stand-in for the ssh binary: it skips ssh options, treats the
+// first operand as the host and runs the remote command with the local sh.
+// The host "unreachable" simulates a connection failure.
+const fakeSSH = `#!/bin/sh
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -o|-i|-p|-O|-S) shift 2 ;;
+    -*) shift ;;
+    *) break ;;
+  esac
+done
+if [ "$1" = "unreachable" ]; then
+  echo "ssh: connect to host unreachable port 22: Connection refused" >&2
+  exit 255
+fi
+shift
+exec sh -c "$1"
+`
+
+func newTestSSHConnection(t *testing.T, host string) *SSHConnection {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "ssh")
+	if err := os.WriteFile(bin, []byte(fakeSSH), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewSSHConnection(&Machine{Name: "buildbox", Type: "ssh", Host: host})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.sshPath = bin
+	c.controlDir = t.TempDir()
+	return c
+}
+
+func TestSplitHostPort(t *testing.T) {
+	tests := []struct {
+		in, host, port string
+	}{
+		{"user@box", "user@box", ""},
+		{"user@box:2222", "user@box", "2222"},
+		{"box:notaport", "box:notaport", ""},
+		{"[::1]", "[::1]", ""},
+	}
+	for _, tt := range tests {
+		host, port := splitHostPort(tt.in)
+		if host != tt.host || port != tt.port {
+			t.Errorf("splitHostPort(%q) = %q, %q; want %q, %q", tt.in, host, port, tt.host, tt.port)
+		}
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	c, err := NewSSHConnection(&Machine{Name: "buildbox", Type: "ssh", Host: "dev@box:2222", KeyPath: "/keys/id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := strings.Join(c.sshArgs(), " ")
+	for _, want := range []string{"BatchMode=yes", "ControlMaster=auto", "ControlPersist=", "-i /keys/id", "-p 2222"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("ssh args %q missing %q", args, want)
+		}
+	}
+	if !strings.HasSuffix(args, " dev@box") {
+		t.Errorf("ssh args should end with host, got %q", args)
+	}
+}
+
+func TestSSHConnection_Files(t *testing.T) {
+	c := newTestSSHConnection(t, "dev@box")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "it's a file.txt")
+
+	if c.IsLocal() || c.Name() != "buildbox" {
+		t.Errorf("unexpected identity: local=%v name=%q", c.IsLocal(), c.Name())
+	}
+
+	if _, err := c.ReadFile(path); !isNotFound(err) {
+		t.Fatalf("ReadFile missing file: want NotFoundError, got %v", err)
+	}
+	if ok, err := c.Exists(path); err != nil || ok {
+		t.Fatalf("Exists before write = %v, %v", ok, err)
+	}
+
+	data := []byte("line one\n$HOME `x` 'quoted'\n")
+	if err := c.WriteFile(path, data, 0640); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	got, err := c.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("ReadFile = %q, want %q", got, data)
+	}
+
+	fi, err := c.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Size() != int64(len(data)) || fi.IsDir() || fi.Mode().Perm() != 0640 {
+		t.Errorf("Stat = size %d dir %v mode %v", fi.Size(), fi.IsDir(), fi.Mode())
+	}
+	if fi.Name() != "it's a file.txt" {
+		t.Errorf("Stat name = %q", fi.Name())
+	}
+
+	sub := filepath.Join(dir, "a", "b")
+	if err := c.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if fi, err := c.Stat(sub); err != nil || !fi.IsDir() {
+		t.Fatalf("Stat dir = %v, %v", fi, err)
+	}
+	if err := c.WriteFile(filepath.Join(sub, "x.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := c.Glob(filepath.Join(dir, "a", "b", "*.json"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 1 || matches[0] != filepath.Join(sub, "x.json") {
+		t.Errorf("Glob = %v", matches)
+	}
+	if matches, err := c.Glob(filepath.Join(dir, "*.none")); err != nil || len(matches) != 0 {
+		t.Errorf("Glob with no matches = %v, %v", matches, err)
+	}
+
+	if err := c.Remove(path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := c.Remove(path); err != nil {
+		t.Errorf("Remove of missing file should succeed, got %v", err)
+	}
+	if err := c.RemoveAll(filepath.Join(dir, "a")); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if ok, _ := c.Exists(filepath.Join(dir, "a")); ok {
+		t.Error("directory still exists after RemoveAll")
+	}
+}
+
+func TestSSHConnection_WriteFileMissingDir(t *testing.T) {
+	c := newTestSSHConnection(t, "dev@box")
+	err := c.WriteFile(filepath.Join(t.TempDir(), "nope", "f"), []byte("x"), 0644)
+	if !isNotFound(err) {
+		t.Errorf("WriteFile into missing dir: want NotFoundError, got %v", err)
+	}
+}
+
+func TestSSHConnection_Exec(t *testing.T) {
+	c := newTestSSHConnection(t, "dev@box")
+
+	out, err := c.Exec("echo", "hello world", "it's")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "hello world it's" {
+		t.Errorf("Exec output = %q", out)
+	}
+
+	dir := t.TempDir()
+	out, err = c.ExecDir(dir, "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	if got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out))); got != mustEval(t, dir) {
+		t.Errorf("ExecDir pwd = %q, want %q", got, dir)
+	}
+
+	out, err = c.ExecEnv(map[string]string{"GT_TEST": "a b"}, "sh", "-c", "echo $GT_TEST")
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "a b" {
+		t.Errorf("ExecEnv output = %q", out)
+	}
+
+	// A failing remote command is an ordinary exit error, not a connection error.
+	_, err = c.Exec("sh", "-c", "exit 3")
+	var connErr *ConnectionError
+	if err == nil || errors.As(err, &connErr) || exitCode(err) != 3 {
+		t.Errorf("Exec exit 3: got %v", err)
+	}
+}
+
+func TestSSHConnection_Unreachable(t *testing.T) {
+	c := newTestSSHConnection(t, "unreachable")
+
+	var connErr *ConnectionError
+	if _, err := c.ReadFile("/etc/hostname"); !errors.As(err, &connErr) {
+		t.Errorf("ReadFile: want ConnectionError, got %v", err)
+	}
+	if _, err := c.Exec("true"); !errors.As(err, &connErr) {
+		t.Errorf("Exec: want ConnectionError, got %v", err)
+	}
+	if _, err := c.Exists("/"); !errors.As(err, &connErr) {
+		t.Errorf("Exists: want ConnectionError, got %v", err)
+	}
+	if connErr != nil && connErr.Machine != "buildbox" {
+		t.Errorf("ConnectionError machine = %q", connErr.Machine)
+	}
+}
+
+func TestMachineRegistry_SSHConnection(t *testing.T) {
+	r, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(&Machine{Name: "buildbox", Type: "ssh", Host: "dev@box"}); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := r.Connection("buildbox")
+	if err != nil {
+		t.Fatalf("Connection: %v", err)
+	}
+	if _, ok := conn.(*SSHConnection); !ok {
+		t.Errorf("Connection returned %T, want *SSHConnection", conn)
+	}
+	if err := r.Add(&Machine{Name: "local", Type: "ssh", Host: "dev@box"}); err == nil {
+		t.Error("expected error when shadowing the local machine")
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	fi, err := parseStat("d", "4096 41ed 1700000000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsDir() || fi.Mode().Perm() != 0755 || fi.ModTime().Unix() != 1700000000 {
+		t.Errorf("parseStat dir = %+v", fi)
+	}
+	if _, err := parseStat("x", "garbage"); err == nil {
+		t.Error("expected error for malformed stat output")
+	}
+}
+
+func isNotFound(err error) bool {
+	var nf *NotFoundError
+	return errors.As(err, &nf)
+}
+
+func mustEval(t *testing.T, p string) string {
+	t.Helper()
+	r, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
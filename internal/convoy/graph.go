@@ -0,0 +1,217 @@
+package convoy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConvoyDepType is the dependency type linking a convoy to an upstream convoy
+// it waits for. It is a blocking type, so a waiting convoy is never fed while
+// its upstream convoys are open.
+const ConvoyDepType = "blocks"
+
+// GraphNode is a convoy in the convoy dependency graph.
+type GraphNode struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Status     string   `json:"status"`
+	Upstream   []string `json:"upstream,omitempty"`   // convoys this one waits for
+	Downstream []string `json:"downstream,omitempty"` // convoys waiting for this one
+}
+
+// Graph is the convoy-to-convoy dependency graph. An edge from a convoy to an
+// upstream convoy means the convoy must not start until the upstream lands.
+type Graph struct {
+	Nodes map[string]*GraphNode
+}
+
+// NewGraph returns an empty convoy graph.
+func NewGraph() *Graph {
+	return &Graph{Nodes: make(map[string]*GraphNode)}
+}
+
+// AddConvoy adds (or updates) a convoy node.
+func (g *Graph) AddConvoy(id, title, status string) *GraphNode {
+	node := g.node(id)
+	node.Title = title
+	node.Status = status
+	return node
+}
+
+// AddDependency records that convoyID waits for upstreamID. Duplicate edges
+// are ignored.
+func (g *Graph) AddDependency(convoyID, upstreamID string) {
+	node := g.node(convoyID)
+	for _, id := range node.Upstream {
+		if id == upstreamID {
+			return
+		}
+	}
+	node.Upstream = append(node.Upstream, upstreamID)
+	up := g.node(upstreamID)
+	up.Downstream = append(up.Downstream, convoyID)
+}
+
+func (g *Graph) node(id string) *GraphNode {
+	node, ok := g.Nodes[id]
+	if !ok {
+		node = &GraphNode{ID: id}
+		g.Nodes[id] = node
+	}
+	return node
+}
+
+// DependencyCycle returns the cycle that adding "convoyID waits for
+// upstreamID" would create, as a path from convoyID back to itself, or nil if
+// the edge is safe.
+func (g *Graph) DependencyCycle(convoyID, upstreamID string) []string {
+	if convoyID == upstreamID {
+		return []string{convoyID, convoyID}
+	}
+	// The edge closes a cycle if convoyID is already upstream of upstreamID.
+	path := g.upstreamPath(upstreamID, convoyID, make(map[string]bool))
+	if path == nil {
+		return nil
+	}
+	return append([]string{convoyID}, path...)
+}
+
+// upstreamPath returns a path from id to target following upstream edges.
+func (g *Graph) upstreamPath(id, target string, visited map[string]bool) []string {
+	if id == target {
+		return []string{id}
+	}
+	if visited[id] {
+		return nil
+	}
+	visited[id] = true
+	node := g.Nodes[id]
+	if node == nil {
+		return nil
+	}
+	for _, up := range sortedCopy(node.Upstream) {
+		if path := g.upstreamPath(up, target, visited); path != nil {
+			return append([]string{id}, path...)
+		}
+	}
+	return nil
+}
+
+// Stages orders the graph into stages: stage 0 holds convoys with no
+// upstream convoys, and every other convoy sits one stage after its latest
+// upstream. Returns an error naming a cycle if the graph has one.
+func (g *Graph) Stages() ([][]string, error) {
+	depth := make(map[string]int)
+	onStack := make(map[string]bool)
+
+	var visit func(id string, path []string) (int, error)
+	visit = func(id string, path []string) (int, error) {
+		if d, ok := depth[id]; ok {
+			return d, nil
+		}
+		if onStack[id] {
+			return 0, fmt.Errorf("convoy dependency cycle: %s", strings.Join(append(path, id), " → "))
+		}
+		onStack[id] = true
+		d := 0
+		if node := g.Nodes[id]; node != nil {
+			for _, up := range sortedCopy(node.Upstream) {
+				ud, err := visit(up, append(path, id))
+				if err != nil {
+					return 0, err
+				}
+				if ud+1 > d {
+					d = ud + 1
+				}
+			}
+		}
+		onStack[id] = false
+		depth[id] = d
+		return d, nil
+	}
+
+	var stages [][]string
+	for _, id := range g.sortedIDs() {
+		d, err := visit(id, nil)
+		if err != nil {
+			return nil, err
+		}
+		for len(stages) <= d {
+			stages = append(stages, nil)
+		}
+		stages[d] = append(stages[d], id)
+	}
+	for _, stage := range stages {
+		sort.Strings(stage)
+	}
+	return stages, nil
+}
+
+// OpenUpstream returns the upstream convoys of id that have not landed yet.
+// Upstream convoys missing from the graph count as open.
+func (g *Graph) OpenUpstream(id string) []string {
+	node := g.Nodes[id]
+	if node == nil {
+		return nil
+	}
+	var open []string
+	for _, up := range sortedCopy(node.Upstream) {
+		if n := g.Nodes[up]; n == nil || !isLanded(n.Status) {
+			open = append(open, up)
+		}
+	}
+	return open
+}
+
+// Launchable returns the staged convoys downstream of upstreamID whose
+// upstream convoys have all landed. Only staged_ready convoys qualify;
+// convoys staged with warnings still need an explicit launch.
+func (g *Graph) Launchable(upstreamID string) []string {
+	node := g.Nodes[upstreamID]
+	if node == nil {
+		return nil
+	}
+	var ready []string
+	for _, id := range sortedCopy(node.Downstream) {
+		down := g.Nodes[id]
+		if down == nil || down.Status != "staged_ready" {
+			continue
+		}
+		if len(g.OpenUpstream(id)) == 0 {
+			ready = append(ready, id)
+		}
+	}
+	return ready
+}
+
+// Connected returns the IDs of convoys that have at least one dependency
+// edge, sorted.
+func (g *Graph) Connected() []string {
+	var ids []string
+	for _, id := range g.sortedIDs() {
+		if n := g.Nodes[id]; len(n.Upstream) > 0 || len(n.Downstream) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (g *Graph) sortedIDs() []string {
+	ids := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func isLanded(status string) bool {
+	return status == "closed" || status == "tombstone"
+}
+
+func sortedCopy(ids []string) []string {
+	out := append([]string(nil), ids...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,87 @@
+package convoy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildTestGraph: api ← client ← rollout, api ← docs.
+func buildTestGraph() *Graph {
+	g := NewGraph()
+	g.AddConvoy("hq-cv-api", "API migration", "closed")
+	g.AddConvoy("hq-cv-client", "Client rollout", "staged_ready")
+	g.AddConvoy("hq-cv-docs", "Docs", "staged_warnings")
+	g.AddConvoy("hq-cv-rollout", "Rollout", "staged_ready")
+	g.AddDependency("hq-cv-client", "hq-cv-api")
+	g.AddDependency("hq-cv-docs", "hq-cv-api")
+	g.AddDependency("hq-cv-rollout", "hq-cv-client")
+	g.AddDependency("hq-cv-rollout", "hq-cv-client") // duplicate ignored
+	return g
+}
+
+func TestGraphStages(t *testing.T) {
+	stages, err := buildTestGraph().Stages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"hq-cv-api"},
+		{"hq-cv-client", "hq-cv-docs"},
+		{"hq-cv-rollout"},
+	}
+	if !reflect.DeepEqual(stages, want) {
+		t.Errorf("Stages() = %v, want %v", stages, want)
+	}
+}
+
+func TestGraphDependencyCycle(t *testing.T) {
+	g := buildTestGraph()
+	if cycle := g.DependencyCycle("hq-cv-docs", "hq-cv-client"); cycle != nil {
+		t.Errorf("docs → client is acyclic, got cycle %v", cycle)
+	}
+
+	cycle := g.DependencyCycle("hq-cv-api", "hq-cv-rollout")
+	want := []string{"hq-cv-api", "hq-cv-rollout", "hq-cv-client", "hq-cv-api"}
+	if !reflect.DeepEqual(cycle, want) {
+		t.Errorf("DependencyCycle(api, rollout) = %v, want %v", cycle, want)
+	}
+	if cycle := g.DependencyCycle("hq-cv-api", "hq-cv-api"); len(cycle) != 2 {
+		t.Errorf("self-dependency should be a cycle, got %v", cycle)
+	}
+
+	g.AddDependency("hq-cv-api", "hq-cv-rollout")
+	if _, err := g.Stages(); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Stages() on cyclic graph: err = %v, want cycle error", err)
+	}
+}
+
+func TestGraphLaunchable(t *testing.T) {
+	g := buildTestGraph()
+
+	// docs is staged with warnings and needs a manual launch.
+	if got := g.Launchable("hq-cv-api"); !reflect.DeepEqual(got, []string{"hq-cv-client"}) {
+		t.Errorf("Launchable(api) = %v, want [hq-cv-client]", got)
+	}
+	// rollout still waits for client, which has not landed.
+	if got := g.Launchable("hq-cv-client"); got != nil {
+		t.Errorf("Launchable(client) = %v, want none", got)
+	}
+	if got := g.OpenUpstream("hq-cv-rollout"); !reflect.DeepEqual(got, []string{"hq-cv-client"}) {
+		t.Errorf("OpenUpstream(rollout) = %v", got)
+	}
+
+	g.AddConvoy("hq-cv-client", "Client rollout", "closed")
+	if got := g.Launchable("hq-cv-client"); !reflect.DeepEqual(got, []string{"hq-cv-rollout"}) {
+		t.Errorf("after client lands, Launchable(client) = %v, want [hq-cv-rollout]", got)
+	}
+}
+
+func TestGraphOpenUpstream_UnknownCountsAsOpen(t *testing.T) {
+	g := NewGraph()
+	g.AddConvoy("hq-cv-b", "B", "staged_ready")
+	g.AddDependency("hq-cv-b", "hq-cv-missing")
+	if got := g.OpenUpstream("hq-cv-b"); !reflect.DeepEqual(got, []string{"hq-cv-missing"}) {
+		t.Errorf("OpenUpstream = %v, want [hq-cv-missing]", got)
+	}
+}
@@ -73,7 +73,13 @@ func CheckConvoysForIssue(ctx context.Context, store beadsdk.Storage, townRoot,
 		// Continuation feed: if convoy is still open after the completion check,
 		// reactively dispatch the next ready issue. This makes convoy feeding
 		// event-driven instead of relying on polling-based patrol cycles.
+		// A convoy waiting on upstream convoys (convoy-level blocks deps)
+		// is not fed until they land.
 		if !isConvoyClosed(ctx, store, convoyID) {
+			if isIssueBlocked(ctx, store, convoyID) {
+				logger("%s: convoy %s is waiting on upstream convoys, skipping feed", caller, convoyID)
+				continue
+			}
 			feedNextReadyIssue(ctx, store, townRoot, convoyID, caller, logger, gtPath, isRigParked)
 		}
 	}
@@ -139,9 +145,10 @@ func runConvoyCheck(ctx context.Context, townRoot, convoyID, gtPath string) erro
 	return nil
 }
 
-// trackedIssue holds basic info about an issue tracked by a convoy.
-type trackedIssue struct {
+// TrackedIssue holds basic info about an issue tracked by a convoy.
+type TrackedIssue struct {
 	ID        string `json:"id"`
+	Title     string `json:"title"`
 	Status    string `json:"status"`
 	Assignee  string `json:"assignee"`
 	Priority  int    `json:"priority"`
@@ -214,7 +221,7 @@ func isIssueBlocked(ctx context.Context, store beadsdk.Storage, issueID string)
 // next close event triggers another feed cycle.
 // gtPath is the resolved path to the gt binary.
 func feedNextReadyIssue(ctx context.Context, store beadsdk.Storage, townRoot, convoyID, caller string, logger func(format string, args ...interface{}), gtPath string, isRigParked func(string) bool) {
-	tracked := getConvoyTrackedIssues(ctx, store, convoyID)
+	tracked := TrackedIssues(ctx, store, convoyID)
 	if len(tracked) == 0 {
 		return
 	}
@@ -272,9 +279,21 @@ func feedNextReadyIssue(ctx context.Context, store beadsdk.Storage, townRoot, co
 	logger("%s: convoy %s: no ready issues to feed", caller, convoyID)
 }
 
-// getConvoyTrackedIssues returns issues tracked by a convoy with fresh status.
+// ListConvoys returns the convoys in store with the given status, or every
+// convoy when status is empty.
+func ListConvoys(ctx context.Context, store beadsdk.Storage, status string) ([]*beadsdk.Issue, error) {
+	convoyType := beadsdk.IssueType("convoy")
+	filter := beadsdk.IssueFilter{IssueType: &convoyType}
+	if status != "" {
+		s := beadsdk.Status(status)
+		filter.Status = &s
+	}
+	return store.SearchIssues(ctx, "", filter)
+}
+
+// TrackedIssues returns issues tracked by a convoy with fresh status.
 // Uses SDK GetDependenciesWithMetadata filtered by tracks, then GetIssuesByIDs for current status.
-func getConvoyTrackedIssues(ctx context.Context, store beadsdk.Storage, convoyID string) []trackedIssue {
+func TrackedIssues(ctx context.Context, store beadsdk.Storage, convoyID string) []TrackedIssue {
 	deps, err := store.GetDependenciesWithMetadata(ctx, convoyID)
 	if err != nil || len(deps) == 0 {
 		return nil
@@ -283,6 +302,7 @@ func getConvoyTrackedIssues(ctx context.Context, store beadsdk.Storage, convoyID
 	// Filter by tracks type and collect IDs
 	var ids []string
 	type depMeta struct {
+		title     string
 		status    string
 		assignee  string
 		priority  int
@@ -294,6 +314,7 @@ func getConvoyTrackedIssues(ctx context.Context, store beadsdk.Storage, convoyID
 			id := extractIssueID(d.ID)
 			ids = append(ids, id)
 			metaByID[id] = depMeta{
+				title:     d.Title,
 				status:    string(d.Status),
 				assignee:  d.Assignee,
 				priority:  d.Priority,
@@ -318,15 +339,17 @@ func getConvoyTrackedIssues(ctx context.Context, store beadsdk.Storage, convoyID
 		}
 	}
 
-	result := make([]trackedIssue, 0, len(ids))
+	result := make([]TrackedIssue, 0, len(ids))
 	for _, id := range ids {
-		t := trackedIssue{ID: id}
+		t := TrackedIssue{ID: id}
 		if fresh := freshMap[id]; fresh != nil {
+			t.Title = fresh.Title
 			t.Status = string(fresh.Status)
 			t.Assignee = fresh.Assignee
 			t.Priority = fresh.Priority
 			t.IssueType = string(fresh.IssueType)
 		} else if meta, ok := metaByID[id]; ok {
+			t.Title = meta.title
 			t.Status = meta.status
 			t.Assignee = meta.assignee
 			t.Priority = meta.priority
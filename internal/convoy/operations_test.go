@@ -81,7 +81,7 @@ func TestIsIssueBlocked_NoStore(t *testing.T) {
 func TestReadyIssueFilterLogic_SkipsNonSlingableTypes(t *testing.T) {
 	// Validates that feedNextReadyIssue's type filter skips non-slingable types.
 	// We test the predicate inline (same pattern as existing filter tests).
-	tracked := []trackedIssue{
+	tracked := []TrackedIssue{
 		{ID: "gt-epic", Status: "open", Assignee: "", IssueType: "epic"},
 		{ID: "gt-task", Status: "open", Assignee: "", IssueType: "task"},
 		{ID: "gt-convoy", Status: "open", Assignee: "", IssueType: "convoy"},
@@ -109,7 +109,7 @@ func TestReadyIssueFilterLogic_SkipsNonOpenIssues(t *testing.T) {
 	// the predicate inline because feedNextReadyIssue also calls rigForIssue
 	// and dispatchIssue, making isolated unit testing impractical without a
 	// real store. Integration coverage lives in convoy_manager_integration_test.go.
-	tracked := []trackedIssue{
+	tracked := []TrackedIssue{
 		{ID: "gt-closed", Status: "closed", Assignee: ""},
 		{ID: "gt-inprog", Status: "in_progress", Assignee: "gastown/polecats/alpha"},
 		{ID: "gt-hooked", Status: "hooked", Assignee: "gastown/polecats/beta"},
@@ -128,7 +128,7 @@ func TestReadyIssueFilterLogic_FindsReadyIssue(t *testing.T) {
 	// Validates that the "first open+unassigned" selection picks the correct
 	// issue. See comment on TestReadyIssueFilterLogic_SkipsNonOpenIssues for
 	// why this tests the predicate inline rather than calling feedNextReadyIssue.
-	tracked := []trackedIssue{
+	tracked := []TrackedIssue{
 		{ID: "gt-closed", Status: "closed", Assignee: ""},
 		{ID: "gt-inprog", Status: "in_progress", Assignee: "gastown/polecats/alpha"},
 		{ID: "gt-ready", Status: "open", Assignee: ""},
@@ -812,7 +812,7 @@ func TestFeedNextReadyIssue_SkipsBlockedIssue(t *testing.T) {
 	if err != nil {
 		// If gt was not called at all, check if GetDependenciesWithMetadata
 		// failed (embedded Dolt nested query limitation). This means both
-		// isIssueBlocked and getConvoyTrackedIssues may fail.
+		// isIssueBlocked and TrackedIssues may fail.
 		t.Logf("gt stub not called; log messages: %v", *logMsgs)
 		t.Skipf("gt stub was not called — likely embedded Dolt nested query limitation")
 	}
@@ -972,7 +972,7 @@ func TestFeedNextReadyIssue_SkipsParkedRig(t *testing.T) {
 		}
 	}
 	if !foundParked {
-		// It's also possible we got "no ready issues" if getConvoyTrackedIssues
+		// It's also possible we got "no ready issues" if TrackedIssues
 		// failed due to embedded Dolt. Accept either.
 		t.Logf("log messages: %v", *logMsgs)
 	}
@@ -179,3 +179,52 @@ func TestIsConvoyClosed_ReturnsCorrectStatus(t *testing.T) {
 		t.Error("isConvoyClosed(closed) = false, want true")
 	}
 }
+
+func TestListConvoysAndTrackedIssues(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for _, issue := range []*beadsdk.Issue{
+		{ID: "hq-cv-open", Title: "Open Convoy", Status: beadsdk.StatusOpen, IssueType: beadsdk.IssueType("convoy")},
+		{ID: "hq-cv-done", Title: "Done Convoy", Status: beadsdk.StatusClosed, IssueType: beadsdk.IssueType("convoy"), ClosedAt: &now},
+		{ID: "gt-work", Title: "Work", Status: beadsdk.StatusOpen, IssueType: beadsdk.TypeTask},
+	} {
+		issue.Priority = 2
+		issue.CreatedAt, issue.UpdatedAt = now, now
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue %s: %v", issue.ID, err)
+		}
+	}
+	if err := store.AddDependency(ctx, &beadsdk.Dependency{
+		IssueID:     "hq-cv-open",
+		DependsOnID: "gt-work",
+		Type:        beadsdk.DependencyType("tracks"),
+		CreatedAt:   now,
+		CreatedBy:   "test",
+	}, "test"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+
+	open, err := ListConvoys(ctx, store, "open")
+	if err != nil {
+		t.Fatalf("ListConvoys open: %v", err)
+	}
+	if len(open) != 1 || open[0].ID != "hq-cv-open" {
+		t.Errorf("open convoys = %v, want [hq-cv-open]", open)
+	}
+	all, err := ListConvoys(ctx, store, "")
+	if err != nil {
+		t.Fatalf("ListConvoys all: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("all convoys = %d, want 2", len(all))
+	}
+
+	tracked := TrackedIssues(ctx, store, "hq-cv-open")
+	if len(tracked) != 1 || tracked[0].ID != "gt-work" || tracked[0].Title != "Work" {
+		t.Errorf("TrackedIssues = %+v, want gt-work titled Work", tracked)
+	}
+}
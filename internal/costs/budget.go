@@ -0,0 +1,194 @@
+package costs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Budget scopes.
+const (
+	ScopeDay    = "day"    // town-wide spend today
+	ScopeRig    = "rig"    // one rig's spend today
+	ScopeConvoy = "convoy" // one convoy's total spend
+)
+
+// Spend is the current spend against each budget scope.
+type Spend struct {
+	Day    float64
+	RigDay map[string]float64
+	Convoy map[string]float64
+}
+
+// Tally computes spend from ledger entries plus archived convoy spend.
+//
+// The Stop hook records a session's cumulative cost after every turn, so a
+// session appears many times in the ledger. Only the last entry of each run
+// counts; a drop in cost marks a new session reusing the same name.
+func Tally(entries []LedgerEntry, archivedConvoy map[string]float64, now time.Time) *Spend {
+	spend := &Spend{RigDay: map[string]float64{}, Convoy: map[string]float64{}}
+	for convoy, usd := range archivedConvoy {
+		spend.Convoy[convoy] += usd
+	}
+
+	sorted := make([]LedgerEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].SessionID != sorted[j].SessionID {
+			return sorted[i].SessionID < sorted[j].SessionID
+		}
+		return sorted[i].EndedAt.Before(sorted[j].EndedAt)
+	})
+
+	today := now.Format("2006-01-02")
+	count := func(e LedgerEntry) {
+		if e.EndedAt.In(now.Location()).Format("2006-01-02") == today {
+			spend.Day += e.CostUSD
+			if e.Rig != "" {
+				spend.RigDay[e.Rig] += e.CostUSD
+			}
+		}
+		if e.Convoy != "" {
+			spend.Convoy[e.Convoy] += e.CostUSD
+		}
+	}
+	for i, e := range sorted {
+		last := i == len(sorted)-1 ||
+			sorted[i+1].SessionID != e.SessionID ||
+			sorted[i+1].CostUSD < e.CostUSD
+		if last {
+			count(e)
+		}
+	}
+	return spend
+}
+
+// LoadSpend reads the ledger and convoy archive and tallies current spend.
+func LoadSpend(townRoot string, now time.Time) (*Spend, error) {
+	entries, err := ReadLedger(LedgerPath())
+	if err != nil {
+		return nil, err
+	}
+	archived, err := LoadConvoySpend(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return Tally(entries, archived, now), nil
+}
+
+// Status is spend against one budget.
+type Status struct {
+	Scope    string  `json:"scope"`
+	Name     string  `json:"name,omitempty"` // rig or convoy ID
+	LimitUSD float64 `json:"limit_usd"`
+	SpentUSD float64 `json:"spent_usd"`
+	Exceeded bool    `json:"exceeded"`
+}
+
+// Key identifies the budget, e.g. "rig:gastown".
+func (s Status) Key() string {
+	if s.Name == "" {
+		return s.Scope
+	}
+	return s.Scope + ":" + s.Name
+}
+
+func (s Status) String() string {
+	var what string
+	switch s.Scope {
+	case ScopeDay:
+		what = "town daily budget"
+	case ScopeRig:
+		what = fmt.Sprintf("rig %s daily budget", s.Name)
+	default:
+		what = fmt.Sprintf("convoy %s budget", s.Name)
+	}
+	return fmt.Sprintf("%s: $%.2f of $%.2f spent", what, s.SpentUSD, s.LimitUSD)
+}
+
+// Verdict is the outcome of checking the budgets that cover a piece of work.
+type Verdict struct {
+	// Statuses lists budgets at or above the warning threshold, exhausted first.
+	Statuses []Status
+	// Action is the configured budget action.
+	Action string
+}
+
+// Exceeded returns the exhausted budgets.
+func (v Verdict) Exceeded() []Status {
+	var out []Status
+	for _, s := range v.Statuses {
+		if s.Exceeded {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Blocks reports whether the work must be held back.
+func (v Verdict) Blocks() bool {
+	return v.Action != config.BudgetActionWarn && len(v.Exceeded()) > 0
+}
+
+// Evaluate checks the budgets covering work on rig (and convoy, if non-empty).
+func (s *Spend) Evaluate(cfg *config.BudgetConfig, rig, convoy string) Verdict {
+	v := Verdict{Action: cfg.GetAction()}
+	if cfg == nil {
+		return v
+	}
+	warnAt := float64(cfg.GetWarnPercent()) / 100
+
+	check := func(scope, name string, limit, spent float64) {
+		if limit <= 0 || spent < limit*warnAt {
+			return
+		}
+		v.Statuses = append(v.Statuses, Status{
+			Scope:    scope,
+			Name:     name,
+			LimitUSD: limit,
+			SpentUSD: spent,
+			Exceeded: spent >= limit,
+		})
+	}
+	check(ScopeDay, "", cfg.DailyUSD, s.Day)
+	if rig != "" {
+		check(ScopeRig, rig, cfg.GetRigDailyUSD(rig), s.RigDay[rig])
+	}
+	if convoy != "" {
+		check(ScopeConvoy, convoy, cfg.ConvoyUSD, s.Convoy[convoy])
+	}
+
+	sort.SliceStable(v.Statuses, func(i, j int) bool {
+		return v.Statuses[i].Exceeded && !v.Statuses[j].Exceeded
+	})
+	return v
+}
+
+// escalationStatePath records which exhausted budgets have been escalated.
+func escalationStatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "cost-budget-escalations.json")
+}
+
+// MarkEscalated records that an exhausted budget was escalated today and
+// reports whether this is the first escalation for it today, so callers
+// escalate once per budget per day instead of on every dispatch cycle.
+func MarkEscalated(townRoot string, s Status, now time.Time) (bool, error) {
+	path := escalationStatePath(townRoot)
+	state := map[string]string{}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+
+	today := now.Format("2006-01-02")
+	if state[s.Key()] == today {
+		return false, nil
+	}
+	state[s.Key()] = today
+	return true, util.EnsureDirAndWriteJSON(path, state)
+}
@@ -0,0 +1,136 @@
+package costs
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestTally_CountsLastEntryPerRun(t *testing.T) {
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	entries := []LedgerEntry{
+		// Stop hook records cumulative cost after every turn.
+		{SessionID: "gt-gastown-toast", Rig: "gastown", Convoy: "hq-cv-1", CostUSD: 1, EndedAt: now.Add(-3 * time.Hour)},
+		{SessionID: "gt-gastown-toast", Rig: "gastown", Convoy: "hq-cv-1", CostUSD: 2, EndedAt: now.Add(-2 * time.Hour)},
+		// Cost drops: a new session reused the name.
+		{SessionID: "gt-gastown-toast", Rig: "gastown", CostUSD: 0.5, EndedAt: now.Add(-1 * time.Hour)},
+		{SessionID: "hq-mayor", CostUSD: 4, EndedAt: now.Add(-time.Hour)},
+		// Yesterday counts toward convoys only.
+		{SessionID: "gt-beads-nux", Rig: "beads", Convoy: "hq-cv-1", CostUSD: 10, EndedAt: yesterday},
+	}
+
+	spend := Tally(entries, map[string]float64{"hq-cv-1": 5, "hq-cv-old": 7}, now)
+
+	if spend.Day != 6.5 {
+		t.Errorf("Day = %v, want 6.5", spend.Day)
+	}
+	if spend.RigDay["gastown"] != 2.5 {
+		t.Errorf("RigDay[gastown] = %v, want 2.5", spend.RigDay["gastown"])
+	}
+	if _, ok := spend.RigDay["beads"]; ok {
+		t.Errorf("RigDay includes yesterday's beads spend: %v", spend.RigDay)
+	}
+	if spend.Convoy["hq-cv-1"] != 17 {
+		t.Errorf("Convoy[hq-cv-1] = %v, want 17", spend.Convoy["hq-cv-1"])
+	}
+	if spend.Convoy["hq-cv-old"] != 7 {
+		t.Errorf("Convoy[hq-cv-old] = %v, want 7", spend.Convoy["hq-cv-old"])
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	spend := &Spend{
+		Day:    85,
+		RigDay: map[string]float64{"gastown": 50, "beads": 10},
+		Convoy: map[string]float64{"hq-cv-1": 20},
+	}
+	cfg := &config.BudgetConfig{
+		DailyUSD:    100,
+		RigDailyUSD: 40,
+		Rigs:        map[string]float64{"beads": -1},
+		ConvoyUSD:   100,
+	}
+
+	v := spend.Evaluate(cfg, "gastown", "hq-cv-1")
+	if len(v.Statuses) != 2 {
+		t.Fatalf("Statuses = %+v, want rig exhausted and day warning", v.Statuses)
+	}
+	if v.Statuses[0].Key() != "rig:gastown" || !v.Statuses[0].Exceeded {
+		t.Errorf("Statuses[0] = %+v, want exhausted rig:gastown first", v.Statuses[0])
+	}
+	if v.Statuses[1].Key() != "day" || v.Statuses[1].Exceeded {
+		t.Errorf("Statuses[1] = %+v, want day warning", v.Statuses[1])
+	}
+	if !v.Blocks() {
+		t.Error("Blocks() = false, want true with default pause action")
+	}
+
+	if v := spend.Evaluate(cfg, "beads", ""); v.Blocks() {
+		t.Errorf("unlimited rig blocked: %+v", v)
+	}
+
+	cfg.Action = config.BudgetActionWarn
+	if v := spend.Evaluate(cfg, "gastown", ""); v.Blocks() || len(v.Exceeded()) != 1 {
+		t.Errorf("warn action: Blocks()=%v Exceeded()=%v, want false and 1", v.Blocks(), v.Exceeded())
+	}
+
+	if v := spend.Evaluate(nil, "gastown", ""); v.Blocks() || len(v.Statuses) != 0 {
+		t.Errorf("nil config: %+v, want no statuses", v)
+	}
+}
+
+func TestMarkEscalated_OncePerDay(t *testing.T) {
+	townRoot := t.TempDir()
+	s := Status{Scope: ScopeRig, Name: "gastown"}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	for i, tc := range []struct {
+		at   time.Time
+		want bool
+	}{
+		{now, true},
+		{now.Add(time.Hour), false},
+		{now.AddDate(0, 0, 1), true},
+	} {
+		first, err := MarkEscalated(townRoot, s, tc.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != tc.want {
+			t.Errorf("call %d: first = %v, want %v", i, first, tc.want)
+		}
+	}
+}
+
+func TestConvoySpendArchive(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := ArchiveConvoySpend(townRoot, map[string]float64{"hq-cv-1": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ArchiveConvoySpend(townRoot, map[string]float64{"hq-cv-1": 3, "hq-cv-2": 1}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadConvoySpend(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["hq-cv-1"] != 5 || got["hq-cv-2"] != 1 {
+		t.Errorf("archive = %v, want hq-cv-1=5 hq-cv-2=1", got)
+	}
+
+	ledger := filepath.Join(t.TempDir(), "costs.jsonl")
+	entry := LedgerEntry{SessionID: "s", CostUSD: 1.5, Convoy: "hq-cv-1", EndedAt: time.Now().UTC().Truncate(time.Second)}
+	if err := AppendLedger(ledger, entry); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadLedger(ledger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0] != entry {
+		t.Errorf("ReadLedger = %+v, want [%+v]", entries, entry)
+	}
+}
@@ -0,0 +1,140 @@
+package costs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// claudeTranscriptMessage is one line of a Claude Code transcript.
+type claudeTranscriptMessage struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+// ClaudeProjectDir returns the Claude Code project directory for a working directory.
+// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
+func ClaudeProjectDir(home, workDir string) string {
+	// Keep leading slash - it becomes a leading dash in Claude's encoding
+	return filepath.Join(home, ".claude", "projects", strings.ReplaceAll(workDir, "/", "-"))
+}
+
+func claudeUsage(home, workDir string) (*Usage, error) {
+	path, err := latestFile(ClaudeProjectDir(home, workDir), ".jsonl")
+	if err != nil {
+		return nil, err
+	}
+	return parseClaudeTranscript(path)
+}
+
+// parseClaudeTranscript sums token usage from the assistant messages of a transcript.
+func parseClaudeTranscript(path string) (*Usage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	usage := &Usage{}
+	scanner := newLineScanner(file)
+	for scanner.Scan() {
+		var msg claudeTranscriptMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue // Skip malformed lines
+		}
+		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+			continue
+		}
+		// Use the first model found; they should all be the same
+		if usage.Model == "" {
+			usage.Model = msg.Message.Model
+		}
+		u := msg.Message.Usage
+		usage.InputTokens += u.InputTokens
+		usage.CacheWriteTokens += u.CacheCreationInputTokens
+		usage.CacheReadTokens += u.CacheReadInputTokens
+		usage.OutputTokens += u.OutputTokens
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// latestFile finds the most recently modified file with the given suffix
+// directly inside dir.
+func latestFile(dir, suffix string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w in %s", ErrNoSession, dir)
+		}
+		return "", err
+	}
+
+	var latestPath string
+	var latestTime time.Time
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), suffix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // Skip files we can't stat
+		}
+		if info.ModTime().After(latestTime) {
+			latestTime = info.ModTime()
+			latestPath = filepath.Join(dir, e.Name())
+		}
+	}
+	if latestPath == "" {
+		return "", fmt.Errorf("%w in %s", ErrNoSession, dir)
+	}
+	return latestPath, nil
+}
+
+// filesByModTime walks root and returns files accepted by match, newest first.
+func filesByModTime(root string, match func(name string) bool) []string {
+	type found struct {
+		path string
+		mod  time.Time
+	}
+	var files []found
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !match(d.Name()) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files = append(files, found{path, info.ModTime()})
+		}
+		return nil
+	})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.After(files[j].mod) })
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths
+}
+
+// newLineScanner returns a scanner sized for large JSONL lines.
+func newLineScanner(f *os.File) *bufio.Scanner {
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	return scanner
+}
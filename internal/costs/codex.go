@@ -0,0 +1,89 @@
+package costs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// codexRolloutLine is one line of a Codex rollout log.
+type codexRolloutLine struct {
+	Type    string `json:"type"`
+	Payload struct {
+		Type  string `json:"type"`
+		CWD   string `json:"cwd"`
+		Model string `json:"model"`
+		Info  *struct {
+			Total *struct {
+				InputTokens       int `json:"input_tokens"`
+				CachedInputTokens int `json:"cached_input_tokens"`
+				OutputTokens      int `json:"output_tokens"`
+			} `json:"total_token_usage"`
+		} `json:"info"`
+	} `json:"payload"`
+}
+
+// codexUsage reads the newest rollout under ~/.codex/sessions whose session
+// metadata names workDir. Codex reports cumulative totals, so the last
+// token_count event is the session's usage.
+func codexUsage(home, workDir string) (*Usage, error) {
+	root := filepath.Join(home, ".codex", "sessions")
+	rollouts := filesByModTime(root, func(name string) bool {
+		return strings.HasPrefix(name, "rollout-") && strings.HasSuffix(name, ".jsonl")
+	})
+	for _, path := range rollouts {
+		if usage, err := parseCodexRollout(path, workDir); err == nil {
+			return usage, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s in %s", ErrNoSession, workDir, root)
+}
+
+// parseCodexRollout returns a rollout's usage, or ErrNoSession if the
+// rollout belongs to a different working directory.
+func parseCodexRollout(path, workDir string) (*Usage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	usage := &Usage{}
+	var cwd string
+	scanner := newLineScanner(file)
+	for scanner.Scan() {
+		var line codexRolloutLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		switch {
+		case line.Type == "session_meta" || line.Type == "turn_context":
+			if cwd == "" {
+				cwd = line.Payload.CWD
+			}
+			if line.Payload.Model != "" {
+				usage.Model = line.Payload.Model
+			}
+		case line.Type == "event_msg" && line.Payload.Type == "token_count":
+			if line.Payload.Info == nil || line.Payload.Info.Total == nil {
+				continue
+			}
+			t := line.Payload.Info.Total
+			// Codex input totals include cached tokens.
+			usage.InputTokens = t.InputTokens - t.CachedInputTokens
+			usage.CacheReadTokens = t.CachedInputTokens
+			usage.OutputTokens = t.OutputTokens
+		}
+		// session_meta is the first line, so other directories' rollouts
+		// are rejected without reading them in full.
+		if cwd == "" || filepath.Clean(cwd) != filepath.Clean(workDir) {
+			return nil, ErrNoSession
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
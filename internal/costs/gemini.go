@@ -0,0 +1,60 @@
+package costs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// geminiChat is a Gemini CLI chat recording.
+type geminiChat struct {
+	Messages []struct {
+		Type   string `json:"type"`
+		Model  string `json:"model"`
+		Tokens *struct {
+			Input    int `json:"input"`
+			Output   int `json:"output"`
+			Cached   int `json:"cached"`
+			Thoughts int `json:"thoughts"`
+		} `json:"tokens"`
+	} `json:"messages"`
+}
+
+// GeminiChatsDir returns the Gemini CLI chat directory for a working directory.
+// Gemini CLI keys project state by the SHA-256 of the project root.
+func GeminiChatsDir(home, workDir string) string {
+	sum := sha256.Sum256([]byte(workDir))
+	return filepath.Join(home, ".gemini", "tmp", hex.EncodeToString(sum[:]), "chats")
+}
+
+func geminiUsage(home, workDir string) (*Usage, error) {
+	path, err := latestFile(GeminiChatsDir(home, workDir), ".json")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var chat geminiChat
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return nil, err
+	}
+
+	usage := &Usage{}
+	for _, m := range chat.Messages {
+		if m.Tokens == nil {
+			continue
+		}
+		if usage.Model == "" {
+			usage.Model = m.Model
+		}
+		// Gemini input counts include cached tokens; thinking is billed as output.
+		usage.InputTokens += m.Tokens.Input - m.Tokens.Cached
+		usage.CacheReadTokens += m.Tokens.Cached
+		usage.OutputTokens += m.Tokens.Output + m.Tokens.Thoughts
+	}
+	return usage, nil
+}
@@ -0,0 +1,128 @@
+package costs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// LedgerEntry is a single line in the costs.jsonl ledger.
+type LedgerEntry struct {
+	SessionID string    `json:"session_id"`
+	Role      string    `json:"role"`
+	Rig       string    `json:"rig,omitempty"`
+	Worker    string    `json:"worker,omitempty"`
+	CostUSD   float64   `json:"cost_usd"`
+	EndedAt   time.Time `json:"ended_at"`
+	WorkItem  string    `json:"work_item,omitempty"`
+	Convoy    string    `json:"convoy,omitempty"`
+	Agent     string    `json:"agent,omitempty"`
+	Model     string    `json:"model,omitempty"`
+}
+
+// LedgerPath returns the path to the costs ledger (~/.gt/costs.jsonl).
+func LedgerPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp/gt-costs.jsonl" // Fallback
+	}
+	return filepath.Join(home, ".gt", "costs.jsonl")
+}
+
+// AppendLedger appends an entry to the ledger at path.
+func AppendLedger(path string, entry LedgerEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling cost entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	// O_APPEND writes are atomic on POSIX for writes < PIPE_BUF (~4KB).
+	// A JSON log entry is ~300 bytes, so concurrent appends are safe.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening costs log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing to costs log: %w", err)
+	}
+	return nil
+}
+
+// ReadLedger reads all entries from the ledger at path, skipping malformed
+// lines. A missing ledger has no entries.
+func ReadLedger(path string) ([]LedgerEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading costs log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []LedgerEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e LedgerEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading costs log: %w", err)
+	}
+	return entries, nil
+}
+
+// ConvoySpendPath returns the path of the per-convoy spend archive.
+// The daily digest removes ledger entries, so it folds their convoy
+// spend into this file to keep convoy budgets accurate across days.
+func ConvoySpendPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "convoy-costs.json")
+}
+
+// LoadConvoySpend reads archived per-convoy spend. A missing file is empty.
+func LoadConvoySpend(townRoot string) (map[string]float64, error) {
+	data, err := os.ReadFile(ConvoySpendPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]float64{}, nil
+		}
+		return nil, err
+	}
+	spend := map[string]float64{}
+	if err := json.Unmarshal(data, &spend); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ConvoySpendPath(townRoot), err)
+	}
+	return spend, nil
+}
+
+// ArchiveConvoySpend adds spend to the per-convoy archive.
+func ArchiveConvoySpend(townRoot string, add map[string]float64) error {
+	if len(add) == 0 {
+		return nil
+	}
+	spend, err := LoadConvoySpend(townRoot)
+	if err != nil {
+		return err
+	}
+	for convoy, usd := range add {
+		spend[convoy] += usd
+	}
+	return util.EnsureDirAndWriteJSON(ConvoySpendPath(townRoot), spend)
+}
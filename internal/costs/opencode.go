@@ -0,0 +1,97 @@
+package costs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// openCodeMessage is an OpenCode message record.
+type openCodeMessage struct {
+	Role    string `json:"role"`
+	ModelID string `json:"modelID"`
+	Path    *struct {
+		CWD string `json:"cwd"`
+	} `json:"path"`
+	Tokens *struct {
+		Input     int `json:"input"`
+		Output    int `json:"output"`
+		Reasoning int `json:"reasoning"`
+		Cache     struct {
+			Read  int `json:"read"`
+			Write int `json:"write"`
+		} `json:"cache"`
+	} `json:"tokens"`
+}
+
+// openCodeUsage sums the assistant messages of the newest OpenCode session
+// whose messages were recorded in workDir. OpenCode stores one JSON file per
+// message under storage/message/<session-id>/.
+func openCodeUsage(home, workDir string) (*Usage, error) {
+	root := filepath.Join(home, ".local", "share", "opencode", "storage", "message")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %v", ErrNoSession, workDir, err)
+	}
+
+	type sessionDir struct {
+		path string
+		mod  int64
+	}
+	var sessions []sessionDir
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			sessions = append(sessions, sessionDir{filepath.Join(root, e.Name()), info.ModTime().UnixNano()})
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].mod > sessions[j].mod })
+
+	for _, s := range sessions {
+		if usage, ok := parseOpenCodeSession(s.path, workDir); ok {
+			return usage, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s in %s", ErrNoSession, workDir, root)
+}
+
+// parseOpenCodeSession sums a session's assistant usage. ok is false if the
+// session has no assistant messages recorded in workDir.
+func parseOpenCodeSession(dir, workDir string) (*Usage, bool) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, false
+	}
+
+	usage := &Usage{}
+	matched := false
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var msg openCodeMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.Role != "assistant" || msg.Tokens == nil || msg.Path == nil {
+			continue
+		}
+		if filepath.Clean(msg.Path.CWD) != filepath.Clean(workDir) {
+			return nil, false
+		}
+		matched = true
+		if usage.Model == "" {
+			usage.Model = msg.ModelID
+		}
+		usage.InputTokens += msg.Tokens.Input
+		usage.CacheReadTokens += msg.Tokens.Cache.Read
+		usage.CacheWriteTokens += msg.Tokens.Cache.Write
+		usage.OutputTokens += msg.Tokens.Output + msg.Tokens.Reasoning
+	}
+	return usage, matched
+}
@@ -0,0 +1,95 @@
+package costs
+
+import (
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DefaultModel is the pricing key used for models with no matching entry.
+const DefaultModel = "default"
+
+// DefaultPricing is the built-in price table in USD per million tokens.
+// Keys match a model name exactly or as a prefix; the longest match wins.
+// Town settings (costs.pricing) override or extend these entries.
+var DefaultPricing = map[string]config.ModelPricing{
+	// Anthropic (see https://www.anthropic.com/pricing)
+	"claude-opus-4-5-20251101":  {InputPerMillion: 15.0, OutputPerMillion: 75.0, CacheReadPerMillion: 1.5, CacheWritePerMillion: 18.75},
+	"claude-opus-4":             {InputPerMillion: 15.0, OutputPerMillion: 75.0, CacheReadPerMillion: 1.5, CacheWritePerMillion: 18.75},
+	"claude-sonnet-4-20250514":  {InputPerMillion: 3.0, OutputPerMillion: 15.0, CacheReadPerMillion: 0.3, CacheWritePerMillion: 3.75},
+	"claude-sonnet-4":           {InputPerMillion: 3.0, OutputPerMillion: 15.0, CacheReadPerMillion: 0.3, CacheWritePerMillion: 3.75},
+	"claude-haiku-4":            {InputPerMillion: 1.0, OutputPerMillion: 5.0, CacheReadPerMillion: 0.1, CacheWritePerMillion: 1.25},
+	"claude-3-5-haiku-20241022": {InputPerMillion: 1.0, OutputPerMillion: 5.0, CacheReadPerMillion: 0.1, CacheWritePerMillion: 1.25},
+
+	// OpenAI (see https://openai.com/api/pricing)
+	"gpt-5":      {InputPerMillion: 1.25, OutputPerMillion: 10.0, CacheReadPerMillion: 0.125},
+	"gpt-5-mini": {InputPerMillion: 0.25, OutputPerMillion: 2.0, CacheReadPerMillion: 0.025},
+	"gpt-5-nano": {InputPerMillion: 0.05, OutputPerMillion: 0.4, CacheReadPerMillion: 0.005},
+	"o3":         {InputPerMillion: 2.0, OutputPerMillion: 8.0, CacheReadPerMillion: 0.5},
+	"o4-mini":    {InputPerMillion: 1.1, OutputPerMillion: 4.4, CacheReadPerMillion: 0.275},
+
+	// Google (see https://ai.google.dev/pricing)
+	"gemini-2.5-pro":        {InputPerMillion: 1.25, OutputPerMillion: 10.0, CacheReadPerMillion: 0.31},
+	"gemini-2.5-flash":      {InputPerMillion: 0.30, OutputPerMillion: 2.5, CacheReadPerMillion: 0.075},
+	"gemini-2.5-flash-lite": {InputPerMillion: 0.10, OutputPerMillion: 0.4, CacheReadPerMillion: 0.025},
+
+	// Fallback for unknown models (Sonnet pricing)
+	DefaultModel: {InputPerMillion: 3.0, OutputPerMillion: 15.0, CacheReadPerMillion: 0.3, CacheWritePerMillion: 3.75},
+}
+
+// PriceTable prices token usage by model.
+type PriceTable struct {
+	prices map[string]config.ModelPricing
+}
+
+// NewPriceTable returns the built-in prices with overrides applied.
+func NewPriceTable(overrides map[string]*config.ModelPricing) *PriceTable {
+	prices := make(map[string]config.ModelPricing, len(DefaultPricing)+len(overrides))
+	for model, p := range DefaultPricing {
+		prices[model] = p
+	}
+	for model, p := range overrides {
+		if p != nil {
+			prices[model] = *p
+		}
+	}
+	return &PriceTable{prices: prices}
+}
+
+// PriceTableFromSettings builds a price table from town settings.
+func PriceTableFromSettings(settings *config.TownSettings) *PriceTable {
+	if settings == nil || settings.Costs == nil {
+		return NewPriceTable(nil)
+	}
+	return NewPriceTable(settings.Costs.Pricing)
+}
+
+// Lookup returns the price for a model: an exact match, else the longest
+// matching prefix, else the default entry.
+func (t *PriceTable) Lookup(model string) config.ModelPricing {
+	if p, ok := t.prices[model]; ok {
+		return p
+	}
+	best := ""
+	for key := range t.prices {
+		if key != DefaultModel && strings.HasPrefix(model, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best != "" {
+		return t.prices[best]
+	}
+	return t.prices[DefaultModel]
+}
+
+// Cost converts usage to USD.
+func (t *PriceTable) Cost(u *Usage) float64 {
+	if u == nil {
+		return 0
+	}
+	p := t.Lookup(u.Model)
+	return float64(u.InputTokens)/1_000_000*p.InputPerMillion +
+		float64(u.CacheReadTokens)/1_000_000*p.CacheReadPerMillion +
+		float64(u.CacheWriteTokens)/1_000_000*p.CacheWritePerMillion +
+		float64(u.OutputTokens)/1_000_000*p.OutputPerMillion
+}
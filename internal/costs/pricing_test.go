@@ -0,0 +1,51 @@
+package costs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPriceTable_Lookup(t *testing.T) {
+	table := NewPriceTable(map[string]*config.ModelPricing{
+		"my-model": {InputPerMillion: 1, OutputPerMillion: 2},
+		"gpt-5":    {InputPerMillion: 9},
+	})
+
+	tests := []struct {
+		model string
+		input float64
+	}{
+		{"claude-opus-4-5-20251101", 15.0}, // exact
+		{"claude-opus-4-1-20250805", 15.0}, // prefix
+		{"gpt-5-mini-2025-08-07", 0.25},    // longest prefix beats gpt-5
+		{"gpt-5", 9},                       // override
+		{"my-model", 1},                    // added
+		{"llama-3", 3.0},                   // default
+		{"", 3.0},
+	}
+	for _, tt := range tests {
+		if got := table.Lookup(tt.model).InputPerMillion; got != tt.input {
+			t.Errorf("Lookup(%q).InputPerMillion = %v, want %v", tt.model, got, tt.input)
+		}
+	}
+}
+
+func TestPriceTable_Cost(t *testing.T) {
+	table := NewPriceTable(nil)
+	u := &Usage{
+		Model:            "claude-sonnet-4-20250514",
+		InputTokens:      1_000_000,
+		CacheReadTokens:  1_000_000,
+		CacheWriteTokens: 1_000_000,
+		OutputTokens:     1_000_000,
+	}
+	want := 3.0 + 0.3 + 3.75 + 15.0
+	if got := table.Cost(u); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+	if got := table.Cost(nil); got != 0 {
+		t.Errorf("Cost(nil) = %v, want 0", got)
+	}
+}
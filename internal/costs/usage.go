@@ -0,0 +1,78 @@
+// Package costs ingests token usage from agent session logs, prices it, and
+// enforces spend budgets.
+//
+// Each agent preset names the session log format it writes
+// (AgentPresetInfo.UsageFormat). This package knows how to find the most
+// recent session for a working directory in each format and sum its usage:
+//
+//   - claude   → ~/.claude/projects/<encoded-workdir>/*.jsonl
+//   - codex    → ~/.codex/sessions/**/rollout-*.jsonl
+//   - gemini   → ~/.gemini/tmp/<sha256(workdir)>/chats/session-*.json
+//   - opencode → ~/.local/share/opencode/storage/message/<session>/*.json
+//
+// Usage is converted to USD with a PriceTable (built-in prices plus the
+// costs.pricing overrides in town settings). Costs are appended to the
+// ~/.gt/costs.jsonl ledger, which budget checks read back.
+package costs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Supported usage formats.
+const (
+	FormatClaude   = "claude"
+	FormatCodex    = "codex"
+	FormatGemini   = "gemini"
+	FormatOpenCode = "opencode"
+)
+
+// ErrUnsupportedFormat is returned for agents whose usage cannot be read.
+var ErrUnsupportedFormat = errors.New("unsupported usage format")
+
+// ErrNoSession is returned when no session log exists for a working directory.
+var ErrNoSession = errors.New("no session log found")
+
+// Usage is the token usage of one agent session.
+type Usage struct {
+	Model            string `json:"model,omitempty"`
+	InputTokens      int    `json:"input_tokens"`       // uncached input
+	CacheReadTokens  int    `json:"cache_read_tokens"`  // input served from cache
+	CacheWriteTokens int    `json:"cache_write_tokens"` // input written to cache
+	OutputTokens     int    `json:"output_tokens"`      // including reasoning
+}
+
+// ingesters maps usage formats to readers. Each reader finds the most recent
+// session for workDir under home and sums its usage.
+var ingesters = map[string]func(home, workDir string) (*Usage, error){
+	FormatClaude:   claudeUsage,
+	FormatCodex:    codexUsage,
+	FormatGemini:   geminiUsage,
+	FormatOpenCode: openCodeUsage,
+}
+
+// SessionUsage returns the usage of the most recent session in workDir,
+// read from the session logs of the given format.
+func SessionUsage(format, workDir string) (*Usage, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("finding home directory: %w", err)
+	}
+	return sessionUsageIn(home, format, workDir)
+}
+
+func sessionUsageIn(home, format, workDir string) (*Usage, error) {
+	ingest, ok := ingesters[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	return ingest(home, workDir)
+}
+
+// Supported reports whether usage can be read for a format.
+func Supported(format string) bool {
+	_, ok := ingesters[format]
+	return ok
+}
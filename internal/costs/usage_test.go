@@ -0,0 +1,115 @@
+package costs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionUsage_Claude(t *testing.T) {
+	home := t.TempDir()
+	workDir := "/town/gastown/polecats/toast"
+	writeFile(t, filepath.Join(ClaudeProjectDir(home, workDir), "s1.jsonl"), strings.Join([]string{
+		`{"type":"user","message":{"content":"hi"}}`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":100,"cache_creation_input_tokens":10,"cache_read_input_tokens":1000,"output_tokens":50}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":200,"cache_read_input_tokens":2000,"output_tokens":25}}}`,
+	}, "\n"))
+
+	u, err := sessionUsageIn(home, FormatClaude, workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Usage{Model: "claude-sonnet-4-20250514", InputTokens: 300, CacheReadTokens: 3000, CacheWriteTokens: 10, OutputTokens: 75}
+	if *u != want {
+		t.Errorf("usage = %+v, want %+v", *u, want)
+	}
+}
+
+func TestSessionUsage_Codex(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".codex", "sessions", "2026", "10", "16")
+	writeFile(t, filepath.Join(dir, "rollout-other.jsonl"),
+		`{"type":"session_meta","payload":{"cwd":"/elsewhere"}}`+"\n")
+	writeFile(t, filepath.Join(dir, "rollout-mine.jsonl"), strings.Join([]string{
+		`{"type":"session_meta","payload":{"cwd":"/work"}}`,
+		`{"type":"turn_context","payload":{"cwd":"/work","model":"gpt-5"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":500,"cached_input_tokens":100,"output_tokens":40}}}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":900,"cached_input_tokens":300,"output_tokens":70}}}}`,
+	}, "\n"))
+
+	u, err := sessionUsageIn(home, FormatCodex, "/work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Usage{Model: "gpt-5", InputTokens: 600, CacheReadTokens: 300, OutputTokens: 70}
+	if *u != want {
+		t.Errorf("usage = %+v, want %+v", *u, want)
+	}
+
+	if _, err := sessionUsageIn(home, FormatCodex, "/nowhere"); !errors.Is(err, ErrNoSession) {
+		t.Errorf("unknown workdir: err = %v, want ErrNoSession", err)
+	}
+}
+
+func TestSessionUsage_Gemini(t *testing.T) {
+	home := t.TempDir()
+	writeFile(t, filepath.Join(GeminiChatsDir(home, "/work"), "session-1.json"), `{"messages":[
+		{"type":"user"},
+		{"type":"gemini","model":"gemini-2.5-pro","tokens":{"input":1000,"output":20,"cached":400,"thoughts":30}},
+		{"type":"gemini","model":"gemini-2.5-pro","tokens":{"input":1200,"output":10,"cached":1000,"thoughts":0}}
+	]}`)
+
+	u, err := sessionUsageIn(home, FormatGemini, "/work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Usage{Model: "gemini-2.5-pro", InputTokens: 800, CacheReadTokens: 1400, OutputTokens: 60}
+	if *u != want {
+		t.Errorf("usage = %+v, want %+v", *u, want)
+	}
+}
+
+func TestSessionUsage_OpenCode(t *testing.T) {
+	home := t.TempDir()
+	root := filepath.Join(home, ".local", "share", "opencode", "storage", "message")
+	writeFile(t, filepath.Join(root, "ses_other", "msg_1.json"),
+		`{"role":"assistant","modelID":"x","path":{"cwd":"/elsewhere"},"tokens":{"input":9,"output":9}}`)
+	writeFile(t, filepath.Join(root, "ses_mine", "msg_1.json"), `{"role":"user"}`)
+	writeFile(t, filepath.Join(root, "ses_mine", "msg_2.json"),
+		`{"role":"assistant","modelID":"claude-sonnet-4","path":{"cwd":"/work"},"tokens":{"input":10,"output":5,"reasoning":2,"cache":{"read":100,"write":7}}}`)
+
+	u, err := sessionUsageIn(home, FormatOpenCode, "/work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Usage{Model: "claude-sonnet-4", InputTokens: 10, CacheReadTokens: 100, CacheWriteTokens: 7, OutputTokens: 7}
+	if *u != want {
+		t.Errorf("usage = %+v, want %+v", *u, want)
+	}
+}
+
+func TestSessionUsage_Errors(t *testing.T) {
+	home := t.TempDir()
+	if _, err := sessionUsageIn(home, "aider", "/work"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("unsupported format: err = %v, want ErrUnsupportedFormat", err)
+	}
+	if _, err := sessionUsageIn(home, FormatClaude, "/work"); !errors.Is(err, ErrNoSession) {
+		t.Errorf("missing transcript: err = %v, want ErrNoSession", err)
+	}
+	if Supported("aider") || !Supported(FormatGemini) {
+		t.Error("Supported() disagrees with registered ingesters")
+	}
+}
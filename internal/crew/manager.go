@@ -764,7 +764,7 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		}
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName(name)
 
 	// Check if session already exists — kill AFTER command is fully built
@@ -807,12 +807,17 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
-	// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew")
+	// Theming, key bindings and dialog handling are tmux features; other
+	// backends skip them.
+	tm, isTmux := t.(*tmux.Tmux)
+	if isTmux {
+		// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
+		theme := tmux.AssignTheme(m.rig.Name)
+		_ = tm.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew")
 
-	// Set up C-b n/p keybindings for crew session cycling (non-fatal)
-	_ = t.SetCrewCycleBindings(sessionID)
+		// Set up C-b n/p keybindings for crew session cycling (non-fatal)
+		_ = tm.SetCrewCycleBindings(sessionID)
+	}
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
 	_ = session.TrackSessionPID(townRoot, sessionID, t)
@@ -820,7 +825,7 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	// Wait for the agent to start, then accept the bypass permissions warning
 	// dialog if it appears. Without this, crew sessions get stuck on the
 	// "Bypass Permissions mode" confirmation dialog.
-	if !opts.Interactive {
+	if !opts.Interactive && isTmux {
 		agentName := opts.AgentOverride
 		if agentName == "" {
 			if rc := config.ResolveRoleAgentConfig("crew", townRoot, m.rig.Path); rc != nil && rc.Provider != "" {
@@ -831,11 +836,11 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		}
 		preset := config.GetAgentPresetByName(agentName)
 		if preset != nil && preset.EmitsPermissionWarning {
-			if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+			if err := tm.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 				// Non-fatal — agent might still start
 				style.PrintWarning("timeout waiting for agent to start: %v", err)
 			}
-			_ = t.AcceptStartupDialogs(sessionID)
+			_ = tm.AcceptStartupDialogs(sessionID)
 		}
 	}
 
@@ -848,7 +853,7 @@ func (m *Manager) Stop(name string) error {
 		return err
 	}
 
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName(name)
 
 	// Check if session exists
@@ -872,7 +877,7 @@ func (m *Manager) Stop(name string) error {
 
 // IsRunning checks if a crew member's session is active.
 func (m *Manager) IsRunning(name string) (bool, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName(name)
 	return t.HasSession(sessionID)
 }
@@ -27,6 +27,7 @@ type strandedConvoyInfo struct {
 	Title       string   `json:"title"`
 	ReadyCount  int      `json:"ready_count"`
 	ReadyIssues []string `json:"ready_issues"`
+	WaitingOn   []string `json:"waiting_on,omitempty"`
 }
 
 // ConvoyManager monitors beads events for issue closes and periodically scans for stranded convoys.
@@ -351,11 +352,16 @@ func (m *ConvoyManager) findStranded() ([]strandedConvoyInfo, error) {
 // dispatches the first one that can be successfully slung. Issues are skipped
 // (with logging) when the prefix is unresolvable, the rig has no route, the
 // rig is parked, or the sling command fails. This ensures convoys progress
-// even when some issues target unavailable rigs.
+// even when some issues target unavailable rigs. Convoys waiting on upstream
+// convoys are not fed until those land.
 func (m *ConvoyManager) feedFirstReady(c strandedConvoyInfo) {
 	if len(c.ReadyIssues) == 0 {
 		return
 	}
+	if len(c.WaitingOn) > 0 {
+		m.logger("Convoy %s: waiting on upstream convoy(s) %v, not feeding", c.ID, c.WaitingOn)
+		return
+	}
 
 	for _, issueID := range c.ReadyIssues {
 		prefix := beads.ExtractPrefix(issueID)
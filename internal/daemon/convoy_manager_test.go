@@ -1942,3 +1942,41 @@ func TestPollStore_NilHqStore_LogsWarningAndSkips(t *testing.T) {
 		}
 	}
 }
+
+func TestFeedFirstReady_SkipsConvoyWaitingOnUpstream(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on Windows")
+	}
+
+	binDir := t.TempDir()
+	slingLogPath := filepath.Join(binDir, "sling.log")
+	gtScript := `#!/bin/sh
+echo "$@" >> "` + slingLogPath + `"
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(binDir, "gt"), []byte(gtScript), 0755); err != nil {
+		t.Fatalf("write mock gt: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var logged []string
+	logger := func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+	m := NewConvoyManager(t.TempDir(), logger, "gt", 10*time.Minute, nil, nil, nil)
+
+	m.feedFirstReady(strandedConvoyInfo{
+		ID:          "hq-cv-client",
+		Title:       "Client rollout",
+		ReadyCount:  1,
+		ReadyIssues: []string{"gt-issue1"},
+		WaitingOn:   []string{"hq-cv-api"},
+	})
+
+	if _, err := os.Stat(slingLogPath); err == nil {
+		t.Error("convoy waiting on an upstream convoy should not be fed")
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], "hq-cv-api") {
+		t.Errorf("expected a waiting log naming the upstream convoy, got: %v", logged)
+	}
+}
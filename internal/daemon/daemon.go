@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -44,7 +45,7 @@ import (
 type Daemon struct {
 	config        *Config
 	patrolConfig  *DaemonPatrolConfig
-	tmux          *tmux.Tmux
+	tmux          session.Backend
 	logger        *log.Logger
 	ctx           context.Context
 	cancel        context.CancelFunc
@@ -64,6 +65,10 @@ type Daemon struct {
 	// Note: Only accessed from heartbeat loop goroutine - no sync needed.
 	deaconLastStarted time.Time
 
+	// startedAt is when Run began. Plugin event gates on "startup" fire
+	// once per daemon start.
+	startedAt time.Time
+
 	// syncFailures tracks consecutive git pull failures per workdir.
 	// Used to escalate logging from WARN to ERROR after repeated failures.
 	// Only accessed from heartbeat loop goroutine - no sync needed.
@@ -80,6 +85,11 @@ type Daemon struct {
 	// Nil when telemetry is disabled (GT_OTEL_METRICS_URL / GT_OTEL_LOGS_URL not set).
 	otelProvider *telemetry.Provider
 	metrics      *daemonMetrics
+
+	// metricsAddr is the Prometheus /metrics listen address from
+	// mayor/config.json (daemon.metrics_addr). Empty when disabled.
+	metricsAddr   string
+	metricsServer *metricsServer
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -167,13 +177,16 @@ func New(config *Config) (*Daemon, error) {
 	if otelErr != nil {
 		logger.Printf("Warning: telemetry init failed: %v", otelErr)
 	}
+	// The scrape endpoint reads the same instruments, so they are registered
+	// when either OTel export or the endpoint is enabled.
+	promAddr := metricsAddr(config.TownRoot)
 	var dm *daemonMetrics
-	if otelProvider != nil {
+	if otelProvider != nil || promAddr != "" {
 		dm, err = newDaemonMetrics()
 		if err != nil {
 			logger.Printf("Warning: failed to register daemon metrics: %v", err)
 			dm = nil
-		} else {
+		} else if otelProvider != nil {
 			metricsURL := os.Getenv(telemetry.EnvMetricsURL)
 			if metricsURL == "" {
 				metricsURL = telemetry.DefaultMetricsURL
@@ -190,7 +203,7 @@ func New(config *Config) (*Daemon, error) {
 	return &Daemon{
 		config:         config,
 		patrolConfig:   patrolConfig,
-		tmux:           tmux.NewTmux(),
+		tmux:           session.NewBackend(),
 		logger:         logger,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -200,6 +213,7 @@ func New(config *Config) (*Daemon, error) {
 		restartTracker: restartTracker,
 		otelProvider:   otelProvider,
 		metrics:        dm,
+		metricsAddr:    promAddr,
 	}, nil
 }
 
@@ -244,10 +258,11 @@ func (d *Daemon) Run() error {
 	defer func() { _ = os.Remove(d.config.PidFile) }() // best-effort cleanup
 
 	// Update state
+	d.startedAt = time.Now()
 	state := &State{
 		Running:   true,
 		PID:       os.Getpid(),
-		StartedAt: time.Now(),
+		StartedAt: d.startedAt,
 	}
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
@@ -264,6 +279,17 @@ func (d *Daemon) Run() error {
 
 	d.logger.Printf("Daemon running, recovery heartbeat interval %v", recoveryHeartbeatInterval)
 
+	// Start Prometheus scrape endpoint if configured (daemon.metrics_addr).
+	if d.metricsAddr != "" {
+		ms, err := d.startMetricsServer(d.metricsAddr)
+		if err != nil {
+			d.logger.Printf("Warning: failed to start metrics endpoint: %v", err)
+		} else {
+			d.metricsServer = ms
+			d.logger.Printf("Metrics endpoint listening on http://%s/metrics", d.metricsAddr)
+		}
+	}
+
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
 	if err := d.curator.Start(); err != nil {
@@ -330,6 +356,19 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Dolt remotes push ticker started (interval %v)", interval)
 	}
 
+	// Start the scheduled mail ticker: releases deferred messages and
+	// archives expired ones. Delivery times are minute-grained, so this runs
+	// well inside the heartbeat (default 30s).
+	var mailTicker *time.Ticker
+	var mailChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "mail") {
+		interval := mailPatrolInterval(d.patrolConfig)
+		mailTicker = time.NewTicker(interval)
+		mailChan = mailTicker.C
+		defer mailTicker.Stop()
+		d.logger.Printf("Scheduled mail ticker started (interval %v)", interval)
+	}
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -367,6 +406,11 @@ func (d *Daemon) Run() error {
 				d.pushDoltRemotes()
 			}
 
+		case <-mailChan:
+			if !d.isShutdownInProgress() {
+				d.deliverScheduledMail()
+			}
+
 		case <-timer.C:
 			d.heartbeat(state)
 
@@ -491,6 +535,18 @@ func (d *Daemon) heartbeat(state *State) {
 	// Shells out to `gt scheduler run` to avoid circular import between daemon and cmd.
 	d.dispatchQueuedWork()
 
+	// 15. Re-escalate unacknowledged escalations past their SLA.
+	// Shells out to `gt escalate stale` for the same reason as dispatch.
+	if IsPatrolEnabled(d.patrolConfig, "escalation") {
+		d.trackEscalations()
+	}
+
+	// 16. Rotate rate-limited sessions and fail over exhausted providers.
+	// Opt-in: rotation restarts sessions, so it only runs when enabled.
+	if IsPatrolEnabled(d.patrolConfig, "quota") {
+		d.rotateQuota()
+	}
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -591,19 +647,23 @@ func (d *Daemon) ensureBootRunning() {
 	b := boot.New(d.config.TownRoot)
 
 	// Boot is ephemeral - always spawn fresh each tick.
-	// spawnTmux() kills any existing session before spawning, ensuring
+	// spawnSession() kills any existing session before spawning, ensuring
 	// Boot never accumulates context across triage cycles.
 
-	// Check for degraded mode
+	// Check for degraded mode. Only a tmux backend can be missing its
+	// server; the pty supervisor is started on demand.
 	degraded := os.Getenv("GT_DEGRADED") == "true"
-	if degraded || !d.tmux.IsAvailable() {
+	if tm, ok := d.tmux.(*tmux.Tmux); ok && !tm.IsAvailable() {
+		degraded = true
+	}
+	if degraded {
 		// In degraded mode, run mechanical triage directly
 		d.logger.Println("Degraded mode: running mechanical Boot triage")
 		d.runDegradedBootTriage(b)
 		return
 	}
 
-	// Spawn Boot in a fresh tmux session
+	// Spawn Boot in a fresh session
 	d.logger.Println("Spawning Boot for triage...")
 	if err := b.Spawn(""); err != nil {
 		d.logger.Printf("Error spawning Boot: %v, falling back to direct Deacon check", err)
@@ -1133,6 +1193,14 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("KRC pruner stopped")
 	}
 
+	// Stop metrics endpoint
+	if d.metricsServer != nil {
+		if err := d.metricsServer.Close(); err != nil {
+			d.logger.Printf("Warning: stopping metrics endpoint: %v", err)
+		}
+		d.metricsServer = nil
+	}
+
 	// Push Dolt remotes before stopping the server (if patrol is enabled)
 	d.pushDoltRemotes()
 
@@ -1622,12 +1690,6 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	// Pre-sync workspace (ensure beads are current)
 	d.syncWorkspace(workDir)
 
-	// Create new tmux session
-	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
-	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
-		return fmt.Errorf("creating session: %w", err)
-	}
-
 	// Set environment variables using centralized AgentEnv
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:      "polecat",
@@ -1636,6 +1698,21 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 		TownRoot:  d.config.TownRoot,
 	})
 
+	// Launch Claude with environment exported inline
+	// Pass rigPath so rig agent settings are honored (not town-level defaults)
+	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
+
+	// Create new session
+	tm, isTmux := d.tmux.(*tmux.Tmux)
+	if isTmux {
+		// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
+		if err := tm.EnsureSessionFresh(sessionName, workDir); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+	} else if err := d.newBackendSession(sessionName, workDir, startCmd); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
 	for k, v := range envVars {
 		_ = d.tmux.SetEnvironment(sessionName, k, v)
@@ -1654,27 +1731,28 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	processNames := config.ResolveProcessNames(rc.ResolvedAgent, rc.Command)
 	_ = d.tmux.SetEnvironment(sessionName, "GT_PROCESS_NAMES", strings.Join(processNames, ","))
 
+	if !isTmux {
+		return nil
+	}
+
 	// Apply theme
 	theme := tmux.AssignTheme(rigName)
-	_ = d.tmux.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
+	_ = tm.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
 
 	// Set pane-died hook for future crash detection
 	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
-	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
+	_ = tm.SetPaneDiedHook(sessionName, agentID)
 
-	// Launch Claude with environment exported inline
-	// Pass rigPath so rig agent settings are honored (not town-level defaults)
-	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
-	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
+	if err := tm.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
 
 	// Wait for Claude to start, then accept bypass permissions warning if it appears.
 	// This ensures automated restarts aren't blocked by the warning dialog.
-	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if err := tm.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
-	_ = d.tmux.AcceptStartupDialogs(sessionName)
+	_ = tm.AcceptStartupDialogs(sessionName)
 
 	return nil
 }
@@ -1796,3 +1874,55 @@ func (d *Daemon) dispatchQueuedWork() {
 		d.logger.Printf("Scheduler dispatch: %s", string(out))
 	}
 }
+
+// rotateQuota shells out to `gt quota rotate --auto`, which releases accounts
+// past their reset time, rotates limited sessions and fails over sessions
+// whose provider has no accounts left. Like dispatch, this avoids importing cmd.
+func (d *Daemon) rotateQuota() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "quota", "rotate", "--auto")
+	cmd.Dir = d.config.TownRoot
+	// The daemon's agent identity must not be mistaken for the rotated
+	// sessions' when their restart commands are built.
+	cmd.Env = append(envWithout(os.Environ(), "GT_AGENT", "GT_PROCESS_NAMES"), "GT_DAEMON=1")
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		d.logger.Printf("Quota rotation timed out after 5m")
+	} else if err != nil {
+		d.logger.Printf("Quota rotation failed: %v (output: %s)", err, string(out))
+	} else if trimmed := strings.TrimSpace(string(out)); trimmed != "" {
+		d.logger.Printf("Quota rotation: %s", trimmed)
+	}
+}
+
+// envWithout returns environ minus the given variables.
+func envWithout(environ []string, keys ...string) []string {
+	out := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if !slices.Contains(keys, name) {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+// trackEscalations re-routes stale unacknowledged escalations through their
+// next severity. Quiet hours and on-call windows are applied by the command,
+// so this only needs to run it regularly; a no-op pass is not logged.
+func (d *Daemon) trackEscalations() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, d.gtPath, "escalate", "stale", "--json")
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = append(os.Environ(), "GT_DAEMON=1")
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		d.logger.Printf("Escalation tracker timed out after 5m")
+	} else if err != nil {
+		d.logger.Printf("Escalation tracker failed: %v (output: %s)", err, string(out))
+	} else if trimmed := strings.TrimSpace(string(out)); trimmed != "" && trimmed != "[]" {
+		d.logger.Printf("Escalation tracker: %s", trimmed)
+	}
+}
@@ -402,3 +402,11 @@ func TestDaemon_StopsManagerAndScanner(t *testing.T) {
 		t.Fatal("Stop() did not complete within 5s")
 	}
 }
+
+func TestEnvWithout(t *testing.T) {
+	environ := []string{"PATH=/bin", "GT_AGENT=claude", "GT_AGENT_HOME=/x", "GT_PROCESS_NAMES=node,claude"}
+	got := envWithout(environ, "GT_AGENT", "GT_PROCESS_NAMES")
+	if want := []string{"PATH=/bin", "GT_AGENT_HOME=/x"}; !slices.Equal(got, want) {
+		t.Errorf("envWithout = %v, want %v", got, want)
+	}
+}
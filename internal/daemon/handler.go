@@ -181,8 +181,8 @@ func (d *Daemon) reapIdleDogs(mgr *dog.Manager, sm *dog.SessionManager) {
 	}
 }
 
-// dispatchPlugins scans for plugins, evaluates cooldown gates, and dispatches
-// eligible plugins to idle dogs.
+// dispatchPlugins scans for plugins, evaluates their gates (cooldown, cron,
+// condition, event), and dispatches eligible plugins to idle dogs.
 func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsConfig *config.RigsConfig) {
 	// Get rig names for scanner
 	var rigNames []string
@@ -206,22 +206,24 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 	recorder := plugin.NewRecorder(d.config.TownRoot)
 	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
 
+	gates := plugin.NewGateEvaluator(d.config.TownRoot, recorder)
+	if !d.startedAt.IsZero() {
+		gates.StartedAt = d.startedAt
+	}
+
 	for _, p := range plugins {
-		// Only dispatch plugins with cooldown gates.
-		if p.Gate == nil || p.Gate.Type != plugin.GateCooldown {
+		// Manual and ungated plugins are never auto-dispatched.
+		if p.Gate == nil || p.Gate.Type == plugin.GateManual {
 			continue
 		}
 
-		// Evaluate cooldown: skip if plugin ran recently.
-		if p.Gate.Duration != "" {
-			count, err := recorder.CountRunsSince(p.Name, p.Gate.Duration)
-			if err != nil {
-				d.logger.Printf("Handler: error checking cooldown for plugin %s: %v", p.Name, err)
-				continue
-			}
-			if count > 0 {
-				continue // Still in cooldown
-			}
+		decision, err := gates.Evaluate(p)
+		if err != nil {
+			d.logger.Printf("Handler: error evaluating %s gate for plugin %s: %v", p.Gate.Type, p.Name, err)
+			continue
+		}
+		if !decision.Open {
+			continue
 		}
 
 		// Find an idle dog.
@@ -267,7 +269,13 @@ func (d *Daemon) dispatchPlugins(mgr *dog.Manager, sm *dog.SessionManager, rigsC
 			// Session is already started — dog will find no mail and idle out.
 		}
 
-		d.logger.Printf("Handler: dispatched plugin %s to dog %s", p.Name, idleDog.Name)
+		// Advance cron/event bookkeeping only once the plugin is actually
+		// dispatched, so deferred plugins stay due.
+		if err := gates.Commit(p, decision); err != nil {
+			d.logger.Printf("Handler: failed to record gate state for plugin %s: %v", p.Name, err)
+		}
+
+		d.logger.Printf("Handler: dispatched plugin %s to dog %s (%s)", p.Name, idleDog.Name, decision.Reason)
 	}
 }
 
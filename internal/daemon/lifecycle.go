@@ -365,35 +365,58 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 		d.syncWorkspace(workDir)
 	}
 
+	// Get startup command
+	startCmd := d.getStartCommand(config, parsed)
+
 	// Create session
-	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
-	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
+	tm, isTmux := d.tmux.(*tmux.Tmux)
+	if isTmux {
+		// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
+		if err := tm.EnsureSessionFresh(sessionName, workDir); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+	} else if err := d.newBackendSession(sessionName, workDir, startCmd); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
 	// Set environment variables
 	d.setSessionEnvironment(sessionName, config, parsed)
 
+	if !isTmux {
+		return nil
+	}
+
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
-	d.applySessionTheme(sessionName, parsed)
+	d.applySessionTheme(tm, sessionName, parsed)
 
-	// Get and send startup command
-	startCmd := d.getStartCommand(config, parsed)
-	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
+	// Send startup command
+	if err := tm.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
 
 	// Wait for Claude to start, then accept bypass permissions warning if it appears.
 	// This ensures automated role starts aren't blocked by the warning dialog.
-	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if err := tm.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
-	_ = d.tmux.AcceptStartupDialogs(sessionName)
+	_ = tm.AcceptStartupDialogs(sessionName)
 	time.Sleep(constants.ShutdownNotifyDelay)
 
 	return nil
 }
 
+// newBackendSession replaces any existing session with a fresh one running
+// startCmd. Backends other than tmux run the agent as the session's own
+// command, so there is no shell to type it into.
+func (d *Daemon) newBackendSession(sessionName, workDir, startCmd string) error {
+	if running, _ := d.tmux.HasSession(sessionName); running {
+		if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+			return fmt.Errorf("killing stale session: %w", err)
+		}
+	}
+	return d.tmux.NewSessionWithCommand(sessionName, workDir, startCmd)
+}
+
 // getWorkDir determines the working directory for an agent.
 // Uses role config if available, falls back to hardcoded defaults.
 func (d *Daemon) getWorkDir(config *beads.RoleConfig, parsed *ParsedIdentity) string {
@@ -542,13 +565,13 @@ func (d *Daemon) setSessionEnvironment(sessionName string, roleConfig *beads.Rol
 }
 
 // applySessionTheme applies tmux theming to the session.
-func (d *Daemon) applySessionTheme(sessionName string, parsed *ParsedIdentity) {
+func (d *Daemon) applySessionTheme(tm *tmux.Tmux, sessionName string, parsed *ParsedIdentity) {
 	if parsed.RoleType == "mayor" {
 		theme := tmux.MayorTheme()
-		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, "", "Mayor", "coordinator")
+		_ = tm.ConfigureGasTownSession(sessionName, theme, "", "Mayor", "coordinator")
 	} else if parsed.RigName != "" {
 		theme := tmux.AssignTheme(parsed.RigName)
-		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, parsed.RigName, parsed.RoleType, parsed.RoleType)
+		_ = tm.ConfigureGasTownSession(sessionName, theme, parsed.RigName, parsed.RoleType, parsed.RoleType)
 	}
 }
 
@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -27,11 +28,20 @@ type daemonMetrics struct {
 	doltLatencyMs      float64
 	doltDiskBytes      int64
 	doltHealthy        int64 // 1 = healthy, 0 = unhealthy
+	doltObserved       bool  // set once a health snapshot has been taken
+
+	// localMu protects the counter values mirrored for the Prometheus
+	// scrape endpoint (see prometheus.go); OTel counters can't be read back.
+	localMu       sync.Mutex
+	heartbeats    int64
+	lastHeartbeat time.Time
+	restarts      map[string]int64 // agent type -> restarts
 }
 
 // newDaemonMetrics registers all daemon OTel instruments against the global
 // MeterProvider. Must be called after telemetry.Init so the provider is set.
-// Returns a no-op struct if no provider is configured.
+// Without a provider the instruments are no-ops, but the values mirrored for
+// the Prometheus scrape endpoint are still kept.
 func newDaemonMetrics() (*daemonMetrics, error) {
 	m := otel.GetMeterProvider().Meter(meterName)
 	dm := &daemonMetrics{}
@@ -114,6 +124,11 @@ func (dm *daemonMetrics) recordHeartbeat(ctx context.Context) {
 		return
 	}
 	dm.heartbeatTotal.Add(ctx, 1)
+
+	dm.localMu.Lock()
+	defer dm.localMu.Unlock()
+	dm.heartbeats++
+	dm.lastHeartbeat = time.Now()
 }
 
 // recordRestart increments the restart counter, labeled with the agent type
@@ -125,6 +140,13 @@ func (dm *daemonMetrics) recordRestart(ctx context.Context, agentType string) {
 	dm.restartTotal.Add(ctx, 1,
 		metric.WithAttributes(attribute.String("agent.type", agentType)),
 	)
+
+	dm.localMu.Lock()
+	defer dm.localMu.Unlock()
+	if dm.restarts == nil {
+		dm.restarts = make(map[string]int64)
+	}
+	dm.restarts[agentType]++
 }
 
 // updateDoltHealth stores the latest Dolt health snapshot for observable gauges.
@@ -143,4 +165,5 @@ func (dm *daemonMetrics) updateDoltHealth(conns, maxConns int64, latencyMs float
 	dm.doltLatencyMs = latencyMs
 	dm.doltDiskBytes = diskBytes
 	dm.doltHealthy = healthyInt
+	dm.doltObserved = true
 }
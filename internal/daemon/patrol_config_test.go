@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -79,6 +80,50 @@ func TestIsPatrolEnabled_DoltRemotes(t *testing.T) {
 	}
 }
 
+func TestIsPatrolEnabled_Quota(t *testing.T) {
+	// quota rotation restarts sessions, so it is opt-in like dolt_remotes
+	if IsPatrolEnabled(nil, "quota") {
+		t.Error("expected quota to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{},
+	}
+	if IsPatrolEnabled(config, "quota") {
+		t.Error("expected quota to be disabled by default")
+	}
+
+	config.Patrols.Quota = &PatrolConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "quota") {
+		t.Error("expected quota to be enabled when configured")
+	}
+}
+
+func TestMailPatrol(t *testing.T) {
+	// Scheduled mail delivery is on by default.
+	if !IsPatrolEnabled(nil, "mail") {
+		t.Error("expected mail patrol to be enabled with nil config")
+	}
+	if got := mailPatrolInterval(nil); got != defaultMailPatrolInterval {
+		t.Errorf("expected default interval %v, got %v", defaultMailPatrolInterval, got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{Mail: &PatrolConfig{Enabled: false, Interval: "1m"}},
+	}
+	if IsPatrolEnabled(config, "mail") {
+		t.Error("expected mail patrol to be disabled when configured off")
+	}
+	if got := mailPatrolInterval(config); got != time.Minute {
+		t.Errorf("expected 1m interval, got %v", got)
+	}
+
+	config.Patrols.Mail.Interval = "soon"
+	if got := mailPatrolInterval(config); got != defaultMailPatrolInterval {
+		t.Errorf("expected default for bad interval, got %v", got)
+	}
+}
+
 func TestDoltRemotesInterval(t *testing.T) {
 	// Default interval
 	if got := doltRemotesInterval(nil); got != defaultDoltRemotesInterval {
@@ -0,0 +1,396 @@
+package daemon
+
+// Prometheus scrape endpoint.
+//
+// The OTel instruments in metrics.go push to an OTLP endpoint. For monitoring
+// stacks that scrape instead, the daemon can serve the same signals plus town
+// queue depths in the Prometheus text exposition format. Enable it by setting
+// daemon.metrics_addr in mayor/config.json:
+//
+//	{"daemon": {"metrics_addr": "127.0.0.1:9464"}}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// scrapeCacheTTL bounds how often a scrape re-queries beads, tmux and the
+// scheduler. Prometheus typically scrapes every 15-60s; queue depths don't
+// need to be fresher than this.
+const scrapeCacheTTL = 30 * time.Second
+
+// scrapeSourceTimeout bounds each subprocess-backed source during a scrape.
+const scrapeSourceTimeout = 20 * time.Second
+
+// schedulerRigDepth is the per-rig part of `gt scheduler status --json`.
+type schedulerRigDepth struct {
+	Rig    string `json:"rig"`
+	Queued int    `json:"queued"`
+	Ready  int    `json:"ready"`
+}
+
+// schedulerDepth is the part of `gt scheduler status --json` exported.
+type schedulerDepth struct {
+	Queued int                 `json:"queued_total"`
+	Ready  int                 `json:"queued_ready"`
+	Paused bool                `json:"paused"`
+	Rigs   []schedulerRigDepth `json:"rigs"`
+}
+
+// Scrape sources, replaceable in tests.
+var (
+	// schedulerDepthFn shells out to gt (the scheduler lives in cmd, which
+	// the daemon can't import).
+	schedulerDepthFn = func(townRoot, gtPath string) (*schedulerDepth, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), scrapeSourceTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, gtPath, "scheduler", "status", "--json") //nolint:gosec // G204: gt is a trusted internal tool
+		cmd.Dir = townRoot
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, err
+		}
+		var status schedulerDepth
+		if err := json.Unmarshal(out, &status); err != nil {
+			return nil, fmt.Errorf("parsing scheduler status: %w", err)
+		}
+		return &status, nil
+	}
+
+	mergeQueueDepthFn = func(townRoot, rigName string) (int, error) {
+		r := &rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)}
+		return refinery.NewEngineer(r).QueueDepth()
+	}
+
+	mailBacklogFn = func(townRoot string) (map[string]int, error) {
+		return mail.NewRouter(townRoot).OpenMessageCounts()
+	}
+
+	listSessionsFn = func() ([]string, error) {
+		return tmux.NewTmux().ListSessions()
+	}
+)
+
+// scrapeSnapshot holds the town gauges collected for one scrape.
+type scrapeSnapshot struct {
+	at          time.Time
+	scheduler   *schedulerDepth
+	mergeQueue  map[string]int // rig -> open MRs
+	polecats    map[string]int // rig -> live polecat sessions
+	mailBacklog map[string]int // role -> open messages
+	errors      map[string]int // source -> 1 if collection failed
+}
+
+// metricsServer serves /metrics for a daemon.
+type metricsServer struct {
+	d    *Daemon
+	srv  *http.Server
+	rigs func() []string
+	now  func() time.Time
+
+	mu   sync.Mutex
+	last *scrapeSnapshot
+}
+
+// metricsAddr returns the configured scrape listen address, or "".
+func metricsAddr(townRoot string) string {
+	cfg, err := config.LoadMayorConfig(constants.MayorConfigPath(townRoot))
+	if err != nil || cfg.Daemon == nil {
+		return ""
+	}
+	return strings.TrimSpace(cfg.Daemon.MetricsAddr)
+}
+
+// startMetricsServer listens on addr and serves /metrics in the background.
+func (d *Daemon) startMetricsServer(addr string) (*metricsServer, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", addr, err)
+	}
+	ms := newMetricsServer(d)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", ms)
+	ms.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := ms.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("Warning: metrics server: %v", err)
+		}
+	}()
+	return ms, nil
+}
+
+func newMetricsServer(d *Daemon) *metricsServer {
+	return &metricsServer{d: d, rigs: d.getKnownRigs, now: time.Now}
+}
+
+// Close stops the server.
+func (ms *metricsServer) Close() error {
+	if ms == nil || ms.srv == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return ms.srv.Shutdown(ctx)
+}
+
+// ServeHTTP renders all metrics in the Prometheus text format (version 0.0.4).
+func (ms *metricsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	ms.write(w)
+}
+
+// snapshot returns the cached town gauges, collecting them if stale.
+// Holding mu while collecting makes concurrent scrapes share one collection.
+func (ms *metricsServer) snapshot() *scrapeSnapshot {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.last != nil && ms.now().Sub(ms.last.at) < scrapeCacheTTL {
+		return ms.last
+	}
+	ms.last = ms.collect()
+	return ms.last
+}
+
+// collect queries the scheduler, merge queues, tmux and mail.
+func (ms *metricsServer) collect() *scrapeSnapshot {
+	townRoot := ms.d.config.TownRoot
+	snap := &scrapeSnapshot{
+		at:          ms.now(),
+		mergeQueue:  make(map[string]int),
+		polecats:    make(map[string]int),
+		mailBacklog: make(map[string]int),
+		errors:      make(map[string]int),
+	}
+	fail := func(source string, err error) {
+		snap.errors[source] = 1
+		ms.d.logger.Printf("Warning: metrics: collecting %s: %v", source, err)
+	}
+
+	if status, err := schedulerDepthFn(townRoot, ms.d.gtPath); err != nil {
+		fail("scheduler", err)
+	} else {
+		snap.scheduler = status
+	}
+
+	rigs := ms.rigs()
+	for _, rigName := range rigs {
+		depth, err := mergeQueueDepthFn(townRoot, rigName)
+		if err != nil {
+			fail("merge_queue", err)
+			continue
+		}
+		snap.mergeQueue[rigName] = depth
+	}
+
+	if sessions, err := listSessionsFn(); err != nil {
+		fail("sessions", err)
+	} else {
+		for _, rigName := range rigs {
+			snap.polecats[rigName] = 0
+		}
+		for _, name := range sessions {
+			id, err := session.ParseSessionName(name)
+			if err != nil || id.Role != session.RolePolecat {
+				continue
+			}
+			snap.polecats[id.Rig]++
+		}
+	}
+
+	if counts, err := mailBacklogFn(townRoot); err != nil {
+		fail("mail", err)
+	} else {
+		for address, n := range counts {
+			role := "other"
+			if id, err := session.ParseAddress(address); err == nil {
+				role = string(id.Role)
+			}
+			snap.mailBacklog[role] += n
+		}
+	}
+	return snap
+}
+
+// write renders every metric family.
+func (ms *metricsServer) write(w io.Writer) {
+	p := &promWriter{w: w}
+
+	// Daemon heartbeat and restarts (mirrored from the OTel instruments).
+	dm := ms.d.metrics
+	var heartbeats int64
+	var lastHeartbeat time.Time
+	var restarts map[string]int64
+	if dm != nil {
+		dm.localMu.Lock()
+		heartbeats, lastHeartbeat = dm.heartbeats, dm.lastHeartbeat
+		restarts = make(map[string]int64, len(dm.restarts))
+		for k, v := range dm.restarts {
+			restarts[k] = v
+		}
+		dm.localMu.Unlock()
+	}
+	p.family("gastown_daemon_heartbeat_total", "counter", "Total number of daemon heartbeat cycles.")
+	p.sample("gastown_daemon_heartbeat_total", nil, float64(heartbeats))
+	if !lastHeartbeat.IsZero() {
+		p.family("gastown_daemon_last_heartbeat_timestamp_seconds", "gauge", "Unix time of the last daemon heartbeat.")
+		p.sample("gastown_daemon_last_heartbeat_timestamp_seconds", nil, float64(lastHeartbeat.Unix()))
+	}
+	if !ms.d.startedAt.IsZero() {
+		p.family("gastown_daemon_start_timestamp_seconds", "gauge", "Unix time the daemon started.")
+		p.sample("gastown_daemon_start_timestamp_seconds", nil, float64(ms.d.startedAt.Unix()))
+	}
+	p.family("gastown_daemon_restart_total", "counter", "Agent session restarts by the daemon, by agent type.")
+	for _, agentType := range sortedKeys(restarts) {
+		p.sample("gastown_daemon_restart_total", []string{"agent_type", agentType}, float64(restarts[agentType]))
+	}
+
+	// Restart tracker: backoff state per agent (persisted across daemon runs).
+	if ms.d.restartTracker != nil {
+		agents := ms.d.restartTracker.Snapshot()
+		now := ms.now()
+		p.family("gastown_agent_restart_count", "gauge", "Restarts in the agent's current backoff window.")
+		for _, id := range sortedKeys(agents) {
+			p.sample("gastown_agent_restart_count", []string{"agent", id}, float64(agents[id].RestartCount))
+		}
+		p.family("gastown_agent_crash_loop", "gauge", "Whether the agent is crash-looping (1) or not (0).")
+		for _, id := range sortedKeys(agents) {
+			p.sample("gastown_agent_crash_loop", []string{"agent", id}, boolValue(!agents[id].CrashLoopSince.IsZero()))
+		}
+		p.family("gastown_agent_backoff_seconds", "gauge", "Seconds until the agent may be restarted again.")
+		for _, id := range sortedKeys(agents) {
+			remaining := agents[id].BackoffUntil.Sub(now).Seconds()
+			if remaining < 0 {
+				remaining = 0
+			}
+			p.sample("gastown_agent_backoff_seconds", []string{"agent", id}, remaining)
+		}
+	}
+
+	// Dolt health (updated by the Dolt health check).
+	if dm != nil {
+		dm.doltMu.RLock()
+		if dm.doltObserved {
+			p.family("gastown_dolt_healthy", "gauge", "Dolt server health (1=healthy, 0=unhealthy).")
+			p.sample("gastown_dolt_healthy", nil, float64(dm.doltHealthy))
+			p.family("gastown_dolt_connections", "gauge", "Active Dolt server connections.")
+			p.sample("gastown_dolt_connections", nil, float64(dm.doltConnections))
+			p.family("gastown_dolt_max_connections", "gauge", "Configured maximum Dolt server connections.")
+			p.sample("gastown_dolt_max_connections", nil, float64(dm.doltMaxConnections))
+			p.family("gastown_dolt_query_latency_seconds", "gauge", "Dolt SELECT 1 round-trip latency.")
+			p.sample("gastown_dolt_query_latency_seconds", nil, dm.doltLatencyMs/1000)
+			p.family("gastown_dolt_disk_usage_bytes", "gauge", "Dolt data directory disk usage.")
+			p.sample("gastown_dolt_disk_usage_bytes", nil, float64(dm.doltDiskBytes))
+		}
+		dm.doltMu.RUnlock()
+	}
+
+	// Town gauges (cached, see scrapeCacheTTL).
+	snap := ms.snapshot()
+	if s := snap.scheduler; s != nil {
+		p.family("gastown_scheduler_queue_depth", "gauge", "Beads waiting in the scheduler queue.")
+		p.sample("gastown_scheduler_queue_depth", nil, float64(s.Queued))
+		p.family("gastown_scheduler_queue_ready", "gauge", "Queued beads ready to dispatch.")
+		p.sample("gastown_scheduler_queue_ready", nil, float64(s.Ready))
+		p.family("gastown_scheduler_rig_queue_depth", "gauge", "Beads waiting in the scheduler queue, by target rig.")
+		for _, r := range s.Rigs {
+			p.sample("gastown_scheduler_rig_queue_depth", []string{"rig", r.Rig}, float64(r.Queued))
+		}
+		p.family("gastown_scheduler_paused", "gauge", "Whether scheduler dispatch is paused (1) or not (0).")
+		p.sample("gastown_scheduler_paused", nil, boolValue(s.Paused))
+	}
+	p.family("gastown_merge_queue_depth", "gauge", "Open merge requests, by rig.")
+	for _, rigName := range sortedKeys(snap.mergeQueue) {
+		p.sample("gastown_merge_queue_depth", []string{"rig", rigName}, float64(snap.mergeQueue[rigName]))
+	}
+	p.family("gastown_polecats_active", "gauge", "Live polecat sessions, by rig.")
+	for _, rigName := range sortedKeys(snap.polecats) {
+		p.sample("gastown_polecats_active", []string{"rig", rigName}, float64(snap.polecats[rigName]))
+	}
+	p.family("gastown_mail_backlog", "gauge", "Unread messages, by recipient role.")
+	for _, role := range sortedKeys(snap.mailBacklog) {
+		p.sample("gastown_mail_backlog", []string{"role", role}, float64(snap.mailBacklog[role]))
+	}
+	p.family("gastown_scrape_collector_errors", "gauge", "Whether collecting a source failed in the last collection (1) or not (0).")
+	for _, source := range []string{"mail", "merge_queue", "scheduler", "sessions"} {
+		p.sample("gastown_scrape_collector_errors", []string{"source", source}, float64(snap.errors[source]))
+	}
+}
+
+// promWriter writes the Prometheus text exposition format.
+type promWriter struct {
+	w io.Writer
+}
+
+func (p *promWriter) family(name, typ, help string) {
+	_, _ = fmt.Fprintf(p.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes one sample; labels are name/value pairs.
+func (p *promWriter) sample(name string, labels []string, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i])
+			b.WriteString(`="`)
+			b.WriteString(escapeLabelValue(labels[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	b.WriteByte('\n')
+	_, _ = io.WriteString(p.w, b.String())
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestMetricsAddr(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := metricsAddr(townRoot); got != "" {
+		t.Errorf("metricsAddr without config = %q, want empty", got)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{"type": "mayor-config", "version": 1, "daemon": {"metrics_addr": "127.0.0.1:9464"}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := metricsAddr(townRoot); got != "127.0.0.1:9464" {
+		t.Errorf("metricsAddr = %q, want 127.0.0.1:9464", got)
+	}
+}
+
+func TestMetricsServer_Scrape(t *testing.T) {
+	townRoot := t.TempDir()
+	d := testHandlerDaemon(t, townRoot)
+	d.startedAt = time.Unix(1700000000, 0)
+
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	oldReg := session.DefaultRegistry()
+	session.SetDefaultRegistry(reg)
+	t.Cleanup(func() { session.SetDefaultRegistry(oldReg) })
+
+	dm, err := newDaemonMetrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	dm.recordHeartbeat(ctx)
+	dm.recordHeartbeat(ctx)
+	dm.recordRestart(ctx, "witness")
+	dm.updateDoltHealth(3, 100, 2.5, 4096, true)
+	d.metrics = dm
+
+	d.restartTracker = NewRestartTracker(townRoot)
+	d.restartTracker.RecordRestart("gastown-witness")
+
+	oldSched, oldMQ, oldMail, oldSessions := schedulerDepthFn, mergeQueueDepthFn, mailBacklogFn, listSessionsFn
+	defer func() {
+		schedulerDepthFn, mergeQueueDepthFn, mailBacklogFn, listSessionsFn = oldSched, oldMQ, oldMail, oldSessions
+	}()
+	collections := 0
+	schedulerDepthFn = func(string, string) (*schedulerDepth, error) {
+		collections++
+		return &schedulerDepth{Queued: 4, Ready: 1, Rigs: []schedulerRigDepth{{Rig: "gastown", Queued: 4, Ready: 1}}}, nil
+	}
+	mergeQueueDepthFn = func(_, rigName string) (int, error) {
+		if rigName == "beads" {
+			return 0, errors.New("bd unavailable")
+		}
+		return 2, nil
+	}
+	mailBacklogFn = func(string) (map[string]int, error) {
+		return map[string]int{"mayor/": 3, "gastown/witness": 1}, nil
+	}
+	listSessionsFn = func() ([]string, error) {
+		return []string{"gt-nux", "gt-witness", "hq-mayor"}, nil
+	}
+
+	ms := newMetricsServer(d)
+	ms.rigs = func() []string { return []string{"beads", "gastown"} }
+	srv := httptest.NewServer(ms)
+	defer srv.Close()
+
+	scrape := func() string {
+		t.Helper()
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	body := scrape()
+
+	for _, want := range []string{
+		"# TYPE gastown_daemon_heartbeat_total counter",
+		"gastown_daemon_heartbeat_total 2\n",
+		"gastown_daemon_start_timestamp_seconds 1.7e+09\n",
+		`gastown_daemon_restart_total{agent_type="witness"} 1`,
+		`gastown_agent_restart_count{agent="gastown-witness"} 1`,
+		`gastown_agent_crash_loop{agent="gastown-witness"} 0`,
+		"gastown_dolt_healthy 1\n",
+		"gastown_dolt_connections 3\n",
+		"gastown_dolt_query_latency_seconds 0.0025\n",
+		"gastown_scheduler_queue_depth 4\n",
+		`gastown_scheduler_rig_queue_depth{rig="gastown"} 4`,
+		`gastown_merge_queue_depth{rig="gastown"} 2`,
+		`gastown_polecats_active{rig="beads"} 0`,
+		`gastown_polecats_active{rig="gastown"} 1`,
+		`gastown_mail_backlog{role="mayor"} 3`,
+		`gastown_mail_backlog{role="witness"} 1`,
+		`gastown_scrape_collector_errors{source="merge_queue"} 1`,
+		`gastown_scrape_collector_errors{source="scheduler"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("scrape missing %q\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `gastown_merge_queue_depth{rig="beads"}`) {
+		t.Error("failed merge queue source should not report a depth")
+	}
+
+	// A second scrape within the TTL reuses the collected snapshot.
+	scrape()
+	if collections != 1 {
+		t.Errorf("collections = %d, want 1 (cached)", collections)
+	}
+}
+
+func TestPromWriter_EscapesLabels(t *testing.T) {
+	var b strings.Builder
+	p := &promWriter{w: &b}
+	p.sample("m", []string{"a", "x\"y\\z\nw"}, 1.5)
+	if got, want := b.String(), `m{a="x\"y\\z\nw"} 1.5`+"\n"; got != want {
+		t.Errorf("sample = %q, want %q", got, want)
+	}
+}
@@ -201,3 +201,15 @@ func (rt *RestartTracker) ClearCrashLoop(agentID string) {
 		info.BackoffUntil = time.Time{}
 	}
 }
+
+// Snapshot returns a copy of the restart info of every tracked agent.
+func (rt *RestartTracker) Snapshot() map[string]AgentRestartInfo {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	out := make(map[string]AgentRestartInfo, len(rt.state.Agents))
+	for id, info := range rt.state.Agents {
+		out[id] = *info
+	}
+	return out
+}
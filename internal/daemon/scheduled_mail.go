@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+const defaultMailPatrolInterval = 30 * time.Second
+
+// mailPatrolInterval returns the configured mail patrol interval, or the
+// default (30s) when unset or unparseable.
+func mailPatrolInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Mail != nil {
+		if d, err := time.ParseDuration(config.Patrols.Mail.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultMailPatrolInterval
+}
+
+// deliverScheduledMail releases deferred messages that are due and archives
+// delivered messages past their expiry, so stale notices don't linger in
+// inboxes and queues. Non-fatal: errors are logged and retried next tick.
+func (d *Daemon) deliverScheduledMail() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	now := time.Now()
+
+	result, err := router.ReleaseScheduled(now)
+	if err != nil {
+		d.logger.Printf("mail: releasing scheduled messages: %v", err)
+	}
+	if result != nil {
+		for _, msg := range result.Released {
+			d.logger.Printf("mail: delivered scheduled message %s to %s", msg.ID, msg.To)
+		}
+		for _, msg := range result.Expired {
+			d.logger.Printf("mail: dropped scheduled message %s to %s (expired before delivery)", msg.ID, msg.To)
+		}
+	}
+
+	archived, err := router.ArchiveExpired(now)
+	if err != nil {
+		d.logger.Printf("mail: archiving expired messages: %v", err)
+	}
+	if len(archived) > 0 {
+		d.logger.Printf("mail: archived %d expired message(s)", len(archived))
+	}
+}
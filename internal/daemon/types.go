@@ -101,7 +101,8 @@ type PatrolConfig struct {
 	// Enabled controls whether this patrol runs during heartbeat.
 	Enabled bool `json:"enabled"`
 
-	// Interval is how often to run this patrol (not used yet).
+	// Interval is how often to run this patrol, as a Go duration.
+	// Only the mail patrol reads it so far.
 	Interval string `json:"interval,omitempty"`
 
 	// Agent is the agent type for this patrol (not used yet).
@@ -117,6 +118,9 @@ type PatrolsConfig struct {
 	Witness     *PatrolConfig      `json:"witness,omitempty"`
 	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
 	Handler     *PatrolConfig      `json:"handler,omitempty"`
+	Escalation  *PatrolConfig      `json:"escalation,omitempty"`
+	Quota       *PatrolConfig      `json:"quota,omitempty"`
+	Mail        *PatrolConfig      `json:"mail,omitempty"`
 	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
 	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
 }
@@ -176,7 +180,7 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
 // Returns true if the config doesn't exist (default enabled for backwards compatibility).
-// Exception: opt-in patrols (dolt_remotes) default to disabled.
+// Exception: opt-in patrols (dolt_remotes, quota) default to disabled.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 	// Opt-in patrols: disabled unless explicitly enabled in config.
 	// Must check before the nil-config fallback, otherwise nil config
@@ -187,6 +191,12 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.DoltRemotes.Enabled
 	}
+	if patrol == "quota" {
+		if config == nil || config.Patrols == nil || config.Patrols.Quota == nil {
+			return false
+		}
+		return config.Patrols.Quota.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
@@ -209,6 +219,14 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Handler != nil {
 			return config.Patrols.Handler.Enabled
 		}
+	case "escalation":
+		if config.Patrols.Escalation != nil {
+			return config.Patrols.Escalation.Enabled
+		}
+	case "mail":
+		if config.Patrols.Mail != nil {
+			return config.Patrols.Mail.Enabled
+		}
 	}
 	return true // Default: enabled
 }
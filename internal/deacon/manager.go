@@ -20,21 +20,26 @@ var (
 	ErrAlreadyRunning = errors.New("deacon already running")
 )
 
-// tmuxOps abstracts tmux operations for testing.
+// tmuxOps abstracts the session backend operations for testing.
 type tmuxOps interface {
 	HasSession(name string) (bool, error)
 	IsAgentAlive(session string) bool
 	KillSessionWithProcesses(name string) error
 	NewSessionWithCommand(name, workDir, command string) error
-	SetRemainOnExit(pane string, on bool) error
 	SetEnvironment(session, key, value string) error
+	SendKeysRaw(session, keys string) error
+}
+
+// tmuxFeatureOps are the tmux-only operations. Backends without them (the
+// pty supervisor) skip those steps.
+type tmuxFeatureOps interface {
+	SetRemainOnExit(pane string, on bool) error
 	ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
 	WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
 	SetAutoRespawnHook(session string) error
 	AcceptStartupDialogs(session string) error
 	AcceptWorkspaceTrustDialog(session string) error
 	AcceptBypassPermissionsWarning(session string) error
-	SendKeysRaw(session, keys string) error
 	GetSessionInfo(name string) (*tmux.SessionInfo, error)
 }
 
@@ -48,7 +53,7 @@ type Manager struct {
 func NewManager(townRoot string) *Manager {
 	return &Manager{
 		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		tmux:     session.NewBackend(),
 	}
 }
 
@@ -123,10 +128,14 @@ func (m *Manager) Start(agentOverride string) error {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
+	tf, isTmux := t.(tmuxFeatureOps)
+
 	// PATCH-010: Set remain-on-exit IMMEDIATELY after session creation.
 	// This ensures the pane stays if Claude exits before hooks are fully set.
 	// The pane will show "[Exited]" status but remain available for respawn.
-	_ = t.SetRemainOnExit(sessionID, true)
+	if isTmux {
+		_ = tf.SetRemainOnExit(sessionID, true)
+	}
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
@@ -140,34 +149,38 @@ func (m *Manager) Start(agentOverride string) error {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
 
-	// Apply Deacon theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.DeaconTheme()
-	_ = t.ConfigureGasTownSession(sessionID, theme, "", "Deacon", "health-check")
+	if isTmux {
+		// Apply Deacon theming (non-fatal: theming failure doesn't affect operation)
+		theme := tmux.DeaconTheme()
+		_ = tf.ConfigureGasTownSession(sessionID, theme, "", "Deacon", "health-check")
 
-	// Wait for Claude to start - fatal if Claude fails to launch
-	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-		// Kill the zombie session before returning error
-		_ = t.KillSessionWithProcesses(sessionID)
-		return fmt.Errorf("waiting for deacon to start: %w", err)
+		// Wait for Claude to start - fatal if Claude fails to launch
+		if err := tf.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+			// Kill the zombie session before returning error
+			_ = t.KillSessionWithProcesses(sessionID)
+			return fmt.Errorf("waiting for deacon to start: %w", err)
+		}
 	}
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
-	if realTmux, ok := t.(*tmux.Tmux); ok {
-		_ = session.TrackSessionPID(m.townRoot, sessionID, realTmux)
+	if backend, ok := t.(session.Backend); ok {
+		_ = session.TrackSessionPID(m.townRoot, sessionID, backend)
 	}
 
-	// PATCH-010: Set auto-respawn hook for Deacon resilience.
-	// When Claude exits (for any reason), tmux will automatically respawn it.
-	// This prevents the crash loop where daemon repeatedly restarts Deacon.
-	// Note: SetAutoRespawnHook calls SetRemainOnExit again (harmless, already set above).
-	if err := t.SetAutoRespawnHook(sessionID); err != nil {
-		// Non-fatal: Deacon still works, just won't auto-respawn on crash
-		// Daemon will still restart it, but with a delay
-		fmt.Printf("warning: failed to set auto-respawn hook for deacon: %v\n", err)
-	}
+	if isTmux {
+		// PATCH-010: Set auto-respawn hook for Deacon resilience.
+		// When Claude exits (for any reason), tmux will automatically respawn it.
+		// This prevents the crash loop where daemon repeatedly restarts Deacon.
+		// Note: SetAutoRespawnHook calls SetRemainOnExit again (harmless, already set above).
+		if err := tf.SetAutoRespawnHook(sessionID); err != nil {
+			// Non-fatal: Deacon still works, just won't auto-respawn on crash
+			// Daemon will still restart it, but with a delay
+			fmt.Printf("warning: failed to set auto-respawn hook for deacon: %v\n", err)
+		}
 
-	// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
-	_ = t.AcceptStartupDialogs(sessionID)
+		// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
+		_ = tf.AcceptStartupDialogs(sessionID)
+	}
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 
@@ -220,5 +233,8 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	if tf, ok := t.(tmuxFeatureOps); ok {
+		return tf.GetSessionInfo(sessionID)
+	}
+	return &tmux.SessionInfo{Name: sessionID, Windows: 1}, nil
 }
@@ -102,6 +102,7 @@ func (d *Doctor) RunStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 		report.Add(result)
 	}
 
+	report.Duration = time.Since(report.Timestamp)
 	return report
 }
 
@@ -230,6 +231,7 @@ func (d *Doctor) FixStreaming(ctx *CheckContext, w io.Writer, slowThreshold time
 		report.Add(result)
 	}
 
+	report.Duration = time.Since(report.Timestamp)
 	return report
 }
 
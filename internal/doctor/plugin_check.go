@@ -0,0 +1,302 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// PluginDir is the directory, at the town root or in a rig, that holds
+// external doctor checks.
+const PluginDir = "doctor.d"
+
+// DefaultPluginTimeout bounds a single external check (or fix) run.
+const DefaultPluginTimeout = 30 * time.Second
+
+// pluginWaitDelay is how long a timed-out plugin's output pipes may stay
+// open before they are closed on it.
+const pluginWaitDelay = 2 * time.Second
+
+// PluginResult is the JSON object an external check prints on stdout.
+//
+//	{"status": "ok|warning|error", "message": "...", "details": ["..."],
+//	 "fix_hint": "...", "fixable": true}
+//
+// Only status is required. An executable that reports fixable is run again
+// with --fix under gt doctor --fix.
+type PluginResult struct {
+	Status  string   `json:"status"`
+	Message string   `json:"message,omitempty"`
+	Details []string `json:"details,omitempty"`
+	FixHint string   `json:"fix_hint,omitempty"`
+	Fixable bool     `json:"fixable,omitempty"`
+}
+
+// PluginSpec declares an external check in a doctor.d/*.toml file:
+//
+//	name = "disk-space"
+//	description = "Town volume has room for new worktrees"
+//	category = "Infrastructure"
+//	command = "scripts/check-disk.sh"
+//	fix = "scripts/prune-worktrees.sh"
+//	timeout = "10s"
+//
+// Commands run through sh -c with the doctor.d directory as the working
+// directory and must print a PluginResult.
+type PluginSpec struct {
+	Name        string `toml:"name"`
+	Description string `toml:"description"`
+	Category    string `toml:"category"`
+	Command     string `toml:"command"`
+	Fix         string `toml:"fix"`
+	Timeout     string `toml:"timeout"`
+}
+
+// PluginCheck runs an external check discovered in a doctor.d directory.
+// Plugins see GT_TOWN_ROOT, GT_RIG (rig plugins only) and GT_DOCTOR_VERBOSE.
+type PluginCheck struct {
+	BaseCheck
+	dir     string   // doctor.d directory (working directory)
+	source  string   // plugin file
+	rig     string   // owning rig, empty for town plugins
+	argv    []string // check command
+	fixArgv []string // fix command, nil if not fixable
+	fixAll  bool     // fix command applies to any problem (declared in TOML)
+	timeout time.Duration
+	fixable bool // last run reported a fixable problem
+}
+
+// CanFix reports whether the last run found a problem the plugin can fix.
+func (c *PluginCheck) CanFix() bool {
+	return c.fixArgv != nil && c.fixable
+}
+
+// Run executes the plugin and translates its JSON result.
+func (c *PluginCheck) Run(ctx *CheckContext) *CheckResult {
+	c.fixable = false
+	result := &CheckResult{Name: c.CheckName, Category: c.CheckCategory, Source: c.source}
+
+	stdout, stderr, runErr := c.exec(ctx, c.argv)
+	var pr PluginResult
+	if err := json.Unmarshal(bytes.TrimSpace(stdout), &pr); err != nil {
+		result.Status = StatusError
+		if runErr != nil {
+			result.Message = "plugin failed: " + runErr.Error()
+		} else {
+			result.Message = "plugin did not print a JSON result"
+		}
+		if s := strings.TrimSpace(string(stderr)); s != "" {
+			result.Details = strings.Split(s, "\n")
+		}
+		result.FixHint = "See " + c.source
+		return result
+	}
+
+	switch strings.ToLower(pr.Status) {
+	case "ok", "pass":
+		result.Status = StatusOK
+	case "warning", "warn":
+		result.Status = StatusWarning
+	case "error", "fail":
+		result.Status = StatusError
+	default:
+		result.Status = StatusError
+		result.Message = fmt.Sprintf("plugin reported unknown status %q", pr.Status)
+		return result
+	}
+	result.Message = pr.Message
+	result.Details = pr.Details
+	result.FixHint = pr.FixHint
+	c.fixable = result.Status != StatusOK && (pr.Fixable || c.fixAll)
+	return result
+}
+
+// Fix runs the plugin's fix command.
+func (c *PluginCheck) Fix(ctx *CheckContext) error {
+	if c.fixArgv == nil {
+		return ErrCannotFix
+	}
+	if _, stderr, err := c.exec(ctx, c.fixArgv); err != nil {
+		if s := strings.TrimSpace(string(stderr)); s != "" {
+			return fmt.Errorf("%w: %s", err, s)
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *PluginCheck) exec(ctx *CheckContext, argv []string) (stdout, stderr []byte, err error) {
+	runCtx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...) //nolint:gosec // G204: plugins are installed by the town operator
+	cmd.Dir = c.dir
+	cmd.Env = append(os.Environ(), "GT_TOWN_ROOT="+ctx.TownRoot)
+	if c.rig != "" {
+		cmd.Env = append(cmd.Env, "GT_RIG="+c.rig)
+	}
+	if ctx.Verbose {
+		cmd.Env = append(cmd.Env, "GT_DOCTOR_VERBOSE=1")
+	}
+	// Kill the plugin's whole process group on timeout, and don't wait on
+	// pipes a surviving grandchild still holds, so doctor never hangs.
+	util.SetProcessGroup(cmd)
+	cmd.WaitDelay = pluginWaitDelay
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	err = cmd.Run()
+	if runCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+	return out.Bytes(), errOut.Bytes(), err
+}
+
+// DiscoverPluginChecks loads external checks from <town>/doctor.d and from
+// doctor.d in each rig (only rigName's when set). Town checks keep their
+// name; rig checks are named "<rig>/<name>". Files that cannot be loaded
+// become failing checks so a broken plugin is never silently skipped.
+func DiscoverPluginChecks(townRoot, rigName string) []Check {
+	checks := loadPluginDir(filepath.Join(townRoot, PluginDir), "")
+
+	var rigPaths []string
+	if rigName != "" {
+		rigPaths = []string{filepath.Join(townRoot, rigName)}
+	} else {
+		rigPaths = findAllRigs(townRoot)
+	}
+	for _, rigPath := range rigPaths {
+		checks = append(checks, loadPluginDir(filepath.Join(rigPath, PluginDir), filepath.Base(rigPath))...)
+	}
+	return checks
+}
+
+// loadPluginDir loads the plugins in one doctor.d directory, in name order:
+// *.toml declarations and executable files. Other files are ignored.
+func loadPluginDir(dir, rig string) []Check {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var checks []Check
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+
+		var check *PluginCheck
+		var loadErr error
+		if filepath.Ext(name) == ".toml" {
+			check, loadErr = loadPluginSpec(path)
+		} else {
+			info, err := entry.Info()
+			if err != nil || info.Mode()&0111 == 0 {
+				continue
+			}
+			check = &PluginCheck{
+				BaseCheck: BaseCheck{CheckName: strings.TrimSuffix(name, filepath.Ext(name))},
+				argv:      []string{path},
+				fixArgv:   []string{path, "--fix"},
+				timeout:   DefaultPluginTimeout,
+			}
+		}
+		if loadErr != nil {
+			checks = append(checks, &brokenPluginCheck{
+				BaseCheck: BaseCheck{CheckName: pluginCheckName(rig, strings.TrimSuffix(name, ".toml")), CheckCategory: CategoryPlugins},
+				source:    path,
+				err:       loadErr,
+			})
+			continue
+		}
+
+		check.CheckName = pluginCheckName(rig, check.CheckName)
+		if check.CheckDescription == "" {
+			check.CheckDescription = "External check " + path
+		}
+		if check.CheckCategory == "" {
+			check.CheckCategory = CategoryPlugins
+		}
+		check.dir = dir
+		check.source = path
+		check.rig = rig
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// loadPluginSpec parses a TOML-declared check.
+func loadPluginSpec(path string) (*PluginCheck, error) {
+	var spec PluginSpec
+	if _, err := toml.DecodeFile(path, &spec); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	if spec.Command == "" {
+		return nil, errors.New("missing command")
+	}
+	if spec.Name == "" {
+		spec.Name = strings.TrimSuffix(filepath.Base(path), ".toml")
+	}
+	timeout := DefaultPluginTimeout
+	if spec.Timeout != "" {
+		d, err := time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %w", err)
+		}
+		timeout = d
+	}
+
+	check := &PluginCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        spec.Name,
+			CheckDescription: spec.Description,
+			CheckCategory:    spec.Category,
+		},
+		argv:    []string{"sh", "-c", spec.Command},
+		timeout: timeout,
+	}
+	if spec.Fix != "" {
+		check.fixArgv = []string{"sh", "-c", spec.Fix}
+		check.fixAll = true
+	}
+	return check, nil
+}
+
+func pluginCheckName(rig, name string) string {
+	if rig == "" {
+		return name
+	}
+	return rig + "/" + name
+}
+
+// brokenPluginCheck reports a doctor.d file that could not be loaded.
+type brokenPluginCheck struct {
+	BaseCheck
+	source string
+	err    error
+}
+
+func (c *brokenPluginCheck) Run(ctx *CheckContext) *CheckResult {
+	return &CheckResult{
+		Name:     c.CheckName,
+		Category: c.CheckCategory,
+		Source:   c.source,
+		Status:   StatusError,
+		Message:  "invalid plugin: " + c.err.Error(),
+		FixHint:  "Fix or remove " + c.source,
+	}
+}
@@ -0,0 +1,133 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writePlugin(t *testing.T, dir, name, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverPluginChecks(t *testing.T) {
+	townRoot := t.TempDir()
+	townDir := filepath.Join(townRoot, PluginDir)
+	writePlugin(t, townDir, "disk.sh", "#!/bin/sh\necho '{\"status\":\"warning\",\"message\":\"low disk\",\"fixable\":true}'\n", 0755)
+	writePlugin(t, townDir, "README.md", "not a plugin", 0644)
+	writePlugin(t, townDir, "broken.toml", "name = \"broken\"\n", 0644)
+	writePlugin(t, townDir, "net.toml", `
+name = "net-ok"
+category = "Infrastructure"
+command = "echo '{\"status\":\"ok\",\"message\":\"reachable\"}'"
+`, 0644)
+
+	rigPath := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(filepath.Join(rigPath, "witness"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writePlugin(t, filepath.Join(rigPath, PluginDir), "rig.toml", `command = "echo '{\"status\":\"ok\",\"message\":\"'$GT_RIG'\"}'"`, 0644)
+
+	checks := DiscoverPluginChecks(townRoot, "")
+	var names []string
+	for _, c := range checks {
+		names = append(names, c.Name())
+	}
+	if got, want := strings.Join(names, ","), "broken,disk,net-ok,gastown/rig"; got != want {
+		t.Fatalf("checks = %s, want %s", got, want)
+	}
+
+	ctx := &CheckContext{TownRoot: townRoot}
+	results := make(map[string]*CheckResult)
+	for _, c := range checks {
+		results[c.Name()] = c.Run(ctx)
+	}
+
+	if r := results["broken"]; r.Status != StatusError || !strings.Contains(r.Message, "missing command") {
+		t.Errorf("broken = %v %q, want error about missing command", r.Status, r.Message)
+	}
+	if r := results["disk"]; r.Status != StatusWarning || r.Message != "low disk" || r.Category != CategoryPlugins {
+		t.Errorf("disk = %v %q %q", r.Status, r.Message, r.Category)
+	}
+	if !checks[1].CanFix() {
+		t.Error("disk reported fixable, CanFix() = false")
+	}
+	if r := results["net-ok"]; r.Status != StatusOK || r.Category != "Infrastructure" {
+		t.Errorf("net-ok = %v %q", r.Status, r.Category)
+	}
+	if checks[2].CanFix() {
+		t.Error("net-ok has no fix command, CanFix() = true")
+	}
+	if r := results["gastown/rig"]; r.Status != StatusOK || r.Message != "gastown" {
+		t.Errorf("gastown/rig = %v %q, want ok with GT_RIG", r.Status, r.Message)
+	}
+}
+
+func TestPluginCheck_InvalidOutput(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), PluginDir)
+	writePlugin(t, dir, "noisy", "#!/bin/sh\necho 'not json'\necho 'boom' >&2\nexit 3\n", 0755)
+
+	checks := loadPluginDir(dir, "")
+	if len(checks) != 1 {
+		t.Fatalf("got %d checks, want 1", len(checks))
+	}
+	r := checks[0].Run(&CheckContext{TownRoot: t.TempDir()})
+	if r.Status != StatusError || !strings.Contains(r.Message, "plugin failed") {
+		t.Errorf("status = %v %q, want plugin failed error", r.Status, r.Message)
+	}
+	if len(r.Details) != 1 || r.Details[0] != "boom" {
+		t.Errorf("details = %v, want stderr", r.Details)
+	}
+	if checks[0].CanFix() {
+		t.Error("CanFix() = true for a plugin that did not report fixable")
+	}
+}
+
+func TestPluginCheck_Fix(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), PluginDir)
+	writePlugin(t, dir, "marker.toml", `
+command = "test -f fixed && echo '{\"status\":\"ok\"}' || echo '{\"status\":\"error\",\"message\":\"missing\"}'"
+fix = "touch fixed"
+`, 0644)
+
+	check := loadPluginDir(dir, "")[0]
+	ctx := &CheckContext{TownRoot: t.TempDir()}
+	if r := check.Run(ctx); r.Status != StatusError {
+		t.Fatalf("first run = %v, want error", r.Status)
+	}
+	if !check.CanFix() {
+		t.Fatal("TOML fix command should make the failing check fixable")
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if r := check.Run(ctx); r.Status != StatusOK {
+		t.Errorf("after fix = %v %q, want ok", r.Status, r.Message)
+	}
+}
+
+func TestPluginCheck_TimeoutKillsGrandchildren(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), PluginDir)
+	// The background sleep inherits stdout and outlives its shell.
+	writePlugin(t, dir, "hang.toml", `
+command = "sleep 30 & sleep 30"
+timeout = "200ms"
+`, 0644)
+
+	start := time.Now()
+	r := loadPluginDir(dir, "")[0].Run(&CheckContext{TownRoot: t.TempDir()})
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run took %v, want it bounded by the timeout", elapsed)
+	}
+	if r.Status != StatusError || !strings.Contains(r.Message+strings.Join(r.Details, " "), "timed out") {
+		t.Errorf("result = %v %q %v, want a timeout error", r.Status, r.Message, r.Details)
+	}
+}
@@ -0,0 +1,194 @@
+package doctor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Report output formats for machine consumers (cron, CI).
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// jsonReport is the schema written by WriteJSON.
+type jsonReport struct {
+	Timestamp  string       `json:"timestamp"`
+	DurationMS int64        `json:"duration_ms"`
+	Healthy    bool         `json:"healthy"`
+	Summary    jsonSummary  `json:"summary"`
+	Checks     []jsonResult `json:"checks"`
+}
+
+type jsonSummary struct {
+	Total    int `json:"total"`
+	OK       int `json:"ok"`
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+	Fixed    int `json:"fixed"`
+}
+
+type jsonResult struct {
+	Name      string   `json:"name"`
+	Category  string   `json:"category"`
+	Status    string   `json:"status"` // ok, warning, error
+	Message   string   `json:"message,omitempty"`
+	Details   []string `json:"details,omitempty"`
+	FixHint   string   `json:"fix_hint,omitempty"`
+	Fixed     bool     `json:"fixed,omitempty"`
+	ElapsedMS int64    `json:"elapsed_ms"`
+	Source    string   `json:"source,omitempty"`
+}
+
+// statusKey returns the lowercase status used in machine-readable output.
+func statusKey(s CheckStatus) string {
+	return strings.ToLower(s.String())
+}
+
+// WriteJSON writes the report as a single JSON document.
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := jsonReport{
+		Timestamp:  r.Timestamp.UTC().Format(time.RFC3339),
+		DurationMS: r.Duration.Milliseconds(),
+		Healthy:    r.IsHealthy(),
+		Summary: jsonSummary{
+			Total:    r.Summary.Total,
+			OK:       r.Summary.OK,
+			Warnings: r.Summary.Warnings,
+			Errors:   r.Summary.Errors,
+			Fixed:    r.Summary.Fixed,
+		},
+		Checks: make([]jsonResult, 0, len(r.Checks)),
+	}
+	for _, c := range r.Checks {
+		out.Checks = append(out.Checks, jsonResult{
+			Name:      c.Name,
+			Category:  categoryOrOther(c.Category),
+			Status:    statusKey(c.Status),
+			Message:   c.Message,
+			Details:   c.Details,
+			FixHint:   c.FixHint,
+			Fixed:     c.Fixed,
+			ElapsedMS: c.Elapsed.Milliseconds(),
+			Source:    c.Source,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// JUnit XML schema (the subset CI systems read).
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML with one test suite per
+// category. Errors are failures; warnings pass but are reported in
+// system-out so they stay visible without failing the build.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suites := junitSuites{
+		Name:  "gt doctor",
+		Tests: len(r.Checks),
+		Time:  junitSeconds(r.Duration),
+	}
+	timestamp := r.Timestamp.UTC().Format("2006-01-02T15:04:05")
+
+	byCategory := make(map[string]*junitSuite)
+	elapsed := make(map[string]time.Duration)
+	var order []string
+	for _, c := range r.Checks {
+		category := categoryOrOther(c.Category)
+		suite, ok := byCategory[category]
+		if !ok {
+			suite = &junitSuite{Name: category, Timestamp: timestamp}
+			byCategory[category] = suite
+			order = append(order, category)
+		}
+
+		tc := junitCase{
+			Name:      c.Name,
+			Classname: "doctor." + strings.ToLower(category),
+			Time:      junitSeconds(c.Elapsed),
+		}
+		body := strings.Join(append(append([]string{}, c.Details...), fixHintLine(c.FixHint)...), "\n")
+		switch c.Status {
+		case StatusError:
+			tc.Failure = &junitFailure{Message: c.Message, Type: statusKey(c.Status), Text: body}
+			suite.Failures++
+			suites.Failures++
+		case StatusWarning:
+			tc.SystemOut = strings.TrimSpace("warning: " + c.Message + "\n" + body)
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+		elapsed[category] += c.Elapsed
+	}
+	for _, category := range order {
+		suite := byCategory[category]
+		suite.Time = junitSeconds(elapsed[category])
+		suites.Suites = append(suites.Suites, *suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func categoryOrOther(category string) string {
+	if category == "" {
+		return "Other"
+	}
+	return category
+}
+
+func fixHintLine(hint string) []string {
+	if hint == "" {
+		return nil
+	}
+	return []string{"fix: " + hint}
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
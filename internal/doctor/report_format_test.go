@@ -0,0 +1,71 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleReport() *Report {
+	r := NewReport()
+	r.Add(&CheckResult{Name: "town-config-exists", Category: CategoryCore, Status: StatusOK, Elapsed: 2 * time.Millisecond})
+	r.Add(&CheckResult{Name: "daemon", Category: CategoryInfrastructure, Status: StatusError, Message: "not running", FixHint: "gt daemon start", Elapsed: 1500 * time.Millisecond})
+	r.Add(&CheckResult{Name: "gastown/disk", Category: CategoryPlugins, Status: StatusWarning, Message: "low disk", Details: []string{"90% used"}, Source: "/town/gastown/doctor.d/disk.sh"})
+	r.Duration = 2 * time.Second
+	return r
+}
+
+func TestReportWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Healthy || got.DurationMS != 2000 || got.Summary.Errors != 1 || got.Summary.Warnings != 1 {
+		t.Errorf("summary = %+v healthy=%v duration=%d", got.Summary, got.Healthy, got.DurationMS)
+	}
+	if len(got.Checks) != 3 {
+		t.Fatalf("got %d checks, want 3", len(got.Checks))
+	}
+	daemon := got.Checks[1]
+	if daemon.Status != "error" || daemon.ElapsedMS != 1500 || daemon.FixHint != "gt daemon start" {
+		t.Errorf("daemon = %+v", daemon)
+	}
+	if got.Checks[2].Status != "warning" || got.Checks[2].Source == "" {
+		t.Errorf("plugin = %+v", got.Checks[2])
+	}
+}
+
+func TestReportWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().WriteJUnit(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if got.Tests != 3 || got.Failures != 1 || got.Time != "2.000" {
+		t.Errorf("testsuites tests=%d failures=%d time=%s", got.Tests, got.Failures, got.Time)
+	}
+	if len(got.Suites) != 3 {
+		t.Fatalf("got %d suites, want one per category", len(got.Suites))
+	}
+	infra := got.Suites[1]
+	if infra.Name != CategoryInfrastructure || infra.Failures != 1 || infra.Time != "1.500" {
+		t.Errorf("infrastructure suite = %+v", infra)
+	}
+	if f := infra.Cases[0].Failure; f == nil || f.Message != "not running" || !strings.Contains(f.Text, "fix: gt daemon start") {
+		t.Errorf("daemon failure = %+v", f)
+	}
+	plugin := got.Suites[2].Cases[0]
+	if plugin.Failure != nil || !strings.HasPrefix(plugin.SystemOut, "warning: low disk") {
+		t.Errorf("warning case = %+v, want pass with warning in system-out", plugin)
+	}
+}
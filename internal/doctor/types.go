@@ -18,6 +18,7 @@ const (
 	CategoryConfig        = "Configuration"
 	CategoryCleanup       = "Cleanup"
 	CategoryHooks         = "Hooks"
+	CategoryPlugins       = "Plugins"
 )
 
 // CategoryOrder defines the display order for categories
@@ -29,6 +30,7 @@ var CategoryOrder = []string{
 	CategoryConfig,
 	CategoryCleanup,
 	CategoryHooks,
+	CategoryPlugins,
 }
 
 // CheckStatus represents the result status of a health check.
@@ -88,6 +90,7 @@ type CheckResult struct {
 	Category string        // Category for grouping (e.g., CategoryCore)
 	Elapsed  time.Duration // How long the check took to run
 	Fixed    bool          // True if this check was auto-fixed
+	Source   string        // Plugin file for external checks (empty for built-ins)
 }
 
 // Check defines the interface for a health check.
@@ -124,6 +127,7 @@ type ReportSummary struct {
 // Report contains all check results and a summary.
 type Report struct {
 	Timestamp time.Time
+	Duration  time.Duration // Wall time of the whole run
 	Checks    []*CheckResult
 	Summary   ReportSummary
 }
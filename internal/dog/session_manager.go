@@ -22,17 +22,18 @@ var (
 
 // SessionManager handles dog session lifecycle.
 type SessionManager struct {
-	tmux     *tmux.Tmux
+	tmux     session.Backend
 	mgr      *Manager
 	townRoot string
 }
 
 // NewSessionManager creates a new dog session manager.
 // The Manager parameter is used to sync persistent dog state (idle/working)
-// when sessions start and stop.
+// when sessions start and stop. Sessions run on the configured backend:
+// t under tmux, the pty supervisor otherwise.
 func NewSessionManager(t *tmux.Tmux, townRoot string, mgr *Manager) *SessionManager {
 	return &SessionManager{
-		tmux:     t,
+		tmux:     session.ResolveBackend(t),
 		mgr:      mgr,
 		townRoot: townRoot,
 	}
@@ -197,7 +198,7 @@ func (m *SessionManager) Status(dogName string) (*SessionInfo, error) {
 		return info, nil
 	}
 
-	tmuxInfo, err := m.tmux.GetSessionInfo(sessionID)
+	tmuxInfo, err := session.GetSessionInfo(m.tmux, sessionID)
 	if err != nil {
 		return info, nil
 	}
@@ -219,8 +220,14 @@ func (m *SessionManager) GetPane(dogName string) (string, error) {
 		return "", ErrSessionNotFound
 	}
 
+	// Backends other than tmux address the session itself.
+	tm, ok := m.tmux.(*tmux.Tmux)
+	if !ok {
+		return sessionID, nil
+	}
+
 	// Get pane ID from session
-	pane, err := m.tmux.GetPaneID(sessionID)
+	pane, err := tm.GetPaneID(sessionID)
 	if err != nil {
 		return "", fmt.Errorf("getting pane: %w", err)
 	}
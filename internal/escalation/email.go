@@ -0,0 +1,156 @@
+package escalation
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// defaultSMTPPort is the submission port used when channels.smtp.port is unset.
+const defaultSMTPPort = 587
+
+// DefaultSMTPTimeout bounds an SMTP delivery when the caller's context has
+// no deadline of its own.
+const DefaultSMTPTimeout = 30 * time.Second
+
+// EmailNotifier sends escalations over SMTP.
+type EmailNotifier struct {
+	Addr        string // host:port
+	Host        string
+	From        string
+	Username    string
+	PasswordEnv string
+
+	// sendMail sends the message (overridable for tests).
+	sendMail func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates an email notifier from SMTP settings.
+func NewEmailNotifier(cfg *config.EscalationSMTPConfig) *EmailNotifier {
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	return &EmailNotifier{
+		Addr:        net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		Host:        cfg.Host,
+		From:        cfg.From,
+		Username:    cfg.Username,
+		PasswordEnv: cfg.PasswordEnv,
+		sendMail:    sendMailContext,
+	}
+}
+
+// Notify emails the notification to the address in target.
+// SMTP has no useful retry semantics at this layer; a single attempt is made.
+func (e *EmailNotifier) Notify(ctx context.Context, target string, n *Notification) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var auth smtp.Auth
+	if e.Username != "" {
+		password := ""
+		if e.PasswordEnv != "" {
+			password = os.Getenv(e.PasswordEnv)
+		}
+		auth = smtp.PlainAuth("", e.Username, password, e.Host)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultSMTPTimeout)
+		defer cancel()
+	}
+
+	msg := buildEmail(e.From, target, n, time.Now())
+	if err := e.sendMail(ctx, e.Addr, auth, e.From, []string{target}, msg); err != nil {
+		return 1, fmt.Errorf("sending email via %s: %w", e.Addr, err)
+	}
+	return 1, nil
+}
+
+// sendMailContext is smtp.SendMail bounded by ctx: the dial honors ctx, the
+// connection's deadline is ctx's, and canceling ctx closes the connection.
+func sendMailContext(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildEmail renders an RFC 5322 message with CRLF line endings.
+func buildEmail(from, to string, n *Notification, now time.Time) []byte {
+	headers := []string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + sanitizeHeader(n.Subject()),
+		"Date: " + now.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"X-Gastown-Escalation: " + sanitizeHeader(n.ID),
+		"X-Gastown-Severity: " + sanitizeHeader(n.Severity),
+	}
+	body := strings.ReplaceAll(n.Body(), "\r\n", "\n")
+	body = strings.ReplaceAll(body, "\n", "\r\n")
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body + "\r\n")
+}
+
+// sanitizeHeader strips CR/LF to prevent header injection.
+func sanitizeHeader(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
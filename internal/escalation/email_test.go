@@ -0,0 +1,182 @@
+package escalation
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// smtpStandIn is a minimal SMTP server that accepts one message.
+type smtpStandIn struct {
+	ln   net.Listener
+	from string
+	rcpt []string
+	data string
+	done chan struct{}
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpStandIn{ln: ln, done: make(chan struct{})}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *smtpStandIn) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP stand-in")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.rcpt = append(s.rcpt, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.data = b.String()
+			reply("250 OK queued")
+		case upper == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestEmailNotifier_SendsViaSMTP(t *testing.T) {
+	srv := newSMTPStandIn(t)
+	e := NewEmailNotifier(&config.EscalationSMTPConfig{
+		Host: "127.0.0.1",
+		Port: srv.port(),
+		From: "gastown@example.com",
+	})
+
+	n := testNotification()
+	attempts, err := e.Notify(context.Background(), "human@example.com", n)
+	if err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+	<-srv.done
+
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+	if srv.from != "gastown@example.com" {
+		t.Errorf("MAIL FROM = %q", srv.from)
+	}
+	if len(srv.rcpt) != 1 || srv.rcpt[0] != "human@example.com" {
+		t.Errorf("RCPT TO = %v", srv.rcpt)
+	}
+	for _, want := range []string{
+		"Subject: [HIGH] Build broken on main (hq-abc)",
+		"X-Gastown-Escalation: hq-abc",
+		"3 consecutive failures",
+	} {
+		if !strings.Contains(srv.data, want) {
+			t.Errorf("message missing %q:\n%s", want, srv.data)
+		}
+	}
+}
+
+func TestEmailNotifier_ConnectionFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close() // nothing listening now
+
+	e := NewEmailNotifier(&config.EscalationSMTPConfig{Host: "127.0.0.1", Port: port, From: "gt@example.com"})
+	if _, err := e.Notify(context.Background(), "human@example.com", testNotification()); err == nil {
+		t.Error("expected error when SMTP server is unreachable")
+	} else if !strings.Contains(err.Error(), strconv.Itoa(port)) {
+		t.Errorf("error %q should mention server address", err)
+	}
+}
+
+func TestBuildEmail_StripsHeaderInjection(t *testing.T) {
+	n := testNotification()
+	n.Title = "evil\r\nBcc: attacker@example.com"
+	msg := string(buildEmail("a@example.com", "b@example.com", n, fixedTime))
+	headers := msg[:strings.Index(msg, "\r\n\r\n")]
+	if strings.Contains(headers, "\r\nBcc:") {
+		t.Errorf("header injection not stripped:\n%s", headers)
+	}
+}
+
+func TestEmailNotifier_HonorsContextDeadline(t *testing.T) {
+	// A server that accepts the connection but never greets.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		<-done
+		conn.Close()
+	}()
+
+	e := NewEmailNotifier(&config.EscalationSMTPConfig{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port, From: "gt@example.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := e.Notify(ctx, "human@example.com", testNotification()); err == nil {
+		t.Fatal("expected error from a server that never answers")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Notify took %v, want it bounded by the context deadline", elapsed)
+	}
+}
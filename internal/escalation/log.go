@@ -0,0 +1,144 @@
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Defaults for the escalation log.
+const (
+	DefaultLogMaxSizeMB  = 10
+	DefaultLogMaxBackups = 5
+)
+
+// LogNotifier appends escalations to a log file, rotating it by size.
+// Rotated files are named <path>.1 (newest) through <path>.<MaxBackups>.
+type LogNotifier struct {
+	Path       string
+	MaxSize    int64 // bytes
+	MaxBackups int
+
+	mu sync.Mutex
+}
+
+// NewLogNotifier creates a log notifier from log settings (nil = defaults).
+func NewLogNotifier(townRoot string, cfg *config.EscalationLogConfig) *LogNotifier {
+	l := &LogNotifier{
+		Path:       filepath.Join(townRoot, "logs", "escalations.log"),
+		MaxSize:    DefaultLogMaxSizeMB * 1024 * 1024,
+		MaxBackups: DefaultLogMaxBackups,
+	}
+	if cfg == nil {
+		return l
+	}
+	if cfg.Path != "" {
+		if filepath.IsAbs(cfg.Path) {
+			l.Path = cfg.Path
+		} else {
+			l.Path = filepath.Join(townRoot, cfg.Path)
+		}
+	}
+	if cfg.MaxSizeMB > 0 {
+		l.MaxSize = int64(cfg.MaxSizeMB) * 1024 * 1024
+	}
+	if cfg.MaxBackups > 0 {
+		l.MaxBackups = cfg.MaxBackups
+	}
+	return l
+}
+
+// Notify appends one line for the notification. target is unused.
+func (l *LogNotifier) Notify(_ context.Context, _ string, n *Notification) (int, error) {
+	if err := l.Append(formatLogEntry(n, time.Now())); err != nil {
+		return 1, err
+	}
+	return 1, nil
+}
+
+// Append writes a line to the log, rotating first if it would exceed MaxSize.
+// A cross-process file lock keeps concurrent gt processes from interleaving
+// rotation and writes.
+func (l *LogNotifier) Append(line string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	fl := flock.New(l.Path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring escalation log lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	line = strings.TrimRight(line, "\n") + "\n"
+	if info, err := os.Stat(l.Path); err == nil && l.MaxSize > 0 && info.Size()+int64(len(line)) > l.MaxSize {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("rotating escalation log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening escalation log: %w", err)
+	}
+	if _, err := f.WriteString(line); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing escalation log: %w", err)
+	}
+	return f.Close()
+}
+
+// rotate shifts <path>.N → <path>.N+1, dropping the oldest, then moves the
+// live file to <path>.1.
+func (l *LogNotifier) rotate() error {
+	backups := l.MaxBackups
+	if backups <= 0 {
+		return os.Remove(l.Path)
+	}
+	_ = os.Remove(fmt.Sprintf("%s.%d", l.Path, backups))
+	for i := backups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", l.Path, i)
+		if _, err := os.Stat(src); err == nil {
+			if err := os.Rename(src, fmt.Sprintf("%s.%d", l.Path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Rename(l.Path, l.Path+".1")
+}
+
+// formatLogEntry formats a notification as a single log line.
+// Format: 2026-01-02 15:04:05 [HIGH] gt-abc "title" from=gastown/Toast source=... reason=...
+func formatLogEntry(n *Notification, now time.Time) string {
+	severity := strings.ToUpper(n.Severity)
+	if n.Reescalation {
+		severity = "RE-ESCALATED " + severity
+	}
+	parts := []string{
+		now.Format("2006-01-02 15:04:05"),
+		"[" + severity + "]",
+		n.ID,
+		fmt.Sprintf("%q", n.Title),
+		"from=" + n.From,
+	}
+	if n.Source != "" {
+		parts = append(parts, "source="+n.Source)
+	}
+	if n.RelatedBead != "" {
+		parts = append(parts, "related="+n.RelatedBead)
+	}
+	if n.Reason != "" {
+		parts = append(parts, fmt.Sprintf("reason=%q", n.Reason))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,90 @@
+package escalation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+var fixedTime = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+func TestNewLogNotifier_Defaults(t *testing.T) {
+	l := NewLogNotifier("/town", nil)
+	if l.Path != filepath.Join("/town", "logs", "escalations.log") {
+		t.Errorf("Path = %q", l.Path)
+	}
+	if l.MaxSize != DefaultLogMaxSizeMB*1024*1024 || l.MaxBackups != DefaultLogMaxBackups {
+		t.Errorf("defaults = %d/%d", l.MaxSize, l.MaxBackups)
+	}
+
+	l = NewLogNotifier("/town", &config.EscalationLogConfig{Path: "custom/esc.log", MaxSizeMB: 1, MaxBackups: 2})
+	if l.Path != filepath.Join("/town", "custom", "esc.log") || l.MaxSize != 1024*1024 || l.MaxBackups != 2 {
+		t.Errorf("configured = %+v", l)
+	}
+}
+
+func TestLogNotifier_Appends(t *testing.T) {
+	l := NewLogNotifier(t.TempDir(), nil)
+	n := testNotification()
+	for i := 0; i < 2; i++ {
+		if _, err := l.Notify(context.Background(), "", n); err != nil {
+			t.Fatalf("Notify error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(l.Path)
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if !strings.Contains(lines[0], `[HIGH] hq-abc "Build broken on main" from=gastown/witness`) {
+		t.Errorf("line = %q", lines[0])
+	}
+}
+
+func TestLogNotifier_Rotates(t *testing.T) {
+	dir := t.TempDir()
+	l := &LogNotifier{Path: filepath.Join(dir, "esc.log"), MaxSize: 20, MaxBackups: 2}
+
+	for _, line := range []string{"first-line-xxxxx", "second-line-xxxx", "third-line-xxxxx", "fourth-line-xxxx"} {
+		if err := l.Append(line); err != nil {
+			t.Fatalf("Append error: %v", err)
+		}
+	}
+
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return strings.TrimSpace(string(data))
+	}
+	if got := read("esc.log"); got != "fourth-line-xxxx" {
+		t.Errorf("live log = %q", got)
+	}
+	if got := read("esc.log.1"); got != "third-line-xxxxx" {
+		t.Errorf("esc.log.1 = %q", got)
+	}
+	if got := read("esc.log.2"); got != "second-line-xxxx" {
+		t.Errorf("esc.log.2 = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "esc.log.3")); !os.IsNotExist(err) {
+		t.Error("esc.log.3 should not exist beyond MaxBackups")
+	}
+}
+
+func TestFormatLogEntry(t *testing.T) {
+	n := testNotification()
+	n.Source = "plugin:rebuild-gt"
+	n.Reescalation = true
+	got := formatLogEntry(n, fixedTime)
+	want := `2026-01-02 15:04:05 [RE-ESCALATED HIGH] hq-abc "Build broken on main" from=gastown/witness source=plugin:rebuild-gt reason="3 consecutive failures"`
+	if got != want {
+		t.Errorf("formatLogEntry() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,234 @@
+// Package escalation delivers escalations to external notification channels.
+//
+// Escalation routes (settings/escalation.json) list actions per severity.
+// The "bead" and "mail:" actions are handled by gt escalate directly; this
+// package implements the external ones:
+//
+//   - email:<target> → SMTP (channels.smtp)
+//   - sms:<target>   → HTTP SMS gateway (channels.sms)
+//   - slack          → Slack-compatible incoming webhook (contacts.slack_webhook)
+//   - webhook        → generic JSON webhook (contacts.webhook_url)
+//   - log            → append-only escalation log with rotation (channels.log)
+//
+// Each action produces a beads.EscalationDelivery describing the outcome so
+// callers can record delivery status on the escalation bead.
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Notification is the escalation content delivered to external channels.
+type Notification struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Title       string `json:"title"`
+	Reason      string `json:"reason,omitempty"`
+	Source      string `json:"source,omitempty"`
+	From        string `json:"from"`
+	RelatedBead string `json:"related_bead,omitempty"`
+
+	// Reescalation is set when this notification announces a severity bump.
+	Reescalation bool `json:"reescalation,omitempty"`
+}
+
+// Subject returns a one-line summary suitable for email subjects and SMS.
+func (n *Notification) Subject() string {
+	prefix := strings.ToUpper(n.Severity)
+	if n.Reescalation {
+		prefix = "RE-ESCALATED " + prefix
+	}
+	return fmt.Sprintf("[%s] %s (%s)", prefix, n.Title, n.ID)
+}
+
+// Body returns a plain-text body describing the escalation.
+func (n *Notification) Body() string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Escalation ID: %s", n.ID))
+	lines = append(lines, fmt.Sprintf("Severity: %s", n.Severity))
+	lines = append(lines, fmt.Sprintf("From: %s", n.From))
+	if n.Source != "" {
+		lines = append(lines, fmt.Sprintf("Source: %s", n.Source))
+	}
+	if n.Reason != "" {
+		lines = append(lines, "", "Reason:", n.Reason)
+	}
+	if n.RelatedBead != "" {
+		lines = append(lines, "", fmt.Sprintf("Related: %s", n.RelatedBead))
+	}
+	lines = append(lines, "", "---",
+		"To acknowledge: gt escalate ack "+n.ID,
+		"To close: gt escalate close "+n.ID+" --reason \"resolution\"")
+	return strings.Join(lines, "\n")
+}
+
+// Notifier delivers a notification to a single channel.
+// Notify returns the number of attempts made, which is greater than one
+// when the notifier retried transient failures.
+type Notifier interface {
+	Notify(ctx context.Context, target string, n *Notification) (attempts int, err error)
+}
+
+// Channel names used to register notifiers with a Dispatcher.
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelSlack   = "slack"
+	ChannelWebhook = "webhook"
+	ChannelLog     = "log"
+)
+
+// Dispatcher maps route actions to notifiers and resolves their targets.
+type Dispatcher struct {
+	contacts  config.EscalationContacts
+	notifiers map[string]Notifier
+	now       func() time.Time
+}
+
+// NewDispatcher creates a dispatcher with notifiers built from the escalation
+// config. Channels whose transport is not configured are left unregistered,
+// and actions routed to them are recorded as skipped. Targets resolve through
+// the contacts of the on-call window active now, if any.
+func NewDispatcher(cfg *config.EscalationConfig, townRoot string) *Dispatcher {
+	d := &Dispatcher{
+		contacts:  cfg.ContactsAt(time.Now()),
+		notifiers: make(map[string]Notifier),
+		now:       time.Now,
+	}
+
+	var channels config.EscalationChannels
+	if cfg.Channels != nil {
+		channels = *cfg.Channels
+	}
+
+	poster := NewPoster(channels.Webhook)
+	d.notifiers[ChannelSlack] = &SlackNotifier{Poster: poster}
+	d.notifiers[ChannelWebhook] = &WebhookNotifier{Poster: poster}
+	d.notifiers[ChannelLog] = NewLogNotifier(townRoot, channels.Log)
+	if channels.SMTP != nil {
+		d.notifiers[ChannelEmail] = NewEmailNotifier(channels.SMTP)
+	}
+	if channels.SMS != nil {
+		d.notifiers[ChannelSMS] = NewSMSNotifier(channels.SMS, poster)
+	}
+
+	return d
+}
+
+// Register installs (or replaces) the notifier for a channel.
+func (d *Dispatcher) Register(channel string, n Notifier) {
+	d.notifiers[channel] = n
+}
+
+// IsExternalAction reports whether an action is delivered by this package
+// (as opposed to "bead" and "mail:" actions handled by gt escalate).
+func IsExternalAction(action string) bool {
+	channel, _ := splitAction(action)
+	switch channel {
+	case ChannelEmail, ChannelSMS, ChannelSlack, ChannelWebhook, ChannelLog:
+		return true
+	}
+	return false
+}
+
+// Deliver executes every external action in order and returns one delivery
+// record per action. Failures in one channel do not stop the others.
+func (d *Dispatcher) Deliver(ctx context.Context, actions []string, n *Notification) []beads.EscalationDelivery {
+	var deliveries []beads.EscalationDelivery
+	for _, action := range actions {
+		if !IsExternalAction(action) {
+			continue
+		}
+		deliveries = append(deliveries, d.deliverOne(ctx, action, n))
+	}
+	return deliveries
+}
+
+// deliverOne executes a single external action.
+func (d *Dispatcher) deliverOne(ctx context.Context, action string, n *Notification) beads.EscalationDelivery {
+	rec := beads.EscalationDelivery{Action: action}
+	finish := func(status string, attempts int, detail string) beads.EscalationDelivery {
+		rec.At = d.now().UTC().Format(time.RFC3339)
+		rec.Status = status
+		rec.Attempts = attempts
+		rec.Detail = detail
+		return rec
+	}
+
+	channel, arg := splitAction(action)
+	target, err := d.resolveTarget(channel, arg)
+	if err != nil {
+		return finish(beads.DeliverySkipped, 0, err.Error())
+	}
+
+	notifier, ok := d.notifiers[channel]
+	if !ok {
+		return finish(beads.DeliverySkipped, 0, fmt.Sprintf("channels.%s not configured in settings/escalation.json", channel))
+	}
+
+	attempts, err := notifier.Notify(ctx, target, n)
+	if err != nil {
+		return finish(beads.DeliveryFailed, attempts, err.Error())
+	}
+	return finish(beads.DeliverySent, attempts, "")
+}
+
+// resolveTarget maps an action's argument to a concrete address.
+// "human" resolves through contacts; email and sms also accept a literal
+// address or number (e.g., "email:oncall@example.com").
+func (d *Dispatcher) resolveTarget(channel, arg string) (string, error) {
+	switch channel {
+	case ChannelEmail:
+		if arg == "" || arg == "human" {
+			if d.contacts.HumanEmail == "" {
+				return "", fmt.Errorf("contacts.human_email not configured in settings/escalation.json")
+			}
+			return d.contacts.HumanEmail, nil
+		}
+		if !strings.Contains(arg, "@") {
+			return "", fmt.Errorf("unknown email target %q", arg)
+		}
+		return arg, nil
+
+	case ChannelSMS:
+		if arg == "" || arg == "human" {
+			if d.contacts.HumanSMS == "" {
+				return "", fmt.Errorf("contacts.human_sms not configured in settings/escalation.json")
+			}
+			return d.contacts.HumanSMS, nil
+		}
+		if !strings.HasPrefix(arg, "+") {
+			return "", fmt.Errorf("unknown sms target %q", arg)
+		}
+		return arg, nil
+
+	case ChannelSlack:
+		if d.contacts.SlackWebhook == "" {
+			return "", fmt.Errorf("contacts.slack_webhook not configured in settings/escalation.json")
+		}
+		return d.contacts.SlackWebhook, nil
+
+	case ChannelWebhook:
+		if d.contacts.WebhookURL == "" {
+			return "", fmt.Errorf("contacts.webhook_url not configured in settings/escalation.json")
+		}
+		return d.contacts.WebhookURL, nil
+
+	default:
+		return "", nil
+	}
+}
+
+// splitAction splits "email:human" into ("email", "human").
+func splitAction(action string) (channel, arg string) {
+	if idx := strings.Index(action, ":"); idx >= 0 {
+		return action[:idx], action[idx+1:]
+	}
+	return action, ""
+}
@@ -0,0 +1,147 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// fakeNotifier records calls and returns a canned result.
+type fakeNotifier struct {
+	targets  []string
+	attempts int
+	err      error
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, target string, _ *Notification) (int, error) {
+	f.targets = append(f.targets, target)
+	return f.attempts, f.err
+}
+
+func testNotification() *Notification {
+	return &Notification{
+		ID:       "hq-abc",
+		Severity: config.SeverityHigh,
+		Title:    "Build broken on main",
+		Reason:   "3 consecutive failures",
+		From:     "gastown/witness",
+	}
+}
+
+func TestIsExternalAction(t *testing.T) {
+	tests := map[string]bool{
+		"bead":        false,
+		"mail:mayor":  false,
+		"email:human": true,
+		"sms:human":   true,
+		"slack":       true,
+		"webhook":     true,
+		"log":         true,
+		"carrier":     false,
+	}
+	for action, want := range tests {
+		if got := IsExternalAction(action); got != want {
+			t.Errorf("IsExternalAction(%q) = %v, want %v", action, got, want)
+		}
+	}
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	cfg := &config.EscalationConfig{
+		Contacts: config.EscalationContacts{
+			HumanEmail:   "human@example.com",
+			HumanSMS:     "+15551234567",
+			SlackWebhook: "https://hooks.example.com/slack",
+		},
+	}
+	d := NewDispatcher(cfg, t.TempDir())
+
+	email := &fakeNotifier{attempts: 1}
+	sms := &fakeNotifier{attempts: 3, err: errors.New("gateway down")}
+	slack := &fakeNotifier{attempts: 2}
+	d.Register(ChannelEmail, email)
+	d.Register(ChannelSMS, sms)
+	d.Register(ChannelSlack, slack)
+
+	actions := []string{"bead", "mail:mayor", "email:human", "email:oncall@example.com", "sms:human", "slack", "webhook"}
+	deliveries := d.Deliver(context.Background(), actions, testNotification())
+
+	want := []struct {
+		action   string
+		status   string
+		attempts int
+	}{
+		{"email:human", beads.DeliverySent, 1},
+		{"email:oncall@example.com", beads.DeliverySent, 1},
+		{"sms:human", beads.DeliveryFailed, 3},
+		{"slack", beads.DeliverySent, 2},
+		{"webhook", beads.DeliverySkipped, 0},
+	}
+	if len(deliveries) != len(want) {
+		t.Fatalf("got %d deliveries, want %d: %+v", len(deliveries), len(want), deliveries)
+	}
+	for i, w := range want {
+		got := deliveries[i]
+		if got.Action != w.action || got.Status != w.status || got.Attempts != w.attempts {
+			t.Errorf("delivery %d = %+v, want action=%s status=%s attempts=%d", i, got, w.action, w.status, w.attempts)
+		}
+		if got.At == "" {
+			t.Errorf("delivery %d missing timestamp", i)
+		}
+	}
+
+	if strings.Join(email.targets, ",") != "human@example.com,oncall@example.com" {
+		t.Errorf("email targets = %v", email.targets)
+	}
+	if deliveries[2].Detail != "gateway down" {
+		t.Errorf("sms detail = %q, want error text", deliveries[2].Detail)
+	}
+	if !strings.Contains(deliveries[4].Detail, "webhook_url") {
+		t.Errorf("webhook skip detail = %q, want mention of webhook_url", deliveries[4].Detail)
+	}
+}
+
+func TestDispatcher_UnconfiguredChannelsSkipped(t *testing.T) {
+	cfg := &config.EscalationConfig{
+		Contacts: config.EscalationContacts{
+			HumanEmail: "human@example.com",
+			HumanSMS:   "+15551234567",
+		},
+	}
+	d := NewDispatcher(cfg, t.TempDir())
+
+	deliveries := d.Deliver(context.Background(), []string{"email:human", "sms:human", "email:bogus"}, testNotification())
+	for _, rec := range deliveries {
+		if rec.Status != beads.DeliverySkipped {
+			t.Errorf("%s status = %q, want skipped", rec.Action, rec.Status)
+		}
+	}
+	if !strings.Contains(deliveries[0].Detail, "channels.email") {
+		t.Errorf("email detail = %q, want channels hint", deliveries[0].Detail)
+	}
+	if !strings.Contains(deliveries[2].Detail, "unknown email target") {
+		t.Errorf("bogus detail = %q", deliveries[2].Detail)
+	}
+}
+
+func TestNotification_SubjectAndBody(t *testing.T) {
+	n := testNotification()
+	if got := n.Subject(); got != "[HIGH] Build broken on main (hq-abc)" {
+		t.Errorf("Subject() = %q", got)
+	}
+	n.Reescalation = true
+	if got := n.Subject(); !strings.HasPrefix(got, "[RE-ESCALATED HIGH]") {
+		t.Errorf("reescalation Subject() = %q", got)
+	}
+
+	body := n.Body()
+	for _, want := range []string{"Escalation ID: hq-abc", "3 consecutive failures", "gt escalate ack hq-abc"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Body() missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// smsMaxLen caps message length, in characters, to a single concatenated SMS.
+const smsMaxLen = 320
+
+// SMSNotifier sends text messages through an HTTP SMS gateway.
+// The gateway receives {"to": "<number>", "message": "<text>"}.
+type SMSNotifier struct {
+	URL      string
+	TokenEnv string
+	Poster   *Poster
+}
+
+// NewSMSNotifier creates an SMS notifier from gateway settings.
+func NewSMSNotifier(cfg *config.EscalationSMSConfig, poster *Poster) *SMSNotifier {
+	return &SMSNotifier{URL: cfg.URL, TokenEnv: cfg.TokenEnv, Poster: poster}
+}
+
+// Notify sends the notification subject to the phone number in target.
+func (s *SMSNotifier) Notify(ctx context.Context, target string, n *Notification) (int, error) {
+	headers := map[string]string{}
+	if s.TokenEnv != "" {
+		token := os.Getenv(s.TokenEnv)
+		if token == "" {
+			return 0, fmt.Errorf("sms gateway token env %s is not set", s.TokenEnv)
+		}
+		headers["Authorization"] = "Bearer " + token
+	}
+
+	message := n.Subject()
+	if n.Reason != "" {
+		message += ": " + n.Reason
+	}
+	if runes := []rune(message); len(runes) > smsMaxLen {
+		message = string(runes[:smsMaxLen-3]) + "..."
+	}
+
+	return s.Poster.PostJSON(ctx, s.URL, headers, map[string]string{
+		"to":      target,
+		"message": message,
+	})
+}
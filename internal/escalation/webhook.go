@@ -0,0 +1,181 @@
+package escalation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Default retry policy for webhook-style deliveries.
+const (
+	DefaultWebhookMaxAttempts    = 3
+	DefaultWebhookInitialBackoff = 1 * time.Second
+	DefaultWebhookTimeout        = 10 * time.Second
+)
+
+// Poster sends JSON POST requests with retry and exponential backoff.
+// Network errors, 429 and 5xx responses are retried; other 4xx responses
+// fail immediately since retrying won't help.
+type Poster struct {
+	Client         *http.Client
+	MaxAttempts    int
+	InitialBackoff time.Duration
+
+	// sleep waits between attempts (overridable for tests).
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewPoster creates a Poster from webhook channel settings (nil = defaults).
+func NewPoster(cfg *config.EscalationWebhookConfig) *Poster {
+	p := &Poster{
+		Client:         &http.Client{Timeout: DefaultWebhookTimeout},
+		MaxAttempts:    DefaultWebhookMaxAttempts,
+		InitialBackoff: DefaultWebhookInitialBackoff,
+		sleep:          sleepContext,
+	}
+	if cfg == nil {
+		return p
+	}
+	if cfg.MaxAttempts > 0 {
+		p.MaxAttempts = cfg.MaxAttempts
+	}
+	if d, err := time.ParseDuration(cfg.InitialBackoff); err == nil && d >= 0 {
+		p.InitialBackoff = d
+	}
+	if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+		p.Client.Timeout = d
+	}
+	return p
+}
+
+// PostJSON marshals payload and POSTs it to url, retrying transient failures.
+// Returns the number of attempts made.
+func (p *Poster) PostJSON(ctx context.Context, url string, headers map[string]string, payload interface{}) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("encoding payload: %w", err)
+	}
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	backoff := p.InitialBackoff
+	sleep := p.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		retry, err := p.postOnce(ctx, url, headers, body)
+		if err == nil {
+			return attempt, nil
+		}
+		lastErr = err
+		if !retry || attempt == maxAttempts {
+			return attempt, lastErr
+		}
+		if err := sleep(ctx, backoff); err != nil {
+			return attempt, fmt.Errorf("%v (retry aborted: %w)", lastErr, err)
+		}
+		backoff *= 2
+	}
+	return maxAttempts, lastErr
+}
+
+// postOnce performs a single POST. retry reports whether the failure is transient.
+func (p *Poster) postOnce(ctx context.Context, url string, headers map[string]string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("posting: %w", err)
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SlackNotifier posts to a Slack-compatible incoming webhook.
+type SlackNotifier struct {
+	Poster *Poster
+}
+
+// Notify posts a {"text": ...} message to the webhook URL in target.
+func (s *SlackNotifier) Notify(ctx context.Context, target string, n *Notification) (int, error) {
+	text := fmt.Sprintf("%s *%s*\n%s", severityIcon(n.Severity), n.Subject(), n.Body())
+	return s.Poster.PostJSON(ctx, target, nil, map[string]string{"text": text})
+}
+
+// WebhookNotifier posts the structured notification as JSON.
+type WebhookNotifier struct {
+	Poster *Poster
+}
+
+// webhookPayload is the generic webhook body.
+type webhookPayload struct {
+	Event string `json:"event"`
+	Text  string `json:"text"` // Slack-compatible summary line
+	*Notification
+}
+
+// Notify posts the notification fields plus a summary to the URL in target.
+func (w *WebhookNotifier) Notify(ctx context.Context, target string, n *Notification) (int, error) {
+	event := "escalation"
+	if n.Reescalation {
+		event = "reescalation"
+	}
+	return w.Poster.PostJSON(ctx, target, nil, webhookPayload{Event: event, Text: n.Subject(), Notification: n})
+}
+
+// severityIcon returns a Slack emoji shortcode for a severity.
+func severityIcon(severity string) string {
+	switch severity {
+	case config.SeverityCritical:
+		return ":rotating_light:"
+	case config.SeverityHigh:
+		return ":warning:"
+	case config.SeverityMedium:
+		return ":loudspeaker:"
+	default:
+		return ":information_source:"
+	}
+}
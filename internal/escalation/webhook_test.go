@@ -0,0 +1,169 @@
+package escalation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// testPoster returns a poster that records backoff delays instead of sleeping.
+func testPoster(maxAttempts int, delays *[]time.Duration) *Poster {
+	p := NewPoster(&config.EscalationWebhookConfig{MaxAttempts: maxAttempts, InitialBackoff: "100ms"})
+	p.sleep = func(_ context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return p
+}
+
+func TestPoster_RetriesWithBackoff(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	attempts, err := testPoster(5, &delays).PostJSON(context.Background(), srv.URL, nil, map[string]string{"text": "hi"})
+	if err != nil {
+		t.Fatalf("PostJSON error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if len(delays) != 2 || delays[0] != 100*time.Millisecond || delays[1] != 200*time.Millisecond {
+		t.Errorf("backoff delays = %v, want [100ms 200ms]", delays)
+	}
+}
+
+func TestPoster_GivesUpAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	attempts, err := testPoster(3, &delays).PostJSON(context.Background(), srv.URL, nil, struct{}{})
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("PostJSON error = %v, want HTTP 429", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestPoster_NoRetryOnClientError(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	attempts, err := testPoster(3, &delays).PostJSON(context.Background(), srv.URL, nil, struct{}{})
+	if err == nil || !strings.Contains(err.Error(), "invalid_payload") {
+		t.Fatalf("PostJSON error = %v, want response body in error", err)
+	}
+	if attempts != 1 || calls != 1 {
+		t.Errorf("attempts = %d, calls = %d, want 1", attempts, calls)
+	}
+}
+
+func TestSlackNotifier_Payload(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	s := &SlackNotifier{Poster: NewPoster(nil)}
+	if _, err := s.Notify(context.Background(), srv.URL, testNotification()); err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+	if !strings.Contains(got["text"], "[HIGH] Build broken on main") {
+		t.Errorf("slack text = %q", got["text"])
+	}
+}
+
+func TestWebhookNotifier_Payload(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	w := &WebhookNotifier{Poster: NewPoster(nil)}
+	if _, err := w.Notify(context.Background(), srv.URL, testNotification()); err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+	if got["event"] != "escalation" || got["id"] != "hq-abc" || got["severity"] != "high" {
+		t.Errorf("webhook payload = %v", got)
+	}
+}
+
+func TestSMSNotifier(t *testing.T) {
+	var got map[string]string
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	t.Setenv("GT_TEST_SMS_TOKEN", "s3cret")
+	s := NewSMSNotifier(&config.EscalationSMSConfig{URL: srv.URL, TokenEnv: "GT_TEST_SMS_TOKEN"}, NewPoster(nil))
+	if _, err := s.Notify(context.Background(), "+15551234567", testNotification()); err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+	if got["to"] != "+15551234567" || !strings.Contains(got["message"], "Build broken") {
+		t.Errorf("sms payload = %v", got)
+	}
+	if auth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestSMSNotifier_TruncatesByCharacter(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	n := testNotification()
+	n.Reason = strings.Repeat("é", 400)
+	s := NewSMSNotifier(&config.EscalationSMSConfig{URL: srv.URL}, NewPoster(nil))
+	if _, err := s.Notify(context.Background(), "+15551234567", n); err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+	msg := got["message"]
+	if !utf8.ValidString(msg) {
+		t.Errorf("truncated message is not valid UTF-8: %q", msg)
+	}
+	if n := utf8.RuneCountInString(msg); n != smsMaxLen || !strings.HasSuffix(msg, "...") {
+		t.Errorf("message has %d characters, want %d ending in ...", n, smsMaxLen)
+	}
+}
+
+func TestSMSNotifier_MissingToken(t *testing.T) {
+	s := NewSMSNotifier(&config.EscalationSMSConfig{URL: "http://127.0.0.1:0", TokenEnv: "GT_TEST_SMS_TOKEN_UNSET"}, NewPoster(nil))
+	if _, err := s.Notify(context.Background(), "+15551234567", testNotification()); err == nil {
+		t.Error("expected error when token env is unset")
+	}
+}
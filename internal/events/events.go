@@ -74,6 +74,10 @@ const (
 	TypeSchedulerDispatch       = "scheduler_dispatch"        // Bead dispatched from scheduler
 	TypeSchedulerDispatchFailed = "scheduler_dispatch_failed" // Bead dispatch failed (requeued)
 	TypeSchedulerCloseRetry     = "scheduler_close_retry"     // Context close needed last-resort attempt
+
+	// Quota events (emitted by gt quota rotate --auto)
+	TypeQuotaRotate   = "quota_rotate"   // Limited session moved to another account
+	TypeQuotaFailover = "quota_failover" // Session moved to a fallback agent preset
 )
 
 // EventsFile is the name of the raw events log.
@@ -368,3 +372,21 @@ func SchedulerDispatchFailedPayload(beadID, rig, errMsg string) map[string]inter
 		"error": errMsg,
 	}
 }
+
+// QuotaRotatePayload creates a payload for quota rotation events.
+func QuotaRotatePayload(session, fromAccount, toAccount string) map[string]interface{} {
+	return map[string]interface{}{
+		"session": session,
+		"from":    fromAccount,
+		"to":      toAccount,
+	}
+}
+
+// QuotaFailoverPayload creates a payload for quota failover events.
+func QuotaFailoverPayload(session, fromAgent, toAgent string) map[string]interface{} {
+	return map[string]interface{}{
+		"session":    session,
+		"from_agent": fromAgent,
+		"to_agent":   toAgent,
+	}
+}
@@ -0,0 +1,162 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Record is an event read back from the events log. Offset is the byte
+// offset just past the event's line: reading again from Offset resumes with
+// the next event, so it doubles as a stream cursor (e.g., an SSE event ID).
+type Record struct {
+	Offset int64 `json:"offset"`
+	Event  Event `json:"event"`
+}
+
+// Filter selects events from the log. Each non-empty field must match;
+// within a field any listed value matches.
+type Filter struct {
+	Types      []string // event types (e.g., "merged", "session_death")
+	Actors     []string // exact actors, or "<prefix>/" to match everything under it
+	Rigs       []string // rig from the payload, or the first segment of the actor
+	Visibility []string // "audit" or "feed"; events logged "both" match either
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e *Event) bool {
+	if len(f.Types) > 0 && !contains(f.Types, e.Type) {
+		return false
+	}
+	if len(f.Actors) > 0 && !matchActor(f.Actors, e.Actor) {
+		return false
+	}
+	if len(f.Rigs) > 0 && !contains(f.Rigs, EventRig(e)) {
+		return false
+	}
+	if len(f.Visibility) > 0 {
+		if e.Visibility != VisibilityBoth && !contains(f.Visibility, e.Visibility) {
+			return false
+		}
+	}
+	return true
+}
+
+// EventRig returns the rig an event belongs to: its payload "rig" field, or
+// the first segment of an actor address like "gastown/polecats/Toast".
+// Returns "" for town-level events.
+func EventRig(e *Event) string {
+	if rig, ok := e.Payload["rig"].(string); ok && rig != "" {
+		return rig
+	}
+	if idx := strings.Index(e.Actor, "/"); idx > 0 {
+		return e.Actor[:idx]
+	}
+	return ""
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func matchActor(actors []string, actor string) bool {
+	for _, a := range actors {
+		if a == actor || (strings.HasSuffix(a, "/") && strings.HasPrefix(actor, a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogSize returns the current size of the events log at path, which is the
+// offset a stream should start from to see only new events.
+func LogSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Follow streams events matching filter from the events log at path,
+// starting at offset, and keeps following the log until ctx is done or fn
+// returns an error. If offset is past the end of the log (it was truncated
+// or replaced), following restarts from the beginning. Malformed lines are
+// skipped; a partially written last line is held until it is complete.
+func Follow(ctx context.Context, path string, offset int64, filter Filter, poll time.Duration, fn func(Record) error) error {
+	if offset < 0 {
+		offset = 0
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for {
+		next, err := readFrom(path, offset, filter, fn)
+		if err != nil {
+			return err
+		}
+		offset = next
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readFrom delivers the complete events after offset and returns the offset
+// to continue from.
+func readFrom(path string, offset int64, filter Filter, fn func(Record) error) (int64, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the town events log
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return offset, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return offset, fmt.Errorf("reading events log: %w", err)
+	}
+	if offset > info.Size() {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, fmt.Errorf("seeking events log: %w", err)
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// EOF, possibly mid-line: resume at the start of the partial line.
+			return offset, nil
+		}
+		offset += int64(len(line))
+
+		var e Event
+		if json.Unmarshal(line, &e) != nil || !filter.Match(&e) {
+			continue
+		}
+		if err := fn(Record{Offset: offset, Event: e}); err != nil {
+			return offset, err
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func appendEvents(t *testing.T, path string, evs ...Event) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range evs {
+		data, _ := json.Marshal(e)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	merged := &Event{Type: TypeMerged, Actor: "gastown/refinery", Visibility: VisibilityFeed}
+	death := &Event{Type: TypeSessionDeath, Actor: "daemon", Visibility: VisibilityBoth, Payload: map[string]interface{}{"rig": "beads"}}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		event  *Event
+		want   bool
+	}{
+		{"empty filter", Filter{}, merged, true},
+		{"type match", Filter{Types: []string{TypeMerged, TypeDone}}, merged, true},
+		{"type mismatch", Filter{Types: []string{TypeDone}}, merged, false},
+		{"actor prefix", Filter{Actors: []string{"gastown/"}}, merged, true},
+		{"actor exact mismatch", Filter{Actors: []string{"gastown"}}, merged, false},
+		{"rig from actor", Filter{Rigs: []string{"gastown"}}, merged, true},
+		{"rig from payload", Filter{Rigs: []string{"beads"}}, death, true},
+		{"visibility audit excludes feed", Filter{Visibility: []string{VisibilityAudit}}, merged, false},
+		{"visibility both matches audit", Filter{Visibility: []string{VisibilityAudit}}, death, true},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(tt.event); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFollow_ResumesFromOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EventsFile)
+	appendEvents(t, path,
+		Event{Type: TypeSling, Actor: "mayor"},
+		Event{Type: TypeMerged, Actor: "gastown/refinery"},
+		Event{Type: TypeDone, Actor: "gastown/polecats/toast"},
+	)
+	// A torn write must not be delivered until it is complete.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	_, _ = f.WriteString(`{"type":"merged","act`)
+	_ = f.Close()
+
+	errStop := errors.New("stop")
+	var got []Record
+	collect := func(rec Record) error {
+		got = append(got, rec)
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Follow(ctx, path, 0, Filter{}, time.Millisecond, collect); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d events, want 3 complete events", len(got))
+	}
+
+	// Resuming after the first event yields the rest.
+	var resumed []string
+	err := Follow(context.Background(), path, got[0].Offset, Filter{Types: []string{TypeDone}}, time.Millisecond, func(rec Record) error {
+		resumed = append(resumed, rec.Event.Type)
+		return errStop
+	})
+	if !errors.Is(err, errStop) || len(resumed) != 1 || resumed[0] != TypeDone {
+		t.Errorf("resume = %v, %v; want [done]", resumed, err)
+	}
+
+	// An offset past the end (log was truncated) restarts from the beginning.
+	var restarted []string
+	_ = Follow(context.Background(), path, 1<<20, Filter{}, time.Millisecond, func(rec Record) error {
+		restarted = append(restarted, rec.Event.Type)
+		return errStop
+	})
+	if len(restarted) != 1 || restarted[0] != TypeSling {
+		t.Errorf("restart = %v, want [sling]", restarted)
+	}
+}
+
+func TestLogSize_Missing(t *testing.T) {
+	size, err := LogSize(filepath.Join(t.TempDir(), EventsFile))
+	if err != nil || size != 0 {
+		t.Errorf("LogSize(missing) = %d, %v; want 0, nil", size, err)
+	}
+}
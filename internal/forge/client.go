@@ -0,0 +1,122 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiTimeout bounds a single forge API request.
+const apiTimeout = 30 * time.Second
+
+// APIError is a non-2xx response from a forge API.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s %s: HTTP %d", e.Method, e.Path, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: HTTP %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// client is a minimal JSON REST client shared by the forge implementations.
+type client struct {
+	base    string
+	http    *http.Client
+	headers map[string]string
+}
+
+func newClient(base string, headers map[string]string) *client {
+	return &client{
+		base:    strings.TrimSuffix(base, "/"),
+		http:    &http.Client{Timeout: apiTimeout},
+		headers: headers,
+	}
+}
+
+// do sends in (if non-nil) as JSON and decodes a 2xx response into out (if
+// non-nil). Other responses become *APIError.
+func (c *client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{Method: method, Path: path, StatusCode: resp.StatusCode}
+		var msg struct {
+			Message any `json:"message"`
+			Error   any `json:"error"`
+		}
+		if data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10)); json.Unmarshal(data, &msg) == nil {
+			switch {
+			case msg.Message != nil:
+				apiErr.Message = fmt.Sprint(msg.Message)
+			case msg.Error != nil:
+				apiErr.Message = fmt.Sprint(msg.Error)
+			}
+		}
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
+
+// statusCode returns the HTTP status of an *APIError, or 0.
+func statusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// notMergeable wraps a refused merge in ErrNotMergeable.
+func notMergeable(err error) error {
+	return fmt.Errorf("%w: %v", ErrNotMergeable, err)
+}
+
+// commitMessage joins a title and body the way git does.
+func commitMessage(title, message string) string {
+	if message == "" {
+		return title
+	}
+	return title + "\n\n" + message
+}
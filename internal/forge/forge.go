@@ -0,0 +1,256 @@
+// Package forge talks to code forges (GitHub, GitLab, Gitea) over their REST
+// APIs so the refinery can land work through pull requests on repositories
+// whose target branches are protected against direct pushes.
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrNotMergeable is returned by Merge when the forge refuses the merge:
+// conflicts, unmet branch protection, or a head that moved since the checks.
+var ErrNotMergeable = errors.New("pull request is not mergeable")
+
+// Pull request states, normalized across forges.
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+	StateMerged = "merged"
+)
+
+// PullRequest is a pull request (GitLab: merge request) on the forge.
+type PullRequest struct {
+	Number      int    `json:"number"` // GitLab: the MR iid
+	URL         string `json:"url"`
+	HeadSHA     string `json:"head_sha"`
+	State       string `json:"state"`
+	MergeCommit string `json:"merge_commit,omitempty"`
+}
+
+// PullRequestSpec describes the pull request to open for a branch.
+type PullRequestSpec struct {
+	Head  string // Source branch
+	Base  string // Target branch
+	Title string
+	Body  string
+}
+
+// CheckState is the normalized state of a status check.
+type CheckState string
+
+const (
+	CheckPending CheckState = "pending"
+	CheckSuccess CheckState = "success"
+	CheckFailure CheckState = "failure"
+)
+
+// Check is one status check (GitHub check run or commit status, GitLab
+// pipeline job status, Gitea commit status) on the PR's head commit.
+type Check struct {
+	Name  string     `json:"name"`
+	State CheckState `json:"state"`
+}
+
+// ReviewState summarizes the reviews on a pull request.
+type ReviewState string
+
+const (
+	ReviewNone             ReviewState = ""
+	ReviewApproved         ReviewState = "approved"
+	ReviewChangesRequested ReviewState = "changes_requested"
+)
+
+// Mergeability is whether the forge would accept a merge right now.
+type Mergeability string
+
+const (
+	// MergeableUnknown means the forge is still computing mergeability.
+	MergeableUnknown Mergeability = ""
+	MergeableClean   Mergeability = "clean"
+	// MergeableConflict means the branch conflicts with its base.
+	MergeableConflict Mergeability = "conflict"
+	// MergeableBlocked means branch protection (reviews, checks, an
+	// out-of-date branch) blocks the merge.
+	MergeableBlocked Mergeability = "blocked"
+)
+
+// Status is a snapshot of everything that gates merging a pull request.
+type Status struct {
+	PR        *PullRequest `json:"pr"`
+	Checks    []Check      `json:"checks,omitempty"`
+	Review    ReviewState  `json:"review,omitempty"`
+	Mergeable Mergeability `json:"mergeable,omitempty"`
+}
+
+// gating returns the checks that gate the merge: those named in required
+// (a required check that has not reported yet is pending), or all checks.
+func (s *Status) gating(required []string) []Check {
+	if len(required) == 0 {
+		return s.Checks
+	}
+	var out []Check
+	for _, name := range required {
+		c := Check{Name: name, State: CheckPending}
+		for _, reported := range s.Checks {
+			if reported.Name == name {
+				c = reported
+				break
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// FailedChecks returns the names of gating checks that failed.
+func (s *Status) FailedChecks(required []string) []string {
+	return s.checksIn(required, CheckFailure)
+}
+
+// PendingChecks returns the names of gating checks still running.
+func (s *Status) PendingChecks(required []string) []string {
+	return s.checksIn(required, CheckPending)
+}
+
+func (s *Status) checksIn(required []string, state CheckState) []string {
+	var names []string
+	for _, c := range s.gating(required) {
+		if c.State == state {
+			names = append(names, c.Name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// MergeMethod is how the forge combines the pull request into its base.
+type MergeMethod string
+
+const (
+	MethodSquash      MergeMethod = "squash"
+	MethodMerge       MergeMethod = "merge"
+	MethodRebase      MergeMethod = "rebase"
+	MethodFastForward MergeMethod = "fast-forward"
+)
+
+// MethodForStrategy maps a merge_queue.merge_strategy to a merge method.
+// Forges without a fast-forward-only method rebase instead.
+func MethodForStrategy(strategy string) MergeMethod {
+	switch strategy {
+	case config.MergeStrategyMergeNoFF:
+		return MethodMerge
+	case config.MergeStrategyRebaseFF:
+		return MethodRebase
+	case config.MergeStrategyFFOnly:
+		return MethodFastForward
+	default:
+		return MethodSquash
+	}
+}
+
+// MergeOptions controls a merge through the forge API.
+type MergeOptions struct {
+	Method MergeMethod
+	// Title and Message form the commit message for squash and merge
+	// commits; forges ignore them for rebase and fast-forward merges.
+	Title   string
+	Message string
+	// SHA is the head commit the checks ran on; the forge refuses the
+	// merge if the branch has moved since.
+	SHA string
+}
+
+// Forge is a code forge's pull request API.
+type Forge interface {
+	// Kind returns the forge type ("github", "gitlab" or "gitea").
+	Kind() string
+
+	// FindPullRequest returns the open pull request from head into base,
+	// or nil if there is none.
+	FindPullRequest(ctx context.Context, head, base string) (*PullRequest, error)
+
+	CreatePullRequest(ctx context.Context, spec PullRequestSpec) (*PullRequest, error)
+
+	// UpdatePullRequest sets the title and body of an existing pull request.
+	UpdatePullRequest(ctx context.Context, number int, spec PullRequestSpec) (*PullRequest, error)
+
+	// Status refreshes the pull request and reports its checks, reviews and
+	// mergeability.
+	Status(ctx context.Context, number int) (*Status, error)
+
+	// Merge merges the pull request and returns the commit now at the tip
+	// of its base branch.
+	Merge(ctx context.Context, number int, opts MergeOptions) (string, error)
+}
+
+// New returns a client for the configured forge. The repository defaults to
+// the one remoteURL (usually origin) points at; the token is read from
+// cfg.GetTokenEnv().
+func New(cfg *config.ForgeConfig, remoteURL string) (Forge, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	repo := cfg.Repo
+	if repo == "" {
+		repo = RepoFromRemoteURL(remoteURL)
+		if repo == "" {
+			return nil, fmt.Errorf("merge_queue.forge: set repo (cannot derive it from remote %q)", remoteURL)
+		}
+	}
+	token := os.Getenv(cfg.GetTokenEnv())
+	if token == "" {
+		return nil, fmt.Errorf("merge_queue.forge: $%s is not set", cfg.GetTokenEnv())
+	}
+
+	switch cfg.Type {
+	case config.ForgeGitHub:
+		return newGitHub(cfg.GetURL(), repo, token), nil
+	case config.ForgeGitLab:
+		return newGitLab(cfg.GetURL(), repo, token), nil
+	default:
+		return newGitea(cfg.GetURL(), repo, token), nil
+	}
+}
+
+// remoteURLPattern matches scp-style ("git@host:owner/repo.git") and URL
+// ("https://host/owner/repo", "ssh://git@host:22/owner/repo.git") remotes.
+var remoteURLPattern = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?[^:/]+(?::\d+)?[:/](.+?)(?:\.git)?/?$`)
+
+// RepoFromRemoteURL extracts the "owner/name" repository path from a git
+// remote URL. Returns "" if the URL has no recognizable path.
+func RepoFromRemoteURL(remoteURL string) string {
+	m := remoteURLPattern.FindStringSubmatch(strings.TrimSpace(remoteURL))
+	if m == nil || !strings.Contains(m[1], "/") {
+		return ""
+	}
+	return m[1]
+}
+
+// EnsurePullRequest opens a pull request for spec, or updates the title and
+// body of the one already open for the branch. Reports whether it was created.
+func EnsurePullRequest(ctx context.Context, f Forge, spec PullRequestSpec) (*PullRequest, bool, error) {
+	pr, err := f.FindPullRequest(ctx, spec.Head, spec.Base)
+	if err != nil {
+		return nil, false, fmt.Errorf("finding pull request for %s: %w", spec.Head, err)
+	}
+	if pr != nil {
+		pr, err = f.UpdatePullRequest(ctx, pr.Number, spec)
+		if err != nil {
+			return nil, false, fmt.Errorf("updating pull request: %w", err)
+		}
+		return pr, false, nil
+	}
+	pr, err = f.CreatePullRequest(ctx, spec)
+	if err != nil {
+		return nil, false, fmt.Errorf("creating pull request for %s: %w", spec.Head, err)
+	}
+	return pr, true, nil
+}
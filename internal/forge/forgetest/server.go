@@ -0,0 +1,440 @@
+// Package forgetest provides an in-memory forge for tests. It serves the
+// subset of the GitHub, GitLab and Gitea REST APIs that package forge uses.
+package forgetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// PR is a pull request held by the fake forge.
+type PR struct {
+	Number      int
+	Head, Base  string
+	Title, Body string
+	HeadSHA     string
+	State       string // forge.StateOpen, StateClosed or StateMerged
+	Checks      []forge.Check
+	Review      forge.ReviewState
+	Mergeable   forge.Mergeability
+	MergeCommit string
+
+	// Recorded by the merge endpoint.
+	MergeMethod  string
+	MergeTitle   string
+	MergeMessage string
+}
+
+// Server is a fake forge of one Kind hosting one Repo.
+type Server struct {
+	Kind  string
+	Repo  string
+	Token string
+
+	// HeadSHA resolves the branch a new PR is opened from to its head
+	// commit. Default: "sha-<branch>".
+	HeadSHA func(branch string) string
+
+	// OnStatus runs, under the server lock, every time a PR is read. Tests
+	// use it to advance checks and reviews between polls.
+	OnStatus func(pr *PR)
+
+	srv      *httptest.Server
+	mu       sync.Mutex
+	prs      map[int]*PR
+	next     int
+	requests []string
+}
+
+// NewServer starts a fake forge that accepts token. It is closed when the
+// test ends.
+func NewServer(t testing.TB, kind, repo, token string) *Server {
+	s := &Server{Kind: kind, Repo: repo, Token: token, prs: make(map[int]*PR), next: 1}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// URL returns the API base URL to put in config.ForgeConfig.URL.
+func (s *Server) URL() string {
+	switch s.Kind {
+	case config.ForgeGitLab:
+		return s.srv.URL + "/api/v4"
+	case config.ForgeGitea:
+		return s.srv.URL + "/api/v1"
+	}
+	return s.srv.URL
+}
+
+// AddPR stores pr (assigning its number) and returns the number.
+func (s *Server) AddPR(pr PR) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pr.Number = s.next
+	s.next++
+	if pr.State == "" {
+		pr.State = forge.StateOpen
+	}
+	s.prs[pr.Number] = &pr
+	return pr.Number
+}
+
+// PR returns a copy of PR number, or false if there is none.
+func (s *Server) PR(number int) (PR, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pr, ok := s.prs[number]
+	if !ok {
+		return PR{}, false
+	}
+	return *pr, true
+}
+
+// PRs returns copies of every PR, in number order.
+func (s *Server) PRs() []PR {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []PR
+	for _, pr := range s.prs {
+		out = append(out, *pr)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Number < out[j].Number })
+	return out
+}
+
+// Update mutates PR number under the server lock.
+func (s *Server) Update(number int, fn func(*PR)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pr, ok := s.prs[number]; ok {
+		fn(pr)
+	}
+}
+
+// Requests returns "METHOD path" for every request served so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+var (
+	pullPath   = regexp.MustCompile(`^/(?:pulls|merge_requests)/(\d+)(/reviews|/approvals|/merge)?$`)
+	commitPath = regexp.MustCompile(`^(?:/repository)?/commits/([^/]+)/(check-runs|status|statuses)$`)
+)
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "bad credentials"})
+		return
+	}
+	var prefix string
+	switch s.Kind {
+	case config.ForgeGitHub:
+		prefix = "/repos/" + s.Repo
+	case config.ForgeGitLab:
+		prefix = "/api/v4/projects/" + strings.ReplaceAll(url.PathEscape(s.Repo), "/", "%2F")
+	case config.ForgeGitea:
+		prefix = "/api/v1/repos/" + s.Repo
+	}
+	path := r.URL.EscapedPath()
+	if !strings.HasPrefix(path, prefix) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "not found"})
+		return
+	}
+	path = strings.TrimPrefix(path, prefix)
+
+	switch {
+	case path == "/pulls" || path == "/merge_requests":
+		if r.Method == http.MethodPost {
+			s.create(w, r)
+		} else {
+			s.list(w, r)
+		}
+	case pullPath.MatchString(path):
+		m := pullPath.FindStringSubmatch(path)
+		n, _ := strconv.Atoi(m[1])
+		pr, ok := s.prs[n]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "no such pull request"})
+			return
+		}
+		switch {
+		case m[2] == "/merge":
+			s.merge(w, r, pr)
+		case m[2] != "":
+			s.reviews(w, pr)
+		case r.Method == http.MethodGet:
+			if s.OnStatus != nil {
+				s.OnStatus(pr)
+			}
+			writeJSON(w, http.StatusOK, s.render(pr))
+		default:
+			var in map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			pr.Title = in["title"]
+			pr.Body = in["body"] + in["description"]
+			writeJSON(w, http.StatusOK, s.render(pr))
+		}
+	case commitPath.MatchString(path):
+		m := commitPath.FindStringSubmatch(path)
+		s.checks(w, m[1], m[2])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "not found"})
+	}
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	switch s.Kind {
+	case config.ForgeGitHub:
+		return r.Header.Get("Authorization") == "Bearer "+s.Token
+	case config.ForgeGitLab:
+		return r.Header.Get("PRIVATE-TOKEN") == s.Token
+	default:
+		return r.Header.Get("Authorization") == "token "+s.Token
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	head, base := q.Get("source_branch"), q.Get("target_branch")
+	if s.Kind == config.ForgeGitHub {
+		_, head, _ = strings.Cut(q.Get("head"), ":")
+		base = q.Get("base")
+	}
+	if s.Kind == config.ForgeGitea && q.Get("page") != "" && q.Get("page") != "1" {
+		writeJSON(w, http.StatusOK, []any{})
+		return
+	}
+	out := []any{}
+	for _, n := range s.numbers() {
+		pr := s.prs[n]
+		if pr.State != forge.StateOpen {
+			continue
+		}
+		if (head != "" && pr.Head != head) || (base != "" && pr.Base != base) {
+			continue
+		}
+		out = append(out, s.render(pr))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var in map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	pr := &PR{
+		Number:    s.next,
+		Head:      in["head"] + in["source_branch"],
+		Base:      in["base"] + in["target_branch"],
+		Title:     in["title"],
+		Body:      in["body"] + in["description"],
+		State:     forge.StateOpen,
+		Mergeable: forge.MergeableClean,
+	}
+	pr.HeadSHA = "sha-" + pr.Head
+	if s.HeadSHA != nil {
+		pr.HeadSHA = s.HeadSHA(pr.Head)
+	}
+	s.next++
+	s.prs[pr.Number] = pr
+	writeJSON(w, http.StatusCreated, s.render(pr))
+}
+
+func (s *Server) merge(w http.ResponseWriter, r *http.Request, pr *PR) {
+	var in map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&in)
+	str := func(k string) string { v, _ := in[k].(string); return v }
+
+	var sha string
+	switch s.Kind {
+	case config.ForgeGitHub:
+		pr.MergeMethod, sha = str("merge_method"), str("sha")
+		pr.MergeTitle, pr.MergeMessage = str("commit_title"), str("commit_message")
+	case config.ForgeGitLab:
+		pr.MergeMethod, sha = "merge", str("sha")
+		if squash, _ := in["squash"].(bool); squash {
+			pr.MergeMethod = "squash"
+		}
+		msg := str("squash_commit_message") + str("merge_commit_message")
+		pr.MergeTitle, pr.MergeMessage, _ = strings.Cut(msg, "\n\n")
+	case config.ForgeGitea:
+		pr.MergeMethod, sha = str("Do"), str("head_commit_id")
+		pr.MergeTitle, pr.MergeMessage = str("MergeTitleField"), str("MergeMessageField")
+	}
+
+	switch {
+	case pr.State != forge.StateOpen || pr.Mergeable == forge.MergeableConflict || pr.Mergeable == forge.MergeableBlocked:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"message": "pull request is not mergeable"})
+		return
+	case sha != "" && sha != pr.HeadSHA:
+		writeJSON(w, http.StatusConflict, map[string]string{"message": "head branch was modified"})
+		return
+	}
+	pr.State = forge.StateMerged
+	if pr.MergeCommit == "" {
+		pr.MergeCommit = "merge-" + pr.HeadSHA
+	}
+
+	switch s.Kind {
+	case config.ForgeGitHub:
+		writeJSON(w, http.StatusOK, map[string]any{"sha": pr.MergeCommit, "merged": true})
+	case config.ForgeGitLab:
+		writeJSON(w, http.StatusOK, s.render(pr))
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (s *Server) reviews(w http.ResponseWriter, pr *PR) {
+	if s.Kind == config.ForgeGitLab {
+		writeJSON(w, http.StatusOK, map[string]bool{"approved": pr.Review == forge.ReviewApproved})
+		return
+	}
+	out := []any{}
+	state := ""
+	switch pr.Review {
+	case forge.ReviewApproved:
+		state = "APPROVED"
+	case forge.ReviewChangesRequested:
+		state = "CHANGES_REQUESTED"
+		if s.Kind == config.ForgeGitea {
+			state = "REQUEST_CHANGES"
+		}
+	}
+	if state != "" {
+		out = append(out, map[string]any{"state": state, "user": map[string]string{"login": "reviewer"}})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) checks(w http.ResponseWriter, sha, kind string) {
+	var checks []forge.Check
+	for _, pr := range s.prs {
+		if pr.HeadSHA == sha {
+			checks = pr.Checks
+		}
+	}
+
+	switch kind {
+	case "check-runs":
+		runs := []any{}
+		for _, c := range checks {
+			run := map[string]any{"name": c.Name, "status": "in_progress"}
+			if c.State != forge.CheckPending {
+				run["status"], run["conclusion"] = "completed", string(c.State)
+			}
+			runs = append(runs, run)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"check_runs": runs})
+	case "status":
+		statuses := []any{}
+		if s.Kind == config.ForgeGitea {
+			for _, c := range checks {
+				statuses = append(statuses, map[string]string{"context": c.Name, "status": string(c.State)})
+			}
+		}
+		// GitHub checks are all served as check runs.
+		writeJSON(w, http.StatusOK, map[string]any{"statuses": statuses})
+	case "statuses":
+		out := []any{}
+		for _, c := range checks {
+			status := map[forge.CheckState]string{
+				forge.CheckPending: "running",
+				forge.CheckSuccess: "success",
+				forge.CheckFailure: "failed",
+			}[c.State]
+			out = append(out, map[string]string{"name": c.Name, "status": status})
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// render returns pr in the Kind's JSON shape.
+func (s *Server) render(pr *PR) map[string]any {
+	htmlURL := fmt.Sprintf("%s/%s/pull/%d", s.srv.URL, s.Repo, pr.Number)
+	merged := pr.State == forge.StateMerged
+	switch s.Kind {
+	case config.ForgeGitLab:
+		state := map[string]string{forge.StateOpen: "opened", forge.StateClosed: "closed", forge.StateMerged: "merged"}[pr.State]
+		status := map[forge.Mergeability]string{
+			forge.MergeableUnknown:  "checking",
+			forge.MergeableClean:    "mergeable",
+			forge.MergeableConflict: "conflict",
+			forge.MergeableBlocked:  "not_approved",
+		}[pr.Mergeable]
+		if pr.Review == forge.ReviewChangesRequested {
+			status = "requested_changes"
+		}
+		out := map[string]any{
+			"iid": pr.Number, "web_url": htmlURL, "state": state, "sha": pr.HeadSHA,
+			"has_conflicts": pr.Mergeable == forge.MergeableConflict, "detailed_merge_status": status,
+		}
+		if merged {
+			out["merge_commit_sha"] = pr.MergeCommit
+		}
+		return out
+	default:
+		state := "open"
+		if pr.State != forge.StateOpen {
+			state = "closed"
+		}
+		out := map[string]any{
+			"number": pr.Number, "html_url": htmlURL, "state": state, "merged": merged,
+			"head": map[string]string{"ref": pr.Head, "sha": pr.HeadSHA},
+			"base": map[string]string{"ref": pr.Base},
+		}
+		if merged {
+			out["merge_commit_sha"] = pr.MergeCommit
+		}
+		if s.Kind == config.ForgeGitea {
+			out["mergeable"] = pr.Mergeable != forge.MergeableConflict
+			return out
+		}
+		switch pr.Mergeable {
+		case forge.MergeableUnknown:
+			out["mergeable"], out["mergeable_state"] = nil, "unknown"
+		case forge.MergeableConflict:
+			out["mergeable"], out["mergeable_state"] = false, "dirty"
+		case forge.MergeableBlocked:
+			out["mergeable"], out["mergeable_state"] = true, "blocked"
+		default:
+			out["mergeable"], out["mergeable_state"] = true, "clean"
+		}
+		return out
+	}
+}
+
+func (s *Server) numbers() []int {
+	var ns []int
+	for n := range s.prs {
+		ns = append(ns, n)
+	}
+	sort.Ints(ns)
+	return ns
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
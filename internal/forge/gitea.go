@@ -0,0 +1,168 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// gitea implements Forge against the Gitea (and Forgejo) REST API (v1).
+type gitea struct {
+	c    *client
+	repo string // owner/name
+}
+
+func newGitea(base, repo, token string) *gitea {
+	return &gitea{
+		c:    newClient(base, map[string]string{"Authorization": "token " + token}),
+		repo: repo,
+	}
+}
+
+type giteaPull struct {
+	Number         int    `json:"number"`
+	HTMLURL        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	Mergeable      bool   `json:"mergeable"`
+	Head           struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p *giteaPull) toPR() *PullRequest {
+	pr := &PullRequest{Number: p.Number, URL: p.HTMLURL, HeadSHA: p.Head.SHA, State: StateOpen}
+	switch {
+	case p.Merged:
+		pr.State = StateMerged
+		pr.MergeCommit = p.MergeCommitSHA
+	case p.State == "closed":
+		pr.State = StateClosed
+	}
+	return pr
+}
+
+func (g *gitea) Kind() string { return config.ForgeGitea }
+
+func (g *gitea) path(format string, args ...any) string {
+	return "/repos/" + g.repo + fmt.Sprintf(format, args...)
+}
+
+func (g *gitea) FindPullRequest(ctx context.Context, head, base string) (*PullRequest, error) {
+	// Older Gitea versions cannot filter by branch; page through open PRs.
+	for page := 1; ; page++ {
+		var pulls []giteaPull
+		if err := g.c.do(ctx, http.MethodGet, g.path("/pulls?state=open&limit=50&page=%d", page), nil, &pulls); err != nil {
+			return nil, err
+		}
+		for i := range pulls {
+			if pulls[i].Head.Ref == head && pulls[i].Base.Ref == base {
+				return pulls[i].toPR(), nil
+			}
+		}
+		if len(pulls) < 50 {
+			return nil, nil
+		}
+	}
+}
+
+func (g *gitea) CreatePullRequest(ctx context.Context, spec PullRequestSpec) (*PullRequest, error) {
+	in := map[string]string{"head": spec.Head, "base": spec.Base, "title": spec.Title, "body": spec.Body}
+	var p giteaPull
+	if err := g.c.do(ctx, http.MethodPost, g.path("/pulls"), in, &p); err != nil {
+		return nil, err
+	}
+	return p.toPR(), nil
+}
+
+func (g *gitea) UpdatePullRequest(ctx context.Context, number int, spec PullRequestSpec) (*PullRequest, error) {
+	in := map[string]string{"title": spec.Title, "body": spec.Body}
+	var p giteaPull
+	if err := g.c.do(ctx, http.MethodPatch, g.path("/pulls/%d", number), in, &p); err != nil {
+		return nil, err
+	}
+	return p.toPR(), nil
+}
+
+func (g *gitea) Status(ctx context.Context, number int) (*Status, error) {
+	var p giteaPull
+	if err := g.c.do(ctx, http.MethodGet, g.path("/pulls/%d", number), nil, &p); err != nil {
+		return nil, err
+	}
+	st := &Status{PR: p.toPR(), Mergeable: MergeableClean}
+	if !p.Mergeable {
+		st.Mergeable = MergeableConflict
+	}
+
+	var combined struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			Status  string `json:"status"`
+		} `json:"statuses"`
+	}
+	if err := g.c.do(ctx, http.MethodGet, g.path("/commits/%s/status", p.Head.SHA), nil, &combined); err != nil {
+		return nil, err
+	}
+	for _, s := range combined.Statuses {
+		st.Checks = append(st.Checks, Check{Name: s.Context, State: commitStatusState(s.Status)})
+	}
+
+	var reviews []struct {
+		State     string `json:"state"`
+		Dismissed bool   `json:"dismissed"`
+		Stale     bool   `json:"stale"`
+		User      struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := g.c.do(ctx, http.MethodGet, g.path("/pulls/%d/reviews", number), nil, &reviews); err != nil {
+		return nil, err
+	}
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		if r.Dismissed || r.Stale {
+			delete(latest, r.User.Login)
+			continue
+		}
+		if r.State == "APPROVED" || r.State == "REQUEST_CHANGES" {
+			latest[r.User.Login] = r.State
+		}
+	}
+	st.Review = summarizeReviews(latest, "APPROVED", "REQUEST_CHANGES")
+	return st, nil
+}
+
+func (g *gitea) Merge(ctx context.Context, number int, opts MergeOptions) (string, error) {
+	do := string(opts.Method)
+	if opts.Method == MethodFastForward {
+		do = "fast-forward-only"
+	}
+	in := map[string]string{
+		"Do":                do,
+		"MergeTitleField":   opts.Title,
+		"MergeMessageField": opts.Message,
+		"head_commit_id":    opts.SHA,
+	}
+	if err := g.c.do(ctx, http.MethodPost, g.path("/pulls/%d/merge", number), in, nil); err != nil {
+		if code := statusCode(err); code == http.StatusMethodNotAllowed || code == http.StatusConflict {
+			return "", notMergeable(err)
+		}
+		return "", err
+	}
+	// The merge endpoint returns no body; read the merge commit back.
+	var p giteaPull
+	if err := g.c.do(ctx, http.MethodGet, g.path("/pulls/%d", number), nil, &p); err != nil {
+		return "", fmt.Errorf("reading merged pull request: %w", err)
+	}
+	if !p.Merged {
+		return "", fmt.Errorf("%w: forge reported the pull request as not merged", ErrNotMergeable)
+	}
+	return p.MergeCommitSHA, nil
+}
@@ -0,0 +1,217 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// gitHub implements Forge against the GitHub REST API (also GitHub
+// Enterprise, via a custom URL).
+type gitHub struct {
+	c    *client
+	repo string // owner/name
+}
+
+func newGitHub(base, repo, token string) *gitHub {
+	return &gitHub{
+		c: newClient(base, map[string]string{
+			"Authorization":        "Bearer " + token,
+			"Accept":               "application/vnd.github+json",
+			"X-GitHub-Api-Version": "2022-11-28",
+		}),
+		repo: repo,
+	}
+}
+
+type gitHubPull struct {
+	Number         int    `json:"number"`
+	HTMLURL        string `json:"html_url"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	Mergeable      *bool  `json:"mergeable"`
+	MergeableState string `json:"mergeable_state"`
+	Head           struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+func (p *gitHubPull) toPR() *PullRequest {
+	pr := &PullRequest{Number: p.Number, URL: p.HTMLURL, HeadSHA: p.Head.SHA, State: StateOpen}
+	switch {
+	case p.Merged:
+		pr.State = StateMerged
+		pr.MergeCommit = p.MergeCommitSHA
+	case p.State == "closed":
+		pr.State = StateClosed
+	}
+	return pr
+}
+
+func (g *gitHub) Kind() string { return config.ForgeGitHub }
+
+func (g *gitHub) path(format string, args ...any) string {
+	return "/repos/" + g.repo + fmt.Sprintf(format, args...)
+}
+
+func (g *gitHub) FindPullRequest(ctx context.Context, head, base string) (*PullRequest, error) {
+	owner, _, _ := strings.Cut(g.repo, "/")
+	q := url.Values{"state": {"open"}, "head": {owner + ":" + head}, "base": {base}}
+	var pulls []gitHubPull
+	if err := g.c.do(ctx, http.MethodGet, g.path("/pulls?%s", q.Encode()), nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	return pulls[0].toPR(), nil
+}
+
+func (g *gitHub) CreatePullRequest(ctx context.Context, spec PullRequestSpec) (*PullRequest, error) {
+	in := map[string]string{"head": spec.Head, "base": spec.Base, "title": spec.Title, "body": spec.Body}
+	var p gitHubPull
+	if err := g.c.do(ctx, http.MethodPost, g.path("/pulls"), in, &p); err != nil {
+		return nil, err
+	}
+	return p.toPR(), nil
+}
+
+func (g *gitHub) UpdatePullRequest(ctx context.Context, number int, spec PullRequestSpec) (*PullRequest, error) {
+	in := map[string]string{"title": spec.Title, "body": spec.Body}
+	var p gitHubPull
+	if err := g.c.do(ctx, http.MethodPatch, g.path("/pulls/%d", number), in, &p); err != nil {
+		return nil, err
+	}
+	return p.toPR(), nil
+}
+
+func (g *gitHub) Status(ctx context.Context, number int) (*Status, error) {
+	var p gitHubPull
+	if err := g.c.do(ctx, http.MethodGet, g.path("/pulls/%d", number), nil, &p); err != nil {
+		return nil, err
+	}
+	st := &Status{PR: p.toPR()}
+	switch {
+	case p.Mergeable == nil || p.MergeableState == "unknown":
+		st.Mergeable = MergeableUnknown
+	case !*p.Mergeable || p.MergeableState == "dirty":
+		st.Mergeable = MergeableConflict
+	case p.MergeableState == "blocked" || p.MergeableState == "behind":
+		st.Mergeable = MergeableBlocked
+	default:
+		st.Mergeable = MergeableClean
+	}
+
+	// Checks come from both the Checks API (Actions, apps) and the older
+	// commit status API (external CI).
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := g.c.do(ctx, http.MethodGet, g.path("/commits/%s/check-runs?per_page=100", p.Head.SHA), nil, &runs); err != nil {
+		return nil, err
+	}
+	for _, r := range runs.CheckRuns {
+		c := Check{Name: r.Name, State: CheckPending}
+		if r.Status == "completed" {
+			switch r.Conclusion {
+			case "success", "neutral", "skipped":
+				c.State = CheckSuccess
+			default:
+				c.State = CheckFailure
+			}
+		}
+		st.Checks = append(st.Checks, c)
+	}
+	var combined struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+	if err := g.c.do(ctx, http.MethodGet, g.path("/commits/%s/status", p.Head.SHA), nil, &combined); err != nil {
+		return nil, err
+	}
+	for _, s := range combined.Statuses {
+		st.Checks = append(st.Checks, Check{Name: s.Context, State: commitStatusState(s.State)})
+	}
+
+	var reviews []struct {
+		State string `json:"state"`
+		User  struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := g.c.do(ctx, http.MethodGet, g.path("/pulls/%d/reviews?per_page=100", number), nil, &reviews); err != nil {
+		return nil, err
+	}
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		if r.State == "APPROVED" || r.State == "CHANGES_REQUESTED" || r.State == "DISMISSED" {
+			latest[r.User.Login] = r.State
+		}
+	}
+	st.Review = summarizeReviews(latest, "APPROVED", "CHANGES_REQUESTED")
+	return st, nil
+}
+
+func (g *gitHub) Merge(ctx context.Context, number int, opts MergeOptions) (string, error) {
+	method := opts.Method
+	if method == MethodFastForward {
+		method = MethodRebase // GitHub has no fast-forward-only merge
+	}
+	in := map[string]string{"merge_method": string(method), "sha": opts.SHA}
+	if method != MethodRebase {
+		in["commit_title"] = opts.Title
+		in["commit_message"] = opts.Message
+	}
+	var out struct {
+		SHA    string `json:"sha"`
+		Merged bool   `json:"merged"`
+	}
+	if err := g.c.do(ctx, http.MethodPut, g.path("/pulls/%d/merge", number), in, &out); err != nil {
+		if code := statusCode(err); code == http.StatusMethodNotAllowed || code == http.StatusConflict {
+			return "", notMergeable(err)
+		}
+		return "", err
+	}
+	if !out.Merged {
+		return "", fmt.Errorf("%w: forge reported the pull request as not merged", ErrNotMergeable)
+	}
+	return out.SHA, nil
+}
+
+// commitStatusState maps GitHub/Gitea commit status states.
+func commitStatusState(state string) CheckState {
+	switch state {
+	case "success", "warning":
+		return CheckSuccess
+	case "failure", "error":
+		return CheckFailure
+	default:
+		return CheckPending
+	}
+}
+
+// summarizeReviews reduces each reviewer's latest review state: any change
+// request wins over approvals.
+func summarizeReviews(latest map[string]string, approved, changesRequested string) ReviewState {
+	review := ReviewNone
+	for _, state := range latest {
+		switch state {
+		case changesRequested:
+			return ReviewChangesRequested
+		case approved:
+			review = ReviewApproved
+		}
+	}
+	return review
+}
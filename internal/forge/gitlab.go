@@ -0,0 +1,181 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// gitLab implements Forge against the GitLab REST API (v4). Pull requests
+// are merge requests, identified by their project-scoped iid.
+type gitLab struct {
+	c       *client
+	project string // URL-encoded project path
+}
+
+func newGitLab(base, repo, token string) *gitLab {
+	return &gitLab{
+		c:       newClient(base, map[string]string{"PRIVATE-TOKEN": token}),
+		project: strings.ReplaceAll(url.PathEscape(repo), "/", "%2F"),
+	}
+}
+
+type gitLabMR struct {
+	IID                 int    `json:"iid"`
+	WebURL              string `json:"web_url"`
+	State               string `json:"state"`
+	SHA                 string `json:"sha"`
+	MergeCommitSHA      string `json:"merge_commit_sha"`
+	SquashCommitSHA     string `json:"squash_commit_sha"`
+	HasConflicts        bool   `json:"has_conflicts"`
+	DetailedMergeStatus string `json:"detailed_merge_status"`
+}
+
+func (m *gitLabMR) toPR() *PullRequest {
+	pr := &PullRequest{Number: m.IID, URL: m.WebURL, HeadSHA: m.SHA, State: StateOpen}
+	switch m.State {
+	case "merged":
+		pr.State = StateMerged
+		pr.MergeCommit = m.mergedSHA()
+	case "closed":
+		pr.State = StateClosed
+	}
+	return pr
+}
+
+// mergedSHA is the commit a merge left on the target: the merge commit, the
+// squash commit, or the head itself for fast-forward merges.
+func (m *gitLabMR) mergedSHA() string {
+	switch {
+	case m.MergeCommitSHA != "":
+		return m.MergeCommitSHA
+	case m.SquashCommitSHA != "":
+		return m.SquashCommitSHA
+	default:
+		return m.SHA
+	}
+}
+
+func (g *gitLab) Kind() string { return config.ForgeGitLab }
+
+func (g *gitLab) path(format string, args ...any) string {
+	return "/projects/" + g.project + fmt.Sprintf(format, args...)
+}
+
+func (g *gitLab) FindPullRequest(ctx context.Context, head, base string) (*PullRequest, error) {
+	q := url.Values{"state": {"opened"}, "source_branch": {head}, "target_branch": {base}}
+	var mrs []gitLabMR
+	if err := g.c.do(ctx, http.MethodGet, g.path("/merge_requests?%s", q.Encode()), nil, &mrs); err != nil {
+		return nil, err
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return mrs[0].toPR(), nil
+}
+
+func (g *gitLab) CreatePullRequest(ctx context.Context, spec PullRequestSpec) (*PullRequest, error) {
+	in := map[string]string{
+		"source_branch": spec.Head,
+		"target_branch": spec.Base,
+		"title":         spec.Title,
+		"description":   spec.Body,
+	}
+	var mr gitLabMR
+	if err := g.c.do(ctx, http.MethodPost, g.path("/merge_requests"), in, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPR(), nil
+}
+
+func (g *gitLab) UpdatePullRequest(ctx context.Context, number int, spec PullRequestSpec) (*PullRequest, error) {
+	in := map[string]string{"title": spec.Title, "description": spec.Body}
+	var mr gitLabMR
+	if err := g.c.do(ctx, http.MethodPut, g.path("/merge_requests/%d", number), in, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPR(), nil
+}
+
+func (g *gitLab) Status(ctx context.Context, number int) (*Status, error) {
+	var mr gitLabMR
+	if err := g.c.do(ctx, http.MethodGet, g.path("/merge_requests/%d", number), nil, &mr); err != nil {
+		return nil, err
+	}
+	st := &Status{PR: mr.toPR()}
+	switch mr.DetailedMergeStatus {
+	case "mergeable", "ci_must_pass", "ci_still_running":
+		// Pipelines are reported as checks below.
+		st.Mergeable = MergeableClean
+	case "conflict", "need_rebase":
+		st.Mergeable = MergeableConflict
+	case "", "checking", "unchecked", "preparing", "approvals_syncing":
+		st.Mergeable = MergeableUnknown
+	default:
+		st.Mergeable = MergeableBlocked
+	}
+	if mr.HasConflicts {
+		st.Mergeable = MergeableConflict
+	}
+
+	var statuses []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	if err := g.c.do(ctx, http.MethodGet, g.path("/repository/commits/%s/statuses?per_page=100", mr.SHA), nil, &statuses); err != nil {
+		return nil, err
+	}
+	for _, s := range statuses {
+		c := Check{Name: s.Name, State: CheckPending}
+		switch s.Status {
+		case "success", "skipped", "manual":
+			c.State = CheckSuccess
+		case "failed", "canceled":
+			c.State = CheckFailure
+		}
+		st.Checks = append(st.Checks, c)
+	}
+
+	if mr.DetailedMergeStatus == "requested_changes" {
+		st.Review = ReviewChangesRequested
+		return st, nil
+	}
+	var approvals struct {
+		Approved bool `json:"approved"`
+	}
+	if err := g.c.do(ctx, http.MethodGet, g.path("/merge_requests/%d/approvals", number), nil, &approvals); err != nil {
+		return nil, err
+	}
+	if approvals.Approved {
+		st.Review = ReviewApproved
+	}
+	return st, nil
+}
+
+func (g *gitLab) Merge(ctx context.Context, number int, opts MergeOptions) (string, error) {
+	// GitLab's merge method (merge commit, semi-linear, fast-forward) is a
+	// project setting; only squashing is chosen per merge.
+	in := map[string]any{"sha": opts.SHA, "squash": opts.Method == MethodSquash}
+	msg := commitMessage(opts.Title, opts.Message)
+	if opts.Method == MethodSquash {
+		in["squash_commit_message"] = msg
+	} else if opts.Method == MethodMerge {
+		in["merge_commit_message"] = msg
+	}
+	var mr gitLabMR
+	if err := g.c.do(ctx, http.MethodPut, g.path("/merge_requests/%d/merge", number), in, &mr); err != nil {
+		switch statusCode(err) {
+		case http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusConflict, http.StatusUnprocessableEntity:
+			return "", notMergeable(err)
+		}
+		return "", err
+	}
+	if mr.State != "merged" {
+		return "", fmt.Errorf("%w: merge request is %s", ErrNotMergeable, mr.State)
+	}
+	return mr.mergedSHA(), nil
+}
@@ -0,0 +1,423 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrFormulaNotFound is returned when a formula is not on the search path.
+var ErrFormulaNotFound = errors.New("formula not found")
+
+// IsComposed reports whether the formula extends or includes other formulas
+// and must be resolved (see Resolver) before use.
+func (f *Formula) IsComposed() bool {
+	return len(f.Extends) > 0 || len(f.Includes) > 0
+}
+
+// validateComposition checks the fields of a composed formula that can be
+// checked before resolution.
+func (f *Formula) validateComposition() error {
+	if f.Name == "" {
+		return fmt.Errorf("formula field is required")
+	}
+	if f.Type != "" && !f.Type.IsValid() {
+		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
+	}
+	for _, parent := range f.Extends {
+		if parent == "" {
+			return fmt.Errorf("extends contains an empty formula name")
+		}
+	}
+	for i, inc := range f.Includes {
+		if inc.Formula == "" {
+			return fmt.Errorf("include %d missing required formula field", i+1)
+		}
+	}
+	for _, step := range f.Steps {
+		if step.ID == "" {
+			return fmt.Errorf("step missing required id field")
+		}
+	}
+	return nil
+}
+
+// Source is a formula loaded from the search path.
+type Source struct {
+	Formula *Formula
+	Path    string // file path, or "embedded:<name>" for built-in formulas
+	tier    int    // index in the search path; len(Dirs) for embedded
+}
+
+// Resolver loads formulas by name and flattens their composition.
+//
+// Formulas are searched for in Dirs, most specific first, then among the
+// formulas embedded in gt (see docs/formula-resolution.md):
+//
+//	<rig>/.beads/formulas/    project
+//	<town>/.beads/formulas/   town
+//	(embedded)                system
+//
+// A formula that extends its own name inherits the next tier's copy, so a
+// town can customize mol-polecat-work without forking it:
+//
+//	formula = "mol-polecat-work"
+//	extends = "mol-polecat-work"
+type Resolver struct {
+	Dirs []string
+}
+
+// NewResolver returns a resolver for the formula search path of a rig
+// directory and town root. Either may be empty.
+func NewResolver(rigPath, townRoot string) *Resolver {
+	var dirs []string
+	for _, root := range []string{rigPath, townRoot} {
+		if root == "" {
+			continue
+		}
+		dir := filepath.Join(root, ".beads", "formulas")
+		if len(dirs) > 0 && dirs[len(dirs)-1] == dir {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return &Resolver{Dirs: dirs}
+}
+
+// Find loads the most specific formula with the given name, unresolved.
+func (r *Resolver) Find(name string) (*Source, error) {
+	return r.find(name, 0)
+}
+
+// find loads the first formula named name at or below tier.
+func (r *Resolver) find(name string, tier int) (*Source, error) {
+	name = strings.TrimSuffix(name, ".formula.toml")
+	for i := tier; i < len(r.Dirs); i++ {
+		path := filepath.Join(r.Dirs[i], name+".formula.toml")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		f, err := ParseFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("formula %s (%s): %w", name, path, err)
+		}
+		return &Source{Formula: f, Path: path, tier: i}, nil
+	}
+	content, err := GetEmbeddedFormulaContent(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrFormulaNotFound, name)
+	}
+	f, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("formula %s (embedded): %w", name, err)
+	}
+	return &Source{Formula: f, Path: "embedded:" + name, tier: len(r.Dirs)}, nil
+}
+
+// Resolve loads a formula by name and flattens its composition.
+func (r *Resolver) Resolve(name string) (*Formula, error) {
+	src, err := r.Find(name)
+	if err != nil {
+		return nil, err
+	}
+	return r.ResolveSource(src)
+}
+
+// ResolveFile parses a formula file and flattens its composition.
+// Formulas it builds on are looked up on the resolver's search path.
+func (r *Resolver) ResolveFile(path string) (*Formula, error) {
+	f, err := ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tier := 0
+	for i, dir := range r.Dirs {
+		if filepath.Dir(path) == dir {
+			tier = i
+			break
+		}
+	}
+	return r.ResolveSource(&Source{Formula: f, Path: path, tier: tier})
+}
+
+// ResolveSource flattens a loaded formula: parents (extends) are resolved
+// and merged in order, included step fragments are added with prefixed IDs,
+// and then the formula's own vars and steps are applied on top. A step with
+// the ID of an inherited step overrides the fields it sets. The result has no
+// extends or includes and is fully validated.
+func (r *Resolver) ResolveSource(src *Source) (*Formula, error) {
+	if !src.Formula.IsComposed() {
+		return src.Formula, nil
+	}
+
+	c := &composer{
+		r:       r,
+		sources: map[string]*Source{},
+		edges:   map[string][]*Source{},
+		deps:    map[string][]string{},
+	}
+	if err := c.walk(src); err != nil {
+		return nil, err
+	}
+	if err := checkDependencyCycles(c.deps); err != nil {
+		return nil, fmt.Errorf("formula %s: composition %w", src.Formula.Name, err)
+	}
+
+	f, err := c.flatten(src, map[string]*Formula{})
+	if err != nil {
+		return nil, err
+	}
+	f.inferType()
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("formula %s (resolved): %w", f.Name, err)
+	}
+	return f, nil
+}
+
+// composer loads the composition graph of a formula, keyed by source path.
+type composer struct {
+	r       *Resolver
+	sources map[string]*Source   // path -> source
+	edges   map[string][]*Source // path -> parents then includes
+	deps    map[string][]string  // path -> dependency paths, for cycle checks
+}
+
+// walk loads every formula src builds on, recording the dependency graph.
+func (c *composer) walk(src *Source) error {
+	if _, seen := c.sources[src.Path]; seen {
+		return nil
+	}
+	c.sources[src.Path] = src
+
+	var names []string
+	names = append(names, src.Formula.Extends...)
+	for _, inc := range src.Formula.Includes {
+		names = append(names, inc.Formula)
+	}
+
+	deps := make([]string, 0, len(names))
+	for _, name := range names {
+		tier := 0
+		if strings.TrimSuffix(name, ".formula.toml") == src.Formula.Name {
+			// Extending yourself means the copy in the next tier down.
+			tier = src.tier + 1
+		}
+		dep, err := c.r.find(name, tier)
+		if err != nil {
+			return fmt.Errorf("formula %s: %w", src.Formula.Name, err)
+		}
+		if known, ok := c.sources[dep.Path]; ok {
+			dep = known
+		}
+		c.edges[src.Path] = append(c.edges[src.Path], dep)
+		deps = append(deps, dep.Path)
+		if err := c.walk(dep); err != nil {
+			return err
+		}
+	}
+	c.deps[src.Path] = deps
+	return nil
+}
+
+// flatten merges src with everything it builds on. Must run after walk and
+// the cycle check.
+func (c *composer) flatten(src *Source, memo map[string]*Formula) (*Formula, error) {
+	if f, ok := memo[src.Path]; ok {
+		return f, nil
+	}
+	own := src.Formula
+	edges := c.edges[src.Path]
+
+	out := &Formula{}
+	for i := range own.Extends {
+		parent, err := c.flatten(edges[i], memo)
+		if err != nil {
+			return nil, err
+		}
+		mergeFormula(out, parent)
+	}
+
+	for i, inc := range own.Includes {
+		lib, err := c.flatten(edges[len(own.Extends)+i], memo)
+		if err != nil {
+			return nil, err
+		}
+		steps, err := includeSteps(lib, inc)
+		if err != nil {
+			return nil, fmt.Errorf("formula %s: %w", own.Name, err)
+		}
+		for _, step := range steps {
+			if out.GetStep(step.ID) != nil {
+				return nil, fmt.Errorf("formula %s: included step %s collides with an existing step (set a different prefix)", own.Name, step.ID)
+			}
+			out.Steps = append(out.Steps, step)
+		}
+		for name, v := range lib.Vars {
+			if _, ok := out.Vars[name]; !ok {
+				if out.Vars == nil {
+					out.Vars = map[string]Var{}
+				}
+				out.Vars[name] = v
+			}
+		}
+	}
+
+	mergeFormula(out, own)
+	out.Extends, out.Includes = nil, nil
+	memo[src.Path] = out
+	return out, nil
+}
+
+// mergeFormula applies the fields set in over onto base. Steps with an
+// existing ID override that step; new steps are appended.
+func mergeFormula(base, over *Formula) {
+	if over.Name != "" {
+		base.Name = over.Name
+	}
+	if over.Description != "" {
+		base.Description = over.Description
+	}
+	if over.Type != "" {
+		base.Type = over.Type
+	}
+	if over.Version != 0 {
+		base.Version = over.Version
+	}
+	if len(over.Vars) > 0 {
+		if base.Vars == nil {
+			base.Vars = make(map[string]Var, len(over.Vars))
+		}
+		for name, v := range over.Vars {
+			base.Vars[name] = v
+		}
+	}
+	for _, step := range over.Steps {
+		if existing := base.GetStep(step.ID); existing != nil {
+			overrideStep(existing, step)
+			continue
+		}
+		base.Steps = append(base.Steps, copyStep(step))
+	}
+
+	// Other formula types are not composable step by step; the most
+	// specific definition wins.
+	if len(over.Inputs) > 0 {
+		base.Inputs = over.Inputs
+	}
+	if len(over.Prompts) > 0 {
+		base.Prompts = over.Prompts
+	}
+	if over.Output != nil {
+		base.Output = over.Output
+	}
+	if len(over.Legs) > 0 {
+		base.Legs = over.Legs
+	}
+	if over.Synthesis != nil {
+		base.Synthesis = over.Synthesis
+	}
+	if len(over.Template) > 0 {
+		base.Template = over.Template
+	}
+	if len(over.Aspects) > 0 {
+		base.Aspects = over.Aspects
+	}
+}
+
+// overrideStep applies the fields set in over onto step.
+func overrideStep(step *Step, over Step) {
+	if over.Title != "" {
+		step.Title = over.Title
+	}
+	if over.Description != "" {
+		step.Description = over.Description
+	}
+	if over.Needs != nil {
+		step.Needs = append([]string(nil), over.Needs...)
+	}
+	if over.Parallel {
+		step.Parallel = true
+	}
+	if over.Acceptance != "" {
+		step.Acceptance = over.Acceptance
+	}
+	if over.When != "" {
+		step.When = over.When
+	}
+	if over.Retries != 0 {
+		step.Retries = over.Retries
+	}
+	if over.Backoff != "" {
+		step.Backoff = over.Backoff
+	}
+	if over.Timeout != "" {
+		step.Timeout = over.Timeout
+	}
+}
+
+func copyStep(step Step) Step {
+	step.Needs = append([]string(nil), step.Needs...)
+	return step
+}
+
+// includeSteps selects the steps of lib named by inc (with the steps they
+// need), prefixes their IDs and attaches the fragment's entry steps to
+// inc.Needs.
+func includeSteps(lib *Formula, inc Include) ([]Step, error) {
+	if lib.Type != TypeWorkflow {
+		return nil, fmt.Errorf("include %s: only workflow formulas can be included (got %s)", inc.Formula, lib.Type)
+	}
+	prefix := inc.Prefix
+	if prefix == "" {
+		prefix = lib.Name
+	}
+
+	selected := make(map[string]bool)
+	if len(inc.Steps) == 0 {
+		for _, step := range lib.Steps {
+			selected[step.ID] = true
+		}
+	} else {
+		var add func(id string) error
+		add = func(id string) error {
+			if selected[id] {
+				return nil
+			}
+			step := lib.GetStep(id)
+			if step == nil {
+				return fmt.Errorf("include %s: unknown step %s", inc.Formula, id)
+			}
+			selected[id] = true
+			for _, need := range step.Needs {
+				if err := add(need); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for _, id := range inc.Steps {
+			if err := add(id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var steps []Step
+	for _, step := range lib.Steps {
+		if !selected[step.ID] {
+			continue
+		}
+		step = copyStep(step)
+		step.ID = prefix + "." + step.ID
+		for i, need := range step.Needs {
+			step.Needs[i] = prefix + "." + need
+		}
+		if len(step.Needs) == 0 {
+			step.Needs = append(step.Needs, inc.Needs...)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
@@ -0,0 +1,317 @@
+package formula
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFormula writes a formula file into dir/.beads/formulas.
+func writeFormula(t *testing.T, root, name, content string) string {
+	t.Helper()
+	dir := filepath.Join(root, ".beads", "formulas")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name+".formula.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func stepIDs(f *Formula) []string {
+	ids := make([]string, 0, len(f.Steps))
+	for _, s := range f.Steps {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+const baseWork = `
+formula = "base-work"
+type = "workflow"
+version = 2
+
+[vars.issue]
+required = true
+
+[[steps]]
+id = "load"
+title = "Load {{issue}}"
+
+[[steps]]
+id = "implement"
+title = "Implement"
+needs = ["load"]
+
+[[steps]]
+id = "submit"
+title = "Submit"
+needs = ["implement"]
+`
+
+const checksLib = `
+formula = "go-checks"
+type = "workflow"
+
+[vars.pkg]
+default = "./..."
+
+[[steps]]
+id = "vet"
+title = "go vet {{pkg}}"
+
+[[steps]]
+id = "test"
+title = "go test {{pkg}}"
+needs = ["vet"]
+
+[[steps]]
+id = "bench"
+title = "go test -bench"
+`
+
+func TestParse_ComposedDefersValidation(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "child"
+extends = "base-work"
+
+[[steps]]
+id = "review"
+needs = ["implement"]
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !f.IsComposed() || !reflect.DeepEqual([]string(f.Extends), []string{"base-work"}) {
+		t.Errorf("Extends = %v", f.Extends)
+	}
+
+	f, err = Parse([]byte(`
+formula = "multi"
+extends = ["a", "b"]
+`))
+	if err != nil {
+		t.Fatalf("Parse list extends: %v", err)
+	}
+	if !reflect.DeepEqual([]string(f.Extends), []string{"a", "b"}) {
+		t.Errorf("Extends = %v", f.Extends)
+	}
+
+	if _, err := Parse([]byte("formula = \"x\"\n[[include]]\nprefix = \"p\"\n")); err == nil {
+		t.Error("include without formula should fail")
+	}
+}
+
+func TestResolve_ExtendsWithOverrides(t *testing.T) {
+	town := t.TempDir()
+	writeFormula(t, town, "base-work", baseWork)
+	writeFormula(t, town, "careful-work", `
+formula = "careful-work"
+extends = "base-work"
+description = "Base work plus review"
+
+[vars.reviewer]
+default = "witness"
+
+[[steps]]
+id = "review"
+title = "Review by {{reviewer}}"
+needs = ["implement"]
+
+[[steps]]
+id = "submit"
+needs = ["review"]
+timeout = "1h"
+`)
+
+	f, err := NewResolver("", town).Resolve("careful-work")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if f.IsComposed() {
+		t.Error("resolved formula still composed")
+	}
+	if f.Name != "careful-work" || f.Type != TypeWorkflow || f.Version != 2 {
+		t.Errorf("metadata = %q %q %d", f.Name, f.Type, f.Version)
+	}
+	if got, want := stepIDs(f), []string{"load", "implement", "submit", "review"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+	submit := f.GetStep("submit")
+	if submit.Title != "Submit" || !reflect.DeepEqual(submit.Needs, []string{"review"}) || submit.Timeout != "1h" {
+		t.Errorf("submit override = %+v", submit)
+	}
+	if _, ok := f.Vars["issue"]; !ok {
+		t.Error("inherited var issue missing")
+	}
+	if f.Vars["reviewer"].Default != "witness" {
+		t.Error("own var reviewer missing")
+	}
+	order, err := f.TopologicalSort()
+	if err != nil || order[len(order)-1] != "submit" {
+		t.Errorf("TopologicalSort = %v, %v", order, err)
+	}
+}
+
+func TestResolve_Include(t *testing.T) {
+	town := t.TempDir()
+	writeFormula(t, town, "base-work", baseWork)
+	writeFormula(t, town, "go-checks", checksLib)
+	writeFormula(t, town, "go-work", `
+formula = "go-work"
+extends = "base-work"
+
+[[include]]
+formula = "go-checks"
+steps = ["test"]
+prefix = "checks"
+needs = ["implement"]
+
+[[steps]]
+id = "submit"
+needs = ["checks.test"]
+`)
+
+	f, err := NewResolver("", town).Resolve("go-work")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got, want := stepIDs(f), []string{"load", "implement", "submit", "checks.vet", "checks.test"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+	if got := f.GetStep("checks.vet").Needs; !reflect.DeepEqual(got, []string{"implement"}) {
+		t.Errorf("fragment entry needs = %v", got)
+	}
+	if got := f.GetStep("checks.test").Needs; !reflect.DeepEqual(got, []string{"checks.vet"}) {
+		t.Errorf("fragment internal needs = %v", got)
+	}
+	if f.Vars["pkg"].Default != "./..." {
+		t.Error("included var pkg missing")
+	}
+}
+
+func TestEncode_ResolvedRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	writeFormula(t, town, "base-work", baseWork)
+	writeFormula(t, town, "go-checks", checksLib)
+	writeFormula(t, town, "go-work", `
+formula = "go-work"
+extends = "base-work"
+
+[[include]]
+formula = "go-checks"
+prefix = "checks"
+needs = ["implement"]
+`)
+
+	f, err := NewResolver("", town).Resolve("go-work")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	data, err := f.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if strings.Contains(string(data), "extends") || strings.Contains(string(data), "[[include]]") {
+		t.Errorf("encoded formula still composed:\n%s", data)
+	}
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse(encoded): %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, f) {
+		t.Errorf("round trip changed the formula:\n got %+v\nwant %+v", got, f)
+	}
+}
+
+func TestResolve_IncludeCollision(t *testing.T) {
+	town := t.TempDir()
+	writeFormula(t, town, "go-checks", checksLib)
+	writeFormula(t, town, "twice", `
+formula = "twice"
+type = "workflow"
+
+[[include]]
+formula = "go-checks"
+
+[[include]]
+formula = "go-checks"
+`)
+	_, err := NewResolver("", town).Resolve("twice")
+	if err == nil || !strings.Contains(err.Error(), "collides") {
+		t.Errorf("err = %v, want collision", err)
+	}
+}
+
+func TestResolve_Cycle(t *testing.T) {
+	town := t.TempDir()
+	writeFormula(t, town, "cyc-a", "formula = \"cyc-a\"\nextends = \"cyc-b\"\n")
+	writeFormula(t, town, "cyc-b", "formula = \"cyc-b\"\n[[include]]\nformula = \"cyc-a\"\n")
+
+	_, err := NewResolver("", town).Resolve("cyc-a")
+	if err == nil || !strings.Contains(err.Error(), "cycle detected") {
+		t.Errorf("err = %v, want cycle", err)
+	}
+}
+
+func TestResolve_TierPrecedenceAndSelfExtend(t *testing.T) {
+	rig, town := t.TempDir(), t.TempDir()
+	writeFormula(t, town, "base-work", baseWork)
+	// The rig customizes base-work by extending the town's copy.
+	writeFormula(t, rig, "base-work", `
+formula = "base-work"
+extends = "base-work"
+
+[[steps]]
+id = "implement"
+title = "Implement carefully"
+`)
+
+	r := NewResolver(rig, town)
+	src, err := r.Find("base-work")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if !strings.HasPrefix(src.Path, rig) {
+		t.Errorf("Find picked %s, want rig copy", src.Path)
+	}
+	f, err := r.Resolve("base-work")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if got := f.GetStep("implement").Title; got != "Implement carefully" {
+		t.Errorf("implement title = %q", got)
+	}
+	if len(f.Steps) != 3 {
+		t.Errorf("steps = %v", stepIDs(f))
+	}
+}
+
+func TestResolve_NotFound(t *testing.T) {
+	town := t.TempDir()
+	writeFormula(t, town, "orphan", "formula = \"orphan\"\nextends = \"no-such-formula\"\n")
+	_, err := NewResolver("", town).Resolve("orphan")
+	if !errors.Is(err, ErrFormulaNotFound) {
+		t.Errorf("err = %v, want ErrFormulaNotFound", err)
+	}
+}
+
+func TestResolve_EmbeddedExtends(t *testing.T) {
+	// shiny-secure extends the embedded shiny formula.
+	f, err := NewResolver("", "").Resolve("shiny-secure")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	shiny, err := NewResolver("", "").Resolve("shiny")
+	if err != nil {
+		t.Fatalf("Resolve shiny: %v", err)
+	}
+	if f.Name != "shiny-secure" || !reflect.DeepEqual(stepIDs(f), stepIDs(shiny)) {
+		t.Errorf("shiny-secure steps = %v, want %v", stepIDs(f), stepIDs(shiny))
+	}
+}
@@ -0,0 +1,290 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Condition is a parsed step "when" expression.
+//
+// The grammar is deliberately small:
+//
+//	expr    = or
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" expr ")" | compare
+//	compare = operand [ ("==" | "!=") operand ]
+//	operand = var | "{{" var "}}" | quoted-string | true | false
+//
+// A bare variable is true when it is set to anything other than "", "false",
+// "0", "no" or "off". Unset variables are empty.
+//
+//	when = "publish"
+//	when = "channel == 'stable' && !skip_docs"
+//	when = "{{target}} != 'dev'"
+type Condition struct {
+	root condNode
+	vars []string
+}
+
+// ParseCondition parses a "when" expression.
+func ParseCondition(expr string) (*Condition, error) {
+	p := &condParser{}
+	if err := p.tokenize(expr); err != nil {
+		return nil, fmt.Errorf("invalid when %q: %w", expr, err)
+	}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("invalid when %q: empty expression", expr)
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid when %q: %w", expr, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid when %q: unexpected %q", expr, p.tokens[p.pos].text)
+	}
+	return &Condition{root: root, vars: p.vars}, nil
+}
+
+// Eval evaluates the condition against vars.
+func (c *Condition) Eval(vars map[string]string) bool {
+	return c.root.eval(vars)
+}
+
+// Vars returns the variable names the condition references.
+func (c *Condition) Vars() []string {
+	return c.vars
+}
+
+// EvalCondition parses and evaluates a "when" expression. An empty
+// expression is true.
+func EvalCondition(expr string, vars map[string]string) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+	c, err := ParseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	return c.Eval(vars), nil
+}
+
+// truthy reports whether a variable value counts as true.
+func truthy(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "false", "0", "no", "off":
+		return false
+	}
+	return true
+}
+
+type condNode interface {
+	eval(vars map[string]string) bool
+}
+
+// operand is a variable reference or a literal.
+type operand struct {
+	name    string // variable name, if a reference
+	literal string
+	isVar   bool
+}
+
+func (o operand) value(vars map[string]string) string {
+	if o.isVar {
+		return vars[o.name]
+	}
+	return o.literal
+}
+
+func (o operand) eval(vars map[string]string) bool { return truthy(o.value(vars)) }
+
+type compareNode struct {
+	left, right operand
+	negate      bool
+}
+
+func (n compareNode) eval(vars map[string]string) bool {
+	return (n.left.value(vars) == n.right.value(vars)) != n.negate
+}
+
+type notNode struct{ x condNode }
+
+func (n notNode) eval(vars map[string]string) bool { return !n.x.eval(vars) }
+
+type andNode struct{ l, r condNode }
+
+func (n andNode) eval(vars map[string]string) bool { return n.l.eval(vars) && n.r.eval(vars) }
+
+type orNode struct{ l, r condNode }
+
+func (n orNode) eval(vars map[string]string) bool { return n.l.eval(vars) || n.r.eval(vars) }
+
+type condToken struct {
+	kind string // "op", "ident", "string"
+	text string
+}
+
+type condParser struct {
+	tokens []condToken
+	pos    int
+	vars   []string
+}
+
+func (p *condParser) tokenize(s string) error {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"),
+			strings.HasPrefix(s[i:], "=="), strings.HasPrefix(s[i:], "!="):
+			p.tokens = append(p.tokens, condToken{"op", s[i : i+2]})
+			i += 2
+		case c == '!' || c == '(' || c == ')':
+			p.tokens = append(p.tokens, condToken{"op", string(c)})
+			i++
+		case strings.HasPrefix(s[i:], "{{"):
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				return fmt.Errorf("unterminated {{")
+			}
+			name := strings.TrimSpace(s[i+2 : i+end])
+			if !isCondIdent(name) {
+				return fmt.Errorf("invalid variable %q", name)
+			}
+			p.tokens = append(p.tokens, condToken{"ident", name})
+			i += end + 2
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return fmt.Errorf("unterminated string")
+			}
+			p.tokens = append(p.tokens, condToken{"string", s[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			j := i
+			for j < len(s) && isCondIdentRune(rune(s[j])) {
+				j++
+			}
+			if j == i {
+				return fmt.Errorf("unexpected character %q", c)
+			}
+			p.tokens = append(p.tokens, condToken{"ident", s[i:j]})
+			i = j
+		}
+	}
+	return nil
+}
+
+func isCondIdentRune(r rune) bool {
+	return r == '_' || r == '-' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isCondIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isCondIdentRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *condParser) peek(text string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == "op" && p.tokens[p.pos].text == text
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	if p.peek("!") {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x}, nil
+	}
+	if p.peek("(") {
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return x, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek("==") || p.peek("!=") {
+		negate := p.tokens[p.pos].text == "!="
+		p.pos++
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{left: left, right: right, negate: negate}, nil
+	}
+	return left, nil
+}
+
+func (p *condParser) parseOperand() (operand, error) {
+	if p.pos >= len(p.tokens) {
+		return operand{}, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case "string":
+		return operand{literal: tok.text}, nil
+	case "ident":
+		switch tok.text {
+		case "true":
+			return operand{literal: "true"}, nil
+		case "false":
+			return operand{literal: "false"}, nil
+		}
+		p.vars = append(p.vars, tok.text)
+		return operand{name: tok.text, isVar: true}, nil
+	}
+	return operand{}, fmt.Errorf("unexpected %q", tok.text)
+}
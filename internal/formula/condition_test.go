@@ -0,0 +1,76 @@
+package formula
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvalCondition(t *testing.T) {
+	vars := map[string]string{
+		"publish":   "true",
+		"skip_docs": "no",
+		"channel":   "stable",
+		"target":    "prod",
+		"count":     "0",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{"publish", true},
+		{"skip_docs", false},
+		{"count", false},
+		{"unset", false},
+		{"!unset", true},
+		{"true", true},
+		{"false", false},
+		{"channel == 'stable'", true},
+		{`channel == "beta"`, false},
+		{"channel != 'beta'", true},
+		{"{{target}} != 'dev'", true},
+		{"channel == 'stable' && !skip_docs", true},
+		{"channel == 'beta' || publish", true},
+		{"!(channel == 'stable' && publish)", false},
+		{"unset == ''", true},
+	}
+
+	for _, tt := range tests {
+		got, err := EvalCondition(tt.expr, vars)
+		if err != nil {
+			t.Errorf("EvalCondition(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("EvalCondition(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCondition_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"   ",
+		"a &&",
+		"(a || b",
+		"a == ",
+		"'unterminated",
+		"{{target",
+		"a b",
+		"a > b",
+	} {
+		if _, err := ParseCondition(expr); err == nil {
+			t.Errorf("ParseCondition(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCondition_Vars(t *testing.T) {
+	c, err := ParseCondition("publish && {{channel}} == 'stable'")
+	if err != nil {
+		t.Fatalf("ParseCondition: %v", err)
+	}
+	if got, want := c.Vars(), []string{"publish", "channel"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Vars() = %v, want %v", got, want)
+	}
+}
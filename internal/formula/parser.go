@@ -1,6 +1,7 @@
 package formula
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"sort"
@@ -27,6 +28,15 @@ func Parse(data []byte) (*Formula, error) {
 	// Infer type from content if not explicitly set
 	f.inferType()
 
+	// Composed formulas are validated once resolved: their steps may
+	// override or need steps defined by the formulas they build on.
+	if f.IsComposed() {
+		if err := f.validateComposition(); err != nil {
+			return nil, err
+		}
+		return &f, nil
+	}
+
 	if err := f.Validate(); err != nil {
 		return nil, err
 	}
@@ -34,6 +44,16 @@ func Parse(data []byte) (*Formula, error) {
 	return &f, nil
 }
 
+// Encode renders the formula as formula.toml content. Used to hand resolved
+// formulas to bd, which doesn't understand extends or includes.
+func (f *Formula) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(f); err != nil {
+		return nil, fmt.Errorf("encoding formula %s: %w", f.Name, err)
+	}
+	return buf.Bytes(), nil
+}
+
 // inferType sets the formula type based on content when not explicitly set.
 func (f *Formula) inferType() {
 	if f.Type != "" {
@@ -133,13 +153,16 @@ func (f *Formula) validateWorkflow() error {
 		seen[step.ID] = true
 	}
 
-	// Validate step needs references
-	for _, step := range f.Steps {
+	// Validate step needs references and step policies
+	for i, step := range f.Steps {
 		for _, need := range step.Needs {
 			if !seen[need] {
 				return fmt.Errorf("step %q needs unknown step: %s", step.ID, need)
 			}
 		}
+		if err := validateStepPolicy(&f.Steps[i]); err != nil {
+			return err
+		}
 	}
 
 	// Check for cycles
@@ -357,18 +380,28 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 
 // ReadySteps returns steps that have no unmet dependencies.
 // completed is a set of step IDs that have been completed.
+// Step when conditions are evaluated against variable defaults;
+// use ReadyStepsWithVars to supply the molecule's variables.
 func (f *Formula) ReadySteps(completed map[string]bool) []string {
+	return f.ReadyStepsWithVars(completed, nil)
+}
+
+// ReadyStepsWithVars returns steps that have no unmet dependencies, given
+// the variables the molecule was created with. Workflow steps whose when
+// condition is false are never ready and satisfy the needs of later steps.
+func (f *Formula) ReadyStepsWithVars(completed map[string]bool, vars map[string]string) []string {
 	var ready []string
 
 	switch f.Type {
 	case TypeWorkflow:
+		skipped := f.SkippedSteps(vars)
 		for _, step := range f.Steps {
-			if completed[step.ID] {
+			if completed[step.ID] || skipped[step.ID] {
 				continue
 			}
 			allMet := true
 			for _, need := range step.Needs {
-				if !completed[need] {
+				if !completed[need] && !skipped[need] {
 					allMet = false
 					break
 				}
@@ -428,7 +461,13 @@ func (f *Formula) GetStep(id string) *Step {
 // - sequentialStep: the first non-parallel ready step, or nil if all are parallel
 // If multiple parallel steps are ready, they should all be executed concurrently.
 func (f *Formula) ParallelReadySteps(completed map[string]bool) (parallel []string, sequential string) {
-	ready := f.ReadySteps(completed)
+	return f.ParallelReadyStepsWithVars(completed, nil)
+}
+
+// ParallelReadyStepsWithVars is ParallelReadySteps with the molecule's
+// variables applied to step when conditions.
+func (f *Formula) ParallelReadyStepsWithVars(completed map[string]bool, vars map[string]string) (parallel []string, sequential string) {
+	ready := f.ReadyStepsWithVars(completed, vars)
 	if len(ready) == 0 {
 		return nil, ""
 	}
@@ -0,0 +1,117 @@
+package formula
+
+import (
+	"fmt"
+	"time"
+)
+
+// Step policy defaults.
+const (
+	// DefaultRetryBackoff is the delay before a step's first retry.
+	DefaultRetryBackoff = 30 * time.Second
+	// MaxRetryBackoff caps the doubling retry delay.
+	MaxRetryBackoff = 30 * time.Minute
+)
+
+// HasPolicy reports whether the step uses when, retries or timeout.
+func (s *Step) HasPolicy() bool {
+	return s.When != "" || s.Retries > 0 || s.Timeout != ""
+}
+
+// TimeoutDuration returns the step timeout, or 0 if the step has none.
+func (s *Step) TimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(s.Timeout)
+	return d
+}
+
+// RetryDelay returns the delay before retry attempt n (1 = first retry):
+// the backoff doubled for each earlier retry, capped at MaxRetryBackoff.
+func (s *Step) RetryDelay(n int) time.Duration {
+	return RetryDelay(s.Backoff, n)
+}
+
+// RetryDelay computes the delay before retry attempt n for a backoff base
+// duration string (empty means DefaultRetryBackoff).
+func RetryDelay(backoff string, n int) time.Duration {
+	base := DefaultRetryBackoff
+	if d, err := time.ParseDuration(backoff); err == nil && d > 0 {
+		base = d
+	}
+	delay := base
+	for i := 1; i < n && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxRetryBackoff {
+		delay = MaxRetryBackoff
+	}
+	return delay
+}
+
+// validateStepPolicy checks a step's when, retries, backoff and timeout fields.
+func validateStepPolicy(step *Step) error {
+	if step.When != "" {
+		if _, err := ParseCondition(step.When); err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
+	}
+	if step.Retries < 0 {
+		return fmt.Errorf("step %q: retries must be >= 0", step.ID)
+	}
+	for _, field := range []struct{ name, value string }{
+		{"backoff", step.Backoff},
+		{"timeout", step.Timeout},
+	} {
+		if field.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(field.value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("step %q: invalid %s %q (use a duration like 30s or 10m)", step.ID, field.name, field.value)
+		}
+	}
+	if step.Backoff != "" && step.Retries == 0 {
+		return fmt.Errorf("step %q: backoff set without retries", step.ID)
+	}
+	return nil
+}
+
+// HasStepPolicies reports whether any step uses when, retries or timeout.
+func (f *Formula) HasStepPolicies() bool {
+	for i := range f.Steps {
+		if f.Steps[i].HasPolicy() {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveVars returns the formula's variable defaults overlaid with vars.
+func (f *Formula) ResolveVars(vars map[string]string) map[string]string {
+	resolved := make(map[string]string, len(f.Vars)+len(vars))
+	for name, v := range f.Vars {
+		if v.Default != "" {
+			resolved[name] = v.Default
+		}
+	}
+	for name, v := range vars {
+		resolved[name] = v
+	}
+	return resolved
+}
+
+// SkippedSteps returns the workflow steps whose when condition is false for
+// vars (after applying variable defaults). Skipped steps never run, and
+// count as complete for the steps that need them.
+func (f *Formula) SkippedSteps(vars map[string]string) map[string]bool {
+	skipped := make(map[string]bool)
+	if f.Type != TypeWorkflow {
+		return skipped
+	}
+	resolved := f.ResolveVars(vars)
+	for _, step := range f.Steps {
+		if ok, err := EvalCondition(step.When, resolved); err == nil && !ok {
+			skipped[step.ID] = true
+		}
+	}
+	return skipped
+}
@@ -0,0 +1,253 @@
+package formula
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+const policyFormula = `
+formula = "release"
+type = "workflow"
+version = 1
+
+[vars]
+[vars.publish]
+default = "false"
+[vars.version]
+required = true
+
+[[steps]]
+id = "build"
+title = "Build {{version}}"
+timeout = "10m"
+
+[[steps]]
+id = "test"
+title = "Test {{version}}"
+needs = ["build"]
+retries = 2
+backoff = "1m"
+
+[[steps]]
+id = "publish"
+title = "Publish {{version}}"
+needs = ["test"]
+when = "publish"
+
+[[steps]]
+id = "announce"
+title = "Announce {{version}}"
+needs = ["publish"]
+`
+
+func TestParse_StepPolicies(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !f.HasStepPolicies() {
+		t.Error("HasStepPolicies() = false, want true")
+	}
+	build := f.GetStep("build")
+	if got := build.TimeoutDuration(); got != 10*time.Minute {
+		t.Errorf("TimeoutDuration() = %v, want 10m", got)
+	}
+	test := f.GetStep("test")
+	if test.Retries != 2 || test.Backoff != "1m" {
+		t.Errorf("test step retries/backoff = %d/%q", test.Retries, test.Backoff)
+	}
+}
+
+func TestParse_StepPolicyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		step  string
+		error string
+	}{
+		{"bad when", `when = "a &&"`, "invalid when"},
+		{"negative retries", `retries = -1`, "retries must be >= 0"},
+		{"bad timeout", `timeout = "soon"`, "invalid timeout"},
+		{"zero backoff", "retries = 1\nbackoff = \"0s\"", "invalid backoff"},
+		{"backoff without retries", `backoff = "1m"`, "backoff set without retries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "formula = \"f\"\ntype = \"workflow\"\n\n[[steps]]\nid = \"a\"\ntitle = \"A\"\n" + tt.step + "\n"
+			_, err := Parse([]byte(data))
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Errorf("Parse error = %v, want containing %q", err, tt.error)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		backoff string
+		n       int
+		want    time.Duration
+	}{
+		{"", 1, DefaultRetryBackoff},
+		{"", 2, 2 * DefaultRetryBackoff},
+		{"1m", 1, time.Minute},
+		{"1m", 3, 4 * time.Minute},
+		{"10m", 10, MaxRetryBackoff},
+		{"bogus", 1, DefaultRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := RetryDelay(tt.backoff, tt.n); got != tt.want {
+			t.Errorf("RetryDelay(%q, %d) = %v, want %v", tt.backoff, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestReadyStepsWithVars_SkipsFalseConditions(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	skipped := f.SkippedSteps(map[string]string{"version": "1.0"})
+	if !reflect.DeepEqual(skipped, map[string]bool{"publish": true}) {
+		t.Errorf("SkippedSteps = %v, want publish only", skipped)
+	}
+
+	// With publish defaulting to false, announce becomes ready once test is
+	// done: the skipped publish step satisfies its needs.
+	ready := f.ReadyStepsWithVars(map[string]bool{"build": true, "test": true}, map[string]string{"version": "1.0"})
+	if !reflect.DeepEqual(ready, []string{"announce"}) {
+		t.Errorf("ReadyStepsWithVars = %v, want [announce]", ready)
+	}
+
+	ready = f.ReadyStepsWithVars(map[string]bool{"build": true, "test": true}, map[string]string{"version": "1.0", "publish": "yes"})
+	if !reflect.DeepEqual(ready, []string{"publish"}) {
+		t.Errorf("ReadyStepsWithVars(publish=yes) = %v, want [publish]", ready)
+	}
+}
+
+func TestRun_Lifecycle(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	run := NewRun(f, "gt-wisp-abc", map[string]string{"version": "1.0"}, now)
+
+	if got := run.Steps["build"].Title; got != "Build 1.0" {
+		t.Errorf("build title = %q, want expanded", got)
+	}
+	if !run.Steps["build"].StartedAt.Equal(now) {
+		t.Error("build should start immediately")
+	}
+	if !run.Steps["publish"].Skipped {
+		t.Error("publish should be skipped")
+	}
+
+	id, state := run.StepFor("gt-wisp-abc.1", "Build 1.0")
+	if id != "build" || state == nil {
+		t.Fatalf("StepFor by title = %q", id)
+	}
+	run.Start("build", "gt-wisp-abc.1", now)
+	if id, _ := run.StepFor("gt-wisp-abc.1", "renamed"); id != "build" {
+		t.Errorf("StepFor by bead ID = %q, want build", id)
+	}
+
+	// Timeout enforcement
+	if overdue := run.Overdue(now.Add(5 * time.Minute)); len(overdue) != 0 {
+		t.Errorf("Overdue before timeout = %v", overdue)
+	}
+	overdue := run.Overdue(now.Add(11 * time.Minute))
+	if !reflect.DeepEqual(overdue, []string{"build"}) {
+		t.Fatalf("Overdue = %v, want [build]", overdue)
+	}
+	run.Complete("build")
+	if overdue := run.Overdue(now.Add(11 * time.Minute)); len(overdue) != 0 {
+		t.Errorf("Overdue after complete = %v", overdue)
+	}
+
+	// Retries with doubling backoff, then failure
+	run.Start("test", "gt-wisp-abc.2", now)
+	delay, ok := run.Fail("test", "flaky", now)
+	if !ok || delay != time.Minute {
+		t.Errorf("first Fail = %v, %v; want 1m, true", delay, ok)
+	}
+	delay, ok = run.Fail("test", "flaky", now)
+	if !ok || delay != 2*time.Minute {
+		t.Errorf("second Fail = %v, %v; want 2m, true", delay, ok)
+	}
+	if _, ok := run.Fail("test", "flaky", now); ok {
+		t.Error("third Fail should exhaust retries")
+	}
+	if !strings.Contains(run.Failed, "after 3 attempt(s)") {
+		t.Errorf("Failed = %q", run.Failed)
+	}
+}
+
+func TestRun_TimeOut(t *testing.T) {
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	now := time.Now()
+	run := NewRun(f, "gt-wisp-abc", map[string]string{"version": "1.0"}, now)
+	run.TimeOut("build", now)
+	if !run.Steps["build"].TimedOut || !strings.Contains(run.Failed, "10m timeout") {
+		t.Errorf("TimeOut did not fail the run: %+v", run)
+	}
+	if overdue := run.Overdue(now.Add(time.Hour)); overdue != nil {
+		t.Errorf("Overdue on failed run = %v, want nil", overdue)
+	}
+}
+
+func TestRun_Storage(t *testing.T) {
+	townRoot := t.TempDir()
+	f, err := Parse([]byte(policyFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if run, err := LoadRun(townRoot, "missing"); err != nil || run != nil {
+		t.Errorf("LoadRun(missing) = %v, %v; want nil, nil", run, err)
+	}
+
+	for _, id := range []string{"gt-wisp-a", "gt-wisp-b"} {
+		run := NewRun(f, id, map[string]string{"version": "1.0"}, time.Now())
+		run.Rig = "gastown"
+		if err := SaveRun(townRoot, run); err != nil {
+			t.Fatalf("SaveRun: %v", err)
+		}
+	}
+	if want := filepath.Join(townRoot, ".runtime", "formula-runs", "gt-wisp-a.json"); RunPath(townRoot, "gt-wisp-a") != want {
+		t.Errorf("RunPath = %q, want %q", RunPath(townRoot, "gt-wisp-a"), want)
+	}
+
+	runs, err := ListRuns(townRoot)
+	if err != nil {
+		t.Fatalf("ListRuns: %v", err)
+	}
+	var ids []string
+	for _, r := range runs {
+		ids = append(ids, r.MoleculeID)
+		if r.Rig != "gastown" || r.Steps["build"] == nil {
+			t.Errorf("run %s not round-tripped: %+v", r.MoleculeID, r)
+		}
+	}
+	sort.Strings(ids)
+	if !reflect.DeepEqual(ids, []string{"gt-wisp-a", "gt-wisp-b"}) {
+		t.Errorf("ListRuns ids = %v", ids)
+	}
+
+	if err := RemoveRun(townRoot, "gt-wisp-a"); err != nil {
+		t.Fatalf("RemoveRun: %v", err)
+	}
+	if err := RemoveRun(townRoot, "gt-wisp-a"); err != nil {
+		t.Errorf("RemoveRun twice: %v", err)
+	}
+	if run, _ := LoadRun(townRoot, "gt-wisp-a"); run != nil {
+		t.Error("run still present after RemoveRun")
+	}
+}
@@ -0,0 +1,244 @@
+package formula
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Run is the runtime record of a molecule poured from a formula whose steps
+// use when, retries or timeout. Molecule steps are beads created by bd, which
+// ignores these fields, so gt keeps their policy here: gt mol step consults
+// it to skip and retry steps, and the witness checks it for timeouts.
+//
+// Runs are stored at <townRoot>/.runtime/formula-runs/<molecule-id>.json.
+type Run struct {
+	MoleculeID string                `json:"molecule_id"`
+	BeadID     string                `json:"bead_id,omitempty"` // work bead the molecule is bonded to
+	Formula    string                `json:"formula"`
+	Rig        string                `json:"rig,omitempty"`
+	Vars       map[string]string     `json:"vars,omitempty"`
+	Steps      map[string]*StepState `json:"steps"` // keyed by formula step ID
+	CreatedAt  time.Time             `json:"created_at"`
+
+	// Failed is set when the molecule failed: a step ran out of retries or
+	// exceeded its timeout.
+	Failed   string    `json:"failed,omitempty"`
+	FailedAt time.Time `json:"failed_at,omitempty"`
+}
+
+// StepState is the policy and progress of one formula step in a Run.
+type StepState struct {
+	Title   string `json:"title"` // step title with vars expanded, used to match step beads
+	Skipped bool   `json:"skipped,omitempty"`
+	Retries int    `json:"retries,omitempty"`
+	Backoff string `json:"backoff,omitempty"`
+	Timeout string `json:"timeout,omitempty"`
+
+	BeadID    string    `json:"bead_id,omitempty"`
+	Attempts  int       `json:"attempts,omitempty"` // failed attempts so far
+	StartedAt time.Time `json:"started_at,omitempty"`
+	RetryAt   time.Time `json:"retry_at,omitempty"`
+	Done      bool      `json:"done,omitempty"`
+	TimedOut  bool      `json:"timed_out,omitempty"`
+}
+
+// NewRun records the step policies of a molecule poured from f with vars.
+// Steps that are ready immediately are marked started at now.
+func NewRun(f *Formula, moleculeID string, vars map[string]string, now time.Time) *Run {
+	resolved := f.ResolveVars(vars)
+	skipped := f.SkippedSteps(vars)
+	run := &Run{
+		MoleculeID: moleculeID,
+		Formula:    f.Name,
+		Vars:       resolved,
+		Steps:      make(map[string]*StepState, len(f.Steps)),
+		CreatedAt:  now,
+	}
+	for _, step := range f.Steps {
+		run.Steps[step.ID] = &StepState{
+			Title:   expandVars(step.Title, resolved),
+			Skipped: skipped[step.ID],
+			Retries: step.Retries,
+			Backoff: step.Backoff,
+			Timeout: step.Timeout,
+		}
+	}
+	for _, id := range f.ReadyStepsWithVars(nil, vars) {
+		run.Steps[id].StartedAt = now
+	}
+	return run
+}
+
+// expandVars substitutes {{var}} placeholders.
+func expandVars(s string, vars map[string]string) string {
+	for name, v := range vars {
+		s = strings.ReplaceAll(s, "{{"+name+"}}", v)
+	}
+	return s
+}
+
+// StepFor finds the formula step a step bead belongs to: by recorded bead
+// ID, else by title. Returns "" and nil if the bead matches no step.
+func (r *Run) StepFor(beadID, title string) (string, *StepState) {
+	for id, s := range r.Steps {
+		if s.BeadID != "" && s.BeadID == beadID {
+			return id, s
+		}
+	}
+	title = strings.TrimSpace(title)
+	for _, id := range r.stepIDs() {
+		if s := r.Steps[id]; s.BeadID == "" && s.Title == title {
+			return id, s
+		}
+	}
+	return "", nil
+}
+
+// Start marks a step as started by beadID.
+func (r *Run) Start(stepID, beadID string, now time.Time) {
+	if s := r.Steps[stepID]; s != nil {
+		s.BeadID = beadID
+		s.StartedAt = now
+		s.RetryAt = time.Time{}
+	}
+}
+
+// Complete marks a step as done.
+func (r *Run) Complete(stepID string) {
+	if s := r.Steps[stepID]; s != nil {
+		s.Done = true
+	}
+}
+
+// Fail records a failed attempt of a step. It returns the delay before the
+// retry, or ok=false when the step is out of retries and the molecule fails.
+func (r *Run) Fail(stepID, reason string, now time.Time) (delay time.Duration, ok bool) {
+	s := r.Steps[stepID]
+	if s == nil {
+		r.markFailed(fmt.Sprintf("step %s failed: %s", stepID, reason), now)
+		return 0, false
+	}
+	s.Attempts++
+	if s.Attempts > s.Retries {
+		r.markFailed(fmt.Sprintf("step %s failed after %d attempt(s): %s", stepID, s.Attempts, reason), now)
+		return 0, false
+	}
+	delay = RetryDelay(s.Backoff, s.Attempts)
+	s.RetryAt = now.Add(delay)
+	return delay, true
+}
+
+func (r *Run) markFailed(reason string, now time.Time) {
+	if r.Failed == "" {
+		r.Failed = reason
+		r.FailedAt = now
+	}
+}
+
+// Overdue returns the IDs of running steps that have exceeded their timeout.
+// A step waiting to retry is not running.
+func (r *Run) Overdue(now time.Time) []string {
+	if r.Failed != "" {
+		return nil
+	}
+	var overdue []string
+	for _, id := range r.stepIDs() {
+		s := r.Steps[id]
+		if s.Done || s.Skipped || s.TimedOut || s.StartedAt.IsZero() || !s.RetryAt.IsZero() {
+			continue
+		}
+		d, err := time.ParseDuration(s.Timeout)
+		if err != nil || d <= 0 {
+			continue
+		}
+		if now.Sub(s.StartedAt) > d {
+			overdue = append(overdue, id)
+		}
+	}
+	return overdue
+}
+
+// TimeOut marks a step as timed out and fails the molecule.
+func (r *Run) TimeOut(stepID string, now time.Time) {
+	s := r.Steps[stepID]
+	if s == nil {
+		return
+	}
+	s.TimedOut = true
+	r.markFailed(fmt.Sprintf("step %s exceeded its %s timeout", stepID, s.Timeout), now)
+}
+
+func (r *Run) stepIDs() []string {
+	ids := make([]string, 0, len(r.Steps))
+	for id := range r.Steps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// RunPath returns the path of a molecule's run record.
+func RunPath(townRoot, moleculeID string) string {
+	return filepath.Join(townRoot, ".runtime", "formula-runs", moleculeID+".json")
+}
+
+// SaveRun writes a run record.
+func SaveRun(townRoot string, run *Run) error {
+	return util.EnsureDirAndWriteJSON(RunPath(townRoot, run.MoleculeID), run)
+}
+
+// LoadRun reads a molecule's run record. Returns nil, nil if the molecule
+// has none (its formula uses no step policies).
+func LoadRun(townRoot, moleculeID string) (*Run, error) {
+	data, err := os.ReadFile(RunPath(townRoot, moleculeID)) //nolint:gosec // G304: path from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("parsing run %s: %w", moleculeID, err)
+	}
+	return &run, nil
+}
+
+// ListRuns reads all run records in the town, skipping unreadable ones.
+func ListRuns(townRoot string) ([]*Run, error) {
+	dir := filepath.Join(townRoot, ".runtime", "formula-runs")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var runs []*Run
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if run, err := LoadRun(townRoot, id); err == nil && run != nil {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// RemoveRun deletes a molecule's run record.
+func RemoveRun(townRoot, moleculeID string) error {
+	err := os.Remove(RunPath(townRoot, moleculeID))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
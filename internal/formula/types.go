@@ -28,92 +28,134 @@ const (
 type Formula struct {
 	// Common fields
 	Name        string      `toml:"formula"`
-	Description string      `toml:"description"`
-	Type        FormulaType `toml:"type"`
-	Version     int         `toml:"version"`
+	Description string      `toml:"description,omitempty"`
+	Type        FormulaType `toml:"type,omitempty"`
+	Version     int         `toml:"version,omitzero"`
+
+	// Composition (see Resolve): parent formulas whose steps and vars this
+	// formula inherits and overrides, and step fragments included from others.
+	Extends  StringList `toml:"extends,omitempty"`
+	Includes []Include  `toml:"include,omitempty"`
 
 	// Convoy-specific
-	Inputs    map[string]Input `toml:"inputs"`
-	Prompts   map[string]string `toml:"prompts"`
-	Output    *Output           `toml:"output"`
-	Legs      []Leg             `toml:"legs"`
-	Synthesis *Synthesis        `toml:"synthesis"`
+	Inputs    map[string]Input  `toml:"inputs,omitempty"`
+	Prompts   map[string]string `toml:"prompts,omitempty"`
+	Output    *Output           `toml:"output,omitempty"`
+	Legs      []Leg             `toml:"legs,omitempty"`
+	Synthesis *Synthesis        `toml:"synthesis,omitempty"`
 
 	// Workflow-specific
-	Steps []Step           `toml:"steps"`
-	Vars  map[string]Var   `toml:"vars"`
+	Steps []Step         `toml:"steps,omitempty"`
+	Vars  map[string]Var `toml:"vars,omitempty"`
 
 	// Expansion-specific
-	Template []Template `toml:"template"`
+	Template []Template `toml:"template,omitempty"`
 
 	// Aspect-specific (similar to convoy but for analysis)
-	Aspects []Aspect `toml:"aspects"`
+	Aspects []Aspect `toml:"aspects,omitempty"`
 }
 
 // Aspect represents a parallel analysis aspect in an aspect formula.
 type Aspect struct {
 	ID          string `toml:"id"`
-	Title       string `toml:"title"`
-	Focus       string `toml:"focus"`
-	Description string `toml:"description"`
+	Title       string `toml:"title,omitempty"`
+	Focus       string `toml:"focus,omitempty"`
+	Description string `toml:"description,omitempty"`
 }
 
 // Input represents an input parameter for a formula.
 type Input struct {
-	Description    string   `toml:"description"`
-	Type           string   `toml:"type"`
-	Required       bool     `toml:"required"`
-	RequiredUnless []string `toml:"required_unless"`
-	Default        string   `toml:"default"`
+	Description    string   `toml:"description,omitempty"`
+	Type           string   `toml:"type,omitempty"`
+	Required       bool     `toml:"required,omitempty"`
+	RequiredUnless []string `toml:"required_unless,omitempty"`
+	Default        string   `toml:"default,omitempty"`
 }
 
 // Output configures where formula outputs are written.
 type Output struct {
-	Directory  string `toml:"directory"`
-	LegPattern string `toml:"leg_pattern"`
-	Synthesis  string `toml:"synthesis"`
+	Directory  string `toml:"directory,omitempty"`
+	LegPattern string `toml:"leg_pattern,omitempty"`
+	Synthesis  string `toml:"synthesis,omitempty"`
 }
 
 // Leg represents a parallel execution unit in a convoy formula.
 type Leg struct {
 	ID          string `toml:"id"`
-	Title       string `toml:"title"`
-	Focus       string `toml:"focus"`
-	Description string `toml:"description"`
+	Title       string `toml:"title,omitempty"`
+	Focus       string `toml:"focus,omitempty"`
+	Description string `toml:"description,omitempty"`
 }
 
 // Synthesis represents the synthesis step that combines leg outputs.
 type Synthesis struct {
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	DependsOn   []string `toml:"depends_on"`
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	DependsOn   []string `toml:"depends_on,omitempty"`
 }
 
 // Step represents a sequential step in a workflow formula.
 type Step struct {
 	ID          string   `toml:"id"`
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
-	Parallel    bool     `toml:"parallel"`   // If true, this step can run concurrently with other parallel steps that share the same needs
-	Acceptance  string   `toml:"acceptance"` // Exit criteria for this step (used by Ralph loop mode)
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	Needs       []string `toml:"needs,omitempty"`
+	Parallel    bool     `toml:"parallel,omitempty"`   // If true, this step can run concurrently with other parallel steps that share the same needs
+	Acceptance  string   `toml:"acceptance,omitempty"` // Exit criteria for this step (used by Ralph loop mode)
+	When        string   `toml:"when,omitempty"`       // Condition over vars; the step is skipped when false (see ParseCondition)
+	Retries     int      `toml:"retries,omitzero"`    // Extra attempts allowed after a failure
+	Backoff     string   `toml:"backoff,omitempty"`    // Delay before the first retry, doubled per attempt (default: 30s)
+	Timeout     string   `toml:"timeout,omitempty"`    // Maximum run time per attempt; the witness fails the molecule past it
+}
+
+// Include pulls steps from another formula into a workflow. Included step
+// IDs are prefixed ("<prefix>.<id>") so fragments cannot collide.
+type Include struct {
+	Formula string   `toml:"formula"`          // formula to include steps from
+	Steps   []string `toml:"steps,omitempty"`  // subset of steps (plus what they need); default all
+	Prefix  string   `toml:"prefix,omitempty"` // ID prefix (default: the formula name)
+	Needs   []string `toml:"needs,omitempty"`  // steps the fragment's entry steps wait for
+}
+
+// StringList is a list of strings that may also be written as a single string.
+type StringList []string
+
+// UnmarshalTOML accepts `extends = "a"` as well as `extends = ["a", "b"]`.
+func (l *StringList) UnmarshalTOML(data any) error {
+	switch val := data.(type) {
+	case string:
+		*l = StringList{val}
+	case []any:
+		out := make(StringList, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("expected string, got %T", item)
+			}
+			out = append(out, s)
+		}
+		*l = out
+	default:
+		return fmt.Errorf("expected string or array of strings, got %T", data)
+	}
+	return nil
 }
 
 // Template represents a template step in an expansion formula.
 type Template struct {
 	ID          string   `toml:"id"`
-	Title       string   `toml:"title"`
-	Description string   `toml:"description"`
-	Needs       []string `toml:"needs"`
+	Title       string   `toml:"title,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	Needs       []string `toml:"needs,omitempty"`
 }
 
 // Var represents a variable definition for formulas.
 // Supports both shorthand string syntax (wisp_type = "gc_report")
 // and full table syntax ([vars.wisp_type] with description/required/default).
 type Var struct {
-	Description string `toml:"description"`
-	Required    bool   `toml:"required"`
-	Default     string `toml:"default"`
+	Description string `toml:"description,omitempty"`
+	Required    bool   `toml:"required,omitempty"`
+	Default     string `toml:"default,omitempty"`
 }
 
 // UnmarshalTOML allows Var to be decoded from either a plain string
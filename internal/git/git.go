@@ -368,6 +368,14 @@ func (g *Git) Checkout(ref string) error {
 	return err
 }
 
+// CheckoutDetached checks out the commit at ref with a detached HEAD.
+// Unlike Checkout, this works even when ref is a branch that is already
+// checked out in another worktree, and never moves the branch itself.
+func (g *Git) CheckoutDetached(ref string) error {
+	_, err := g.run("checkout", "--detach", ref)
+	return err
+}
+
 // Fetch fetches from the remote.
 func (g *Git) Fetch(remote string) error {
 	_, err := g.run("fetch", remote)
@@ -647,6 +655,13 @@ func (g *Git) MergeSquash(branch, message string) error {
 	return err
 }
 
+// MergeFFOnly fast-forwards the current branch to the given ref. It fails
+// without changing anything if the current branch has diverged from ref.
+func (g *Git) MergeFFOnly(ref string) error {
+	_, err := g.run("merge", "--ff-only", ref)
+	return err
+}
+
 // GetBranchCommitMessage returns the commit message of the HEAD commit on the given branch.
 // This is useful for preserving the original conventional commit message (feat:/fix:) when
 // performing squash merges.
@@ -785,6 +800,18 @@ func (g *Git) GetConflictingFiles() ([]string, error) {
 	return result, nil
 }
 
+// CommitFiles returns the files a commit changed relative to its first parent.
+func (g *Git) CommitFiles(commit string) ([]string, error) {
+	out, err := g.run("diff-tree", "--no-commit-id", "--name-only", "-r", "--first-parent", "--root", commit)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
 // AbortRebase aborts a rebase in progress.
 func (g *Git) AbortRebase() error {
 	_, err := g.run("rebase", "--abort")
@@ -17,6 +17,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/ptyd"
 )
 
 // Common errors
@@ -258,8 +260,8 @@ func CleanStaleLocks(root string) (int, error) {
 		return 0, err
 	}
 
-	// Get active tmux sessions to verify locks
-	activeSessions := getActiveTmuxSessions()
+	// Get active sessions to verify locks
+	activeSessions := append(getActiveTmuxSessions(), listPTYSessions()...)
 	sessionSet := make(map[string]bool)
 	for _, s := range activeSessions {
 		sessionSet[s] = true
@@ -318,6 +320,13 @@ func getActiveTmuxSessions() []string {
 	return sessions
 }
 
+// listPTYSessions returns the sessions held by the headless pty supervisor
+// (see ptyd), for towns running without tmux. Replaced in tests.
+var listPTYSessions = func() []string {
+	names, _ := ptyd.NewClient(ptyd.SocketPath()).ListSessions()
+	return names
+}
+
 // splitOnColon splits on the first colon only (session names shouldn't have colons)
 func splitOnColon(s string) []string {
 	idx := -1
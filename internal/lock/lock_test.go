@@ -2,6 +2,7 @@ package lock
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -452,6 +453,33 @@ func TestCleanStaleLocks(t *testing.T) {
 	}
 }
 
+func TestCleanStaleLocks_PTYSessionKeepsLock(t *testing.T) {
+	origExecCommand, origPTY := execCommand, listPTYSessions
+	defer func() { execCommand, listPTYSessions = origExecCommand, origPTY }()
+	execCommand = func(name string, args ...string) interface{ Output() ([]byte, error) } {
+		return &mockCmd{err: errors.New("no server running")}
+	}
+	listPTYSessions = func() []string { return []string{"gt-gastown-nux"} }
+
+	tmpDir := t.TempDir()
+	runtimeDir := filepath.Join(tmpDir, "nux", ".runtime")
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(LockInfo{PID: 999999999, AcquiredAt: time.Now(), SessionID: "gt-gastown-nux"})
+	if err := os.WriteFile(filepath.Join(runtimeDir, "agent.lock"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cleaned, err := CleanStaleLocks(tmpDir)
+	if err != nil {
+		t.Fatalf("CleanStaleLocks() error = %v", err)
+	}
+	if cleaned != 0 {
+		t.Errorf("CleanStaleLocks() cleaned %d, want 0 (pty session alive)", cleaned)
+	}
+}
+
 type mockCmd struct {
 	output []byte
 	err    error
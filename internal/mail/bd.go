@@ -55,7 +55,11 @@ func (e *bdError) ContainsError(substr string) bool {
 // extraEnv contains additional environment variables to set (e.g., "BD_IDENTITY=...").
 // Returns stdout bytes on success, or a *bdError on failure.
 func runBdCommand(ctx context.Context, args []string, workDir, beadsDir string, extraEnv ...string) (_ []byte, retErr error) {
-	defer func() { telemetry.RecordMail(ctx, "bd."+firstArg(args), retErr) }()
+	spanCtx, span := telemetry.StartSpan(context.Background(), "bd "+firstArg(args))
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+		telemetry.RecordMail(ctx, "bd."+firstArg(args), retErr)
+	}()
 
 	// Remove stale dolt-server.pid before spawning bd. A stale PID file causes
 	// bd to connect to port 3307 which may be occupied by a different Dolt server
@@ -68,6 +72,7 @@ func runBdCommand(ctx context.Context, args []string, workDir, beadsDir string,
 	env := append(cmd.Environ(), "BEADS_DIR="+beadsDir)
 	env = append(env, extraEnv...)
 	env = append(env, telemetry.OTELEnvForSubprocess()...)
+	env = append(env, telemetry.TraceEnv(spanCtx)...)
 	cmd.Env = env
 
 	var stdout, stderr bytes.Buffer
@@ -25,6 +25,7 @@ var timeNow = time.Now
 var (
 	ErrMessageNotFound = errors.New("message not found")
 	ErrEmptyInbox      = errors.New("inbox is empty")
+	ErrMessageExpired  = errors.New("message has expired")
 )
 
 // Mailbox manages messages for an identity via beads.
@@ -142,6 +143,7 @@ func (m *Mailbox) listFromDir(beadsDir string) ([]*Message, error) {
 	// Deduplicate messages across queries (assignee + CC may overlap)
 	seen := make(map[string]bool)
 	messages := make([]*Message, 0)
+	now := timeNow()
 
 	// Query 1: assignee match (per identity variant)
 	for _, id := range identities {
@@ -172,10 +174,13 @@ func (m *Mailbox) listFromDir(beadsDir string) ([]*Message, error) {
 			if seen[bm.ID] {
 				continue
 			}
-			// Assignee match: open or hooked status
+			// Assignee match: open or hooked status, unless expired
+			// (the daemon archives those, but may not have run yet)
 			if bm.Status == "open" || bm.Status == "hooked" {
 				seen[bm.ID] = true
-				messages = append(messages, bm.ToMessage())
+				if msg := bm.ToMessage(); !msg.IsExpired(now) {
+					messages = append(messages, msg)
+				}
 			}
 		}
 	}
@@ -214,7 +219,9 @@ func (m *Mailbox) listFromDir(beadsDir string) ([]*Message, error) {
 			// CC match: open status only
 			if bm.Status == "open" {
 				seen[bm.ID] = true
-				messages = append(messages, bm.ToMessage())
+				if msg := bm.ToMessage(); !msg.IsExpired(now) {
+					messages = append(messages, msg)
+				}
 			}
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -12,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -812,7 +814,22 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // Supports single-copy delivery for:
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
+// Messages with a future DeliverAt are held in the scheduled store instead
+// (any address type); ReleaseScheduled sends them when due.
 func (r *Router) Send(msg *Message) error {
+	now := timeNow()
+	if msg.IsExpired(now) {
+		return ErrMessageExpired
+	}
+	if msg.IsDeferred(now) {
+		return r.schedule(msg)
+	}
+	if msg.ExpiresAt != nil {
+		if err := r.noteExpiry(*msg.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -948,6 +965,13 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
 	}
 
+	// Apply the recipient's mail rules (settings/mail-rules.toml)
+	msg, rules := r.applyMailRules(msg)
+	digest := rules.Digest
+	if rules.ArchivedBy != "" {
+		digest = "" // archived mail is not summarized
+	}
+
 	// Build labels for type, from/thread/reply-to/cc
 	var labels []string
 	labels = append(labels, "gt:message")
@@ -964,6 +988,10 @@ func (r *Router) sendToSingle(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	if msg.ExpiresAt != nil {
+		labels = append(labels, ExpiryLabel(*msg.ExpiresAt))
+	}
+	labels = append(labels, ruleLabels(rules)...)
 
 	// Build command: bd create --assignee=<recipient> -d <body> --labels=gt:message,... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
@@ -990,6 +1018,11 @@ func (r *Router) sendToSingle(msg *Message) error {
 		args = append(args, "--ephemeral")
 	}
 
+	// Archiving and digest rules need the created message's ID.
+	if rules.ArchivedBy != "" || digest != "" {
+		args = append(args, "--json")
+	}
+
 	// End flag parsing with --, then add subject as positional argument.
 	// This prevents subjects like "--help" or "--json" from being parsed as flags.
 	args = append(args, "--", msg.Subject)
@@ -1000,19 +1033,43 @@ func (r *Router) sendToSingle(msg *Message) error {
 	}
 	ctx, cancel := bdWriteCtx()
 	defer cancel()
-	_, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return fmt.Errorf("sending message: %w", err)
 	}
 
+	if rules.ArchivedBy != "" {
+		if err := r.archiveByRule(out, rules.ArchivedBy); err != nil {
+			return err
+		}
+		r.forwardByRules(msg, rules)
+		return nil
+	}
+
+	// A digest rule delivers the message quietly and notifies about the
+	// digest instead, once when it is started. If the digest can't be
+	// updated the message is announced on its own.
+	notifyMsg := msg
+	if digest != "" {
+		id, err := createdID(out)
+		if err == nil {
+			notifyMsg, err = r.addToDigest(toIdentity, msg, id, digest)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mail rule %s: %v (notifying directly)\n", digest, err)
+			notifyMsg = msg
+		}
+	}
+
 	// Notify recipient if they have an active session (best-effort notification).
 	// Skip when the caller explicitly suppressed notification (--no-notify)
 	// or for self-mail (handoffs to future-self don't need present-self notified).
 	// Notification is async: the durable write is complete, so the caller
 	// doesn't block on idle probing (up to 1s per recipient in fan-out).
 	// Callers that exit soon after Send should call WaitPendingNotifications.
-	if !msg.SuppressNotify && !isSelfMail(msg.From, msg.To) {
-		msgCopy := *msg // copy to avoid data race if caller mutates msg
+	if notifyMsg != nil && !msg.SuppressNotify && !isSelfMail(msg.From, msg.To) {
+		msgCopy := *notifyMsg // copy to avoid data race if caller mutates msg
+		msgCopy.Delivery = msg.Delivery
 		r.notifyWg.Add(1)
 		go func() {
 			defer r.notifyWg.Done()
@@ -1020,6 +1077,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 		}()
 	}
 
+	r.forwardByRules(msg, rules)
 	return nil
 }
 
@@ -1092,6 +1150,9 @@ func (r *Router) sendToQueue(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	if msg.ExpiresAt != nil {
+		labels = append(labels, ExpiryLabel(*msg.ExpiresAt))
+	}
 
 	// Build command: bd create --assignee=queue:<name> -d <body> ... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
@@ -1176,6 +1237,9 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	if msg.ExpiresAt != nil {
+		labels = append(labels, ExpiryLabel(*msg.ExpiresAt))
+	}
 
 	// Build command: bd create --assignee=announce:<name> -d <body> ... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
@@ -1262,6 +1326,9 @@ func (r *Router) sendToChannel(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	if msg.ExpiresAt != nil {
+		labels = append(labels, ExpiryLabel(*msg.ExpiresAt))
+	}
 
 	// Build command: bd create --assignee=channel:<name> -d <body> ... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
@@ -1403,6 +1470,39 @@ func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 	return NewMailboxFromAddress(address, workDir), nil
 }
 
+// OpenMessageCounts returns the number of open (unread) direct messages per
+// recipient address, from a single query of the town's mail.
+func (r *Router) OpenMessageCounts() (map[string]int, error) {
+	beadsDir := r.resolveBeadsDir()
+	args := []string{"list",
+		"--label", "gt:message",
+		"--status", "open",
+		"--json",
+		"--limit", "0",
+	}
+	ctx, cancel := bdReadCtx()
+	defer cancel()
+	stdout, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	if len(stdout) == 0 || string(stdout) == "null" {
+		return counts, nil
+	}
+	var msgs []BeadsMessage
+	if err := json.Unmarshal(stdout, &msgs); err != nil {
+		return nil, fmt.Errorf("parsing messages: %w", err)
+	}
+	for _, msg := range msgs {
+		if msg.Assignee != "" {
+			counts[msg.Assignee]++
+		}
+	}
+	return counts, nil
+}
+
 // notifyRecipient sends a notification to a recipient's tmux session.
 //
 // Notification strategy (idle-aware):
@@ -1426,6 +1526,20 @@ func (r *Router) notifyRecipient(msg *Message) error {
 		return nil // Unable to determine session ID
 	}
 
+	notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
+
+	// Inside the sandbox tmux is out of reach: queue the notification for
+	// the recipient to drain. Ambiguous addresses are left to the inbox hook.
+	if sandbox.Active() {
+		if len(sessionIDs) != 1 || r.townRoot == "" || msg.To == "overseer" {
+			return nil
+		}
+		return nudge.Enqueue(r.townRoot, sessionIDs[0], nudge.QueuedNudge{
+			Sender:  msg.From,
+			Message: notification,
+		})
+	}
+
 	timeout := r.IdleNotifyTimeout
 	if timeout == 0 {
 		timeout = DefaultIdleNotifyTimeout
@@ -1446,27 +1560,37 @@ func (r *Router) notifyRecipient(msg *Message) error {
 			return r.tmux.SendNotificationBanner(sessionID, msg.From, msg.Subject)
 		}
 
-		notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
+		// Interrupt delivery (set by a mail rule) nudges right away, busy or not.
+		if msg.Delivery == DeliveryInterrupt {
+			err := r.tmux.NudgeSession(sessionID, notification)
+			if errors.Is(err, tmux.ErrSessionNotFound) {
+				continue
+			}
+			return err
+		}
 
 		// Idle-aware notification: try immediate nudge first, fall back to queue.
-		waitErr := r.tmux.WaitForIdle(sessionID, timeout)
-		if waitErr == nil {
-			// Session is idle → send immediate nudge
-			if err := r.tmux.NudgeSession(sessionID, notification); err == nil {
+		// Queue delivery (set by a mail rule) goes straight to the queue.
+		if msg.Delivery != DeliveryQueue {
+			waitErr := r.tmux.WaitForIdle(sessionID, timeout)
+			if waitErr == nil {
+				// Session is idle → send immediate nudge
+				if err := r.tmux.NudgeSession(sessionID, notification); err == nil {
+					return nil
+				} else if errors.Is(err, tmux.ErrSessionNotFound) {
+					// Session disappeared between idle check and nudge — try next candidate
+					continue
+				} else if errors.Is(err, tmux.ErrNoServer) {
+					return nil
+				}
+				// NudgeSession failed for non-terminal reason — fall through to queue
+			} else if errors.Is(waitErr, tmux.ErrNoServer) {
+				// No tmux server — no point trying other candidates
 				return nil
-			} else if errors.Is(err, tmux.ErrSessionNotFound) {
-				// Session disappeared between idle check and nudge — try next candidate
+			} else if errors.Is(waitErr, tmux.ErrSessionNotFound) {
+				// Session disappeared — try next candidate
 				continue
-			} else if errors.Is(err, tmux.ErrNoServer) {
-				return nil
 			}
-			// NudgeSession failed for non-terminal reason — fall through to queue
-		} else if errors.Is(waitErr, tmux.ErrNoServer) {
-			// No tmux server — no point trying other candidates
-			return nil
-		} else if errors.Is(waitErr, tmux.ErrSessionNotFound) {
-			// Session disappeared — try next candidate
-			continue
 		}
 
 		// Busy or nudge failed → enqueue for cooperative delivery at the
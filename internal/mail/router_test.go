@@ -11,6 +11,8 @@ import (
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/testutil"
 )
@@ -1169,3 +1171,20 @@ func TestAddressToAgentBeadID(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifyRecipient_SandboxedQueues(t *testing.T) {
+	t.Setenv(sandbox.EnvSandboxed, "1")
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	// tmux is unreachable from the sandbox: the notification is queued
+	// for the recipient to drain instead.
+	msg := NewMessage("gastown/polecats/nux", "gastown/polecats/alpha", "Review", "Please look")
+	if err := r.notifyRecipient(msg); err != nil {
+		t.Fatalf("notifyRecipient: %v", err)
+	}
+	sessionID := AddressToSessionIDs(msg.To)[0]
+	if n, err := nudge.Pending(townRoot, sessionID); err != nil || n != 1 {
+		t.Errorf("Pending(%s) = %d, %v; want 1 queued notification", sessionID, n, err)
+	}
+}
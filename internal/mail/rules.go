@@ -0,0 +1,406 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// RuleTrace records how one rule fared against a message.
+type RuleTrace struct {
+	Rule    string `json:"rule"`
+	Matched bool   `json:"matched"`
+	// Reason names the first criterion that did not match, or why the
+	// rule was not evaluated.
+	Reason string `json:"reason,omitempty"`
+}
+
+// RuleResult is the combined effect of the mail rules on one message.
+type RuleResult struct {
+	Trace []RuleTrace `json:"trace"`
+
+	// Matched lists the matching rules, in evaluation order.
+	Matched []string `json:"matched,omitempty"`
+
+	// ArchivedBy is the first matching rule that archives the message.
+	ArchivedBy string   `json:"archived_by,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	Priority   Priority `json:"priority"`
+	Delivery   Delivery `json:"delivery,omitempty"`
+	Forward    []string `json:"forward,omitempty"`
+
+	// Digest is the first matching rule that coalesces the message.
+	Digest string `json:"digest,omitempty"`
+}
+
+// EvaluateRules runs the rules against msg as delivered to msg.To.
+// A nil config matches nothing.
+func EvaluateRules(cfg *config.MailRulesConfig, msg *Message) *RuleResult {
+	result := &RuleResult{Priority: msg.Priority, Delivery: msg.Delivery}
+	if cfg == nil {
+		return result
+	}
+
+	stopped := ""
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		if stopped != "" {
+			result.Trace = append(result.Trace, RuleTrace{Rule: rule.Name, Reason: "stopped by " + stopped})
+			continue
+		}
+		reason := matchRule(rule, msg)
+		result.Trace = append(result.Trace, RuleTrace{Rule: rule.Name, Matched: reason == "", Reason: reason})
+		if reason != "" {
+			continue
+		}
+
+		result.Matched = append(result.Matched, rule.Name)
+		if rule.Archive && result.ArchivedBy == "" {
+			result.ArchivedBy = rule.Name
+		}
+		result.Labels = append(result.Labels, rule.Labels...)
+		if rule.Priority != "" {
+			result.Priority = applyRulePriority(result.Priority, rule.Priority)
+		}
+		if rule.Delivery != "" {
+			result.Delivery = Delivery(rule.Delivery)
+		}
+		if !msg.forwarded {
+			result.Forward = append(result.Forward, rule.Forward...)
+		}
+		if rule.Digest && result.Digest == "" {
+			result.Digest = rule.Name
+		}
+		if rule.Stop {
+			stopped = rule.Name
+		}
+	}
+	return result
+}
+
+// matchRule returns "" if rule matches msg, or the criterion that failed.
+func matchRule(rule *config.MailRule, msg *Message) string {
+	if len(rule.To) > 0 && !matchAddress(rule.To, msg.To) {
+		return "to"
+	}
+	if len(rule.Roles) > 0 && !slices.Contains(rule.Roles, recipientRole(AddressToIdentity(msg.To))) {
+		return "roles"
+	}
+	if len(rule.From) > 0 && !matchAddress(rule.From, msg.From) {
+		return "from"
+	}
+	if len(rule.Types) > 0 && !slices.Contains(rule.Types, string(msg.Type)) {
+		return "types"
+	}
+	if !rule.MatchSubject(msg.Subject) {
+		return "subject"
+	}
+	if !rule.MatchBody(msg.Body) {
+		return "body"
+	}
+	return ""
+}
+
+// matchAddress reports whether address, as written or as its normalized
+// identity, matches any of the patterns.
+func matchAddress(patterns []string, address string) bool {
+	identity := AddressToIdentity(address)
+	for _, p := range patterns {
+		if p == "*" || matchPattern(p, address) || matchPattern(p, identity) {
+			return true
+		}
+	}
+	return false
+}
+
+// recipientRole returns the role a rule's roles field matches for an
+// identity. Polecats and crew share normalized identities, so both are
+// "worker".
+func recipientRole(identity string) string {
+	switch {
+	case identity == "mayor/":
+		return "mayor"
+	case identity == "deacon/":
+		return "deacon"
+	case identity == "overseer":
+		return "overseer"
+	case strings.HasPrefix(identity, "dog/"):
+		return "dog"
+	}
+	parts := strings.Split(identity, "/")
+	if len(parts) != 2 || parts[1] == "" {
+		return ""
+	}
+	switch parts[1] {
+	case "witness", "refinery":
+		return parts[1]
+	}
+	return "worker"
+}
+
+// priorityOrder lists priorities from least to most urgent.
+var priorityOrder = []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent}
+
+// applyRulePriority applies a rule's priority setting: a level, or a step
+// "raise" or "lower" clamped to the ends of the scale.
+func applyRulePriority(p Priority, setting string) Priority {
+	step := 0
+	switch setting {
+	case "raise":
+		step = 1
+	case "lower":
+		step = -1
+	default:
+		return Priority(setting)
+	}
+	i := 1 // unknown priorities count as normal
+	for j, q := range priorityOrder {
+		if q == p {
+			i = j
+		}
+	}
+	i = min(max(i+step, 0), len(priorityOrder)-1)
+	return priorityOrder[i]
+}
+
+// mailRulesCache holds the last load of each rules file, keyed by path, so
+// delivery re-reads the file only when it changes.
+var mailRulesCache = struct {
+	sync.Mutex
+	entries map[string]mailRulesEntry
+}{entries: make(map[string]mailRulesEntry)}
+
+// mailRulesEntry is one cached load of a rules file.
+type mailRulesEntry struct {
+	modTime time.Time
+	size    int64
+	cfg     *config.MailRulesConfig
+	err     error
+}
+
+// loadMailRulesCached loads the rules file at path, reusing the previous
+// load while the file's modification time and size are unchanged.
+func loadMailRulesCached(path string) (*config.MailRulesConfig, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		// Missing or unreadable: let the loader report it.
+		return config.LoadMailRules(path)
+	}
+
+	mailRulesCache.Lock()
+	defer mailRulesCache.Unlock()
+	if e, ok := mailRulesCache.entries[path]; ok && e.modTime.Equal(info.ModTime()) && e.size == info.Size() {
+		return e.cfg, e.err
+	}
+	cfg, err := config.LoadMailRules(path)
+	mailRulesCache.entries[path] = mailRulesEntry{modTime: info.ModTime(), size: info.Size(), cfg: cfg, err: err}
+	return cfg, err
+}
+
+// loadMailRules reads the town's mail rules. A missing file means no rules;
+// a broken one is reported and ignored so a typo cannot stop mail.
+func (r *Router) loadMailRules() *config.MailRulesConfig {
+	if r.townRoot == "" {
+		return nil
+	}
+	cfg, err := loadMailRulesCached(config.MailRulesPath(r.townRoot))
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			fmt.Fprintf(os.Stderr, "mail rules: %v (delivering unfiltered)\n", err)
+		}
+		return nil
+	}
+	return cfg
+}
+
+// ApplyRules evaluates the rules for msg and returns the message to deliver
+// (a copy when a rule changed it) with the rules' outcome. Rules change only
+// priority and delivery: subject and body always reach the recipient as
+// sent, so protocol mail stays parseable whatever the rules do.
+func ApplyRules(cfg *config.MailRulesConfig, msg *Message) (*Message, *RuleResult) {
+	result := EvaluateRules(cfg, msg)
+	if len(result.Matched) == 0 {
+		return msg, result
+	}
+	out := *msg
+	out.Priority = result.Priority
+	out.Delivery = result.Delivery
+	return &out, result
+}
+
+// applyMailRules applies the town's mail rules to msg.
+func (r *Router) applyMailRules(msg *Message) (*Message, *RuleResult) {
+	return ApplyRules(r.loadMailRules(), msg)
+}
+
+// ruleLabels returns the labels the rules add to a delivered message.
+func ruleLabels(result *RuleResult) []string {
+	var labels []string
+	for _, name := range result.Matched {
+		labels = append(labels, "rule:"+name)
+	}
+	return append(labels, result.Labels...)
+}
+
+// forwardByRules sends a copy of msg to each forward address. Forwarded
+// copies are not forwarded again. Failures are reported but don't fail the
+// original delivery.
+func (r *Router) forwardByRules(msg *Message, result *RuleResult) {
+	seen := map[string]bool{AddressToIdentity(msg.To): true}
+	for _, addr := range result.Forward {
+		if seen[AddressToIdentity(addr)] {
+			continue
+		}
+		seen[AddressToIdentity(addr)] = true
+
+		fwd := *msg
+		fwd.ID = ""
+		fwd.To = addr
+		fwd.forwarded = true
+		if err := r.Send(&fwd); err != nil {
+			fmt.Fprintf(os.Stderr, "mail rules: forwarding %q to %s: %v\n", msg.Subject, addr, err)
+		}
+	}
+}
+
+// digestEntry is the line a message adds to its recipient's digest. It names
+// the delivered message so the full text is one `gt mail read` away.
+func digestEntry(msg *Message, id string) string {
+	return fmt.Sprintf("- %s %s: %s (%s)", timeNow().Format("2006-01-02 15:04"), msg.From, msg.Subject, id)
+}
+
+// digestSubject is the subject of a digest holding n messages.
+func digestSubject(rule string, n int) string {
+	if n == 1 {
+		return fmt.Sprintf("[digest] %s: 1 message", rule)
+	}
+	return fmt.Sprintf("[digest] %s: %d messages", rule, n)
+}
+
+// appendDigest adds entry to a digest body, returning the new body and the
+// number of messages it now holds.
+func appendDigest(body, entry string) (string, int) {
+	body = strings.TrimRight(body, "\n")
+	if body != "" {
+		body += "\n"
+	}
+	body += entry
+	n := 0
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "- ") {
+			n++
+		}
+	}
+	return body, n
+}
+
+// addToDigest records the delivered message id in the recipient's open
+// digest for rule, starting a new digest if there is none. The digest is a
+// summary alongside the messages, not a replacement: they stay in the inbox
+// as sent. Returns the new digest when one was started, nil otherwise.
+func (r *Router) addToDigest(toIdentity string, msg *Message, id, rule string) (*Message, error) {
+	entry := digestEntry(msg, id)
+	folded, err := r.foldIntoDigest(toIdentity, entry, rule)
+	if err != nil || folded {
+		return nil, err
+	}
+
+	digest := NewMessage(msg.From, msg.To, digestSubject(rule, 1), entry)
+	labels := []string{"gt:message", "from:" + digest.From, "digest:" + rule}
+	labels = append(labels, DeliverySendLabels()...)
+	args := []string{"create",
+		"--assignee", toIdentity,
+		"-d", digest.Body,
+		"--priority", fmt.Sprintf("%d", PriorityToBeads(msg.Priority)),
+		"--labels", strings.Join(labels, ","),
+		"--actor", digest.From,
+		"--", digest.Subject,
+	}
+	beadsDir := r.resolveBeadsDir()
+	ctx, cancel := bdWriteCtx()
+	defer cancel()
+	if _, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir); err != nil {
+		return nil, fmt.Errorf("creating digest: %w", err)
+	}
+	return digest, nil
+}
+
+// foldIntoDigest appends entry to the recipient's open digest for rule.
+// Returns false when there is no open digest yet.
+func (r *Router) foldIntoDigest(toIdentity, entry, rule string) (bool, error) {
+	beadsDir := r.resolveBeadsDir()
+	args := []string{"list",
+		"--label", "digest:" + rule,
+		"--assignee", toIdentity,
+		"--status", "open",
+		"--json",
+		"--limit", "0",
+	}
+	ctx, cancel := bdReadCtx()
+	stdout, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	var digests []BeadsMessage
+	if len(stdout) > 0 && string(stdout) != "null" {
+		if err := json.Unmarshal(stdout, &digests); err != nil {
+			return false, fmt.Errorf("parsing digests: %w", err)
+		}
+	}
+	if len(digests) == 0 {
+		return false, nil
+	}
+
+	digest := digests[0]
+	body, n := appendDigest(digest.Description, entry)
+	update := []string{"update", digest.ID,
+		"--title=" + digestSubject(rule, n),
+		"--description=" + body,
+	}
+	ctx, cancel = bdWriteCtx()
+	defer cancel()
+	if _, err := runBdCommand(ctx, update, filepath.Dir(beadsDir), beadsDir); err != nil {
+		return false, fmt.Errorf("updating digest %s: %w", digest.ID, err)
+	}
+	return true, nil
+}
+
+// createdID reads the new bead's ID from bd create --json output.
+func createdID(createOut []byte) (string, error) {
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(createOut, &created); err != nil {
+		return "", fmt.Errorf("reading created message ID: %w", err)
+	}
+	if created.ID == "" {
+		return "", errors.New("reading created message ID: no id in bd output")
+	}
+	return created.ID, nil
+}
+
+// archiveByRule closes a just-created message (from bd create --json
+// output) on behalf of an archiving rule. The recipient is not notified.
+func (r *Router) archiveByRule(createOut []byte, rule string) error {
+	id, err := createdID(createOut)
+	if err != nil {
+		return fmt.Errorf("mail rule %s: %w", rule, err)
+	}
+	beadsDir := r.resolveBeadsDir()
+	ctx, cancel := bdWriteCtx()
+	defer cancel()
+	args := []string{"close", id, "--reason=archived by mail rule " + rule}
+	if _, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir); err != nil {
+		return fmt.Errorf("mail rule %s: archiving %s: %w", rule, id, err)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+var testMailRules = &config.MailRulesConfig{Rules: []config.MailRule{
+	{Name: "witness-status", Roles: []string{"witness"}, Subject: "^STATUS", Digest: true},
+	{Name: "from-polecats", From: []string{"*/polecats/*"}, Labels: []string{"protocol"}, Priority: "lower"},
+	{Name: "escalations", To: []string{"mayor/"}, Subject: "ESCALATION", Priority: "urgent", Delivery: "interrupt", Forward: []string{"overseer"}, Stop: true},
+	{Name: "mayor-lifecycle", To: []string{"mayor/"}, Types: []string{"notification"}, Archive: true},
+}}
+
+func TestEvaluateRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      *Message
+		matched  []string
+		priority Priority
+		archived string
+		digest   string
+	}{
+		{
+			name:     "protocol mail to witness",
+			msg:      NewMessage("gastown/polecats/nux", "gastown/witness", "POLECAT_DONE nux", ""),
+			matched:  []string{"from-polecats"},
+			priority: PriorityLow,
+		},
+		{
+			name:     "status mail to witness",
+			msg:      NewMessage("gastown/polecats/nux", "gastown/witness", "STATUS nux: running tests", ""),
+			matched:  []string{"witness-status", "from-polecats"},
+			priority: PriorityLow,
+			digest:   "witness-status",
+		},
+		{
+			name:     "other mail to witness",
+			msg:      NewMessage("mayor/", "gastown/witness", "Status?", ""),
+			priority: PriorityNormal,
+		},
+		{
+			name:     "escalation stops later rules",
+			msg:      NewMessage("gastown/witness", "mayor/", "ESCALATION: stuck", ""),
+			matched:  []string{"escalations"},
+			priority: PriorityUrgent,
+		},
+		{
+			name:     "mayor notification archived",
+			msg:      NewMessage("deacon/", "mayor", "LIFECYCLE: cycled", ""),
+			matched:  []string{"mayor-lifecycle"},
+			priority: PriorityNormal,
+			archived: "mayor-lifecycle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluateRules(testMailRules, tt.msg)
+			if strings.Join(got.Matched, ",") != strings.Join(tt.matched, ",") {
+				t.Errorf("matched = %v, want %v", got.Matched, tt.matched)
+			}
+			if got.Priority != tt.priority || got.ArchivedBy != tt.archived || got.Digest != tt.digest {
+				t.Errorf("result = %+v", got)
+			}
+			if len(got.Trace) != len(testMailRules.Rules) {
+				t.Errorf("trace has %d entries, want one per rule", len(got.Trace))
+			}
+		})
+	}
+}
+
+func TestEvaluateRules_Trace(t *testing.T) {
+	msg := NewMessage("gastown/witness", "mayor/", "ESCALATION: stuck", "")
+	got := EvaluateRules(testMailRules, msg)
+
+	want := []RuleTrace{
+		{Rule: "witness-status", Reason: "roles"},
+		{Rule: "from-polecats", Reason: "from"},
+		{Rule: "escalations", Matched: true},
+		{Rule: "mayor-lifecycle", Reason: "stopped by escalations"},
+	}
+	for i, w := range want {
+		if got.Trace[i] != w {
+			t.Errorf("trace[%d] = %+v, want %+v", i, got.Trace[i], w)
+		}
+	}
+	if got.Delivery != DeliveryInterrupt || len(got.Forward) != 1 || got.Forward[0] != "overseer" {
+		t.Errorf("result = %+v", got)
+	}
+
+	// Forwarded copies are not forwarded again.
+	msg.forwarded = true
+	if got := EvaluateRules(testMailRules, msg); len(got.Forward) != 0 {
+		t.Errorf("forwarded copy forwards to %v", got.Forward)
+	}
+}
+
+func TestEvaluateRules_NilConfig(t *testing.T) {
+	msg := NewMessage("mayor/", "gastown/witness", "s", "b")
+	got := EvaluateRules(nil, msg)
+	if len(got.Matched) != 0 || got.Priority != msg.Priority {
+		t.Errorf("result = %+v", got)
+	}
+}
+
+func TestRecipientRole(t *testing.T) {
+	tests := map[string]string{
+		"mayor/":           "mayor",
+		"deacon/":          "deacon",
+		"overseer":         "overseer",
+		"dog/alpha":        "dog",
+		"gastown/witness":  "witness",
+		"gastown/refinery": "refinery",
+		"gastown/nux":      "worker",
+		"gastown/":         "",
+		"queue:work":       "",
+	}
+	for identity, want := range tests {
+		if got := recipientRole(identity); got != want {
+			t.Errorf("recipientRole(%q) = %q, want %q", identity, got, want)
+		}
+	}
+}
+
+func TestApplyRulePriority(t *testing.T) {
+	tests := []struct {
+		p       Priority
+		setting string
+		want    Priority
+	}{
+		{PriorityNormal, "raise", PriorityHigh},
+		{PriorityNormal, "lower", PriorityLow},
+		{PriorityUrgent, "raise", PriorityUrgent},
+		{PriorityLow, "lower", PriorityLow},
+		{PriorityLow, "urgent", PriorityUrgent},
+	}
+	for _, tt := range tests {
+		if got := applyRulePriority(tt.p, tt.setting); got != tt.want {
+			t.Errorf("applyRulePriority(%s, %s) = %s, want %s", tt.p, tt.setting, got, tt.want)
+		}
+	}
+}
+
+func TestRuleLabels(t *testing.T) {
+	got := ruleLabels(&RuleResult{Matched: []string{"a", "b"}, Labels: []string{"protocol"}, Digest: "a"})
+	if strings.Join(got, ",") != "rule:a,rule:b,protocol" {
+		t.Errorf("ruleLabels = %v", got)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	orig := timeNow
+	timeNow = func() time.Time { return time.Date(2026, 3, 1, 9, 5, 0, 0, time.UTC) }
+	defer func() { timeNow = orig }()
+
+	entry := digestEntry(NewMessage("gastown/nux", "gastown/witness", "STATUS nux", "long body"), "hq-abc")
+	if entry != "- 2026-03-01 09:05 gastown/nux: STATUS nux (hq-abc)" {
+		t.Errorf("digestEntry = %q", entry)
+	}
+
+	body, n := appendDigest("", entry)
+	if n != 1 || body != entry {
+		t.Errorf("first entry: n=%d body=%q", n, body)
+	}
+	body, n = appendDigest(body+"\n", "- 2026-03-01 09:06 gastown/max: STATUS max (hq-def)")
+	if n != 2 || strings.Count(body, "\n") != 1 {
+		t.Errorf("second entry: n=%d body=%q", n, body)
+	}
+	if digestSubject("witness-status", n) != "[digest] witness-status: 2 messages" {
+		t.Errorf("digestSubject = %q", digestSubject("witness-status", n))
+	}
+}
+
+func TestApplyMailRules(t *testing.T) {
+	townRoot := t.TempDir()
+	path := config.MailRulesPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rules := "[[rule]]\nname = \"loud\"\nto = [\"*/witness\"]\npriority = \"high\"\ndelivery = \"interrupt\"\n"
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	msg := NewMessage("mayor/", "gastown/witness", "Wake up", "")
+	out, result := r.applyMailRules(msg)
+	if out == msg {
+		t.Fatal("expected a modified copy")
+	}
+	if out.Priority != PriorityHigh || out.Delivery != DeliveryInterrupt {
+		t.Errorf("message = %+v", out)
+	}
+	if msg.Priority != PriorityNormal || msg.Delivery != "" {
+		t.Error("caller's message was modified")
+	}
+	if len(result.Matched) != 1 {
+		t.Errorf("matched = %v", result.Matched)
+	}
+
+	// A broken rules file delivers unfiltered.
+	if err := os.WriteFile(path, []byte("[[rule]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := r.applyMailRules(msg); out != msg {
+		t.Error("broken rules file should leave the message unchanged")
+	}
+}
+
+func TestLoadMailRulesCached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail-rules.toml")
+	rules := "[[rule]]\nname = \"loud\"\nsubject = \"^URGENT\"\npriority = \"high\"\n"
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := loadMailRulesCached(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := loadMailRulesCached(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Error("unchanged file should reuse the cached rules")
+	}
+	if !first.Rules[0].MatchSubject("URGENT: disk full") || first.Rules[0].MatchSubject("fyi") {
+		t.Error("cached rule subject pattern does not match as written")
+	}
+
+	// Same size, new modification time: reloaded.
+	rules = strings.Replace(rules, "high", "low\"#", 1)
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := loadMailRulesCached(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded == first || reloaded.Rules[0].Priority != "low" {
+		t.Errorf("changed file should be reloaded, got priority %q", reloaded.Rules[0].Priority)
+	}
+}
@@ -0,0 +1,346 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// scheduledMessage is a deferred message as stored on disk.
+type scheduledMessage struct {
+	Message *Message `json:"message"`
+	// SuppressNotify is not part of Message's JSON, but must survive the wait.
+	SuppressNotify bool      `json:"suppress_notify,omitempty"`
+	ScheduledAt    time.Time `json:"scheduled_at"`
+}
+
+// runtimeDir returns <townRoot>/.runtime/mail.
+func (r *Router) runtimeDir() string {
+	root := r.townRoot
+	if root == "" {
+		root = r.workDir
+	}
+	return filepath.Join(root, ".runtime", "mail")
+}
+
+// ScheduledDir returns the directory holding deferred messages, one JSON file
+// per message: <townRoot>/.runtime/mail/scheduled.
+func (r *Router) ScheduledDir() string {
+	return filepath.Join(r.runtimeDir(), "scheduled")
+}
+
+// expiryIndexPath is the index of pending message expiries,
+// <townRoot>/.runtime/mail/expiring.json: a sorted list of the times at
+// which sent messages expire, so ArchiveExpired only scans when one is due.
+func (r *Router) expiryIndexPath() string {
+	return filepath.Join(r.runtimeDir(), "expiring.json")
+}
+
+// schedule stores msg for release at msg.DeliverAt. The address is resolved
+// at release time, so lists, groups, queues and announces pick up their
+// membership as of delivery.
+func (r *Router) schedule(msg *Message) error {
+	if msg.ID == "" {
+		msg.ID = generateID()
+	}
+	if msg.To == "" {
+		return fmt.Errorf("invalid message: message must have a recipient")
+	}
+	if err := msg.Validate(); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	dir := r.ScheduledDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating scheduled mail dir: %w", err)
+	}
+	entry := scheduledMessage{Message: msg, SuppressNotify: msg.SuppressNotify, ScheduledAt: timeNow()}
+	if err := util.AtomicWriteJSON(filepath.Join(dir, msg.ID+".json"), entry); err != nil {
+		return fmt.Errorf("scheduling message: %w", err)
+	}
+	return nil
+}
+
+// ListScheduled returns the deferred messages, soonest first.
+func (r *Router) ListScheduled() ([]*Message, error) {
+	entries, err := r.loadScheduled()
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]*Message, 0, len(entries))
+	for _, e := range entries {
+		msgs = append(msgs, e.entry.Message)
+	}
+	return msgs, nil
+}
+
+// CancelScheduled removes a deferred message before it is delivered.
+func (r *Router) CancelScheduled(id string) error {
+	unlock, err := r.lockScheduled()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := filepath.Join(r.ScheduledDir(), filepath.Base(id)+".json")
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return ErrMessageNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ReleaseResult summarizes a ReleaseScheduled pass.
+type ReleaseResult struct {
+	Released []*Message `json:"released,omitempty"`
+	// Expired lists messages that expired before they were delivered; they
+	// are dropped without being sent.
+	Expired []*Message `json:"expired,omitempty"`
+}
+
+// ReleaseScheduled sends every deferred message due at now and removes it
+// from the store. A message whose send fails stays scheduled and is retried
+// on the next pass; the failures are returned joined.
+func (r *Router) ReleaseScheduled(now time.Time) (*ReleaseResult, error) {
+	unlock, err := r.lockScheduled()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := r.loadScheduled()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReleaseResult{}
+	var errs []error
+	for _, e := range entries {
+		msg := e.entry.Message
+		if msg.IsDeferred(now) {
+			break // sorted by DeliverAt: nothing later is due
+		}
+		if msg.IsExpired(now) {
+			result.Expired = append(result.Expired, msg)
+			_ = os.Remove(e.path)
+			continue
+		}
+
+		out := *msg
+		out.DeliverAt = nil
+		out.SuppressNotify = e.entry.SuppressNotify
+		if err := r.Send(&out); err != nil {
+			errs = append(errs, fmt.Errorf("%s to %s: %w", msg.ID, msg.To, err))
+			continue
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("%s: sent, but removing from schedule: %w", msg.ID, err))
+		}
+		result.Released = append(result.Released, msg)
+	}
+	return result, errors.Join(errs...)
+}
+
+type scheduledFile struct {
+	path  string
+	entry scheduledMessage
+}
+
+// loadScheduled reads the scheduled store, sorted by delivery time.
+// Unreadable entries are skipped.
+func (r *Router) loadScheduled() ([]scheduledFile, error) {
+	dir := r.ScheduledDir()
+	names, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading scheduled mail: %w", err)
+	}
+
+	var out []scheduledFile
+	for _, n := range names {
+		if n.IsDir() || !strings.HasSuffix(n.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, n.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry scheduledMessage
+		if err := json.Unmarshal(data, &entry); err != nil || entry.Message == nil {
+			continue
+		}
+		out = append(out, scheduledFile{path: path, entry: entry})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return deliverAt(out[i].entry.Message).Before(deliverAt(out[j].entry.Message))
+	})
+	return out, nil
+}
+
+func deliverAt(m *Message) time.Time {
+	if m.DeliverAt == nil {
+		return time.Time{}
+	}
+	return *m.DeliverAt
+}
+
+// lockScheduled serializes release and cancel so a message is never sent twice.
+func (r *Router) lockScheduled() (func(), error) {
+	dir := r.ScheduledDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating scheduled mail dir: %w", err)
+	}
+	fl := flock.New(filepath.Join(dir, ".lock"))
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("locking scheduled mail: %w", err)
+	}
+	return func() { _ = fl.Unlock() }, nil
+}
+
+// noteExpiry records that a message expiring at exp is being sent.
+func (r *Router) noteExpiry(exp time.Time) error {
+	return r.updateExpiryIndex(func(times []time.Time) []time.Time {
+		return append(times, exp)
+	})
+}
+
+// updateExpiryIndex rewrites the expiry index under its lock. update gets
+// the current times (nil when there is no index yet).
+func (r *Router) updateExpiryIndex(update func([]time.Time) []time.Time) error {
+	dir := r.runtimeDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating mail runtime dir: %w", err)
+	}
+	fl := flock.New(r.expiryIndexPath() + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking expiry index: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	times, _, err := r.loadExpiryIndex()
+	if err != nil {
+		return err
+	}
+	times = update(times)
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	if times == nil {
+		times = []time.Time{}
+	}
+	return util.AtomicWriteJSON(r.expiryIndexPath(), times)
+}
+
+// loadExpiryIndex reads the expiry index. ok is false when there is none
+// (a town that predates it, or whose index was removed).
+func (r *Router) loadExpiryIndex() (times []time.Time, ok bool, err error) {
+	data, err := os.ReadFile(r.expiryIndexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("reading expiry index: %w", err)
+	}
+	if err := json.Unmarshal(data, &times); err != nil {
+		// Rebuilt by the next scan.
+		return nil, false, nil
+	}
+	return times, true, nil
+}
+
+// ArchiveExpired closes every open message whose expiry has passed, so stale
+// notices drop out of inboxes, queues and announce channels. Returns the IDs
+// archived.
+//
+// Mail is only scanned when the expiry index says a message is due (or
+// there is no index yet), so a town without expiring mail costs nothing.
+// The scan rebuilds the index from the open messages' expiries.
+func (r *Router) ArchiveExpired(now time.Time) ([]string, error) {
+	times, ok, err := r.loadExpiryIndex()
+	if err != nil {
+		return nil, err
+	}
+	if ok && (len(times) == 0 || times[0].After(now)) {
+		return nil, nil
+	}
+
+	beadsDir := r.resolveBeadsDir()
+	args := []string{"list",
+		"--label", "gt:message",
+		"--status", "open",
+		"--json",
+		"--limit", "0",
+	}
+	ctx, cancel := bdReadCtx()
+	stdout, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	var msgs []BeadsMessage
+	if len(stdout) > 0 && string(stdout) != "null" {
+		if err := json.Unmarshal(stdout, &msgs); err != nil {
+			return nil, fmt.Errorf("parsing messages: %w", err)
+		}
+	}
+
+	var archived []string
+	var errs []error
+	var pending []time.Time
+	for _, id := range expiredMessageIDs(msgs, now) {
+		ctx, cancel := bdWriteCtx()
+		_, err := runBdCommand(ctx, []string{"close", id, "--reason=expired"}, filepath.Dir(beadsDir), beadsDir)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			// Still due: retry on the next pass.
+			pending = append(pending, now)
+			continue
+		}
+		archived = append(archived, id)
+	}
+	for i := range msgs {
+		if exp := msgs[i].GetExpiresAt(); exp != nil && exp.After(now) {
+			pending = append(pending, *exp)
+		}
+	}
+
+	// Keep future expiries already indexed, including any noted during the
+	// scan; everything due by now was covered by it.
+	if err := r.updateExpiryIndex(func(times []time.Time) []time.Time {
+		for _, t := range times {
+			if t.After(now) && !slices.ContainsFunc(pending, t.Equal) {
+				pending = append(pending, t)
+			}
+		}
+		return pending
+	}); err != nil {
+		errs = append(errs, err)
+	}
+	return archived, errors.Join(errs...)
+}
+
+// expiredMessageIDs returns the IDs of messages whose expires-at label is at
+// or before now.
+func expiredMessageIDs(msgs []BeadsMessage, now time.Time) []string {
+	var ids []string
+	for i := range msgs {
+		msgs[i].ParseLabels()
+		if exp := msgs[i].GetExpiresAt(); exp != nil && !exp.After(now) {
+			ids = append(ids, msgs[i].ID)
+		}
+	}
+	return ids
+}
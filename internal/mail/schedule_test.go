@@ -0,0 +1,241 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newScheduleRouter(t *testing.T) *Router {
+	t.Helper()
+	townRoot := t.TempDir()
+	return NewRouterWithTownRoot(townRoot, townRoot)
+}
+
+func scheduledMsg(to string, deliverAt time.Time) *Message {
+	msg := NewMessage("mayor/", to, "Standup", "Status?")
+	msg.DeliverAt = &deliverAt
+	return msg
+}
+
+func TestSend_DeferredIsScheduled(t *testing.T) {
+	r := newScheduleRouter(t)
+	later := time.Now().Add(time.Hour).Truncate(time.Second)
+	msg := scheduledMsg("gastown/witness", later)
+	msg.SuppressNotify = true
+
+	// No bd is needed: deferred messages never reach beads at send time.
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	msgs, err := r.ListScheduled()
+	if err != nil {
+		t.Fatalf("ListScheduled: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].ID != msg.ID || !msgs[0].DeliverAt.Equal(later) {
+		t.Fatalf("scheduled = %+v", msgs)
+	}
+	entries, _ := r.loadScheduled()
+	if !entries[0].entry.SuppressNotify {
+		t.Error("SuppressNotify was not kept with the scheduled message")
+	}
+}
+
+func TestSend_Expired(t *testing.T) {
+	r := newScheduleRouter(t)
+	msg := NewMessage("mayor/", "gastown/witness", "Merge ready", "gt-abc")
+	past := time.Now().Add(-time.Minute)
+	msg.ExpiresAt = &past
+
+	if err := r.Send(msg); !errors.Is(err, ErrMessageExpired) {
+		t.Fatalf("Send = %v, want ErrMessageExpired", err)
+	}
+}
+
+func TestSend_ExpiresBeforeDelivery(t *testing.T) {
+	r := newScheduleRouter(t)
+	msg := scheduledMsg("gastown/witness", time.Now().Add(2*time.Hour))
+	exp := time.Now().Add(time.Hour)
+	msg.ExpiresAt = &exp
+
+	if err := r.Send(msg); err == nil {
+		t.Fatal("expected error for a message expiring before delivery")
+	}
+	if msgs, _ := r.ListScheduled(); len(msgs) != 0 {
+		t.Errorf("invalid message was scheduled: %+v", msgs)
+	}
+}
+
+func TestListScheduled_Order(t *testing.T) {
+	r := newScheduleRouter(t)
+	now := time.Now()
+	for _, d := range []time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour} {
+		if err := r.Send(scheduledMsg("gastown/witness", now.Add(d))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	msgs, err := r.ListScheduled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(msgs); i++ {
+		if msgs[i].DeliverAt.Before(*msgs[i-1].DeliverAt) {
+			t.Fatalf("not sorted by delivery time: %v before %v", msgs[i-1].DeliverAt, msgs[i].DeliverAt)
+		}
+	}
+}
+
+func TestCancelScheduled(t *testing.T) {
+	r := newScheduleRouter(t)
+	msg := scheduledMsg("gastown/witness", time.Now().Add(time.Hour))
+	if err := r.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.CancelScheduled(msg.ID); err != nil {
+		t.Fatalf("CancelScheduled: %v", err)
+	}
+	if msgs, _ := r.ListScheduled(); len(msgs) != 0 {
+		t.Errorf("message still scheduled: %+v", msgs)
+	}
+	if err := r.CancelScheduled(msg.ID); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("second cancel = %v, want ErrMessageNotFound", err)
+	}
+}
+
+func TestReleaseScheduled(t *testing.T) {
+	r := newScheduleRouter(t)
+	now := time.Now()
+
+	notDue := scheduledMsg("gastown/witness", now.Add(time.Hour))
+	expired := scheduledMsg("gastown/witness", now.Add(time.Minute))
+	exp := now.Add(2 * time.Minute)
+	expired.ExpiresAt = &exp
+	// Due, but the queue doesn't exist, so the send fails and it stays.
+	failing := scheduledMsg("queue:nope", now.Add(time.Minute))
+	for _, m := range []*Message{notDue, expired, failing} {
+		if err := r.Send(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := r.ReleaseScheduled(now.Add(5 * time.Minute))
+	if err == nil {
+		t.Error("expected the failed send to be reported")
+	}
+	if len(result.Released) != 0 {
+		t.Errorf("released = %+v", result.Released)
+	}
+	if len(result.Expired) != 1 || result.Expired[0].ID != expired.ID {
+		t.Errorf("expired = %+v", result.Expired)
+	}
+
+	msgs, _ := r.ListScheduled()
+	ids := map[string]bool{}
+	for _, m := range msgs {
+		ids[m.ID] = true
+	}
+	if len(msgs) != 2 || !ids[failing.ID] || !ids[notDue.ID] {
+		t.Errorf("still scheduled = %v, want the failed and not-yet-due messages", ids)
+	}
+}
+
+func TestReleaseScheduled_SkipsCorruptEntries(t *testing.T) {
+	r := newScheduleRouter(t)
+	dir := r.ScheduledDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := r.ReleaseScheduled(time.Now())
+	if err != nil || len(result.Released)+len(result.Expired) != 0 {
+		t.Errorf("result = %+v, err = %v", result, err)
+	}
+}
+
+func TestSend_NotesExpiry(t *testing.T) {
+	r := newScheduleRouter(t)
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	msg := NewMessage("mayor/", "queue:nope", "Merge ready", "gt-abc")
+	msg.ExpiresAt = &exp
+
+	// The queue doesn't exist, but the expiry is indexed before delivery.
+	_ = r.Send(msg)
+
+	times, ok, err := r.loadExpiryIndex()
+	if err != nil || !ok {
+		t.Fatalf("loadExpiryIndex: ok=%v err=%v", ok, err)
+	}
+	if len(times) != 1 || !times[0].Equal(exp) {
+		t.Errorf("indexed expiries = %v, want [%v]", times, exp)
+	}
+}
+
+func TestArchiveExpired_SkipsScanUntilDue(t *testing.T) {
+	r := newScheduleRouter(t)
+	now := time.Now()
+
+	// An empty index (no expiring mail) and one whose next expiry is still
+	// ahead both skip the scan; no bd is needed.
+	if err := r.updateExpiryIndex(func([]time.Time) []time.Time { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if ids, err := r.ArchiveExpired(now); err != nil || len(ids) != 0 {
+		t.Errorf("empty index: ArchiveExpired = %v, %v", ids, err)
+	}
+	if err := r.noteExpiry(now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if ids, err := r.ArchiveExpired(now); err != nil || len(ids) != 0 {
+		t.Errorf("future expiry: ArchiveExpired = %v, %v", ids, err)
+	}
+}
+
+func TestExpiredMessageIDs(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	msgs := []BeadsMessage{
+		{ID: "hq-old", Labels: []string{"from:mayor/", ExpiryLabel(now.Add(-time.Hour))}},
+		{ID: "hq-now", Labels: []string{ExpiryLabel(now)}},
+		{ID: "hq-later", Labels: []string{ExpiryLabel(now.Add(time.Hour))}},
+		{ID: "hq-never", Labels: []string{"from:mayor/"}},
+	}
+
+	got := expiredMessageIDs(msgs, now)
+	if len(got) != 2 || got[0] != "hq-old" || got[1] != "hq-now" {
+		t.Errorf("expiredMessageIDs = %v, want [hq-old hq-now]", got)
+	}
+}
+
+func TestMessageDeferredExpired(t *testing.T) {
+	now := time.Now()
+	before, after := now.Add(-time.Minute), now.Add(time.Minute)
+
+	msg := NewMessage("mayor/", "gastown/witness", "s", "b")
+	if msg.IsDeferred(now) || msg.IsExpired(now) {
+		t.Error("plain message should be neither deferred nor expired")
+	}
+	msg.DeliverAt, msg.ExpiresAt = &after, &before
+	if !msg.IsDeferred(now) || !msg.IsExpired(now) {
+		t.Error("expected deferred and expired")
+	}
+	msg.DeliverAt, msg.ExpiresAt = &before, &now
+	if msg.IsDeferred(now) || !msg.IsExpired(now) {
+		t.Error("expiry at now should count as expired; past delivery is not deferred")
+	}
+}
+
+func TestBeadsMessageExpiresAtLabel(t *testing.T) {
+	exp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bm := BeadsMessage{ID: "hq-1", Title: "Merge ready", Labels: []string{"from:refinery", ExpiryLabel(exp)}}
+
+	msg := bm.ToMessage()
+	if msg.ExpiresAt == nil || !msg.ExpiresAt.Equal(exp) {
+		t.Errorf("ExpiresAt = %v, want %v", msg.ExpiresAt, exp)
+	}
+}
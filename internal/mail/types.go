@@ -130,10 +130,22 @@ type Message struct {
 	// DeliveryAckedAt is when receipt was acknowledged.
 	DeliveryAckedAt *time.Time `json:"delivery_acked_at,omitempty"`
 
+	// DeliverAt defers delivery: Router.Send holds the message in the
+	// scheduled store until the daemon releases it at this time.
+	DeliverAt *time.Time `json:"deliver_at,omitempty"`
+
+	// ExpiresAt is when the message goes stale. Expired messages are hidden
+	// from inboxes and archived by the daemon.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
 	// SuppressNotify tells the router to skip all recipient notification
 	// (no nudge, no banner). Set by the CLI when --no-notify is passed.
 	// In-memory only — not serialized.
 	SuppressNotify bool `json:"-"`
+
+	// forwarded marks a copy sent by a mail rule's forward action, so
+	// rules don't forward it again.
+	forwarded bool
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -218,6 +230,16 @@ func (m *Message) IsDirectMessage() bool {
 	return m.Queue == "" && m.Channel == "" && m.To != ""
 }
 
+// IsDeferred returns true if the message is scheduled for later delivery.
+func (m *Message) IsDeferred(now time.Time) bool {
+	return m.DeliverAt != nil && m.DeliverAt.After(now)
+}
+
+// IsExpired returns true if the message has an expiry that has passed.
+func (m *Message) IsExpired(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(now)
+}
+
 // IsClaimed returns true if this queue message has been claimed.
 func (m *Message) IsClaimed() bool {
 	return m.ClaimedBy != ""
@@ -264,6 +286,10 @@ func (m *Message) Validate() error {
 		return fmt.Errorf("claimed_at is only valid for queue messages")
 	}
 
+	if m.DeliverAt != nil && m.ExpiresAt != nil && !m.ExpiresAt.After(*m.DeliverAt) {
+		return fmt.Errorf("message would expire before it is delivered")
+	}
+
 	return nil
 }
 
@@ -299,7 +325,7 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X, expires-at:X)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (not synced to git)
 
@@ -313,6 +339,7 @@ type BeadsMessage struct {
 	channel   string     // Channel name (for broadcast messages)
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
+	expiresAt *time.Time // When the message goes stale
 	// Two-phase delivery metadata
 	deliveryState   string
 	deliveryAckedBy string
@@ -331,6 +358,7 @@ func (bm *BeadsMessage) ParseLabels() {
 	bm.channel = ""
 	bm.claimedBy = ""
 	bm.claimedAt = nil
+	bm.expiresAt = nil
 	bm.deliveryState = ""
 	bm.deliveryAckedBy = ""
 	bm.deliveryAckedAt = nil
@@ -357,6 +385,10 @@ func (bm *BeadsMessage) ParseLabels() {
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
 				bm.claimedAt = &t
 			}
+		} else if strings.HasPrefix(label, "expires-at:") {
+			if t, err := time.Parse(time.RFC3339, strings.TrimPrefix(label, "expires-at:")); err == nil {
+				bm.expiresAt = &t
+			}
 		}
 	}
 
@@ -427,12 +459,23 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		Channel:         bm.channel,
 		ClaimedBy:       bm.claimedBy,
 		ClaimedAt:       bm.claimedAt,
+		ExpiresAt:       bm.expiresAt,
 		DeliveryState:   bm.deliveryState,
 		DeliveryAckedBy: bm.deliveryAckedBy,
 		DeliveryAckedAt: bm.deliveryAckedAt,
 	}
 }
 
+// GetExpiresAt returns when the message expires, or nil if it never does.
+func (bm *BeadsMessage) GetExpiresAt() *time.Time {
+	return bm.expiresAt
+}
+
+// ExpiryLabel returns the expires-at label recording t.
+func ExpiryLabel(t time.Time) string {
+	return "expires-at:" + t.UTC().Format(time.RFC3339)
+}
+
 // GetQueue returns the queue name for queue messages.
 func (bm *BeadsMessage) GetQueue() string {
 	return bm.queue
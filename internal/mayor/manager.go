@@ -48,7 +48,7 @@ func (m *Manager) mayorDir() string {
 // Start starts the mayor session.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) Start(agentOverride string) error {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName()
 
 	// Kill any existing zombie session (tmux alive but agent dead).
@@ -95,7 +95,7 @@ func (m *Manager) Start(agentOverride string) error {
 
 // Stop stops the mayor session.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName()
 
 	// Check if session exists
@@ -121,7 +121,7 @@ func (m *Manager) Stop() error {
 
 // IsRunning checks if the mayor session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	return t.HasSession(m.SessionName())
 }
 
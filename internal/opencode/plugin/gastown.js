@@ -44,11 +44,10 @@ export const GasTown = async ({ $, directory }) => {
         // Reset so next system.transform gets fresh context.
         primePromise = loadPrime();
       }
-      if (event?.type === "session.deleted") {
-        const sessionID = event.properties?.info?.id;
-        if (sessionID) {
-          await $`gt costs record --session ${sessionID}`.catch(() => {});
-        }
+      if (event?.type === "session.idle" || event?.type === "session.deleted") {
+        // gt derives the Gas Town session from GT_* env; the OpenCode
+        // session ID is not a Gas Town session name.
+        await $`gt costs record`.cwd(directory).quiet().catch(() => {});
       }
     },
     "experimental.chat.system.transform": async (input, output) => {
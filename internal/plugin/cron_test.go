@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+	}
+	for _, expr := range tests {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected error, got nil", expr)
+		}
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Wednesday, 2026-01-14 10:30 UTC
+	base := time.Date(2026, 1, 14, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", base, time.Date(2026, 1, 14, 10, 31, 0, 0, time.UTC)},
+		{"0 9 * * *", base, time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"0 11 * * *", base, time.Date(2026, 1, 14, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", base, time.Date(2026, 1, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", base, time.Date(2026, 1, 14, 13, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", base, time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", base, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", base, time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", base, time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1-5", base, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 3 *", base, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", base, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", base, time.Date(2026, 1, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", base, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", base, time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", base, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either may match (the 20th, or any Monday).
+		{"0 0 20 * 1", base, time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		sched, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error: %v", tt.expr, err)
+		}
+		if got := sched.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next(%v) = %v, want %v", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestCronSchedule_NextImpossible(t *testing.T) {
+	sched, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron error: %v", err)
+	}
+	if got := sched.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time for impossible date", got)
+	}
+}
@@ -0,0 +1,422 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultConditionTimeout bounds how long a condition gate's check command
+// may run. A check that times out is treated as a closed gate.
+const DefaultConditionTimeout = 30 * time.Second
+
+// EventStartup is the pseudo-event matched once per daemon start by event
+// gates with on = "startup".
+const EventStartup = "startup"
+
+// GateState is the per-plugin gate bookkeeping persisted between evaluations.
+// Stored in <townRoot>/.runtime/plugin-gates.json keyed by plugin name.
+type GateState struct {
+	// Schedule is the cron expression NextFire was computed from.
+	// A changed schedule invalidates NextFire.
+	Schedule string `json:"schedule,omitempty"`
+
+	// NextFire is when a cron gate next opens.
+	NextFire time.Time `json:"next_fire,omitempty"`
+
+	// EventOffset is the byte offset into .events.jsonl up to which
+	// events have been consumed by this plugin's event gate.
+	EventOffset int64 `json:"event_offset,omitempty"`
+
+	// EventWatching is set once EventOffset has been initialized.
+	EventWatching bool `json:"event_watching,omitempty"`
+
+	// LastFired is when the plugin was last dispatched through its gate.
+	LastFired time.Time `json:"last_fired,omitempty"`
+}
+
+// gateStateFile returns the path to the plugin gate state file.
+func gateStateFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "plugin-gates.json")
+}
+
+// loadGateStates reads all persisted gate states.
+// Returns an empty map if the file doesn't exist.
+func (r *Recorder) loadGateStates() (map[string]*GateState, error) {
+	data, err := os.ReadFile(gateStateFile(r.townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]*GateState{}, nil
+		}
+		return nil, fmt.Errorf("reading gate state: %w", err)
+	}
+
+	states := map[string]*GateState{}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("parsing gate state: %w", err)
+	}
+	return states, nil
+}
+
+// GetGateState returns the persisted gate state for a plugin.
+// Returns a zero-value state if none has been recorded yet.
+func (r *Recorder) GetGateState(pluginName string) (*GateState, error) {
+	states, err := r.loadGateStates()
+	if err != nil {
+		return nil, err
+	}
+	if st, ok := states[pluginName]; ok && st != nil {
+		return st, nil
+	}
+	return &GateState{}, nil
+}
+
+// SaveGateState persists the gate state for a plugin.
+func (r *Recorder) SaveGateState(pluginName string, state *GateState) error {
+	states, err := r.loadGateStates()
+	if err != nil {
+		return err
+	}
+	states[pluginName] = state
+	if err := util.EnsureDirAndWriteJSON(gateStateFile(r.townRoot), states); err != nil {
+		return fmt.Errorf("writing gate state: %w", err)
+	}
+	return nil
+}
+
+// GateDecision is the outcome of evaluating a plugin's gate.
+type GateDecision struct {
+	// Open is true if the plugin should be dispatched now.
+	Open bool
+
+	// Reason explains the decision (for logs and CLI output).
+	Reason string
+
+	// pending is the state to persist once the plugin is actually
+	// dispatched. Nil when dispatch requires no state change.
+	pending *GateState
+}
+
+// GateEvaluator decides whether plugins are due to run.
+//
+// Evaluation is side-effect free for open gates: cron next-fire times and
+// event offsets only advance when Commit is called after a successful
+// dispatch, so a plugin deferred for lack of an idle dog stays due.
+type GateEvaluator struct {
+	townRoot string
+	recorder *Recorder
+
+	// StartedAt is when the evaluating process started. Event gates on
+	// "startup" fire once for each start.
+	StartedAt time.Time
+
+	// ConditionTimeout bounds condition gate check commands.
+	ConditionTimeout time.Duration
+
+	// Now returns the current time (overridable for tests).
+	Now func() time.Time
+}
+
+// NewGateEvaluator creates a gate evaluator for the given town.
+func NewGateEvaluator(townRoot string, recorder *Recorder) *GateEvaluator {
+	return &GateEvaluator{
+		townRoot:         townRoot,
+		recorder:         recorder,
+		StartedAt:        time.Now(),
+		ConditionTimeout: DefaultConditionTimeout,
+		Now:              time.Now,
+	}
+}
+
+// Evaluate decides whether the plugin's gate is open.
+// Plugins without a gate, or with a manual gate, are never auto-dispatched.
+func (e *GateEvaluator) Evaluate(p *Plugin) (*GateDecision, error) {
+	if p.Gate == nil {
+		return &GateDecision{Reason: "manual (no gate)"}, nil
+	}
+
+	switch p.Gate.Type {
+	case GateCooldown:
+		return e.evaluateCooldown(p)
+	case GateCron:
+		return e.evaluateCron(p)
+	case GateCondition:
+		return e.evaluateCondition(p)
+	case GateEvent:
+		return e.evaluateEvent(p)
+	case GateManual, "":
+		return &GateDecision{Reason: "manual gate"}, nil
+	default:
+		return nil, fmt.Errorf("unknown gate type %q", p.Gate.Type)
+	}
+}
+
+// Commit records that the plugin was dispatched, advancing cron and event
+// bookkeeping so the same trigger does not fire twice.
+func (e *GateEvaluator) Commit(p *Plugin, decision *GateDecision) error {
+	if decision == nil || decision.pending == nil {
+		return nil
+	}
+	decision.pending.LastFired = e.Now()
+	return e.recorder.SaveGateState(p.Name, decision.pending)
+}
+
+// evaluateCooldown opens the gate if the plugin has not run within Duration.
+func (e *GateEvaluator) evaluateCooldown(p *Plugin) (*GateDecision, error) {
+	if p.Gate.Duration == "" {
+		return &GateDecision{Open: true, Reason: "no cooldown configured"}, nil
+	}
+	return e.checkCooldown(p, &GateDecision{Open: true, Reason: "cooldown elapsed"})
+}
+
+// checkCooldown closes an otherwise-open decision if the plugin ran within
+// the gate's Duration. Cron, condition and event gates honor an optional
+// duration this way to rate-limit noisy triggers.
+func (e *GateEvaluator) checkCooldown(p *Plugin, open *GateDecision) (*GateDecision, error) {
+	if p.Gate.Duration == "" {
+		return open, nil
+	}
+	count, err := e.recorder.CountRunsSince(p.Name, p.Gate.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("checking cooldown: %w", err)
+	}
+	if count > 0 {
+		return &GateDecision{
+			Reason: fmt.Sprintf("ran %d time(s) within %s cooldown", count, p.Gate.Duration),
+		}, nil
+	}
+	return open, nil
+}
+
+// evaluateCron opens the gate once the persisted next-fire time has passed.
+// On first sight of a plugin (or after its schedule changes) the next-fire
+// time is initialized and persisted without firing, so adding a plugin
+// does not trigger a spurious immediate run.
+func (e *GateEvaluator) evaluateCron(p *Plugin) (*GateDecision, error) {
+	sched, err := ParseCron(p.Gate.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := e.recorder.GetGateState(p.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := e.Now()
+	if state.NextFire.IsZero() || state.Schedule != p.Gate.Schedule {
+		state.Schedule = p.Gate.Schedule
+		state.NextFire = sched.Next(now)
+		if err := e.recorder.SaveGateState(p.Name, state); err != nil {
+			return nil, err
+		}
+		return &GateDecision{Reason: fmt.Sprintf("scheduled for %s", state.NextFire.Format(time.RFC3339))}, nil
+	}
+
+	if now.Before(state.NextFire) {
+		return &GateDecision{Reason: fmt.Sprintf("next run at %s", state.NextFire.Format(time.RFC3339))}, nil
+	}
+
+	pending := *state
+	pending.NextFire = sched.Next(now)
+	return e.checkCooldown(p, &GateDecision{
+		Open:    true,
+		Reason:  fmt.Sprintf("schedule %q due since %s", p.Gate.Schedule, state.NextFire.Format(time.RFC3339)),
+		pending: &pending,
+	})
+}
+
+// evaluateCondition runs the gate's check command in the plugin directory.
+// Exit 0 opens the gate; any other exit status, or a timeout, keeps it closed.
+func (e *GateEvaluator) evaluateCondition(p *Plugin) (*GateDecision, error) {
+	if strings.TrimSpace(p.Gate.Check) == "" {
+		return nil, fmt.Errorf("condition gate has no check command")
+	}
+
+	timeout := e.ConditionTimeout
+	if timeout <= 0 {
+		timeout = DefaultConditionTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.Gate.Check) //nolint:gosec // G204: check command comes from trusted plugin.md
+	cmd.Dir = p.Path
+	cmd.Env = append(os.Environ(),
+		"GT_TOWN_ROOT="+e.townRoot,
+		"GT_PLUGIN="+p.Name,
+	)
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return &GateDecision{Reason: fmt.Sprintf("check timed out after %v", timeout)}, nil
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &GateDecision{Reason: fmt.Sprintf("check exited %d", exitErr.ExitCode())}, nil
+		}
+		return nil, fmt.Errorf("running check command: %w", err)
+	}
+
+	return e.checkCooldown(p, &GateDecision{Open: true, Reason: "check passed"})
+}
+
+// evaluateEvent opens the gate when an event whose type is listed in On
+// (comma-separated) has been appended to .events.jsonl since the last
+// dispatch. The special "startup" event matches once per evaluator start.
+//
+// On first sight the offset is initialized to the end of the log so that
+// historical events do not fire a newly added plugin.
+func (e *GateEvaluator) evaluateEvent(p *Plugin) (*GateDecision, error) {
+	wanted := parseEventTypes(p.Gate.On)
+	if len(wanted) == 0 {
+		return nil, fmt.Errorf("event gate has no events configured")
+	}
+
+	state, err := e.recorder.GetGateState(p.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if wanted[EventStartup] && state.LastFired.Before(e.StartedAt) {
+		pending := *state
+		if end, err := e.eventsLogSize(); err == nil {
+			pending.EventOffset = end
+			pending.EventWatching = true
+		}
+		return e.checkCooldown(p, &GateDecision{Open: true, Reason: "startup", pending: &pending})
+	}
+
+	eventsPath := filepath.Join(e.townRoot, events.EventsFile)
+	var size int64
+	info, err := os.Stat(eventsPath)
+	switch {
+	case err == nil:
+		size = info.Size()
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("checking events log: %w", err)
+	}
+
+	if !state.EventWatching {
+		state.EventWatching = true
+		state.EventOffset = size
+		if err := e.recorder.SaveGateState(p.Name, state); err != nil {
+			return nil, err
+		}
+		return &GateDecision{Reason: "watching for " + p.Gate.On}, nil
+	}
+	if info == nil {
+		return &GateDecision{Reason: "no events yet"}, nil
+	}
+
+	offset := state.EventOffset
+	var notBefore time.Time
+	if size < offset {
+		// The log was rewritten (e.g., pruned by krc). Rescan from the
+		// start but ignore anything that predates the last dispatch.
+		offset = 0
+		notBefore = state.LastFired
+	}
+
+	match, end, err := scanEvents(eventsPath, offset, wanted, notBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := *state
+	pending.EventOffset = end
+	if match == "" {
+		// Nothing relevant — consume the scanned range now so the next
+		// evaluation doesn't rescan it.
+		if end != state.EventOffset {
+			if err := e.recorder.SaveGateState(p.Name, &pending); err != nil {
+				return nil, err
+			}
+		}
+		return &GateDecision{Reason: "no matching events"}, nil
+	}
+
+	return e.checkCooldown(p, &GateDecision{
+		Open:    true,
+		Reason:  fmt.Sprintf("event %s", match),
+		pending: &pending,
+	})
+}
+
+// eventsLogSize returns the current size of the town events log.
+func (e *GateEvaluator) eventsLogSize() (int64, error) {
+	info, err := os.Stat(filepath.Join(e.townRoot, events.EventsFile))
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// parseEventTypes splits a comma-separated On value into a lookup set.
+func parseEventTypes(on string) map[string]bool {
+	wanted := make(map[string]bool)
+	for _, t := range strings.Split(on, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			wanted[t] = true
+		}
+	}
+	return wanted
+}
+
+// scanEvents reads .events.jsonl from offset and returns the first event type
+// in wanted (or "" if none), along with the offset of the last complete line
+// read. Events timestamped before notBefore are ignored. A trailing partial
+// line (a writer mid-append) is left for the next scan.
+func scanEvents(path string, offset int64, wanted map[string]bool, notBefore time.Time) (string, int64, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return "", offset, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return "", offset, fmt.Errorf("seeking events log: %w", err)
+	}
+
+	reader := bufio.NewReader(f)
+	pos := offset
+	match := ""
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", offset, fmt.Errorf("reading events log: %w", err)
+		}
+		pos += int64(len(line))
+		if match != "" {
+			continue
+		}
+
+		var ev events.Event
+		if json.Unmarshal(line, &ev) != nil || !wanted[ev.Type] {
+			continue
+		}
+		if !notBefore.IsZero() {
+			ts, err := time.Parse(time.RFC3339, ev.Timestamp)
+			if err != nil || ts.Before(notBefore) {
+				continue
+			}
+		}
+		match = ev.Type
+	}
+	return match, pos, nil
+}
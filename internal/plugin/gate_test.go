@@ -0,0 +1,302 @@
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// testGatePlugin creates a plugin with the given gate in a temp directory.
+func testGatePlugin(t *testing.T, gate *Gate) *Plugin {
+	t.Helper()
+	return &Plugin{Name: "test-plugin", Path: t.TempDir(), Gate: gate}
+}
+
+// testAppendEvent appends an event line to the town's .events.jsonl.
+func testAppendEvent(t *testing.T, townRoot, eventType string, ts time.Time) {
+	t.Helper()
+	data, err := json.Marshal(events.Event{
+		Timestamp: ts.UTC().Format(time.RFC3339),
+		Source:    "gt",
+		Type:      eventType,
+		Actor:     "test",
+	})
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	f, err := os.OpenFile(filepath.Join(townRoot, events.EventsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open events file: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		t.Fatalf("write event: %v", err)
+	}
+}
+
+func TestGateState_RoundTrip(t *testing.T) {
+	r := NewRecorder(t.TempDir())
+
+	st, err := r.GetGateState("missing")
+	if err != nil {
+		t.Fatalf("GetGateState error: %v", err)
+	}
+	if !st.NextFire.IsZero() || st.EventOffset != 0 {
+		t.Errorf("expected zero state, got %+v", st)
+	}
+
+	next := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	if err := r.SaveGateState("a", &GateState{Schedule: "0 9 * * *", NextFire: next}); err != nil {
+		t.Fatalf("SaveGateState error: %v", err)
+	}
+	if err := r.SaveGateState("b", &GateState{EventOffset: 42, EventWatching: true}); err != nil {
+		t.Fatalf("SaveGateState error: %v", err)
+	}
+
+	a, _ := r.GetGateState("a")
+	if !a.NextFire.Equal(next) || a.Schedule != "0 9 * * *" {
+		t.Errorf("state a = %+v", a)
+	}
+	b, _ := r.GetGateState("b")
+	if b.EventOffset != 42 || !b.EventWatching {
+		t.Errorf("state b = %+v", b)
+	}
+}
+
+func TestGateEvaluator_ManualNeverOpens(t *testing.T) {
+	e := NewGateEvaluator(t.TempDir(), NewRecorder(t.TempDir()))
+	for _, gate := range []*Gate{nil, {Type: GateManual}} {
+		d, err := e.Evaluate(&Plugin{Name: "m", Gate: gate})
+		if err != nil {
+			t.Fatalf("Evaluate error: %v", err)
+		}
+		if d.Open {
+			t.Errorf("manual gate %+v should never open", gate)
+		}
+	}
+}
+
+func TestGateEvaluator_UnknownType(t *testing.T) {
+	e := NewGateEvaluator(t.TempDir(), NewRecorder(t.TempDir()))
+	if _, err := e.Evaluate(&Plugin{Name: "x", Gate: &Gate{Type: "bogus"}}); err == nil {
+		t.Error("expected error for unknown gate type")
+	}
+}
+
+func TestGateEvaluator_Cron(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRecorder(townRoot)
+	e := NewGateEvaluator(townRoot, r)
+
+	now := time.Date(2026, 1, 14, 8, 0, 0, 0, time.UTC)
+	e.Now = func() time.Time { return now }
+	p := testGatePlugin(t, &Gate{Type: GateCron, Schedule: "0 9 * * *"})
+
+	// First sight initializes next-fire without firing.
+	d, err := e.Evaluate(p)
+	if err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+	if d.Open {
+		t.Fatal("cron gate should not fire on first sight")
+	}
+	st, _ := r.GetGateState(p.Name)
+	wantNext := time.Date(2026, 1, 14, 9, 0, 0, 0, time.UTC)
+	if !st.NextFire.Equal(wantNext) {
+		t.Fatalf("NextFire = %v, want %v", st.NextFire, wantNext)
+	}
+
+	// Before the scheduled time: closed.
+	now = wantNext.Add(-time.Minute)
+	if d, _ = e.Evaluate(p); d.Open {
+		t.Error("cron gate should be closed before next fire")
+	}
+
+	// After the scheduled time: open, and stays open until committed.
+	now = wantNext.Add(5 * time.Minute)
+	if d, _ = e.Evaluate(p); !d.Open {
+		t.Fatal("cron gate should be open after next fire")
+	}
+	if d2, _ := e.Evaluate(p); !d2.Open {
+		t.Error("uncommitted cron gate should remain open")
+	}
+
+	if err := e.Commit(p, d); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+	st, _ = r.GetGateState(p.Name)
+	if want := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC); !st.NextFire.Equal(want) {
+		t.Errorf("NextFire after commit = %v, want %v", st.NextFire, want)
+	}
+	if d, _ = e.Evaluate(p); d.Open {
+		t.Error("cron gate should be closed after commit")
+	}
+}
+
+func TestGateEvaluator_CronScheduleChangeResets(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRecorder(townRoot)
+	e := NewGateEvaluator(townRoot, r)
+	now := time.Date(2026, 1, 14, 8, 0, 0, 0, time.UTC)
+	e.Now = func() time.Time { return now }
+
+	// Stale state from an old schedule that would otherwise be due.
+	_ = r.SaveGateState("test-plugin", &GateState{Schedule: "0 1 * * *", NextFire: now.Add(-time.Hour)})
+
+	p := testGatePlugin(t, &Gate{Type: GateCron, Schedule: "0 9 * * *"})
+	d, err := e.Evaluate(p)
+	if err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+	if d.Open {
+		t.Error("changed schedule should reinitialize rather than fire")
+	}
+}
+
+func TestGateEvaluator_CronInvalidSchedule(t *testing.T) {
+	e := NewGateEvaluator(t.TempDir(), NewRecorder(t.TempDir()))
+	p := testGatePlugin(t, &Gate{Type: GateCron, Schedule: "not a schedule"})
+	if _, err := e.Evaluate(p); err == nil {
+		t.Error("expected error for invalid schedule")
+	}
+}
+
+func TestGateEvaluator_Condition(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("condition gates use sh")
+	}
+	e := NewGateEvaluator(t.TempDir(), NewRecorder(t.TempDir()))
+
+	tests := []struct {
+		check string
+		open  bool
+	}{
+		{"true", true},
+		{"false", false},
+		{"exit 3", false},
+		{"test -d .", true},
+	}
+	for _, tt := range tests {
+		d, err := e.Evaluate(testGatePlugin(t, &Gate{Type: GateCondition, Check: tt.check}))
+		if err != nil {
+			t.Fatalf("Evaluate(%q) error: %v", tt.check, err)
+		}
+		if d.Open != tt.open {
+			t.Errorf("check %q: Open = %v, want %v (%s)", tt.check, d.Open, tt.open, d.Reason)
+		}
+	}
+}
+
+func TestGateEvaluator_ConditionTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("condition gates use sh")
+	}
+	e := NewGateEvaluator(t.TempDir(), NewRecorder(t.TempDir()))
+	e.ConditionTimeout = 100 * time.Millisecond
+
+	d, err := e.Evaluate(testGatePlugin(t, &Gate{Type: GateCondition, Check: "sleep 5"}))
+	if err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+	if d.Open {
+		t.Error("timed-out check should keep gate closed")
+	}
+}
+
+func TestGateEvaluator_ConditionMissingCheck(t *testing.T) {
+	e := NewGateEvaluator(t.TempDir(), NewRecorder(t.TempDir()))
+	if _, err := e.Evaluate(testGatePlugin(t, &Gate{Type: GateCondition})); err == nil {
+		t.Error("expected error for condition gate without check")
+	}
+}
+
+func TestGateEvaluator_Event(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRecorder(townRoot)
+	e := NewGateEvaluator(townRoot, r)
+	p := testGatePlugin(t, &Gate{Type: GateEvent, On: "merged, session_death"})
+
+	// History before the plugin was first seen must not fire it.
+	testAppendEvent(t, townRoot, events.TypeMerged, time.Now())
+	d, err := e.Evaluate(p)
+	if err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+	if d.Open {
+		t.Fatal("event gate should not fire on historical events")
+	}
+
+	// Unrelated events keep it closed.
+	testAppendEvent(t, townRoot, events.TypeSling, time.Now())
+	if d, _ = e.Evaluate(p); d.Open {
+		t.Error("event gate should ignore non-matching events")
+	}
+
+	// A matching event opens it, and it stays open until committed.
+	testAppendEvent(t, townRoot, events.TypeSessionDeath, time.Now())
+	if d, _ = e.Evaluate(p); !d.Open {
+		t.Fatal("event gate should open on matching event")
+	}
+	if d2, _ := e.Evaluate(p); !d2.Open {
+		t.Error("uncommitted event gate should remain open")
+	}
+
+	if err := e.Commit(p, d); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+	if d, _ = e.Evaluate(p); d.Open {
+		t.Error("event gate should be closed after commit")
+	}
+
+	testAppendEvent(t, townRoot, events.TypeMerged, time.Now())
+	if d, _ = e.Evaluate(p); !d.Open {
+		t.Error("event gate should reopen on a new matching event")
+	}
+}
+
+func TestGateEvaluator_EventLogCreatedLater(t *testing.T) {
+	townRoot := t.TempDir()
+	e := NewGateEvaluator(townRoot, NewRecorder(townRoot))
+	p := testGatePlugin(t, &Gate{Type: GateEvent, On: "merged"})
+
+	if d, _ := e.Evaluate(p); d.Open {
+		t.Fatal("event gate should be closed with no log")
+	}
+	testAppendEvent(t, townRoot, events.TypeMerged, time.Now())
+	if d, _ := e.Evaluate(p); !d.Open {
+		t.Error("event gate should fire on the first event written after it started watching")
+	}
+}
+
+func TestGateEvaluator_EventStartup(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRecorder(townRoot)
+	e := NewGateEvaluator(townRoot, r)
+	p := testGatePlugin(t, &Gate{Type: GateEvent, On: EventStartup})
+
+	d, err := e.Evaluate(p)
+	if err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+	if !d.Open {
+		t.Fatal("startup gate should open once after start")
+	}
+	e.Now = func() time.Time { return e.StartedAt.Add(time.Second) }
+	if err := e.Commit(p, d); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+	if d, _ = e.Evaluate(p); d.Open {
+		t.Error("startup gate should not fire twice for the same start")
+	}
+
+	// A restart fires it again.
+	e.StartedAt = e.StartedAt.Add(time.Minute)
+	if d, _ = e.Evaluate(p); !d.Open {
+		t.Error("startup gate should fire after restart")
+	}
+}
@@ -122,7 +122,7 @@ type Manager struct {
 	git      *git.Git
 	beads    *beads.Beads
 	namePool *NamePool
-	tmux     *tmux.Tmux
+	tmux     session.Backend
 }
 
 // NewManager creates a new polecat manager.
@@ -153,12 +153,18 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 	}
 	_ = pool.Load() // non-fatal: state file may not exist for new rigs
 
+	// A nil t means "don't touch sessions"; otherwise use the configured backend.
+	var backend session.Backend
+	if t != nil {
+		backend = session.ResolveBackend(t)
+	}
+
 	return &Manager{
 		rig:      r,
 		git:      g,
 		beads:    beads.NewWithBeadsDir(beadsPath, resolvedBeads),
 		namePool: pool,
-		tmux:     t,
+		tmux:     backend,
 	}
 }
 
@@ -1372,7 +1378,7 @@ func (m *Manager) ReconcilePoolWith(namesWithDirs, namesWithSessions []string) {
 // isSessionProcessDead checks if a tmux session's pane process has exited.
 // Returns true only when we can confirm the process is dead, not on transient
 // tmux query failures (gt-kncti: permission denied false positives).
-func isSessionProcessDead(t *tmux.Tmux, sessionName string) bool {
+func isSessionProcessDead(t session.Backend, sessionName string) bool {
 	pidStr, err := t.GetPanePID(sessionName)
 	if err != nil {
 		// Tmux query failed — could be permission denied, server busy, etc.
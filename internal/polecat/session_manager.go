@@ -15,12 +15,16 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/sandbox"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -40,14 +44,16 @@ var (
 
 // SessionManager handles polecat session lifecycle.
 type SessionManager struct {
-	tmux *tmux.Tmux
+	tmux session.Backend
 	rig  *rig.Rig
 }
 
 // NewSessionManager creates a new polecat session manager for a rig.
+// Sessions run on the configured backend: t under tmux, the pty
+// supervisor otherwise.
 func NewSessionManager(t *tmux.Tmux, r *rig.Rig) *SessionManager {
 	return &SessionManager{
-		tmux: t,
+		tmux: session.ResolveBackend(t),
 		rig:  r,
 	}
 }
@@ -183,7 +189,7 @@ func (m *SessionManager) hasPolecat(polecat string) bool {
 }
 
 // Start creates and starts a new session for a polecat.
-func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
+func (m *SessionManager) Start(polecat string, opts SessionStartOptions) (retErr error) {
 	if !m.hasPolecat(polecat) {
 		return fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
 	}
@@ -215,11 +221,30 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Validate issue exists and isn't tombstoned BEFORE creating session.
 	// This prevents CPU spin loops from agents retrying work on invalid issues.
+	var issueTraceParent string
 	if opts.Issue != "" {
-		if err := m.validateIssue(opts.Issue, workDir); err != nil {
+		tp, err := m.validateIssue(opts.Issue, workDir)
+		if err != nil {
 			return err
 		}
+		issueTraceParent = tp
+	}
+
+	// The session joins the trace of the sling that assigned its work: the
+	// current process's trace when started by gt sling, otherwise the one
+	// recorded on the hooked bead (e.g., witness respawns).
+	traceCtx := context.Background()
+	if telemetry.CurrentTraceParent() == "" {
+		traceCtx = telemetry.ContextWithTraceParent(traceCtx, issueTraceParent)
 	}
+	traceCtx, span := telemetry.StartSpan(traceCtx, "session.start",
+		attribute.String("gt.role", "polecat"),
+		attribute.String("gt.rig", m.rig.Name),
+		attribute.String("gt.session", sessionID),
+		attribute.String("gt.issue", opts.Issue),
+	)
+	defer func() { telemetry.EndSpan(span, retErr) }()
+	traceParent := telemetry.TraceParent(traceCtx)
 
 	// Resolve runtime config for the agent that will actually run in this session.
 	// When an explicit --agent override is provided (e.g., "codex"), use it to resolve
@@ -277,6 +302,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 			Issue:       opts.Issue,
 			Topic:       "assigned",
 			SessionName: sessionID,
+			TraceParent: traceParent,
 		}, m.rig.Path, beacon, "")
 		if err != nil {
 			return fmt.Errorf("building startup command: %w", err)
@@ -316,6 +342,13 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	}
 	command = config.PrependEnv(command, envVarsToInject)
 
+	// Run the agent inside the rig's sandbox, if configured. Fail rather than
+	// start an unconfined polecat when the sandbox is unavailable.
+	command, err = m.sandboxCommand(sessionID, workDir, townRoot, opts.RuntimeConfigDir, command)
+	if err != nil {
+		return err
+	}
+
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
@@ -332,6 +365,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		TownRoot:         townRoot,
 		RuntimeConfigDir: opts.RuntimeConfigDir,
 		Agent:            opts.Agent,
+		TraceParent:      traceParent,
 	})
 	for k, v := range envVars {
 		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
@@ -373,24 +407,26 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		}
 	}
 
-	// Apply theme (non-fatal)
-	theme := tmux.AssignTheme(m.rig.Name)
-	debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
+	if tm, ok := m.tmux.(*tmux.Tmux); ok {
+		// Apply theme (non-fatal)
+		theme := tmux.AssignTheme(m.rig.Name)
+		debugSession("ConfigureGasTownSession", tm.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
 
-	// Set pane-died hook for crash detection (non-fatal)
-	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
-	debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
+		// Set pane-died hook for crash detection (non-fatal)
+		agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
+		debugSession("SetPaneDiedHook", tm.SetPaneDiedHook(sessionID, agentID))
 
-	// Wait for Claude to start (non-fatal)
-	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
+		// Wait for Claude to start (non-fatal)
+		debugSession("WaitForCommand", tm.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
 
-	// Accept startup dialogs (workspace trust + bypass permissions) if they appear
-	debugSession("AcceptStartupDialogs", m.tmux.AcceptStartupDialogs(sessionID))
+		// Accept startup dialogs (workspace trust + bypass permissions) if they appear
+		debugSession("AcceptStartupDialogs", tm.AcceptStartupDialogs(sessionID))
+	}
 
 	// Wait for runtime to be fully ready at the prompt (not just started).
 	// Uses prompt-based polling for agents with ReadyPromptPrefix (e.g., Claude "❯ "),
 	// falling back to ReadyDelayMs sleep for agents without prompt detection.
-	debugSession("WaitForRuntimeReady", m.tmux.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout))
+	debugSession("WaitForRuntimeReady", session.WaitForRuntimeReady(m.tmux, sessionID, runtimeConfig, constants.ClaudeStartTimeout))
 
 	// Handle fallback nudges for non-hook agents.
 	// See StartupFallbackInfo in runtime package for the fallback matrix.
@@ -408,7 +444,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 			// Wait for agent to finish processing beacon + gt prime before sending work instructions.
 			// Uses prompt-based detection where available; falls back to max(ReadyDelayMs, StartupNudgeDelayMs).
 			primeWaitRC := runtime.RuntimeConfigWithMinDelay(runtimeConfig, fallbackInfo.StartupNudgeDelayMs)
-			debugSession("WaitForPrimeReady", m.tmux.WaitForRuntimeReady(sessionID, primeWaitRC, constants.ClaudeStartTimeout))
+			debugSession("WaitForPrimeReady", session.WaitForRuntimeReady(m.tmux, sessionID, primeWaitRC, constants.ClaudeStartTimeout))
 		}
 
 		if fallbackInfo.SendStartupNudge {
@@ -463,6 +499,51 @@ func (m *SessionManager) isSessionStale(sessionID string) bool {
 	return isSessionProcessDead(m.tmux, sessionID)
 }
 
+// sandboxCommand wraps command in the rig's polecat sandbox. Returns command
+// unchanged when the rig has no sandbox enabled.
+//
+// Besides the worktree, the sandbox can write to the rig's shared .repo.git
+// (worktree metadata and objects), the town and rig beads directories, the
+// town runtime state (including the nudge queue, which replaces tmux for
+// nudges sent from inside), the event log and file events gt writes to, and
+// the agent's runtime config dir.
+func (m *SessionManager) sandboxCommand(sessionID, workDir, townRoot, runtimeConfigDir, command string) (string, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(m.rig.Path))
+	if err != nil || settings.Sandbox == nil || !settings.Sandbox.Enabled {
+		return command, nil
+	}
+	cfg := settings.Sandbox
+
+	writable := []string{
+		filepath.Join(m.rig.Path, ".repo.git"),
+		filepath.Join(m.rig.Path, ".beads"),
+		filepath.Join(m.rig.Path, "mayor", "rig", ".beads"),
+		filepath.Join(townRoot, ".beads"),
+		filepath.Join(townRoot, constants.DirRuntime),
+		filepath.Join(townRoot, "logs"),
+		filepath.Join(townRoot, events.EventsFile),
+		filepath.Join(townRoot, events.EventsFile+".lock"),
+		filepath.Join(townRoot, "events"),
+	}
+	// Writable paths must exist to be mounted; the event channels dir is
+	// otherwise created on first use.
+	_ = os.MkdirAll(filepath.Join(townRoot, "events"), 0755)
+	if runtimeConfigDir != "" {
+		writable = append(writable, runtimeConfigDir)
+	}
+	writable = append(writable, cfg.WritablePaths()...)
+
+	wrapped, err := sandbox.Wrap(cfg, sandbox.Spec{
+		Name:     sessionID,
+		WorkDir:  workDir,
+		Writable: writable,
+	}, command)
+	if err != nil {
+		return "", fmt.Errorf("sandboxing polecat: %w", err)
+	}
+	return wrapped, nil
+}
+
 // Stop terminates a polecat session.
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
@@ -495,7 +576,7 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 // reporting zombie sessions (tmux alive but Claude dead) as "running".
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
-	status := m.tmux.CheckSessionHealth(sessionID, 0)
+	status := session.CheckSessionHealth(m.tmux, sessionID, 0)
 	return status == tmux.SessionHealthy, nil
 }
 
@@ -519,7 +600,7 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 		return info, nil
 	}
 
-	tmuxInfo, err := m.tmux.GetSessionInfo(sessionID)
+	tmuxInfo, err := session.GetSessionInfo(m.tmux, sessionID)
 	if err != nil {
 		return info, nil
 	}
@@ -614,7 +695,11 @@ func (m *SessionManager) Attach(polecat string) error {
 		return ErrSessionNotFound
 	}
 
-	return m.tmux.AttachSession(sessionID)
+	tm, ok := m.tmux.(*tmux.Tmux)
+	if !ok {
+		return fmt.Errorf("attaching to %s requires the tmux session backend", sessionID)
+	}
+	return tm.AttachSession(sessionID)
 }
 
 // Capture returns the recent output from a polecat session.
@@ -662,7 +747,11 @@ func (m *SessionManager) Inject(polecat, message string) error {
 		debounceMs = 1500
 	}
 
-	return m.tmux.SendKeysDebounced(sessionID, message, debounceMs)
+	tm, ok := m.tmux.(*tmux.Tmux)
+	if !ok {
+		return m.tmux.SendKeys(sessionID, message)
+	}
+	return tm.SendKeysDebounced(sessionID, message, debounceMs)
 }
 
 // StopAll terminates all polecat sessions for this rig.
@@ -692,8 +781,9 @@ func (m *SessionManager) resolveBeadsDir(issueID, fallbackDir string) string {
 
 // validateIssue checks that an issue exists and is not tombstoned.
 // This must be called before starting a session to avoid CPU spin loops
-// from agents retrying work on invalid issues.
-func (m *SessionManager) validateIssue(issueID, workDir string) error {
+// from agents retrying work on invalid issues. Returns the trace_parent
+// recorded on the issue, if any.
+func (m *SessionManager) validateIssue(issueID, workDir string) (string, error) {
 	bdWorkDir := m.resolveBeadsDir(issueID, workDir)
 
 	ctx, cancel := context.WithTimeout(context.Background(), constants.BdCommandTimeout)
@@ -702,22 +792,27 @@ func (m *SessionManager) validateIssue(issueID, workDir string) error {
 	cmd.Dir = bdWorkDir
 	output, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrIssueInvalid, issueID)
+		return "", fmt.Errorf("%w: %s", ErrIssueInvalid, issueID)
 	}
 
 	var issues []struct {
-		Status string `json:"status"`
+		Status      string `json:"status"`
+		Description string `json:"description"`
 	}
 	if err := json.Unmarshal(output, &issues); err != nil {
-		return fmt.Errorf("parsing issue: %w", err)
+		return "", fmt.Errorf("parsing issue: %w", err)
 	}
 	if len(issues) == 0 {
-		return fmt.Errorf("%w: %s", ErrIssueInvalid, issueID)
+		return "", fmt.Errorf("%w: %s", ErrIssueInvalid, issueID)
 	}
 	if issues[0].Status == "tombstone" {
-		return fmt.Errorf("%w: %s is tombstoned", ErrIssueInvalid, issueID)
+		return "", fmt.Errorf("%w: %s is tombstoned", ErrIssueInvalid, issueID)
 	}
-	return nil
+	var traceParent string
+	if fields := beads.ParseAttachmentFields(&beads.Issue{Description: issues[0].Description}); fields != nil {
+		traceParent = fields.TraceParent
+	}
+	return traceParent, nil
 }
 
 // verifyStartupNudgeDelivery checks if the polecat started working after the
@@ -737,6 +832,11 @@ func (m *SessionManager) verifyStartupNudgeDelivery(sessionID string, rc *config
 	if rc == nil || rc.Tmux == nil || rc.Tmux.ReadyPromptPrefix == "" {
 		return
 	}
+	// Prompt detection reads the tmux pane.
+	tm, ok := m.tmux.(*tmux.Tmux)
+	if !ok {
+		return
+	}
 
 	nudgeContent := runtime.StartupNudgeContent()
 
@@ -751,7 +851,7 @@ func (m *SessionManager) verifyStartupNudgeDelivery(sessionID string, rc *config
 		}
 
 		// If the agent is NOT at the prompt, it's working — nudge was received.
-		if !m.tmux.IsAtPrompt(sessionID, rc) {
+		if !tm.IsAtPrompt(sessionID, rc) {
 			return
 		}
 
@@ -766,7 +866,7 @@ func (m *SessionManager) verifyStartupNudgeDelivery(sessionID string, rc *config
 
 	// If we exhausted retries and the agent is still idle, log a warning.
 	// The witness zombie patrol will handle this case.
-	if m.tmux.IsAtPrompt(sessionID, rc) {
+	if tm.IsAtPrompt(sessionID, rc) {
 		fmt.Fprintf(os.Stderr, "[startup-nudge] WARNING: agent %s still idle after %d nudge retries\n",
 			sessionID, constants.StartupNudgeMaxRetries)
 	}
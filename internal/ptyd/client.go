@@ -0,0 +1,222 @@
+package ptyd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// ErrNoServer is returned when no supervisor is listening on the socket.
+var ErrNoServer = errors.New("ptyd is not running")
+
+const (
+	dialTimeout    = 2 * time.Second
+	startupTimeout = 5 * time.Second
+)
+
+// startServer launches a detached supervisor on socket. Replaced in tests.
+var startServer = func(socket string) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	cmd := exec.Command(gtPath, "ptyd", "serve", "--socket", socket)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting ptyd: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// Client talks to the supervisor. Its methods mirror the tmux operations of
+// the same name, so it can stand in for *tmux.Tmux as a session backend.
+type Client struct {
+	socket string
+}
+
+// NewClient returns a client for the supervisor on socket.
+func NewClient(socket string) *Client {
+	return &Client{socket: socket}
+}
+
+// Socket returns the supervisor socket path.
+func (c *Client) Socket() string {
+	return c.socket
+}
+
+// IsRunning reports whether a supervisor is listening.
+func (c *Client) IsRunning() bool {
+	conn, err := net.DialTimeout("unix", c.socket, dialTimeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// EnsureRunning starts a supervisor if none is listening, like the tmux
+// server starting with its first session.
+func (c *Client) EnsureRunning() error {
+	if c.IsRunning() {
+		return nil
+	}
+	if err := startServer(c.socket); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(startupTimeout)
+	for time.Now().Before(deadline) {
+		if c.IsRunning() {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("ptyd did not start listening on %s", c.socket)
+}
+
+func (c *Client) call(req request) (*response, error) {
+	conn, err := net.DialTimeout("unix", c.socket, dialTimeout)
+	if err != nil {
+		if errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, ErrNoServer
+		}
+		return nil, fmt.Errorf("connecting to ptyd: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("sending ptyd request: %w", err)
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading ptyd response: %w", err)
+	}
+	if resp.Error != "" {
+		return &resp, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// NewSession creates a session running the user's login shell.
+func (c *Client) NewSession(name, workDir string) error {
+	return c.NewSessionWithCommand(name, workDir, "")
+}
+
+// NewSessionWithCommand creates a session running command via sh -c,
+// starting the supervisor if needed.
+func (c *Client) NewSessionWithCommand(name, workDir, command string) error {
+	return c.NewSessionWithCommandAndEnv(name, workDir, command, nil)
+}
+
+// NewSessionWithCommandAndEnv creates a session with extra environment
+// variables, which are also readable with GetEnvironment.
+func (c *Client) NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error {
+	if err := c.EnsureRunning(); err != nil {
+		return err
+	}
+	_, err := c.call(request{Op: opNew, Session: name, WorkDir: workDir, Command: command, Env: env})
+	return err
+}
+
+// KillSessionWithProcesses terminates the session and its process group.
+func (c *Client) KillSessionWithProcesses(name string) error {
+	_, err := c.call(request{Op: opKill, Session: name})
+	return err
+}
+
+// SendKeys sends literal text, waits for it to be processed, then presses
+// Enter.
+func (c *Client) SendKeys(session, keys string) error {
+	if _, err := c.call(request{Op: opSend, Session: session, Keys: keys}); err != nil {
+		return err
+	}
+	time.Sleep(time.Duration(constants.DefaultDebounceMs) * time.Millisecond)
+	return c.SendKeysRaw(session, "Enter")
+}
+
+// NudgeSession delivers message to the session's agent: the text, then
+// Enter. Each session has one writer, so no cross-process lock is needed.
+func (c *Client) NudgeSession(session, message string) error {
+	return c.SendKeys(session, message)
+}
+
+// SendKeysRaw sends a tmux-style key ("Enter", "Escape", "C-c") or literal
+// text without pressing Enter.
+func (c *Client) SendKeysRaw(session, keys string) error {
+	_, err := c.call(request{Op: opKeys, Session: session, Keys: keys})
+	return err
+}
+
+// CapturePane returns the last lines of the session's output as plain text.
+func (c *Client) CapturePane(session string, lines int) (string, error) {
+	resp, err := c.call(request{Op: opCapture, Session: session, Lines: lines})
+	if err != nil {
+		return "", err
+	}
+	return resp.Output, nil
+}
+
+// HasSession reports whether the session exists. No server means no sessions.
+func (c *Client) HasSession(name string) (bool, error) {
+	resp, err := c.call(request{Op: opHas, Session: name})
+	if errors.Is(err, ErrNoServer) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return resp.Exists, nil
+}
+
+// ListSessions returns the session names. No server means no sessions.
+func (c *Client) ListSessions() ([]string, error) {
+	resp, err := c.call(request{Op: opList})
+	if errors.Is(err, ErrNoServer) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Sessions, nil
+}
+
+// SetEnvironment records a variable in the session's environment table.
+// Like tmux, it does not change the environment of the running command.
+func (c *Client) SetEnvironment(session, key, value string) error {
+	_, err := c.call(request{Op: opSetEnv, Session: session, Key: key, Value: value})
+	return err
+}
+
+// GetEnvironment reads a variable from the session's environment table.
+func (c *Client) GetEnvironment(session, key string) (string, error) {
+	resp, err := c.call(request{Op: opGetEnv, Session: session, Key: key})
+	if err != nil {
+		return "", err
+	}
+	return resp.Value, nil
+}
+
+// GetPanePID returns the PID of the session's command.
+func (c *Client) GetPanePID(session string) (string, error) {
+	resp, err := c.call(request{Op: opPID, Session: session})
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(resp.PID), nil
+}
+
+// IsAgentAlive reports whether the session's command is running. Sessions
+// end when their command exits, so this is the same as HasSession.
+func (c *Client) IsAgentAlive(session string) bool {
+	ok, err := c.HasSession(session)
+	return err == nil && ok
+}
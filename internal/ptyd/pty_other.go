@@ -0,0 +1,24 @@
+//go:build !linux
+
+package ptyd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// openPTY is only implemented on Linux.
+func openPTY(rows, cols uint16) (master, slave *os.File, err error) {
+	return nil, nil, fmt.Errorf("pty sessions are not supported on %s", runtime.GOOS)
+}
+
+func setControllingTTY(cmd *exec.Cmd) {}
+
+func signalGroup(pid int, sig syscall.Signal) error {
+	return fmt.Errorf("process groups are not supported on %s", runtime.GOOS)
+}
+
+func detachedProcAttr() *syscall.SysProcAttr { return nil }
@@ -0,0 +1,112 @@
+// Package ptyd is a headless session supervisor for hosts without tmux.
+//
+// A single gt-managed server process (gt ptyd serve) holds a pseudo-terminal
+// and scrollback for each agent session. gt commands talk to it over a unix
+// socket through Client, which implements the same session operations as
+// tmux (create, kill, send keys, capture, has, list), so every agent can run
+// in CI containers and systemd units where tmux isn't installed (see
+// session.Backend).
+//
+// Sessions end when their command exits; there is no attach. Use
+// gt ptyd capture to see what an agent is doing.
+package ptyd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvSocket overrides the supervisor's socket path.
+const EnvSocket = "GT_PTYD_SOCKET"
+
+const (
+	// defaultRows and defaultCols size each session's terminal.
+	defaultRows = 50
+	defaultCols = 200
+
+	// scrollbackLines is how many lines of output are kept per session.
+	scrollbackLines = 5000
+)
+
+// SocketPath returns the supervisor socket path: $GT_PTYD_SOCKET, else
+// gastown-ptyd.sock in $XDG_RUNTIME_DIR, else a per-user directory in the
+// system temp dir (like tmux's default socket).
+func SocketPath() string {
+	if p := os.Getenv(EnvSocket); p != "" {
+		return p
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "gastown-ptyd.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("gastown-ptyd-%d", os.Getuid()), "ptyd.sock")
+}
+
+// Protocol operations. Each connection carries one JSON request and one
+// JSON response.
+const (
+	opNew     = "new"
+	opKill    = "kill"
+	opSend    = "send"
+	opKeys    = "keys"
+	opCapture = "capture"
+	opHas     = "has"
+	opList    = "list"
+	opSetEnv  = "setenv"
+	opGetEnv  = "getenv"
+	opPID     = "pid"
+)
+
+type request struct {
+	Op      string            `json:"op"`
+	Session string            `json:"session,omitempty"`
+	WorkDir string            `json:"work_dir,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Keys    string            `json:"keys,omitempty"`
+	Enter   bool              `json:"enter,omitempty"`
+	Lines   int               `json:"lines,omitempty"`
+	Key     string            `json:"key,omitempty"`
+	Value   string            `json:"value,omitempty"`
+}
+
+type response struct {
+	Error    string   `json:"error,omitempty"`
+	Exists   bool     `json:"exists,omitempty"`
+	Sessions []string `json:"sessions,omitempty"`
+	Output   string   `json:"output,omitempty"`
+	Value    string   `json:"value,omitempty"`
+	PID      int      `json:"pid,omitempty"`
+}
+
+// namedKeys maps tmux key names to the bytes a terminal sends for them.
+var namedKeys = map[string]string{
+	"Enter":  "\r",
+	"C-m":    "\r",
+	"Escape": "\x1b",
+	"Tab":    "\t",
+	"BSpace": "\x7f",
+	"Space":  " ",
+	"Up":     "\x1b[A",
+	"Down":   "\x1b[B",
+	"Right":  "\x1b[C",
+	"Left":   "\x1b[D",
+	"Home":   "\x1b[H",
+	"End":    "\x1b[F",
+}
+
+// keyBytes translates a tmux-style key argument: a key name ("Enter",
+// "Escape", "C-c"), or literal text.
+func keyBytes(key string) string {
+	if b, ok := namedKeys[key]; ok {
+		return b
+	}
+	if len(key) == 3 && strings.HasPrefix(key, "C-") {
+		c := key[2] | 0x20 // lower case
+		if c >= 'a' && c <= 'z' {
+			return string(rune(c - 'a' + 1))
+		}
+	}
+	return key
+}
@@ -0,0 +1,144 @@
+package ptyd
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// screen turns pty output into plain-text lines for capture.
+//
+// It is not a terminal emulator: escape sequences are dropped, carriage
+// return rewinds the current line and erase-line clears it, which is enough
+// for prompt detection and scanning output. Cursor addressing is ignored, so
+// full-screen redraws are captured as they were written.
+type screen struct {
+	maxLines int
+	lines    []string // completed lines, oldest first
+	cur      []rune   // current line
+	col      int      // cursor column in cur
+
+	state   escState
+	pending []byte // incomplete UTF-8 sequence
+}
+
+type escState int
+
+const (
+	escNone   escState = iota
+	escStart           // after ESC
+	escCSI             // ESC [ ... final byte
+	escOSC             // ESC ] ... BEL or ESC \
+	escOSCEsc          // ESC inside OSC
+)
+
+func newScreen(maxLines int) *screen {
+	return &screen{maxLines: maxLines}
+}
+
+// Write consumes raw pty output.
+func (s *screen) Write(p []byte) {
+	if len(s.pending) > 0 {
+		p = append(s.pending, p...)
+		s.pending = nil
+	}
+	for len(p) > 0 {
+		b := p[0]
+		switch s.state {
+		case escStart:
+			switch b {
+			case '[':
+				s.state = escCSI
+			case ']':
+				s.state = escOSC
+			default:
+				s.state = escNone
+			}
+			p = p[1:]
+			continue
+		case escCSI:
+			if b >= 0x40 && b <= 0x7e {
+				s.state = escNone
+				if b == 'K' {
+					s.cur = s.cur[:min(s.col, len(s.cur))]
+				}
+			}
+			p = p[1:]
+			continue
+		case escOSC:
+			switch b {
+			case 0x07:
+				s.state = escNone
+			case 0x1b:
+				s.state = escOSCEsc
+			}
+			p = p[1:]
+			continue
+		case escOSCEsc:
+			s.state = escNone
+			p = p[1:]
+			continue
+		}
+
+		switch b {
+		case 0x1b:
+			s.state = escStart
+		case '\n':
+			s.newline()
+		case '\r':
+			s.col = 0
+		case '\b':
+			if s.col > 0 {
+				s.col--
+			}
+		case '\t':
+			s.put(' ')
+		default:
+			if b < 0x20 || b == 0x7f {
+				break
+			}
+			if b >= utf8.RuneSelf {
+				if !utf8.FullRune(p) {
+					s.pending = append([]byte(nil), p...)
+					return
+				}
+				r, size := utf8.DecodeRune(p)
+				s.put(r)
+				p = p[size:]
+				continue
+			}
+			s.put(rune(b))
+		}
+		p = p[1:]
+	}
+}
+
+func (s *screen) put(r rune) {
+	if s.col < len(s.cur) {
+		s.cur[s.col] = r
+	} else {
+		s.cur = append(s.cur, r)
+	}
+	s.col++
+}
+
+func (s *screen) newline() {
+	s.lines = append(s.lines, strings.TrimRight(string(s.cur), " "))
+	if len(s.lines) > s.maxLines {
+		s.lines = append([]string(nil), s.lines[len(s.lines)-s.maxLines:]...)
+	}
+	s.cur = s.cur[:0]
+	s.col = 0
+}
+
+// Tail returns the last n lines, including the current line if it is not
+// empty. n <= 0 returns all lines.
+func (s *screen) Tail(n int) []string {
+	all := s.lines
+	if len(s.cur) > 0 {
+		all = append(all[:len(all):len(all)], strings.TrimRight(string(s.cur), " "))
+	}
+	if n > 0 && len(all) > n {
+		all = all[len(all)-n:]
+	}
+	return append([]string(nil), all...)
+}
@@ -0,0 +1,42 @@
+package ptyd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScreen(t *testing.T) {
+	s := newScreen(3)
+	s.Write([]byte("\x1b[1;32mgreen\x1b[0m line\r\n"))
+	s.Write([]byte("progress 10%\rprogress 100%\n"))
+	s.Write([]byte("\x1b]0;window title\x07title stripped\n"))
+	s.Write([]byte("erase me\r\x1b[Kkept\n"))
+	s.Write([]byte("❯ "))
+	s.Write([]byte{0xe2, 0x9c}) // split multi-byte rune
+	s.Write([]byte{0x93, 'x'})
+
+	want := []string{"progress 100%", "title stripped", "kept", "❯ ✓x"}
+	if got := s.Tail(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tail(0) = %q, want %q", got, want)
+	}
+	if got := s.Tail(2); !reflect.DeepEqual(got, want[2:]) {
+		t.Errorf("Tail(2) = %q, want %q", got, want[2:])
+	}
+}
+
+func TestKeyBytes(t *testing.T) {
+	tests := map[string]string{
+		"Enter":  "\r",
+		"Escape": "\x1b",
+		"C-c":    "\x03",
+		"C-U":    "\x15",
+		"Down":   "\x1b[B",
+		"hello":  "hello",
+		"C-":     "C-",
+	}
+	for in, want := range tests {
+		if got := keyBytes(in); got != want {
+			t.Errorf("keyBytes(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package ptyd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// killGrace is how long a killed session gets to exit after SIGTERM before
+// its process group is sent SIGKILL.
+const killGrace = 2 * time.Second
+
+// Server supervises pty sessions and serves requests on a unix socket.
+type Server struct {
+	socket string
+
+	mu       sync.Mutex
+	sessions map[string]*ptySession
+}
+
+// ptySession is one supervised command and its terminal.
+type ptySession struct {
+	name   string
+	cmd    *exec.Cmd
+	master *os.File
+	done   chan struct{} // closed when the command has exited
+
+	mu     sync.Mutex // guards screen and env
+	screen *screen
+	env    map[string]string
+}
+
+// NewServer returns a server that will listen on socket.
+func NewServer(socket string) *Server {
+	return &Server{socket: socket, sessions: make(map[string]*ptySession)}
+}
+
+// Serve listens on the socket and handles requests until ctx is cancelled,
+// then kills all sessions. Fails if another server is already listening.
+func (s *Server) Serve(ctx context.Context) error {
+	if err := os.MkdirAll(filepath.Dir(s.socket), 0700); err != nil {
+		return fmt.Errorf("creating socket dir: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", s.socket, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("ptyd already running on %s", s.socket)
+	}
+	_ = os.Remove(s.socket) // stale socket from a dead server
+
+	ln, err := net.Listen("unix", s.socket)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.socket, err)
+	}
+	_ = os.Chmod(s.socket, 0600)
+
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				s.killAll()
+				_ = os.Remove(s.socket)
+				return nil
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			continue
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		_ = json.NewEncoder(conn).Encode(response{Error: fmt.Sprintf("decoding request: %v", err)})
+		return
+	}
+	resp, err := s.handle(req)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+func (s *Server) handle(req request) (response, error) {
+	switch req.Op {
+	case opList:
+		return response{Sessions: s.list()}, nil
+	case opHas:
+		return response{Exists: s.get(req.Session) != nil}, nil
+	case opNew:
+		return response{}, s.start(req)
+	}
+
+	sess := s.get(req.Session)
+	if sess == nil {
+		return response{}, fmt.Errorf("session not found: %s", req.Session)
+	}
+	switch req.Op {
+	case opKill:
+		s.kill(sess)
+		return response{}, nil
+	case opSend:
+		text := req.Keys
+		if req.Enter {
+			text += "\r"
+		}
+		_, err := sess.master.Write([]byte(text))
+		return response{}, err
+	case opKeys:
+		_, err := sess.master.Write([]byte(keyBytes(req.Keys)))
+		return response{}, err
+	case opCapture:
+		sess.mu.Lock()
+		lines := sess.screen.Tail(req.Lines)
+		sess.mu.Unlock()
+		return response{Output: strings.Join(lines, "\n")}, nil
+	case opSetEnv:
+		sess.mu.Lock()
+		sess.env[req.Key] = req.Value
+		sess.mu.Unlock()
+		return response{}, nil
+	case opGetEnv:
+		sess.mu.Lock()
+		v, ok := sess.env[req.Key]
+		sess.mu.Unlock()
+		if !ok {
+			return response{}, fmt.Errorf("unknown variable: %s", req.Key)
+		}
+		return response{Value: v}, nil
+	case opPID:
+		return response{PID: sess.cmd.Process.Pid}, nil
+	}
+	return response{}, fmt.Errorf("unknown op %q", req.Op)
+}
+
+func (s *Server) get(name string) *ptySession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[name]
+}
+
+func (s *Server) list() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.sessions))
+	for name := range s.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// start runs req.Command (or the user's shell) in a new pty session. The
+// session is removed when the command exits.
+func (s *Server) start(req request) error {
+	if req.Session == "" {
+		return fmt.Errorf("session name is required")
+	}
+	s.mu.Lock()
+	if _, exists := s.sessions[req.Session]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("duplicate session: %s", req.Session)
+	}
+	// Reserve the name while the process starts.
+	s.sessions[req.Session] = nil
+	s.mu.Unlock()
+
+	sess, err := s.spawn(req)
+	s.mu.Lock()
+	if err != nil {
+		delete(s.sessions, req.Session)
+	} else {
+		s.sessions[req.Session] = sess
+	}
+	s.mu.Unlock()
+	return err
+}
+
+func (s *Server) spawn(req request) (*ptySession, error) {
+	master, slave, err := openPTY(defaultRows, defaultCols)
+	if err != nil {
+		return nil, err
+	}
+	defer slave.Close()
+
+	var cmd *exec.Cmd
+	if req.Command == "" {
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		cmd = exec.Command(shell, "-l")
+	} else {
+		cmd = exec.Command("/bin/sh", "-c", req.Command)
+	}
+	cmd.Dir = req.WorkDir
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color", "GT_PTYD_SESSION="+req.Session)
+	for k, v := range req.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	setControllingTTY(cmd)
+	if err := cmd.Start(); err != nil {
+		_ = master.Close()
+		return nil, fmt.Errorf("starting command: %w", err)
+	}
+
+	sess := &ptySession{
+		name:   req.Session,
+		cmd:    cmd,
+		master: master,
+		done:   make(chan struct{}),
+		screen: newScreen(scrollbackLines),
+		env:    make(map[string]string),
+	}
+	for k, v := range req.Env {
+		sess.env[k] = v
+	}
+
+	// The reader drains output until every holder of the slave has exited
+	// (read fails with EIO), so the command's last output is captured.
+	go func() {
+		defer master.Close()
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := master.Read(buf)
+			if n > 0 {
+				sess.mu.Lock()
+				sess.screen.Write(buf[:n])
+				sess.mu.Unlock()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	go func() {
+		_ = cmd.Wait()
+		s.mu.Lock()
+		if s.sessions[sess.name] == sess {
+			delete(s.sessions, sess.name)
+		}
+		s.mu.Unlock()
+		close(sess.done)
+	}()
+	return sess, nil
+}
+
+// kill terminates the session's process group: SIGHUP and SIGTERM first,
+// then SIGKILL if it has not exited after killGrace.
+func (s *Server) kill(sess *ptySession) {
+	pid := sess.cmd.Process.Pid
+	_ = signalGroup(pid, syscall.SIGHUP)
+	_ = signalGroup(pid, syscall.SIGTERM)
+	select {
+	case <-sess.done:
+	case <-time.After(killGrace):
+		_ = signalGroup(pid, syscall.SIGKILL)
+		_ = sess.cmd.Process.Kill()
+		<-sess.done
+	}
+	// Reap stragglers that outlived the group leader.
+	_ = signalGroup(pid, syscall.SIGKILL)
+}
+
+func (s *Server) killAll() {
+	s.mu.Lock()
+	sessions := make([]*ptySession, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		if sess != nil {
+			sessions = append(sessions, sess)
+		}
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, sess := range sessions {
+		wg.Add(1)
+		go func(sess *ptySession) {
+			defer wg.Done()
+			s.kill(sess)
+		}(sess)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,161 @@
+//go:build linux
+
+package ptyd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startTestServer runs a supervisor on a temp socket for the test's lifetime.
+func startTestServer(t *testing.T) *Client {
+	t.Helper()
+	if _, err := os.Stat("/dev/ptmx"); err != nil {
+		t.Skip("no /dev/ptmx")
+	}
+	socket := filepath.Join(t.TempDir(), "ptyd.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(socket).Serve(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	c := NewClient(socket)
+	for i := 0; i < 100 && !c.IsRunning(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !c.IsRunning() {
+		t.Fatal("server did not start")
+	}
+	return c
+}
+
+// waitForOutput polls the session's capture until it contains want.
+func waitForOutput(t *testing.T, c *Client, session, want string) string {
+	t.Helper()
+	var out string
+	for i := 0; i < 200; i++ {
+		out, _ = c.CapturePane(session, 0)
+		if strings.Contains(out, want) {
+			return out
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("output of %s never contained %q:\n%s", session, want, out)
+	return out
+}
+
+func TestServer_SessionLifecycle(t *testing.T) {
+	c := startTestServer(t)
+	workDir := t.TempDir()
+
+	// The command echoes each line it reads, so we can see keys arrive.
+	err := c.NewSessionWithCommandAndEnv("gt-test-nux", workDir,
+		`echo "ready in $(pwd) as $GT_ROLE"; while read line; do echo "got:$line"; done`,
+		map[string]string{"GT_ROLE": "polecat"})
+	if err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := c.NewSessionWithCommand("gt-test-nux", workDir, "true"); err == nil {
+		t.Error("duplicate session name should fail")
+	}
+
+	waitForOutput(t, c, "gt-test-nux", "ready in "+workDir+" as polecat")
+
+	if ok, err := c.HasSession("gt-test-nux"); err != nil || !ok {
+		t.Errorf("HasSession = %v, %v", ok, err)
+	}
+	if names, err := c.ListSessions(); err != nil || len(names) != 1 || names[0] != "gt-test-nux" {
+		t.Errorf("ListSessions = %v, %v", names, err)
+	}
+	if v, err := c.GetEnvironment("gt-test-nux", "GT_ROLE"); err != nil || v != "polecat" {
+		t.Errorf("GetEnvironment(GT_ROLE) = %q, %v", v, err)
+	}
+	if err := c.SetEnvironment("gt-test-nux", "GT_AGENT", "claude"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.GetEnvironment("gt-test-nux", "GT_AGENT"); v != "claude" {
+		t.Errorf("GetEnvironment(GT_AGENT) = %q", v)
+	}
+	if pid, err := c.GetPanePID("gt-test-nux"); err != nil || pid == "0" {
+		t.Errorf("GetPanePID = %q, %v", pid, err)
+	}
+
+	if err := c.SendKeys("gt-test-nux", "hello world"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitForOutput(t, c, "gt-test-nux", "got:hello world")
+
+	if err := c.KillSessionWithProcesses("gt-test-nux"); err != nil {
+		t.Fatalf("KillSessionWithProcesses: %v", err)
+	}
+	if ok, _ := c.HasSession("gt-test-nux"); ok {
+		t.Error("session still exists after kill")
+	}
+	if c.IsAgentAlive("gt-test-nux") {
+		t.Error("IsAgentAlive after kill")
+	}
+}
+
+func TestServer_SessionEndsWithCommand(t *testing.T) {
+	c := startTestServer(t)
+	if err := c.NewSessionWithCommand("short", t.TempDir(), "exit 0"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		if ok, _ := c.HasSession("short"); !ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("session did not end when its command exited")
+}
+
+func TestServer_CtrlCInterrupts(t *testing.T) {
+	c := startTestServer(t)
+	if err := c.NewSessionWithCommand("sleepy", t.TempDir(), "echo started; sleep 60"); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, c, "sleepy", "started")
+	if err := c.SendKeysRaw("sleepy", "C-c"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		if ok, _ := c.HasSession("sleepy"); !ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("C-c did not interrupt the session's command")
+}
+
+func TestClient_NoServer(t *testing.T) {
+	c := NewClient(filepath.Join(t.TempDir(), "none.sock"))
+	if ok, err := c.HasSession("x"); ok || err != nil {
+		t.Errorf("HasSession without server = %v, %v; want false, nil", ok, err)
+	}
+	if names, err := c.ListSessions(); names != nil || err != nil {
+		t.Errorf("ListSessions without server = %v, %v", names, err)
+	}
+	if _, err := c.CapturePane("x", 10); !errors.Is(err, ErrNoServer) {
+		t.Errorf("CapturePane without server = %v, want ErrNoServer", err)
+	}
+
+	old := startServer
+	defer func() { startServer = old }()
+	started := ""
+	startServer = func(socket string) error {
+		started = socket
+		return errors.New("cannot start")
+	}
+	if err := c.NewSessionWithCommand("x", t.TempDir(), "true"); err == nil || started != c.Socket() {
+		t.Errorf("NewSessionWithCommand without server: err=%v started=%q", err, started)
+	}
+}
@@ -220,6 +220,27 @@ func (r *Rotator) executeOne(state *config.QuotaState, mu *sync.Mutex, session,
 		return result
 	}
 
+	// 9. Respawn with new account.
+	if err := r.restartPane(session, pane, respawnCmd); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	// 10. Update in-memory quota state (no disk I/O here).
+	// Lock only for the map mutation — tmux I/O above runs lock-free.
+	mu.Lock()
+	existing := state.Accounts[newAccount]
+	existing.LastUsed = time.Now().UTC().Format(time.RFC3339)
+	state.Accounts[newAccount] = existing
+	mu.Unlock()
+
+	result.Rotated = true
+	return result
+}
+
+// restartPane kills the pane's processes and respawns it with respawnCmd.
+// Only the respawn itself is fatal; the surrounding steps log warnings.
+func (r *Rotator) restartPane(session, pane, respawnCmd string) error {
 	// Set remain-on-exit to prevent pane destruction during restart.
 	if err := r.tmuxExec.SetRemainOnExit(pane, true); err != nil {
 		r.log.Warn("could not set remain-on-exit for %s: %v", session, err)
@@ -235,25 +256,13 @@ func (r *Rotator) executeOne(state *config.QuotaState, mu *sync.Mutex, session,
 		r.log.Warn("could not clear history for %s: %v", session, err)
 	}
 
-	// Respawn with new account.
 	if err := r.tmuxExec.RespawnPane(pane, respawnCmd); err != nil {
-		result.Error = fmt.Sprintf("respawning pane: %v", err)
-		return result
+		return fmt.Errorf("respawning pane: %v", err)
 	}
 
-	// 9. Accept startup dialogs (non-critical).
+	// Accept startup dialogs (non-critical).
 	if err := r.tmuxExec.AcceptStartupDialogs(session); err != nil {
 		r.log.Warn("could not accept startup dialogs for %s: %v", session, err)
 	}
-
-	// 10. Update in-memory quota state (no disk I/O here).
-	// Lock only for the map mutation — tmux I/O above runs lock-free.
-	mu.Lock()
-	existing := state.Accounts[newAccount]
-	existing.LastUsed = time.Now().UTC().Format(time.RFC3339)
-	state.Accounts[newAccount] = existing
-	mu.Unlock()
-
-	result.Rotated = true
-	return result
+	return nil
 }
@@ -0,0 +1,105 @@
+package quota
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// FailoverAssignment moves a rate-limited session to another agent preset.
+type FailoverAssignment struct {
+	Session   string `json:"session"`
+	FromAgent string `json:"from_agent"`
+	ToAgent   string `json:"to_agent"`
+}
+
+// FailoverResult holds the result of failing over a single session.
+type FailoverResult struct {
+	Session    string `json:"session"`
+	FromAgent  string `json:"from_agent"`
+	ToAgent    string `json:"to_agent"`
+	FailedOver bool   `json:"failed_over"`
+	Error      string `json:"error,omitempty"`
+}
+
+// PlanFailover picks a fallback agent for every limited session the rotation
+// plan could not place, i.e. every account for its agent is exhausted.
+// A session's agent is its GT_AGENT, or defaultAgent if unset. fallbackFor
+// returns the preset to fail over to, or "" to leave the session waiting.
+func PlanFailover(plan *RotatePlan, tmux TmuxClient, defaultAgent string, fallbackFor func(agent string) string) []FailoverAssignment {
+	var assignments []FailoverAssignment
+	for _, r := range plan.LimitedSessions {
+		if _, rotating := plan.Assignments[r.Session]; rotating {
+			continue
+		}
+		agent := defaultAgent
+		if val, err := tmux.GetEnvironment(r.Session, "GT_AGENT"); err == nil && strings.TrimSpace(val) != "" {
+			agent = strings.TrimSpace(val)
+		}
+		to := fallbackFor(agent)
+		if to == "" || to == agent {
+			continue
+		}
+		assignments = append(assignments, FailoverAssignment{Session: r.Session, FromAgent: agent, ToAgent: to})
+	}
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].Session < assignments[j].Session })
+	return assignments
+}
+
+// Failover restarts each assigned session on its fallback agent. The session
+// keeps its hooked work: restartCmd builds the same startup command a
+// handoff would, with the agent overridden. Sessions restart concurrently.
+func (r *Rotator) Failover(assignments []FailoverAssignment, restartCmd func(session, agent string) (string, error)) []FailoverResult {
+	results := make([]FailoverResult, len(assignments))
+	var wg sync.WaitGroup
+	for i, a := range assignments {
+		wg.Add(1)
+		go func(i int, a FailoverAssignment) {
+			defer wg.Done()
+			results[i] = r.failoverOne(a, restartCmd)
+		}(i, a)
+	}
+	wg.Wait()
+	return results
+}
+
+func (r *Rotator) failoverOne(a FailoverAssignment, restartCmd func(session, agent string) (string, error)) FailoverResult {
+	result := FailoverResult{Session: a.Session, FromAgent: a.FromAgent, ToAgent: a.ToAgent}
+
+	// --- Validation phase: read-only, no side effects ---
+
+	respawnCmd, err := restartCmd(a.Session, a.ToAgent)
+	if err != nil {
+		result.Error = fmt.Sprintf("building restart command: %v", err)
+		return result
+	}
+	pane, err := r.tmuxExec.GetPaneID(a.Session)
+	if err != nil {
+		result.Error = fmt.Sprintf("getting pane: %v", err)
+		return result
+	}
+
+	// --- Mutation phase ---
+
+	// Liveness checks read the agent and its process names from the session
+	// environment, so both must follow the agent switch.
+	if err := r.tmuxExec.SetEnvironment(a.Session, "GT_AGENT", a.ToAgent); err != nil {
+		result.Error = fmt.Sprintf("setting GT_AGENT: %v", err)
+		return result
+	}
+	processNames := strings.Join(config.ResolveProcessNames(a.ToAgent, ""), ",")
+	if err := r.tmuxExec.SetEnvironment(a.Session, "GT_PROCESS_NAMES", processNames); err != nil {
+		r.log.Warn("could not set GT_PROCESS_NAMES for %s: %v", a.Session, err)
+	}
+
+	if err := r.restartPane(a.Session, pane, respawnCmd); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.FailedOver = true
+	return result
+}
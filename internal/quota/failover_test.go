@@ -0,0 +1,92 @@
+package quota
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPlanFailover(t *testing.T) {
+	tmux := &mockTmux{
+		envVars: map[string]map[string]string{
+			"gt-crew-bear":  {"GT_AGENT": "claude"},
+			"gt-crew-wolf":  {},
+			"gt-crew-crow":  {"GT_AGENT": "codex"},
+			"gt-crew-moose": {"GT_AGENT": "claude"},
+		},
+	}
+	plan := &RotatePlan{
+		LimitedSessions: []ScanResult{
+			{Session: "gt-crew-wolf", RateLimited: true},
+			{Session: "gt-crew-bear", RateLimited: true},
+			{Session: "gt-crew-crow", RateLimited: true},
+			{Session: "gt-crew-moose", RateLimited: true},
+		},
+		Assignments: map[string]string{"gt-crew-moose": "personal"},
+	}
+	fallback := &config.QuotaConfig{Fallback: map[string]string{"claude": "gemini"}}
+
+	got := PlanFailover(plan, tmux, "claude", fallback.FallbackFor)
+
+	// moose rotates to an account, crow has no fallback for codex.
+	want := []FailoverAssignment{
+		{Session: "gt-crew-bear", FromAgent: "claude", ToAgent: "gemini"},
+		{Session: "gt-crew-wolf", FromAgent: "claude", ToAgent: "gemini"},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("PlanFailover = %v, want %v", got, want)
+	}
+}
+
+func TestFailover_RestartsOnFallbackAgent(t *testing.T) {
+	exec := newMockExecutor()
+	exec.paneIDs["gt-crew-bear"] = "%0"
+	log := &mockLogger{}
+	rotator := NewRotator(&mockTmux{}, exec, nil, &config.AccountsConfig{}, nil, log, "", "", nil)
+
+	results := rotator.Failover(
+		[]FailoverAssignment{
+			{Session: "gt-crew-bear", FromAgent: "claude", ToAgent: "gemini"},
+			{Session: "gt-crew-gone", FromAgent: "claude", ToAgent: "gemini"},
+		},
+		func(session, agent string) (string, error) { return "exec " + agent, nil },
+	)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !results[0].FailedOver {
+		t.Errorf("expected gt-crew-bear to fail over, error=%s", results[0].Error)
+	}
+	if results[1].FailedOver || !strings.Contains(results[1].Error, "getting pane") {
+		t.Errorf("expected pane error for missing session, got %+v", results[1])
+	}
+	if got := exec.respawned["%0"]; got != "exec gemini" {
+		t.Errorf("expected respawn with fallback command, got %q", got)
+	}
+	if got := exec.envSets["gt-crew-bear"]["GT_AGENT"]; got != "gemini" {
+		t.Errorf("expected GT_AGENT=gemini, got %q", got)
+	}
+	if exec.envSets["gt-crew-bear"]["GT_PROCESS_NAMES"] == "" {
+		t.Error("expected GT_PROCESS_NAMES to be updated")
+	}
+}
+
+func TestFailover_RestartCommandError(t *testing.T) {
+	exec := newMockExecutor()
+	exec.paneIDs["gt-crew-bear"] = "%0"
+	rotator := NewRotator(&mockTmux{}, exec, nil, &config.AccountsConfig{}, nil, &mockLogger{}, "", "", nil)
+
+	results := rotator.Failover(
+		[]FailoverAssignment{{Session: "gt-crew-bear", FromAgent: "claude", ToAgent: "gemini"}},
+		func(string, string) (string, error) { return "", fmt.Errorf("no such agent") },
+	)
+	if results[0].FailedOver || !strings.Contains(results[0].Error, "no such agent") {
+		t.Errorf("expected restart command error, got %+v", results[0])
+	}
+	if len(exec.envSets) != 0 || len(exec.respawned) != 0 {
+		t.Error("expected no tmux mutation when validation fails")
+	}
+}
@@ -0,0 +1,91 @@
+package quota
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// resetZonePattern matches a parenthesized IANA zone, e.g. "(America/Los_Angeles)".
+var resetZonePattern = regexp.MustCompile(`\(([^)]+)\)`)
+
+// resetZoneAbbrevs maps the zone abbreviations providers print to IANA zones.
+// time.LoadLocation cannot resolve abbreviations like "PST" on its own.
+var resetZoneAbbrevs = map[string]string{
+	"UTC": "UTC",
+	"GMT": "UTC",
+	"PST": "America/Los_Angeles",
+	"PDT": "America/Los_Angeles",
+	"MST": "America/Denver",
+	"MDT": "America/Denver",
+	"CST": "America/Chicago",
+	"CDT": "America/Chicago",
+	"EST": "America/New_York",
+	"EDT": "America/New_York",
+}
+
+// ResolveResetTime resolves a provider reset hint such as
+// "7pm (America/Los_Angeles)" or "3:00 AM PST" to the first matching instant
+// after limitedAt. Hints without a zone use local time. Returns false if the
+// hint has no recognizable time of day.
+func ResolveResetTime(hint string, limitedAt time.Time) (time.Time, bool) {
+	hint = strings.TrimSpace(hint)
+	if hint == "" {
+		return time.Time{}, false
+	}
+
+	loc := time.Local
+	if m := resetZonePattern.FindStringSubmatch(hint); m != nil {
+		l, err := time.LoadLocation(strings.TrimSpace(m[1]))
+		if err != nil {
+			return time.Time{}, false
+		}
+		loc = l
+		hint = strings.TrimSpace(resetZonePattern.ReplaceAllString(hint, ""))
+	}
+	if fields := strings.Fields(hint); len(fields) > 1 {
+		if zone, ok := resetZoneAbbrevs[strings.ToUpper(fields[len(fields)-1])]; ok {
+			l, err := time.LoadLocation(zone)
+			if err != nil {
+				return time.Time{}, false
+			}
+			loc = l
+			hint = strings.Join(fields[:len(fields)-1], " ")
+		}
+	}
+
+	clock := strings.ToLower(strings.ReplaceAll(hint, " ", ""))
+	var parsed time.Time
+	var err error
+	for _, layout := range []string{"3pm", "3:04pm", "15:04"} {
+		if parsed, err = time.Parse(layout, clock); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := limitedAt.In(loc)
+	reset := time.Date(local.Year(), local.Month(), local.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc)
+	if !reset.After(limitedAt) {
+		reset = reset.AddDate(0, 0, 1)
+	}
+	return reset, true
+}
+
+// ResetDeadline returns when a limited account becomes available again: the
+// parsed ResetsAt hint, or LimitedAt plus cooldown when the hint is missing
+// or unrecognized. Returns false if LimitedAt is unknown.
+func ResetDeadline(st config.AccountQuotaState, cooldown time.Duration) (time.Time, bool) {
+	limitedAt, err := time.Parse(time.RFC3339, st.LimitedAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if reset, ok := ResolveResetTime(st.ResetsAt, limitedAt); ok {
+		return reset, true
+	}
+	return limitedAt.Add(cooldown), true
+}
@@ -0,0 +1,66 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestResolveResetTime(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	limitedAt := time.Date(2026, 3, 10, 15, 30, 0, 0, la) // 3:30pm PDT
+
+	tests := []struct {
+		hint string
+		want time.Time
+	}{
+		{"7pm (America/Los_Angeles)", time.Date(2026, 3, 10, 19, 0, 0, 0, la)},
+		{"3:00 AM PST", time.Date(2026, 3, 11, 3, 0, 0, 0, la)},
+		{"3pm (America/Los_Angeles)", time.Date(2026, 3, 11, 15, 0, 0, 0, la)}, // already passed today
+		{"23:30 UTC", time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, ok := ResolveResetTime(tt.hint, limitedAt)
+		if !ok {
+			t.Errorf("ResolveResetTime(%q) failed", tt.hint)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ResolveResetTime(%q) = %v, want %v", tt.hint, got, tt.want)
+		}
+	}
+
+	for _, hint := range []string{"", "soon", "7pm (Mars/Olympus)"} {
+		if _, ok := ResolveResetTime(hint, limitedAt); ok {
+			t.Errorf("ResolveResetTime(%q) should fail", hint)
+		}
+	}
+}
+
+func TestResetDeadline(t *testing.T) {
+	st := config.AccountQuotaState{
+		Status:    config.QuotaStatusLimited,
+		LimitedAt: "2026-03-10T12:00:00Z",
+		ResetsAt:  "14:00 UTC",
+	}
+	got, ok := ResetDeadline(st, time.Hour)
+	if !ok || !got.Equal(time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("ResetDeadline with hint = %v, %v", got, ok)
+	}
+
+	// Unparseable hint falls back to the cooldown.
+	st.ResetsAt = "later"
+	got, ok = ResetDeadline(st, time.Hour)
+	if !ok || !got.Equal(time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("ResetDeadline with cooldown = %v, %v", got, ok)
+	}
+
+	st.LimitedAt = ""
+	if _, ok := ResetDeadline(st, time.Hour); ok {
+		t.Error("expected no deadline without LimitedAt")
+	}
+}
@@ -1,8 +1,10 @@
 // Package quota manages Claude Code account quota rotation for Gas Town.
 //
 // When sessions hit rate limits, the overseer can scan for blocked sessions
-// and rotate them to available accounts. State is persisted to mayor/quota.json
-// with crash-safe atomic writes and file-level locking.
+// and rotate them to available accounts. The daemon's opt-in quota patrol does
+// the same unattended, releasing accounts at their reset time and failing over
+// to a fallback agent when every account is limited. State is persisted to
+// mayor/quota.json with crash-safe atomic writes and file-level locking.
 package quota
 
 import (
@@ -10,6 +12,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/gofrs/flock"
@@ -204,3 +207,81 @@ func (m *Manager) EnsureAccountsTracked(state *config.QuotaState, accounts map[s
 		}
 	}
 }
+
+// RecordLimits marks the accounts of rate-limited scan results as limited.
+// LimitedAt is kept for accounts that were already limited, so a session
+// still showing the same limit message does not push the reset back.
+func (m *Manager) RecordLimits(results []ScanResult, now time.Time) error {
+	return m.WithLock(func() error {
+		state, err := m.Load()
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		for _, r := range results {
+			if !r.RateLimited || r.AccountHandle == "" {
+				continue
+			}
+			existing := state.Accounts[r.AccountHandle]
+			updated := existing
+			if existing.Status != config.QuotaStatusLimited || existing.LimitedAt == "" {
+				updated.Status = config.QuotaStatusLimited
+				updated.LimitedAt = now.UTC().Format(time.RFC3339)
+				updated.ResetsAt = r.ResetsAt
+			} else if r.ResetsAt != "" {
+				updated.ResetsAt = r.ResetsAt
+			}
+			if updated != existing {
+				state.Accounts[r.AccountHandle] = updated
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return m.SaveUnlocked(state)
+	})
+}
+
+// ReleaseExpired marks limited and cooldown accounts available once their
+// reset deadline (see ResetDeadline) has passed. Accounts limited without a
+// LimitedAt get one now, starting their cooldown. Returns the released handles.
+func (m *Manager) ReleaseExpired(now time.Time, cooldown time.Duration) ([]string, error) {
+	var released []string
+	err := m.WithLock(func() error {
+		state, err := m.Load()
+		if err != nil {
+			return err
+		}
+
+		changed := false
+		for handle, st := range state.Accounts {
+			if st.Status != config.QuotaStatusLimited && st.Status != config.QuotaStatusCooldown {
+				continue
+			}
+			deadline, ok := ResetDeadline(st, cooldown)
+			if !ok {
+				st.LimitedAt = now.UTC().Format(time.RFC3339)
+				state.Accounts[handle] = st
+				changed = true
+				continue
+			}
+			if now.Before(deadline) {
+				continue
+			}
+			state.Accounts[handle] = config.AccountQuotaState{
+				Status:   config.QuotaStatusAvailable,
+				LastUsed: st.LastUsed,
+			}
+			released = append(released, handle)
+			changed = true
+		}
+		if !changed {
+			return nil
+		}
+		return m.SaveUnlocked(state)
+	})
+	sort.Strings(released)
+	return released, err
+}
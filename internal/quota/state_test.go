@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -388,3 +389,79 @@ func TestSaveCreatesDirectory(t *testing.T) {
 		t.Fatalf("parsing saved file: %v", err)
 	}
 }
+
+func TestRecordLimits_KeepsLimitedAt(t *testing.T) {
+	townRoot := setupTestTown(t)
+	mgr := NewManager(townRoot)
+
+	first := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	results := []ScanResult{
+		{Session: "gt-crew-bear", AccountHandle: "work", RateLimited: true, ResetsAt: "7pm"},
+		{Session: "gt-witness", AccountHandle: "personal"},
+	}
+	if err := mgr.RecordLimits(results, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.RecordLimits(results, first.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := mgr.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := state.Accounts["work"]
+	if work.Status != config.QuotaStatusLimited {
+		t.Errorf("expected work limited, got %q", work.Status)
+	}
+	if work.LimitedAt != "2026-03-10T12:00:00Z" {
+		t.Errorf("expected LimitedAt kept from first detection, got %q", work.LimitedAt)
+	}
+	if work.ResetsAt != "7pm" {
+		t.Errorf("expected ResetsAt=7pm, got %q", work.ResetsAt)
+	}
+	if _, ok := state.Accounts["personal"]; ok {
+		t.Error("expected personal (not limited) to be left alone")
+	}
+}
+
+func TestReleaseExpired(t *testing.T) {
+	townRoot := setupTestTown(t)
+	mgr := NewManager(townRoot)
+
+	state := &config.QuotaState{
+		Accounts: map[string]config.AccountQuotaState{
+			"reset":   {Status: config.QuotaStatusLimited, LimitedAt: "2026-03-10T12:00:00Z", ResetsAt: "13:00 UTC", LastUsed: "2026-03-10T11:00:00Z"},
+			"waiting": {Status: config.QuotaStatusLimited, LimitedAt: "2026-03-10T12:00:00Z", ResetsAt: "18:00 UTC"},
+			"cooled":  {Status: config.QuotaStatusCooldown, LimitedAt: "2026-03-10T08:00:00Z"},
+			"unknown": {Status: config.QuotaStatusLimited},
+			"free":    {Status: config.QuotaStatusAvailable},
+		},
+	}
+	if err := mgr.Save(state); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	released, err := mgr.ReleaseExpired(now, 5*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(released) != 2 || released[0] != "cooled" || released[1] != "reset" {
+		t.Errorf("expected [cooled reset] released, got %v", released)
+	}
+
+	state, err = mgr.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Accounts["reset"]; got.Status != config.QuotaStatusAvailable || got.LastUsed != "2026-03-10T11:00:00Z" || got.ResetsAt != "" {
+		t.Errorf("unexpected reset account state: %+v", got)
+	}
+	if got := state.Accounts["waiting"]; got.Status != config.QuotaStatusLimited {
+		t.Errorf("expected waiting still limited, got %q", got.Status)
+	}
+	if got := state.Accounts["unknown"]; got.Status != config.QuotaStatusLimited || got.LimitedAt != "2026-03-10T14:00:00Z" {
+		t.Errorf("expected unknown to start its cooldown now, got %+v", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -18,12 +19,18 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/forge"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/routing"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DefaultStaleClaimTimeout is the default duration after which a claimed MR
@@ -76,8 +83,27 @@ type MergeQueueConfig struct {
 	Enabled bool `json:"enabled"`
 
 	// OnConflict is the strategy for handling conflicts: "assign_back" or "auto_rebase".
+	// With "auto_rebase" the Engineer rebases the branch onto the target itself and
+	// only assigns the conflict back when the rebase cannot be completed cleanly.
 	OnConflict string `json:"on_conflict"`
 
+	// MergeStrategy is how branches land on their target: "squash" (default),
+	// "merge-no-ff", "rebase-ff" or "ff-only". See MergeWithStrategy.
+	MergeStrategy string `json:"merge_strategy"`
+
+	// CommitTemplate is a text/template for squash and merge-no-ff commit
+	// messages, rendered against CommitMessageData. Empty keeps the branch's
+	// last commit message.
+	CommitTemplate string `json:"commit_template"`
+
+	// CommitTrailers lists trailers ("bead", "co-authored-by") appended to
+	// squash and merge-no-ff commit messages.
+	CommitTrailers []string `json:"commit_trailers"`
+
+	// Forge, when set, lands branches through pull requests on the forge
+	// instead of pushing merges to the target. See doForgeMerge.
+	Forge *config.ForgeConfig `json:"forge,omitempty"`
+
 	// RunTests controls whether to run tests before merging.
 	RunTests bool `json:"run_tests"`
 
@@ -94,6 +120,8 @@ type MergeQueueConfig struct {
 	PollInterval time.Duration `json:"poll_interval"`
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
+	// It sets the merge train size: how many ready MRs are stacked and
+	// gated together by ProcessTrain.
 	MaxConcurrent int `json:"max_concurrent"`
 
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
@@ -118,7 +146,8 @@ type MergeQueueConfig struct {
 func DefaultMergeQueueConfig() *MergeQueueConfig {
 	return &MergeQueueConfig{
 		Enabled:              true,
-		OnConflict:           "assign_back",
+		OnConflict:           config.OnConflictAssignBack,
+		MergeStrategy:        config.MergeStrategySquash,
 		RunTests:             true,
 		TestCommand:          "",
 		DeleteMergedBranches: true,
@@ -141,11 +170,14 @@ type MRInfo struct {
 	Title           string     // MR title
 	Priority        int        // Priority (lower = higher priority)
 	AgentBead       string     // Agent bead ID that created this MR
+	AgentPreset     string     // Agent preset the worker ran
+	Formula         string     // Formula the work ran under
 	RetryCount      int        // Conflict retry count
 	ConvoyID        string     // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	TraceParent     string     // Trace of the work (W3C traceparent), joined by the merge
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
@@ -196,6 +228,7 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	forge                 forge.Forge   // Forge client in forge mode (built lazily from config.Forge)
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -269,6 +302,10 @@ func (e *Engineer) LoadConfig() error {
 	var mqRaw struct {
 		Enabled              *bool                      `json:"enabled"`
 		OnConflict           *string                    `json:"on_conflict"`
+		MergeStrategy        *string                    `json:"merge_strategy"`
+		CommitTemplate       *string                    `json:"commit_template"`
+		CommitTrailers       []string                   `json:"commit_trailers"`
+		Forge                *config.ForgeConfig        `json:"forge"`
 		RunTests             *bool                      `json:"run_tests"`
 		TestCommand          *string                    `json:"test_command"`
 		DeleteMergedBranches *bool                      `json:"delete_merged_branches"`
@@ -291,6 +328,34 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.OnConflict != nil {
 		e.config.OnConflict = *mqRaw.OnConflict
 	}
+	if mqRaw.MergeStrategy != nil {
+		if *mqRaw.MergeStrategy != "" && !slices.Contains(config.MergeStrategies, *mqRaw.MergeStrategy) {
+			return fmt.Errorf("invalid merge_strategy %q (want one of %s)",
+				*mqRaw.MergeStrategy, strings.Join(config.MergeStrategies, ", "))
+		}
+		e.config.MergeStrategy = *mqRaw.MergeStrategy
+	}
+	if mqRaw.CommitTemplate != nil {
+		if _, err := RenderCommitMessage(*mqRaw.CommitTemplate, nil, CommitMessageData{}); err != nil {
+			return fmt.Errorf("invalid commit_template: %w", err)
+		}
+		e.config.CommitTemplate = *mqRaw.CommitTemplate
+	}
+	for _, t := range mqRaw.CommitTrailers {
+		if t != config.CommitTrailerBead && t != config.CommitTrailerCoAuthoredBy {
+			return fmt.Errorf("invalid commit_trailers entry %q (want %q or %q)",
+				t, config.CommitTrailerBead, config.CommitTrailerCoAuthoredBy)
+		}
+	}
+	if mqRaw.CommitTrailers != nil {
+		e.config.CommitTrailers = mqRaw.CommitTrailers
+	}
+	if mqRaw.Forge != nil {
+		if err := mqRaw.Forge.Validate(); err != nil {
+			return err
+		}
+		e.config.Forge = mqRaw.Forge
+	}
 	if mqRaw.RunTests != nil {
 		e.config.RunTests = *mqRaw.RunTests
 	}
@@ -363,16 +428,36 @@ func (e *Engineer) Config() *MergeQueueConfig {
 
 // ProcessResult contains the result of processing a merge request.
 type ProcessResult struct {
-	Success     bool
-	MergeCommit string
-	Error       string
-	Conflict    bool
-	TestsFailed bool
-	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	Success     bool   `json:"success"`
+	MergeCommit string `json:"merge_commit,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Conflict    bool   `json:"conflict,omitempty"`
+	TestsFailed bool   `json:"tests_failed,omitempty"`
+	SlotTimeout bool   `json:"slot_timeout,omitempty"` // Merge slot contention timeout (distinct from build/test failure)
+	Rebased     bool   `json:"rebased,omitempty"`      // Branch was auto-rebased onto the target before merging
+
+	// Failure classifies a failed result when the flags above don't say
+	// enough (forge mode: failed checks, rejected reviews, pending PRs).
+	Failure FailureType `json:"failure_type,omitempty"`
+
+	// PullRequest is the forge pull request URL (forge mode).
+	PullRequest string `json:"pull_request,omitempty"`
+}
+
+// err returns the result's failure as an error, or nil on success.
+func (r ProcessResult) err() error {
+	if r.Success {
+		return nil
+	}
+	return errors.New(r.Error)
 }
 
 // doMerge performs the actual git merge operation.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+func (e *Engineer) doMerge(ctx context.Context, mr *MRInfo) ProcessResult {
+	if e.config.Forge != nil {
+		return e.doForgeMerge(ctx, mr)
+	}
+	branch, target := mr.Branch, mr.Target
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
@@ -414,84 +499,82 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			Error:    fmt.Sprintf("conflict check failed: %v", err),
 		}
 	}
-	if len(conflicts) > 0 {
-		return ProcessResult{
-			Success:  false,
-			Conflict: true,
-			Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
-		}
-	}
-
-	// Step 3.5: Push submodule commits if the branch changes submodule pointers.
-	// The refinery owns all remote pushes — submodule commits must land before the
-	// parent pointer is merged, otherwise main gets dangling submodule references.
-	subChanges, err := e.git.SubmoduleChanges(target, branch)
-	if err != nil {
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check submodule changes: %v\n", err)
-	}
-	if len(subChanges) > 0 {
-		// Ensure submodules are initialized in the refinery worktree
-		if initErr := git.InitSubmodules(e.git.WorkDir()); initErr != nil {
+	// ff-only can only land a branch that already contains the target, so a
+	// branch that is merely behind needs a rebase just like a conflicting one.
+	strategy := e.mergeStrategy()
+	behind := false
+	if len(conflicts) == 0 && strategy == config.MergeStrategyFFOnly {
+		contains, err := e.git.IsAncestor(target, branch)
+		if err != nil {
 			return ProcessResult{
 				Success: false,
-				Error:   fmt.Sprintf("failed to init submodules in refinery worktree: %v", initErr),
+				Error:   fmt.Sprintf("failed to check whether %s contains %s: %v", branch, target, err),
 			}
 		}
-		for _, sc := range subChanges {
-			if sc.NewSHA == "" {
-				continue // Submodule removed, nothing to push
+		behind = !contains
+	}
+
+	// mergeRef is what actually gets merged: the branch itself, or the
+	// rebased commit when auto_rebase resolved the conflicts.
+	mergeRef := branch
+	rebased := false
+	if len(conflicts) > 0 || behind {
+		if e.config.OnConflict != config.OnConflictAutoRebase {
+			if behind {
+				return needsRebaseResult(branch, target)
 			}
-			_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing submodule %s (commit %s)...\n", sc.Path, sc.NewSHA[:8])
-			if pushErr := e.git.PushSubmoduleCommit(sc.Path, sc.NewSHA, "origin"); pushErr != nil {
-				return ProcessResult{
-					Success: false,
-					Error:   fmt.Sprintf("failed to push submodule %s: %v", sc.Path, pushErr),
-				}
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
 			}
 		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
+		if behind {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] %s is behind %s, attempting auto-rebase for ff-only...\n", branch, target)
+		} else {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Conflicts in %v, attempting auto-rebase onto %s...\n", conflicts, target)
+		}
+		rebasedSHA, rebaseResult := e.autoRebase(ctx, branch, target)
+		if !rebaseResult.Success {
+			return rebaseResult
+		}
+		mergeRef = rebasedSHA
+		rebased = true
 	}
 
-	// Step 4: Run quality gates (or legacy tests) if configured
-	if len(e.config.Gates) > 0 {
-		// New gates system: run configured quality gates
-		gateResult := e.runGates(ctx)
-		if !gateResult.Success {
-			return gateResult
-		}
-	} else if e.config.RunTests && e.config.TestCommand != "" {
-		// Legacy test command path (backward compatible)
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
-		result := e.runTests(ctx)
-		if !result.Success {
-			return ProcessResult{
-				Success:     false,
-				TestsFailed: true,
-				Error:       result.Error,
-			}
+	// Step 3.5: Push submodule commits if the branch changes submodule pointers.
+	// The refinery owns all remote pushes — submodule commits must land before the
+	// parent pointer is merged, otherwise main gets dangling submodule references.
+	if err := e.pushSubmoduleCommits(target, mergeRef); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   err.Error(),
 		}
-		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
-	// Step 5: Perform the actual merge using squash merge
-	// Get the original commit message from the polecat branch to preserve the
-	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
-	if err != nil {
-		// Fallback to a descriptive message if we can't get the original
-		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
-		if sourceIssue != "" {
-			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
+	// Step 4: Run quality gates (or legacy tests) if configured.
+	// A rebased branch already passed them on the rebased tree in autoRebase.
+	if !rebased {
+		if result := e.runQualityChecks(ctx); !result.Success {
+			return result
 		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
 	}
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
-	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
-		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
-		// GetConflictingFiles() uses `git diff --diff-filter=U` which is proper.
-		conflicts, conflictErr := e.git.GetConflictingFiles()
-		if conflictErr == nil && len(conflicts) > 0 {
-			_ = e.git.AbortMerge()
+
+	// Step 5: Perform the actual merge using the configured strategy
+	var msg string
+	if strategy == config.MergeStrategySquash || strategy == config.MergeStrategyMergeNoFF {
+		msg = e.commitMessage(mr, true)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merging (%s) with message: %s\n", strategy, strings.TrimSpace(msg))
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merging (%s)...\n", strategy)
+	}
+	if conflicts, err := MergeWithStrategy(e.git, strategy, mergeRef, msg); err != nil {
+		if errors.Is(err, ErrNeedsRebase) {
+			return needsRebaseResult(branch, target)
+		}
+		// ZFC: MergeWithStrategy uses git's porcelain output (`git diff --diff-filter=U`)
+		// to detect conflicts instead of parsing stderr.
+		if len(conflicts) > 0 {
 			return ProcessResult{
 				Success:  false,
 				Conflict: true,
@@ -513,16 +596,43 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	// Step 7: Acquire merge slot before push to serialize writes to the default branch.
-	// Only serialize pushes to the rig's default branch (typically main).
-	// Integration-branch and feature-branch pushes don't need serialization.
+	// Steps 7-8: Acquire the merge slot (default branch only) and push to origin
+	if result := e.pushTarget(ctx, target); !result.Success {
+		return result
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
+	return ProcessResult{
+		Success:     true,
+		MergeCommit: mergeCommit,
+		Rebased:     rebased,
+	}
+}
+
+// needsRebaseResult is the result for a branch that ff-only cannot land
+// because it is behind target. It is handled like a conflict: the MR is
+// assigned back (or auto-rebased) rather than retried as is.
+func needsRebaseResult(branch, target string) ProcessResult {
+	return ProcessResult{
+		Success:  false,
+		Conflict: true,
+		Failure:  FailureConflict,
+		Error:    fmt.Sprintf("%s cannot fast-forward: needs a rebase onto %s", branch, target),
+	}
+}
+
+// pushTarget pushes the locally committed target branch to origin. Pushes to
+// the rig's default branch are serialized through the merge slot; integration
+// and feature branch pushes don't need serialization. On failure the local
+// target is reset to origin so a retry does not see stale local commits.
+func (e *Engineer) pushTarget(ctx context.Context, target string) ProcessResult {
 	var pushHolder string
 	if target == e.rig.DefaultBranch() {
 		var slotErr error
 		pushHolder, slotErr = e.acquireMainPushSlot(ctx)
 		if slotErr != nil {
 			// Reset the checked-out target branch to origin to undo the local squash commit.
-			// ResetHard is required because target is the current branch (checked out in Step 2).
+			// ResetHard is required because target is the current branch (checked out by the caller).
 			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after slot failure: %v\n", target, resetErr)
 			}
@@ -546,7 +656,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}()
 	}
 
-	// Step 8: Push to origin
+	// Push to origin
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing to origin/%s...\n", target)
 	if err := e.git.Push("origin", target, false); err != nil {
 		// Reset the checked-out target branch to undo the local squash commit.
@@ -560,10 +670,128 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
-	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
-	return ProcessResult{
-		Success:     true,
-		MergeCommit: mergeCommit,
+	return ProcessResult{Success: true}
+}
+
+// pushSubmoduleCommits pushes the submodule commits referenced by pointer
+// changes between from and to, so they exist on the remote before the parent
+// commit that references them is pushed.
+func (e *Engineer) pushSubmoduleCommits(from, to string) error {
+	subChanges, err := e.git.SubmoduleChanges(from, to)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check submodule changes: %v\n", err)
+	}
+	if len(subChanges) == 0 {
+		return nil
+	}
+
+	// Ensure submodules are initialized in the refinery worktree
+	if initErr := git.InitSubmodules(e.git.WorkDir()); initErr != nil {
+		return fmt.Errorf("failed to init submodules in refinery worktree: %v", initErr)
+	}
+	for _, sc := range subChanges {
+		if sc.NewSHA == "" {
+			continue // Submodule removed, nothing to push
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing submodule %s (commit %s)...\n", sc.Path, sc.NewSHA[:8])
+		if pushErr := e.git.PushSubmoduleCommit(sc.Path, sc.NewSHA, "origin"); pushErr != nil {
+			return fmt.Errorf("failed to push submodule %s: %v", sc.Path, pushErr)
+		}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
+	return nil
+}
+
+// runQualityChecks runs the configured quality gates, or the legacy test
+// command when no gates are defined, against the current working tree.
+// Returns a successful result when nothing is configured.
+func (e *Engineer) runQualityChecks(ctx context.Context) ProcessResult {
+	if len(e.config.Gates) > 0 {
+		// New gates system: run configured quality gates
+		return e.runGates(ctx)
+	}
+	if e.config.RunTests && e.config.TestCommand != "" {
+		// Legacy test command path (backward compatible)
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Running tests: %s\n", e.config.TestCommand)
+		result := e.runTests(ctx)
+		if !result.Success {
+			return ProcessResult{
+				Success:     false,
+				TestsFailed: true,
+				Error:       result.Error,
+			}
+		}
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
+	}
+	return ProcessResult{Success: true}
+}
+
+// autoRebase implements the auto_rebase conflict strategy. It replays branch
+// onto target on a detached HEAD, so the polecat's branch ref (which may still
+// be checked out in its worktree) is never moved, then runs the quality gates
+// against the rebased tree. On success it checks target out again and returns
+// the rebased commit SHA for the caller to merge.
+//
+// If the rebase itself conflicts it is aborted and a Conflict result is
+// returned, which sends the MR down the usual conflict-resolution task path.
+func (e *Engineer) autoRebase(ctx context.Context, branch, target string) (string, ProcessResult) {
+	if err := e.git.CheckoutDetached(branch); err != nil {
+		e.restoreTarget(target)
+		return "", ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("auto-rebase: failed to checkout %s: %v", branch, err),
+		}
+	}
+
+	if err := e.git.Rebase(target); err != nil {
+		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
+		conflicts, conflictErr := e.git.GetConflictingFiles()
+		_ = e.git.AbortRebase()
+		e.restoreTarget(target)
+		if conflictErr == nil && len(conflicts) > 0 {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Auto-rebase conflicts in %v, assigning back\n", conflicts)
+			return "", ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error:    fmt.Sprintf("auto-rebase onto %s conflicts in: %v", target, conflicts),
+			}
+		}
+		return "", ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("auto-rebase onto %s failed: %v", target, err),
+		}
+	}
+
+	rebasedSHA, err := e.git.Rev("HEAD")
+	if err != nil {
+		e.restoreTarget(target)
+		return "", ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("auto-rebase: failed to get rebased SHA: %v", err),
+		}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Rebased %s onto %s: %s\n", branch, target, rebasedSHA[:8])
+
+	// Gates must see the rebased result, not the pre-rebase branch.
+	if result := e.runQualityChecks(ctx); !result.Success {
+		e.restoreTarget(target)
+		return "", result
+	}
+
+	if err := e.git.Checkout(target); err != nil {
+		return "", ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to checkout target %s after rebase: %v", target, err),
+		}
+	}
+	return rebasedSHA, ProcessResult{Success: true}
+}
+
+// restoreTarget checks the target branch back out after an aborted auto-rebase
+// so the refinery worktree is not left on a detached HEAD.
+func (e *Engineer) restoreTarget(target string) {
+	if err := e.git.Checkout(target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to restore %s after auto-rebase: %v\n", target, err)
 	}
 }
 
@@ -683,7 +911,15 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 }
 
 // runGate executes a single quality gate command and returns the result.
-func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) GateResult {
+func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) (result GateResult) {
+	ctx, span := telemetry.StartSpan(ctx, "refinery.gate", attribute.String("gt.gate", name))
+	defer func() {
+		var err error
+		if !result.Success {
+			err = errors.New(result.Error)
+		}
+		telemetry.EndSpan(span, err)
+	}()
 	start := time.Now()
 
 	if strings.TrimSpace(gate.Cmd) == "" {
@@ -840,7 +1076,19 @@ func (e *Engineer) syncCrewWorkspaces() {
 }
 
 // ProcessMRInfo processes a merge request from MRInfo.
-func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult {
+func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) (result ProcessResult) {
+	// The merge joins the trace of the work (sling → polecat → gt done), so
+	// gate and merge time shows up end to end.
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = telemetry.ContextWithTraceParent(ctx, mr.TraceParent)
+	}
+	ctx, span := telemetry.StartSpan(ctx, "refinery.merge",
+		attribute.String("gt.mr", mr.ID),
+		attribute.String("gt.branch", mr.Branch),
+		attribute.String("gt.target", mr.Target),
+	)
+	defer func() { telemetry.EndSpan(span, result.err()) }()
+
 	// MR fields are directly on the struct
 	_, _ = fmt.Fprintln(e.output, "[Engineer] Processing MR:")
 	_, _ = fmt.Fprintf(e.output, "  Branch: %s\n", mr.Branch)
@@ -849,7 +1097,67 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	return e.doMerge(ctx, mr)
+}
+
+// mergeStrategy returns the configured merge strategy (default squash).
+func (e *Engineer) mergeStrategy() string {
+	if e.config.MergeStrategy == "" {
+		return config.MergeStrategySquash
+	}
+	return e.config.MergeStrategy
+}
+
+// commitMessage builds the commit message for merging mr: the branch's last
+// commit message (which keeps the conventional commit format, feat:/fix:),
+// or commit_template rendered with the MR's fields, plus any configured
+// trailers. Unused by rebase-ff and ff-only, which keep the branch's commits.
+func (e *Engineer) commitMessage(mr *MRInfo, warn bool) string {
+	msg, err := e.git.GetBranchCommitMessage(mr.Branch)
+	if err != nil {
+		// Fallback to a descriptive message if we can't get the original
+		msg = fmt.Sprintf("Squash merge %s into %s", mr.Branch, mr.Target)
+		if mr.SourceIssue != "" {
+			msg = fmt.Sprintf("Squash merge %s into %s (%s)", mr.Branch, mr.Target, mr.SourceIssue)
+		}
+		if warn {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
+		}
+	}
+	if e.config.CommitTemplate == "" && len(e.config.CommitTrailers) == 0 {
+		return msg
+	}
+
+	data := CommitMessageData{
+		BeadID:  mr.SourceIssue,
+		Title:   mr.Title,
+		Worker:  mr.Worker,
+		Convoy:  mr.ConvoyID,
+		MRID:    mr.ID,
+		Branch:  mr.Branch,
+		Target:  mr.Target,
+		Message: strings.TrimSpace(msg),
+	}
+	// The MR bead's title is "Merge: <issue>"; templates want the work item's.
+	if e.config.CommitTemplate != "" && mr.SourceIssue != "" && e.beads != nil {
+		if issue, err := e.beads.Show(mr.SourceIssue); err == nil && issue != nil {
+			data.Title = issue.Title
+		}
+	}
+	if mr.Worker != "" {
+		townRoot := filepath.Dir(e.rig.Path)
+		settings, _ := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		data.WorkerEmail = config.AgentEmail(mr.Worker, settings.GetAgentEmailDomain())
+	}
+
+	rendered, err := RenderCommitMessage(e.config.CommitTemplate, e.config.CommitTrailers, data)
+	if err != nil {
+		if warn {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v (using branch commit message)\n", err)
+		}
+		return msg
+	}
+	return rendered
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -929,13 +1237,29 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	// Run convoy check to auto-close and notify subscribers.
 	e.postMergeConvoyCheck(mr)
 
+	// 3.5. Record the outcome for dispatch routing track records
+	outcome := e.newOutcome(mr, routing.ResultMerged)
+	if result.MergeCommit != "" {
+		if files, err := e.git.CommitFiles(result.MergeCommit); err == nil {
+			outcome.Files = files
+		}
+	}
+	e.recordOutcome(outcome)
+
 	// 4. Log success
+	if result.Rebased {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s, auto-rebased)\n", mr.ID, result.MergeCommit)
+		return
+	}
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
-// For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
+// Under the auto_rebase strategy a conflict only reaches here when the
+// Engineer's own rebase attempt conflicted.
+// For slot timeouts and pull requests still pending on the forge, the MR stays
+// in queue for automatic retry without notifying polecats.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	// Slot timeout is transient infrastructure contention — not a build/test/conflict failure.
@@ -947,6 +1271,14 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		return
 	}
 
+	// Likewise a pull request still waiting on checks or branch protection
+	// is not the worker's to fix; the next poll picks it up again.
+	if result.Failure == FailureForgePending {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Waiting on forge: %s - %s\n", mr.ID, result.Error)
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue until the pull request can merge")
+		return
+	}
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
@@ -955,6 +1287,9 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	} else if result.TestsFailed {
 		failureType = "tests"
 	}
+	if result.Failure != FailureNone {
+		failureType = string(result.Failure)
+	}
 	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
@@ -979,6 +1314,11 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// Record the outcome for dispatch routing track records
+	outcome := e.newOutcome(mr, routing.ResultFailed)
+	outcome.FailureType = failureType
+	e.recordOutcome(outcome)
+
 	// Log the failure - MR stays in queue but may be blocked
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 	if mr.BlockedBy != "" {
@@ -988,6 +1328,33 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// newOutcome builds a routing outcome for an MR verdict.
+func (e *Engineer) newOutcome(mr *MRInfo, result string) routing.Outcome {
+	rigName := mr.Rig
+	if rigName == "" {
+		rigName = e.rig.Name
+	}
+	return routing.Outcome{
+		Rig:     rigName,
+		Bead:    mr.SourceIssue,
+		MR:      mr.ID,
+		Worker:  mr.Worker,
+		Agent:   mr.AgentPreset,
+		Formula: mr.Formula,
+		Result:  result,
+		Retries: mr.RetryCount,
+	}
+}
+
+// recordOutcome appends an outcome to the town's routing log. Best-effort:
+// a lost outcome only weakens the track record.
+func (e *Engineer) recordOutcome(o routing.Outcome) {
+	townRoot := filepath.Dir(e.rig.Path)
+	if err := routing.RecordOutcome(townRoot, o); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record routing outcome: %v\n", err)
+	}
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -1162,12 +1529,15 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		Title:           issue.Title,
 		Priority:        issue.Priority,
 		AgentBead:       fields.AgentBead,
+		AgentPreset:     fields.AgentPreset,
+		Formula:         fields.Formula,
 		RetryCount:      fields.RetryCount,
 		ConvoyID:        fields.ConvoyID,
 		ConvoyCreatedAt: convoyCreatedAt,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Assignee:        issue.Assignee,
+		TraceParent:     fields.TraceParent,
 	}
 }
 
@@ -1333,6 +1703,20 @@ func (e *Engineer) ListAllOpenMRs() ([]*MRInfo, error) {
 	return mrs, nil
 }
 
+// QueueDepth returns the number of open merge requests, ready or not.
+// Unlike ListAllOpenMRs it makes a single bd query and no git calls.
+func (e *Engineer) QueueDepth() (int, error) {
+	issues, err := e.beads.List(beads.ListOptions{
+		Status:   "open",
+		Label:    "gt:merge-request",
+		Priority: -1,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("querying beads for merge-requests: %w", err)
+	}
+	return len(issues), nil
+}
+
 // ListQueueAnomalies finds stale claims and orphaned branches in open MRs.
 // This gives Witness/Refinery patrols deterministic signals for deadlock risk.
 func (e *Engineer) ListQueueAnomalies(now time.Time) ([]*MRAnomaly, error) {
@@ -1461,6 +1845,12 @@ func (e *Engineer) postMergeConvoyCheck(mr *MRInfo) {
 		e.landConvoySwarm(townRoot, convoy)
 	}
 
+	// Step 2b: Launch staged convoys that were waiting on the convoys that
+	// just landed (convoy-level dependencies).
+	for _, convoy := range closedConvoys {
+		e.launchDownstreamConvoys(townRoot, convoy.ID)
+	}
+
 	// Step 3: Notify deacon of convoy-eligible merges for immediate feeding.
 	// When the merged MR is part of a convoy, send a structured CONVOY_NEEDS_FEEDING
 	// protocol message so the deacon can immediately feed the next ready issue
@@ -1495,6 +1885,24 @@ func (e *Engineer) notifyDeaconConvoyFeeding(mr *MRInfo) {
 	_ = events.LogFeed(events.TypeMail, e.rig.Name+"/refinery", events.MailPayload("deacon/", "CONVOY_NEEDS_FEEDING "+mr.ConvoyID))
 }
 
+// launchDownstreamConvoys runs `gt convoy check` on a convoy the refinery just
+// closed. For a closed convoy the check launches staged convoys whose upstream
+// convoys have now all landed.
+func (e *Engineer) launchDownstreamConvoys(townRoot, convoyID string) {
+	cmd := exec.Command("gt", "convoy", "check", convoyID)
+	cmd.Dir = townRoot
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: downstream launch check for convoy %s failed: %v\n", convoyID, err)
+		return
+	}
+	if strings.Contains(out.String(), "Launched convoy") {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s", out.String())
+	}
+}
+
 // convoyInfo holds minimal info about a closed convoy for post-merge processing.
 type convoyInfo struct {
 	ID          string
@@ -0,0 +1,180 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func runGitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func commitFile(t *testing.T, dir, name, content, msg string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	runGitCmd(t, dir, "add", name)
+	runGitCmd(t, dir, "commit", "-m", msg)
+	return runGitCmd(t, dir, "rev-parse", "HEAD")
+}
+
+// setupRebaseRepo creates a repo where polecat/nux conflicts with main on a
+// plain merge. The branch's first commit was already cherry-picked onto main,
+// so the conflict is only with the branch's own history and a rebase (which
+// drops the already-applied commit) completes cleanly.
+func setupRebaseRepo(t *testing.T) (*Engineer, string) {
+	t.Helper()
+	dir := t.TempDir()
+	runGitCmd(t, dir, "init", "-b", "main")
+	runGitCmd(t, dir, "config", "user.email", "test@test.com")
+	runGitCmd(t, dir, "config", "user.name", "Test User")
+	commitFile(t, dir, "value.txt", "1\n", "initial")
+
+	runGitCmd(t, dir, "checkout", "-b", "polecat/nux")
+	first := commitFile(t, dir, "value.txt", "2\n", "bump to 2")
+	commitFile(t, dir, "value.txt", "3\n", "bump to 3")
+
+	runGitCmd(t, dir, "checkout", "main")
+	runGitCmd(t, dir, "cherry-pick", "-x", first)
+
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.git = git.NewGit(dir)
+	e.workDir = dir
+	e.config.OnConflict = config.OnConflictAutoRebase
+	e.config.RunTests = false
+	e.output = &bytes.Buffer{}
+	return e, dir
+}
+
+func TestAutoRebase_ResolvesTrivialConflict(t *testing.T) {
+	e, dir := setupRebaseRepo(t)
+	branchBefore := runGitCmd(t, dir, "rev-parse", "polecat/nux")
+
+	conflicts, err := e.git.CheckConflicts("polecat/nux", "main")
+	if err != nil || len(conflicts) == 0 {
+		t.Fatalf("expected merge conflict in fixture, got %v (err %v)", conflicts, err)
+	}
+
+	sha, result := e.autoRebase(context.Background(), "polecat/nux", "main")
+	if !result.Success {
+		t.Fatalf("autoRebase failed: %s", result.Error)
+	}
+
+	if got := runGitCmd(t, dir, "show", sha+":value.txt"); got != "3" {
+		t.Errorf("rebased value.txt = %q, want %q", got, "3")
+	}
+	if parent := runGitCmd(t, dir, "rev-parse", sha+"^"); parent != runGitCmd(t, dir, "rev-parse", "main") {
+		t.Errorf("rebased commit parent = %s, want main", parent)
+	}
+	if head := runGitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); head != "main" {
+		t.Errorf("HEAD = %q after rebase, want main", head)
+	}
+	if after := runGitCmd(t, dir, "rev-parse", "polecat/nux"); after != branchBefore {
+		t.Errorf("polecat branch moved: %s -> %s", branchBefore, after)
+	}
+
+	conflicts, err = e.git.CheckConflicts(sha, "main")
+	if err != nil || len(conflicts) != 0 {
+		t.Errorf("rebased commit should merge cleanly, got %v (err %v)", conflicts, err)
+	}
+}
+
+func TestAutoRebase_ConflictFallsBack(t *testing.T) {
+	e, dir := setupRebaseRepo(t)
+	// A genuine conflict: main rewrites the line the branch's last commit touches.
+	commitFile(t, dir, "value.txt", "main\n", "diverge on main")
+
+	sha, result := e.autoRebase(context.Background(), "polecat/nux", "main")
+	if result.Success || sha != "" {
+		t.Fatalf("expected autoRebase to fail, got sha %q", sha)
+	}
+	if !result.Conflict {
+		t.Errorf("expected Conflict result so the MR is assigned back, got %+v", result)
+	}
+	if !strings.Contains(result.Error, "value.txt") {
+		t.Errorf("error should list conflicting files, got %q", result.Error)
+	}
+	if head := runGitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); head != "main" {
+		t.Errorf("HEAD = %q after aborted rebase, want main", head)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "rebase-merge")); !os.IsNotExist(err) {
+		t.Error("rebase was not aborted")
+	}
+}
+
+func TestAutoRebase_RunsGatesOnRebasedTree(t *testing.T) {
+	e, dir := setupRebaseRepo(t)
+	e.config.Gates = map[string]*GateConfig{
+		"value": {Cmd: `test "$(cat value.txt)" = 3 && exit 1 || exit 0`},
+	}
+
+	_, result := e.autoRebase(context.Background(), "polecat/nux", "main")
+	if result.Success {
+		t.Fatal("expected gate to fail against the rebased tree")
+	}
+	if result.Conflict {
+		t.Error("gate failure must not be reported as a conflict")
+	}
+	if head := runGitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); head != "main" {
+		t.Errorf("HEAD = %q after gate failure, want main", head)
+	}
+}
+
+// setupBehindRepo returns an engineer whose polecat/a branch is cleanly
+// behind main (and origin/main), so ff-only cannot land it as is.
+func setupBehindRepo(t *testing.T, onConflict string) (*Engineer, string, string) {
+	t.Helper()
+	e, dir, origin := setupTrainRepo(t, map[string]string{"polecat/a": "a.txt"})
+	commitFile(t, dir, "main.txt", "main\n", "chore: move main")
+	runGitCmd(t, dir, "push", "origin", "main")
+	e.config.MergeStrategy = config.MergeStrategyFFOnly
+	e.config.OnConflict = onConflict
+	return e, dir, origin
+}
+
+func TestDoMerge_FFOnlyBehindAssignsBack(t *testing.T) {
+	e, _, origin := setupBehindRepo(t, config.OnConflictAssignBack)
+	before := runGitCmd(t, origin, "rev-parse", "main")
+
+	result := e.doMerge(context.Background(), &MRInfo{ID: "mr-a", Branch: "polecat/a", Target: "main"})
+	if result.Success {
+		t.Fatal("expected ff-only to refuse a branch behind main")
+	}
+	if !result.Conflict || result.Failure != FailureConflict {
+		t.Errorf("expected a conflict result so on_conflict applies, got %+v", result)
+	}
+	if after := runGitCmd(t, origin, "rev-parse", "main"); after != before {
+		t.Errorf("origin/main moved: %s -> %s", before, after)
+	}
+}
+
+func TestDoMerge_FFOnlyBehindAutoRebases(t *testing.T) {
+	e, _, origin := setupBehindRepo(t, config.OnConflictAutoRebase)
+
+	result := e.doMerge(context.Background(), &MRInfo{ID: "mr-a", Branch: "polecat/a", Target: "main"})
+	if !result.Success || !result.Rebased {
+		t.Fatalf("expected the branch to be rebased and fast-forwarded, got %+v", result)
+	}
+	log := runGitCmd(t, origin, "log", "--format=%s", "main")
+	if want := "feat: add a.txt\nchore: move main\ninitial"; log != want {
+		t.Errorf("origin/main history = %q, want %q", log, want)
+	}
+}
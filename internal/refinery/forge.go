@@ -0,0 +1,199 @@
+package refinery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/forge"
+)
+
+// forgeClient returns the forge client for forge mode, building it from
+// merge_queue.forge and origin's URL on first use.
+func (e *Engineer) forgeClient() (forge.Forge, error) {
+	if e.forge != nil {
+		return e.forge, nil
+	}
+	remoteURL, _ := e.git.RemoteURL("origin")
+	f, err := forge.New(e.config.Forge, remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	e.forge = f
+	return f, nil
+}
+
+// doForgeMerge lands mr through a pull request instead of pushing to the
+// target (forge mode, for protected branches). It pushes the branch, opens
+// or updates the PR, checks its status once and merges it through the forge
+// API if the required checks passed. The forge's checks replace the local
+// quality gates.
+//
+// Failed checks and change requests come back as FailureChecksFail and
+// FailureReviewRejected for the worker to fix; a PR that is merely not
+// ready yet (checks running, approval missing) is FailureForgePending and
+// stays in the queue to be checked again on the next pass, so the refinery
+// never blocks on CI.
+func (e *Engineer) doForgeMerge(ctx context.Context, mr *MRInfo) ProcessResult {
+	branch, target := mr.Branch, mr.Target
+	cfg := e.config.Forge
+
+	f, err := e.forgeClient()
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("forge: %v", err)}
+	}
+
+	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
+	exists, err := e.git.BranchExists(branch)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("failed to check branch %s: %v", branch, err)}
+	}
+	if !exists {
+		return ProcessResult{Error: fmt.Sprintf("branch %s not found locally", branch)}
+	}
+
+	// Step 2: Push the branch; polecats may have rebased it since the last push.
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing %s to origin...\n", branch)
+	if err := e.git.Push("origin", branch, true); err != nil {
+		return ProcessResult{
+			Error:   fmt.Sprintf("failed to push %s to origin: %v", branch, err),
+			Failure: FailurePushFail,
+		}
+	}
+
+	// Step 3: Open the pull request, or refresh the one already open.
+	pr, created, err := forge.EnsurePullRequest(ctx, f, e.pullRequestSpec(mr))
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("forge: %v", err)}
+	}
+	if created {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Opened pull request #%d: %s\n", pr.Number, pr.URL)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Updated pull request #%d: %s\n", pr.Number, pr.URL)
+	}
+
+	// Step 4: Check the required checks; anything still running is left
+	// for the next pass.
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking status of #%d...\n", pr.Number)
+	st, err := f.Status(ctx, pr.Number)
+	if err != nil {
+		return ProcessResult{Error: fmt.Sprintf("forge: reading pull request #%d: %v", pr.Number, err), PullRequest: pr.URL}
+	}
+	if result, done := e.forgeVerdict(st, cfg.RequiredChecks); done {
+		return result
+	}
+
+	// Step 5: Merge through the forge, pinned to the head the checks ran on.
+	strategy := e.mergeStrategy()
+	opts := forge.MergeOptions{Method: forge.MethodForStrategy(strategy), SHA: st.PR.HeadSHA}
+	opts.Title, opts.Message, _ = strings.Cut(strings.TrimSpace(e.commitMessage(mr, true)), "\n")
+	opts.Message = strings.TrimSpace(opts.Message)
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merging pull request #%d (%s)...\n", pr.Number, opts.Method)
+	mergeCommit, err := f.Merge(ctx, pr.Number, opts)
+	if err != nil {
+		if errors.Is(err, forge.ErrNotMergeable) {
+			// The forge knows why (protection rule, head moved, conflict);
+			// re-check on the next poll rather than guessing here.
+			return forgePending(pr, err.Error())
+		}
+		return ProcessResult{
+			Error:       fmt.Sprintf("forge: merging pull request #%d: %v", pr.Number, err),
+			Failure:     FailurePushFail,
+			PullRequest: pr.URL,
+		}
+	}
+
+	// Step 6: Bring the local target up to date so the merge commit is known locally.
+	if err := e.git.FetchBranch("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin/%s: %v\n", target, err)
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged pull request #%d\n", pr.Number)
+	return ProcessResult{Success: true, MergeCommit: mergeCommit, PullRequest: pr.URL}
+}
+
+// forgeVerdict maps a pull request status to a result when the PR cannot be
+// merged now. done is false when it is ready to merge.
+func (e *Engineer) forgeVerdict(st *forge.Status, required []string) (result ProcessResult, done bool) {
+	pr := st.PR
+	switch {
+	case pr.State == forge.StateMerged:
+		// Merged on the forge by someone else while we waited.
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Pull request #%d was already merged\n", pr.Number)
+		return ProcessResult{Success: true, MergeCommit: pr.MergeCommit, PullRequest: pr.URL}, true
+	case pr.State == forge.StateClosed:
+		return ProcessResult{Error: fmt.Sprintf("pull request #%d was closed without merging", pr.Number), PullRequest: pr.URL}, true
+	}
+	if failed := st.FailedChecks(required); len(failed) > 0 {
+		return ProcessResult{
+			Error:       fmt.Sprintf("checks failed on pull request #%d: %s", pr.Number, strings.Join(failed, ", ")),
+			TestsFailed: true,
+			Failure:     FailureChecksFail,
+			PullRequest: pr.URL,
+		}, true
+	}
+	if st.Review == forge.ReviewChangesRequested {
+		return ProcessResult{
+			Error:       fmt.Sprintf("changes requested on pull request #%d", pr.Number),
+			Failure:     FailureReviewRejected,
+			PullRequest: pr.URL,
+		}, true
+	}
+	if st.Mergeable == forge.MergeableConflict {
+		return ProcessResult{
+			Error:       fmt.Sprintf("pull request #%d conflicts with its base", pr.Number),
+			Conflict:    true,
+			Failure:     FailureConflict,
+			PullRequest: pr.URL,
+		}, true
+	}
+	if pending := st.PendingChecks(required); len(pending) > 0 {
+		return forgePending(pr, "checks still running: "+strings.Join(pending, ", ")), true
+	}
+	switch st.Mergeable {
+	case forge.MergeableUnknown:
+		return forgePending(pr, "forge is still computing mergeability"), true
+	case forge.MergeableBlocked:
+		return forgePending(pr, "blocked by branch protection (approval or up-to-date branch required)"), true
+	}
+	return ProcessResult{}, false
+}
+
+func forgePending(pr *forge.PullRequest, reason string) ProcessResult {
+	return ProcessResult{
+		Error:       fmt.Sprintf("pull request #%d not ready: %s", pr.Number, reason),
+		Failure:     FailureForgePending,
+		PullRequest: pr.URL,
+	}
+}
+
+// pullRequestSpec builds the pull request for mr: titled and described from
+// the source issue bead, with the MR's identifiers appended.
+func (e *Engineer) pullRequestSpec(mr *MRInfo) forge.PullRequestSpec {
+	spec := forge.PullRequestSpec{Head: mr.Branch, Base: mr.Target, Title: mr.Title}
+	var body strings.Builder
+	if mr.SourceIssue != "" && e.beads != nil {
+		if issue, err := e.beads.Show(mr.SourceIssue); err == nil && issue != nil {
+			spec.Title = issue.Title
+			if desc := strings.TrimSpace(issue.Description); desc != "" {
+				body.WriteString(desc)
+				body.WriteString("\n\n---\n")
+			}
+		}
+	}
+	if spec.Title == "" {
+		spec.Title = fmt.Sprintf("Merge %s into %s", mr.Branch, mr.Target)
+	}
+	if mr.SourceIssue != "" {
+		fmt.Fprintf(&body, "Bead: %s\n", mr.SourceIssue)
+	}
+	if mr.ID != "" {
+		fmt.Fprintf(&body, "Merge request: %s\n", mr.ID)
+	}
+	if mr.Worker != "" {
+		fmt.Fprintf(&body, "Worker: %s\n", mr.Worker)
+	}
+	spec.Body = strings.TrimSpace(body.String())
+	return spec
+}
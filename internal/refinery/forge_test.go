@@ -0,0 +1,287 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/forge"
+	"github.com/steveyegge/gastown/internal/forge/forgetest"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// setupForgeEngineer returns an Engineer in forge mode against a fake GitHub,
+// with a local repo (setupStrategyRepo) whose origin is a bare repo.
+func setupForgeEngineer(t *testing.T) (*Engineer, *forgetest.Server, string) {
+	t.Helper()
+	g, dir := setupStrategyRepo(t)
+	origin := filepath.Join(t.TempDir(), "origin.git")
+	runGitCmd(t, dir, "init", "--bare", "-b", "main", origin)
+	runGitCmd(t, dir, "remote", "add", "origin", origin)
+	runGitCmd(t, dir, "push", "origin", "main")
+
+	srv := forgetest.NewServer(t, config.ForgeGitHub, "acme/widgets", "secret")
+	srv.HeadSHA = func(branch string) string {
+		return strings.TrimSpace(runGitCmd(t, origin, "rev-parse", branch))
+	}
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.git = g
+	e.workDir = dir
+	e.beads = nil
+	e.output = &bytes.Buffer{}
+	e.config.Forge = &config.ForgeConfig{
+		Type:           config.ForgeGitHub,
+		URL:            srv.URL(),
+		Repo:           "acme/widgets",
+		RequiredChecks: []string{"ci"},
+	}
+	return e, srv, origin
+}
+
+var forgeMR = &MRInfo{ID: "gt-mr1", Branch: "polecat/nux", Target: "main", SourceIssue: "gt-abc", Worker: "nux", Title: "Merge: gt-abc"}
+
+func TestDoForgeMerge_Success(t *testing.T) {
+	e, srv, origin := setupForgeEngineer(t)
+	// The check goes green on the second pass.
+	polls := 0
+	srv.OnStatus = func(pr *forgetest.PR) {
+		polls++
+		state := forge.CheckPending
+		if polls >= 2 {
+			state = forge.CheckSuccess
+		}
+		pr.Checks = []forge.Check{{Name: "ci", State: state}}
+	}
+
+	// The first pass does not wait for the running check.
+	result := e.ProcessMRInfo(context.Background(), forgeMR)
+	if result.Failure != FailureForgePending || polls != 1 {
+		t.Fatalf("first pass = %+v after %d poll(s), want forge_pending after 1", result, polls)
+	}
+
+	result = e.ProcessMRInfo(context.Background(), forgeMR)
+	if !result.Success {
+		t.Fatalf("expected success, got %+v\n%s", result, e.output)
+	}
+
+	pr, ok := srv.PR(1)
+	if !ok {
+		t.Fatal("no pull request opened")
+	}
+	if pr.Head != "polecat/nux" || pr.Base != "main" || pr.State != forge.StateMerged {
+		t.Errorf("pull request = %+v", pr)
+	}
+	if !strings.Contains(pr.Body, "Bead: gt-abc") || !strings.Contains(pr.Body, "Merge request: gt-mr1") {
+		t.Errorf("body = %q", pr.Body)
+	}
+	if pr.MergeMethod != "squash" || pr.MergeTitle != "feat: add b" {
+		t.Errorf("merged with %q, title %q", pr.MergeMethod, pr.MergeTitle)
+	}
+	if result.MergeCommit != pr.MergeCommit || result.PullRequest == "" {
+		t.Errorf("result = %+v", result)
+	}
+	// The branch was pushed, and the forge merged (not the refinery).
+	if got := runGitCmd(t, origin, "rev-parse", "polecat/nux"); strings.TrimSpace(got) != pr.HeadSHA {
+		t.Errorf("origin polecat/nux = %s, want %s", got, pr.HeadSHA)
+	}
+	if got := runGitCmd(t, origin, "log", "--format=%s", "main"); strings.Contains(got, "feat: add") {
+		t.Errorf("refinery pushed the merge itself:\n%s", got)
+	}
+}
+
+func TestDoForgeMerge_UpdatesExistingPR(t *testing.T) {
+	e, srv, _ := setupForgeEngineer(t)
+	n := srv.AddPR(forgetest.PR{Head: "polecat/nux", Base: "main", Title: "old", Body: "old", Mergeable: forge.MergeableClean})
+	srv.OnStatus = func(pr *forgetest.PR) {
+		pr.HeadSHA = srv.HeadSHA(pr.Head)
+		pr.Checks = []forge.Check{{Name: "ci", State: forge.CheckSuccess}}
+	}
+
+	result := e.ProcessMRInfo(context.Background(), forgeMR)
+	if !result.Success {
+		t.Fatalf("expected success, got %+v", result)
+	}
+	if prs := srv.PRs(); len(prs) != 1 {
+		t.Fatalf("got %d pull requests, want the existing one reused", len(prs))
+	}
+	pr, _ := srv.PR(n)
+	if pr.Title != "Merge: gt-abc" || pr.State != forge.StateMerged {
+		t.Errorf("pull request = %+v", pr)
+	}
+}
+
+func TestDoForgeMerge_Failures(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      func(pr *forgetest.PR)
+		want     FailureType
+		conflict bool
+	}{
+		{
+			name: "checks fail",
+			set: func(pr *forgetest.PR) {
+				pr.Checks = []forge.Check{{Name: "ci", State: forge.CheckFailure}}
+			},
+			want: FailureChecksFail,
+		},
+		{
+			name: "changes requested",
+			set: func(pr *forgetest.PR) {
+				pr.Checks = []forge.Check{{Name: "ci", State: forge.CheckSuccess}}
+				pr.Review = forge.ReviewChangesRequested
+			},
+			want: FailureReviewRejected,
+		},
+		{
+			name: "conflict",
+			set: func(pr *forgetest.PR) {
+				pr.Checks = []forge.Check{{Name: "ci", State: forge.CheckSuccess}}
+				pr.Mergeable = forge.MergeableConflict
+			},
+			want:     FailureConflict,
+			conflict: true,
+		},
+		{
+			name: "no checks reported yet",
+			set: func(pr *forgetest.PR) {
+				pr.Checks = nil
+			},
+			want: FailureForgePending,
+		},
+		{
+			name: "required check never reports",
+			set: func(pr *forgetest.PR) {
+				pr.Checks = []forge.Check{{Name: "lint", State: forge.CheckSuccess}}
+			},
+			want: FailureForgePending,
+		},
+		{
+			name: "blocked by branch protection",
+			set: func(pr *forgetest.PR) {
+				pr.Checks = []forge.Check{{Name: "ci", State: forge.CheckSuccess}}
+				pr.Mergeable = forge.MergeableBlocked
+			},
+			want: FailureForgePending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, srv, _ := setupForgeEngineer(t)
+			srv.OnStatus = tt.set
+
+			result := e.ProcessMRInfo(context.Background(), forgeMR)
+			if result.Success {
+				t.Fatal("expected failure")
+			}
+			if result.Failure != tt.want || result.Conflict != tt.conflict {
+				t.Errorf("result = %+v, want failure %q", result, tt.want)
+			}
+			if result.PullRequest == "" {
+				t.Error("result should link the pull request")
+			}
+			if pr, _ := srv.PR(1); pr.State != forge.StateOpen {
+				t.Errorf("pull request state = %q, want open", pr.State)
+			}
+		})
+	}
+}
+
+func TestDoForgeMerge_HeadMoved(t *testing.T) {
+	e, srv, _ := setupForgeEngineer(t)
+	srv.OnStatus = func(pr *forgetest.PR) {
+		pr.Checks = []forge.Check{{Name: "ci", State: forge.CheckSuccess}}
+	}
+	// The branch moves after the checks reported, so the forge refuses
+	// a merge pinned to the head they ran on.
+	e.forge = &movingHeadForge{Forge: mustForge(t, e)}
+
+	result := e.ProcessMRInfo(context.Background(), forgeMR)
+	if result.Failure != FailureForgePending {
+		t.Errorf("result = %+v, want forge_pending", result)
+	}
+}
+
+// movingHeadForge reports a stale head SHA, so the merge is refused.
+type movingHeadForge struct{ forge.Forge }
+
+func (f *movingHeadForge) Status(ctx context.Context, number int) (*forge.Status, error) {
+	st, err := f.Forge.Status(ctx, number)
+	if st != nil {
+		st.PR.HeadSHA = "stale"
+	}
+	return st, err
+}
+
+func mustForge(t *testing.T, e *Engineer) forge.Forge {
+	t.Helper()
+	f, err := e.forgeClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestProcessTrain_ForgeMode(t *testing.T) {
+	e, srv, _ := setupForgeEngineer(t)
+	srv.OnStatus = func(pr *forgetest.PR) {
+		pr.Checks = []forge.Check{{Name: "ci", State: forge.CheckSuccess}}
+	}
+
+	cars := e.ProcessTrain(context.Background(), []*MRInfo{forgeMR})
+	if len(cars) != 1 || !cars[0].Landed() {
+		t.Fatalf("cars = %+v", cars[0].Result)
+	}
+	if pr, _ := srv.PR(1); pr.State != forge.StateMerged {
+		t.Errorf("pull request state = %q, want merged", pr.State)
+	}
+}
+
+func TestHandleMRInfoFailure_ForgePending(t *testing.T) {
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	var out bytes.Buffer
+	e.output = &out
+
+	e.HandleMRInfoFailure(forgeMR, ProcessResult{Error: "pull request #1 not ready", Failure: FailureForgePending})
+	if !strings.Contains(out.String(), "Waiting on forge") || strings.Contains(out.String(), "Notified witness") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestEngineer_LoadConfig_Forge(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(fc map[string]interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{"merge_queue": map[string]interface{}{"forge": fc}})
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(map[string]interface{}{"type": "gitlab", "required_checks": []string{"test"}})
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	fc := e.Config().Forge
+	if fc == nil || fc.Type != config.ForgeGitLab || len(fc.RequiredChecks) != 1 {
+		t.Errorf("forge config = %+v", fc)
+	}
+
+	for _, bad := range []map[string]interface{}{
+		{"type": "bitbucket"},
+		{"type": "gitea"},
+		{"type": "github"},
+	} {
+		write(bad)
+		if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+			t.Errorf("LoadConfig(%v): expected error", bad)
+		}
+	}
+}
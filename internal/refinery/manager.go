@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/routing"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -67,9 +68,9 @@ func (m *Manager) SessionName() string {
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionName := m.SessionName()
-	status := t.CheckSessionHealth(sessionName, 0)
+	status := session.CheckSessionHealth(t, sessionName, 0)
 	return status == tmux.SessionHealthy, nil
 }
 
@@ -79,14 +80,13 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
-	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
+	return session.CheckSessionHealth(session.NewBackend(), m.SessionName(), maxInactivity)
 }
 
 // Status returns information about the refinery session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -97,7 +97,7 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	return session.GetSessionInfo(t, sessionID)
 }
 
 // Start starts the refinery.
@@ -106,7 +106,7 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 // The agentOverride parameter allows specifying an agent alias to use instead of the town default.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string) error {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -123,7 +123,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		}
 		// Zombie - tmux alive but agent dead. Kill and recreate.
 		_, _ = fmt.Fprintln(m.output, "⚠ Detected zombie session (tmux alive, agent dead). Recreating...")
-		if err := t.KillSession(sessionID); err != nil {
+		if err := session.KillSession(t, sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
@@ -197,17 +197,20 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
 
-	// Apply theme (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
+	// Theming and dialog handling are tmux features; other backends skip them.
+	if tm, ok := t.(*tmux.Tmux); ok {
+		// Apply theme (non-fatal: theming failure doesn't affect operation)
+		theme := tmux.AssignTheme(m.rig.Name)
+		_ = tm.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
 
-	// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
-	// Must be before WaitForRuntimeReady to avoid race where dialog blocks prompt detection.
-	_ = t.AcceptStartupDialogs(sessionID)
+		// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
+		// Must be before WaitForRuntimeReady to avoid race where dialog blocks prompt detection.
+		_ = tm.AcceptStartupDialogs(sessionID)
+	}
 
 	// Wait for Claude to start and show its prompt - fatal if Claude fails to launch
 	// WaitForRuntimeReady waits for the runtime to be ready
-	if err := t.WaitForRuntimeReady(sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
+	if err := session.WaitForRuntimeReady(t, sessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
 		// Kill the zombie session before returning error
 		_ = t.KillSessionWithProcesses(sessionID)
 		return fmt.Errorf("waiting for refinery to start: %w", err)
@@ -221,7 +224,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 // Stop stops the refinery.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName()
 
 	// Check if tmux session exists
@@ -231,7 +234,7 @@ func (m *Manager) Stop() error {
 	}
 
 	// Kill the tmux session
-	return t.KillSession(sessionID)
+	return session.KillSession(t, sessionID)
 }
 
 // Queue returns the current merge queue.
@@ -475,6 +478,19 @@ func (m *Manager) RejectMR(idOrBranch string, reason string, notify bool) (*Merg
 	}
 	mr.Error = reason
 
+	// Record the rejection for dispatch routing track records (best-effort)
+	outcome := routing.Outcome{Rig: m.rig.Name, Bead: mr.IssueID, MR: mr.ID, Worker: mr.Worker, Result: routing.ResultRejected}
+	if issue, err := b.Show(mr.ID); err == nil {
+		if fields := beads.ParseMRFields(issue); fields != nil {
+			outcome.Agent = fields.AgentPreset
+			outcome.Formula = fields.Formula
+			outcome.Retries = fields.RetryCount
+		}
+	}
+	if err := routing.RecordOutcome(filepath.Dir(m.rig.Path), outcome); err != nil {
+		_, _ = fmt.Fprintf(m.output, "Warning: failed to record routing outcome: %v\n", err)
+	}
+
 	// Optionally notify worker
 	if notify {
 		m.notifyWorkerRejected(mr, reason)
@@ -0,0 +1,187 @@
+package refinery
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// CommitMessageData holds the fields available to merge_queue.commit_template.
+type CommitMessageData struct {
+	BeadID  string // Work item being merged (e.g., "gt-abc123")
+	Title   string // Work item title
+	Worker  string // Who did the work (e.g., "gastown/polecats/nux")
+	Convoy  string // Parent convoy ID, if any
+	MRID    string // Merge request bead ID
+	Branch  string // Source branch
+	Target  string // Target branch
+	Message string // Last commit message on the source branch
+
+	// WorkerEmail is the worker's git email, used by the co-authored-by
+	// trailer. Empty uses the default agent email domain.
+	WorkerEmail string
+}
+
+// trailerLine matches a git trailer such as "Bead: gt-abc123". Keys must be
+// capitalized so conventional commit subjects ("feat: ...") don't match.
+var trailerLine = regexp.MustCompile(`^[A-Z][A-Za-z0-9-]*: `)
+
+// RenderCommitMessage builds a merge commit message: tmpl rendered against
+// data (data.Message when tmpl is empty), followed by the requested trailers.
+// Trailers already present in the message are not repeated.
+func RenderCommitMessage(tmpl string, trailers []string, data CommitMessageData) (string, error) {
+	data.Message = strings.TrimSpace(data.Message)
+	msg := data.Message
+	if tmpl != "" {
+		t, err := template.New("commit_template").Parse(tmpl)
+		if err != nil {
+			return "", fmt.Errorf("parsing commit_template: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("rendering commit_template: %w", err)
+		}
+		msg = buf.String()
+	}
+	msg = strings.TrimRight(msg, "\n ")
+
+	var lines []string
+	for _, name := range trailers {
+		var line string
+		switch name {
+		case config.CommitTrailerBead:
+			if data.BeadID != "" {
+				line = "Bead: " + data.BeadID
+			}
+		case config.CommitTrailerCoAuthoredBy:
+			if data.Worker != "" {
+				email := data.WorkerEmail
+				if email == "" {
+					email = config.AgentEmail(data.Worker, config.DefaultAgentEmailDomain)
+				}
+				line = fmt.Sprintf("Co-authored-by: %s <%s>", data.Worker, email)
+			}
+		}
+		if line != "" && !strings.Contains("\n"+msg+"\n", "\n"+line+"\n") {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return msg, nil
+	}
+
+	// Extend an existing trailer block rather than starting a second one.
+	sep := "\n\n"
+	if paragraphs := strings.Split(msg, "\n\n"); len(paragraphs) > 1 {
+		last := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+		block := true
+		for _, l := range last {
+			if !trailerLine.MatchString(l) {
+				block = false
+				break
+			}
+		}
+		if block {
+			sep = "\n"
+		}
+	}
+	return msg + sep + strings.Join(lines, "\n"), nil
+}
+
+// ErrNeedsRebase is returned by MergeWithStrategy when ff-only cannot
+// fast-forward because ref does not contain the current HEAD.
+var ErrNeedsRebase = errors.New("branch needs a rebase")
+
+// MergeWithStrategy lands ref on the current HEAD using a merge_queue
+// merge_strategy. message is the commit message for squash and merge-no-ff;
+// rebase-ff and ff-only keep ref's commits as they are. On failure the
+// worktree is restored to HEAD and any conflicting files are returned.
+func MergeWithStrategy(g *git.Git, strategy, ref, message string) ([]string, error) {
+	switch strategy {
+	case "", config.MergeStrategySquash:
+		if err := g.MergeSquash(ref, message); err != nil {
+			return abortMerge(g), err
+		}
+	case config.MergeStrategyMergeNoFF:
+		if err := g.MergeNoFF(ref, message); err != nil {
+			return abortMerge(g), err
+		}
+	case config.MergeStrategyFFOnly:
+		ok, err := g.IsAncestor("HEAD", ref)
+		if err != nil {
+			return nil, fmt.Errorf("checking whether %s contains HEAD: %w", ref, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("cannot fast-forward to %s: %w", ref, ErrNeedsRebase)
+		}
+		if err := g.MergeFFOnly(ref); err != nil {
+			return nil, err
+		}
+	case config.MergeStrategyRebaseFF:
+		rebased, conflicts, err := rebaseOntoHead(g, ref)
+		if err != nil {
+			return conflicts, err
+		}
+		if err := g.MergeFFOnly(rebased); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", config.ErrInvalidMergeStrategy, strategy)
+	}
+	return nil, nil
+}
+
+// abortMerge collects conflicting files from a failed merge, then discards
+// the merge so the worktree is back at HEAD.
+func abortMerge(g *git.Git) []string {
+	conflicts, _ := g.GetConflictingFiles()
+	_ = g.ResetHard("HEAD")
+	return conflicts
+}
+
+// rebaseOntoHead replays ref's commits onto the current HEAD on a detached
+// checkout and returns the rebased commit. The original checkout (branch or
+// detached HEAD) is restored either way.
+func rebaseOntoHead(g *git.Git, ref string) (string, []string, error) {
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		return "", nil, fmt.Errorf("resolving HEAD: %w", err)
+	}
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		return "", nil, fmt.Errorf("resolving current branch: %w", err)
+	}
+	restore := func() error {
+		if branch == "HEAD" {
+			return g.CheckoutDetached(base)
+		}
+		return g.Checkout(branch)
+	}
+
+	if err := g.CheckoutDetached(ref); err != nil {
+		return "", nil, fmt.Errorf("checking out %s: %w", ref, err)
+	}
+	if err := g.Rebase(base); err != nil {
+		conflicts, _ := g.GetConflictingFiles()
+		_ = g.AbortRebase()
+		if restoreErr := restore(); restoreErr != nil {
+			return "", conflicts, fmt.Errorf("rebase failed (%v), then restoring checkout failed: %w", err, restoreErr)
+		}
+		return "", conflicts, fmt.Errorf("rebasing %s: %w", ref, err)
+	}
+	rebased, err := g.Rev("HEAD")
+	if err != nil {
+		_ = restore()
+		return "", nil, fmt.Errorf("resolving rebased %s: %w", ref, err)
+	}
+	if err := restore(); err != nil {
+		return "", nil, fmt.Errorf("restoring checkout: %w", err)
+	}
+	return rebased, nil, nil
+}
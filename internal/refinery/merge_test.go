@@ -0,0 +1,286 @@
+package refinery
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestRenderCommitMessage(t *testing.T) {
+	data := CommitMessageData{
+		BeadID:  "gt-abc",
+		Title:   "Add login",
+		Worker:  "gastown/polecats/nux",
+		Convoy:  "hq-cv-1",
+		MRID:    "gt-mr1",
+		Message: "feat: add login\n",
+	}
+
+	tests := []struct {
+		name     string
+		tmpl     string
+		trailers []string
+		data     CommitMessageData
+		want     string
+	}{
+		{
+			name: "no template keeps branch message",
+			data: data,
+			want: "feat: add login",
+		},
+		{
+			name: "template fields",
+			tmpl: "{{.Title}} ({{.BeadID}})\n\nMR: {{.MRID}}\nConvoy: {{.Convoy}}",
+			data: data,
+			want: "Add login (gt-abc)\n\nMR: gt-mr1\nConvoy: hq-cv-1",
+		},
+		{
+			name:     "trailers start a new block",
+			trailers: []string{config.CommitTrailerBead, config.CommitTrailerCoAuthoredBy},
+			data:     data,
+			want: "feat: add login\n\nBead: gt-abc\n" +
+				"Co-authored-by: gastown/polecats/nux <gastown.polecats.nux@gastown.local>",
+		},
+		{
+			name:     "trailers extend an existing block",
+			tmpl:     "{{.Message}}\n\nMR: {{.MRID}}",
+			trailers: []string{config.CommitTrailerBead},
+			data:     data,
+			want:     "feat: add login\n\nMR: gt-mr1\nBead: gt-abc",
+		},
+		{
+			name:     "existing trailer not repeated",
+			tmpl:     "{{.Message}}\n\nBead: {{.BeadID}}",
+			trailers: []string{config.CommitTrailerBead},
+			data:     data,
+			want:     "feat: add login\n\nBead: gt-abc",
+		},
+		{
+			name:     "worker email override",
+			trailers: []string{config.CommitTrailerCoAuthoredBy},
+			data:     CommitMessageData{Worker: "gastown/crew/max", WorkerEmail: "max@example.com", Message: "fix: x"},
+			want:     "fix: x\n\nCo-authored-by: gastown/crew/max <max@example.com>",
+		},
+		{
+			name:     "empty fields skip trailers",
+			trailers: []string{config.CommitTrailerBead, config.CommitTrailerCoAuthoredBy},
+			data:     CommitMessageData{Message: "fix: x"},
+			want:     "fix: x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderCommitMessage(tt.tmpl, tt.trailers, tt.data)
+			if err != nil {
+				t.Fatalf("RenderCommitMessage: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderCommitMessage_BadTemplate(t *testing.T) {
+	for _, tmpl := range []string{"{{.Title", "{{.Nope}}"} {
+		if _, err := RenderCommitMessage(tmpl, nil, CommitMessageData{}); err == nil {
+			t.Errorf("expected error for template %q", tmpl)
+		}
+	}
+}
+
+// setupStrategyRepo creates a repo where polecat/nux (two commits) and main
+// (one commit) have diverged without conflicting.
+func setupStrategyRepo(t *testing.T) (*git.Git, string) {
+	t.Helper()
+	dir := t.TempDir()
+	runGitCmd(t, dir, "init", "-b", "main")
+	runGitCmd(t, dir, "config", "user.email", "test@test.com")
+	runGitCmd(t, dir, "config", "user.name", "Test User")
+	commitFile(t, dir, "base.txt", "base\n", "initial")
+
+	runGitCmd(t, dir, "checkout", "-b", "polecat/nux")
+	commitFile(t, dir, "a.txt", "a\n", "feat: add a")
+	commitFile(t, dir, "b.txt", "b\n", "feat: add b")
+
+	runGitCmd(t, dir, "checkout", "main")
+	commitFile(t, dir, "c.txt", "c\n", "chore: add c")
+	return git.NewGit(dir), dir
+}
+
+func TestMergeWithStrategy(t *testing.T) {
+	tests := []struct {
+		strategy string
+		// subjects are main's first-parent commit subjects after the merge, newest first.
+		subjects []string
+		parents  int
+	}{
+		{config.MergeStrategySquash, []string{"landed", "chore: add c", "initial"}, 1},
+		{config.MergeStrategyMergeNoFF, []string{"landed", "chore: add c", "initial"}, 2},
+		{config.MergeStrategyRebaseFF, []string{"feat: add b", "feat: add a", "chore: add c", "initial"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			g, dir := setupStrategyRepo(t)
+			conflicts, err := MergeWithStrategy(g, tt.strategy, "polecat/nux", "landed")
+			if err != nil {
+				t.Fatalf("MergeWithStrategy: %v (conflicts %v)", err, conflicts)
+			}
+
+			if branch := runGitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+				t.Errorf("HEAD = %s, want main", branch)
+			}
+			log := runGitCmd(t, dir, "log", "--first-parent", "--format=%s", "main")
+			if got := strings.Split(log, "\n"); strings.Join(got, "|") != strings.Join(tt.subjects, "|") {
+				t.Errorf("main history = %q, want %q", got, tt.subjects)
+			}
+			parents := strings.Fields(runGitCmd(t, dir, "log", "-1", "--format=%P", "main"))
+			if len(parents) != tt.parents {
+				t.Errorf("HEAD has %d parent(s), want %d", len(parents), tt.parents)
+			}
+			for _, f := range []string{"a.txt", "b.txt", "c.txt"} {
+				if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+					t.Errorf("%s missing after merge: %v", f, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeWithStrategy_FFOnly(t *testing.T) {
+	g, dir := setupStrategyRepo(t)
+	before := runGitCmd(t, dir, "rev-parse", "main")
+	if _, err := MergeWithStrategy(g, config.MergeStrategyFFOnly, "polecat/nux", ""); !errors.Is(err, ErrNeedsRebase) {
+		t.Fatalf("ff-only on diverged branches = %v, want ErrNeedsRebase", err)
+	}
+	if after := runGitCmd(t, dir, "rev-parse", "main"); after != before {
+		t.Errorf("main moved after failed ff-only: %s -> %s", before, after)
+	}
+
+	runGitCmd(t, dir, "checkout", "polecat/nux")
+	runGitCmd(t, dir, "rebase", "main")
+	runGitCmd(t, dir, "checkout", "main")
+	if _, err := MergeWithStrategy(g, config.MergeStrategyFFOnly, "polecat/nux", ""); err != nil {
+		t.Fatalf("ff-only after rebase: %v", err)
+	}
+	if main, branch := runGitCmd(t, dir, "rev-parse", "main"), runGitCmd(t, dir, "rev-parse", "polecat/nux"); main != branch {
+		t.Errorf("main = %s, want polecat/nux %s", main, branch)
+	}
+}
+
+func TestMergeWithStrategy_Conflicts(t *testing.T) {
+	for _, strategy := range []string{config.MergeStrategySquash, config.MergeStrategyMergeNoFF, config.MergeStrategyRebaseFF} {
+		t.Run(strategy, func(t *testing.T) {
+			g, dir := setupStrategyRepo(t)
+			runGitCmd(t, dir, "checkout", "polecat/nux")
+			commitFile(t, dir, "c.txt", "other\n", "feat: clash")
+			runGitCmd(t, dir, "checkout", "main")
+			before := runGitCmd(t, dir, "rev-parse", "main")
+
+			conflicts, err := MergeWithStrategy(g, strategy, "polecat/nux", "landed")
+			if err == nil {
+				t.Fatal("expected merge to fail")
+			}
+			if len(conflicts) != 1 || conflicts[0] != "c.txt" {
+				t.Errorf("conflicts = %v, want [c.txt]", conflicts)
+			}
+			if branch := runGitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+				t.Errorf("HEAD = %s, want main", branch)
+			}
+			if after := runGitCmd(t, dir, "rev-parse", "main"); after != before {
+				t.Errorf("main moved after failed merge")
+			}
+			if status := runGitCmd(t, dir, "status", "--porcelain"); status != "" {
+				t.Errorf("worktree not clean after failed merge:\n%s", status)
+			}
+		})
+	}
+}
+
+func TestMergeWithStrategy_RebaseFFOnDetachedHead(t *testing.T) {
+	g, dir := setupStrategyRepo(t)
+	tip := runGitCmd(t, dir, "rev-parse", "main")
+	runGitCmd(t, dir, "checkout", "--detach", tip)
+
+	if _, err := MergeWithStrategy(g, config.MergeStrategyRebaseFF, "polecat/nux", ""); err != nil {
+		t.Fatalf("MergeWithStrategy: %v", err)
+	}
+	if branch := runGitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "HEAD" {
+		t.Errorf("expected detached HEAD, got %s", branch)
+	}
+	if parent := runGitCmd(t, dir, "rev-parse", "HEAD~2"); parent != tip {
+		t.Errorf("HEAD~2 = %s, want train base %s", parent, tip)
+	}
+	if main := runGitCmd(t, dir, "rev-parse", "main"); main != tip {
+		t.Errorf("main moved while merging on a detached HEAD")
+	}
+}
+
+func TestEngineer_CommitMessage(t *testing.T) {
+	g, dir := setupStrategyRepo(t)
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: t.TempDir()})
+	e.git = g
+	e.workDir = dir
+	e.output = &bytes.Buffer{}
+	e.config.MergeStrategy = config.MergeStrategyMergeNoFF
+	e.config.CommitTemplate = "Merge {{.Branch}} into {{.Target}}\n\n{{.Message}}"
+	e.config.CommitTrailers = []string{config.CommitTrailerBead, config.CommitTrailerCoAuthoredBy}
+
+	mr := &MRInfo{ID: "gt-mr1", Branch: "polecat/nux", Target: "main", SourceIssue: "gt-abc", Worker: "gastown/polecats/nux"}
+	msg := e.commitMessage(mr, true)
+	want := "Merge polecat/nux into main\n\nfeat: add b\n\nBead: gt-abc\n" +
+		"Co-authored-by: gastown/polecats/nux <gastown.polecats.nux@gastown.local>"
+	if msg != want {
+		t.Errorf("commitMessage:\n%s\nwant:\n%s", msg, want)
+	}
+}
+
+func TestEngineer_LoadConfig_MergeStrategy(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(mq map[string]interface{}) {
+		data, _ := json.Marshal(map[string]interface{}{"merge_queue": mq})
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(map[string]interface{}{
+		"merge_strategy":  "rebase-ff",
+		"commit_template": "{{.Title}}",
+		"commit_trailers": []string{"bead"},
+	})
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if e.Config().MergeStrategy != config.MergeStrategySquash {
+		t.Errorf("default merge strategy = %q, want squash", e.Config().MergeStrategy)
+	}
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	cfg := e.Config()
+	if cfg.MergeStrategy != config.MergeStrategyRebaseFF || cfg.CommitTemplate != "{{.Title}}" ||
+		len(cfg.CommitTrailers) != 1 || cfg.CommitTrailers[0] != "bead" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	for name, mq := range map[string]map[string]interface{}{
+		"bad strategy": {"merge_strategy": "octopus"},
+		"bad template": {"commit_template": "{{.Title"},
+		"bad field":    {"commit_template": "{{.Nope}}"},
+		"bad trailer":  {"commit_trailers": []string{"signed-off-by"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			write(mq)
+			if err := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir}).LoadConfig(); err == nil {
+				t.Error("expected LoadConfig error")
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+// Package refinery provides the merge queue processing agent.
+// This file implements merge trains: landing several MRs per gate run.
+
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TrainCar is one MR in a merge train together with its outcome.
+type TrainCar struct {
+	MR     *MRInfo       `json:"mr"`
+	Result ProcessResult `json:"result"`
+
+	// commit is the car's merge commit (its tip commit for rebase-ff and
+	// ff-only) in the last stack that passed gates.
+	// Empty until the car has landed (locally) in a passing stack.
+	commit string
+}
+
+// Landed reports whether the car was merged and pushed.
+func (c *TrainCar) Landed() bool {
+	return c.Result.Success
+}
+
+// TrainSize returns how many MRs a merge train may carry. This is the
+// configured MaxConcurrent, with anything below 1 treated as 1. ff-only
+// cannot stack branches (only the first car could fast-forward), so its
+// trains are always a single MR.
+func (e *Engineer) TrainSize() int {
+	if e.config.MaxConcurrent < 1 || e.mergeStrategy() == config.MergeStrategyFFOnly {
+		return 1
+	}
+	return e.config.MaxConcurrent
+}
+
+// SelectTrain picks the MRs for the next merge train: the highest-scoring
+// ready MRs (ScoreMR order) that share the top MR's target branch, up to size.
+// MRs for other targets are left for a later train.
+func SelectTrain(ready []*MRInfo, size int, now time.Time) []*MRInfo {
+	if len(ready) == 0 || size < 1 {
+		return nil
+	}
+
+	sorted := make([]*MRInfo, len(ready))
+	copy(sorted, ready)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ScoreAt(now) > sorted[j].ScoreAt(now)
+	})
+
+	target := sorted[0].Target
+	var train []*MRInfo
+	for _, mr := range sorted {
+		if mr.Target != target {
+			continue
+		}
+		train = append(train, mr)
+		if len(train) == size {
+			break
+		}
+	}
+	return train
+}
+
+// ProcessTrain speculatively stacks the given MRs (in order) on top of their
+// shared target using the configured merge strategy, runs the quality gates once on the combined
+// result, and pushes everything that passed in a single push.
+//
+// When the combined stack fails gates, the train is bisected: each half is
+// re-stacked and gated on its own so the offending MR(s) are isolated while
+// the rest still land. A car whose merge conflicts with the stack is
+// dropped from the train with a Conflict result.
+//
+// With the ff-only strategy the cars are not stacked: each branch must
+// already contain the target, so they land one at a time as single merges
+// (with on_conflict deciding whether a branch left behind is rebased).
+//
+// The returned cars are in the same order as mrs. Callers handle each car
+// with HandleMRInfoSuccess or HandleMRInfoFailure as for single merges.
+func (e *Engineer) ProcessTrain(ctx context.Context, mrs []*MRInfo) []*TrainCar {
+	// A train serves several traces: link each MR's trace to the train span.
+	ctx, span := telemetry.StartSpan(ctx, "refinery.train", attribute.Int("gt.train_size", len(mrs)))
+	defer span.End()
+
+	cars := make([]*TrainCar, len(mrs))
+	for i, mr := range mrs {
+		cars[i] = &TrainCar{MR: mr}
+		telemetry.LinkTraceParent(span, mr.TraceParent)
+	}
+	if len(cars) == 0 {
+		return cars
+	}
+
+	// In forge mode each MR lands through its own pull request and the
+	// forge runs the checks, so there is no local stack to gate. ff-only
+	// has no stack either: every car after the first would be behind it.
+	if e.config.Forge != nil || e.mergeStrategy() == config.MergeStrategyFFOnly {
+		for _, car := range cars {
+			car.Result = e.ProcessMRInfo(ctx, car.MR)
+		}
+		return cars
+	}
+
+	target := mrs[0].Target
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train: %d MR(s) onto %s\n", len(cars), target)
+
+	// Verify every branch exists before building the stack.
+	var pending []*TrainCar
+	for _, car := range cars {
+		switch {
+		case car.MR.Target != target:
+			car.Result = ProcessResult{Error: fmt.Sprintf("target %s does not match train target %s", car.MR.Target, target)}
+		default:
+			exists, err := e.git.BranchExists(car.MR.Branch)
+			if err != nil {
+				car.Result = ProcessResult{Error: fmt.Sprintf("failed to check branch %s: %v", car.MR.Branch, err)}
+			} else if !exists {
+				car.Result = ProcessResult{Error: fmt.Sprintf("branch %s not found locally", car.MR.Branch)}
+			} else {
+				pending = append(pending, car)
+			}
+		}
+	}
+
+	if err := e.git.Checkout(target); err != nil {
+		failCars(pending, fmt.Sprintf("failed to checkout target %s: %v", target, err))
+		return cars
+	}
+	if err := e.git.Pull("origin", target); err != nil {
+		// Pull might fail if nothing to pull, that's ok
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+	}
+	base, err := e.git.Rev("HEAD")
+	if err != nil {
+		failCars(pending, fmt.Sprintf("failed to resolve %s: %v", target, err))
+		return cars
+	}
+
+	tip := e.landCars(ctx, base, pending, nil)
+
+	var landed []*TrainCar
+	for _, car := range pending {
+		if car.commit != "" {
+			landed = append(landed, car)
+		}
+	}
+
+	// Move the target branch to the last passing stack and push it once.
+	if err := e.git.Checkout(target); err != nil {
+		failCars(landed, fmt.Sprintf("failed to checkout target %s: %v", target, err))
+		return cars
+	}
+	if len(landed) == 0 {
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Merge train: nothing landed")
+		return cars
+	}
+	if err := e.git.ResetHard(tip); err != nil {
+		failCars(landed, fmt.Sprintf("failed to advance %s to train tip: %v", target, err))
+		return cars
+	}
+	if err := e.pushSubmoduleCommits(base, tip); err != nil {
+		if resetErr := e.git.ResetHard(base); resetErr != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after submodule failure: %v\n", target, resetErr)
+		}
+		failCars(landed, err.Error())
+		return cars
+	}
+	if result := e.pushTarget(ctx, target); !result.Success {
+		for _, car := range landed {
+			car.Result = result
+		}
+		return cars
+	}
+
+	for _, car := range landed {
+		car.Result = ProcessResult{Success: true, MergeCommit: car.commit}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train: landed %d/%d MR(s) at %s\n", len(landed), len(cars), tip[:8])
+	return cars
+}
+
+// landCars stacks cars on base and gates the result, returning the new tip
+// containing every car that passed. Cars that pass get their commit recorded;
+// cars that fail get their Result set.
+//
+// known, when non-nil, is a gate failure already observed for exactly this
+// stack on this base, which lets bisection skip re-running it.
+func (e *Engineer) landCars(ctx context.Context, base string, cars []*TrainCar, known *ProcessResult) string {
+	if len(cars) == 0 {
+		return base
+	}
+
+	result := known
+	var stacked []*TrainCar
+	var commits []string
+	var tip string
+	if result == nil {
+		var err error
+		stacked, commits, tip, err = e.stackCars(base, cars)
+		if err != nil {
+			failCars(cars, err.Error())
+			return base
+		}
+		if len(stacked) == 0 {
+			return base
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train: gating %s\n", carIDs(stacked))
+		gateResult := e.runQualityChecks(ctx)
+		if gateResult.Success {
+			for i, car := range stacked {
+				car.commit = commits[i]
+			}
+			return tip
+		}
+		result = &gateResult
+	} else {
+		stacked = cars
+	}
+
+	if len(stacked) == 1 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train: %s fails gates, dropping from train\n", stacked[0].MR.ID)
+		stacked[0].Result = *result
+		return base
+	}
+
+	// Bisect: land what we can from the left half, then try the right half on
+	// top of it. If the whole left half landed, the right half on the new base
+	// is exactly the stack that just failed, so its result is already known.
+	mid := len(stacked) / 2
+	left, right := stacked[:mid], stacked[mid:]
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train: bisecting %d MR(s)\n", len(stacked))
+
+	newBase := e.landCars(ctx, base, left, nil)
+	var rightKnown *ProcessResult
+	if allCommitted(left) {
+		rightKnown = result
+	}
+	return e.landCars(ctx, newBase, right, rightKnown)
+}
+
+// stackCars merges each car onto base on a detached HEAD using the configured
+// merge strategy. Cars that conflict with the stack are given a Conflict
+// result and skipped. Returns the cars actually stacked, the commit each car
+// left at the tip, and the resulting tip.
+func (e *Engineer) stackCars(base string, cars []*TrainCar) ([]*TrainCar, []string, string, error) {
+	if err := e.git.CheckoutDetached(base); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to checkout train base %s: %v", base, err)
+	}
+
+	strategy := e.mergeStrategy()
+	var stacked []*TrainCar
+	var commits []string
+	tip := base
+	for _, car := range cars {
+		var msg string
+		if strategy == config.MergeStrategySquash || strategy == config.MergeStrategyMergeNoFF {
+			msg = e.commitMessage(car.MR, false)
+		}
+
+		if conflicts, err := MergeWithStrategy(e.git, strategy, car.MR.Branch, msg); err != nil {
+			// ZFC: MergeWithStrategy uses git's porcelain output to detect conflicts.
+			if resetErr := e.git.ResetHard(tip); resetErr != nil {
+				return nil, nil, "", fmt.Errorf("failed to reset train stack after %s: %v", car.MR.ID, resetErr)
+			}
+			if len(conflicts) > 0 {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train: %s conflicts with the train, dropping\n", car.MR.ID)
+				car.Result = ProcessResult{
+					Conflict: true,
+					Error:    fmt.Sprintf("merge conflicts in: %v", conflicts),
+				}
+			} else {
+				car.Result = ProcessResult{Error: fmt.Sprintf("merge failed: %v", err)}
+			}
+			continue
+		}
+
+		commit, err := e.git.Rev("HEAD")
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to get merge commit for %s: %v", car.MR.ID, err)
+		}
+		stacked = append(stacked, car)
+		commits = append(commits, commit)
+		tip = commit
+	}
+	return stacked, commits, tip, nil
+}
+
+// failCars marks every car as failed with the same error.
+func failCars(cars []*TrainCar, msg string) {
+	for _, car := range cars {
+		car.Result = ProcessResult{Error: msg}
+		car.commit = ""
+	}
+}
+
+// allCommitted reports whether every car landed in a passing stack.
+func allCommitted(cars []*TrainCar) bool {
+	for _, car := range cars {
+		if car.commit == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// carIDs formats the MR IDs of cars for log output.
+func carIDs(cars []*TrainCar) string {
+	ids := make([]string, len(cars))
+	for i, car := range cars {
+		ids[i] = car.MR.ID
+	}
+	return strings.Join(ids, ", ")
+}
@@ -0,0 +1,240 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestSelectTrain(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ready := []*MRInfo{
+		{ID: "low", Target: "main", Priority: 3, CreatedAt: now},
+		{ID: "top", Target: "main", Priority: 0, CreatedAt: now},
+		{ID: "other", Target: "integration/x", Priority: 1, CreatedAt: now},
+		{ID: "mid", Target: "main", Priority: 2, CreatedAt: now},
+	}
+
+	train := SelectTrain(ready, 2, now)
+	var ids []string
+	for _, mr := range train {
+		ids = append(ids, mr.ID)
+	}
+	if got := strings.Join(ids, ","); got != "top,mid" {
+		t.Errorf("SelectTrain = %s, want top,mid", got)
+	}
+
+	if got := SelectTrain(ready, 10, now); len(got) != 3 {
+		t.Errorf("expected only the 3 main-target MRs, got %d", len(got))
+	}
+	if got := SelectTrain(nil, 3, now); got != nil {
+		t.Errorf("expected nil train for empty queue, got %v", got)
+	}
+}
+
+func TestTrainSize(t *testing.T) {
+	e := &Engineer{config: DefaultMergeQueueConfig()}
+	if got := e.TrainSize(); got != 1 {
+		t.Errorf("default TrainSize = %d, want 1", got)
+	}
+	e.config.MaxConcurrent = 0
+	if got := e.TrainSize(); got != 1 {
+		t.Errorf("TrainSize with MaxConcurrent=0 = %d, want 1", got)
+	}
+	e.config.MaxConcurrent = 5
+	if got := e.TrainSize(); got != 5 {
+		t.Errorf("TrainSize = %d, want 5", got)
+	}
+}
+
+// setupTrainRepo creates a refinery clone of a bare origin with one polecat
+// branch per entry in files, each adding the named file off main.
+func setupTrainRepo(t *testing.T, files map[string]string) (*Engineer, string, string) {
+	t.Helper()
+	origin := t.TempDir()
+	runGitCmd(t, origin, "init", "--bare", "-b", "main")
+
+	dir := t.TempDir()
+	runGitCmd(t, dir, "init", "-b", "main")
+	runGitCmd(t, dir, "config", "user.email", "test@test.com")
+	runGitCmd(t, dir, "config", "user.name", "Test User")
+	commitFile(t, dir, "README.md", "# test\n", "initial")
+	runGitCmd(t, dir, "remote", "add", "origin", origin)
+	runGitCmd(t, dir, "push", "-u", "origin", "main")
+
+	for branch, file := range files {
+		runGitCmd(t, dir, "checkout", "-b", branch, "main")
+		commitFile(t, dir, file, branch+"\n", "feat: add "+file)
+	}
+	runGitCmd(t, dir, "checkout", "main")
+
+	e := &Engineer{
+		rig:     &rig.Rig{Name: "test-rig", Path: t.TempDir()},
+		git:     git.NewGit(dir),
+		config:  DefaultMergeQueueConfig(),
+		workDir: dir,
+		output:  &bytes.Buffer{},
+		mergeSlotEnsureExists: func() (string, error) {
+			return "merge-slot", nil
+		},
+		mergeSlotAcquire: func(holder string, _ bool) (*beads.MergeSlotStatus, error) {
+			return &beads.MergeSlotStatus{ID: "merge-slot", Available: true, Holder: holder}, nil
+		},
+		mergeSlotRelease: func(_ string) error { return nil },
+	}
+	e.config.RunTests = false
+	return e, dir, origin
+}
+
+func trainMRs(branches ...string) []*MRInfo {
+	var mrs []*MRInfo
+	for _, b := range branches {
+		mrs = append(mrs, &MRInfo{ID: "mr-" + filepath.Base(b), Branch: b, Target: "main"})
+	}
+	return mrs
+}
+
+func TestProcessTrain_AllPass(t *testing.T) {
+	e, dir, origin := setupTrainRepo(t, map[string]string{
+		"polecat/a": "a.txt",
+		"polecat/b": "b.txt",
+	})
+	counter := filepath.Join(t.TempDir(), "runs")
+	e.config.Gates = map[string]*GateConfig{
+		"count": {Cmd: "echo run >> " + counter},
+	}
+
+	cars := e.ProcessTrain(context.Background(), trainMRs("polecat/a", "polecat/b"))
+	for _, car := range cars {
+		if !car.Landed() {
+			t.Fatalf("%s did not land: %s", car.MR.ID, car.Result.Error)
+		}
+		if car.Result.MergeCommit == "" {
+			t.Errorf("%s has no merge commit", car.MR.ID)
+		}
+	}
+
+	if runs := countLines(t, counter); runs != 1 {
+		t.Errorf("gates ran %d times, want 1 for a passing train", runs)
+	}
+	remote := runGitCmd(t, origin, "ls-tree", "--name-only", "main")
+	for _, f := range []string{"a.txt", "b.txt"} {
+		if !strings.Contains(remote, f) {
+			t.Errorf("origin/main missing %s: %s", f, remote)
+		}
+	}
+	if head := runGitCmd(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); head != "main" {
+		t.Errorf("HEAD = %q after train, want main", head)
+	}
+}
+
+func TestProcessTrain_BisectsFailure(t *testing.T) {
+	e, _, origin := setupTrainRepo(t, map[string]string{
+		"polecat/a": "a.txt",
+		"polecat/b": "bad.txt",
+		"polecat/c": "c.txt",
+	})
+	counter := filepath.Join(t.TempDir(), "runs")
+	e.config.Gates = map[string]*GateConfig{
+		"no-bad": {Cmd: "echo run >> " + counter + " && test ! -e bad.txt"},
+	}
+
+	cars := e.ProcessTrain(context.Background(), trainMRs("polecat/a", "polecat/b", "polecat/c"))
+
+	if !cars[0].Landed() || !cars[2].Landed() {
+		t.Fatalf("expected a and c to land: a=%+v c=%+v", cars[0].Result, cars[2].Result)
+	}
+	if cars[1].Landed() || cars[1].Result.Conflict {
+		t.Errorf("expected b to fail gates, got %+v", cars[1].Result)
+	}
+
+	// full stack, [a], [b], [c]; [b c] is skipped as already known to fail.
+	if runs := countLines(t, counter); runs != 4 {
+		t.Errorf("gates ran %d times, want 4", runs)
+	}
+	remote := runGitCmd(t, origin, "ls-tree", "--name-only", "main")
+	if strings.Contains(remote, "bad.txt") {
+		t.Error("failing MR was pushed to origin")
+	}
+	for _, f := range []string{"a.txt", "c.txt"} {
+		if !strings.Contains(remote, f) {
+			t.Errorf("origin/main missing %s: %s", f, remote)
+		}
+	}
+}
+
+func TestProcessTrain_DropsConflictingCar(t *testing.T) {
+	e, _, origin := setupTrainRepo(t, map[string]string{
+		"polecat/a": "same.txt",
+		"polecat/b": "same.txt",
+	})
+
+	cars := e.ProcessTrain(context.Background(), trainMRs("polecat/a", "polecat/b"))
+	if !cars[0].Landed() {
+		t.Fatalf("expected a to land: %+v", cars[0].Result)
+	}
+	if !cars[1].Result.Conflict {
+		t.Errorf("expected b to be dropped with a conflict, got %+v", cars[1].Result)
+	}
+	if got := runGitCmd(t, origin, "show", "main:same.txt"); got != "polecat/a" {
+		t.Errorf("origin/main same.txt = %q, want polecat/a", got)
+	}
+}
+
+func TestProcessTrain_FFOnlyLandsCarsSingly(t *testing.T) {
+	for _, tt := range []struct {
+		onConflict  string
+		secondLands bool
+	}{
+		{config.OnConflictAutoRebase, true},
+		{config.OnConflictAssignBack, false},
+	} {
+		t.Run(tt.onConflict, func(t *testing.T) {
+			e, _, origin := setupTrainRepo(t, map[string]string{
+				"polecat/a": "a.txt",
+				"polecat/b": "b.txt",
+			})
+			e.config.MergeStrategy = config.MergeStrategyFFOnly
+			e.config.OnConflict = tt.onConflict
+			e.config.MaxConcurrent = 3
+			if got := e.TrainSize(); got != 1 {
+				t.Errorf("ff-only TrainSize = %d, want 1", got)
+			}
+
+			cars := e.ProcessTrain(context.Background(), trainMRs("polecat/a", "polecat/b"))
+			if !cars[0].Landed() {
+				t.Fatalf("expected a to fast-forward: %+v", cars[0].Result)
+			}
+			if cars[1].Landed() != tt.secondLands {
+				t.Errorf("b landed = %v, want %v: %+v", cars[1].Landed(), tt.secondLands, cars[1].Result)
+			}
+			if !tt.secondLands && (!cars[1].Result.Conflict || cars[1].Result.Failure != FailureConflict) {
+				t.Errorf("expected b to need a rebase, got %+v", cars[1].Result)
+			}
+			if merges := runGitCmd(t, origin, "rev-list", "--merges", "main"); merges != "" {
+				t.Errorf("origin/main has merge commits: %s", merges)
+			}
+		})
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	return strings.Count(string(data), "\n")
+}
@@ -277,6 +277,18 @@ const (
 
 	// FailureCheckout indicates checkout of target branch failed.
 	FailureCheckout FailureType = "checkout_fail"
+
+	// FailureChecksFail indicates required forge status checks failed on
+	// the pull request (forge mode).
+	FailureChecksFail FailureType = "checks_fail"
+
+	// FailureReviewRejected indicates a reviewer requested changes on the
+	// pull request (forge mode).
+	FailureReviewRejected FailureType = "review_rejected"
+
+	// FailureForgePending indicates the pull request is still waiting on
+	// checks, reviews or branch protection; the MR is retried later.
+	FailureForgePending FailureType = "forge_pending"
 )
 
 // FailureLabel returns the beads label for this failure type.
@@ -284,7 +296,7 @@ func (f FailureType) FailureLabel() string {
 	switch f {
 	case FailureConflict:
 		return "needs-rebase"
-	case FailureTestsFail, FailureBuildFail, FailureFlakyTest:
+	case FailureTestsFail, FailureBuildFail, FailureFlakyTest, FailureChecksFail, FailureReviewRejected:
 		return "needs-fix"
 	case FailurePushFail:
 		return "needs-retry"
@@ -296,7 +308,7 @@ func (f FailureType) FailureLabel() string {
 // ShouldAssignToWorker returns true if this failure should be assigned back to the worker.
 func (f FailureType) ShouldAssignToWorker() bool {
 	switch f {
-	case FailureConflict, FailureTestsFail, FailureBuildFail, FailureFlakyTest:
+	case FailureConflict, FailureTestsFail, FailureBuildFail, FailureFlakyTest, FailureChecksFail, FailureReviewRejected:
 		return true
 	default:
 		return false
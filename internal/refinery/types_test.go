@@ -269,6 +269,9 @@ func TestFailureType_FailureLabel(t *testing.T) {
 		{FailurePushFail, "needs-retry"},
 		{FailureFetch, ""},
 		{FailureCheckout, ""},
+		{FailureChecksFail, "needs-fix"},
+		{FailureReviewRejected, "needs-fix"},
+		{FailureForgePending, ""},
 	}
 
 	for _, tt := range tests {
@@ -294,6 +297,9 @@ func TestFailureType_ShouldAssignToWorker(t *testing.T) {
 		{FailurePushFail, false},
 		{FailureFetch, false},
 		{FailureCheckout, false},
+		{FailureChecksFail, true},
+		{FailureReviewRejected, true},
+		{FailureForgePending, false},
 	}
 
 	for _, tt := range tests {
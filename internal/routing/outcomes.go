@@ -0,0 +1,128 @@
+// Package routing picks the rig and agent preset for a bead when the
+// dispatcher is asked to choose (gt sling --auto, scheduled auto-routed beads).
+//
+// Rigs advertise the work they take in their settings (capabilities matched
+// against bead labels, path globs matched against files touched by linked
+// beads). The refinery records every verdict it reaches on polecat work, and
+// those outcomes become each rig/agent/formula combination's track record.
+package routing
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Outcome results.
+const (
+	ResultMerged   = "merged"
+	ResultFailed   = "failed"
+	ResultRejected = "rejected"
+)
+
+// Outcome is one refinery verdict on a polecat's work.
+type Outcome struct {
+	Time        time.Time `json:"time"`
+	Rig         string    `json:"rig"`
+	Bead        string    `json:"bead,omitempty"` // source issue
+	MR          string    `json:"mr,omitempty"`
+	Worker      string    `json:"worker,omitempty"`
+	Agent       string    `json:"agent,omitempty"`        // agent preset that did the work
+	Formula     string    `json:"formula,omitempty"`      // formula the work ran under
+	Result      string    `json:"result"`                 // merged, failed or rejected
+	FailureType string    `json:"failure_type,omitempty"` // build, tests, conflict, or a forge failure (checks_fail, review_rejected)
+	Retries     int       `json:"retries,omitempty"`      // conflict-resolution cycles before this verdict
+	Files       []string  `json:"files,omitempty"`        // files the merge touched
+}
+
+// OutcomesPath returns the path of the town's outcome log.
+func OutcomesPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "routing-outcomes.jsonl")
+}
+
+// RecordOutcome appends an outcome to the town's outcome log.
+func RecordOutcome(townRoot string, o Outcome) error {
+	if o.Time.IsZero() {
+		o.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("marshaling outcome: %w", err)
+	}
+	path := OutcomesPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+
+	// Single O_APPEND writes of one line are atomic enough for concurrent
+	// refineries; an outcome with a long file list may exceed PIPE_BUF, but
+	// a torn line is skipped on read.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening outcome log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing outcome log: %w", err)
+	}
+	return nil
+}
+
+// LoadOutcomes reads the town's outcome log, skipping malformed lines.
+// A missing log has no outcomes.
+func LoadOutcomes(townRoot string) ([]Outcome, error) {
+	f, err := os.Open(OutcomesPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading outcome log: %w", err)
+	}
+	defer f.Close()
+
+	var outcomes []Outcome
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var o Outcome
+		if err := json.Unmarshal([]byte(line), &o); err != nil {
+			continue
+		}
+		outcomes = append(outcomes, o)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading outcome log: %w", err)
+	}
+	return outcomes, nil
+}
+
+// TouchedFiles returns the files touched by merges of the given beads,
+// deduplicated and in log order.
+func TouchedFiles(outcomes []Outcome, beadIDs []string) []string {
+	want := make(map[string]bool, len(beadIDs))
+	for _, id := range beadIDs {
+		want[id] = true
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, o := range outcomes {
+		if o.Result != ResultMerged || !want[o.Bead] {
+			continue
+		}
+		for _, f := range o.Files {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
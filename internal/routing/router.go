@@ -0,0 +1,238 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrNoMatch is returned when no candidate rig matches the bead's labels or
+// touched paths, so there is nothing to base a choice on.
+var ErrNoMatch = errors.New("no rig matches the bead")
+
+// Scoring weights. A label or path match decides the rig; the track record
+// decides between agents and breaks ties between equally capable rigs.
+const (
+	capabilityWeight = 3.0 // per bead label matching a rig capability
+	pathWeight       = 4.0 // scaled by the fraction of touched files the rig owns
+	recordWeight     = 4.0 // scaled by smoothed merge rate minus 0.5
+	retryPenalty     = 0.5 // per conflict retry per merge
+	minFormulaRuns   = 3   // outcomes needed before formula-specific stats are used
+)
+
+// Candidate is a rig the router may send work to.
+type Candidate struct {
+	Rig     string
+	Routing *config.RoutingConfig // nil: no advertised capabilities
+	Agents  []string              // agent presets to consider; the first is the rig's default
+}
+
+// Request describes the bead being routed.
+type Request struct {
+	Bead    string
+	Labels  []string
+	Paths   []string // files touched by the bead's linked beads
+	Formula string
+	Agent   string // fixed agent preset (--agent); empty lets the router choose
+}
+
+// Stats is a track record for one rig/agent (and optionally formula).
+type Stats struct {
+	Merged   int
+	Failed   int
+	Rejected int
+	Retries  int // conflict retries across merged work
+}
+
+// Total is the number of verdicts recorded.
+func (s Stats) Total() int { return s.Merged + s.Failed + s.Rejected }
+
+// Choice is one scored rig/agent pair.
+type Choice struct {
+	Rig     string
+	Agent   string
+	Score   float64
+	Match   float64 // portion of Score from labels and paths
+	Reasons []string
+}
+
+// Decision is the router's pick, with the alternatives it considered.
+type Decision struct {
+	Choice
+	Ranked []Choice // every candidate pair, best first
+}
+
+// Explain renders the decision for display, one reason per line.
+func (d *Decision) Explain() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (agent %s, score %.2f)\n", d.Rig, d.Agent, d.Score)
+	for _, r := range d.Reasons {
+		fmt.Fprintf(&b, "  - %s\n", r)
+	}
+	if len(d.Ranked) > 1 {
+		b.WriteString("  runners-up:")
+		for i, c := range d.Ranked[1:] {
+			if i == 3 {
+				break
+			}
+			fmt.Fprintf(&b, " %s/%s (%.2f)", c.Rig, c.Agent, c.Score)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Route scores every candidate rig/agent pair for req and returns the best.
+// history is the town's outcome log (LoadOutcomes).
+func Route(req Request, candidates []Candidate, history []Outcome) (*Decision, error) {
+	var ranked []Choice
+	for _, cand := range candidates {
+		if cand.Routing != nil && cand.Routing.Exclude {
+			continue
+		}
+		match, matchReasons := scoreMatch(req, cand.Routing)
+		agents := cand.Agents
+		if req.Agent != "" {
+			agents = []string{req.Agent}
+		} else if cand.Routing != nil && len(cand.Routing.Agents) > 0 {
+			agents = cand.Routing.Agents
+		}
+		for _, agent := range agents {
+			c := Choice{Rig: cand.Rig, Agent: agent, Score: match, Match: match}
+			c.Reasons = append(c.Reasons, matchReasons...)
+			record, reason := scoreRecord(history, cand.Rig, agent, req.Formula)
+			c.Score += record
+			c.Reasons = append(c.Reasons, reason)
+			ranked = append(ranked, c)
+		}
+	}
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("%w: no rigs are available for routing", ErrNoMatch)
+	}
+
+	// Candidate order breaks ties, so a rig's default agent wins over an
+	// untried alternative with the same score.
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	best := ranked[0]
+	if best.Match <= 0 {
+		if len(req.Labels) == 0 && len(req.Paths) == 0 {
+			return nil, fmt.Errorf("%w: %s has no labels and its linked beads touched no recorded files", ErrNoMatch, req.Bead)
+		}
+		return nil, fmt.Errorf("%w: no rig's routing capabilities or paths match %s (labels: %s)",
+			ErrNoMatch, req.Bead, strings.Join(req.Labels, ", "))
+	}
+	return &Decision{Choice: best, Ranked: ranked}, nil
+}
+
+// scoreMatch scores how well a rig's advertised routing fits the request.
+func scoreMatch(req Request, rc *config.RoutingConfig) (float64, []string) {
+	if rc == nil {
+		return 0, nil
+	}
+	var score float64
+	var reasons []string
+	for _, label := range req.Labels {
+		for _, capability := range rc.Capabilities {
+			if strings.EqualFold(label, capability) {
+				score += capabilityWeight
+				reasons = append(reasons, fmt.Sprintf("label %q matches capability", label))
+				break
+			}
+		}
+	}
+	if len(req.Paths) > 0 && len(rc.Paths) > 0 {
+		owned := 0
+		for _, file := range req.Paths {
+			for _, pattern := range rc.Paths {
+				if MatchPath(pattern, file) {
+					owned++
+					break
+				}
+			}
+		}
+		if owned > 0 {
+			score += pathWeight * float64(owned) / float64(len(req.Paths))
+			reasons = append(reasons, fmt.Sprintf("owns %d/%d files touched by linked beads", owned, len(req.Paths)))
+		}
+	}
+	return score, reasons
+}
+
+// scoreRecord scores a rig/agent pair's track record. Formula-specific
+// outcomes are used once there are enough of them; otherwise all of the
+// pair's outcomes count.
+func scoreRecord(history []Outcome, rig, agent, formula string) (float64, string) {
+	stats := StatsFor(history, rig, agent, formula)
+	scope := "formula " + formula
+	if formula == "" || stats.Total() < minFormulaRuns {
+		stats = StatsFor(history, rig, agent, "")
+		scope = "all formulas"
+	}
+	if stats.Total() == 0 {
+		return 0, fmt.Sprintf("agent %s has no track record on %s", agent, rig)
+	}
+
+	// Laplace smoothing keeps one lucky merge from outranking a long record.
+	rate := float64(stats.Merged+1) / float64(stats.Total()+2)
+	score := recordWeight * (rate - 0.5)
+	retriesPerMerge := 0.0
+	if stats.Merged > 0 {
+		retriesPerMerge = float64(stats.Retries) / float64(stats.Merged)
+		score -= retryPenalty * retriesPerMerge
+	}
+	return score, fmt.Sprintf("agent %s on %s: %d/%d merged, %d rejected, %.1f retries/merge (%s)",
+		agent, rig, stats.Merged, stats.Total(), stats.Rejected, retriesPerMerge, scope)
+}
+
+// StatsFor tallies outcomes for a rig/agent pair. An empty formula counts
+// all formulas. Outcomes recorded without an agent are attributed to no one.
+func StatsFor(history []Outcome, rig, agent, formula string) Stats {
+	var s Stats
+	for _, o := range history {
+		if o.Rig != rig || o.Agent != agent || (formula != "" && o.Formula != formula) {
+			continue
+		}
+		switch o.Result {
+		case ResultMerged:
+			s.Merged++
+			s.Retries += o.Retries
+		case ResultFailed:
+			s.Failed++
+		case ResultRejected:
+			s.Rejected++
+		}
+	}
+	return s
+}
+
+// MatchPath reports whether a repository file path matches a routing glob.
+// Patterns without a slash match the file's base name ("*.proto"); "dir/**"
+// matches everything under dir; "dir/**/*.go" matches Go files under dir;
+// other patterns use path.Match against the whole path.
+func MatchPath(pattern, file string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	file = strings.TrimPrefix(file, "./")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	if prefix, rest, found := strings.Cut(pattern, "**"); found {
+		if !strings.HasPrefix(file, prefix) {
+			return false
+		}
+		rest = strings.TrimPrefix(rest, "/")
+		if rest == "" {
+			return true
+		}
+		ok, _ := path.Match(rest, path.Base(file))
+		return ok
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
+}
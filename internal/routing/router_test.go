@@ -0,0 +1,167 @@
+package routing
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"*.proto", "api/v1/user.proto", true},
+		{"*.proto", "api/v1/user.go", false},
+		{"web/**", "web/src/app.tsx", true},
+		{"web/**", "webhooks/handler.go", false},
+		{"internal/**/*.go", "internal/cmd/sling.go", true},
+		{"internal/**/*.go", "internal/cmd/README.md", false},
+		{"docs/*.md", "docs/INSTALLING.md", true},
+		{"docs/*.md", "docs/design/x.md", false},
+		{"./web/**", "./web/index.html", true},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestRoute_CapabilitiesAndPaths(t *testing.T) {
+	candidates := []Candidate{
+		{Rig: "backend", Routing: &config.RoutingConfig{Capabilities: []string{"go", "api"}, Paths: []string{"internal/**"}}, Agents: []string{"claude"}},
+		{Rig: "frontend", Routing: &config.RoutingConfig{Capabilities: []string{"frontend"}, Paths: []string{"web/**"}}, Agents: []string{"claude"}},
+		{Rig: "legacy", Routing: &config.RoutingConfig{Capabilities: []string{"frontend"}, Exclude: true}, Agents: []string{"claude"}},
+		{Rig: "misc", Agents: []string{"claude"}},
+	}
+
+	d, err := Route(Request{Bead: "gt-1", Labels: []string{"Frontend"}}, candidates, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Rig != "frontend" || d.Agent != "claude" {
+		t.Errorf("label routing picked %s/%s, want frontend/claude", d.Rig, d.Agent)
+	}
+	for _, c := range d.Ranked {
+		if c.Rig == "legacy" {
+			t.Error("excluded rig was considered")
+		}
+	}
+
+	d, err = Route(Request{Bead: "gt-2", Paths: []string{"internal/a.go", "internal/b.go", "web/x.ts"}}, candidates, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Rig != "backend" {
+		t.Errorf("path routing picked %s, want backend", d.Rig)
+	}
+	if !strings.Contains(d.Explain(), "owns 2/3 files") {
+		t.Errorf("explanation missing path reason:\n%s", d.Explain())
+	}
+
+	if _, err := Route(Request{Bead: "gt-3", Labels: []string{"docs"}}, candidates, nil); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("unmatched labels: err = %v, want ErrNoMatch", err)
+	}
+	if _, err := Route(Request{Bead: "gt-4"}, candidates, nil); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("no signals: err = %v, want ErrNoMatch", err)
+	}
+}
+
+func TestRoute_TrackRecordPicksAgent(t *testing.T) {
+	candidates := []Candidate{{
+		Rig:     "gastown",
+		Routing: &config.RoutingConfig{Capabilities: []string{"go"}, Agents: []string{"claude", "codex"}},
+		Agents:  []string{"claude"},
+	}}
+	var history []Outcome
+	for i := 0; i < 4; i++ {
+		history = append(history,
+			Outcome{Rig: "gastown", Agent: "claude", Formula: "mol-polecat-work", Result: ResultFailed, FailureType: "tests"},
+			Outcome{Rig: "gastown", Agent: "codex", Formula: "mol-polecat-work", Result: ResultMerged},
+		)
+	}
+	history = append(history, Outcome{Rig: "gastown", Agent: "claude", Formula: "mol-polecat-work", Result: ResultRejected})
+
+	req := Request{Bead: "gt-1", Labels: []string{"go"}, Formula: "mol-polecat-work"}
+	d, err := Route(req, candidates, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Agent != "codex" {
+		t.Errorf("picked agent %s, want codex:\n%s", d.Agent, d.Explain())
+	}
+	if !strings.Contains(d.Explain(), "4/4 merged") {
+		t.Errorf("explanation missing track record:\n%s", d.Explain())
+	}
+
+	// A fixed agent is respected even with a worse record.
+	req.Agent = "claude"
+	d, err = Route(req, candidates, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Agent != "claude" || len(d.Ranked) != 1 {
+		t.Errorf("fixed agent: got %s with %d choices", d.Agent, len(d.Ranked))
+	}
+}
+
+func TestRoute_TiePrefersDefaultAgent(t *testing.T) {
+	candidates := []Candidate{{
+		Rig:     "gastown",
+		Routing: &config.RoutingConfig{Capabilities: []string{"go"}, Agents: []string{"claude", "gemini"}},
+	}}
+	d, err := Route(Request{Bead: "gt-1", Labels: []string{"go"}}, candidates, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Agent != "claude" {
+		t.Errorf("tie picked %s, want first-listed agent claude", d.Agent)
+	}
+}
+
+func TestStatsFor(t *testing.T) {
+	history := []Outcome{
+		{Rig: "r", Agent: "a", Formula: "f1", Result: ResultMerged, Retries: 2},
+		{Rig: "r", Agent: "a", Formula: "f2", Result: ResultMerged},
+		{Rig: "r", Agent: "a", Formula: "f1", Result: ResultRejected},
+		{Rig: "r", Agent: "b", Formula: "f1", Result: ResultFailed},
+		{Rig: "other", Agent: "a", Formula: "f1", Result: ResultFailed},
+	}
+	if got, want := StatsFor(history, "r", "a", "f1"), (Stats{Merged: 1, Rejected: 1, Retries: 2}); got != want {
+		t.Errorf("StatsFor(f1) = %+v, want %+v", got, want)
+	}
+	if got, want := StatsFor(history, "r", "a", ""), (Stats{Merged: 2, Rejected: 1, Retries: 2}); got != want {
+		t.Errorf("StatsFor(all) = %+v, want %+v", got, want)
+	}
+}
+
+func TestOutcomeLog(t *testing.T) {
+	town := t.TempDir()
+	if got, err := LoadOutcomes(town); err != nil || got != nil {
+		t.Fatalf("LoadOutcomes(empty) = %v, %v", got, err)
+	}
+	if err := RecordOutcome(town, Outcome{Rig: "r", Bead: "gt-1", Result: ResultMerged, Files: []string{"a.go", "b.go"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordOutcome(town, Outcome{Rig: "r", Bead: "gt-2", Result: ResultMerged, Files: []string{"b.go", "c.go"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordOutcome(town, Outcome{Rig: "r", Bead: "gt-3", Result: ResultFailed, Files: []string{"d.go"}}); err != nil {
+		t.Fatal(err)
+	}
+	outcomes, err := LoadOutcomes(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outcomes) != 3 || outcomes[0].Time.IsZero() {
+		t.Fatalf("LoadOutcomes = %+v", outcomes)
+	}
+	got := TouchedFiles(outcomes, []string{"gt-1", "gt-2", "gt-3"})
+	if want := []string{"a.go", "b.go", "c.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TouchedFiles = %v, want %v", got, want)
+	}
+}
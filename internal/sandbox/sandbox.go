@@ -0,0 +1,226 @@
+// Package sandbox runs agent commands isolated from the host.
+//
+// A sandboxed command runs under bubblewrap (bwrap) in its own user, mount,
+// PID, IPC and UTS namespaces, with the filesystem mounted read-only except
+// for the paths the agent needs to write. CPU, memory and process limits are
+// enforced by running it in a transient systemd scope, which places it in
+// its own cgroup v2 group:
+//
+//	systemd-run --user --scope -p MemoryMax=... -p CPUQuota=... -p TasksMax=... \
+//	  bwrap --ro-bind / / --bind <worktree> <worktree> ... -- sh -c '<command>'
+//
+// The network namespace is shared: agents need to reach their model API and
+// the Dolt server.
+//
+// The host tmux server is out of reach: /tmp (and $TMUX_TMPDIR) is a private
+// tmpfs and the TMUX variables are cleared, since its socket would let the
+// agent run commands outside the sandbox. Sandboxed processes see
+// GT_SANDBOXED=1 and send nudges through the file queue instead, which the
+// recipient drains from outside.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrUnavailable is returned when the sandbox is enabled but cannot be
+// provided on this host. Callers should fail rather than run unsandboxed.
+var ErrUnavailable = errors.New("sandbox unavailable")
+
+const (
+	bwrapBin      = "bwrap"
+	systemdRunBin = "systemd-run"
+
+	// EnvSandboxed is set to "1" inside the sandbox.
+	EnvSandboxed = "GT_SANDBOXED"
+)
+
+// Active reports whether this process runs inside a sandbox.
+func Active() bool {
+	return os.Getenv(EnvSandboxed) == "1"
+}
+
+// OwnsPIDNamespace reports whether this process runs in a sandbox's private
+// PID namespace: one nested below the host's (NSpid lists more than one PID)
+// whose init is bwrap. Only there does signalling every visible process
+// stay inside the sandbox.
+func OwnsPIDNamespace() bool {
+	status, err := os.ReadFile(filepath.Join(procRoot, "self", "status"))
+	if err != nil {
+		return false
+	}
+	nested := false
+	for _, line := range strings.Split(string(status), "\n") {
+		if f := strings.Fields(line); len(f) > 0 && f[0] == "NSpid:" {
+			nested = len(f) > 2
+		}
+	}
+	if !nested {
+		return false
+	}
+	comm, err := os.ReadFile(filepath.Join(procRoot, "1", "comm"))
+	return err == nil && strings.TrimSpace(string(comm)) == bwrapBin
+}
+
+// Test seams.
+var (
+	lookPath = exec.LookPath
+	hostOS   = goruntime.GOOS
+	homeDir  = os.UserHomeDir
+	getenv   = os.Getenv
+	procRoot = "/proc"
+)
+
+// Spec describes one sandboxed command.
+type Spec struct {
+	// Name identifies the sandbox (e.g., the tmux session name). It names the
+	// systemd scope, so it must be unique among running sandboxes.
+	Name string
+
+	// WorkDir is the working directory. It is always writable.
+	WorkDir string
+
+	// Writable lists additional writable paths. "~/" expands to the home
+	// directory; paths that do not exist are skipped.
+	Writable []string
+}
+
+// Check reports whether the sandbox described by cfg can run on this host.
+func Check(cfg *config.SandboxConfig) error {
+	if hostOS != "linux" {
+		return fmt.Errorf("%w: requires Linux (running on %s)", ErrUnavailable, hostOS)
+	}
+	if _, err := lookPath(bwrapBin); err != nil {
+		return fmt.Errorf("%w: %s not found (install bubblewrap)", ErrUnavailable, bwrapBin)
+	}
+	if cfg.HasLimits() {
+		if _, err := lookPath(systemdRunBin); err != nil {
+			return fmt.Errorf("%w: %s not found (needed for cpu/memory/pids limits)", ErrUnavailable, systemdRunBin)
+		}
+	}
+	return nil
+}
+
+// Wrap returns a shell command that runs command inside the sandbox
+// described by cfg and spec. Environment exported by command itself (e.g.
+// via config.PrependEnv) is evaluated inside the sandbox.
+func Wrap(cfg *config.SandboxConfig, spec Spec, command string) (string, error) {
+	if err := Check(cfg); err != nil {
+		return "", err
+	}
+	if spec.WorkDir == "" {
+		return "", fmt.Errorf("sandbox %s: work dir is required", spec.Name)
+	}
+
+	var args []string
+	if cfg.HasLimits() {
+		limits, err := scopeArgs(cfg, spec.Name)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, limits...)
+	}
+	args = append(args, bwrapArgs(spec)...)
+	args = append(args, "sh", "-c", command)
+
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = config.ShellQuote(a)
+	}
+	return "exec " + strings.Join(quoted, " "), nil
+}
+
+// scopeArgs returns the systemd-run prefix that applies cfg's limits.
+func scopeArgs(cfg *config.SandboxConfig, name string) ([]string, error) {
+	args := []string{systemdRunBin, "--user", "--scope", "--quiet", "--collect"}
+	if name != "" {
+		args = append(args, "--unit=gt-sandbox-"+unitName(name))
+	}
+	mem, err := cfg.MemoryBytes()
+	if err != nil {
+		return nil, err
+	}
+	if mem > 0 {
+		args = append(args,
+			"-p", "MemoryMax="+strconv.FormatInt(mem, 10),
+			"-p", "MemorySwapMax=0")
+	}
+	if cfg.CPUs > 0 {
+		args = append(args, "-p", "CPUQuota="+strconv.FormatFloat(cfg.CPUs*100, 'f', -1, 64)+"%")
+	}
+	if cfg.Pids > 0 {
+		args = append(args, "-p", "TasksMax="+strconv.Itoa(cfg.Pids))
+	}
+	return append(args, "--"), nil
+}
+
+// bwrapArgs returns the bubblewrap invocation for spec, up to and including
+// the "--" that precedes the command.
+func bwrapArgs(spec Spec) []string {
+	args := []string{bwrapBin,
+		"--die-with-parent",
+		"--unshare-user", "--unshare-pid", "--unshare-ipc", "--unshare-uts",
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		// Private /tmp: the host's holds the tmux server socket.
+		"--tmpfs", "/tmp",
+	}
+	if dir := expandHome(getenv("TMUX_TMPDIR")); dir != "" && dir != "/tmp" {
+		args = append(args, "--tmpfs", dir)
+	}
+	args = append(args,
+		"--unsetenv", "TMUX",
+		"--unsetenv", "TMUX_PANE",
+		"--unsetenv", "TMUX_TMPDIR",
+		"--setenv", EnvSandboxed, "1",
+		"--bind", spec.WorkDir, spec.WorkDir,
+	)
+	seen := map[string]bool{spec.WorkDir: true}
+	for _, p := range spec.Writable {
+		p = expandHome(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		args = append(args, "--bind-try", p, p)
+	}
+	return append(args, "--chdir", spec.WorkDir, "--")
+}
+
+// expandHome resolves a leading "~/" and cleans p. Returns "" for relative
+// paths, which have no meaning inside the sandbox.
+func expandHome(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := homeDir()
+		if err != nil {
+			return ""
+		}
+		p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+	}
+	if !filepath.IsAbs(p) {
+		return ""
+	}
+	return filepath.Clean(p)
+}
+
+// unitName replaces characters systemd does not allow in unit names.
+func unitName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '-', r == '_', r == '.', r == ':':
+			return r
+		}
+		return '_'
+	}, name)
+}
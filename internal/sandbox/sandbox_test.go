@@ -0,0 +1,176 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func stubHost(t *testing.T, goos string, bins ...string) {
+	t.Helper()
+	oldLook, oldOS, oldHome, oldGetenv := lookPath, hostOS, homeDir, getenv
+	t.Cleanup(func() { lookPath, hostOS, homeDir, getenv = oldLook, oldOS, oldHome, oldGetenv })
+	hostOS = goos
+	getenv = func(string) string { return "" }
+	homeDir = func() (string, error) { return "/home/gt", nil }
+	lookPath = func(name string) (string, error) {
+		for _, b := range bins {
+			if b == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestCheck(t *testing.T) {
+	limited := &config.SandboxConfig{Enabled: true, Pids: 256}
+
+	stubHost(t, "darwin", bwrapBin, systemdRunBin)
+	if err := Check(limited); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Check on darwin = %v, want ErrUnavailable", err)
+	}
+
+	stubHost(t, "linux")
+	if err := Check(&config.SandboxConfig{Enabled: true}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Check without bwrap = %v, want ErrUnavailable", err)
+	}
+
+	stubHost(t, "linux", bwrapBin)
+	if err := Check(&config.SandboxConfig{Enabled: true}); err != nil {
+		t.Errorf("Check without limits = %v, want nil", err)
+	}
+	if err := Check(limited); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Check with limits but no systemd-run = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	stubHost(t, "linux", bwrapBin, systemdRunBin)
+	cfg := &config.SandboxConfig{Enabled: true, CPUs: 1.5, Memory: "2G", Pids: 512}
+
+	got, err := Wrap(cfg, Spec{
+		Name:     "gt-gastown-nux",
+		WorkDir:  "/town/gastown/polecats/nux",
+		Writable: []string{"/town/gastown/.repo.git", "~/.claude", "relative/path", "/town/gastown/polecats/nux"},
+	}, "export GT_ROLE=polecat && claude 'do it'")
+	if err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+
+	for _, want := range []string{
+		"exec systemd-run --user --scope --quiet --collect --unit=gt-sandbox-gt-gastown-nux ",
+		"-p MemoryMax=2147483648 -p MemorySwapMax=0 -p CPUQuota=150% -p TasksMax=512 -- bwrap ",
+		"--unshare-user --unshare-pid --unshare-ipc --unshare-uts --ro-bind / / ",
+		"--tmpfs /tmp --unsetenv TMUX --unsetenv TMUX_PANE --unsetenv TMUX_TMPDIR --setenv GT_SANDBOXED 1 ",
+		"--bind /town/gastown/polecats/nux /town/gastown/polecats/nux ",
+		"--bind-try /town/gastown/.repo.git /town/gastown/.repo.git ",
+		"--bind-try /home/gt/.claude /home/gt/.claude ",
+		"--chdir /town/gastown/polecats/nux -- sh -c 'export GT_ROLE=polecat && claude '\\''do it'\\'''",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Wrap missing %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "relative/path") {
+		t.Errorf("relative writable path should be skipped: %s", got)
+	}
+	if strings.Contains(got, "--bind /tmp") {
+		t.Errorf("host /tmp (tmux socket) must not be bound: %s", got)
+	}
+	if n := strings.Count(got, "/town/gastown/polecats/nux /town/gastown/polecats/nux"); n != 1 {
+		t.Errorf("work dir bound %d times, want 1", n)
+	}
+}
+
+func TestWrap_NoLimits(t *testing.T) {
+	stubHost(t, "linux", bwrapBin)
+	got, err := Wrap(&config.SandboxConfig{Enabled: true}, Spec{Name: "s", WorkDir: "/w"}, "true")
+	if err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+	if !strings.HasPrefix(got, "exec bwrap ") {
+		t.Errorf("Wrap without limits = %q, want bare bwrap", got)
+	}
+}
+
+func TestWrap_TmuxTmpdir(t *testing.T) {
+	stubHost(t, "linux", bwrapBin)
+	getenv = func(k string) string {
+		if k == "TMUX_TMPDIR" {
+			return "/run/user/1000/tmux"
+		}
+		return ""
+	}
+
+	got, err := Wrap(&config.SandboxConfig{Enabled: true}, Spec{Name: "s", WorkDir: "/w"}, "true")
+	if err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+	if !strings.Contains(got, "--tmpfs /tmp --tmpfs /run/user/1000/tmux ") {
+		t.Errorf("custom tmux socket dir not masked: %s", got)
+	}
+}
+
+func TestUnitName(t *testing.T) {
+	if got := unitName("gt-rig/nux@1"); got != "gt-rig_nux_1" {
+		t.Errorf("unitName = %q", got)
+	}
+}
+
+func TestOwnsPIDNamespace(t *testing.T) {
+	fakeProc := func(t *testing.T, nspid, initComm string) {
+		t.Helper()
+		root := t.TempDir()
+		for dir, files := range map[string]map[string]string{
+			"self": {"status": "Name:\tgt\nPid:\t41\nNSpid:\t" + nspid + "\n"},
+			"1":    {"comm": initComm + "\n"},
+		} {
+			if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+				t.Fatal(err)
+			}
+			for name, data := range files {
+				if err := os.WriteFile(filepath.Join(root, dir, name), []byte(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		old := procRoot
+		t.Cleanup(func() { procRoot = old })
+		procRoot = root
+	}
+
+	tests := []struct {
+		name     string
+		nspid    string
+		initComm string
+		want     bool
+	}{
+		{"sandbox namespace", "81234\t7", "bwrap", true},
+		{"host namespace", "41", "systemd", false},
+		{"host namespace with bwrap init", "41", "bwrap", false},
+		{"other container namespace", "81234\t7", "dumb-init", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeProc(t, tt.nspid, tt.initComm)
+			if got := OwnsPIDNamespace(); got != tt.want {
+				t.Errorf("OwnsPIDNamespace() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no proc", func(t *testing.T) {
+		old := procRoot
+		t.Cleanup(func() { procRoot = old })
+		procRoot = filepath.Join(t.TempDir(), "missing")
+		if OwnsPIDNamespace() {
+			t.Error("OwnsPIDNamespace() = true without /proc")
+		}
+	})
+}
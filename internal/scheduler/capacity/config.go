@@ -13,6 +13,9 @@ import "time"
 //   -1 (default): direct dispatch — gt sling works as before, near-zero overhead
 //    0:           direct dispatch (same as -1)
 //    N > 0:       deferred dispatch — labels/metadata applied, daemon dispatches
+//
+// In deferred mode, free slots are shared between rigs by weighted fair share
+// (see FairShare), optionally bounded per rig by MaxPolecatsPerRig/RigMaxPolecats.
 type SchedulerConfig struct {
 	// MaxPolecats is the max concurrent polecats across ALL rigs.
 	// Includes both scheduler-dispatched and directly-slung polecats.
@@ -28,6 +31,29 @@ type SchedulerConfig struct {
 	// SpawnDelay is the delay between spawns to prevent Dolt lock contention.
 	// Default: "0s".
 	SpawnDelay string `json:"spawn_delay,omitempty"`
+
+	// MaxPolecatsPerRig caps concurrent polecats in any single rig.
+	// nil/absent or <= 0 = no per-rig cap (only MaxPolecats applies).
+	MaxPolecatsPerRig *int `json:"max_polecats_per_rig,omitempty"`
+
+	// RigMaxPolecats overrides MaxPolecatsPerRig for specific rigs.
+	// A value <= 0 removes the cap for that rig.
+	RigMaxPolecats map[string]int `json:"rig_max_polecats,omitempty"`
+
+	// RigWeights sets each rig's share of free slots relative to other rigs.
+	// Rigs not listed have weight 1; a rig with weight 2 gets twice the
+	// polecats of a weight-1 rig when both have work queued.
+	RigWeights map[string]int `json:"rig_weights,omitempty"`
+
+	// Timezone is the IANA zone for Windows and Blackouts (default: local).
+	Timezone string `json:"timezone,omitempty"`
+
+	// Windows vary MaxPolecats/BatchSize by time of day and week; the first
+	// matching window wins and the fields above apply outside all windows.
+	Windows []CapacityWindow `json:"windows,omitempty"`
+
+	// Blackouts pause deferred dispatch automatically (e.g. release freezes).
+	Blackouts []BlackoutWindow `json:"blackouts,omitempty"`
 }
 
 // DefaultSchedulerConfig returns a SchedulerConfig with sensible defaults.
@@ -66,6 +92,34 @@ func (c *SchedulerConfig) GetSpawnDelay() time.Duration {
 	return ParseDurationOrDefault(c.SpawnDelay, 0)
 }
 
+// GetRigCap returns the max concurrent polecats for rig, or 0 if uncapped.
+func (c *SchedulerConfig) GetRigCap(rig string) int {
+	if c == nil {
+		return 0
+	}
+	if n, ok := c.RigMaxPolecats[rig]; ok {
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+	if c.MaxPolecatsPerRig == nil || *c.MaxPolecatsPerRig < 0 {
+		return 0
+	}
+	return *c.MaxPolecatsPerRig
+}
+
+// GetRigWeight returns the fair-share weight for rig (default 1).
+func (c *SchedulerConfig) GetRigWeight(rig string) int {
+	if c == nil {
+		return 1
+	}
+	if w, ok := c.RigWeights[rig]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
 // IsDeferred returns true when the scheduler is configured for deferred dispatch
 // (max_polecats > 0). Returns false for direct dispatch (-1) and disabled (0).
 func (c *SchedulerConfig) IsDeferred() bool {
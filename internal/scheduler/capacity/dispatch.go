@@ -40,6 +40,15 @@ type DispatchCycle struct {
 
 	// SpawnDelay between dispatches.
 	SpawnDelay time.Duration
+
+	// ActiveByRig returns the running polecats per rig. When set, Plan orders
+	// pending beads by priority and shares slots between rigs using Config's
+	// caps and weights (PlanFairDispatch). When nil, beads are taken in
+	// QueryPending order (PlanDispatch).
+	ActiveByRig func() (map[string]int, error)
+
+	// Config supplies per-rig caps and weights for fair-share planning.
+	Config *SchedulerConfig
 }
 
 // DispatchReport summarizes the result of one dispatch cycle.
@@ -62,7 +71,14 @@ func (c *DispatchCycle) Plan() (DispatchPlan, error) {
 		return DispatchPlan{}, fmt.Errorf("querying pending: %w", err)
 	}
 
-	return PlanDispatch(cap, c.BatchSize, pending), nil
+	if c.ActiveByRig == nil {
+		return PlanDispatch(cap, c.BatchSize, pending), nil
+	}
+	active, err := c.ActiveByRig()
+	if err != nil {
+		return DispatchPlan{}, fmt.Errorf("counting active polecats: %w", err)
+	}
+	return PlanFairDispatch(cap, c.BatchSize, pending, active, c.Config), nil
 }
 
 // onSuccessRetries is the number of times to retry OnSuccess before giving up.
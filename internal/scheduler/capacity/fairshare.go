@@ -0,0 +1,162 @@
+package capacity
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultPriority is the priority assumed for a bead whose priority is unknown.
+const DefaultPriority = 2
+
+// EnqueuedAt returns when the bead was scheduled, or the zero time if unknown.
+func (b PendingBead) EnqueuedAt() time.Time {
+	if b.Context == nil || b.Context.EnqueuedAt == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, b.Context.EnqueuedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// age returns the time the bead has been waiting since: its convoy's creation
+// if it belongs to one, otherwise its own enqueue time.
+func (b PendingBead) age() time.Time {
+	if !b.ConvoyCreatedAt.IsZero() {
+		return b.ConvoyCreatedAt
+	}
+	return b.EnqueuedAt()
+}
+
+// dispatchBefore reports whether a should be dispatched before b:
+// higher priority first, then older convoy (or enqueue time for beads outside
+// a convoy), then earlier enqueue time, then ID for determinism.
+func dispatchBefore(a, b PendingBead) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if aa, ba := a.age(), b.age(); !aa.Equal(ba) {
+		if aa.IsZero() || ba.IsZero() {
+			return !aa.IsZero() // known age sorts before unknown
+		}
+		return aa.Before(ba)
+	}
+	if ae, be := a.EnqueuedAt(), b.EnqueuedAt(); !ae.Equal(be) {
+		return ae.Before(be)
+	}
+	return a.ID < b.ID
+}
+
+// OrderPending returns the beads sorted into dispatch order.
+// The input slice is not modified.
+func OrderPending(pending []PendingBead) []PendingBead {
+	ordered := make([]PendingBead, len(pending))
+	copy(ordered, pending)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return dispatchBefore(ordered[i], ordered[j])
+	})
+	return ordered
+}
+
+// FairShare picks up to slots beads from ordered (already in dispatch order),
+// sharing slots between rigs by weighted fair share.
+//
+// Each pick goes to the rig with the lowest load per unit weight, where load
+// counts the rig's active polecats plus beads already picked this cycle. Ties
+// go to the rig whose next bead comes first in dispatch order, so priority
+// still decides between equally loaded rigs. Rigs at their cap are skipped.
+//
+// Returns the picked beads and the number of remaining beads held back only
+// because their rig is at its cap.
+func FairShare(ordered []PendingBead, slots int, active map[string]int, cfg *SchedulerConfig) ([]PendingBead, int) {
+	queues := make(map[string][]PendingBead)
+	var rigs []string
+	for _, b := range ordered {
+		if _, ok := queues[b.TargetRig]; !ok {
+			rigs = append(rigs, b.TargetRig)
+		}
+		queues[b.TargetRig] = append(queues[b.TargetRig], b)
+	}
+
+	load := make(map[string]int, len(rigs))
+	for _, rig := range rigs {
+		load[rig] = active[rig]
+	}
+	atCap := func(rig string) bool {
+		limit := cfg.GetRigCap(rig)
+		return limit > 0 && load[rig] >= limit
+	}
+
+	var picked []PendingBead
+	for len(picked) < slots {
+		best := ""
+		for _, rig := range rigs {
+			if len(queues[rig]) == 0 || atCap(rig) {
+				continue
+			}
+			if best == "" {
+				best = rig
+				continue
+			}
+			// Compare load/weight without division.
+			lr, lb := load[rig]*cfg.GetRigWeight(best), load[best]*cfg.GetRigWeight(rig)
+			if lr < lb || (lr == lb && dispatchBefore(queues[rig][0], queues[best][0])) {
+				best = rig
+			}
+		}
+		if best == "" {
+			break
+		}
+		picked = append(picked, queues[best][0])
+		queues[best] = queues[best][1:]
+		load[best]++
+	}
+
+	capped := 0
+	for _, rig := range rigs {
+		if atCap(rig) {
+			capped += len(queues[rig])
+		}
+	}
+	return picked, capped
+}
+
+// PlanFairDispatch is PlanDispatch with priority ordering and per-rig fair
+// share. active maps rig name to its currently running polecats.
+func PlanFairDispatch(availableCapacity, batchSize int, ready []PendingBead, active map[string]int, cfg *SchedulerConfig) DispatchPlan {
+	if len(ready) == 0 {
+		return DispatchPlan{Reason: "none"}
+	}
+
+	if availableCapacity <= 0 {
+		return DispatchPlan{
+			Skipped: len(ready),
+			Reason:  "capacity",
+		}
+	}
+
+	slots := batchSize
+	if availableCapacity < slots {
+		slots = availableCapacity
+	}
+
+	picked, capped := FairShare(OrderPending(ready), slots, active, cfg)
+
+	reason := "batch"
+	switch {
+	case len(picked) < slots && capped > 0:
+		reason = "rig-cap"
+	case len(picked) < slots:
+		reason = "ready"
+	case availableCapacity < batchSize:
+		reason = "capacity"
+	}
+
+	return DispatchPlan{
+		ToDispatch: picked,
+		Skipped:    len(ready) - len(picked),
+		RigCapped:  capped,
+		Reason:     reason,
+	}
+}
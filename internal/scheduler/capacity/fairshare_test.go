@@ -0,0 +1,195 @@
+package capacity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pending(id, rig string, priority int, enqueued time.Time) PendingBead {
+	return PendingBead{
+		ID:         id,
+		WorkBeadID: id,
+		TargetRig:  rig,
+		Priority:   priority,
+		Context:    &SlingContextFields{EnqueuedAt: enqueued.UTC().Format(time.RFC3339)},
+	}
+}
+
+func ids(beads []PendingBead) string {
+	var out []string
+	for _, b := range beads {
+		out = append(out, b.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestOrderPending(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	old := pending("old", "r", 2, base.Add(time.Hour))
+	newer := pending("newer", "r", 2, base)
+	convoy := pending("convoy", "r", 2, base.Add(2*time.Hour))
+	convoy.ConvoyCreatedAt = base.Add(-time.Hour) // convoy predates everything
+	urgent := pending("urgent", "r", 0, base.Add(3*time.Hour))
+	unknown := PendingBead{ID: "unknown", TargetRig: "r", Priority: 2}
+
+	in := []PendingBead{unknown, old, newer, convoy, urgent}
+	got := ids(OrderPending(in))
+	if want := "urgent,convoy,newer,old,unknown"; got != want {
+		t.Errorf("OrderPending = %s, want %s", got, want)
+	}
+	if in[0].ID != "unknown" {
+		t.Error("OrderPending modified its input")
+	}
+}
+
+func TestFairShare_NoisyRigDoesNotStarve(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ready []PendingBead
+	// A 200-bead epic enqueued first, then one bead each for two other rigs.
+	for i := 0; i < 200; i++ {
+		ready = append(ready, pending(fmt.Sprintf("epic-%03d", i), "noisy", 2, base.Add(time.Duration(i)*time.Second)))
+	}
+	ready = append(ready, pending("a-1", "alpha", 2, base.Add(time.Hour)))
+	ready = append(ready, pending("b-1", "beta", 2, base.Add(time.Hour)))
+
+	picked, capped := FairShare(OrderPending(ready), 3, nil, nil)
+	if got := ids(picked); got != "epic-000,a-1,b-1" {
+		t.Errorf("FairShare = %s, want one bead per rig", got)
+	}
+	if capped != 0 {
+		t.Errorf("capped = %d, want 0 without caps", capped)
+	}
+}
+
+func TestFairShare_WeightsActiveAndCaps(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ready []PendingBead
+	for i := 0; i < 6; i++ {
+		at := base.Add(time.Duration(i) * time.Second)
+		ready = append(ready, pending(fmt.Sprintf("a%d", i), "alpha", 2, at))
+		ready = append(ready, pending(fmt.Sprintf("b%d", i), "beta", 2, at))
+	}
+
+	// alpha has weight 2: it gets two slots for every one beta gets.
+	cfg := &SchedulerConfig{RigWeights: map[string]int{"alpha": 2}}
+	picked, _ := FairShare(OrderPending(ready), 6, nil, cfg)
+	counts := map[string]int{}
+	for _, b := range picked {
+		counts[b.TargetRig]++
+	}
+	if counts["alpha"] != 4 || counts["beta"] != 2 {
+		t.Errorf("weighted split = %v, want alpha:4 beta:2", counts)
+	}
+
+	// Running polecats count against a rig's share.
+	picked, _ = FairShare(OrderPending(ready), 2, map[string]int{"alpha": 3}, nil)
+	if got := ids(picked); got != "b0,b1" {
+		t.Errorf("with alpha busy, FairShare = %s, want b0,b1", got)
+	}
+
+	// Per-rig caps hold beads back even when slots are free.
+	perRig := 1
+	cfg = &SchedulerConfig{MaxPolecatsPerRig: &perRig, RigMaxPolecats: map[string]int{"beta": 2}}
+	picked, capped := FairShare(OrderPending(ready), 10, map[string]int{"beta": 1}, cfg)
+	if got := ids(picked); got != "a0,b0" {
+		t.Errorf("capped FairShare = %s, want a0,b0", got)
+	}
+	if capped != 10 {
+		t.Errorf("capped = %d, want 10", capped)
+	}
+}
+
+func TestFairShare_PriorityBreaksTies(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ready := []PendingBead{
+		pending("low", "alpha", 3, base),
+		pending("high", "beta", 0, base.Add(time.Hour)),
+	}
+	picked, _ := FairShare(OrderPending(ready), 1, nil, nil)
+	if got := ids(picked); got != "high" {
+		t.Errorf("FairShare = %s, want high-priority bead first", got)
+	}
+}
+
+func TestPlanFairDispatch(t *testing.T) {
+	// Without caps, reasons match PlanDispatch.
+	mk := func(n int) []PendingBead {
+		result := make([]PendingBead, n)
+		for i := range result {
+			result[i] = PendingBead{ID: string(rune('a' + i)), TargetRig: "r"}
+		}
+		return result
+	}
+	for _, tc := range []struct{ capacity, batch, ready int }{
+		{5, 3, 0}, {0, 3, 10}, {2, 3, 10}, {10, 3, 10}, {10, 5, 2}, {1, 3, 10},
+	} {
+		want := PlanDispatch(tc.capacity, tc.batch, mk(tc.ready))
+		got := PlanFairDispatch(tc.capacity, tc.batch, mk(tc.ready), nil, nil)
+		if len(got.ToDispatch) != len(want.ToDispatch) || got.Skipped != want.Skipped || got.Reason != want.Reason {
+			t.Errorf("PlanFairDispatch(%d,%d,%d) = %d/%d/%s, want %d/%d/%s", tc.capacity, tc.batch, tc.ready,
+				len(got.ToDispatch), got.Skipped, got.Reason, len(want.ToDispatch), want.Skipped, want.Reason)
+		}
+	}
+
+	one := 1
+	cfg := &SchedulerConfig{MaxPolecatsPerRig: &one}
+	plan := PlanFairDispatch(10, 5, mk(4), map[string]int{"r": 1}, cfg)
+	if len(plan.ToDispatch) != 0 || plan.Reason != "rig-cap" || plan.RigCapped != 4 {
+		t.Errorf("plan = %+v, want nothing dispatched for rig-cap", plan)
+	}
+}
+
+func TestDispatchCycle_FairShare(t *testing.T) {
+	var dispatched []string
+	cycle := &DispatchCycle{
+		AvailableCapacity: func() (int, error) { return 2, nil },
+		QueryPending: func() ([]PendingBead, error) {
+			return []PendingBead{
+				{ID: "n1", TargetRig: "noisy", Priority: 2},
+				{ID: "n2", TargetRig: "noisy", Priority: 2},
+				{ID: "q1", TargetRig: "quiet", Priority: 2},
+			}, nil
+		},
+		Execute: func(b PendingBead) error {
+			dispatched = append(dispatched, b.ID)
+			return nil
+		},
+		ActiveByRig: func() (map[string]int, error) { return map[string]int{"noisy": 1}, nil },
+		BatchSize:   2,
+	}
+
+	report, err := cycle.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Dispatched != 2 || strings.Join(dispatched, ",") != "q1,n1" {
+		t.Errorf("dispatched %v (report %+v), want q1,n1", dispatched, report)
+	}
+}
+
+func TestSchedulerConfig_RigCapAndWeight(t *testing.T) {
+	var nilCfg *SchedulerConfig
+	if nilCfg.GetRigCap("x") != 0 || nilCfg.GetRigWeight("x") != 1 {
+		t.Error("nil config should be uncapped with weight 1")
+	}
+
+	three, none := 3, -1
+	cfg := &SchedulerConfig{
+		MaxPolecatsPerRig: &three,
+		RigMaxPolecats:    map[string]int{"big": 8, "free": -1},
+		RigWeights:        map[string]int{"big": 3, "bad": 0},
+	}
+	if cfg.GetRigCap("other") != 3 || cfg.GetRigCap("big") != 8 || cfg.GetRigCap("free") != 0 {
+		t.Errorf("rig caps = %d/%d/%d", cfg.GetRigCap("other"), cfg.GetRigCap("big"), cfg.GetRigCap("free"))
+	}
+	if cfg.GetRigWeight("big") != 3 || cfg.GetRigWeight("bad") != 1 || cfg.GetRigWeight("other") != 1 {
+		t.Error("unexpected rig weights")
+	}
+	cfg.MaxPolecatsPerRig = &none
+	if cfg.GetRigCap("other") != 0 {
+		t.Error("max_polecats_per_rig -1 should mean uncapped")
+	}
+}
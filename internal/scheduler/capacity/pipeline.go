@@ -1,6 +1,9 @@
 package capacity
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 // PendingBead represents a bead that is scheduled and ready for dispatch evaluation.
 type PendingBead struct {
@@ -8,9 +11,14 @@ type PendingBead struct {
 	WorkBeadID  string             // The actual work bead ID
 	Title       string
 	TargetRig   string
+	Agent       string // Agent preset chosen by auto-routing (empty: the context's)
 	Description string
 	Labels      []string
 	Context     *SlingContextFields // Parsed sling params from context bead
+
+	// Ordering inputs (see OrderPending).
+	Priority        int       // Work bead priority (0 = highest, DefaultPriority if unknown)
+	ConvoyCreatedAt time.Time // When the bead's convoy was created (zero if none)
 }
 
 // SlingContextFields holds scheduling parameters stored on a sling context bead.
@@ -32,6 +40,7 @@ type SlingContextFields struct {
 	HookRawBead      bool   `json:"hook_raw_bead,omitempty"`
 	Owned            bool   `json:"owned,omitempty"`
 	Mode             string `json:"mode,omitempty"`
+	AutoRoute        bool   `json:"auto_route,omitempty"` // re-route rig and agent at dispatch (gt sling --auto)
 	DispatchFailures int    `json:"dispatch_failures,omitempty"`
 	LastFailure      string `json:"last_failure,omitempty"`
 }
@@ -43,7 +52,8 @@ const LabelSlingContext = "gt:sling-context"
 type DispatchPlan struct {
 	ToDispatch []PendingBead
 	Skipped    int
-	Reason     string // "capacity" | "batch" | "ready" | "none"
+	RigCapped  int    // Of Skipped, beads held back by per-rig caps
+	Reason     string // "capacity" | "batch" | "ready" | "rig-cap" | "none"
 }
 
 // FailureAction indicates what to do after a dispatch failure.
@@ -0,0 +1,329 @@
+package capacity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CapacityWindow overrides scheduler limits during a recurring time window,
+// e.g. 4 polecats during business hours when humans share the API quota.
+// Windows are checked in order; the first match wins.
+type CapacityWindow struct {
+	// Name labels the window in status output (e.g., "business-hours").
+	Name string `json:"name"`
+
+	// Days limits the window to these weekdays ("mon".."sun", "weekdays",
+	// "weekends"). Empty = every day.
+	Days []string `json:"days,omitempty"`
+
+	// Start and End are "HH:MM" in the scheduler timezone. A window whose
+	// End is not after Start wraps past midnight and belongs to the day it
+	// starts on. Start == End covers the whole day.
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// MaxPolecats replaces scheduler.max_polecats while the window is active.
+	MaxPolecats *int `json:"max_polecats,omitempty"`
+
+	// BatchSize replaces scheduler.batch_size while the window is active.
+	BatchSize *int `json:"batch_size,omitempty"`
+}
+
+// BlackoutWindow pauses deferred dispatch, e.g. a freeze around a release.
+// A blackout is either recurring (Days/Start/End, like CapacityWindow) or a
+// one-off range (From/Until).
+type BlackoutWindow struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"`
+
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start,omitempty"`
+	End   string   `json:"end,omitempty"`
+
+	// From and Until bound a one-off blackout. Both accept RFC3339 or
+	// "YYYY-MM-DD HH:MM" / "YYYY-MM-DD" in the scheduler timezone.
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+}
+
+// DispatchPolicy is the effective scheduler policy at a point in time.
+type DispatchPolicy struct {
+	MaxPolecats int
+	BatchSize   int
+
+	// Window is the active capacity window, or nil for the base settings.
+	Window *CapacityWindow
+
+	// Blackout is the active blackout, or nil when dispatch may run.
+	Blackout *BlackoutWindow
+
+	// NextChange is when the policy next changes, zero if it does not
+	// change within the next week.
+	NextChange time.Time
+
+	// BlackoutEnds is when the active blackout ends, zero if unknown.
+	BlackoutEnds time.Time
+}
+
+// policyLookahead bounds the search for the next policy change.
+const policyLookahead = 8 * 24 * time.Hour
+
+// Location returns the scheduler timezone (default: local time).
+func (c *SchedulerConfig) Location() (*time.Location, error) {
+	if c == nil || c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduler timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// HasSchedule reports whether any capacity or blackout windows are configured.
+func (c *SchedulerConfig) HasSchedule() bool {
+	return c != nil && (len(c.Windows) > 0 || len(c.Blackouts) > 0)
+}
+
+// Validate checks the timezone and every capacity and blackout window.
+func (c *SchedulerConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	loc, err := c.Location()
+	if err != nil {
+		return err
+	}
+	for i, w := range c.Windows {
+		label := fmt.Sprintf("scheduler.windows[%d]", i)
+		if w.Name != "" {
+			label += " (" + w.Name + ")"
+		}
+		if err := validateRecurring(label, w.Start, w.End, w.Days); err != nil {
+			return err
+		}
+		if w.MaxPolecats == nil && w.BatchSize == nil {
+			return fmt.Errorf("%s: set max_polecats and/or batch_size", label)
+		}
+		if w.MaxPolecats != nil && *w.MaxPolecats <= 0 {
+			return fmt.Errorf("%s: max_polecats must be > 0 (use a blackout to pause dispatch)", label)
+		}
+		if w.BatchSize != nil && *w.BatchSize <= 0 {
+			return fmt.Errorf("%s: batch_size must be > 0", label)
+		}
+	}
+	for i, b := range c.Blackouts {
+		label := fmt.Sprintf("scheduler.blackouts[%d]", i)
+		if b.Name != "" {
+			label += " (" + b.Name + ")"
+		}
+		recurring := b.Start != "" || b.End != "" || len(b.Days) > 0
+		oneOff := b.From != "" || b.Until != ""
+		switch {
+		case recurring && oneOff:
+			return fmt.Errorf("%s: use either start/end/days or from/until, not both", label)
+		case recurring:
+			if err := validateRecurring(label, b.Start, b.End, b.Days); err != nil {
+				return err
+			}
+		case oneOff:
+			from, until, err := b.bounds(loc)
+			if err != nil {
+				return fmt.Errorf("%s: %w", label, err)
+			}
+			if !from.IsZero() && !until.IsZero() && !until.After(from) {
+				return fmt.Errorf("%s: until must be after from", label)
+			}
+		default:
+			return fmt.Errorf("%s: set start/end or from/until", label)
+		}
+	}
+	return nil
+}
+
+// PolicyAt returns the effective dispatch policy at t. Callers should
+// Validate first; malformed windows never match.
+func (c *SchedulerConfig) PolicyAt(t time.Time) DispatchPolicy {
+	base := DispatchPolicy{MaxPolecats: c.GetMaxPolecats(), BatchSize: c.GetBatchSize()}
+	if !c.HasSchedule() {
+		return base
+	}
+	loc, err := c.Location()
+	if err != nil {
+		return base
+	}
+
+	p := c.policyAt(t, loc)
+	if p.Blackout != nil && p.Blackout.Until != "" {
+		_, p.BlackoutEnds, _ = p.Blackout.bounds(loc)
+	}
+	// Policies change on minute boundaries; step to the next one.
+	for next := t.Truncate(time.Minute).Add(time.Minute); next.Sub(t) <= policyLookahead; next = next.Add(time.Minute) {
+		np := c.policyAt(next, loc)
+		if p.NextChange.IsZero() && !np.sameAs(p) {
+			p.NextChange = next
+		}
+		if p.Blackout != nil && p.BlackoutEnds.IsZero() && np.Blackout != p.Blackout {
+			p.BlackoutEnds = next
+		}
+		if !p.NextChange.IsZero() && (p.Blackout == nil || !p.BlackoutEnds.IsZero()) {
+			break
+		}
+	}
+	return p
+}
+
+func (c *SchedulerConfig) policyAt(t time.Time, loc *time.Location) DispatchPolicy {
+	p := DispatchPolicy{MaxPolecats: c.GetMaxPolecats(), BatchSize: c.GetBatchSize()}
+	local := t.In(loc)
+
+	for i := range c.Blackouts {
+		if c.Blackouts[i].activeAt(local, loc) {
+			p.Blackout = &c.Blackouts[i]
+			break
+		}
+	}
+	for i := range c.Windows {
+		w := &c.Windows[i]
+		if !inRecurringWindow(local, w.Start, w.End, w.Days) {
+			continue
+		}
+		p.Window = w
+		if w.MaxPolecats != nil {
+			p.MaxPolecats = *w.MaxPolecats
+		}
+		if w.BatchSize != nil {
+			p.BatchSize = *w.BatchSize
+		}
+		break
+	}
+	return p
+}
+
+func (p DispatchPolicy) sameAs(o DispatchPolicy) bool {
+	return p.MaxPolecats == o.MaxPolecats && p.BatchSize == o.BatchSize &&
+		p.Window == o.Window && p.Blackout == o.Blackout
+}
+
+// activeAt reports whether the blackout covers local time t.
+func (b *BlackoutWindow) activeAt(t time.Time, loc *time.Location) bool {
+	if b.From != "" || b.Until != "" {
+		from, until, err := b.bounds(loc)
+		if err != nil {
+			return false
+		}
+		return (from.IsZero() || !t.Before(from)) && (until.IsZero() || t.Before(until))
+	}
+	return inRecurringWindow(t, b.Start, b.End, b.Days)
+}
+
+// bounds parses From and Until; an unset bound is the zero time.
+func (b *BlackoutWindow) bounds(loc *time.Location) (from, until time.Time, err error) {
+	if b.From != "" {
+		if from, err = parseInstant(b.From, loc); err != nil {
+			return from, until, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if b.Until != "" {
+		if until, err = parseInstant(b.Until, loc); err != nil {
+			return from, until, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	return from, until, nil
+}
+
+// parseInstant accepts RFC3339, "YYYY-MM-DD HH:MM" or "YYYY-MM-DD" (midnight).
+func parseInstant(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not RFC3339, \"YYYY-MM-DD HH:MM\" or \"YYYY-MM-DD\"", s)
+}
+
+var scheduleDays = map[string][]time.Weekday{
+	"sun":      {time.Sunday},
+	"mon":      {time.Monday},
+	"tue":      {time.Tuesday},
+	"wed":      {time.Wednesday},
+	"thu":      {time.Thursday},
+	"fri":      {time.Friday},
+	"sat":      {time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+}
+
+func validateRecurring(label, start, end string, days []string) error {
+	if _, err := parseClock(start); err != nil {
+		return fmt.Errorf("%s: invalid start: %w", label, err)
+	}
+	if _, err := parseClock(end); err != nil {
+		return fmt.Errorf("%s: invalid end: %w", label, err)
+	}
+	for _, d := range days {
+		if _, ok := scheduleDays[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("%s: invalid day %q (use mon..sun, weekdays, weekends)", label, d)
+		}
+	}
+	return nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inRecurringWindow reports whether local time t falls in a daily window.
+// A window that wraps past midnight belongs to the day it starts on.
+func inRecurringWindow(t time.Time, start, end string, days []string) bool {
+	startMin, err := parseClock(start)
+	if err != nil {
+		return false
+	}
+	endMin, err := parseClock(end)
+	if err != nil {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+
+	day := t.Weekday()
+	switch {
+	case startMin == endMin:
+		// Whole day.
+	case startMin < endMin:
+		if now < startMin || now >= endMin {
+			return false
+		}
+	default:
+		// Wraps midnight: after start today, or before end on the day after start.
+		if now < endMin {
+			day = (day + 6) % 7
+		} else if now < startMin {
+			return false
+		}
+	}
+	return onDay(day, days)
+}
+
+func onDay(day time.Weekday, days []string) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		for _, wd := range scheduleDays[strings.ToLower(d)] {
+			if wd == day {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package capacity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func intp(n int) *int { return &n }
+
+func scheduleConfig() *SchedulerConfig {
+	return &SchedulerConfig{
+		MaxPolecats: intp(10),
+		BatchSize:   intp(2),
+		Timezone:    "UTC",
+		Windows: []CapacityWindow{
+			{Name: "business-hours", Days: []string{"weekdays"}, Start: "09:00", End: "18:00", MaxPolecats: intp(4)},
+			{Name: "overnight", Start: "22:00", End: "06:00", MaxPolecats: intp(20), BatchSize: intp(5)},
+		},
+		Blackouts: []BlackoutWindow{
+			{Name: "freeze", From: "2026-12-18", Until: "2027-01-04"},
+			{Name: "deploy", Days: []string{"thu"}, Start: "16:00", End: "17:00"},
+		},
+	}
+}
+
+func utc(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestPolicyAt_Windows(t *testing.T) {
+	cfg := scheduleConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	tests := []struct {
+		at         string
+		window     string
+		max, batch int
+	}{
+		{"2026-10-14 10:00", "business-hours", 4, 2}, // Wednesday
+		{"2026-10-17 10:00", "", 10, 2},              // Saturday
+		{"2026-10-14 19:00", "", 10, 2},
+		{"2026-10-14 23:30", "overnight", 20, 5},
+		{"2026-10-15 05:59", "overnight", 20, 5}, // wrapped past midnight
+		{"2026-10-15 06:00", "", 10, 2},
+	}
+	for _, tt := range tests {
+		p := cfg.PolicyAt(utc(tt.at))
+		got := ""
+		if p.Window != nil {
+			got = p.Window.Name
+		}
+		if got != tt.window || p.MaxPolecats != tt.max || p.BatchSize != tt.batch {
+			t.Errorf("PolicyAt(%s) = window %q max %d batch %d, want %q %d %d",
+				tt.at, got, p.MaxPolecats, p.BatchSize, tt.window, tt.max, tt.batch)
+		}
+	}
+}
+
+func TestPolicyAt_WrappedWindowBelongsToStartDay(t *testing.T) {
+	cfg := &SchedulerConfig{
+		MaxPolecats: intp(10),
+		Timezone:    "UTC",
+		Windows:     []CapacityWindow{{Days: []string{"fri"}, Start: "22:00", End: "02:00", MaxPolecats: intp(3)}},
+	}
+	if p := cfg.PolicyAt(utc("2026-10-17 01:00")); p.Window == nil { // Saturday 01:00, Friday's window
+		t.Error("expected Friday's window to cover Saturday 01:00")
+	}
+	if p := cfg.PolicyAt(utc("2026-10-16 01:00")); p.Window != nil { // Friday 01:00, Thursday's slot
+		t.Error("expected no window on Friday 01:00")
+	}
+}
+
+func TestPolicyAt_Blackouts(t *testing.T) {
+	cfg := scheduleConfig()
+
+	p := cfg.PolicyAt(utc("2026-12-25 12:00"))
+	if p.Blackout == nil || p.Blackout.Name != "freeze" {
+		t.Fatalf("expected freeze blackout, got %+v", p.Blackout)
+	}
+	if want := utc("2027-01-04 00:00"); !p.BlackoutEnds.Equal(want) {
+		t.Errorf("BlackoutEnds = %v, want %v", p.BlackoutEnds, want)
+	}
+	if want := utc("2026-12-25 18:00"); !p.NextChange.Equal(want) {
+		t.Errorf("NextChange = %v, want %v", p.NextChange, want)
+	}
+
+	p = cfg.PolicyAt(utc("2026-10-15 16:30")) // Thursday
+	if p.Blackout == nil || p.Blackout.Name != "deploy" {
+		t.Fatalf("expected deploy blackout, got %+v", p.Blackout)
+	}
+	if want := utc("2026-10-15 17:00"); !p.BlackoutEnds.Equal(want) {
+		t.Errorf("BlackoutEnds = %v, want %v", p.BlackoutEnds, want)
+	}
+	if p.Window == nil || p.Window.Name != "business-hours" {
+		t.Errorf("expected business-hours window during blackout, got %+v", p.Window)
+	}
+
+	if p := cfg.PolicyAt(utc("2026-10-14 16:30")); p.Blackout != nil { // Wednesday
+		t.Errorf("unexpected blackout %q on Wednesday", p.Blackout.Name)
+	}
+}
+
+func TestPolicyAt_NextChange(t *testing.T) {
+	cfg := scheduleConfig()
+	p := cfg.PolicyAt(utc("2026-10-14 10:30").Add(15 * time.Second))
+	if want := utc("2026-10-14 18:00"); !p.NextChange.Equal(want) {
+		t.Errorf("NextChange = %v, want %v", p.NextChange, want)
+	}
+}
+
+func TestPolicyAt_NoSchedule(t *testing.T) {
+	cfg := &SchedulerConfig{MaxPolecats: intp(5)}
+	p := cfg.PolicyAt(time.Now())
+	if p.MaxPolecats != 5 || p.BatchSize != 1 || p.Window != nil || p.Blackout != nil || !p.NextChange.IsZero() {
+		t.Errorf("unexpected policy without schedule: %+v", p)
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SchedulerConfig
+		want string
+	}{
+		{"bad timezone", SchedulerConfig{Timezone: "Mars/Olympus"}, "timezone"},
+		{"bad clock", SchedulerConfig{Windows: []CapacityWindow{{Start: "9am", End: "18:00", MaxPolecats: intp(1)}}}, "invalid start"},
+		{"bad day", SchedulerConfig{Windows: []CapacityWindow{{Days: []string{"someday"}, Start: "09:00", End: "18:00", MaxPolecats: intp(1)}}}, "invalid day"},
+		{"no limits", SchedulerConfig{Windows: []CapacityWindow{{Start: "09:00", End: "18:00"}}}, "max_polecats and/or batch_size"},
+		{"zero max", SchedulerConfig{Windows: []CapacityWindow{{Start: "09:00", End: "18:00", MaxPolecats: intp(0)}}}, "use a blackout"},
+		{"mixed blackout", SchedulerConfig{Blackouts: []BlackoutWindow{{Start: "09:00", End: "10:00", From: "2026-01-01"}}}, "not both"},
+		{"empty blackout", SchedulerConfig{Blackouts: []BlackoutWindow{{Name: "x"}}}, "set start/end or from/until"},
+		{"reversed range", SchedulerConfig{Blackouts: []BlackoutWindow{{From: "2026-02-01", Until: "2026-01-01"}}}, "until must be after from"},
+		{"bad instant", SchedulerConfig{Blackouts: []BlackoutWindow{{From: "soon"}}}, "invalid from"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/ptyd"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Backend is the session backend: the terminal multiplexer agent sessions
+// run in. *tmux.Tmux is the default; *ptyd.Client is a headless supervisor
+// for hosts without tmux (CI containers, systemd units).
+//
+// Every agent manager runs its sessions on a Backend. Features that only
+// tmux has (themes, key bindings, hooks, respawn-pane, startup dialogs,
+// attach) are reached by type-asserting to *tmux.Tmux and are skipped on
+// other backends.
+type Backend interface {
+	NewSession(name, workDir string) error
+	NewSessionWithCommand(name, workDir, command string) error
+	NewSessionWithCommandAndEnv(name, workDir, command string, env map[string]string) error
+	KillSessionWithProcesses(name string) error
+	SendKeys(session, keys string) error
+	SendKeysRaw(session, keys string) error
+	NudgeSession(session, message string) error
+	CapturePane(session string, lines int) (string, error)
+	HasSession(name string) (bool, error)
+	ListSessions() ([]string, error)
+	SetEnvironment(session, key, value string) error
+	GetEnvironment(session, key string) (string, error)
+	GetPanePID(session string) (string, error)
+	IsAgentAlive(session string) bool
+}
+
+var (
+	_ Backend = (*tmux.Tmux)(nil)
+	_ Backend = (*ptyd.Client)(nil)
+)
+
+// EnvBackend selects the session backend: "tmux" or "pty".
+const EnvBackend = "GT_SESSION_BACKEND"
+
+// Backend names.
+const (
+	BackendTmux = "tmux"
+	BackendPTY  = "pty"
+)
+
+// lookPath finds the tmux binary. Replaced in tests.
+var lookPath = exec.LookPath
+
+// BackendName returns the configured session backend: $GT_SESSION_BACKEND,
+// or tmux when it is installed and pty otherwise.
+func BackendName() string {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvBackend))) {
+	case BackendPTY:
+		return BackendPTY
+	case BackendTmux:
+		return BackendTmux
+	}
+	if _, err := lookPath("tmux"); err != nil {
+		return BackendPTY
+	}
+	return BackendTmux
+}
+
+// NewBackend returns the configured session backend.
+func NewBackend() Backend {
+	if BackendName() == BackendPTY {
+		return ptyd.NewClient(ptyd.SocketPath())
+	}
+	return tmux.NewTmux()
+}
+
+// ResolveBackend returns the configured session backend for a manager that
+// was handed a tmux client: t itself under tmux (so callers' tmux handles,
+// including test doubles, keep working), the pty supervisor otherwise.
+func ResolveBackend(t *tmux.Tmux) Backend {
+	if BackendName() == BackendPTY {
+		return ptyd.NewClient(ptyd.SocketPath())
+	}
+	if t == nil {
+		return tmux.NewTmux()
+	}
+	return t
+}
+
+// CheckSessionHealth reports whether a session and its agent are alive.
+// Under tmux it also reports sessions idle longer than maxInactivity as
+// hung; other backends don't track activity.
+func CheckSessionHealth(t Backend, name string, maxInactivity time.Duration) tmux.ZombieStatus {
+	if tm, ok := t.(*tmux.Tmux); ok {
+		return tm.CheckSessionHealth(name, maxInactivity)
+	}
+	if alive, err := t.HasSession(name); err != nil || !alive {
+		return tmux.SessionDead
+	}
+	if !t.IsAgentAlive(name) {
+		return tmux.AgentDead
+	}
+	return tmux.SessionHealthy
+}
+
+// GetSessionInfo returns details of a session. Backends other than tmux
+// only know its name.
+func GetSessionInfo(t Backend, name string) (*tmux.SessionInfo, error) {
+	if tm, ok := t.(*tmux.Tmux); ok {
+		return tm.GetSessionInfo(name)
+	}
+	return &tmux.SessionInfo{Name: name, Windows: 1}, nil
+}
+
+// KillSession ends a session. Under tmux this is a plain kill-session;
+// other backends always end the session's process group.
+func KillSession(t Backend, name string) error {
+	if tm, ok := t.(*tmux.Tmux); ok {
+		return tm.KillSession(name)
+	}
+	return t.KillSessionWithProcesses(name)
+}
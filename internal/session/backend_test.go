@@ -0,0 +1,107 @@
+package session
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/ptyd"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestBackendName(t *testing.T) {
+	old := lookPath
+	defer func() { lookPath = old }()
+	tmuxInstalled := true
+	lookPath = func(string) (string, error) {
+		if tmuxInstalled {
+			return "/usr/bin/tmux", nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	tests := []struct {
+		env       string
+		installed bool
+		want      string
+	}{
+		{"", true, BackendTmux},
+		{"", false, BackendPTY},
+		{"pty", true, BackendPTY},
+		{"PTY", true, BackendPTY},
+		{"tmux", false, BackendTmux},
+		{"bogus", false, BackendPTY},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvBackend, tt.env)
+		tmuxInstalled = tt.installed
+		if got := BackendName(); got != tt.want {
+			t.Errorf("BackendName(env=%q, tmux installed=%v) = %q, want %q", tt.env, tt.installed, got, tt.want)
+		}
+	}
+
+	t.Setenv(EnvBackend, "pty")
+	if _, ok := NewBackend().(*ptyd.Client); !ok {
+		t.Error("NewBackend with pty backend is not a ptyd client")
+	}
+	t.Setenv(EnvBackend, "tmux")
+	if _, ok := NewBackend().(*tmux.Tmux); !ok {
+		t.Error("NewBackend with tmux backend is not tmux")
+	}
+}
+
+func TestResolveBackend(t *testing.T) {
+	tm := tmux.NewTmux()
+	t.Setenv(EnvBackend, "tmux")
+	if got := ResolveBackend(tm); got != Backend(tm) {
+		t.Errorf("ResolveBackend with tmux backend = %T, want the given tmux client", got)
+	}
+	if _, ok := ResolveBackend(nil).(*tmux.Tmux); !ok {
+		t.Error("ResolveBackend(nil) with tmux backend is not tmux")
+	}
+	t.Setenv(EnvBackend, "pty")
+	if _, ok := ResolveBackend(tm).(*ptyd.Client); !ok {
+		t.Error("ResolveBackend with pty backend is not a ptyd client")
+	}
+}
+
+// fakeBackend is a non-tmux Backend with a fixed set of sessions.
+type fakeBackend struct {
+	Backend
+	sessions map[string]bool // name -> agent alive
+	killed   []string
+}
+
+func (f *fakeBackend) HasSession(name string) (bool, error) {
+	_, ok := f.sessions[name]
+	return ok, nil
+}
+
+func (f *fakeBackend) IsAgentAlive(name string) bool { return f.sessions[name] }
+
+func (f *fakeBackend) KillSessionWithProcesses(name string) error {
+	f.killed = append(f.killed, name)
+	return nil
+}
+
+func TestNonTmuxBackendHelpers(t *testing.T) {
+	f := &fakeBackend{sessions: map[string]bool{"alive": true, "zombie": false}}
+
+	for name, want := range map[string]tmux.ZombieStatus{
+		"alive":   tmux.SessionHealthy,
+		"zombie":  tmux.AgentDead,
+		"missing": tmux.SessionDead,
+	} {
+		if got := CheckSessionHealth(f, name, 0); got != want {
+			t.Errorf("CheckSessionHealth(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	info, err := GetSessionInfo(f, "alive")
+	if err != nil || info.Name != "alive" {
+		t.Errorf("GetSessionInfo = %+v, %v; want name alive", info, err)
+	}
+
+	if err := KillSession(f, "alive"); err != nil || len(f.killed) != 1 || f.killed[0] != "alive" {
+		t.Errorf("KillSession killed %v (err %v), want [alive]", f.killed, err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // SessionConfig describes how to create and start a tmux session.
@@ -133,8 +134,22 @@ type StartResult struct {
 // Role-specific concerns (issue validation, fallback nudges, pane-died hooks,
 // crew cycle bindings, etc.) should be handled by the caller before/after
 // calling StartSession.
-func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error) {
-	defer func() { telemetry.RecordSessionStart(context.Background(), cfg.SessionID, cfg.Role, retErr) }()
+func StartSession(t Backend, cfg SessionConfig) (_ *StartResult, retErr error) {
+	ctx, span := telemetry.StartSpan(context.Background(), "session.start",
+		attribute.String("gt.role", cfg.Role),
+		attribute.String("gt.rig", cfg.RigName),
+		attribute.String("gt.session", cfg.SessionID),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+		telemetry.RecordSessionStart(ctx, cfg.SessionID, cfg.Role, retErr)
+	}()
+	// Sessions started as part of a trace (e.g., a witness or refinery
+	// brought up by gt sling) carry it; others don't start a trace of their own.
+	var traceParent string
+	if telemetry.CurrentTraceParent() != "" {
+		traceParent = telemetry.TraceParent(ctx)
+	}
 	if cfg.SessionID == "" {
 		return nil, fmt.Errorf("SessionID is required")
 	}
@@ -179,15 +194,22 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	if len(cfg.ExtraEnv) > 0 {
 		command = config.PrependEnv(command, cfg.ExtraEnv)
 	}
+	if traceParent != "" {
+		command = config.PrependEnv(command, map[string]string{"TRACEPARENT": traceParent})
+	}
 
-	// 4. Create tmux session with command.
+	// 4. Create session with command.
 	if err := t.NewSessionWithCommand(cfg.SessionID, cfg.WorkDir, command); err != nil {
 		return nil, fmt.Errorf("creating session: %w", err)
 	}
 
+	// Themes, hooks, dialogs and pane-command checks are tmux features;
+	// other backends skip them.
+	tm, isTmux := t.(*tmux.Tmux)
+
 	// 5. Set remain-on-exit immediately if requested (before anything else can fail).
-	if cfg.RemainOnExit {
-		_ = t.SetRemainOnExit(cfg.SessionID, true)
+	if cfg.RemainOnExit && isTmux {
+		_ = tm.SetRemainOnExit(cfg.SessionID, true)
 	}
 
 	// 6. Set environment variables.
@@ -198,6 +220,7 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 		TownRoot:         cfg.TownRoot,
 		RuntimeConfigDir: cfg.RuntimeConfigDir,
 		Agent:            cfg.AgentOverride,
+		TraceParent:      traceParent,
 	})
 	envVars = MergeRuntimeLivenessEnv(envVars, runtimeConfig)
 	for _, k := range mapKeysSorted(envVars) {
@@ -208,13 +231,13 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	}
 
 	// 7. Apply theme.
-	if cfg.Theme != nil {
-		_ = t.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
+	if cfg.Theme != nil && isTmux {
+		_ = tm.ConfigureGasTownSession(cfg.SessionID, *cfg.Theme, cfg.RigName, cfg.AgentName, cfg.Role)
 	}
 
 	// 8. Wait for agent to start.
-	if cfg.WaitForAgent {
-		if err := t.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if cfg.WaitForAgent && isTmux {
+		if err := tm.WaitForCommand(cfg.SessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 			if cfg.WaitFatal {
 				_ = t.KillSessionWithProcesses(cfg.SessionID)
 				return nil, fmt.Errorf("waiting for %s to start: %w", cfg.Role, err)
@@ -223,22 +246,22 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	}
 
 	// 9. Auto-respawn hook.
-	if cfg.AutoRespawn {
-		if err := t.SetAutoRespawnHook(cfg.SessionID); err != nil {
+	if cfg.AutoRespawn && isTmux {
+		if err := tm.SetAutoRespawnHook(cfg.SessionID); err != nil {
 			fmt.Printf("warning: failed to set auto-respawn hook for %s: %v\n", cfg.Role, err)
 		}
 	}
 
 	// 10. Accept startup dialogs (workspace trust + bypass permissions).
-	if cfg.AcceptBypass {
-		_ = t.AcceptStartupDialogs(cfg.SessionID)
+	if cfg.AcceptBypass && isTmux {
+		_ = tm.AcceptStartupDialogs(cfg.SessionID)
 	}
 
 	// 11. Ready delay: wait for agent to be fully ready at the prompt.
 	// Uses prompt-based polling for agents with ReadyPromptPrefix,
 	// falling back to ReadyDelayMs sleep for agents without prompt detection.
 	if cfg.ReadyDelay {
-		if err := t.WaitForRuntimeReady(cfg.SessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
+		if err := WaitForRuntimeReady(t, cfg.SessionID, runtimeConfig, constants.ClaudeStartTimeout); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: agent readiness detection timed out for %s: %v\n", cfg.SessionID, err)
 		}
 	}
@@ -264,11 +287,11 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (_ *StartResult, retErr error
 	return &StartResult{RuntimeConfig: runtimeConfig}, nil
 }
 
-// StopSession stops a tmux session with optional graceful shutdown.
+// StopSession stops a session with optional graceful shutdown.
 //
 // If graceful is true, sends Ctrl-C first and waits for the session to exit
 // before force-killing. This allows the agent to clean up.
-func StopSession(t *tmux.Tmux, sessionID string, graceful bool) error {
+func StopSession(t Backend, sessionID string, graceful bool) error {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
@@ -347,7 +370,7 @@ func MergeRuntimeLivenessEnv(envVars map[string]string, runtimeConfig *config.Ru
 // If checkAlive is true, only kills zombie sessions (tmux alive but agent dead).
 // If the session exists and the agent is alive, returns ErrAlreadyRunning.
 // If checkAlive is false, kills any existing session unconditionally.
-func KillExistingSession(t *tmux.Tmux, sessionID string, checkAlive bool) (bool, error) {
+func KillExistingSession(t Backend, sessionID string, checkAlive bool) (bool, error) {
 	running, err := t.HasSession(sessionID)
 	if err != nil {
 		return false, fmt.Errorf("checking session: %w", err)
@@ -367,6 +390,37 @@ func KillExistingSession(t *tmux.Tmux, sessionID string, checkAlive bool) (bool,
 	return true, nil
 }
 
+// WaitForRuntimeReady waits for the agent in a session to reach its ready
+// prompt. tmux sessions use tmux's own detection; other backends poll the
+// captured output for the runtime's ReadyPromptPrefix, falling back to the
+// fixed ReadyDelayMs.
+func WaitForRuntimeReady(t Backend, sessionID string, rc *config.RuntimeConfig, timeout time.Duration) error {
+	if tm, ok := t.(*tmux.Tmux); ok {
+		return tm.WaitForRuntimeReady(sessionID, rc, timeout)
+	}
+	if rc == nil || rc.Tmux == nil {
+		return nil
+	}
+	if rc.Tmux.ReadyPromptPrefix == "" {
+		if rc.Tmux.ReadyDelayMs > 0 {
+			time.Sleep(min(time.Duration(rc.Tmux.ReadyDelayMs)*time.Millisecond, timeout))
+		}
+		return nil
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if out, err := t.CapturePane(sessionID, 10); err == nil {
+			for _, line := range strings.Split(out, "\n") {
+				if tmux.MatchesPromptPrefix(line, rc.Tmux.ReadyPromptPrefix) {
+					return nil
+				}
+			}
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return fmt.Errorf("timeout waiting for runtime prompt")
+}
+
 // buildPrompt creates the startup prompt from beacon + instructions.
 func buildPrompt(cfg SessionConfig) string {
 	if cfg.Instructions != "" {
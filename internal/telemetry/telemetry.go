@@ -1,12 +1,15 @@
-// Package telemetry initializes OpenTelemetry providers for metric and log export.
+// Package telemetry initializes OpenTelemetry providers for metric, log and
+// trace export.
 //
 // Metrics → VictoriaMetrics via OTLP HTTP
 // Logs    → VictoriaLogs via OTLP HTTP
+// Traces  → any OTLP HTTP trace collector (see trace.go)
 //
 // Enabled by setting at least one of:
 //
 //	GT_OTEL_METRICS_URL  (default: http://localhost:8428/opentelemetry/api/v1/push)
 //	GT_OTEL_LOGS_URL     (default: http://localhost:9428/insert/opentelemetry/v1/logs)
+//	GT_OTEL_TRACES_URL   (no default; traces are only exported when set)
 //
 // Telemetry is best-effort: initialization errors are returned but do not
 // affect normal gt operation — callers should log and continue.
@@ -94,13 +97,16 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 // issue. If multiple packages call Init, ensure the entry-point (main or
 // cobra root) calls it first with the correct service name.
 //
-// Returns (nil, nil) if neither GT_OTEL_METRICS_URL nor GT_OTEL_LOGS_URL is set,
-// so that telemetry is strictly opt-in. Set either variable to activate.
+// Returns (nil, nil) if none of GT_OTEL_METRICS_URL, GT_OTEL_LOGS_URL and
+// GT_OTEL_TRACES_URL is set, so that telemetry is strictly opt-in. Set any
+// variable to activate.
 //
-// When active, defaults are used for any unset endpoint:
+// When metrics or logs are active, defaults are used for the other endpoint:
 //
 //	metrics → http://localhost:8428/opentelemetry/api/v1/push
 //	logs    → http://localhost:9428/insert/opentelemetry/v1/logs
+//
+// Traces are exported only when GT_OTEL_TRACES_URL is set.
 func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, error) {
 	initMu.Lock()
 	defer initMu.Unlock()
@@ -110,19 +116,14 @@ func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, e
 
 	metricsURL := os.Getenv(EnvMetricsURL)
 	logsURL := os.Getenv(EnvLogsURL)
+	tracesURL := os.Getenv(EnvTracesURL)
 
-	// Both unset → telemetry disabled, not an error.
-	if metricsURL == "" && logsURL == "" {
+	// All unset → telemetry disabled, not an error.
+	if metricsURL == "" && logsURL == "" && tracesURL == "" {
 		initDone = true
 		globalProvider = nil
 		return nil, nil
 	}
-	if metricsURL == "" {
-		metricsURL = DefaultMetricsURL
-	}
-	if logsURL == "" {
-		logsURL = DefaultLogsURL
-	}
 
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
@@ -137,6 +138,28 @@ func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, e
 	}
 
 	p := &Provider{}
+	otel.SetTextMapPropagator(propagator)
+
+	// Traces → OTLP collector
+	if tracesURL != "" {
+		shutdown, err := initTracing(ctx, res, tracesURL)
+		if err != nil {
+			return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+		}
+		p.shutdowns = append(p.shutdowns, shutdown)
+	}
+
+	if metricsURL == "" && logsURL == "" {
+		initDone = true
+		globalProvider = p
+		return p, nil
+	}
+	if metricsURL == "" {
+		metricsURL = DefaultMetricsURL
+	}
+	if logsURL == "" {
+		logsURL = DefaultLogsURL
+	}
 
 	// Metrics → VictoriaMetrics
 	metricExp, err := otlpmetrichttp.New(ctx,
@@ -175,3 +198,16 @@ func Init(ctx context.Context, serviceName, serviceVersion string) (*Provider, e
 	globalProvider = p
 	return p, nil
 }
+
+// Shutdown flushes and stops the provider created by Init, if any.
+// For processes about to be killed from outside (e.g. gt done killing its own
+// session), where the deferred Provider.Shutdown in main would never run.
+func Shutdown(ctx context.Context) error {
+	initMu.Lock()
+	p := globalProvider
+	initMu.Unlock()
+	if p == nil {
+		return nil
+	}
+	return p.Shutdown(ctx)
+}
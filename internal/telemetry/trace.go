@@ -0,0 +1,160 @@
+// Package telemetry — trace.go
+// Distributed tracing across processes: gt sling → polecat session → bd calls
+// → gt done → refinery gates and merge all join one trace.
+//
+// Each gt process is short-lived, so the trace context travels between them
+// as a W3C traceparent:
+//   - In the environment as TRACEPARENT (agent sessions, bd subprocesses)
+//   - On beads as a trace_parent field (hooked work, merge requests), so
+//     processes started without it (respawned sessions, the refinery) can
+//     rejoin the trace
+//
+// Spans are only exported when GT_OTEL_TRACES_URL is set. Without it the
+// trace context is still propagated, so a downstream process that exports
+// spans stays connected to the trace.
+package telemetry
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// EnvTracesURL is the env var for the OTLP HTTP traces endpoint
+	// (e.g. http://localhost:4318/v1/traces for an OpenTelemetry Collector).
+	EnvTracesURL = "GT_OTEL_TRACES_URL"
+
+	// EnvTraceParent carries the W3C trace context between processes.
+	EnvTraceParent = "TRACEPARENT"
+
+	tracerName = "github.com/steveyegge/gastown"
+)
+
+var (
+	propagator = propagation.TraceContext{}
+
+	// processCtx is the parent of spans started from a context without one.
+	// Seeded from TRACEPARENT; replaced by SetTraceContext.
+	processMu     sync.Mutex
+	processCtx    context.Context
+	processSeeded bool
+)
+
+// initTracing installs an OTLP trace exporter as the global TracerProvider.
+func initTracing(ctx context.Context, res *resource.Resource, tracesURL string) (func(context.Context) error, error) {
+	exp, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(tracesURL))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exp, sdktrace.WithBatchTimeout(2*time.Second)),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// processContext returns the process-wide parent context.
+func processContext() context.Context {
+	processMu.Lock()
+	defer processMu.Unlock()
+	if !processSeeded {
+		processCtx = ContextWithTraceParent(context.Background(), os.Getenv(EnvTraceParent))
+		processSeeded = true
+	}
+	return processCtx
+}
+
+// StartSpan starts a span. When ctx carries no span, the span joins the
+// process trace context (see SetTraceContext), so gt commands run inside an
+// agent session become part of the trace that started the session.
+//
+// End the span with EndSpan.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		parent := processContext()
+		if ctx != nil {
+			parent = trace.ContextWithSpanContext(ctx, trace.SpanContextFromContext(parent))
+		}
+		ctx = parent
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err (if any) on span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetTraceContext makes ctx's span the parent of spans this process starts
+// without one, and of the processes it starts: TRACEPARENT is updated in the
+// process environment so bd subprocesses and new agent sessions inherit it.
+func SetTraceContext(ctx context.Context) {
+	tp := TraceParent(ctx)
+	if tp == "" {
+		return
+	}
+	processMu.Lock()
+	processCtx = trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	processSeeded = true
+	processMu.Unlock()
+	_ = os.Setenv(EnvTraceParent, tp)
+}
+
+// TraceParent returns the W3C traceparent of ctx's span, or "" if none.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// CurrentTraceParent returns the process trace context as a traceparent,
+// or "" if the process is not part of a trace.
+func CurrentTraceParent() string {
+	return TraceParent(processContext())
+}
+
+// ContextWithTraceParent returns ctx carrying the remote span context encoded
+// in traceParent. Invalid or empty values leave ctx unchanged.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	traceParent = strings.TrimSpace(traceParent)
+	if traceParent == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
+
+// TraceEnv returns the TRACEPARENT variable for a subprocess whose work is
+// part of ctx's span. Returns nil when ctx carries no span.
+func TraceEnv(ctx context.Context) []string {
+	tp := TraceParent(ctx)
+	if tp == "" {
+		return nil
+	}
+	return []string{EnvTraceParent + "=" + tp}
+}
+
+// LinkTraceParent links span to the span encoded in traceParent, for work
+// that serves several traces at once (e.g. a merge train of several MRs).
+func LinkTraceParent(span trace.Span, traceParent string) {
+	sc := trace.SpanContextFromContext(ContextWithTraceParent(context.Background(), traceParent))
+	if sc.IsValid() {
+		span.AddLink(trace.Link{SpanContext: sc})
+	}
+}
@@ -0,0 +1,135 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// resetTraceState clears the process trace context and restores the global
+// tracer provider after the test.
+func resetTraceState(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		processMu.Lock()
+		processCtx, processSeeded = nil, false
+		processMu.Unlock()
+	}
+	reset()
+	prev := otel.GetTracerProvider()
+	t.Cleanup(func() {
+		reset()
+		otel.SetTracerProvider(prev)
+	})
+}
+
+func TestTraceParent_RoundTrip(t *testing.T) {
+	ctx := ContextWithTraceParent(context.Background(), testTraceParent)
+	if got := TraceParent(ctx); got != testTraceParent {
+		t.Errorf("TraceParent = %q, want %q", got, testTraceParent)
+	}
+	if got := ContextWithTraceParent(context.Background(), "garbage"); TraceParent(got) != "" {
+		t.Error("invalid traceparent should leave ctx without a span")
+	}
+	if env := TraceEnv(context.Background()); env != nil {
+		t.Errorf("TraceEnv without span = %v, want nil", env)
+	}
+	if env := TraceEnv(ctx); len(env) != 1 || env[0] != EnvTraceParent+"="+testTraceParent {
+		t.Errorf("TraceEnv = %v", env)
+	}
+}
+
+func TestStartSpan_JoinsTraceParentEnv(t *testing.T) {
+	resetTraceState(t)
+	t.Setenv(EnvTraceParent, testTraceParent)
+
+	ctx, span := StartSpan(context.Background(), "gt.done")
+	defer span.End()
+	if got := trace.SpanContextFromContext(ctx).TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("span trace ID = %s, want the TRACEPARENT trace", got)
+	}
+	if got := CurrentTraceParent(); got != testTraceParent {
+		t.Errorf("CurrentTraceParent = %q, want %q", got, testTraceParent)
+	}
+}
+
+func TestEndSpan_RecordsError(t *testing.T) {
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	_, span := otel.Tracer(tracerName).Start(context.Background(), "x")
+	EndSpan(span, errors.New("boom")) // must not panic on a non-recording span
+}
+
+// TestInit_TracesOnly_ExportsToCollector runs the sling → done chain against
+// an OTLP HTTP collector stand-in and checks both spans reach it in one trace.
+func TestInit_TracesOnly_ExportsToCollector(t *testing.T) {
+	resetInitState(t)
+	resetTraceState(t)
+
+	var mu sync.Mutex
+	var bodies [][]byte
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	t.Setenv(EnvMetricsURL, "")
+	t.Setenv(EnvLogsURL, "")
+	t.Setenv(EnvTracesURL, collector.URL+"/v1/traces")
+	t.Setenv(EnvTraceParent, "")
+
+	p, err := Init(context.Background(), "test-svc", "0.0.1")
+	if err != nil || p == nil {
+		t.Fatalf("Init = %v, %v; want provider", p, err)
+	}
+
+	// gt sling: the root span, recorded for child processes.
+	slingCtx, sling := StartSpan(context.Background(), "gt.sling")
+	SetTraceContext(slingCtx)
+	traceID := trace.SpanContextFromContext(slingCtx).TraceID()
+	if !strings.Contains(CurrentTraceParent(), traceID.String()) {
+		t.Fatalf("CurrentTraceParent = %q, want trace %s", CurrentTraceParent(), traceID)
+	}
+
+	// gt done in another process: joins via the persisted traceparent.
+	doneCtx, done := StartSpan(ContextWithTraceParent(context.Background(), CurrentTraceParent()), "gt.done")
+	if got := trace.SpanContextFromContext(doneCtx).TraceID(); got != traceID {
+		t.Errorf("gt.done trace = %s, want %s", got, traceID)
+	}
+	EndSpan(done, nil)
+	EndSpan(sling, nil)
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	all := bytes.Join(bodies, nil)
+	for _, name := range []string{"gt.sling", "gt.done"} {
+		if !bytes.Contains(all, []byte(name)) {
+			t.Errorf("collector did not receive span %s", name)
+		}
+	}
+	id, _ := hex.DecodeString(traceID.String())
+	if !bytes.Contains(all, id) {
+		t.Errorf("collector did not receive trace %s", traceID)
+	}
+}
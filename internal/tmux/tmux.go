@@ -1809,12 +1809,12 @@ func (t *Tmux) WaitForShellReady(session string, timeout time.Duration) error {
 //	- Deacon restarting → Mayor watches via 'gt peek'
 //	- Mayor restarting → Deacon watches via 'gt peek'
 
-// matchesPromptPrefix reports whether a captured pane line matches the
+// MatchesPromptPrefix reports whether a captured pane line matches the
 // configured ready-prompt prefix. It normalizes non-breaking spaces
 // (U+00A0) to regular spaces before matching, because Claude Code uses
 // NBSP after its ❯ prompt character while the default ReadyPromptPrefix
 // uses a regular space. See https://github.com/steveyegge/gastown/issues/1387.
-func matchesPromptPrefix(line, readyPromptPrefix string) bool {
+func MatchesPromptPrefix(line, readyPromptPrefix string) bool {
 	if readyPromptPrefix == "" {
 		return false
 	}
@@ -1855,7 +1855,7 @@ func (t *Tmux) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, tim
 		}
 		// Look for runtime prompt indicator at start of line
 		for _, line := range lines {
-			if matchesPromptPrefix(line, rc.Tmux.ReadyPromptPrefix) {
+			if MatchesPromptPrefix(line, rc.Tmux.ReadyPromptPrefix) {
 				return nil
 			}
 		}
@@ -1899,7 +1899,7 @@ func (t *Tmux) WaitForIdle(session string, timeout time.Duration) error {
 			if trimmed == "" {
 				continue
 			}
-			if matchesPromptPrefix(trimmed, promptPrefix) || (prefix != "" && trimmed == prefix) {
+			if MatchesPromptPrefix(trimmed, promptPrefix) || (prefix != "" && trimmed == prefix) {
 				return nil
 			}
 		}
@@ -1924,7 +1924,7 @@ func (t *Tmux) IsAtPrompt(session string, rc *config.RuntimeConfig) bool {
 	}
 
 	for _, line := range lines {
-		if matchesPromptPrefix(line, promptPrefix) {
+		if MatchesPromptPrefix(line, promptPrefix) {
 			return true
 		}
 	}
@@ -0,0 +1,778 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	beadsdk "github.com/steveyegge/beads"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// APIVersion is the version of the typed JSON API served under /api/v1.
+// Fields may be added to v1 responses; they are never renamed or removed.
+const APIVersion = "v1"
+
+// Pagination defaults for /api/v1 list endpoints.
+const (
+	v1DefaultLimit = 50
+	v1MaxLimit     = 500
+)
+
+// V1Page is the envelope for every /api/v1 list response.
+// NextOffset is omitted on the last page.
+type V1Page[T any] struct {
+	Items      []T  `json:"items"`
+	Total      int  `json:"total"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	NextOffset *int `json:"next_offset,omitempty"`
+}
+
+// V1Error is the envelope for every /api/v1 error response.
+type V1Error struct {
+	Error V1ErrorBody `json:"error"`
+}
+
+// V1ErrorBody describes an /api/v1 error. Code is a stable machine-readable
+// identifier; Message is for humans and may change.
+type V1ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// V1Rig is a rig registered in the town.
+type V1Rig struct {
+	Name         string   `json:"name"`
+	GitURL       string   `json:"git_url"`
+	Prefix       string   `json:"prefix,omitempty"`
+	Polecats     []string `json:"polecats"`
+	Crew         []string `json:"crew"`
+	HasWitness   bool     `json:"has_witness"`
+	HasRefinery  bool     `json:"has_refinery"`
+	HasMayorRepo bool     `json:"has_mayor_repo"`
+}
+
+// V1Polecat is a polecat worker in a rig.
+type V1Polecat struct {
+	Name      string    `json:"name"`
+	Rig       string    `json:"rig"`
+	State     string    `json:"state"`
+	Branch    string    `json:"branch"`
+	Issue     string    `json:"issue,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// V1CrewWorker is a crew workspace in a rig.
+type V1CrewWorker struct {
+	Name      string    `json:"name"`
+	Rig       string    `json:"rig"`
+	Branch    string    `json:"branch"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// V1MergeRequest is a merge request waiting in a rig's refinery queue.
+type V1MergeRequest struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Branch      string     `json:"branch"`
+	Target      string     `json:"target"`
+	SourceIssue string     `json:"source_issue,omitempty"`
+	Worker      string     `json:"worker,omitempty"`
+	Priority    int        `json:"priority"`
+	ConvoyID    string     `json:"convoy_id,omitempty"`
+	RetryCount  int        `json:"retry_count"`
+	Assignee    string     `json:"assignee,omitempty"`
+	BlockedBy   string     `json:"blocked_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// V1Issue is a beads issue.
+type V1Issue struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Status      string   `json:"status"`
+	Priority    int      `json:"priority"`
+	Type        string   `json:"type"`
+	Assignee    string   `json:"assignee,omitempty"`
+	Labels      []string `json:"labels"`
+	Parent      string   `json:"parent,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	ClosedAt    string   `json:"closed_at,omitempty"`
+}
+
+// V1Convoy is a convoy and the issues it tracks.
+// Tracked is only populated on the single-convoy endpoint.
+type V1Convoy struct {
+	ID        string           `json:"id"`
+	Title     string           `json:"title"`
+	Status    string           `json:"status"`
+	CreatedAt string           `json:"created_at"`
+	UpdatedAt string           `json:"updated_at"`
+	ClosedAt  string           `json:"closed_at,omitempty"`
+	Tracked   []V1TrackedIssue `json:"tracked,omitempty"`
+}
+
+// V1TrackedIssue is an issue tracked by a convoy.
+type V1TrackedIssue struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// V1Message is a mail message.
+type V1Message struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Read      bool      `json:"read"`
+	Priority  string    `json:"priority"`
+	Type      string    `json:"type"`
+	ThreadID  string    `json:"thread_id,omitempty"`
+	ReplyTo   string    `json:"reply_to,omitempty"`
+}
+
+// errV1NotFound is returned by a v1Source when the requested object does not exist.
+var errV1NotFound = errors.New("not found")
+
+// v1Source supplies the data behind /api/v1. The live implementation reads
+// directly from the Go packages; tests substitute a fake.
+type v1Source interface {
+	Rigs() ([]*rig.Rig, error)
+	Polecats(rigName string) ([]*polecat.Polecat, error)
+	Crew(rigName string) ([]*crew.CrewWorker, error)
+	MergeQueue(rigName string) ([]*refinery.MRInfo, error)
+	Issues(opts beads.ListOptions) ([]*beads.Issue, error)
+	Issue(id string) (*beads.Issue, error)
+	Convoys(status string) ([]*beadsdk.Issue, error)
+	ConvoyTracked(id string) ([]convoy.TrackedIssue, error)
+	Messages(address string) ([]*mail.Message, error)
+	Message(address, id string) (*mail.Message, error)
+	EventsPath() string
+}
+
+// townV1Source is the live v1Source for a town.
+type townV1Source struct {
+	townRoot string
+}
+
+func (s *townV1Source) rigManager() *rig.Manager {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(s.townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	return rig.NewManager(s.townRoot, rigsConfig, git.NewGit(s.townRoot))
+}
+
+func (s *townV1Source) getRig(name string) (*rig.Rig, error) {
+	r, err := s.rigManager().GetRig(name)
+	if errors.Is(err, rig.ErrRigNotFound) {
+		return nil, fmt.Errorf("rig %q: %w", name, errV1NotFound)
+	}
+	return r, err
+}
+
+func (s *townV1Source) Rigs() ([]*rig.Rig, error) {
+	return s.rigManager().DiscoverRigs()
+}
+
+func (s *townV1Source) Polecats(rigName string) ([]*polecat.Polecat, error) {
+	r, err := s.getRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+	return polecat.NewManager(r, git.NewGit(r.Path), tmux.NewTmux()).List()
+}
+
+func (s *townV1Source) Crew(rigName string) ([]*crew.CrewWorker, error) {
+	r, err := s.getRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+	return crew.NewManager(r, git.NewGit(r.Path)).List()
+}
+
+func (s *townV1Source) MergeQueue(rigName string) ([]*refinery.MRInfo, error) {
+	r, err := s.getRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+	return refinery.NewEngineer(r).ListReadyMRs()
+}
+
+func (s *townV1Source) Issues(opts beads.ListOptions) ([]*beads.Issue, error) {
+	return beads.New(s.townRoot).List(opts)
+}
+
+func (s *townV1Source) Issue(id string) (*beads.Issue, error) {
+	issue, err := beads.New(s.townRoot).Show(id)
+	if errors.Is(err, beads.ErrNotFound) {
+		return nil, fmt.Errorf("issue %q: %w", id, errV1NotFound)
+	}
+	return issue, err
+}
+
+// withStore runs fn against the town's beads store, the same store the
+// daemon's convoy checks read.
+func (s *townV1Source) withStore(fn func(ctx context.Context, store beadsdk.Storage) error) error {
+	ctx := context.Background()
+	store, err := beadsdk.OpenFromConfig(ctx, filepath.Join(s.townRoot, ".beads"))
+	if err != nil {
+		return fmt.Errorf("opening town beads: %w", err)
+	}
+	defer func() { _ = store.Close() }()
+	return fn(ctx, store)
+}
+
+func (s *townV1Source) Convoys(status string) ([]*beadsdk.Issue, error) {
+	if status == "all" {
+		status = ""
+	}
+	var convoys []*beadsdk.Issue
+	err := s.withStore(func(ctx context.Context, store beadsdk.Storage) error {
+		var err error
+		convoys, err = convoy.ListConvoys(ctx, store, status)
+		return err
+	})
+	return convoys, err
+}
+
+func (s *townV1Source) ConvoyTracked(id string) ([]convoy.TrackedIssue, error) {
+	var tracked []convoy.TrackedIssue
+	err := s.withStore(func(ctx context.Context, store beadsdk.Storage) error {
+		tracked = convoy.TrackedIssues(ctx, store, id)
+		return nil
+	})
+	return tracked, err
+}
+
+func (s *townV1Source) EventsPath() string {
+	return filepath.Join(s.townRoot, events.EventsFile)
+}
+
+func (s *townV1Source) mailbox(address string) (*mail.Mailbox, error) {
+	return mail.NewRouterWithTownRoot(s.townRoot, s.townRoot).GetMailbox(address)
+}
+
+func (s *townV1Source) Messages(address string) ([]*mail.Message, error) {
+	mb, err := s.mailbox(address)
+	if err != nil {
+		return nil, err
+	}
+	return mb.List()
+}
+
+func (s *townV1Source) Message(address, id string) (*mail.Message, error) {
+	mb, err := s.mailbox(address)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := mb.Get(id)
+	if errors.Is(err, mail.ErrMessageNotFound) {
+		return nil, fmt.Errorf("message %q: %w", id, errV1NotFound)
+	}
+	return msg, err
+}
+
+// V1Handler serves the typed JSON API under /api/v1.
+//
+// Unlike APIHandler, it never shells out to gt or parses CLI text: every
+// endpoint reads from the underlying Go packages and returns a stable schema.
+// All endpoints are read-only GETs; writes stay on the CSRF-protected /api.
+type V1Handler struct {
+	source v1Source
+}
+
+// NewV1Handler creates a v1 API handler for the town at townRoot.
+func NewV1Handler(townRoot string) *V1Handler {
+	return &V1Handler{source: &townV1Source{townRoot: townRoot}}
+}
+
+// ServeHTTP routes /api/v1 requests to the appropriate handler.
+func (h *V1Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		sendV1Error(w, http.StatusMethodNotAllowed, "method_not_allowed", "the v1 API is read-only")
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"+APIVersion), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "":
+		h.handleIndex(w)
+	case path == "rigs":
+		h.handleRigs(w, r)
+	case len(parts) == 3 && parts[0] == "rigs" && parts[2] == "polecats":
+		h.handlePolecats(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "rigs" && parts[2] == "crew":
+		h.handleCrew(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "rigs" && parts[2] == "merge-queue":
+		h.handleMergeQueue(w, r, parts[1])
+	case path == "issues":
+		h.handleIssues(w, r)
+	case len(parts) == 2 && parts[0] == "issues":
+		h.handleIssue(w, parts[1])
+	case path == "convoys":
+		h.handleConvoys(w, r)
+	case len(parts) == 2 && parts[0] == "convoys":
+		h.handleConvoy(w, parts[1])
+	case path == "mail":
+		h.handleMessages(w, r)
+	case len(parts) == 2 && parts[0] == "mail":
+		h.handleMessage(w, r, parts[1])
+	case path == "events/stream":
+		h.handleEventStream(w, r)
+	default:
+		sendV1Error(w, http.StatusNotFound, "not_found", "unknown endpoint: "+r.URL.Path)
+	}
+}
+
+func (h *V1Handler) handleIndex(w http.ResponseWriter) {
+	sendV1JSON(w, map[string]interface{}{
+		"version": APIVersion,
+		"endpoints": []string{
+			"/api/v1/rigs",
+			"/api/v1/rigs/{rig}/polecats",
+			"/api/v1/rigs/{rig}/crew",
+			"/api/v1/rigs/{rig}/merge-queue",
+			"/api/v1/issues",
+			"/api/v1/issues/{id}",
+			"/api/v1/convoys",
+			"/api/v1/convoys/{id}",
+			"/api/v1/mail",
+			"/api/v1/mail/{id}",
+			"/api/v1/events/stream",
+		},
+	})
+}
+
+func (h *V1Handler) handleRigs(w http.ResponseWriter, r *http.Request) {
+	rigs, err := h.source.Rigs()
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+
+	items := make([]V1Rig, 0, len(rigs))
+	for _, rg := range rigs {
+		item := V1Rig{
+			Name:         rg.Name,
+			GitURL:       rg.GitURL,
+			Polecats:     nonNil(rg.Polecats),
+			Crew:         nonNil(rg.Crew),
+			HasWitness:   rg.HasWitness,
+			HasRefinery:  rg.HasRefinery,
+			HasMayorRepo: rg.HasMayor,
+		}
+		if rg.Config != nil {
+			item.Prefix = rg.Config.Prefix
+		}
+		items = append(items, item)
+	}
+	sendV1Page(w, r, items)
+}
+
+func (h *V1Handler) handlePolecats(w http.ResponseWriter, r *http.Request, rigName string) {
+	polecats, err := h.source.Polecats(rigName)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	sort.Slice(polecats, func(i, j int) bool { return polecats[i].Name < polecats[j].Name })
+
+	items := make([]V1Polecat, 0, len(polecats))
+	for _, p := range polecats {
+		items = append(items, V1Polecat{
+			Name:      p.Name,
+			Rig:       p.Rig,
+			State:     string(p.State),
+			Branch:    p.Branch,
+			Issue:     p.Issue,
+			CreatedAt: p.CreatedAt,
+			UpdatedAt: p.UpdatedAt,
+		})
+	}
+	sendV1Page(w, r, items)
+}
+
+func (h *V1Handler) handleCrew(w http.ResponseWriter, r *http.Request, rigName string) {
+	workers, err := h.source.Crew(rigName)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].Name < workers[j].Name })
+
+	items := make([]V1CrewWorker, 0, len(workers))
+	for _, c := range workers {
+		items = append(items, V1CrewWorker{
+			Name:      c.Name,
+			Rig:       c.Rig,
+			Branch:    c.Branch,
+			CreatedAt: c.CreatedAt,
+			UpdatedAt: c.UpdatedAt,
+		})
+	}
+	sendV1Page(w, r, items)
+}
+
+func (h *V1Handler) handleMergeQueue(w http.ResponseWriter, r *http.Request, rigName string) {
+	mrs, err := h.source.MergeQueue(rigName)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	// Same order the refinery processes them in.
+	now := time.Now()
+	sort.SliceStable(mrs, func(i, j int) bool { return mrs[i].ScoreAt(now) > mrs[j].ScoreAt(now) })
+
+	items := make([]V1MergeRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		item := V1MergeRequest{
+			ID:          mr.ID,
+			Title:       mr.Title,
+			Branch:      mr.Branch,
+			Target:      mr.Target,
+			SourceIssue: mr.SourceIssue,
+			Worker:      mr.Worker,
+			Priority:    mr.Priority,
+			ConvoyID:    mr.ConvoyID,
+			RetryCount:  mr.RetryCount,
+			Assignee:    mr.Assignee,
+			BlockedBy:   mr.BlockedBy,
+			CreatedAt:   mr.CreatedAt,
+		}
+		if !mr.UpdatedAt.IsZero() {
+			updated := mr.UpdatedAt
+			item.UpdatedAt = &updated
+		}
+		items = append(items, item)
+	}
+	sendV1Page(w, r, items)
+}
+
+func (h *V1Handler) handleIssues(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := beads.ListOptions{
+		Status:   q.Get("status"),
+		Label:    q.Get("label"),
+		Assignee: q.Get("assignee"),
+		Priority: -1,
+	}
+	if opts.Status == "" {
+		opts.Status = "open"
+	}
+	if p := q.Get("priority"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 4 {
+			sendV1Error(w, http.StatusBadRequest, "invalid_parameter", "priority must be 0-4")
+			return
+		}
+		opts.Priority = n
+	}
+
+	issues, err := h.source.Issues(opts)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	items := make([]V1Issue, 0, len(issues))
+	for _, issue := range issues {
+		items = append(items, toV1Issue(issue, false))
+	}
+	sendV1Page(w, r, items)
+}
+
+func (h *V1Handler) handleIssue(w http.ResponseWriter, id string) {
+	if !isValidID(id) {
+		sendV1Error(w, http.StatusBadRequest, "invalid_parameter", "invalid issue ID")
+		return
+	}
+	issue, err := h.source.Issue(id)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	sendV1JSON(w, toV1Issue(issue, true))
+}
+
+func (h *V1Handler) handleConvoys(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = "open"
+	case "open", "closed", "all":
+	default:
+		sendV1Error(w, http.StatusBadRequest, "invalid_parameter", "status must be open, closed or all")
+		return
+	}
+
+	convoys, err := h.source.Convoys(status)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	items := make([]V1Convoy, 0, len(convoys))
+	for _, c := range convoys {
+		items = append(items, storeToV1Convoy(c))
+	}
+	sendV1Page(w, r, items)
+}
+
+func (h *V1Handler) handleConvoy(w http.ResponseWriter, id string) {
+	if !isValidID(id) {
+		sendV1Error(w, http.StatusBadRequest, "invalid_parameter", "invalid convoy ID")
+		return
+	}
+	issue, err := h.source.Issue(id)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	if issue.Type != "convoy" {
+		sendV1Error(w, http.StatusNotFound, "not_found", fmt.Sprintf("%s is not a convoy", id))
+		return
+	}
+	tracked, err := h.source.ConvoyTracked(id)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+
+	v := toV1Convoy(issue)
+	v.Tracked = make([]V1TrackedIssue, 0, len(tracked))
+	for _, t := range tracked {
+		v.Tracked = append(v.Tracked, V1TrackedIssue{
+			ID:     t.ID,
+			Title:  t.Title,
+			Status: t.Status,
+		})
+	}
+	sendV1JSON(w, v)
+}
+
+// v1MailAddress is the only mailbox the v1 API serves: the overseer's, the
+// human at the dashboard. Agent mailboxes stay private to their agents.
+const v1MailAddress = "overseer"
+
+// checkMailAddress rejects a mail request that names any mailbox but the
+// overseer's. It reports whether the request may proceed.
+func checkMailAddress(w http.ResponseWriter, r *http.Request) bool {
+	addr := r.URL.Query().Get("address")
+	if addr == "" || mail.AddressToIdentity(addr) == v1MailAddress {
+		return true
+	}
+	sendV1Error(w, http.StatusForbidden, "forbidden", "the v1 API only serves the overseer's mailbox")
+	return false
+}
+
+func (h *V1Handler) handleMessages(w http.ResponseWriter, r *http.Request) {
+	if !checkMailAddress(w, r) {
+		return
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+
+	msgs, err := h.source.Messages(v1MailAddress)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	// Newest first.
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Timestamp.After(msgs[j].Timestamp) })
+
+	items := make([]V1Message, 0, len(msgs))
+	for _, m := range msgs {
+		if unreadOnly && m.Read {
+			continue
+		}
+		// List responses omit bodies; fetch a single message for the full text.
+		items = append(items, toV1Message(m, false))
+	}
+	sendV1Page(w, r, items)
+}
+
+func (h *V1Handler) handleMessage(w http.ResponseWriter, r *http.Request, id string) {
+	if !isValidID(id) {
+		sendV1Error(w, http.StatusBadRequest, "invalid_parameter", "invalid message ID")
+		return
+	}
+	if !checkMailAddress(w, r) {
+		return
+	}
+	msg, err := h.source.Message(v1MailAddress, id)
+	if err != nil {
+		sendV1SourceError(w, err)
+		return
+	}
+	sendV1JSON(w, toV1Message(msg, true))
+}
+
+func toV1Issue(issue *beads.Issue, withDescription bool) V1Issue {
+	v := V1Issue{
+		ID:        issue.ID,
+		Title:     issue.Title,
+		Status:    issue.Status,
+		Priority:  issue.Priority,
+		Type:      issue.Type,
+		Assignee:  issue.Assignee,
+		Labels:    nonNil(issue.Labels),
+		Parent:    issue.Parent,
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.ClosedAt,
+	}
+	if withDescription {
+		v.Description = issue.Description
+	}
+	return v
+}
+
+func toV1Convoy(issue *beads.Issue) V1Convoy {
+	return V1Convoy{
+		ID:        issue.ID,
+		Title:     issue.Title,
+		Status:    issue.Status,
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.ClosedAt,
+	}
+}
+
+// storeToV1Convoy converts a convoy read from the beads store.
+func storeToV1Convoy(issue *beadsdk.Issue) V1Convoy {
+	v := V1Convoy{
+		ID:        issue.ID,
+		Title:     issue.Title,
+		Status:    string(issue.Status),
+		CreatedAt: issue.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: issue.UpdatedAt.Format(time.RFC3339),
+	}
+	if issue.ClosedAt != nil {
+		v.ClosedAt = issue.ClosedAt.Format(time.RFC3339)
+	}
+	return v
+}
+
+func toV1Message(m *mail.Message, withBody bool) V1Message {
+	v := V1Message{
+		ID:        m.ID,
+		From:      m.From,
+		To:        m.To,
+		Subject:   m.Subject,
+		Timestamp: m.Timestamp,
+		Read:      m.Read,
+		Priority:  string(m.Priority),
+		Type:      string(m.Type),
+		ThreadID:  m.ThreadID,
+		ReplyTo:   m.ReplyTo,
+	}
+	if withBody {
+		v.Body = m.Body
+	}
+	return v
+}
+
+// nonNil returns s, or an empty slice if s is nil, so it encodes as [] not null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// parsePagination reads the limit and offset query parameters.
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	limit, offset = v1DefaultLimit, 0
+	q := r.URL.Query()
+	if s := q.Get("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > v1MaxLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", v1MaxLimit)
+		}
+	}
+	if s := q.Get("offset"); s != "" {
+		offset, err = strconv.Atoi(s)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// paginate slices items into a V1Page.
+func paginate[T any](items []T, limit, offset int) V1Page[T] {
+	page := V1Page[T]{Total: len(items), Limit: limit, Offset: offset}
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	page.Items = items[offset:end]
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if end < len(items) {
+		page.NextOffset = &end
+	}
+	return page
+}
+
+func sendV1Page[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		sendV1Error(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	sendV1JSON(w, paginate(items, limit, offset))
+}
+
+func sendV1JSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func sendV1Error(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(V1Error{Error: V1ErrorBody{Code: code, Message: message}})
+}
+
+// sendV1SourceError maps a data source error to an HTTP error response.
+func sendV1SourceError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errV1NotFound) {
+		sendV1Error(w, http.StatusNotFound, "not_found", err.Error())
+		return
+	}
+	sendV1Error(w, http.StatusInternalServerError, "internal", err.Error())
+}
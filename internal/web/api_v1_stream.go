@@ -0,0 +1,228 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"golang.org/x/net/websocket"
+)
+
+// Event stream timing. The log is polled rather than watched so the stream
+// works the same on every filesystem the town may live on.
+const (
+	v1StreamPoll      = 500 * time.Millisecond
+	v1StreamKeepalive = 15 * time.Second
+)
+
+// V1Event is an event pushed by /api/v1/events/stream.
+type V1Event struct {
+	// ID is the stream cursor. Reconnect with it as Last-Event-ID (SSE) or
+	// ?last_event_id= to resume right after this event.
+	ID         string                 `json:"id"`
+	Timestamp  string                 `json:"ts"`
+	Source     string                 `json:"source"`
+	Type       string                 `json:"type"`
+	Actor      string                 `json:"actor"`
+	Rig        string                 `json:"rig,omitempty"`
+	Visibility string                 `json:"visibility"`
+	Payload    map[string]interface{} `json:"payload,omitempty"`
+}
+
+func toV1Event(rec events.Record) V1Event {
+	return V1Event{
+		ID:         strconv.FormatInt(rec.Offset, 10),
+		Timestamp:  rec.Event.Timestamp,
+		Source:     rec.Event.Source,
+		Type:       rec.Event.Type,
+		Actor:      rec.Event.Actor,
+		Rig:        events.EventRig(&rec.Event),
+		Visibility: rec.Event.Visibility,
+		Payload:    rec.Event.Payload,
+	}
+}
+
+// streamRequest is a parsed /api/v1/events/stream request.
+type streamRequest struct {
+	filter events.Filter
+	offset int64
+}
+
+// parseStreamRequest reads the filters and resume point of a stream request.
+// Filters (type, actor, rig, visibility) take comma-separated values and may
+// repeat. The stream resumes after Last-Event-ID / ?last_event_id=, replays
+// the whole log with ?from=start, and otherwise starts with new events.
+func parseStreamRequest(r *http.Request, logPath string) (*streamRequest, error) {
+	q := r.URL.Query()
+	list := func(key string) []string {
+		var values []string
+		for _, v := range q[key] {
+			for _, s := range strings.Split(v, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					values = append(values, s)
+				}
+			}
+		}
+		return values
+	}
+
+	req := &streamRequest{filter: events.Filter{
+		Types:      list("type"),
+		Actors:     list("actor"),
+		Rigs:       list("rig"),
+		Visibility: list("visibility"),
+	}}
+	for _, v := range req.filter.Visibility {
+		if v != events.VisibilityAudit && v != events.VisibilityFeed {
+			return nil, fmt.Errorf("visibility must be audit or feed")
+		}
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if id := q.Get("last_event_id"); id != "" {
+		lastID = id
+	}
+	switch {
+	case lastID != "":
+		offset, err := strconv.ParseInt(lastID, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("last_event_id must be an event ID from this stream")
+		}
+		req.offset = offset
+	case q.Get("from") == "start":
+		req.offset = 0
+	case q.Get("from") == "" || q.Get("from") == "end":
+		size, err := events.LogSize(logPath)
+		if err != nil {
+			return nil, err
+		}
+		req.offset = size
+	default:
+		return nil, fmt.Errorf("from must be start or end")
+	}
+	return req, nil
+}
+
+// handleEventStream pushes events from the town events log as they are
+// written, over WebSocket when the client asks to upgrade and Server-Sent
+// Events otherwise.
+func (h *V1Handler) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	logPath := h.source.EventsPath()
+	req, err := parseStreamRequest(r, logPath)
+	if err != nil {
+		sendV1Error(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		server := websocket.Server{
+			Handshake: checkSameOrigin,
+			Handler: func(ws *websocket.Conn) {
+				streamWebSocket(ws, logPath, req)
+			},
+		}
+		server.ServeHTTP(w, r)
+		return
+	}
+	streamSSE(w, r, logPath, req)
+}
+
+// checkSameOrigin rejects browser WebSocket connections opened by other
+// sites. Clients that send no Origin (bots, CLIs) are allowed.
+func checkSameOrigin(cfg *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host != r.Host {
+		return fmt.Errorf("cross-origin event stream not allowed: %s", origin)
+	}
+	cfg.Origin = u
+	return nil
+}
+
+// streamSSE writes each event as an SSE message whose id is its cursor and
+// whose event name is its type.
+func streamSSE(w http.ResponseWriter, r *http.Request, logPath string, req *streamRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendV1Error(w, http.StatusInternalServerError, "internal", "streaming not supported")
+		return
+	}
+	// The stream outlives the server's write timeout.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprintf(w, ": stream from %d\n\n", req.offset)
+	flusher.Flush()
+
+	// Keepalives come from a second goroutine, so writes are serialized.
+	var mu sync.Mutex
+	write := func(format string, args ...interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		keepalive := time.NewTicker(v1StreamKeepalive)
+		defer keepalive.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-keepalive.C:
+				if write(": keepalive\n\n") != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	_ = events.Follow(ctx, logPath, req.offset, req.filter, v1StreamPoll, func(rec events.Record) error {
+		data, err := json.Marshal(toV1Event(rec))
+		if err != nil {
+			return nil
+		}
+		return write("id: %d\nevent: %s\ndata: %s\n\n", rec.Offset, rec.Event.Type, data)
+	})
+}
+
+// streamWebSocket sends each event as a JSON text message. Messages from the
+// client are ignored; the stream ends when the client closes the connection.
+func streamWebSocket(ws *websocket.Conn, logPath string, req *streamRequest) {
+	defer ws.Close()
+	_ = ws.SetDeadline(time.Time{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// A hijacked connection has no request context; detect the close by reading.
+		var discard []byte
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+		cancel()
+	}()
+
+	_ = events.Follow(ctx, logPath, req.offset, req.filter, v1StreamPoll, func(rec events.Record) error {
+		return websocket.JSON.Send(ws, toV1Event(rec))
+	})
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"golang.org/x/net/websocket"
+)
+
+func writeTestEvents(t *testing.T, path string, evs ...events.Event) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range evs {
+		data, _ := json.Marshal(e)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newStreamServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), events.EventsFile)
+	srv := httptest.NewServer(&V1Handler{source: &fakeV1Source{events: path}})
+	t.Cleanup(srv.Close)
+	return srv, path
+}
+
+func TestV1EventStream_SSE(t *testing.T) {
+	srv, path := newStreamServer(t)
+	writeTestEvents(t, path,
+		events.Event{Type: events.TypeSling, Actor: "mayor", Visibility: events.VisibilityFeed},
+		events.Event{Type: events.TypeMerged, Actor: "gastown/refinery", Visibility: events.VisibilityFeed},
+		events.Event{Type: events.TypeMerged, Actor: "beads/refinery", Visibility: events.VisibilityFeed},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/events/stream?from=start&type=merged&rig=gastown,beads", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	readEvent := func(r *bufio.Reader) (id, name string, ev V1Event) {
+		t.Helper()
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				t.Fatalf("reading stream: %v", err)
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev); err != nil {
+					t.Fatal(err)
+				}
+			case line == "" && id != "":
+				return id, name, ev
+			}
+		}
+	}
+
+	r := bufio.NewReader(resp.Body)
+	id, name, ev := readEvent(r)
+	if name != events.TypeMerged || ev.Rig != "gastown" || ev.ID != id {
+		t.Errorf("first event = %s %+v (id %s)", name, ev, id)
+	}
+	_, _, ev = readEvent(r)
+	if ev.Rig != "beads" {
+		t.Errorf("second event rig = %q, want beads", ev.Rig)
+	}
+
+	// Events written after connecting are pushed live.
+	writeTestEvents(t, path, events.Event{Type: events.TypeMerged, Actor: "gastown/refinery", Payload: map[string]interface{}{"mr": "gt-mr1"}})
+	_, _, ev = readEvent(r)
+	if ev.Payload["mr"] != "gt-mr1" {
+		t.Errorf("live event = %+v", ev)
+	}
+
+	// Reconnecting with Last-Event-ID resumes after that event.
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/events/stream?type=merged", nil)
+	req.Header.Set("Last-Event-ID", id)
+	resumed, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Body.Close()
+	if _, _, ev = readEvent(bufio.NewReader(resumed.Body)); ev.Actor != "beads/refinery" {
+		t.Errorf("resumed event = %+v, want the beads merge", ev)
+	}
+}
+
+func TestV1EventStream_WebSocket(t *testing.T) {
+	srv, path := newStreamServer(t)
+	writeTestEvents(t, path, events.Event{Type: events.TypeSessionDeath, Actor: "daemon", Visibility: events.VisibilityBoth})
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/events/stream?from=start&visibility=audit"
+	ws, err := websocket.Dial(url, "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var ev V1Event
+	if err := websocket.JSON.Receive(ws, &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != events.TypeSessionDeath || ev.ID == "" {
+		t.Errorf("event = %+v", ev)
+	}
+
+	// Other sites may not open the stream from a browser.
+	if _, err := websocket.Dial(url, "", "http://evil.example.com"); err == nil {
+		t.Error("cross-origin WebSocket was accepted")
+	}
+}
+
+func TestV1EventStream_BadParameters(t *testing.T) {
+	h := &V1Handler{source: &fakeV1Source{events: filepath.Join(t.TempDir(), events.EventsFile)}}
+	for _, url := range []string{
+		"/api/v1/events/stream?visibility=secret",
+		"/api/v1/events/stream?last_event_id=abc",
+		"/api/v1/events/stream?from=yesterday",
+	} {
+		v1Get(t, h, url, http.StatusBadRequest, nil)
+	}
+}
@@ -0,0 +1,299 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	beadsdk "github.com/steveyegge/beads"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// fakeV1Source is an in-memory v1Source.
+type fakeV1Source struct {
+	rigs     []*rig.Rig
+	polecats map[string][]*polecat.Polecat
+	mrs      map[string][]*refinery.MRInfo
+	issues   []*beads.Issue
+	tracked  map[string][]convoy.TrackedIssue
+	messages map[string][]*mail.Message
+	lastList beads.ListOptions
+	events   string
+}
+
+func (f *fakeV1Source) Rigs() ([]*rig.Rig, error) { return f.rigs, nil }
+
+func (f *fakeV1Source) Polecats(rigName string) ([]*polecat.Polecat, error) {
+	p, ok := f.polecats[rigName]
+	if !ok {
+		return nil, fmt.Errorf("rig %q: %w", rigName, errV1NotFound)
+	}
+	return p, nil
+}
+
+func (f *fakeV1Source) Crew(rigName string) ([]*crew.CrewWorker, error) { return nil, nil }
+
+func (f *fakeV1Source) MergeQueue(rigName string) ([]*refinery.MRInfo, error) {
+	return f.mrs[rigName], nil
+}
+
+func (f *fakeV1Source) Issues(opts beads.ListOptions) ([]*beads.Issue, error) {
+	f.lastList = opts
+	return f.issues, nil
+}
+
+func (f *fakeV1Source) Issue(id string) (*beads.Issue, error) {
+	for _, i := range f.issues {
+		if i.ID == id {
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("issue %q: %w", id, errV1NotFound)
+}
+
+func (f *fakeV1Source) Convoys(status string) ([]*beadsdk.Issue, error) {
+	var out []*beadsdk.Issue
+	for _, i := range f.issues {
+		if i.Type == "convoy" {
+			out = append(out, &beadsdk.Issue{ID: i.ID, Title: i.Title, Status: beadsdk.Status(i.Status), IssueType: "convoy"})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeV1Source) ConvoyTracked(id string) ([]convoy.TrackedIssue, error) {
+	return f.tracked[id], nil
+}
+
+func (f *fakeV1Source) Messages(address string) ([]*mail.Message, error) {
+	return f.messages[address], nil
+}
+
+func (f *fakeV1Source) Message(address, id string) (*mail.Message, error) {
+	for _, m := range f.messages[address] {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("message %q: %w", id, errV1NotFound)
+}
+
+func (f *fakeV1Source) EventsPath() string { return f.events }
+
+func v1Get(t *testing.T, h http.Handler, url string, wantStatus int, out interface{}) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if rec.Code != wantStatus {
+		t.Fatalf("GET %s = %d, want %d: %s", url, rec.Code, wantStatus, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("GET %s Content-Type = %q", url, ct)
+	}
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("GET %s: decoding %s: %v", url, rec.Body.String(), err)
+		}
+	}
+}
+
+func TestV1Handler_Pagination(t *testing.T) {
+	src := &fakeV1Source{}
+	for i := 0; i < 5; i++ {
+		src.rigs = append(src.rigs, &rig.Rig{Name: fmt.Sprintf("rig%d", i)})
+	}
+	h := &V1Handler{source: src}
+
+	var page V1Page[V1Rig]
+	v1Get(t, h, "/api/v1/rigs?limit=2&offset=1", http.StatusOK, &page)
+	if page.Total != 5 || len(page.Items) != 2 || page.Items[0].Name != "rig1" {
+		t.Errorf("page = %+v", page)
+	}
+	if page.NextOffset == nil || *page.NextOffset != 3 {
+		t.Errorf("next_offset = %v, want 3", page.NextOffset)
+	}
+	if page.Items[0].Polecats == nil || page.Items[0].Crew == nil {
+		t.Error("empty lists should encode as [], not null")
+	}
+
+	page = V1Page[V1Rig]{}
+	v1Get(t, h, "/api/v1/rigs?offset=4", http.StatusOK, &page)
+	if len(page.Items) != 1 || page.NextOffset != nil {
+		t.Errorf("last page = %+v", page)
+	}
+
+	page = V1Page[V1Rig]{}
+	v1Get(t, h, "/api/v1/rigs?offset=10", http.StatusOK, &page)
+	if page.Items == nil || len(page.Items) != 0 {
+		t.Errorf("page past end should have empty items, got %+v", page)
+	}
+
+	var apiErr V1Error
+	v1Get(t, h, "/api/v1/rigs?limit=0", http.StatusBadRequest, &apiErr)
+	if apiErr.Error.Code != "invalid_parameter" {
+		t.Errorf("error code = %q", apiErr.Error.Code)
+	}
+	v1Get(t, h, "/api/v1/rigs?offset=-1", http.StatusBadRequest, nil)
+}
+
+func TestV1Handler_Errors(t *testing.T) {
+	h := &V1Handler{source: &fakeV1Source{}}
+
+	var apiErr V1Error
+	v1Get(t, h, "/api/v1/nope", http.StatusNotFound, &apiErr)
+	if apiErr.Error.Code != "not_found" {
+		t.Errorf("error code = %q", apiErr.Error.Code)
+	}
+	v1Get(t, h, "/api/v1/rigs/ghost/polecats", http.StatusNotFound, nil)
+	v1Get(t, h, "/api/v1/issues/bad;id", http.StatusBadRequest, nil)
+	v1Get(t, h, "/api/v1/issues?priority=9", http.StatusBadRequest, nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/rigs", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want 405", rec.Code)
+	}
+}
+
+func TestV1Handler_Resources(t *testing.T) {
+	now := time.Now()
+	src := &fakeV1Source{
+		polecats: map[string][]*polecat.Polecat{
+			"gastown": {
+				{Name: "toast", Rig: "gastown", State: polecat.StateWorking, Issue: "gt-1"},
+				{Name: "nux", Rig: "gastown", State: polecat.StateIdle},
+			},
+		},
+		mrs: map[string][]*refinery.MRInfo{
+			"gastown": {
+				{ID: "gt-mr-low", Priority: 3, CreatedAt: now},
+				{ID: "gt-mr-high", Priority: 0, CreatedAt: now},
+			},
+		},
+		issues: []*beads.Issue{
+			{ID: "gt-1", Title: "Fix it", Status: "open", Type: "task", Description: "details"},
+			{ID: "hq-cv-abc", Title: "Ship it", Status: "open", Type: "convoy"},
+		},
+		tracked: map[string][]convoy.TrackedIssue{
+			"hq-cv-abc": {{ID: "gt-1", Title: "Fix it", Status: "open"}},
+		},
+		messages: map[string][]*mail.Message{
+			"overseer": {
+				{ID: "hq-m1", Subject: "old", Body: "b1", Read: true, Timestamp: now.Add(-time.Hour)},
+				{ID: "hq-m2", Subject: "new", Body: "b2", Timestamp: now},
+			},
+		},
+	}
+	h := &V1Handler{source: src}
+
+	var polecats V1Page[V1Polecat]
+	v1Get(t, h, "/api/v1/rigs/gastown/polecats", http.StatusOK, &polecats)
+	if len(polecats.Items) != 2 || polecats.Items[0].Name != "nux" || polecats.Items[1].State != "working" {
+		t.Errorf("polecats = %+v", polecats.Items)
+	}
+
+	var mq V1Page[V1MergeRequest]
+	v1Get(t, h, "/api/v1/rigs/gastown/merge-queue", http.StatusOK, &mq)
+	if len(mq.Items) != 2 || mq.Items[0].ID != "gt-mr-high" {
+		t.Errorf("merge queue should be in processing order, got %+v", mq.Items)
+	}
+
+	var issues V1Page[V1Issue]
+	v1Get(t, h, "/api/v1/issues?label=gt:task&priority=1", http.StatusOK, &issues)
+	if src.lastList.Status != "open" || src.lastList.Label != "gt:task" || src.lastList.Priority != 1 {
+		t.Errorf("list options = %+v", src.lastList)
+	}
+	if len(issues.Items) != 2 || issues.Items[0].Description != "" {
+		t.Errorf("issue list should omit descriptions: %+v", issues.Items)
+	}
+
+	var issue V1Issue
+	v1Get(t, h, "/api/v1/issues/gt-1", http.StatusOK, &issue)
+	if issue.Description != "details" || issue.Labels == nil {
+		t.Errorf("issue = %+v", issue)
+	}
+	v1Get(t, h, "/api/v1/issues/gt-404", http.StatusNotFound, nil)
+
+	var convoys V1Page[V1Convoy]
+	v1Get(t, h, "/api/v1/convoys", http.StatusOK, &convoys)
+	if len(convoys.Items) != 1 || convoys.Items[0].ID != "hq-cv-abc" {
+		t.Errorf("convoys = %+v", convoys.Items)
+	}
+	var convoy V1Convoy
+	v1Get(t, h, "/api/v1/convoys/hq-cv-abc", http.StatusOK, &convoy)
+	if len(convoy.Tracked) != 1 || convoy.Tracked[0].ID != "gt-1" {
+		t.Errorf("convoy tracked = %+v", convoy.Tracked)
+	}
+	v1Get(t, h, "/api/v1/convoys/gt-1", http.StatusNotFound, nil)
+	v1Get(t, h, "/api/v1/convoys?status=bogus", http.StatusBadRequest, nil)
+
+	var msgs V1Page[V1Message]
+	v1Get(t, h, "/api/v1/mail", http.StatusOK, &msgs)
+	if len(msgs.Items) != 2 || msgs.Items[0].ID != "hq-m2" || msgs.Items[0].Body != "" {
+		t.Errorf("mail list should be newest first without bodies: %+v", msgs.Items)
+	}
+	msgs = V1Page[V1Message]{}
+	v1Get(t, h, "/api/v1/mail?unread=true", http.StatusOK, &msgs)
+	if msgs.Total != 1 {
+		t.Errorf("unread total = %d, want 1", msgs.Total)
+	}
+	var msg V1Message
+	v1Get(t, h, "/api/v1/mail/hq-m1", http.StatusOK, &msg)
+	if msg.Body != "b1" {
+		t.Errorf("message body = %q", msg.Body)
+	}
+	v1Get(t, h, "/api/v1/mail?address=overseer", http.StatusOK, nil)
+	v1Get(t, h, "/api/v1/mail?address=mayor/", http.StatusForbidden, nil)
+	v1Get(t, h, "/api/v1/mail/hq-m1?address=gastown/witness", http.StatusForbidden, nil)
+}
+
+func TestV1Handler_IndexAndMount(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigsJSON := `{"version":1,"rigs":{"gastown":{"git_url":"https://example.com/gastown.git"}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigsJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", "polecats", "toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	mux, err := NewDashboardMux(&townRootFetcher{townRoot: townRoot}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var index map[string]interface{}
+	v1Get(t, mux, "/api/v1", http.StatusOK, &index)
+	if index["version"] != "v1" {
+		t.Errorf("index = %v", index)
+	}
+
+	var rigs V1Page[V1Rig]
+	v1Get(t, mux, "/api/v1/rigs", http.StatusOK, &rigs)
+	if len(rigs.Items) != 1 || rigs.Items[0].Name != "gastown" || strings.Join(rigs.Items[0].Polecats, ",") != "toast" {
+		t.Errorf("rigs = %+v", rigs.Items)
+	}
+}
+
+// townRootFetcher is a MockConvoyFetcher that also reports a town root.
+type townRootFetcher struct {
+	MockConvoyFetcher
+	townRoot string
+}
+
+func (f *townRootFetcher) TownRoot() string { return f.townRoot }
@@ -119,6 +119,11 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 	}, nil
 }
 
+// TownRoot returns the root of the town this fetcher reads from.
+func (f *LiveConvoyFetcher) TownRoot() string {
+	return f.townRoot
+}
+
 // FetchConvoys fetches all open convoys with their activity data.
 func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 	// List all open convoy issues
@@ -369,6 +369,11 @@ func NewDashboardMux(fetcher ConvoyFetcher, webCfg *config.WebTimeoutsConfig) (h
 	staticHandler := http.FileServer(http.FS(staticFS))
 
 	mux := http.NewServeMux()
+	if tr, ok := fetcher.(interface{ TownRoot() string }); ok && tr.TownRoot() != "" {
+		v1Handler := NewV1Handler(tr.TownRoot())
+		mux.Handle("/api/v1", v1Handler)
+		mux.Handle("/api/v1/", v1Handler)
+	}
 	mux.Handle("/api/", apiHandler)
 	mux.Handle("/static/", http.StripPrefix("/static/", staticHandler))
 	mux.Handle("/", convoyHandler)
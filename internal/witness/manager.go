@@ -45,8 +45,7 @@ func NewManager(r *rig.Rig) *Manager {
 // ZFC: tmux session existence is the source of truth for session state,
 // but agent liveness determines if the session is actually functional.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
-	status := t.CheckSessionHealth(m.SessionName(), 0)
+	status := session.CheckSessionHealth(session.NewBackend(), m.SessionName(), 0)
 	return status == tmux.SessionHealthy, nil
 }
 
@@ -56,8 +55,7 @@ func (m *Manager) IsRunning() (bool, error) {
 // Returns the detailed ZombieStatus for callers that need to distinguish
 // between different failure modes.
 func (m *Manager) IsHealthy(maxInactivity time.Duration) tmux.ZombieStatus {
-	t := tmux.NewTmux()
-	return t.CheckSessionHealth(m.SessionName(), maxInactivity)
+	return session.CheckSessionHealth(session.NewBackend(), m.SessionName(), maxInactivity)
 }
 
 // SessionName returns the tmux session name for this witness.
@@ -68,7 +66,7 @@ func (m *Manager) SessionName() string {
 // Status returns information about the witness session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -79,7 +77,7 @@ func (m *Manager) Status() (*tmux.SessionInfo, error) {
 		return nil, ErrNotRunning
 	}
 
-	return t.GetSessionInfo(sessionID)
+	return session.GetSessionInfo(t, sessionID)
 }
 
 // witnessDir returns the working directory for the witness.
@@ -105,7 +103,7 @@ func (m *Manager) witnessDir() string {
 // envOverrides are KEY=VALUE pairs that override all other env var sources.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []string) error {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -122,7 +120,7 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 			return ErrAlreadyRunning
 		}
 		// Zombie - tmux alive but Claude dead. Kill and recreate.
-		if err := t.KillSession(sessionID); err != nil {
+		if err := session.KillSession(t, sessionID); err != nil {
 			return fmt.Errorf("killing zombie session: %w", err)
 		}
 	}
@@ -194,20 +192,24 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		}
 	}
 
-	// Apply Gas Town theming (non-fatal: theming failure doesn't affect operation)
-	theme := tmux.AssignTheme(m.rig.Name)
-	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
-
-	// Wait for Claude to start - fatal if Claude fails to launch
-	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
-		// Kill the zombie session before returning error
-		_ = t.KillSessionWithProcesses(sessionID)
-		return fmt.Errorf("waiting for witness to start: %w", err)
-	}
+	// Theming, the pane-command wait and dialog handling are tmux features;
+	// other backends skip them.
+	if tm, ok := t.(*tmux.Tmux); ok {
+		// Apply Gas Town theming (non-fatal: theming failure doesn't affect operation)
+		theme := tmux.AssignTheme(m.rig.Name)
+		_ = tm.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "witness", "witness")
+
+		// Wait for Claude to start - fatal if Claude fails to launch
+		if err := tm.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+			// Kill the zombie session before returning error
+			_ = tm.KillSessionWithProcesses(sessionID)
+			return fmt.Errorf("waiting for witness to start: %w", err)
+		}
 
-	// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
-	if err := t.AcceptStartupDialogs(sessionID); err != nil {
-		log.Printf("warning: accepting startup dialogs for %s: %v", sessionID, err)
+		// Accept startup dialogs (workspace trust + bypass permissions) if they appear.
+		if err := tm.AcceptStartupDialogs(sessionID); err != nil {
+			log.Printf("warning: accepting startup dialogs for %s: %v", sessionID, err)
+		}
 	}
 
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
@@ -279,7 +281,7 @@ func buildWitnessStartCommand(rigPath, rigName, townRoot, sessionName, agentOver
 // Stop stops the witness.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := session.NewBackend()
 	sessionID := m.SessionName()
 
 	// Check if tmux session exists
@@ -289,5 +291,5 @@ func (m *Manager) Stop() error {
 	}
 
 	// Kill the tmux session
-	return t.KillSession(sessionID)
+	return session.KillSession(t, sessionID)
 }
@@ -2,6 +2,9 @@ package witness
 
 import (
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
 )
 
 func TestClassifyMessage(t *testing.T) {
@@ -37,6 +40,41 @@ func TestClassifyMessage(t *testing.T) {
 	}
 }
 
+// Mail rules may summarize protocol mail in a digest, but the witness must
+// still get each message as sent.
+func TestClassifyMessage_ThroughDigestRule(t *testing.T) {
+	rules := &config.MailRulesConfig{Rules: []config.MailRule{
+		{Name: "witness-protocol", Roles: []string{"witness"}, Subject: "^(POLECAT_DONE|MERGED)", Digest: true},
+	}}
+
+	done := mail.NewMessage("gastown/polecats/nux", "gastown/witness", "POLECAT_DONE nux",
+		"Exit: COMPLETED\nIssue: gt-abc123\nMR: gt-mr-xyz\nBranch: polecat/nux")
+	delivered, result := mail.ApplyRules(rules, done)
+	if result.Digest != "witness-protocol" {
+		t.Fatalf("digest = %q, want the rule to match", result.Digest)
+	}
+	if got := ClassifyMessage(delivered.Subject); got != ProtoPolecatDone {
+		t.Errorf("ClassifyMessage(%q) = %v, want %v", delivered.Subject, got, ProtoPolecatDone)
+	}
+	payload, err := ParsePolecatDone(delivered.Subject, delivered.Body)
+	if err != nil {
+		t.Fatalf("ParsePolecatDone() error = %v", err)
+	}
+	if payload.Exit != "COMPLETED" || payload.IssueID != "gt-abc123" || payload.MRID != "gt-mr-xyz" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	merged := mail.NewMessage("gastown/refinery", "gastown/witness", "MERGED nux",
+		"Branch: polecat/nux\nIssue: gt-abc123")
+	delivered, _ = mail.ApplyRules(rules, merged)
+	if got := ClassifyMessage(delivered.Subject); got != ProtoMerged {
+		t.Errorf("ClassifyMessage(%q) = %v, want %v", delivered.Subject, got, ProtoMerged)
+	}
+	if mp, err := ParseMerged(delivered.Subject, delivered.Body); err != nil || mp.IssueID != "gt-abc123" {
+		t.Errorf("ParseMerged() = %+v, %v", mp, err)
+	}
+}
+
 func TestParsePolecatDone(t *testing.T) {
 	subject := "POLECAT_DONE nux"
 	body := `Exit: MERGED
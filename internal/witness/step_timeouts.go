@@ -0,0 +1,172 @@
+package witness
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// StepTimeoutResult describes a molecule step failed for exceeding its timeout.
+type StepTimeoutResult struct {
+	MoleculeID  string
+	StepID      string // formula step ID
+	StepBeadID  string
+	BeadID      string // work bead the molecule is bonded to
+	PolecatName string
+	Timeout     string
+	Nudged      bool
+	Escalated   bool
+	Error       error
+}
+
+// DetectStepTimeoutsResult holds aggregate results.
+type DetectStepTimeoutsResult struct {
+	Checked  int // Molecules with step policies inspected
+	TimedOut []StepTimeoutResult
+	Errors   []error
+}
+
+// nowFn returns the current time. Overridable in tests.
+var nowFn = time.Now
+
+// nudgeStepTimeoutFn tells a polecat its step timed out. Overridable in tests.
+var nudgeStepTimeoutFn = func(sessionName, msg string) error {
+	return tmux.NewTmux().NudgeSession(sessionName, msg)
+}
+
+// DetectStepTimeouts enforces formula step timeouts for the rig's molecules.
+//
+// Formulas may declare a per-step timeout. gt records when each step starts
+// (see formula.Run); a step still running past its timeout fails the whole
+// molecule. For each overdue step this:
+//   - Marks the step timed out and the molecule failed, so the polecat's
+//     next 'gt mol step done' refuses to continue
+//   - Nudges the polecat to stop and run 'gt done --status ESCALATED'
+//   - Escalates to the Deacon
+//
+// Idempotent: a failed molecule is not reported again.
+func DetectStepTimeouts(workDir, rigName string, router *mail.Router) *DetectStepTimeoutsResult {
+	result := &DetectStepTimeoutsResult{}
+
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		townRoot = workDir
+	}
+	initRegistryFromTownRoot(townRoot)
+
+	runs, err := formula.ListRuns(townRoot)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("listing formula runs: %w", err))
+		return result
+	}
+
+	now := nowFn()
+	for _, run := range runs {
+		if run.Rig != rigName {
+			continue
+		}
+		result.Checked++
+
+		overdue := run.Overdue(now)
+		if len(overdue) == 0 {
+			continue
+		}
+		for _, stepID := range overdue {
+			run.TimeOut(stepID, now)
+		}
+		if err := formula.SaveRun(townRoot, run); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("saving run %s: %w", run.MoleculeID, err))
+			continue
+		}
+
+		polecat := polecatForBead(workDir, rigName, run.BeadID)
+		for _, stepID := range overdue {
+			st := run.Steps[stepID]
+			timedOut := StepTimeoutResult{
+				MoleculeID:  run.MoleculeID,
+				StepID:      stepID,
+				StepBeadID:  st.BeadID,
+				BeadID:      run.BeadID,
+				PolecatName: polecat,
+				Timeout:     st.Timeout,
+			}
+			if polecat != "" {
+				sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecat)
+				msg := fmt.Sprintf("STEP_TIMEOUT: step %s exceeded its %s timeout and molecule %s has failed. "+
+					"Stop work and run: gt done --status ESCALATED", stepID, st.Timeout, run.MoleculeID)
+				if err := nudgeStepTimeoutFn(sessionName, msg); err == nil {
+					timedOut.Nudged = true
+				}
+			}
+			if router != nil {
+				if err := escalateStepTimeout(router, rigName, run, stepID, polecat); err != nil {
+					timedOut.Error = fmt.Errorf("escalating: %w", err)
+				} else {
+					timedOut.Escalated = true
+				}
+			}
+			result.TimedOut = append(result.TimedOut, timedOut)
+		}
+	}
+	return result
+}
+
+// polecatForBead returns the name of the rig polecat assigned to beadID.
+func polecatForBead(workDir, rigName, beadID string) string {
+	if beadID == "" {
+		return ""
+	}
+	output, err := util.ExecWithOutput(workDir, "bd", "show", beadID, "--json")
+	if err != nil || output == "" {
+		return ""
+	}
+	var issues []struct {
+		Assignee string `json:"assignee"`
+	}
+	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+		return ""
+	}
+	name, ok := strings.CutPrefix(issues[0].Assignee, rigName+"/polecats/")
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+// escalateStepTimeout sends a STEP_TIMEOUT escalation to the Deacon.
+func escalateStepTimeout(router *mail.Router, rigName string, run *formula.Run, stepID, polecat string) error {
+	st := run.Steps[stepID]
+	agent := "unknown"
+	if polecat != "" {
+		agent = fmt.Sprintf("%s/%s", rigName, polecat)
+	}
+	msg := &mail.Message{
+		From:     fmt.Sprintf("%s/witness", rigName),
+		To:       "deacon/",
+		Subject:  fmt.Sprintf("STEP_TIMEOUT %s %s", run.MoleculeID, stepID),
+		Priority: mail.PriorityHigh,
+		Body: fmt.Sprintf(`Molecule: %s (formula %s)
+Step: %s %s
+Timeout: %s (started %s)
+Work bead: %s
+Polecat: %s
+
+The step exceeded its formula timeout, so the molecule has failed.
+The polecat was told to stop with 'gt done --status ESCALATED'.`,
+			run.MoleculeID, run.Formula,
+			stepID, st.BeadID,
+			st.Timeout, st.StartedAt.Format(time.RFC3339),
+			run.BeadID,
+			agent,
+		),
+	}
+	return router.Send(msg)
+}
@@ -0,0 +1,66 @@
+package witness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func TestDetectStepTimeouts(t *testing.T) {
+	townRoot := t.TempDir()
+	started := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	save := func(id, rig, timeout string) {
+		t.Helper()
+		run := &formula.Run{
+			MoleculeID: id,
+			Rig:        rig,
+			Steps: map[string]*formula.StepState{
+				"build": {Title: "Build", Timeout: timeout, StartedAt: started, BeadID: id + ".1"},
+			},
+		}
+		if err := formula.SaveRun(townRoot, run); err != nil {
+			t.Fatalf("SaveRun: %v", err)
+		}
+	}
+	save("gt-wisp-late", "gastown", "10m")
+	save("gt-wisp-ontime", "gastown", "2h")
+	save("gt-wisp-other", "beads", "10m")
+
+	oldNow, oldNudge := nowFn, nudgeStepTimeoutFn
+	defer func() { nowFn, nudgeStepTimeoutFn = oldNow, oldNudge }()
+	nowFn = func() time.Time { return started.Add(time.Hour) }
+	nudgeStepTimeoutFn = func(string, string) error {
+		t.Error("nudged without a known polecat")
+		return nil
+	}
+
+	result := DetectStepTimeouts(townRoot, "gastown", nil)
+	if len(result.Errors) > 0 {
+		t.Fatalf("Errors = %v", result.Errors)
+	}
+	if result.Checked != 2 {
+		t.Errorf("Checked = %d, want 2", result.Checked)
+	}
+	if len(result.TimedOut) != 1 {
+		t.Fatalf("TimedOut = %+v, want 1 entry", result.TimedOut)
+	}
+	got := result.TimedOut[0]
+	if got.MoleculeID != "gt-wisp-late" || got.StepID != "build" || got.StepBeadID != "gt-wisp-late.1" {
+		t.Errorf("TimedOut[0] = %+v", got)
+	}
+
+	run, err := formula.LoadRun(townRoot, "gt-wisp-late")
+	if err != nil || run == nil {
+		t.Fatalf("LoadRun: %v", err)
+	}
+	if run.Failed == "" || !run.Steps["build"].TimedOut {
+		t.Errorf("run not marked failed: %+v", run)
+	}
+
+	// Idempotent: a failed molecule is not reported again.
+	if again := DetectStepTimeouts(townRoot, "gastown", nil); len(again.TimedOut) != 0 {
+		t.Errorf("second pass TimedOut = %+v, want none", again.TimedOut)
+	}
+}